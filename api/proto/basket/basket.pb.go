@@ -112,11 +112,12 @@ func (x *GetBasketRequest) GetUserId() uint32 {
 }
 
 type AddItemRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	ProductId     uint32                 `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	UnitPrice     float64                `protobuf:"fixed64,4,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	UserId    uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ProductId uint32                 `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity  int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// Variant of the product, unset for the product itself
+	VariantId     *uint32 `protobuf:"varint,7,opt,name=variant_id,json=variantId,proto3,oneof" json:"variant_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -172,9 +173,9 @@ func (x *AddItemRequest) GetQuantity() int32 {
 	return 0
 }
 
-func (x *AddItemRequest) GetUnitPrice() float64 {
-	if x != nil {
-		return x.UnitPrice
+func (x *AddItemRequest) GetVariantId() uint32 {
+	if x != nil && x.VariantId != nil {
+		return *x.VariantId
 	}
 	return 0
 }
@@ -184,6 +185,7 @@ type UpdateItemRequest struct {
 	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	ProductId     uint32                 `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
 	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	VariantId     *uint32                `protobuf:"varint,4,opt,name=variant_id,json=variantId,proto3,oneof" json:"variant_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -239,10 +241,18 @@ func (x *UpdateItemRequest) GetQuantity() int32 {
 	return 0
 }
 
+func (x *UpdateItemRequest) GetVariantId() uint32 {
+	if x != nil && x.VariantId != nil {
+		return *x.VariantId
+	}
+	return 0
+}
+
 type RemoveItemRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	ProductId     uint32                 `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	VariantId     *uint32                `protobuf:"varint,3,opt,name=variant_id,json=variantId,proto3,oneof" json:"variant_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -291,6 +301,13 @@ func (x *RemoveItemRequest) GetProductId() uint32 {
 	return 0
 }
 
+func (x *RemoveItemRequest) GetVariantId() uint32 {
+	if x != nil && x.VariantId != nil {
+		return *x.VariantId
+	}
+	return 0
+}
+
 type ClearBasketRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
@@ -460,16 +477,20 @@ func (*CleanupExpiredBasketsRequest) Descriptor() ([]byte, []int) {
 }
 
 type BasketResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	UserId        uint32                 `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Items         []*BasketItem          `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
-	Total         float64                `protobuf:"fixed64,4,opt,name=total,proto3" json:"total,omitempty"`
-	ItemCount     int32                  `protobuf:"varint,5,opt,name=item_count,json=itemCount,proto3" json:"item_count,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
-	IsExpired     bool                   `protobuf:"varint,9,opt,name=is_expired,json=isExpired,proto3" json:"is_expired,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId uint32                 `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Items  []*BasketItem          `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	// Deprecated: Marked as deprecated in api/proto/basket/basket.proto.
+	Total     float64                `protobuf:"fixed64,4,opt,name=total,proto3" json:"total,omitempty"` // Use total_minor
+	ItemCount int32                  `protobuf:"varint,5,opt,name=item_count,json=itemCount,proto3" json:"item_count,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	IsExpired bool                   `protobuf:"varint,9,opt,name=is_expired,json=isExpired,proto3" json:"is_expired,omitempty"`
+	// Total in the minor unit of the currency, e.g. cents for USD
+	TotalMinor    int64  `protobuf:"varint,10,opt,name=total_minor,json=totalMinor,proto3" json:"total_minor,omitempty"`
+	Currency      string `protobuf:"bytes,11,opt,name=currency,proto3" json:"currency,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -525,6 +546,7 @@ func (x *BasketResponse) GetItems() []*BasketItem {
 	return nil
 }
 
+// Deprecated: Marked as deprecated in api/proto/basket/basket.proto.
 func (x *BasketResponse) GetTotal() float64 {
 	if x != nil {
 		return x.Total
@@ -567,17 +589,37 @@ func (x *BasketResponse) GetIsExpired() bool {
 	return false
 }
 
+func (x *BasketResponse) GetTotalMinor() int64 {
+	if x != nil {
+		return x.TotalMinor
+	}
+	return 0
+}
+
+func (x *BasketResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
 type BasketItem struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	ProductId     uint32                 `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	UnitPrice     float64                `protobuf:"fixed64,4,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
-	TotalPrice    float64                `protobuf:"fixed64,5,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId uint32                 `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity  int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// Deprecated: Marked as deprecated in api/proto/basket/basket.proto.
+	UnitPrice float64 `protobuf:"fixed64,4,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"` // Use unit_price_minor
+	// Deprecated: Marked as deprecated in api/proto/basket/basket.proto.
+	TotalPrice float64                `protobuf:"fixed64,5,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"` // Use total_price_minor
+	CreatedAt  *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt  *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Prices in the minor unit of the basket currency, e.g. cents for USD
+	UnitPriceMinor  int64   `protobuf:"varint,8,opt,name=unit_price_minor,json=unitPriceMinor,proto3" json:"unit_price_minor,omitempty"`
+	TotalPriceMinor int64   `protobuf:"varint,9,opt,name=total_price_minor,json=totalPriceMinor,proto3" json:"total_price_minor,omitempty"`
+	VariantId       *uint32 `protobuf:"varint,10,opt,name=variant_id,json=variantId,proto3,oneof" json:"variant_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *BasketItem) Reset() {
@@ -631,6 +673,7 @@ func (x *BasketItem) GetQuantity() int32 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in api/proto/basket/basket.proto.
 func (x *BasketItem) GetUnitPrice() float64 {
 	if x != nil {
 		return x.UnitPrice
@@ -638,6 +681,7 @@ func (x *BasketItem) GetUnitPrice() float64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in api/proto/basket/basket.proto.
 func (x *BasketItem) GetTotalPrice() float64 {
 	if x != nil {
 		return x.TotalPrice
@@ -659,6 +703,27 @@ func (x *BasketItem) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *BasketItem) GetUnitPriceMinor() int64 {
+	if x != nil {
+		return x.UnitPriceMinor
+	}
+	return 0
+}
+
+func (x *BasketItem) GetTotalPriceMinor() int64 {
+	if x != nil {
+		return x.TotalPriceMinor
+	}
+	return 0
+}
+
+func (x *BasketItem) GetVariantId() uint32 {
+	if x != nil && x.VariantId != nil {
+		return *x.VariantId
+	}
+	return 0
+}
+
 type ClearBasketResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
@@ -831,35 +896,43 @@ const file_api_proto_basket_basket_proto_rawDesc = "" +
 	"\x13CreateBasketRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\rR\x06userId\"+\n" +
 	"\x10GetBasketRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\rR\x06userId\"\x83\x01\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\"\xd1\x01\n" +
 	"\x0eAddItemRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\rR\x06userId\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x02 \x01(\rR\tproductId\x12\x1a\n" +
-	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12\x1d\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12\"\n" +
 	"\n" +
-	"unit_price\x18\x04 \x01(\x01R\tunitPrice\"g\n" +
+	"variant_id\x18\a \x01(\rH\x00R\tvariantId\x88\x01\x01B\r\n" +
+	"\v_variant_idJ\x04\b\x04\x10\x05J\x04\b\x05\x10\x06J\x04\b\x06\x10\aR\n" +
+	"unit_priceR\x10unit_price_minorR\bcurrency\"\x9a\x01\n" +
 	"\x11UpdateItemRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\rR\x06userId\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x02 \x01(\rR\tproductId\x12\x1a\n" +
-	"\bquantity\x18\x03 \x01(\x05R\bquantity\"K\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12\"\n" +
+	"\n" +
+	"variant_id\x18\x04 \x01(\rH\x00R\tvariantId\x88\x01\x01B\r\n" +
+	"\v_variant_id\"~\n" +
 	"\x11RemoveItemRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\rR\x06userId\x12\x1d\n" +
 	"\n" +
-	"product_id\x18\x02 \x01(\rR\tproductId\"-\n" +
+	"product_id\x18\x02 \x01(\rR\tproductId\x12\"\n" +
+	"\n" +
+	"variant_id\x18\x03 \x01(\rH\x00R\tvariantId\x88\x01\x01B\r\n" +
+	"\v_variant_id\"-\n" +
 	"\x12ClearBasketRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\rR\x06userId\"/\n" +
 	"\x14GetUserBasketRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\rR\x06userId\"2\n" +
 	"\x17DeleteUserBasketRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\rR\x06userId\"\x1e\n" +
-	"\x1cCleanupExpiredBasketsRequest\"\xe8\x02\n" +
+	"\x1cCleanupExpiredBasketsRequest\"\xa9\x03\n" +
 	"\x0eBasketResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\rR\x06userId\x12(\n" +
-	"\x05items\x18\x03 \x03(\v2\x12.basket.BasketItemR\x05items\x12\x14\n" +
-	"\x05total\x18\x04 \x01(\x01R\x05total\x12\x1d\n" +
+	"\x05items\x18\x03 \x03(\v2\x12.basket.BasketItemR\x05items\x12\x18\n" +
+	"\x05total\x18\x04 \x01(\x01B\x02\x18\x01R\x05total\x12\x1d\n" +
 	"\n" +
 	"item_count\x18\x05 \x01(\x05R\titemCount\x129\n" +
 	"\n" +
@@ -869,21 +942,31 @@ const file_api_proto_basket_basket_proto_rawDesc = "" +
 	"\n" +
 	"expires_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x1d\n" +
 	"\n" +
-	"is_expired\x18\t \x01(\bR\tisExpired\"\x8d\x02\n" +
+	"is_expired\x18\t \x01(\bR\tisExpired\x12\x1f\n" +
+	"\vtotal_minor\x18\n" +
+	" \x01(\x03R\n" +
+	"totalMinor\x12\x1a\n" +
+	"\bcurrency\x18\v \x01(\tR\bcurrency\"\x9e\x03\n" +
 	"\n" +
 	"BasketItem\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\rR\x02id\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x02 \x01(\rR\tproductId\x12\x1a\n" +
-	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12\x1d\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12!\n" +
 	"\n" +
-	"unit_price\x18\x04 \x01(\x01R\tunitPrice\x12\x1f\n" +
-	"\vtotal_price\x18\x05 \x01(\x01R\n" +
+	"unit_price\x18\x04 \x01(\x01B\x02\x18\x01R\tunitPrice\x12#\n" +
+	"\vtotal_price\x18\x05 \x01(\x01B\x02\x18\x01R\n" +
 	"totalPrice\x129\n" +
 	"\n" +
 	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"I\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12(\n" +
+	"\x10unit_price_minor\x18\b \x01(\x03R\x0eunitPriceMinor\x12*\n" +
+	"\x11total_price_minor\x18\t \x01(\x03R\x0ftotalPriceMinor\x12\"\n" +
+	"\n" +
+	"variant_id\x18\n" +
+	" \x01(\rH\x00R\tvariantId\x88\x01\x01B\r\n" +
+	"\v_variant_id\"I\n" +
 	"\x13ClearBasketResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\"N\n" +
@@ -974,6 +1057,10 @@ func file_api_proto_basket_basket_proto_init() {
 	if File_api_proto_basket_basket_proto != nil {
 		return
 	}
+	file_api_proto_basket_basket_proto_msgTypes[2].OneofWrappers = []any{}
+	file_api_proto_basket_basket_proto_msgTypes[3].OneofWrappers = []any{}
+	file_api_proto_basket_basket_proto_msgTypes[4].OneofWrappers = []any{}
+	file_api_proto_basket_basket_proto_msgTypes[10].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
@@ -0,0 +1,1227 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v3.21.12
+// source: api/proto/events/events.proto
+
+package eventspb
+
+import (
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// PaymentItem is an item of a payment, priced in minor units of the payment currency
+type PaymentItem struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ProductId       uint32                 `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity        int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	UnitPriceMinor  int64                  `protobuf:"varint,3,opt,name=unit_price_minor,json=unitPriceMinor,proto3" json:"unit_price_minor,omitempty"`
+	TotalPriceMinor int64                  `protobuf:"varint,4,opt,name=total_price_minor,json=totalPriceMinor,proto3" json:"total_price_minor,omitempty"`
+	VariantId       *uint32                `protobuf:"varint,5,opt,name=variant_id,json=variantId,proto3,oneof" json:"variant_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *PaymentItem) Reset() {
+	*x = PaymentItem{}
+	mi := &file_api_proto_events_events_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentItem) ProtoMessage() {}
+
+func (x *PaymentItem) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_events_events_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentItem.ProtoReflect.Descriptor instead.
+func (*PaymentItem) Descriptor() ([]byte, []int) {
+	return file_api_proto_events_events_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PaymentItem) GetProductId() uint32 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *PaymentItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *PaymentItem) GetUnitPriceMinor() int64 {
+	if x != nil {
+		return x.UnitPriceMinor
+	}
+	return 0
+}
+
+func (x *PaymentItem) GetTotalPriceMinor() int64 {
+	if x != nil {
+		return x.TotalPriceMinor
+	}
+	return 0
+}
+
+func (x *PaymentItem) GetVariantId() uint32 {
+	if x != nil && x.VariantId != nil {
+		return *x.VariantId
+	}
+	return 0
+}
+
+// PaymentCompletedData is the data of payment.completed events
+type PaymentCompletedData struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PaymentId     string                 `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	UserId        uint32                 `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	AmountMinor   int64                  `protobuf:"varint,4,opt,name=amount_minor,json=amountMinor,proto3" json:"amount_minor,omitempty"`
+	Currency      string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	PaymentMethod string                 `protobuf:"bytes,6,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
+	Items         []*PaymentItem         `protobuf:"bytes,7,rep,name=items,proto3" json:"items,omitempty"`
+	BasketId      *string                `protobuf:"bytes,8,opt,name=basket_id,json=basketId,proto3,oneof" json:"basket_id,omitempty"`
+	Metadata      *structpb.Struct       `protobuf:"bytes,9,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentCompletedData) Reset() {
+	*x = PaymentCompletedData{}
+	mi := &file_api_proto_events_events_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentCompletedData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentCompletedData) ProtoMessage() {}
+
+func (x *PaymentCompletedData) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_events_events_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentCompletedData.ProtoReflect.Descriptor instead.
+func (*PaymentCompletedData) Descriptor() ([]byte, []int) {
+	return file_api_proto_events_events_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PaymentCompletedData) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *PaymentCompletedData) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *PaymentCompletedData) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *PaymentCompletedData) GetAmountMinor() int64 {
+	if x != nil {
+		return x.AmountMinor
+	}
+	return 0
+}
+
+func (x *PaymentCompletedData) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *PaymentCompletedData) GetPaymentMethod() string {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return ""
+}
+
+func (x *PaymentCompletedData) GetItems() []*PaymentItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *PaymentCompletedData) GetBasketId() string {
+	if x != nil && x.BasketId != nil {
+		return *x.BasketId
+	}
+	return ""
+}
+
+func (x *PaymentCompletedData) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// PaymentFailedData is the data of payment.failed events
+type PaymentFailedData struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PaymentId     string                 `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	UserId        uint32                 `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	AmountMinor   int64                  `protobuf:"varint,4,opt,name=amount_minor,json=amountMinor,proto3" json:"amount_minor,omitempty"`
+	Currency      string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	PaymentMethod string                 `protobuf:"bytes,6,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
+	Reason        string                 `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`
+	BasketId      *string                `protobuf:"bytes,8,opt,name=basket_id,json=basketId,proto3,oneof" json:"basket_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentFailedData) Reset() {
+	*x = PaymentFailedData{}
+	mi := &file_api_proto_events_events_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentFailedData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentFailedData) ProtoMessage() {}
+
+func (x *PaymentFailedData) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_events_events_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentFailedData.ProtoReflect.Descriptor instead.
+func (*PaymentFailedData) Descriptor() ([]byte, []int) {
+	return file_api_proto_events_events_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PaymentFailedData) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *PaymentFailedData) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *PaymentFailedData) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *PaymentFailedData) GetAmountMinor() int64 {
+	if x != nil {
+		return x.AmountMinor
+	}
+	return 0
+}
+
+func (x *PaymentFailedData) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *PaymentFailedData) GetPaymentMethod() string {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return ""
+}
+
+func (x *PaymentFailedData) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *PaymentFailedData) GetBasketId() string {
+	if x != nil && x.BasketId != nil {
+		return *x.BasketId
+	}
+	return ""
+}
+
+// PaymentCancelledData is the data of payment.cancelled events
+type PaymentCancelledData struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PaymentId     string                 `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	UserId        uint32                 `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	AmountMinor   int64                  `protobuf:"varint,4,opt,name=amount_minor,json=amountMinor,proto3" json:"amount_minor,omitempty"`
+	Currency      string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	PaymentMethod string                 `protobuf:"bytes,6,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
+	Reason        string                 `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`
+	BasketId      *string                `protobuf:"bytes,8,opt,name=basket_id,json=basketId,proto3,oneof" json:"basket_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentCancelledData) Reset() {
+	*x = PaymentCancelledData{}
+	mi := &file_api_proto_events_events_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentCancelledData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentCancelledData) ProtoMessage() {}
+
+func (x *PaymentCancelledData) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_events_events_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentCancelledData.ProtoReflect.Descriptor instead.
+func (*PaymentCancelledData) Descriptor() ([]byte, []int) {
+	return file_api_proto_events_events_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PaymentCancelledData) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *PaymentCancelledData) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *PaymentCancelledData) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *PaymentCancelledData) GetAmountMinor() int64 {
+	if x != nil {
+		return x.AmountMinor
+	}
+	return 0
+}
+
+func (x *PaymentCancelledData) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *PaymentCancelledData) GetPaymentMethod() string {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return ""
+}
+
+func (x *PaymentCancelledData) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *PaymentCancelledData) GetBasketId() string {
+	if x != nil && x.BasketId != nil {
+		return *x.BasketId
+	}
+	return ""
+}
+
+// PaymentRefundedData is the data of payment.refunded events
+type PaymentRefundedData struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	PaymentId          string                 `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	RefundId           string                 `protobuf:"bytes,2,opt,name=refund_id,json=refundId,proto3" json:"refund_id,omitempty"`
+	UserId             uint32                 `protobuf:"varint,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OrderId            string                 `protobuf:"bytes,4,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	AmountMinor        int64                  `protobuf:"varint,5,opt,name=amount_minor,json=amountMinor,proto3" json:"amount_minor,omitempty"`
+	TotalRefundedMinor int64                  `protobuf:"varint,6,opt,name=total_refunded_minor,json=totalRefundedMinor,proto3" json:"total_refunded_minor,omitempty"`
+	Currency           string                 `protobuf:"bytes,7,opt,name=currency,proto3" json:"currency,omitempty"`
+	Reason             string                 `protobuf:"bytes,8,opt,name=reason,proto3" json:"reason,omitempty"`
+	FullRefund         bool                   `protobuf:"varint,9,opt,name=full_refund,json=fullRefund,proto3" json:"full_refund,omitempty"`
+	Items              []*PaymentItem         `protobuf:"bytes,10,rep,name=items,proto3" json:"items,omitempty"`
+	BasketId           *string                `protobuf:"bytes,11,opt,name=basket_id,json=basketId,proto3,oneof" json:"basket_id,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *PaymentRefundedData) Reset() {
+	*x = PaymentRefundedData{}
+	mi := &file_api_proto_events_events_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentRefundedData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentRefundedData) ProtoMessage() {}
+
+func (x *PaymentRefundedData) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_events_events_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentRefundedData.ProtoReflect.Descriptor instead.
+func (*PaymentRefundedData) Descriptor() ([]byte, []int) {
+	return file_api_proto_events_events_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PaymentRefundedData) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *PaymentRefundedData) GetRefundId() string {
+	if x != nil {
+		return x.RefundId
+	}
+	return ""
+}
+
+func (x *PaymentRefundedData) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *PaymentRefundedData) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *PaymentRefundedData) GetAmountMinor() int64 {
+	if x != nil {
+		return x.AmountMinor
+	}
+	return 0
+}
+
+func (x *PaymentRefundedData) GetTotalRefundedMinor() int64 {
+	if x != nil {
+		return x.TotalRefundedMinor
+	}
+	return 0
+}
+
+func (x *PaymentRefundedData) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *PaymentRefundedData) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *PaymentRefundedData) GetFullRefund() bool {
+	if x != nil {
+		return x.FullRefund
+	}
+	return false
+}
+
+func (x *PaymentRefundedData) GetItems() []*PaymentItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *PaymentRefundedData) GetBasketId() string {
+	if x != nil && x.BasketId != nil {
+		return *x.BasketId
+	}
+	return ""
+}
+
+// StockUpdatedData is the data of stock.updated events
+type StockUpdatedData struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ProductId uint32                 `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	// Positive for increase, negative for decrease
+	Quantity  int32   `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	NewStock  int32   `protobuf:"varint,3,opt,name=new_stock,json=newStock,proto3" json:"new_stock,omitempty"`
+	Reason    string  `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	OrderId   *string `protobuf:"bytes,5,opt,name=order_id,json=orderId,proto3,oneof" json:"order_id,omitempty"`
+	PaymentId *string `protobuf:"bytes,6,opt,name=payment_id,json=paymentId,proto3,oneof" json:"payment_id,omitempty"`
+	// Set when the stock of a variant of the product changed
+	VariantId     *uint32 `protobuf:"varint,7,opt,name=variant_id,json=variantId,proto3,oneof" json:"variant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockUpdatedData) Reset() {
+	*x = StockUpdatedData{}
+	mi := &file_api_proto_events_events_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockUpdatedData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockUpdatedData) ProtoMessage() {}
+
+func (x *StockUpdatedData) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_events_events_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockUpdatedData.ProtoReflect.Descriptor instead.
+func (*StockUpdatedData) Descriptor() ([]byte, []int) {
+	return file_api_proto_events_events_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StockUpdatedData) GetProductId() uint32 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *StockUpdatedData) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *StockUpdatedData) GetNewStock() int32 {
+	if x != nil {
+		return x.NewStock
+	}
+	return 0
+}
+
+func (x *StockUpdatedData) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *StockUpdatedData) GetOrderId() string {
+	if x != nil && x.OrderId != nil {
+		return *x.OrderId
+	}
+	return ""
+}
+
+func (x *StockUpdatedData) GetPaymentId() string {
+	if x != nil && x.PaymentId != nil {
+		return *x.PaymentId
+	}
+	return ""
+}
+
+func (x *StockUpdatedData) GetVariantId() uint32 {
+	if x != nil && x.VariantId != nil {
+		return *x.VariantId
+	}
+	return 0
+}
+
+// BasketClearedData is the data of basket.cleared events
+type BasketClearedData struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        uint32                 `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	BasketId      string                 `protobuf:"bytes,2,opt,name=basket_id,json=basketId,proto3" json:"basket_id,omitempty"`
+	Items         []*PaymentItem         `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	OrderId       *string                `protobuf:"bytes,5,opt,name=order_id,json=orderId,proto3,oneof" json:"order_id,omitempty"`
+	PaymentId     *string                `protobuf:"bytes,6,opt,name=payment_id,json=paymentId,proto3,oneof" json:"payment_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BasketClearedData) Reset() {
+	*x = BasketClearedData{}
+	mi := &file_api_proto_events_events_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BasketClearedData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BasketClearedData) ProtoMessage() {}
+
+func (x *BasketClearedData) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_events_events_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BasketClearedData.ProtoReflect.Descriptor instead.
+func (*BasketClearedData) Descriptor() ([]byte, []int) {
+	return file_api_proto_events_events_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *BasketClearedData) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *BasketClearedData) GetBasketId() string {
+	if x != nil {
+		return x.BasketId
+	}
+	return ""
+}
+
+func (x *BasketClearedData) GetItems() []*PaymentItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *BasketClearedData) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *BasketClearedData) GetOrderId() string {
+	if x != nil && x.OrderId != nil {
+		return *x.OrderId
+	}
+	return ""
+}
+
+func (x *BasketClearedData) GetPaymentId() string {
+	if x != nil && x.PaymentId != nil {
+		return *x.PaymentId
+	}
+	return ""
+}
+
+// Address is the shipping or billing address of an order
+type Address struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	City          string                 `protobuf:"bytes,3,opt,name=city,proto3" json:"city,omitempty"`
+	State         string                 `protobuf:"bytes,4,opt,name=state,proto3" json:"state,omitempty"`
+	ZipCode       string                 `protobuf:"bytes,5,opt,name=zip_code,json=zipCode,proto3" json:"zip_code,omitempty"`
+	Country       string                 `protobuf:"bytes,6,opt,name=country,proto3" json:"country,omitempty"`
+	Phone         string                 `protobuf:"bytes,7,opt,name=phone,proto3" json:"phone,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Address) Reset() {
+	*x = Address{}
+	mi := &file_api_proto_events_events_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Address) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Address) ProtoMessage() {}
+
+func (x *Address) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_events_events_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Address.ProtoReflect.Descriptor instead.
+func (*Address) Descriptor() ([]byte, []int) {
+	return file_api_proto_events_events_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Address) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Address) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Address) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Address) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Address) GetZipCode() string {
+	if x != nil {
+		return x.ZipCode
+	}
+	return ""
+}
+
+func (x *Address) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *Address) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+// OrderCreatedData is the data of order.created events
+type OrderCreatedData struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId        uint32                 `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PaymentId     string                 `protobuf:"bytes,3,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	AmountMinor   int64                  `protobuf:"varint,4,opt,name=amount_minor,json=amountMinor,proto3" json:"amount_minor,omitempty"`
+	Currency      string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	Items         []*PaymentItem         `protobuf:"bytes,6,rep,name=items,proto3" json:"items,omitempty"`
+	ShippingInfo  *Address               `protobuf:"bytes,7,opt,name=shipping_info,json=shippingInfo,proto3" json:"shipping_info,omitempty"`
+	BillingInfo   *Address               `protobuf:"bytes,8,opt,name=billing_info,json=billingInfo,proto3" json:"billing_info,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderCreatedData) Reset() {
+	*x = OrderCreatedData{}
+	mi := &file_api_proto_events_events_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderCreatedData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderCreatedData) ProtoMessage() {}
+
+func (x *OrderCreatedData) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_events_events_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderCreatedData.ProtoReflect.Descriptor instead.
+func (*OrderCreatedData) Descriptor() ([]byte, []int) {
+	return file_api_proto_events_events_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *OrderCreatedData) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderCreatedData) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *OrderCreatedData) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *OrderCreatedData) GetAmountMinor() int64 {
+	if x != nil {
+		return x.AmountMinor
+	}
+	return 0
+}
+
+func (x *OrderCreatedData) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *OrderCreatedData) GetItems() []*PaymentItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *OrderCreatedData) GetShippingInfo() *Address {
+	if x != nil {
+		return x.ShippingInfo
+	}
+	return nil
+}
+
+func (x *OrderCreatedData) GetBillingInfo() *Address {
+	if x != nil {
+		return x.BillingInfo
+	}
+	return nil
+}
+
+// OrderCancelledData is the data of order.cancelled events
+type OrderCancelledData struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId        uint32                 `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderCancelledData) Reset() {
+	*x = OrderCancelledData{}
+	mi := &file_api_proto_events_events_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderCancelledData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderCancelledData) ProtoMessage() {}
+
+func (x *OrderCancelledData) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_events_events_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderCancelledData.ProtoReflect.Descriptor instead.
+func (*OrderCancelledData) Descriptor() ([]byte, []int) {
+	return file_api_proto_events_events_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *OrderCancelledData) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderCancelledData) GetUserId() uint32 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *OrderCancelledData) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// OrderPaymentRejectedData is the data of order.payment_rejected events
+type OrderPaymentRejectedData struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	PaymentId     string                 `protobuf:"bytes,2,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderPaymentRejectedData) Reset() {
+	*x = OrderPaymentRejectedData{}
+	mi := &file_api_proto_events_events_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderPaymentRejectedData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderPaymentRejectedData) ProtoMessage() {}
+
+func (x *OrderPaymentRejectedData) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_events_events_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderPaymentRejectedData.ProtoReflect.Descriptor instead.
+func (*OrderPaymentRejectedData) Descriptor() ([]byte, []int) {
+	return file_api_proto_events_events_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *OrderPaymentRejectedData) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderPaymentRejectedData) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *OrderPaymentRejectedData) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+var File_api_proto_events_events_proto protoreflect.FileDescriptor
+
+const file_api_proto_events_events_proto_rawDesc = "" +
+	"\n" +
+	"\x1dapi/proto/events/events.proto\x12\x06events\x1a\x1cgoogle/protobuf/struct.proto\"\xd1\x01\n" +
+	"\vPaymentItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\rR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\x12(\n" +
+	"\x10unit_price_minor\x18\x03 \x01(\x03R\x0eunitPriceMinor\x12*\n" +
+	"\x11total_price_minor\x18\x04 \x01(\x03R\x0ftotalPriceMinor\x12\"\n" +
+	"\n" +
+	"variant_id\x18\x05 \x01(\rH\x00R\tvariantId\x88\x01\x01B\r\n" +
+	"\v_variant_id\"\xf5\x02\n" +
+	"\x14PaymentCompletedData\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x01 \x01(\tR\tpaymentId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\rR\x06userId\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x12!\n" +
+	"\famount_minor\x18\x04 \x01(\x03R\vamountMinor\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\x12%\n" +
+	"\x0epayment_method\x18\x06 \x01(\tR\rpaymentMethod\x12)\n" +
+	"\x05items\x18\a \x03(\v2\x13.events.PaymentItemR\x05items\x12 \n" +
+	"\tbasket_id\x18\b \x01(\tH\x00R\bbasketId\x88\x01\x01\x123\n" +
+	"\bmetadata\x18\t \x01(\v2\x17.google.protobuf.StructR\bmetadataB\f\n" +
+	"\n" +
+	"_basket_idJ\x04\b\n" +
+	"\x10\vR\x0estock_reserved\"\x94\x02\n" +
+	"\x11PaymentFailedData\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x01 \x01(\tR\tpaymentId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\rR\x06userId\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x12!\n" +
+	"\famount_minor\x18\x04 \x01(\x03R\vamountMinor\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\x12%\n" +
+	"\x0epayment_method\x18\x06 \x01(\tR\rpaymentMethod\x12\x16\n" +
+	"\x06reason\x18\a \x01(\tR\x06reason\x12 \n" +
+	"\tbasket_id\x18\b \x01(\tH\x00R\bbasketId\x88\x01\x01B\f\n" +
+	"\n" +
+	"_basket_id\"\x97\x02\n" +
+	"\x14PaymentCancelledData\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x01 \x01(\tR\tpaymentId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\rR\x06userId\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x12!\n" +
+	"\famount_minor\x18\x04 \x01(\x03R\vamountMinor\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\x12%\n" +
+	"\x0epayment_method\x18\x06 \x01(\tR\rpaymentMethod\x12\x16\n" +
+	"\x06reason\x18\a \x01(\tR\x06reason\x12 \n" +
+	"\tbasket_id\x18\b \x01(\tH\x00R\bbasketId\x88\x01\x01B\f\n" +
+	"\n" +
+	"_basket_id\"\x8a\x03\n" +
+	"\x13PaymentRefundedData\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x01 \x01(\tR\tpaymentId\x12\x1b\n" +
+	"\trefund_id\x18\x02 \x01(\tR\brefundId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\rR\x06userId\x12\x19\n" +
+	"\border_id\x18\x04 \x01(\tR\aorderId\x12!\n" +
+	"\famount_minor\x18\x05 \x01(\x03R\vamountMinor\x120\n" +
+	"\x14total_refunded_minor\x18\x06 \x01(\x03R\x12totalRefundedMinor\x12\x1a\n" +
+	"\bcurrency\x18\a \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06reason\x18\b \x01(\tR\x06reason\x12\x1f\n" +
+	"\vfull_refund\x18\t \x01(\bR\n" +
+	"fullRefund\x12)\n" +
+	"\x05items\x18\n" +
+	" \x03(\v2\x13.events.PaymentItemR\x05items\x12 \n" +
+	"\tbasket_id\x18\v \x01(\tH\x00R\bbasketId\x88\x01\x01B\f\n" +
+	"\n" +
+	"_basket_id\"\x95\x02\n" +
+	"\x10StockUpdatedData\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\rR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\x12\x1b\n" +
+	"\tnew_stock\x18\x03 \x01(\x05R\bnewStock\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x1e\n" +
+	"\border_id\x18\x05 \x01(\tH\x00R\aorderId\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"payment_id\x18\x06 \x01(\tH\x01R\tpaymentId\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"variant_id\x18\a \x01(\rH\x02R\tvariantId\x88\x01\x01B\v\n" +
+	"\t_order_idB\r\n" +
+	"\v_payment_idB\r\n" +
+	"\v_variant_id\"\xec\x01\n" +
+	"\x11BasketClearedData\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\rR\x06userId\x12\x1b\n" +
+	"\tbasket_id\x18\x02 \x01(\tR\bbasketId\x12)\n" +
+	"\x05items\x18\x03 \x03(\v2\x13.events.PaymentItemR\x05items\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x1e\n" +
+	"\border_id\x18\x05 \x01(\tH\x00R\aorderId\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"payment_id\x18\x06 \x01(\tH\x01R\tpaymentId\x88\x01\x01B\v\n" +
+	"\t_order_idB\r\n" +
+	"\v_payment_id\"\xac\x01\n" +
+	"\aAddress\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\x12\x12\n" +
+	"\x04city\x18\x03 \x01(\tR\x04city\x12\x14\n" +
+	"\x05state\x18\x04 \x01(\tR\x05state\x12\x19\n" +
+	"\bzip_code\x18\x05 \x01(\tR\azipCode\x12\x18\n" +
+	"\acountry\x18\x06 \x01(\tR\acountry\x12\x14\n" +
+	"\x05phone\x18\a \x01(\tR\x05phone\"\xb9\x02\n" +
+	"\x10OrderCreatedData\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\rR\x06userId\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x03 \x01(\tR\tpaymentId\x12!\n" +
+	"\famount_minor\x18\x04 \x01(\x03R\vamountMinor\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\x12)\n" +
+	"\x05items\x18\x06 \x03(\v2\x13.events.PaymentItemR\x05items\x124\n" +
+	"\rshipping_info\x18\a \x01(\v2\x0f.events.AddressR\fshippingInfo\x122\n" +
+	"\fbilling_info\x18\b \x01(\v2\x0f.events.AddressR\vbillingInfo\"`\n" +
+	"\x12OrderCancelledData\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\rR\x06userId\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"l\n" +
+	"\x18OrderPaymentRejectedData\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x02 \x01(\tR\tpaymentId\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reasonB0Z.github.com/ddd-micro/api/proto/events;eventspbb\x06proto3"
+
+var (
+	file_api_proto_events_events_proto_rawDescOnce sync.Once
+	file_api_proto_events_events_proto_rawDescData []byte
+)
+
+func file_api_proto_events_events_proto_rawDescGZIP() []byte {
+	file_api_proto_events_events_proto_rawDescOnce.Do(func() {
+		file_api_proto_events_events_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_api_proto_events_events_proto_rawDesc), len(file_api_proto_events_events_proto_rawDesc)))
+	})
+	return file_api_proto_events_events_proto_rawDescData
+}
+
+var file_api_proto_events_events_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_api_proto_events_events_proto_goTypes = []any{
+	(*PaymentItem)(nil),              // 0: events.PaymentItem
+	(*PaymentCompletedData)(nil),     // 1: events.PaymentCompletedData
+	(*PaymentFailedData)(nil),        // 2: events.PaymentFailedData
+	(*PaymentCancelledData)(nil),     // 3: events.PaymentCancelledData
+	(*PaymentRefundedData)(nil),      // 4: events.PaymentRefundedData
+	(*StockUpdatedData)(nil),         // 5: events.StockUpdatedData
+	(*BasketClearedData)(nil),        // 6: events.BasketClearedData
+	(*Address)(nil),                  // 7: events.Address
+	(*OrderCreatedData)(nil),         // 8: events.OrderCreatedData
+	(*OrderCancelledData)(nil),       // 9: events.OrderCancelledData
+	(*OrderPaymentRejectedData)(nil), // 10: events.OrderPaymentRejectedData
+	(*structpb.Struct)(nil),          // 11: google.protobuf.Struct
+}
+var file_api_proto_events_events_proto_depIdxs = []int32{
+	0,  // 0: events.PaymentCompletedData.items:type_name -> events.PaymentItem
+	11, // 1: events.PaymentCompletedData.metadata:type_name -> google.protobuf.Struct
+	0,  // 2: events.PaymentRefundedData.items:type_name -> events.PaymentItem
+	0,  // 3: events.BasketClearedData.items:type_name -> events.PaymentItem
+	0,  // 4: events.OrderCreatedData.items:type_name -> events.PaymentItem
+	7,  // 5: events.OrderCreatedData.shipping_info:type_name -> events.Address
+	7,  // 6: events.OrderCreatedData.billing_info:type_name -> events.Address
+	7,  // [7:7] is the sub-list for method output_type
+	7,  // [7:7] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_api_proto_events_events_proto_init() }
+func file_api_proto_events_events_proto_init() {
+	if File_api_proto_events_events_proto != nil {
+		return
+	}
+	file_api_proto_events_events_proto_msgTypes[0].OneofWrappers = []any{}
+	file_api_proto_events_events_proto_msgTypes[1].OneofWrappers = []any{}
+	file_api_proto_events_events_proto_msgTypes[2].OneofWrappers = []any{}
+	file_api_proto_events_events_proto_msgTypes[3].OneofWrappers = []any{}
+	file_api_proto_events_events_proto_msgTypes[4].OneofWrappers = []any{}
+	file_api_proto_events_events_proto_msgTypes[5].OneofWrappers = []any{}
+	file_api_proto_events_events_proto_msgTypes[6].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_events_events_proto_rawDesc), len(file_api_proto_events_events_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_api_proto_events_events_proto_goTypes,
+		DependencyIndexes: file_api_proto_events_events_proto_depIdxs,
+		MessageInfos:      file_api_proto_events_events_proto_msgTypes,
+	}.Build()
+	File_api_proto_events_events_proto = out.File
+	file_api_proto_events_events_proto_goTypes = nil
+	file_api_proto_events_events_proto_depIdxs = nil
+}
@@ -30,34 +30,47 @@ type Product struct {
 	Name             string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	Description      string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
 	ShortDescription string                 `protobuf:"bytes,4,opt,name=short_description,json=shortDescription,proto3" json:"short_description,omitempty"`
-	Price            float64                `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`
-	ComparePrice     float64                `protobuf:"fixed64,6,opt,name=compare_price,json=comparePrice,proto3" json:"compare_price,omitempty"`
-	CostPrice        float64                `protobuf:"fixed64,7,opt,name=cost_price,json=costPrice,proto3" json:"cost_price,omitempty"`
-	Stock            int32                  `protobuf:"varint,8,opt,name=stock,proto3" json:"stock,omitempty"`
-	MinStock         int32                  `protobuf:"varint,9,opt,name=min_stock,json=minStock,proto3" json:"min_stock,omitempty"`
-	MaxStock         int32                  `protobuf:"varint,10,opt,name=max_stock,json=maxStock,proto3" json:"max_stock,omitempty"`
-	Category         string                 `protobuf:"bytes,11,opt,name=category,proto3" json:"category,omitempty"`
-	SubCategory      string                 `protobuf:"bytes,12,opt,name=sub_category,json=subCategory,proto3" json:"sub_category,omitempty"`
-	Brand            string                 `protobuf:"bytes,13,opt,name=brand,proto3" json:"brand,omitempty"`
-	Sku              string                 `protobuf:"bytes,14,opt,name=sku,proto3" json:"sku,omitempty"`
-	Barcode          string                 `protobuf:"bytes,15,opt,name=barcode,proto3" json:"barcode,omitempty"`
-	Weight           float64                `protobuf:"fixed64,16,opt,name=weight,proto3" json:"weight,omitempty"`
-	Dimensions       string                 `protobuf:"bytes,17,opt,name=dimensions,proto3" json:"dimensions,omitempty"`
-	Color            string                 `protobuf:"bytes,18,opt,name=color,proto3" json:"color,omitempty"`
-	Size             string                 `protobuf:"bytes,19,opt,name=size,proto3" json:"size,omitempty"`
-	Material         string                 `protobuf:"bytes,20,opt,name=material,proto3" json:"material,omitempty"`
-	Tags             string                 `protobuf:"bytes,21,opt,name=tags,proto3" json:"tags,omitempty"`
-	Images           string                 `protobuf:"bytes,22,opt,name=images,proto3" json:"images,omitempty"`
-	IsActive         bool                   `protobuf:"varint,23,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
-	IsDigital        bool                   `protobuf:"varint,24,opt,name=is_digital,json=isDigital,proto3" json:"is_digital,omitempty"`
-	IsFeatured       bool                   `protobuf:"varint,25,opt,name=is_featured,json=isFeatured,proto3" json:"is_featured,omitempty"`
-	IsOnSale         bool                   `protobuf:"varint,26,opt,name=is_on_sale,json=isOnSale,proto3" json:"is_on_sale,omitempty"`
-	SortOrder        int32                  `protobuf:"varint,27,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"`
-	ViewCount        int32                  `protobuf:"varint,28,opt,name=view_count,json=viewCount,proto3" json:"view_count,omitempty"`
-	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,29,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt        *timestamppb.Timestamp `protobuf:"bytes,30,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	// Deprecated: Marked as deprecated in api/proto/product/product.proto.
+	Price float64 `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"` // Use price_minor
+	// Deprecated: Marked as deprecated in api/proto/product/product.proto.
+	ComparePrice float64 `protobuf:"fixed64,6,opt,name=compare_price,json=comparePrice,proto3" json:"compare_price,omitempty"` // Use compare_price_minor
+	// Deprecated: Marked as deprecated in api/proto/product/product.proto.
+	CostPrice   float64                `protobuf:"fixed64,7,opt,name=cost_price,json=costPrice,proto3" json:"cost_price,omitempty"` // Use cost_price_minor
+	Stock       int32                  `protobuf:"varint,8,opt,name=stock,proto3" json:"stock,omitempty"`
+	MinStock    int32                  `protobuf:"varint,9,opt,name=min_stock,json=minStock,proto3" json:"min_stock,omitempty"`
+	MaxStock    int32                  `protobuf:"varint,10,opt,name=max_stock,json=maxStock,proto3" json:"max_stock,omitempty"`
+	Category    string                 `protobuf:"bytes,11,opt,name=category,proto3" json:"category,omitempty"`
+	SubCategory string                 `protobuf:"bytes,12,opt,name=sub_category,json=subCategory,proto3" json:"sub_category,omitempty"`
+	Brand       string                 `protobuf:"bytes,13,opt,name=brand,proto3" json:"brand,omitempty"`
+	Sku         string                 `protobuf:"bytes,14,opt,name=sku,proto3" json:"sku,omitempty"`
+	Barcode     string                 `protobuf:"bytes,15,opt,name=barcode,proto3" json:"barcode,omitempty"`
+	Weight      float64                `protobuf:"fixed64,16,opt,name=weight,proto3" json:"weight,omitempty"`
+	Dimensions  string                 `protobuf:"bytes,17,opt,name=dimensions,proto3" json:"dimensions,omitempty"`
+	Color       string                 `protobuf:"bytes,18,opt,name=color,proto3" json:"color,omitempty"`
+	Size        string                 `protobuf:"bytes,19,opt,name=size,proto3" json:"size,omitempty"`
+	Material    string                 `protobuf:"bytes,20,opt,name=material,proto3" json:"material,omitempty"`
+	Tags        string                 `protobuf:"bytes,21,opt,name=tags,proto3" json:"tags,omitempty"`
+	Images      string                 `protobuf:"bytes,22,opt,name=images,proto3" json:"images,omitempty"`
+	IsActive    bool                   `protobuf:"varint,23,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	IsDigital   bool                   `protobuf:"varint,24,opt,name=is_digital,json=isDigital,proto3" json:"is_digital,omitempty"`
+	IsFeatured  bool                   `protobuf:"varint,25,opt,name=is_featured,json=isFeatured,proto3" json:"is_featured,omitempty"`
+	IsOnSale    bool                   `protobuf:"varint,26,opt,name=is_on_sale,json=isOnSale,proto3" json:"is_on_sale,omitempty"`
+	SortOrder   int32                  `protobuf:"varint,27,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"`
+	ViewCount   int32                  `protobuf:"varint,28,opt,name=view_count,json=viewCount,proto3" json:"view_count,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,29,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt   *timestamppb.Timestamp `protobuf:"bytes,30,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Prices in the minor unit of the currency, e.g. cents for USD
+	PriceMinor        int64  `protobuf:"varint,31,opt,name=price_minor,json=priceMinor,proto3" json:"price_minor,omitempty"`
+	ComparePriceMinor int64  `protobuf:"varint,32,opt,name=compare_price_minor,json=comparePriceMinor,proto3" json:"compare_price_minor,omitempty"`
+	CostPriceMinor    int64  `protobuf:"varint,33,opt,name=cost_price_minor,json=costPriceMinor,proto3" json:"cost_price_minor,omitempty"`
+	Currency          string `protobuf:"bytes,34,opt,name=currency,proto3" json:"currency,omitempty"`
+	// Stock held by reservations and the stock left for sale
+	ReservedStock  int32             `protobuf:"varint,35,opt,name=reserved_stock,json=reservedStock,proto3" json:"reserved_stock,omitempty"`
+	AvailableStock int32             `protobuf:"varint,36,opt,name=available_stock,json=availableStock,proto3" json:"available_stock,omitempty"`
+	CategoryId     *uint32           `protobuf:"varint,37,opt,name=category_id,json=categoryId,proto3,oneof" json:"category_id,omitempty"` // Node of the category tree; category and sub_category are its legacy names
+	Variants       []*ProductVariant `protobuf:"bytes,38,rep,name=variants,proto3" json:"variants,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *Product) Reset() {
@@ -118,6 +131,7 @@ func (x *Product) GetShortDescription() string {
 	return ""
 }
 
+// Deprecated: Marked as deprecated in api/proto/product/product.proto.
 func (x *Product) GetPrice() float64 {
 	if x != nil {
 		return x.Price
@@ -125,6 +139,7 @@ func (x *Product) GetPrice() float64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in api/proto/product/product.proto.
 func (x *Product) GetComparePrice() float64 {
 	if x != nil {
 		return x.ComparePrice
@@ -132,6 +147,7 @@ func (x *Product) GetComparePrice() float64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in api/proto/product/product.proto.
 func (x *Product) GetCostPrice() float64 {
 	if x != nil {
 		return x.CostPrice
@@ -300,41 +316,280 @@ func (x *Product) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Product) GetPriceMinor() int64 {
+	if x != nil {
+		return x.PriceMinor
+	}
+	return 0
+}
+
+func (x *Product) GetComparePriceMinor() int64 {
+	if x != nil {
+		return x.ComparePriceMinor
+	}
+	return 0
+}
+
+func (x *Product) GetCostPriceMinor() int64 {
+	if x != nil {
+		return x.CostPriceMinor
+	}
+	return 0
+}
+
+func (x *Product) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Product) GetReservedStock() int32 {
+	if x != nil {
+		return x.ReservedStock
+	}
+	return 0
+}
+
+func (x *Product) GetAvailableStock() int32 {
+	if x != nil {
+		return x.AvailableStock
+	}
+	return 0
+}
+
+func (x *Product) GetCategoryId() uint32 {
+	if x != nil && x.CategoryId != nil {
+		return *x.CategoryId
+	}
+	return 0
+}
+
+func (x *Product) GetVariants() []*ProductVariant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+// ProductVariant message
+type ProductVariant struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId uint32                 `protobuf:"varint,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Name      string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Sku       string                 `protobuf:"bytes,4,opt,name=sku,proto3" json:"sku,omitempty"`
+	// Effective price and weight: the ones of the variant, or else the ones of the product
+	PriceMinor     int64                  `protobuf:"varint,5,opt,name=price_minor,json=priceMinor,proto3" json:"price_minor,omitempty"`
+	Weight         float64                `protobuf:"fixed64,6,opt,name=weight,proto3" json:"weight,omitempty"`
+	Stock          int32                  `protobuf:"varint,7,opt,name=stock,proto3" json:"stock,omitempty"`
+	ReservedStock  int32                  `protobuf:"varint,8,opt,name=reserved_stock,json=reservedStock,proto3" json:"reserved_stock,omitempty"`
+	AvailableStock int32                  `protobuf:"varint,9,opt,name=available_stock,json=availableStock,proto3" json:"available_stock,omitempty"`
+	Color          string                 `protobuf:"bytes,10,opt,name=color,proto3" json:"color,omitempty"`
+	Size           string                 `protobuf:"bytes,11,opt,name=size,proto3" json:"size,omitempty"`
+	Material       string                 `protobuf:"bytes,12,opt,name=material,proto3" json:"material,omitempty"`
+	Image          string                 `protobuf:"bytes,13,opt,name=image,proto3" json:"image,omitempty"`
+	IsActive       bool                   `protobuf:"varint,14,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	SortOrder      int32                  `protobuf:"varint,15,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ProductVariant) Reset() {
+	*x = ProductVariant{}
+	mi := &file_api_proto_product_product_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductVariant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductVariant) ProtoMessage() {}
+
+func (x *ProductVariant) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_product_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductVariant.ProtoReflect.Descriptor instead.
+func (*ProductVariant) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ProductVariant) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ProductVariant) GetProductId() uint32 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *ProductVariant) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ProductVariant) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *ProductVariant) GetPriceMinor() int64 {
+	if x != nil {
+		return x.PriceMinor
+	}
+	return 0
+}
+
+func (x *ProductVariant) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *ProductVariant) GetStock() int32 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+func (x *ProductVariant) GetReservedStock() int32 {
+	if x != nil {
+		return x.ReservedStock
+	}
+	return 0
+}
+
+func (x *ProductVariant) GetAvailableStock() int32 {
+	if x != nil {
+		return x.AvailableStock
+	}
+	return 0
+}
+
+func (x *ProductVariant) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *ProductVariant) GetSize() string {
+	if x != nil {
+		return x.Size
+	}
+	return ""
+}
+
+func (x *ProductVariant) GetMaterial() string {
+	if x != nil {
+		return x.Material
+	}
+	return ""
+}
+
+func (x *ProductVariant) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *ProductVariant) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+func (x *ProductVariant) GetSortOrder() int32 {
+	if x != nil {
+		return x.SortOrder
+	}
+	return 0
+}
+
+func (x *ProductVariant) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ProductVariant) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
 // CreateProduct messages
 type CreateProductRequest struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
 	Name             string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Description      string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
 	ShortDescription string                 `protobuf:"bytes,3,opt,name=short_description,json=shortDescription,proto3" json:"short_description,omitempty"`
-	Price            float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
-	ComparePrice     float64                `protobuf:"fixed64,5,opt,name=compare_price,json=comparePrice,proto3" json:"compare_price,omitempty"`
-	CostPrice        float64                `protobuf:"fixed64,6,opt,name=cost_price,json=costPrice,proto3" json:"cost_price,omitempty"`
-	Stock            int32                  `protobuf:"varint,7,opt,name=stock,proto3" json:"stock,omitempty"`
-	MinStock         int32                  `protobuf:"varint,8,opt,name=min_stock,json=minStock,proto3" json:"min_stock,omitempty"`
-	MaxStock         int32                  `protobuf:"varint,9,opt,name=max_stock,json=maxStock,proto3" json:"max_stock,omitempty"`
-	Category         string                 `protobuf:"bytes,10,opt,name=category,proto3" json:"category,omitempty"`
-	SubCategory      string                 `protobuf:"bytes,11,opt,name=sub_category,json=subCategory,proto3" json:"sub_category,omitempty"`
-	Brand            string                 `protobuf:"bytes,12,opt,name=brand,proto3" json:"brand,omitempty"`
-	Sku              string                 `protobuf:"bytes,13,opt,name=sku,proto3" json:"sku,omitempty"`
-	Barcode          string                 `protobuf:"bytes,14,opt,name=barcode,proto3" json:"barcode,omitempty"`
-	Weight           float64                `protobuf:"fixed64,15,opt,name=weight,proto3" json:"weight,omitempty"`
-	Dimensions       string                 `protobuf:"bytes,16,opt,name=dimensions,proto3" json:"dimensions,omitempty"`
-	Color            string                 `protobuf:"bytes,17,opt,name=color,proto3" json:"color,omitempty"`
-	Size             string                 `protobuf:"bytes,18,opt,name=size,proto3" json:"size,omitempty"`
-	Material         string                 `protobuf:"bytes,19,opt,name=material,proto3" json:"material,omitempty"`
-	Tags             string                 `protobuf:"bytes,20,opt,name=tags,proto3" json:"tags,omitempty"`
-	Images           string                 `protobuf:"bytes,21,opt,name=images,proto3" json:"images,omitempty"`
-	IsDigital        bool                   `protobuf:"varint,22,opt,name=is_digital,json=isDigital,proto3" json:"is_digital,omitempty"`
-	IsFeatured       bool                   `protobuf:"varint,23,opt,name=is_featured,json=isFeatured,proto3" json:"is_featured,omitempty"`
-	IsOnSale         bool                   `protobuf:"varint,24,opt,name=is_on_sale,json=isOnSale,proto3" json:"is_on_sale,omitempty"`
-	SortOrder        int32                  `protobuf:"varint,25,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	// Deprecated: Marked as deprecated in api/proto/product/product.proto.
+	Price float64 `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"` // Use price_minor
+	// Deprecated: Marked as deprecated in api/proto/product/product.proto.
+	ComparePrice float64 `protobuf:"fixed64,5,opt,name=compare_price,json=comparePrice,proto3" json:"compare_price,omitempty"` // Use compare_price_minor
+	// Deprecated: Marked as deprecated in api/proto/product/product.proto.
+	CostPrice   float64 `protobuf:"fixed64,6,opt,name=cost_price,json=costPrice,proto3" json:"cost_price,omitempty"` // Use cost_price_minor
+	Stock       int32   `protobuf:"varint,7,opt,name=stock,proto3" json:"stock,omitempty"`
+	MinStock    int32   `protobuf:"varint,8,opt,name=min_stock,json=minStock,proto3" json:"min_stock,omitempty"`
+	MaxStock    int32   `protobuf:"varint,9,opt,name=max_stock,json=maxStock,proto3" json:"max_stock,omitempty"`
+	Category    string  `protobuf:"bytes,10,opt,name=category,proto3" json:"category,omitempty"`
+	SubCategory string  `protobuf:"bytes,11,opt,name=sub_category,json=subCategory,proto3" json:"sub_category,omitempty"`
+	Brand       string  `protobuf:"bytes,12,opt,name=brand,proto3" json:"brand,omitempty"`
+	Sku         string  `protobuf:"bytes,13,opt,name=sku,proto3" json:"sku,omitempty"`
+	Barcode     string  `protobuf:"bytes,14,opt,name=barcode,proto3" json:"barcode,omitempty"`
+	Weight      float64 `protobuf:"fixed64,15,opt,name=weight,proto3" json:"weight,omitempty"`
+	Dimensions  string  `protobuf:"bytes,16,opt,name=dimensions,proto3" json:"dimensions,omitempty"`
+	Color       string  `protobuf:"bytes,17,opt,name=color,proto3" json:"color,omitempty"`
+	Size        string  `protobuf:"bytes,18,opt,name=size,proto3" json:"size,omitempty"`
+	Material    string  `protobuf:"bytes,19,opt,name=material,proto3" json:"material,omitempty"`
+	Tags        string  `protobuf:"bytes,20,opt,name=tags,proto3" json:"tags,omitempty"`
+	Images      string  `protobuf:"bytes,21,opt,name=images,proto3" json:"images,omitempty"`
+	IsDigital   bool    `protobuf:"varint,22,opt,name=is_digital,json=isDigital,proto3" json:"is_digital,omitempty"`
+	IsFeatured  bool    `protobuf:"varint,23,opt,name=is_featured,json=isFeatured,proto3" json:"is_featured,omitempty"`
+	IsOnSale    bool    `protobuf:"varint,24,opt,name=is_on_sale,json=isOnSale,proto3" json:"is_on_sale,omitempty"`
+	SortOrder   int32   `protobuf:"varint,25,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"`
+	// Prices in the minor unit of the currency, e.g. cents for USD
+	PriceMinor        int64   `protobuf:"varint,26,opt,name=price_minor,json=priceMinor,proto3" json:"price_minor,omitempty"`
+	ComparePriceMinor int64   `protobuf:"varint,27,opt,name=compare_price_minor,json=comparePriceMinor,proto3" json:"compare_price_minor,omitempty"`
+	CostPriceMinor    int64   `protobuf:"varint,28,opt,name=cost_price_minor,json=costPriceMinor,proto3" json:"cost_price_minor,omitempty"`
+	Currency          string  `protobuf:"bytes,29,opt,name=currency,proto3" json:"currency,omitempty"`
+	CategoryId        *uint32 `protobuf:"varint,30,opt,name=category_id,json=categoryId,proto3,oneof" json:"category_id,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *CreateProductRequest) Reset() {
 	*x = CreateProductRequest{}
-	mi := &file_api_proto_product_product_proto_msgTypes[1]
+	mi := &file_api_proto_product_product_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -346,7 +601,7 @@ func (x *CreateProductRequest) String() string {
 func (*CreateProductRequest) ProtoMessage() {}
 
 func (x *CreateProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_product_product_proto_msgTypes[1]
+	mi := &file_api_proto_product_product_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -359,7 +614,7 @@ func (x *CreateProductRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateProductRequest.ProtoReflect.Descriptor instead.
 func (*CreateProductRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_product_product_proto_rawDescGZIP(), []int{1}
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *CreateProductRequest) GetName() string {
@@ -383,6 +638,7 @@ func (x *CreateProductRequest) GetShortDescription() string {
 	return ""
 }
 
+// Deprecated: Marked as deprecated in api/proto/product/product.proto.
 func (x *CreateProductRequest) GetPrice() float64 {
 	if x != nil {
 		return x.Price
@@ -390,6 +646,7 @@ func (x *CreateProductRequest) GetPrice() float64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in api/proto/product/product.proto.
 func (x *CreateProductRequest) GetComparePrice() float64 {
 	if x != nil {
 		return x.ComparePrice
@@ -397,6 +654,7 @@ func (x *CreateProductRequest) GetComparePrice() float64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in api/proto/product/product.proto.
 func (x *CreateProductRequest) GetCostPrice() float64 {
 	if x != nil {
 		return x.CostPrice
@@ -537,6 +795,41 @@ func (x *CreateProductRequest) GetSortOrder() int32 {
 	return 0
 }
 
+func (x *CreateProductRequest) GetPriceMinor() int64 {
+	if x != nil {
+		return x.PriceMinor
+	}
+	return 0
+}
+
+func (x *CreateProductRequest) GetComparePriceMinor() int64 {
+	if x != nil {
+		return x.ComparePriceMinor
+	}
+	return 0
+}
+
+func (x *CreateProductRequest) GetCostPriceMinor() int64 {
+	if x != nil {
+		return x.CostPriceMinor
+	}
+	return 0
+}
+
+func (x *CreateProductRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CreateProductRequest) GetCategoryId() uint32 {
+	if x != nil && x.CategoryId != nil {
+		return *x.CategoryId
+	}
+	return 0
+}
+
 type ProductResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
@@ -546,7 +839,7 @@ type ProductResponse struct {
 
 func (x *ProductResponse) Reset() {
 	*x = ProductResponse{}
-	mi := &file_api_proto_product_product_proto_msgTypes[2]
+	mi := &file_api_proto_product_product_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -558,7 +851,7 @@ func (x *ProductResponse) String() string {
 func (*ProductResponse) ProtoMessage() {}
 
 func (x *ProductResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_product_product_proto_msgTypes[2]
+	mi := &file_api_proto_product_product_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -571,7 +864,7 @@ func (x *ProductResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProductResponse.ProtoReflect.Descriptor instead.
 func (*ProductResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_product_product_proto_rawDescGZIP(), []int{2}
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *ProductResponse) GetProduct() *Product {
@@ -591,7 +884,7 @@ type GetProductRequest struct {
 
 func (x *GetProductRequest) Reset() {
 	*x = GetProductRequest{}
-	mi := &file_api_proto_product_product_proto_msgTypes[3]
+	mi := &file_api_proto_product_product_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -603,7 +896,7 @@ func (x *GetProductRequest) String() string {
 func (*GetProductRequest) ProtoMessage() {}
 
 func (x *GetProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_product_product_proto_msgTypes[3]
+	mi := &file_api_proto_product_product_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -616,7 +909,7 @@ func (x *GetProductRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetProductRequest.ProtoReflect.Descriptor instead.
 func (*GetProductRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_product_product_proto_rawDescGZIP(), []int{3}
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GetProductRequest) GetId() uint32 {
@@ -635,7 +928,7 @@ type GetProductBySKURequest struct {
 
 func (x *GetProductBySKURequest) Reset() {
 	*x = GetProductBySKURequest{}
-	mi := &file_api_proto_product_product_proto_msgTypes[4]
+	mi := &file_api_proto_product_product_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -647,7 +940,7 @@ func (x *GetProductBySKURequest) String() string {
 func (*GetProductBySKURequest) ProtoMessage() {}
 
 func (x *GetProductBySKURequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_product_product_proto_msgTypes[4]
+	mi := &file_api_proto_product_product_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -660,7 +953,7 @@ func (x *GetProductBySKURequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetProductBySKURequest.ProtoReflect.Descriptor instead.
 func (*GetProductBySKURequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_product_product_proto_rawDescGZIP(), []int{4}
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetProductBySKURequest) GetSku() string {
@@ -677,35 +970,44 @@ type UpdateProductRequest struct {
 	Name             *string                `protobuf:"bytes,2,opt,name=name,proto3,oneof" json:"name,omitempty"`
 	Description      *string                `protobuf:"bytes,3,opt,name=description,proto3,oneof" json:"description,omitempty"`
 	ShortDescription *string                `protobuf:"bytes,4,opt,name=short_description,json=shortDescription,proto3,oneof" json:"short_description,omitempty"`
-	Price            *float64               `protobuf:"fixed64,5,opt,name=price,proto3,oneof" json:"price,omitempty"`
-	ComparePrice     *float64               `protobuf:"fixed64,6,opt,name=compare_price,json=comparePrice,proto3,oneof" json:"compare_price,omitempty"`
-	CostPrice        *float64               `protobuf:"fixed64,7,opt,name=cost_price,json=costPrice,proto3,oneof" json:"cost_price,omitempty"`
-	Stock            *int32                 `protobuf:"varint,8,opt,name=stock,proto3,oneof" json:"stock,omitempty"`
-	MinStock         *int32                 `protobuf:"varint,9,opt,name=min_stock,json=minStock,proto3,oneof" json:"min_stock,omitempty"`
-	MaxStock         *int32                 `protobuf:"varint,10,opt,name=max_stock,json=maxStock,proto3,oneof" json:"max_stock,omitempty"`
-	Category         *string                `protobuf:"bytes,11,opt,name=category,proto3,oneof" json:"category,omitempty"`
-	SubCategory      *string                `protobuf:"bytes,12,opt,name=sub_category,json=subCategory,proto3,oneof" json:"sub_category,omitempty"`
-	Brand            *string                `protobuf:"bytes,13,opt,name=brand,proto3,oneof" json:"brand,omitempty"`
-	Barcode          *string                `protobuf:"bytes,14,opt,name=barcode,proto3,oneof" json:"barcode,omitempty"`
-	Weight           *float64               `protobuf:"fixed64,15,opt,name=weight,proto3,oneof" json:"weight,omitempty"`
-	Dimensions       *string                `protobuf:"bytes,16,opt,name=dimensions,proto3,oneof" json:"dimensions,omitempty"`
-	Color            *string                `protobuf:"bytes,17,opt,name=color,proto3,oneof" json:"color,omitempty"`
-	Size             *string                `protobuf:"bytes,18,opt,name=size,proto3,oneof" json:"size,omitempty"`
-	Material         *string                `protobuf:"bytes,19,opt,name=material,proto3,oneof" json:"material,omitempty"`
-	Tags             *string                `protobuf:"bytes,20,opt,name=tags,proto3,oneof" json:"tags,omitempty"`
-	Images           *string                `protobuf:"bytes,21,opt,name=images,proto3,oneof" json:"images,omitempty"`
-	IsActive         *bool                  `protobuf:"varint,22,opt,name=is_active,json=isActive,proto3,oneof" json:"is_active,omitempty"`
-	IsDigital        *bool                  `protobuf:"varint,23,opt,name=is_digital,json=isDigital,proto3,oneof" json:"is_digital,omitempty"`
-	IsFeatured       *bool                  `protobuf:"varint,24,opt,name=is_featured,json=isFeatured,proto3,oneof" json:"is_featured,omitempty"`
-	IsOnSale         *bool                  `protobuf:"varint,25,opt,name=is_on_sale,json=isOnSale,proto3,oneof" json:"is_on_sale,omitempty"`
-	SortOrder        *int32                 `protobuf:"varint,26,opt,name=sort_order,json=sortOrder,proto3,oneof" json:"sort_order,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	// Deprecated: Marked as deprecated in api/proto/product/product.proto.
+	Price *float64 `protobuf:"fixed64,5,opt,name=price,proto3,oneof" json:"price,omitempty"` // Use price_minor
+	// Deprecated: Marked as deprecated in api/proto/product/product.proto.
+	ComparePrice *float64 `protobuf:"fixed64,6,opt,name=compare_price,json=comparePrice,proto3,oneof" json:"compare_price,omitempty"` // Use compare_price_minor
+	// Deprecated: Marked as deprecated in api/proto/product/product.proto.
+	CostPrice   *float64 `protobuf:"fixed64,7,opt,name=cost_price,json=costPrice,proto3,oneof" json:"cost_price,omitempty"` // Use cost_price_minor
+	Stock       *int32   `protobuf:"varint,8,opt,name=stock,proto3,oneof" json:"stock,omitempty"`
+	MinStock    *int32   `protobuf:"varint,9,opt,name=min_stock,json=minStock,proto3,oneof" json:"min_stock,omitempty"`
+	MaxStock    *int32   `protobuf:"varint,10,opt,name=max_stock,json=maxStock,proto3,oneof" json:"max_stock,omitempty"`
+	Category    *string  `protobuf:"bytes,11,opt,name=category,proto3,oneof" json:"category,omitempty"`
+	SubCategory *string  `protobuf:"bytes,12,opt,name=sub_category,json=subCategory,proto3,oneof" json:"sub_category,omitempty"`
+	Brand       *string  `protobuf:"bytes,13,opt,name=brand,proto3,oneof" json:"brand,omitempty"`
+	Barcode     *string  `protobuf:"bytes,14,opt,name=barcode,proto3,oneof" json:"barcode,omitempty"`
+	Weight      *float64 `protobuf:"fixed64,15,opt,name=weight,proto3,oneof" json:"weight,omitempty"`
+	Dimensions  *string  `protobuf:"bytes,16,opt,name=dimensions,proto3,oneof" json:"dimensions,omitempty"`
+	Color       *string  `protobuf:"bytes,17,opt,name=color,proto3,oneof" json:"color,omitempty"`
+	Size        *string  `protobuf:"bytes,18,opt,name=size,proto3,oneof" json:"size,omitempty"`
+	Material    *string  `protobuf:"bytes,19,opt,name=material,proto3,oneof" json:"material,omitempty"`
+	Tags        *string  `protobuf:"bytes,20,opt,name=tags,proto3,oneof" json:"tags,omitempty"`
+	Images      *string  `protobuf:"bytes,21,opt,name=images,proto3,oneof" json:"images,omitempty"`
+	IsActive    *bool    `protobuf:"varint,22,opt,name=is_active,json=isActive,proto3,oneof" json:"is_active,omitempty"`
+	IsDigital   *bool    `protobuf:"varint,23,opt,name=is_digital,json=isDigital,proto3,oneof" json:"is_digital,omitempty"`
+	IsFeatured  *bool    `protobuf:"varint,24,opt,name=is_featured,json=isFeatured,proto3,oneof" json:"is_featured,omitempty"`
+	IsOnSale    *bool    `protobuf:"varint,25,opt,name=is_on_sale,json=isOnSale,proto3,oneof" json:"is_on_sale,omitempty"`
+	SortOrder   *int32   `protobuf:"varint,26,opt,name=sort_order,json=sortOrder,proto3,oneof" json:"sort_order,omitempty"`
+	// Prices in the minor unit of the currency, e.g. cents for USD
+	PriceMinor        *int64  `protobuf:"varint,27,opt,name=price_minor,json=priceMinor,proto3,oneof" json:"price_minor,omitempty"`
+	ComparePriceMinor *int64  `protobuf:"varint,28,opt,name=compare_price_minor,json=comparePriceMinor,proto3,oneof" json:"compare_price_minor,omitempty"`
+	CostPriceMinor    *int64  `protobuf:"varint,29,opt,name=cost_price_minor,json=costPriceMinor,proto3,oneof" json:"cost_price_minor,omitempty"`
+	Currency          *string `protobuf:"bytes,30,opt,name=currency,proto3,oneof" json:"currency,omitempty"`
+	CategoryId        *uint32 `protobuf:"varint,31,opt,name=category_id,json=categoryId,proto3,oneof" json:"category_id,omitempty"` // 0 unlinks the product from the category tree
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *UpdateProductRequest) Reset() {
 	*x = UpdateProductRequest{}
-	mi := &file_api_proto_product_product_proto_msgTypes[5]
+	mi := &file_api_proto_product_product_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -717,7 +1019,7 @@ func (x *UpdateProductRequest) String() string {
 func (*UpdateProductRequest) ProtoMessage() {}
 
 func (x *UpdateProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_product_product_proto_msgTypes[5]
+	mi := &file_api_proto_product_product_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -730,7 +1032,7 @@ func (x *UpdateProductRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateProductRequest.ProtoReflect.Descriptor instead.
 func (*UpdateProductRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_product_product_proto_rawDescGZIP(), []int{5}
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *UpdateProductRequest) GetId() uint32 {
@@ -761,6 +1063,7 @@ func (x *UpdateProductRequest) GetShortDescription() string {
 	return ""
 }
 
+// Deprecated: Marked as deprecated in api/proto/product/product.proto.
 func (x *UpdateProductRequest) GetPrice() float64 {
 	if x != nil && x.Price != nil {
 		return *x.Price
@@ -768,6 +1071,7 @@ func (x *UpdateProductRequest) GetPrice() float64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in api/proto/product/product.proto.
 func (x *UpdateProductRequest) GetComparePrice() float64 {
 	if x != nil && x.ComparePrice != nil {
 		return *x.ComparePrice
@@ -775,6 +1079,7 @@ func (x *UpdateProductRequest) GetComparePrice() float64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in api/proto/product/product.proto.
 func (x *UpdateProductRequest) GetCostPrice() float64 {
 	if x != nil && x.CostPrice != nil {
 		return *x.CostPrice
@@ -915,6 +1220,41 @@ func (x *UpdateProductRequest) GetSortOrder() int32 {
 	return 0
 }
 
+func (x *UpdateProductRequest) GetPriceMinor() int64 {
+	if x != nil && x.PriceMinor != nil {
+		return *x.PriceMinor
+	}
+	return 0
+}
+
+func (x *UpdateProductRequest) GetComparePriceMinor() int64 {
+	if x != nil && x.ComparePriceMinor != nil {
+		return *x.ComparePriceMinor
+	}
+	return 0
+}
+
+func (x *UpdateProductRequest) GetCostPriceMinor() int64 {
+	if x != nil && x.CostPriceMinor != nil {
+		return *x.CostPriceMinor
+	}
+	return 0
+}
+
+func (x *UpdateProductRequest) GetCurrency() string {
+	if x != nil && x.Currency != nil {
+		return *x.Currency
+	}
+	return ""
+}
+
+func (x *UpdateProductRequest) GetCategoryId() uint32 {
+	if x != nil && x.CategoryId != nil {
+		return *x.CategoryId
+	}
+	return 0
+}
+
 // DeleteProduct messages
 type DeleteProductRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -925,7 +1265,7 @@ type DeleteProductRequest struct {
 
 func (x *DeleteProductRequest) Reset() {
 	*x = DeleteProductRequest{}
-	mi := &file_api_proto_product_product_proto_msgTypes[6]
+	mi := &file_api_proto_product_product_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -937,7 +1277,7 @@ func (x *DeleteProductRequest) String() string {
 func (*DeleteProductRequest) ProtoMessage() {}
 
 func (x *DeleteProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_product_product_proto_msgTypes[6]
+	mi := &file_api_proto_product_product_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -950,7 +1290,7 @@ func (x *DeleteProductRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteProductRequest.ProtoReflect.Descriptor instead.
 func (*DeleteProductRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_product_product_proto_rawDescGZIP(), []int{6}
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *DeleteProductRequest) GetId() uint32 {
@@ -969,7 +1309,7 @@ type DeleteProductResponse struct {
 
 func (x *DeleteProductResponse) Reset() {
 	*x = DeleteProductResponse{}
-	mi := &file_api_proto_product_product_proto_msgTypes[7]
+	mi := &file_api_proto_product_product_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -981,7 +1321,7 @@ func (x *DeleteProductResponse) String() string {
 func (*DeleteProductResponse) ProtoMessage() {}
 
 func (x *DeleteProductResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_product_product_proto_msgTypes[7]
+	mi := &file_api_proto_product_product_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -994,7 +1334,7 @@ func (x *DeleteProductResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteProductResponse.ProtoReflect.Descriptor instead.
 func (*DeleteProductResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_product_product_proto_rawDescGZIP(), []int{7}
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *DeleteProductResponse) GetMessage() string {
@@ -1015,7 +1355,7 @@ type ListProductsRequest struct {
 
 func (x *ListProductsRequest) Reset() {
 	*x = ListProductsRequest{}
-	mi := &file_api_proto_product_product_proto_msgTypes[8]
+	mi := &file_api_proto_product_product_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1027,7 +1367,7 @@ func (x *ListProductsRequest) String() string {
 func (*ListProductsRequest) ProtoMessage() {}
 
 func (x *ListProductsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_product_product_proto_msgTypes[8]
+	mi := &file_api_proto_product_product_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1040,7 +1380,7 @@ func (x *ListProductsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListProductsRequest.ProtoReflect.Descriptor instead.
 func (*ListProductsRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_product_product_proto_rawDescGZIP(), []int{8}
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ListProductsRequest) GetOffset() int32 {
@@ -1069,7 +1409,7 @@ type ListProductsResponse struct {
 
 func (x *ListProductsResponse) Reset() {
 	*x = ListProductsResponse{}
-	mi := &file_api_proto_product_product_proto_msgTypes[9]
+	mi := &file_api_proto_product_product_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1081,7 +1421,7 @@ func (x *ListProductsResponse) String() string {
 func (*ListProductsResponse) ProtoMessage() {}
 
 func (x *ListProductsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_product_product_proto_msgTypes[9]
+	mi := &file_api_proto_product_product_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1094,7 +1434,7 @@ func (x *ListProductsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListProductsResponse.ProtoReflect.Descriptor instead.
 func (*ListProductsResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_product_product_proto_rawDescGZIP(), []int{9}
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ListProductsResponse) GetProducts() []*Product {
@@ -1127,25 +1467,30 @@ func (x *ListProductsResponse) GetLimit() int32 {
 
 // SearchProducts messages
 type SearchProductsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
-	Category      string                 `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`
-	Brand         string                 `protobuf:"bytes,3,opt,name=brand,proto3" json:"brand,omitempty"`
-	MinPrice      float64                `protobuf:"fixed64,4,opt,name=min_price,json=minPrice,proto3" json:"min_price,omitempty"`
-	MaxPrice      float64                `protobuf:"fixed64,5,opt,name=max_price,json=maxPrice,proto3" json:"max_price,omitempty"`
-	IsActive      *bool                  `protobuf:"varint,6,opt,name=is_active,json=isActive,proto3,oneof" json:"is_active,omitempty"`
-	IsDigital     *bool                  `protobuf:"varint,7,opt,name=is_digital,json=isDigital,proto3,oneof" json:"is_digital,omitempty"`
-	IsFeatured    *bool                  `protobuf:"varint,8,opt,name=is_featured,json=isFeatured,proto3,oneof" json:"is_featured,omitempty"`
-	IsOnSale      *bool                  `protobuf:"varint,9,opt,name=is_on_sale,json=isOnSale,proto3,oneof" json:"is_on_sale,omitempty"`
-	Offset        int32                  `protobuf:"varint,10,opt,name=offset,proto3" json:"offset,omitempty"`
-	Limit         int32                  `protobuf:"varint,11,opt,name=limit,proto3" json:"limit,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Query    string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Category string                 `protobuf:"bytes,2,opt,name=category,proto3" json:"category,omitempty"`
+	Brand    string                 `protobuf:"bytes,3,opt,name=brand,proto3" json:"brand,omitempty"`
+	// Deprecated: Marked as deprecated in api/proto/product/product.proto.
+	MinPrice float64 `protobuf:"fixed64,4,opt,name=min_price,json=minPrice,proto3" json:"min_price,omitempty"` // Use min_price_minor
+	// Deprecated: Marked as deprecated in api/proto/product/product.proto.
+	MaxPrice   float64 `protobuf:"fixed64,5,opt,name=max_price,json=maxPrice,proto3" json:"max_price,omitempty"` // Use max_price_minor
+	IsActive   *bool   `protobuf:"varint,6,opt,name=is_active,json=isActive,proto3,oneof" json:"is_active,omitempty"`
+	IsDigital  *bool   `protobuf:"varint,7,opt,name=is_digital,json=isDigital,proto3,oneof" json:"is_digital,omitempty"`
+	IsFeatured *bool   `protobuf:"varint,8,opt,name=is_featured,json=isFeatured,proto3,oneof" json:"is_featured,omitempty"`
+	IsOnSale   *bool   `protobuf:"varint,9,opt,name=is_on_sale,json=isOnSale,proto3,oneof" json:"is_on_sale,omitempty"`
+	Offset     int32   `protobuf:"varint,10,opt,name=offset,proto3" json:"offset,omitempty"`
+	Limit      int32   `protobuf:"varint,11,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Price range in minor units, e.g. cents for USD
+	MinPriceMinor int64 `protobuf:"varint,12,opt,name=min_price_minor,json=minPriceMinor,proto3" json:"min_price_minor,omitempty"`
+	MaxPriceMinor int64 `protobuf:"varint,13,opt,name=max_price_minor,json=maxPriceMinor,proto3" json:"max_price_minor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SearchProductsRequest) Reset() {
 	*x = SearchProductsRequest{}
-	mi := &file_api_proto_product_product_proto_msgTypes[10]
+	mi := &file_api_proto_product_product_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1157,7 +1502,7 @@ func (x *SearchProductsRequest) String() string {
 func (*SearchProductsRequest) ProtoMessage() {}
 
 func (x *SearchProductsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_product_product_proto_msgTypes[10]
+	mi := &file_api_proto_product_product_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1170,7 +1515,7 @@ func (x *SearchProductsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SearchProductsRequest.ProtoReflect.Descriptor instead.
 func (*SearchProductsRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_product_product_proto_rawDescGZIP(), []int{10}
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *SearchProductsRequest) GetQuery() string {
@@ -1194,6 +1539,7 @@ func (x *SearchProductsRequest) GetBrand() string {
 	return ""
 }
 
+// Deprecated: Marked as deprecated in api/proto/product/product.proto.
 func (x *SearchProductsRequest) GetMinPrice() float64 {
 	if x != nil {
 		return x.MinPrice
@@ -1201,6 +1547,7 @@ func (x *SearchProductsRequest) GetMinPrice() float64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in api/proto/product/product.proto.
 func (x *SearchProductsRequest) GetMaxPrice() float64 {
 	if x != nil {
 		return x.MaxPrice
@@ -1222,59 +1569,779 @@ func (x *SearchProductsRequest) GetIsDigital() bool {
 	return false
 }
 
-func (x *SearchProductsRequest) GetIsFeatured() bool {
-	if x != nil && x.IsFeatured != nil {
-		return *x.IsFeatured
+func (x *SearchProductsRequest) GetIsFeatured() bool {
+	if x != nil && x.IsFeatured != nil {
+		return *x.IsFeatured
+	}
+	return false
+}
+
+func (x *SearchProductsRequest) GetIsOnSale() bool {
+	if x != nil && x.IsOnSale != nil {
+		return *x.IsOnSale
+	}
+	return false
+}
+
+func (x *SearchProductsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *SearchProductsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *SearchProductsRequest) GetMinPriceMinor() int64 {
+	if x != nil {
+		return x.MinPriceMinor
+	}
+	return 0
+}
+
+func (x *SearchProductsRequest) GetMaxPriceMinor() int64 {
+	if x != nil {
+		return x.MaxPriceMinor
+	}
+	return 0
+}
+
+// ListProductsByCategory messages
+type ListProductsByCategoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Category      string                 `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"` // Category slug or name; products of its subcategories are included
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProductsByCategoryRequest) Reset() {
+	*x = ListProductsByCategoryRequest{}
+	mi := &file_api_proto_product_product_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProductsByCategoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProductsByCategoryRequest) ProtoMessage() {}
+
+func (x *ListProductsByCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_product_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProductsByCategoryRequest.ProtoReflect.Descriptor instead.
+func (*ListProductsByCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListProductsByCategoryRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *ListProductsByCategoryRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ListProductsByCategoryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// Category messages
+type Category struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Slug          string                 `protobuf:"bytes,4,opt,name=slug,proto3" json:"slug,omitempty"`
+	ParentId      *uint32                `protobuf:"varint,5,opt,name=parent_id,json=parentId,proto3,oneof" json:"parent_id,omitempty"`
+	Depth         int32                  `protobuf:"varint,6,opt,name=depth,proto3" json:"depth,omitempty"`
+	Image         string                 `protobuf:"bytes,7,opt,name=image,proto3" json:"image,omitempty"`
+	Icon          string                 `protobuf:"bytes,8,opt,name=icon,proto3" json:"icon,omitempty"`
+	SortOrder     int32                  `protobuf:"varint,9,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"`
+	IsActive      bool                   `protobuf:"varint,10,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	Children      []*Category            `protobuf:"bytes,11,rep,name=children,proto3" json:"children,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Category) Reset() {
+	*x = Category{}
+	mi := &file_api_proto_product_product_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Category) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Category) ProtoMessage() {}
+
+func (x *Category) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_product_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Category.ProtoReflect.Descriptor instead.
+func (*Category) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Category) GetId() uint32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Category) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Category) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Category) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *Category) GetParentId() uint32 {
+	if x != nil && x.ParentId != nil {
+		return *x.ParentId
+	}
+	return 0
+}
+
+func (x *Category) GetDepth() int32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *Category) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *Category) GetIcon() string {
+	if x != nil {
+		return x.Icon
+	}
+	return ""
+}
+
+func (x *Category) GetSortOrder() int32 {
+	if x != nil {
+		return x.SortOrder
+	}
+	return 0
+}
+
+func (x *Category) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+func (x *Category) GetChildren() []*Category {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+func (x *Category) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Category) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type GetCategoryTreeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCategoryTreeRequest) Reset() {
+	*x = GetCategoryTreeRequest{}
+	mi := &file_api_proto_product_product_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCategoryTreeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCategoryTreeRequest) ProtoMessage() {}
+
+func (x *GetCategoryTreeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_product_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCategoryTreeRequest.ProtoReflect.Descriptor instead.
+func (*GetCategoryTreeRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{14}
+}
+
+type CategoryTreeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Categories    []*Category            `protobuf:"bytes,1,rep,name=categories,proto3" json:"categories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CategoryTreeResponse) Reset() {
+	*x = CategoryTreeResponse{}
+	mi := &file_api_proto_product_product_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CategoryTreeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoryTreeResponse) ProtoMessage() {}
+
+func (x *CategoryTreeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_product_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoryTreeResponse.ProtoReflect.Descriptor instead.
+func (*CategoryTreeResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CategoryTreeResponse) GetCategories() []*Category {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+type GetCategoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Key:
+	//
+	//	*GetCategoryRequest_Id
+	//	*GetCategoryRequest_Slug
+	Key           isGetCategoryRequest_Key `protobuf_oneof:"key"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCategoryRequest) Reset() {
+	*x = GetCategoryRequest{}
+	mi := &file_api_proto_product_product_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCategoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCategoryRequest) ProtoMessage() {}
+
+func (x *GetCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_product_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCategoryRequest.ProtoReflect.Descriptor instead.
+func (*GetCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetCategoryRequest) GetKey() isGetCategoryRequest_Key {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *GetCategoryRequest) GetId() uint32 {
+	if x != nil {
+		if x, ok := x.Key.(*GetCategoryRequest_Id); ok {
+			return x.Id
+		}
+	}
+	return 0
+}
+
+func (x *GetCategoryRequest) GetSlug() string {
+	if x != nil {
+		if x, ok := x.Key.(*GetCategoryRequest_Slug); ok {
+			return x.Slug
+		}
+	}
+	return ""
+}
+
+type isGetCategoryRequest_Key interface {
+	isGetCategoryRequest_Key()
+}
+
+type GetCategoryRequest_Id struct {
+	Id uint32 `protobuf:"varint,1,opt,name=id,proto3,oneof"`
+}
+
+type GetCategoryRequest_Slug struct {
+	Slug string `protobuf:"bytes,2,opt,name=slug,proto3,oneof"`
+}
+
+func (*GetCategoryRequest_Id) isGetCategoryRequest_Key() {}
+
+func (*GetCategoryRequest_Slug) isGetCategoryRequest_Key() {}
+
+type CategoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Category      *Category              `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CategoryResponse) Reset() {
+	*x = CategoryResponse{}
+	mi := &file_api_proto_product_product_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CategoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoryResponse) ProtoMessage() {}
+
+func (x *CategoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_product_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoryResponse.ProtoReflect.Descriptor instead.
+func (*CategoryResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CategoryResponse) GetCategory() *Category {
+	if x != nil {
+		return x.Category
+	}
+	return nil
+}
+
+// Stock management messages
+type UpdateStockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     uint32                 `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Stock         int32                  `protobuf:"varint,2,opt,name=stock,proto3" json:"stock,omitempty"`
+	VariantId     *uint32                `protobuf:"varint,3,opt,name=variant_id,json=variantId,proto3,oneof" json:"variant_id,omitempty"` // Variant to count; unset for the product itself
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateStockRequest) Reset() {
+	*x = UpdateStockRequest{}
+	mi := &file_api_proto_product_product_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateStockRequest) ProtoMessage() {}
+
+func (x *UpdateStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_product_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateStockRequest.ProtoReflect.Descriptor instead.
+func (*UpdateStockRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *UpdateStockRequest) GetProductId() uint32 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *UpdateStockRequest) GetStock() int32 {
+	if x != nil {
+		return x.Stock
+	}
+	return 0
+}
+
+func (x *UpdateStockRequest) GetVariantId() uint32 {
+	if x != nil && x.VariantId != nil {
+		return *x.VariantId
+	}
+	return 0
+}
+
+type UpdateStockResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateStockResponse) Reset() {
+	*x = UpdateStockResponse{}
+	mi := &file_api_proto_product_product_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateStockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateStockResponse) ProtoMessage() {}
+
+func (x *UpdateStockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_product_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateStockResponse.ProtoReflect.Descriptor instead.
+func (*UpdateStockResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *UpdateStockResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ReduceStockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     uint32                 `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Amount        int32                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	WarehouseId   *uint32                `protobuf:"varint,3,opt,name=warehouse_id,json=warehouseId,proto3,oneof" json:"warehouse_id,omitempty"` // Only warehouse to take from; unset to allocate
+	Latitude      *float64               `protobuf:"fixed64,4,opt,name=latitude,proto3,oneof" json:"latitude,omitempty"`                         // Destination, for nearest-first allocation
+	Longitude     *float64               `protobuf:"fixed64,5,opt,name=longitude,proto3,oneof" json:"longitude,omitempty"`
+	VariantId     *uint32                `protobuf:"varint,6,opt,name=variant_id,json=variantId,proto3,oneof" json:"variant_id,omitempty"` // Variant to take from; unset for the product itself
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReduceStockRequest) Reset() {
+	*x = ReduceStockRequest{}
+	mi := &file_api_proto_product_product_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReduceStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReduceStockRequest) ProtoMessage() {}
+
+func (x *ReduceStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_product_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReduceStockRequest.ProtoReflect.Descriptor instead.
+func (*ReduceStockRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ReduceStockRequest) GetProductId() uint32 {
+	if x != nil {
+		return x.ProductId
+	}
+	return 0
+}
+
+func (x *ReduceStockRequest) GetAmount() int32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *ReduceStockRequest) GetWarehouseId() uint32 {
+	if x != nil && x.WarehouseId != nil {
+		return *x.WarehouseId
+	}
+	return 0
+}
+
+func (x *ReduceStockRequest) GetLatitude() float64 {
+	if x != nil && x.Latitude != nil {
+		return *x.Latitude
+	}
+	return 0
+}
+
+func (x *ReduceStockRequest) GetLongitude() float64 {
+	if x != nil && x.Longitude != nil {
+		return *x.Longitude
+	}
+	return 0
+}
+
+func (x *ReduceStockRequest) GetVariantId() uint32 {
+	if x != nil && x.VariantId != nil {
+		return *x.VariantId
+	}
+	return 0
+}
+
+type ReduceStockResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReduceStockResponse) Reset() {
+	*x = ReduceStockResponse{}
+	mi := &file_api_proto_product_product_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReduceStockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReduceStockResponse) ProtoMessage() {}
+
+func (x *ReduceStockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_product_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReduceStockResponse.ProtoReflect.Descriptor instead.
+func (*ReduceStockResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ReduceStockResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type IncreaseStockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     uint32                 `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Amount        int32                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	WarehouseId   *uint32                `protobuf:"varint,3,opt,name=warehouse_id,json=warehouseId,proto3,oneof" json:"warehouse_id,omitempty"` // Warehouse to put the stock into; unset for the first by priority
+	VariantId     *uint32                `protobuf:"varint,4,opt,name=variant_id,json=variantId,proto3,oneof" json:"variant_id,omitempty"`       // Variant to put into; unset for the product itself
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IncreaseStockRequest) Reset() {
+	*x = IncreaseStockRequest{}
+	mi := &file_api_proto_product_product_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IncreaseStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IncreaseStockRequest) ProtoMessage() {}
+
+func (x *IncreaseStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_product_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IncreaseStockRequest.ProtoReflect.Descriptor instead.
+func (*IncreaseStockRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *IncreaseStockRequest) GetProductId() uint32 {
+	if x != nil {
+		return x.ProductId
 	}
-	return false
+	return 0
 }
 
-func (x *SearchProductsRequest) GetIsOnSale() bool {
-	if x != nil && x.IsOnSale != nil {
-		return *x.IsOnSale
+func (x *IncreaseStockRequest) GetAmount() int32 {
+	if x != nil {
+		return x.Amount
 	}
-	return false
+	return 0
 }
 
-func (x *SearchProductsRequest) GetOffset() int32 {
-	if x != nil {
-		return x.Offset
+func (x *IncreaseStockRequest) GetWarehouseId() uint32 {
+	if x != nil && x.WarehouseId != nil {
+		return *x.WarehouseId
 	}
 	return 0
 }
 
-func (x *SearchProductsRequest) GetLimit() int32 {
-	if x != nil {
-		return x.Limit
+func (x *IncreaseStockRequest) GetVariantId() uint32 {
+	if x != nil && x.VariantId != nil {
+		return *x.VariantId
 	}
 	return 0
 }
 
-// ListProductsByCategory messages
-type ListProductsByCategoryRequest struct {
+type IncreaseStockResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Category      string                 `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
-	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
-	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListProductsByCategoryRequest) Reset() {
-	*x = ListProductsByCategoryRequest{}
-	mi := &file_api_proto_product_product_proto_msgTypes[11]
+func (x *IncreaseStockResponse) Reset() {
+	*x = IncreaseStockResponse{}
+	mi := &file_api_proto_product_product_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListProductsByCategoryRequest) String() string {
+func (x *IncreaseStockResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListProductsByCategoryRequest) ProtoMessage() {}
+func (*IncreaseStockResponse) ProtoMessage() {}
 
-func (x *ListProductsByCategoryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_product_product_proto_msgTypes[11]
+func (x *IncreaseStockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_product_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1285,56 +2352,43 @@ func (x *ListProductsByCategoryRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListProductsByCategoryRequest.ProtoReflect.Descriptor instead.
-func (*ListProductsByCategoryRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_product_product_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use IncreaseStockResponse.ProtoReflect.Descriptor instead.
+func (*IncreaseStockResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *ListProductsByCategoryRequest) GetCategory() string {
+func (x *IncreaseStockResponse) GetMessage() string {
 	if x != nil {
-		return x.Category
+		return x.Message
 	}
 	return ""
 }
 
-func (x *ListProductsByCategoryRequest) GetOffset() int32 {
-	if x != nil {
-		return x.Offset
-	}
-	return 0
-}
-
-func (x *ListProductsByCategoryRequest) GetLimit() int32 {
-	if x != nil {
-		return x.Limit
-	}
-	return 0
-}
-
-// Stock management messages
-type UpdateStockRequest struct {
+// Stock reservation messages
+type ReservationItem struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ProductId     uint32                 `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Stock         int32                  `protobuf:"varint,2,opt,name=stock,proto3" json:"stock,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	VariantId     *uint32                `protobuf:"varint,3,opt,name=variant_id,json=variantId,proto3,oneof" json:"variant_id,omitempty"` // Unset for the product itself
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateStockRequest) Reset() {
-	*x = UpdateStockRequest{}
-	mi := &file_api_proto_product_product_proto_msgTypes[12]
+func (x *ReservationItem) Reset() {
+	*x = ReservationItem{}
+	mi := &file_api_proto_product_product_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateStockRequest) String() string {
+func (x *ReservationItem) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateStockRequest) ProtoMessage() {}
+func (*ReservationItem) ProtoMessage() {}
 
-func (x *UpdateStockRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_product_product_proto_msgTypes[12]
+func (x *ReservationItem) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_product_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1345,47 +2399,60 @@ func (x *UpdateStockRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateStockRequest.ProtoReflect.Descriptor instead.
-func (*UpdateStockRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_product_product_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use ReservationItem.ProtoReflect.Descriptor instead.
+func (*ReservationItem) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *UpdateStockRequest) GetProductId() uint32 {
+func (x *ReservationItem) GetProductId() uint32 {
 	if x != nil {
 		return x.ProductId
 	}
 	return 0
 }
 
-func (x *UpdateStockRequest) GetStock() int32 {
+func (x *ReservationItem) GetQuantity() int32 {
 	if x != nil {
-		return x.Stock
+		return x.Quantity
 	}
 	return 0
 }
 
-type UpdateStockResponse struct {
+func (x *ReservationItem) GetVariantId() uint32 {
+	if x != nil && x.VariantId != nil {
+		return *x.VariantId
+	}
+	return 0
+}
+
+type Reservation struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ReferenceType string                 `protobuf:"bytes,2,opt,name=reference_type,json=referenceType,proto3" json:"reference_type,omitempty"` // basket or payment
+	ReferenceId   string                 `protobuf:"bytes,3,opt,name=reference_id,json=referenceId,proto3" json:"reference_id,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"` // active, confirmed, released or expired
+	Items         []*ReservationItem     `protobuf:"bytes,5,rep,name=items,proto3" json:"items,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateStockResponse) Reset() {
-	*x = UpdateStockResponse{}
-	mi := &file_api_proto_product_product_proto_msgTypes[13]
+func (x *Reservation) Reset() {
+	*x = Reservation{}
+	mi := &file_api_proto_product_product_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateStockResponse) String() string {
+func (x *Reservation) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateStockResponse) ProtoMessage() {}
+func (*Reservation) ProtoMessage() {}
 
-func (x *UpdateStockResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_product_product_proto_msgTypes[13]
+func (x *Reservation) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_product_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1396,41 +2463,85 @@ func (x *UpdateStockResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateStockResponse.ProtoReflect.Descriptor instead.
-func (*UpdateStockResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_product_product_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use Reservation.ProtoReflect.Descriptor instead.
+func (*Reservation) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *UpdateStockResponse) GetMessage() string {
+func (x *Reservation) GetId() string {
 	if x != nil {
-		return x.Message
+		return x.Id
 	}
 	return ""
 }
 
-type ReduceStockRequest struct {
+func (x *Reservation) GetReferenceType() string {
+	if x != nil {
+		return x.ReferenceType
+	}
+	return ""
+}
+
+func (x *Reservation) GetReferenceId() string {
+	if x != nil {
+		return x.ReferenceId
+	}
+	return ""
+}
+
+func (x *Reservation) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Reservation) GetItems() []*ReservationItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Reservation) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *Reservation) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ReserveStockRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductId     uint32                 `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Amount        int32                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	ReferenceType string                 `protobuf:"bytes,1,opt,name=reference_type,json=referenceType,proto3" json:"reference_type,omitempty"` // basket or payment
+	ReferenceId   string                 `protobuf:"bytes,2,opt,name=reference_id,json=referenceId,proto3" json:"reference_id,omitempty"`
+	Items         []*ReservationItem     `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	TtlSeconds    int32                  `protobuf:"varint,4,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"` // Zero for the default TTL
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ReduceStockRequest) Reset() {
-	*x = ReduceStockRequest{}
-	mi := &file_api_proto_product_product_proto_msgTypes[14]
+func (x *ReserveStockRequest) Reset() {
+	*x = ReserveStockRequest{}
+	mi := &file_api_proto_product_product_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ReduceStockRequest) String() string {
+func (x *ReserveStockRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReduceStockRequest) ProtoMessage() {}
+func (*ReserveStockRequest) ProtoMessage() {}
 
-func (x *ReduceStockRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_product_product_proto_msgTypes[14]
+func (x *ReserveStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_product_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1441,47 +2552,62 @@ func (x *ReduceStockRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReduceStockRequest.ProtoReflect.Descriptor instead.
-func (*ReduceStockRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_product_product_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use ReserveStockRequest.ProtoReflect.Descriptor instead.
+func (*ReserveStockRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *ReduceStockRequest) GetProductId() uint32 {
+func (x *ReserveStockRequest) GetReferenceType() string {
 	if x != nil {
-		return x.ProductId
+		return x.ReferenceType
 	}
-	return 0
+	return ""
 }
 
-func (x *ReduceStockRequest) GetAmount() int32 {
+func (x *ReserveStockRequest) GetReferenceId() string {
 	if x != nil {
-		return x.Amount
+		return x.ReferenceId
+	}
+	return ""
+}
+
+func (x *ReserveStockRequest) GetItems() []*ReservationItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ReserveStockRequest) GetTtlSeconds() int32 {
+	if x != nil {
+		return x.TtlSeconds
 	}
 	return 0
 }
 
-type ReduceStockResponse struct {
+type ConfirmReservationRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	ReferenceType string                 `protobuf:"bytes,1,opt,name=reference_type,json=referenceType,proto3" json:"reference_type,omitempty"`
+	ReferenceId   string                 `protobuf:"bytes,2,opt,name=reference_id,json=referenceId,proto3" json:"reference_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ReduceStockResponse) Reset() {
-	*x = ReduceStockResponse{}
-	mi := &file_api_proto_product_product_proto_msgTypes[15]
+func (x *ConfirmReservationRequest) Reset() {
+	*x = ConfirmReservationRequest{}
+	mi := &file_api_proto_product_product_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ReduceStockResponse) String() string {
+func (x *ConfirmReservationRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReduceStockResponse) ProtoMessage() {}
+func (*ConfirmReservationRequest) ProtoMessage() {}
 
-func (x *ReduceStockResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_product_product_proto_msgTypes[15]
+func (x *ConfirmReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_product_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1492,41 +2618,48 @@ func (x *ReduceStockResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReduceStockResponse.ProtoReflect.Descriptor instead.
-func (*ReduceStockResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_product_product_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use ConfirmReservationRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmReservationRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{27}
 }
 
-func (x *ReduceStockResponse) GetMessage() string {
+func (x *ConfirmReservationRequest) GetReferenceType() string {
 	if x != nil {
-		return x.Message
+		return x.ReferenceType
 	}
 	return ""
 }
 
-type IncreaseStockRequest struct {
+func (x *ConfirmReservationRequest) GetReferenceId() string {
+	if x != nil {
+		return x.ReferenceId
+	}
+	return ""
+}
+
+type ReleaseReservationRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductId     uint32                 `protobuf:"varint,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
-	Amount        int32                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	ReferenceType string                 `protobuf:"bytes,1,opt,name=reference_type,json=referenceType,proto3" json:"reference_type,omitempty"`
+	ReferenceId   string                 `protobuf:"bytes,2,opt,name=reference_id,json=referenceId,proto3" json:"reference_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *IncreaseStockRequest) Reset() {
-	*x = IncreaseStockRequest{}
-	mi := &file_api_proto_product_product_proto_msgTypes[16]
+func (x *ReleaseReservationRequest) Reset() {
+	*x = ReleaseReservationRequest{}
+	mi := &file_api_proto_product_product_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *IncreaseStockRequest) String() string {
+func (x *ReleaseReservationRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*IncreaseStockRequest) ProtoMessage() {}
+func (*ReleaseReservationRequest) ProtoMessage() {}
 
-func (x *IncreaseStockRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_product_product_proto_msgTypes[16]
+func (x *ReleaseReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_product_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1537,47 +2670,47 @@ func (x *IncreaseStockRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use IncreaseStockRequest.ProtoReflect.Descriptor instead.
-func (*IncreaseStockRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_product_product_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use ReleaseReservationRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseReservationRequest) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *IncreaseStockRequest) GetProductId() uint32 {
+func (x *ReleaseReservationRequest) GetReferenceType() string {
 	if x != nil {
-		return x.ProductId
+		return x.ReferenceType
 	}
-	return 0
+	return ""
 }
 
-func (x *IncreaseStockRequest) GetAmount() int32 {
+func (x *ReleaseReservationRequest) GetReferenceId() string {
 	if x != nil {
-		return x.Amount
+		return x.ReferenceId
 	}
-	return 0
+	return ""
 }
 
-type IncreaseStockResponse struct {
+type ReservationResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Reservation   *Reservation           `protobuf:"bytes,1,opt,name=reservation,proto3" json:"reservation,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *IncreaseStockResponse) Reset() {
-	*x = IncreaseStockResponse{}
-	mi := &file_api_proto_product_product_proto_msgTypes[17]
+func (x *ReservationResponse) Reset() {
+	*x = ReservationResponse{}
+	mi := &file_api_proto_product_product_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *IncreaseStockResponse) String() string {
+func (x *ReservationResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*IncreaseStockResponse) ProtoMessage() {}
+func (*ReservationResponse) ProtoMessage() {}
 
-func (x *IncreaseStockResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_product_product_proto_msgTypes[17]
+func (x *ReservationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_proto_product_product_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1588,16 +2721,16 @@ func (x *IncreaseStockResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use IncreaseStockResponse.ProtoReflect.Descriptor instead.
-func (*IncreaseStockResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_product_product_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use ReservationResponse.ProtoReflect.Descriptor instead.
+func (*ReservationResponse) Descriptor() ([]byte, []int) {
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{29}
 }
 
-func (x *IncreaseStockResponse) GetMessage() string {
+func (x *ReservationResponse) GetReservation() *Reservation {
 	if x != nil {
-		return x.Message
+		return x.Reservation
 	}
-	return ""
+	return nil
 }
 
 // Product status management messages
@@ -1610,7 +2743,7 @@ type ActivateProductRequest struct {
 
 func (x *ActivateProductRequest) Reset() {
 	*x = ActivateProductRequest{}
-	mi := &file_api_proto_product_product_proto_msgTypes[18]
+	mi := &file_api_proto_product_product_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1622,7 +2755,7 @@ func (x *ActivateProductRequest) String() string {
 func (*ActivateProductRequest) ProtoMessage() {}
 
 func (x *ActivateProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_product_product_proto_msgTypes[18]
+	mi := &file_api_proto_product_product_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1635,7 +2768,7 @@ func (x *ActivateProductRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ActivateProductRequest.ProtoReflect.Descriptor instead.
 func (*ActivateProductRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_product_product_proto_rawDescGZIP(), []int{18}
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *ActivateProductRequest) GetProductId() uint32 {
@@ -1654,7 +2787,7 @@ type DeactivateProductRequest struct {
 
 func (x *DeactivateProductRequest) Reset() {
 	*x = DeactivateProductRequest{}
-	mi := &file_api_proto_product_product_proto_msgTypes[19]
+	mi := &file_api_proto_product_product_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1666,7 +2799,7 @@ func (x *DeactivateProductRequest) String() string {
 func (*DeactivateProductRequest) ProtoMessage() {}
 
 func (x *DeactivateProductRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_product_product_proto_msgTypes[19]
+	mi := &file_api_proto_product_product_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1679,7 +2812,7 @@ func (x *DeactivateProductRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeactivateProductRequest.ProtoReflect.Descriptor instead.
 func (*DeactivateProductRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_product_product_proto_rawDescGZIP(), []int{19}
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *DeactivateProductRequest) GetProductId() uint32 {
@@ -1698,7 +2831,7 @@ type MarkAsFeaturedRequest struct {
 
 func (x *MarkAsFeaturedRequest) Reset() {
 	*x = MarkAsFeaturedRequest{}
-	mi := &file_api_proto_product_product_proto_msgTypes[20]
+	mi := &file_api_proto_product_product_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1710,7 +2843,7 @@ func (x *MarkAsFeaturedRequest) String() string {
 func (*MarkAsFeaturedRequest) ProtoMessage() {}
 
 func (x *MarkAsFeaturedRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_product_product_proto_msgTypes[20]
+	mi := &file_api_proto_product_product_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1723,7 +2856,7 @@ func (x *MarkAsFeaturedRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MarkAsFeaturedRequest.ProtoReflect.Descriptor instead.
 func (*MarkAsFeaturedRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_product_product_proto_rawDescGZIP(), []int{20}
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *MarkAsFeaturedRequest) GetProductId() uint32 {
@@ -1742,7 +2875,7 @@ type UnmarkAsFeaturedRequest struct {
 
 func (x *UnmarkAsFeaturedRequest) Reset() {
 	*x = UnmarkAsFeaturedRequest{}
-	mi := &file_api_proto_product_product_proto_msgTypes[21]
+	mi := &file_api_proto_product_product_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1754,7 +2887,7 @@ func (x *UnmarkAsFeaturedRequest) String() string {
 func (*UnmarkAsFeaturedRequest) ProtoMessage() {}
 
 func (x *UnmarkAsFeaturedRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_product_product_proto_msgTypes[21]
+	mi := &file_api_proto_product_product_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1767,7 +2900,7 @@ func (x *UnmarkAsFeaturedRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UnmarkAsFeaturedRequest.ProtoReflect.Descriptor instead.
 func (*UnmarkAsFeaturedRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_product_product_proto_rawDescGZIP(), []int{21}
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *UnmarkAsFeaturedRequest) GetProductId() uint32 {
@@ -1786,7 +2919,7 @@ type IncrementViewCountRequest struct {
 
 func (x *IncrementViewCountRequest) Reset() {
 	*x = IncrementViewCountRequest{}
-	mi := &file_api_proto_product_product_proto_msgTypes[22]
+	mi := &file_api_proto_product_product_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1798,7 +2931,7 @@ func (x *IncrementViewCountRequest) String() string {
 func (*IncrementViewCountRequest) ProtoMessage() {}
 
 func (x *IncrementViewCountRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_product_product_proto_msgTypes[22]
+	mi := &file_api_proto_product_product_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1811,7 +2944,7 @@ func (x *IncrementViewCountRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IncrementViewCountRequest.ProtoReflect.Descriptor instead.
 func (*IncrementViewCountRequest) Descriptor() ([]byte, []int) {
-	return file_api_proto_product_product_proto_rawDescGZIP(), []int{22}
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *IncrementViewCountRequest) GetProductId() uint32 {
@@ -1830,7 +2963,7 @@ type IncrementViewCountResponse struct {
 
 func (x *IncrementViewCountResponse) Reset() {
 	*x = IncrementViewCountResponse{}
-	mi := &file_api_proto_product_product_proto_msgTypes[23]
+	mi := &file_api_proto_product_product_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1842,7 +2975,7 @@ func (x *IncrementViewCountResponse) String() string {
 func (*IncrementViewCountResponse) ProtoMessage() {}
 
 func (x *IncrementViewCountResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_proto_product_product_proto_msgTypes[23]
+	mi := &file_api_proto_product_product_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1855,7 +2988,7 @@ func (x *IncrementViewCountResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IncrementViewCountResponse.ProtoReflect.Descriptor instead.
 func (*IncrementViewCountResponse) Descriptor() ([]byte, []int) {
-	return file_api_proto_product_product_proto_rawDescGZIP(), []int{23}
+	return file_api_proto_product_product_proto_rawDescGZIP(), []int{35}
 }
 
 func (x *IncrementViewCountResponse) GetMessage() string {
@@ -1869,16 +3002,16 @@ var File_api_proto_product_product_proto protoreflect.FileDescriptor
 
 const file_api_proto_product_product_proto_rawDesc = "" +
 	"\n" +
-	"\x1fapi/proto/product/product.proto\x12\aproduct\x1a\x1fgoogle/protobuf/timestamp.proto\"\x80\a\n" +
+	"\x1fapi/proto/product/product.proto\x12\aproduct\x1a\x1fgoogle/protobuf/timestamp.proto\"\xde\t\n" +
 	"\aProduct\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\rR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
 	"\vdescription\x18\x03 \x01(\tR\vdescription\x12+\n" +
-	"\x11short_description\x18\x04 \x01(\tR\x10shortDescription\x12\x14\n" +
-	"\x05price\x18\x05 \x01(\x01R\x05price\x12#\n" +
-	"\rcompare_price\x18\x06 \x01(\x01R\fcomparePrice\x12\x1d\n" +
+	"\x11short_description\x18\x04 \x01(\tR\x10shortDescription\x12\x18\n" +
+	"\x05price\x18\x05 \x01(\x01B\x02\x18\x01R\x05price\x12'\n" +
+	"\rcompare_price\x18\x06 \x01(\x01B\x02\x18\x01R\fcomparePrice\x12!\n" +
 	"\n" +
-	"cost_price\x18\a \x01(\x01R\tcostPrice\x12\x14\n" +
+	"cost_price\x18\a \x01(\x01B\x02\x18\x01R\tcostPrice\x12\x14\n" +
 	"\x05stock\x18\b \x01(\x05R\x05stock\x12\x1b\n" +
 	"\tmin_stock\x18\t \x01(\x05R\bminStock\x12\x1b\n" +
 	"\tmax_stock\x18\n" +
@@ -1911,15 +3044,50 @@ const file_api_proto_product_product_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x1d \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\x1e \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xcb\x05\n" +
+	"updated_at\x18\x1e \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1f\n" +
+	"\vprice_minor\x18\x1f \x01(\x03R\n" +
+	"priceMinor\x12.\n" +
+	"\x13compare_price_minor\x18  \x01(\x03R\x11comparePriceMinor\x12(\n" +
+	"\x10cost_price_minor\x18! \x01(\x03R\x0ecostPriceMinor\x12\x1a\n" +
+	"\bcurrency\x18\" \x01(\tR\bcurrency\x12%\n" +
+	"\x0ereserved_stock\x18# \x01(\x05R\rreservedStock\x12'\n" +
+	"\x0favailable_stock\x18$ \x01(\x05R\x0eavailableStock\x12$\n" +
+	"\vcategory_id\x18% \x01(\rH\x00R\n" +
+	"categoryId\x88\x01\x01\x123\n" +
+	"\bvariants\x18& \x03(\v2\x17.product.ProductVariantR\bvariantsB\x0e\n" +
+	"\f_category_id\"\x92\x04\n" +
+	"\x0eProductVariant\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\rR\tproductId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x10\n" +
+	"\x03sku\x18\x04 \x01(\tR\x03sku\x12\x1f\n" +
+	"\vprice_minor\x18\x05 \x01(\x03R\n" +
+	"priceMinor\x12\x16\n" +
+	"\x06weight\x18\x06 \x01(\x01R\x06weight\x12\x14\n" +
+	"\x05stock\x18\a \x01(\x05R\x05stock\x12%\n" +
+	"\x0ereserved_stock\x18\b \x01(\x05R\rreservedStock\x12'\n" +
+	"\x0favailable_stock\x18\t \x01(\x05R\x0eavailableStock\x12\x14\n" +
+	"\x05color\x18\n" +
+	" \x01(\tR\x05color\x12\x12\n" +
+	"\x04size\x18\v \x01(\tR\x04size\x12\x1a\n" +
+	"\bmaterial\x18\f \x01(\tR\bmaterial\x12\x14\n" +
+	"\x05image\x18\r \x01(\tR\x05image\x12\x1b\n" +
+	"\tis_active\x18\x0e \x01(\bR\bisActive\x12\x1d\n" +
+	"\n" +
+	"sort_order\x18\x0f \x01(\x05R\tsortOrder\x129\n" +
+	"\n" +
+	"created_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xa4\a\n" +
 	"\x14CreateProductRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x12+\n" +
-	"\x11short_description\x18\x03 \x01(\tR\x10shortDescription\x12\x14\n" +
-	"\x05price\x18\x04 \x01(\x01R\x05price\x12#\n" +
-	"\rcompare_price\x18\x05 \x01(\x01R\fcomparePrice\x12\x1d\n" +
+	"\x11short_description\x18\x03 \x01(\tR\x10shortDescription\x12\x18\n" +
+	"\x05price\x18\x04 \x01(\x01B\x02\x18\x01R\x05price\x12'\n" +
+	"\rcompare_price\x18\x05 \x01(\x01B\x02\x18\x01R\fcomparePrice\x12!\n" +
 	"\n" +
-	"cost_price\x18\x06 \x01(\x01R\tcostPrice\x12\x14\n" +
+	"cost_price\x18\x06 \x01(\x01B\x02\x18\x01R\tcostPrice\x12\x14\n" +
 	"\x05stock\x18\a \x01(\x05R\x05stock\x12\x1b\n" +
 	"\tmin_stock\x18\b \x01(\x05R\bminStock\x12\x1b\n" +
 	"\tmax_stock\x18\t \x01(\x05R\bmaxStock\x12\x1a\n" +
@@ -1945,22 +3113,30 @@ const file_api_proto_product_product_proto_rawDesc = "" +
 	"\n" +
 	"is_on_sale\x18\x18 \x01(\bR\bisOnSale\x12\x1d\n" +
 	"\n" +
-	"sort_order\x18\x19 \x01(\x05R\tsortOrder\"=\n" +
+	"sort_order\x18\x19 \x01(\x05R\tsortOrder\x12\x1f\n" +
+	"\vprice_minor\x18\x1a \x01(\x03R\n" +
+	"priceMinor\x12.\n" +
+	"\x13compare_price_minor\x18\x1b \x01(\x03R\x11comparePriceMinor\x12(\n" +
+	"\x10cost_price_minor\x18\x1c \x01(\x03R\x0ecostPriceMinor\x12\x1a\n" +
+	"\bcurrency\x18\x1d \x01(\tR\bcurrency\x12$\n" +
+	"\vcategory_id\x18\x1e \x01(\rH\x00R\n" +
+	"categoryId\x88\x01\x01B\x0e\n" +
+	"\f_category_id\"=\n" +
 	"\x0fProductResponse\x12*\n" +
 	"\aproduct\x18\x01 \x01(\v2\x10.product.ProductR\aproduct\"#\n" +
 	"\x11GetProductRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\rR\x02id\"*\n" +
 	"\x16GetProductBySKURequest\x12\x10\n" +
-	"\x03sku\x18\x01 \x01(\tR\x03sku\"\xb0\t\n" +
+	"\x03sku\x18\x01 \x01(\tR\x03sku\"\xe7\v\n" +
 	"\x14UpdateProductRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\rR\x02id\x12\x17\n" +
 	"\x04name\x18\x02 \x01(\tH\x00R\x04name\x88\x01\x01\x12%\n" +
 	"\vdescription\x18\x03 \x01(\tH\x01R\vdescription\x88\x01\x01\x120\n" +
-	"\x11short_description\x18\x04 \x01(\tH\x02R\x10shortDescription\x88\x01\x01\x12\x19\n" +
-	"\x05price\x18\x05 \x01(\x01H\x03R\x05price\x88\x01\x01\x12(\n" +
-	"\rcompare_price\x18\x06 \x01(\x01H\x04R\fcomparePrice\x88\x01\x01\x12\"\n" +
+	"\x11short_description\x18\x04 \x01(\tH\x02R\x10shortDescription\x88\x01\x01\x12\x1d\n" +
+	"\x05price\x18\x05 \x01(\x01B\x02\x18\x01H\x03R\x05price\x88\x01\x01\x12,\n" +
+	"\rcompare_price\x18\x06 \x01(\x01B\x02\x18\x01H\x04R\fcomparePrice\x88\x01\x01\x12&\n" +
 	"\n" +
-	"cost_price\x18\a \x01(\x01H\x05R\tcostPrice\x88\x01\x01\x12\x19\n" +
+	"cost_price\x18\a \x01(\x01B\x02\x18\x01H\x05R\tcostPrice\x88\x01\x01\x12\x19\n" +
 	"\x05stock\x18\b \x01(\x05H\x06R\x05stock\x88\x01\x01\x12 \n" +
 	"\tmin_stock\x18\t \x01(\x05H\aR\bminStock\x88\x01\x01\x12 \n" +
 	"\tmax_stock\x18\n" +
@@ -1987,7 +3163,14 @@ const file_api_proto_product_product_proto_rawDesc = "" +
 	"\n" +
 	"is_on_sale\x18\x19 \x01(\bH\x17R\bisOnSale\x88\x01\x01\x12\"\n" +
 	"\n" +
-	"sort_order\x18\x1a \x01(\x05H\x18R\tsortOrder\x88\x01\x01B\a\n" +
+	"sort_order\x18\x1a \x01(\x05H\x18R\tsortOrder\x88\x01\x01\x12$\n" +
+	"\vprice_minor\x18\x1b \x01(\x03H\x19R\n" +
+	"priceMinor\x88\x01\x01\x123\n" +
+	"\x13compare_price_minor\x18\x1c \x01(\x03H\x1aR\x11comparePriceMinor\x88\x01\x01\x12-\n" +
+	"\x10cost_price_minor\x18\x1d \x01(\x03H\x1bR\x0ecostPriceMinor\x88\x01\x01\x12\x1f\n" +
+	"\bcurrency\x18\x1e \x01(\tH\x1cR\bcurrency\x88\x01\x01\x12$\n" +
+	"\vcategory_id\x18\x1f \x01(\rH\x1dR\n" +
+	"categoryId\x88\x01\x01B\a\n" +
 	"\x05_nameB\x0e\n" +
 	"\f_descriptionB\x14\n" +
 	"\x12_short_descriptionB\b\n" +
@@ -2016,7 +3199,12 @@ const file_api_proto_product_product_proto_rawDesc = "" +
 	"\v_is_digitalB\x0e\n" +
 	"\f_is_featuredB\r\n" +
 	"\v_is_on_saleB\r\n" +
-	"\v_sort_order\"&\n" +
+	"\v_sort_orderB\x0e\n" +
+	"\f_price_minorB\x16\n" +
+	"\x14_compare_price_minorB\x13\n" +
+	"\x11_cost_price_minorB\v\n" +
+	"\t_currencyB\x0e\n" +
+	"\f_category_id\"&\n" +
 	"\x14DeleteProductRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\rR\x02id\"1\n" +
 	"\x15DeleteProductResponse\x12\x18\n" +
@@ -2028,13 +3216,13 @@ const file_api_proto_product_product_proto_rawDesc = "" +
 	"\bproducts\x18\x01 \x03(\v2\x10.product.ProductR\bproducts\x12\x14\n" +
 	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x16\n" +
 	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12\x14\n" +
-	"\x05limit\x18\x04 \x01(\x05R\x05limit\"\x92\x03\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"\xea\x03\n" +
 	"\x15SearchProductsRequest\x12\x14\n" +
 	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1a\n" +
 	"\bcategory\x18\x02 \x01(\tR\bcategory\x12\x14\n" +
-	"\x05brand\x18\x03 \x01(\tR\x05brand\x12\x1b\n" +
-	"\tmin_price\x18\x04 \x01(\x01R\bminPrice\x12\x1b\n" +
-	"\tmax_price\x18\x05 \x01(\x01R\bmaxPrice\x12 \n" +
+	"\x05brand\x18\x03 \x01(\tR\x05brand\x12\x1f\n" +
+	"\tmin_price\x18\x04 \x01(\x01B\x02\x18\x01R\bminPrice\x12\x1f\n" +
+	"\tmax_price\x18\x05 \x01(\x01B\x02\x18\x01R\bmaxPrice\x12 \n" +
 	"\tis_active\x18\x06 \x01(\bH\x00R\bisActive\x88\x01\x01\x12\"\n" +
 	"\n" +
 	"is_digital\x18\a \x01(\bH\x01R\tisDigital\x88\x01\x01\x12$\n" +
@@ -2044,7 +3232,9 @@ const file_api_proto_product_product_proto_rawDesc = "" +
 	"is_on_sale\x18\t \x01(\bH\x03R\bisOnSale\x88\x01\x01\x12\x16\n" +
 	"\x06offset\x18\n" +
 	" \x01(\x05R\x06offset\x12\x14\n" +
-	"\x05limit\x18\v \x01(\x05R\x05limitB\f\n" +
+	"\x05limit\x18\v \x01(\x05R\x05limit\x12&\n" +
+	"\x0fmin_price_minor\x18\f \x01(\x03R\rminPriceMinor\x12&\n" +
+	"\x0fmax_price_minor\x18\r \x01(\x03R\rmaxPriceMinorB\f\n" +
 	"\n" +
 	"_is_activeB\r\n" +
 	"\v_is_digitalB\x0e\n" +
@@ -2053,25 +3243,105 @@ const file_api_proto_product_product_proto_rawDesc = "" +
 	"\x1dListProductsByCategoryRequest\x12\x1a\n" +
 	"\bcategory\x18\x01 \x01(\tR\bcategory\x12\x16\n" +
 	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x14\n" +
-	"\x05limit\x18\x03 \x01(\x05R\x05limit\"I\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"\xb5\x03\n" +
+	"\bCategory\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\rR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x12\n" +
+	"\x04slug\x18\x04 \x01(\tR\x04slug\x12 \n" +
+	"\tparent_id\x18\x05 \x01(\rH\x00R\bparentId\x88\x01\x01\x12\x14\n" +
+	"\x05depth\x18\x06 \x01(\x05R\x05depth\x12\x14\n" +
+	"\x05image\x18\a \x01(\tR\x05image\x12\x12\n" +
+	"\x04icon\x18\b \x01(\tR\x04icon\x12\x1d\n" +
+	"\n" +
+	"sort_order\x18\t \x01(\x05R\tsortOrder\x12\x1b\n" +
+	"\tis_active\x18\n" +
+	" \x01(\bR\bisActive\x12-\n" +
+	"\bchildren\x18\v \x03(\v2\x11.product.CategoryR\bchildren\x129\n" +
+	"\n" +
+	"created_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtB\f\n" +
+	"\n" +
+	"_parent_id\"\x18\n" +
+	"\x16GetCategoryTreeRequest\"I\n" +
+	"\x14CategoryTreeResponse\x121\n" +
+	"\n" +
+	"categories\x18\x01 \x03(\v2\x11.product.CategoryR\n" +
+	"categories\"C\n" +
+	"\x12GetCategoryRequest\x12\x10\n" +
+	"\x02id\x18\x01 \x01(\rH\x00R\x02id\x12\x14\n" +
+	"\x04slug\x18\x02 \x01(\tH\x00R\x04slugB\x05\n" +
+	"\x03key\"A\n" +
+	"\x10CategoryResponse\x12-\n" +
+	"\bcategory\x18\x01 \x01(\v2\x11.product.CategoryR\bcategory\"|\n" +
 	"\x12UpdateStockRequest\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x01 \x01(\rR\tproductId\x12\x14\n" +
-	"\x05stock\x18\x02 \x01(\x05R\x05stock\"/\n" +
+	"\x05stock\x18\x02 \x01(\x05R\x05stock\x12\"\n" +
+	"\n" +
+	"variant_id\x18\x03 \x01(\rH\x00R\tvariantId\x88\x01\x01B\r\n" +
+	"\v_variant_id\"/\n" +
 	"\x13UpdateStockResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage\"K\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\x96\x02\n" +
 	"\x12ReduceStockRequest\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x01 \x01(\rR\tproductId\x12\x16\n" +
-	"\x06amount\x18\x02 \x01(\x05R\x06amount\"/\n" +
+	"\x06amount\x18\x02 \x01(\x05R\x06amount\x12&\n" +
+	"\fwarehouse_id\x18\x03 \x01(\rH\x00R\vwarehouseId\x88\x01\x01\x12\x1f\n" +
+	"\blatitude\x18\x04 \x01(\x01H\x01R\blatitude\x88\x01\x01\x12!\n" +
+	"\tlongitude\x18\x05 \x01(\x01H\x02R\tlongitude\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"variant_id\x18\x06 \x01(\rH\x03R\tvariantId\x88\x01\x01B\x0f\n" +
+	"\r_warehouse_idB\v\n" +
+	"\t_latitudeB\f\n" +
+	"\n" +
+	"_longitudeB\r\n" +
+	"\v_variant_id\"/\n" +
 	"\x13ReduceStockResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage\"M\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\xb9\x01\n" +
 	"\x14IncreaseStockRequest\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x01 \x01(\rR\tproductId\x12\x16\n" +
-	"\x06amount\x18\x02 \x01(\x05R\x06amount\"1\n" +
+	"\x06amount\x18\x02 \x01(\x05R\x06amount\x12&\n" +
+	"\fwarehouse_id\x18\x03 \x01(\rH\x00R\vwarehouseId\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"variant_id\x18\x04 \x01(\rH\x01R\tvariantId\x88\x01\x01B\x0f\n" +
+	"\r_warehouse_idB\r\n" +
+	"\v_variant_id\"1\n" +
 	"\x15IncreaseStockResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage\"7\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\x7f\n" +
+	"\x0fReservationItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\rR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\x12\"\n" +
+	"\n" +
+	"variant_id\x18\x03 \x01(\rH\x00R\tvariantId\x88\x01\x01B\r\n" +
+	"\v_variant_id\"\xa5\x02\n" +
+	"\vReservation\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12%\n" +
+	"\x0ereference_type\x18\x02 \x01(\tR\rreferenceType\x12!\n" +
+	"\freference_id\x18\x03 \x01(\tR\vreferenceId\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12.\n" +
+	"\x05items\x18\x05 \x03(\v2\x18.product.ReservationItemR\x05items\x129\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xb0\x01\n" +
+	"\x13ReserveStockRequest\x12%\n" +
+	"\x0ereference_type\x18\x01 \x01(\tR\rreferenceType\x12!\n" +
+	"\freference_id\x18\x02 \x01(\tR\vreferenceId\x12.\n" +
+	"\x05items\x18\x03 \x03(\v2\x18.product.ReservationItemR\x05items\x12\x1f\n" +
+	"\vttl_seconds\x18\x04 \x01(\x05R\n" +
+	"ttlSeconds\"e\n" +
+	"\x19ConfirmReservationRequest\x12%\n" +
+	"\x0ereference_type\x18\x01 \x01(\tR\rreferenceType\x12!\n" +
+	"\freference_id\x18\x02 \x01(\tR\vreferenceId\"e\n" +
+	"\x19ReleaseReservationRequest\x12%\n" +
+	"\x0ereference_type\x18\x01 \x01(\tR\rreferenceType\x12!\n" +
+	"\freference_id\x18\x02 \x01(\tR\vreferenceId\"M\n" +
+	"\x13ReservationResponse\x126\n" +
+	"\vreservation\x18\x01 \x01(\v2\x14.product.ReservationR\vreservation\"7\n" +
 	"\x16ActivateProductRequest\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x01 \x01(\rR\tproductId\"9\n" +
@@ -2088,8 +3358,7 @@ const file_api_proto_product_product_proto_rawDesc = "" +
 	"\n" +
 	"product_id\x18\x01 \x01(\rR\tproductId\"6\n" +
 	"\x1aIncrementViewCountResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage2\x84\n" +
-	"\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage2\x9a\r\n" +
 	"\x0eProductService\x12H\n" +
 	"\rCreateProduct\x12\x1d.product.CreateProductRequest\x1a\x18.product.ProductResponse\x12B\n" +
 	"\n" +
@@ -2099,10 +3368,15 @@ const file_api_proto_product_product_proto_rawDesc = "" +
 	"\rDeleteProduct\x12\x1d.product.DeleteProductRequest\x1a\x1e.product.DeleteProductResponse\x12K\n" +
 	"\fListProducts\x12\x1c.product.ListProductsRequest\x1a\x1d.product.ListProductsResponse\x12O\n" +
 	"\x0eSearchProducts\x12\x1e.product.SearchProductsRequest\x1a\x1d.product.ListProductsResponse\x12_\n" +
-	"\x16ListProductsByCategory\x12&.product.ListProductsByCategoryRequest\x1a\x1d.product.ListProductsResponse\x12H\n" +
+	"\x16ListProductsByCategory\x12&.product.ListProductsByCategoryRequest\x1a\x1d.product.ListProductsResponse\x12Q\n" +
+	"\x0fGetCategoryTree\x12\x1f.product.GetCategoryTreeRequest\x1a\x1d.product.CategoryTreeResponse\x12E\n" +
+	"\vGetCategory\x12\x1b.product.GetCategoryRequest\x1a\x19.product.CategoryResponse\x12H\n" +
 	"\vUpdateStock\x12\x1b.product.UpdateStockRequest\x1a\x1c.product.UpdateStockResponse\x12H\n" +
 	"\vReduceStock\x12\x1b.product.ReduceStockRequest\x1a\x1c.product.ReduceStockResponse\x12N\n" +
-	"\rIncreaseStock\x12\x1d.product.IncreaseStockRequest\x1a\x1e.product.IncreaseStockResponse\x12L\n" +
+	"\rIncreaseStock\x12\x1d.product.IncreaseStockRequest\x1a\x1e.product.IncreaseStockResponse\x12J\n" +
+	"\fReserveStock\x12\x1c.product.ReserveStockRequest\x1a\x1c.product.ReservationResponse\x12V\n" +
+	"\x12ConfirmReservation\x12\".product.ConfirmReservationRequest\x1a\x1c.product.ReservationResponse\x12V\n" +
+	"\x12ReleaseReservation\x12\".product.ReleaseReservationRequest\x1a\x1c.product.ReservationResponse\x12L\n" +
 	"\x0fActivateProduct\x12\x1f.product.ActivateProductRequest\x1a\x18.product.ProductResponse\x12P\n" +
 	"\x11DeactivateProduct\x12!.product.DeactivateProductRequest\x1a\x18.product.ProductResponse\x12J\n" +
 	"\x0eMarkAsFeatured\x12\x1e.product.MarkAsFeaturedRequest\x1a\x18.product.ProductResponse\x12N\n" +
@@ -2121,76 +3395,111 @@ func file_api_proto_product_product_proto_rawDescGZIP() []byte {
 	return file_api_proto_product_product_proto_rawDescData
 }
 
-var file_api_proto_product_product_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
+var file_api_proto_product_product_proto_msgTypes = make([]protoimpl.MessageInfo, 36)
 var file_api_proto_product_product_proto_goTypes = []any{
 	(*Product)(nil),                       // 0: product.Product
-	(*CreateProductRequest)(nil),          // 1: product.CreateProductRequest
-	(*ProductResponse)(nil),               // 2: product.ProductResponse
-	(*GetProductRequest)(nil),             // 3: product.GetProductRequest
-	(*GetProductBySKURequest)(nil),        // 4: product.GetProductBySKURequest
-	(*UpdateProductRequest)(nil),          // 5: product.UpdateProductRequest
-	(*DeleteProductRequest)(nil),          // 6: product.DeleteProductRequest
-	(*DeleteProductResponse)(nil),         // 7: product.DeleteProductResponse
-	(*ListProductsRequest)(nil),           // 8: product.ListProductsRequest
-	(*ListProductsResponse)(nil),          // 9: product.ListProductsResponse
-	(*SearchProductsRequest)(nil),         // 10: product.SearchProductsRequest
-	(*ListProductsByCategoryRequest)(nil), // 11: product.ListProductsByCategoryRequest
-	(*UpdateStockRequest)(nil),            // 12: product.UpdateStockRequest
-	(*UpdateStockResponse)(nil),           // 13: product.UpdateStockResponse
-	(*ReduceStockRequest)(nil),            // 14: product.ReduceStockRequest
-	(*ReduceStockResponse)(nil),           // 15: product.ReduceStockResponse
-	(*IncreaseStockRequest)(nil),          // 16: product.IncreaseStockRequest
-	(*IncreaseStockResponse)(nil),         // 17: product.IncreaseStockResponse
-	(*ActivateProductRequest)(nil),        // 18: product.ActivateProductRequest
-	(*DeactivateProductRequest)(nil),      // 19: product.DeactivateProductRequest
-	(*MarkAsFeaturedRequest)(nil),         // 20: product.MarkAsFeaturedRequest
-	(*UnmarkAsFeaturedRequest)(nil),       // 21: product.UnmarkAsFeaturedRequest
-	(*IncrementViewCountRequest)(nil),     // 22: product.IncrementViewCountRequest
-	(*IncrementViewCountResponse)(nil),    // 23: product.IncrementViewCountResponse
-	(*timestamppb.Timestamp)(nil),         // 24: google.protobuf.Timestamp
+	(*ProductVariant)(nil),                // 1: product.ProductVariant
+	(*CreateProductRequest)(nil),          // 2: product.CreateProductRequest
+	(*ProductResponse)(nil),               // 3: product.ProductResponse
+	(*GetProductRequest)(nil),             // 4: product.GetProductRequest
+	(*GetProductBySKURequest)(nil),        // 5: product.GetProductBySKURequest
+	(*UpdateProductRequest)(nil),          // 6: product.UpdateProductRequest
+	(*DeleteProductRequest)(nil),          // 7: product.DeleteProductRequest
+	(*DeleteProductResponse)(nil),         // 8: product.DeleteProductResponse
+	(*ListProductsRequest)(nil),           // 9: product.ListProductsRequest
+	(*ListProductsResponse)(nil),          // 10: product.ListProductsResponse
+	(*SearchProductsRequest)(nil),         // 11: product.SearchProductsRequest
+	(*ListProductsByCategoryRequest)(nil), // 12: product.ListProductsByCategoryRequest
+	(*Category)(nil),                      // 13: product.Category
+	(*GetCategoryTreeRequest)(nil),        // 14: product.GetCategoryTreeRequest
+	(*CategoryTreeResponse)(nil),          // 15: product.CategoryTreeResponse
+	(*GetCategoryRequest)(nil),            // 16: product.GetCategoryRequest
+	(*CategoryResponse)(nil),              // 17: product.CategoryResponse
+	(*UpdateStockRequest)(nil),            // 18: product.UpdateStockRequest
+	(*UpdateStockResponse)(nil),           // 19: product.UpdateStockResponse
+	(*ReduceStockRequest)(nil),            // 20: product.ReduceStockRequest
+	(*ReduceStockResponse)(nil),           // 21: product.ReduceStockResponse
+	(*IncreaseStockRequest)(nil),          // 22: product.IncreaseStockRequest
+	(*IncreaseStockResponse)(nil),         // 23: product.IncreaseStockResponse
+	(*ReservationItem)(nil),               // 24: product.ReservationItem
+	(*Reservation)(nil),                   // 25: product.Reservation
+	(*ReserveStockRequest)(nil),           // 26: product.ReserveStockRequest
+	(*ConfirmReservationRequest)(nil),     // 27: product.ConfirmReservationRequest
+	(*ReleaseReservationRequest)(nil),     // 28: product.ReleaseReservationRequest
+	(*ReservationResponse)(nil),           // 29: product.ReservationResponse
+	(*ActivateProductRequest)(nil),        // 30: product.ActivateProductRequest
+	(*DeactivateProductRequest)(nil),      // 31: product.DeactivateProductRequest
+	(*MarkAsFeaturedRequest)(nil),         // 32: product.MarkAsFeaturedRequest
+	(*UnmarkAsFeaturedRequest)(nil),       // 33: product.UnmarkAsFeaturedRequest
+	(*IncrementViewCountRequest)(nil),     // 34: product.IncrementViewCountRequest
+	(*IncrementViewCountResponse)(nil),    // 35: product.IncrementViewCountResponse
+	(*timestamppb.Timestamp)(nil),         // 36: google.protobuf.Timestamp
 }
 var file_api_proto_product_product_proto_depIdxs = []int32{
-	24, // 0: product.Product.created_at:type_name -> google.protobuf.Timestamp
-	24, // 1: product.Product.updated_at:type_name -> google.protobuf.Timestamp
-	0,  // 2: product.ProductResponse.product:type_name -> product.Product
-	0,  // 3: product.ListProductsResponse.products:type_name -> product.Product
-	1,  // 4: product.ProductService.CreateProduct:input_type -> product.CreateProductRequest
-	3,  // 5: product.ProductService.GetProduct:input_type -> product.GetProductRequest
-	4,  // 6: product.ProductService.GetProductBySKU:input_type -> product.GetProductBySKURequest
-	5,  // 7: product.ProductService.UpdateProduct:input_type -> product.UpdateProductRequest
-	6,  // 8: product.ProductService.DeleteProduct:input_type -> product.DeleteProductRequest
-	8,  // 9: product.ProductService.ListProducts:input_type -> product.ListProductsRequest
-	10, // 10: product.ProductService.SearchProducts:input_type -> product.SearchProductsRequest
-	11, // 11: product.ProductService.ListProductsByCategory:input_type -> product.ListProductsByCategoryRequest
-	12, // 12: product.ProductService.UpdateStock:input_type -> product.UpdateStockRequest
-	14, // 13: product.ProductService.ReduceStock:input_type -> product.ReduceStockRequest
-	16, // 14: product.ProductService.IncreaseStock:input_type -> product.IncreaseStockRequest
-	18, // 15: product.ProductService.ActivateProduct:input_type -> product.ActivateProductRequest
-	19, // 16: product.ProductService.DeactivateProduct:input_type -> product.DeactivateProductRequest
-	20, // 17: product.ProductService.MarkAsFeatured:input_type -> product.MarkAsFeaturedRequest
-	21, // 18: product.ProductService.UnmarkAsFeatured:input_type -> product.UnmarkAsFeaturedRequest
-	22, // 19: product.ProductService.IncrementViewCount:input_type -> product.IncrementViewCountRequest
-	2,  // 20: product.ProductService.CreateProduct:output_type -> product.ProductResponse
-	2,  // 21: product.ProductService.GetProduct:output_type -> product.ProductResponse
-	2,  // 22: product.ProductService.GetProductBySKU:output_type -> product.ProductResponse
-	2,  // 23: product.ProductService.UpdateProduct:output_type -> product.ProductResponse
-	7,  // 24: product.ProductService.DeleteProduct:output_type -> product.DeleteProductResponse
-	9,  // 25: product.ProductService.ListProducts:output_type -> product.ListProductsResponse
-	9,  // 26: product.ProductService.SearchProducts:output_type -> product.ListProductsResponse
-	9,  // 27: product.ProductService.ListProductsByCategory:output_type -> product.ListProductsResponse
-	13, // 28: product.ProductService.UpdateStock:output_type -> product.UpdateStockResponse
-	15, // 29: product.ProductService.ReduceStock:output_type -> product.ReduceStockResponse
-	17, // 30: product.ProductService.IncreaseStock:output_type -> product.IncreaseStockResponse
-	2,  // 31: product.ProductService.ActivateProduct:output_type -> product.ProductResponse
-	2,  // 32: product.ProductService.DeactivateProduct:output_type -> product.ProductResponse
-	2,  // 33: product.ProductService.MarkAsFeatured:output_type -> product.ProductResponse
-	2,  // 34: product.ProductService.UnmarkAsFeatured:output_type -> product.ProductResponse
-	23, // 35: product.ProductService.IncrementViewCount:output_type -> product.IncrementViewCountResponse
-	20, // [20:36] is the sub-list for method output_type
-	4,  // [4:20] is the sub-list for method input_type
-	4,  // [4:4] is the sub-list for extension type_name
-	4,  // [4:4] is the sub-list for extension extendee
-	0,  // [0:4] is the sub-list for field type_name
+	36, // 0: product.Product.created_at:type_name -> google.protobuf.Timestamp
+	36, // 1: product.Product.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 2: product.Product.variants:type_name -> product.ProductVariant
+	36, // 3: product.ProductVariant.created_at:type_name -> google.protobuf.Timestamp
+	36, // 4: product.ProductVariant.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 5: product.ProductResponse.product:type_name -> product.Product
+	0,  // 6: product.ListProductsResponse.products:type_name -> product.Product
+	13, // 7: product.Category.children:type_name -> product.Category
+	36, // 8: product.Category.created_at:type_name -> google.protobuf.Timestamp
+	36, // 9: product.Category.updated_at:type_name -> google.protobuf.Timestamp
+	13, // 10: product.CategoryTreeResponse.categories:type_name -> product.Category
+	13, // 11: product.CategoryResponse.category:type_name -> product.Category
+	24, // 12: product.Reservation.items:type_name -> product.ReservationItem
+	36, // 13: product.Reservation.expires_at:type_name -> google.protobuf.Timestamp
+	36, // 14: product.Reservation.created_at:type_name -> google.protobuf.Timestamp
+	24, // 15: product.ReserveStockRequest.items:type_name -> product.ReservationItem
+	25, // 16: product.ReservationResponse.reservation:type_name -> product.Reservation
+	2,  // 17: product.ProductService.CreateProduct:input_type -> product.CreateProductRequest
+	4,  // 18: product.ProductService.GetProduct:input_type -> product.GetProductRequest
+	5,  // 19: product.ProductService.GetProductBySKU:input_type -> product.GetProductBySKURequest
+	6,  // 20: product.ProductService.UpdateProduct:input_type -> product.UpdateProductRequest
+	7,  // 21: product.ProductService.DeleteProduct:input_type -> product.DeleteProductRequest
+	9,  // 22: product.ProductService.ListProducts:input_type -> product.ListProductsRequest
+	11, // 23: product.ProductService.SearchProducts:input_type -> product.SearchProductsRequest
+	12, // 24: product.ProductService.ListProductsByCategory:input_type -> product.ListProductsByCategoryRequest
+	14, // 25: product.ProductService.GetCategoryTree:input_type -> product.GetCategoryTreeRequest
+	16, // 26: product.ProductService.GetCategory:input_type -> product.GetCategoryRequest
+	18, // 27: product.ProductService.UpdateStock:input_type -> product.UpdateStockRequest
+	20, // 28: product.ProductService.ReduceStock:input_type -> product.ReduceStockRequest
+	22, // 29: product.ProductService.IncreaseStock:input_type -> product.IncreaseStockRequest
+	26, // 30: product.ProductService.ReserveStock:input_type -> product.ReserveStockRequest
+	27, // 31: product.ProductService.ConfirmReservation:input_type -> product.ConfirmReservationRequest
+	28, // 32: product.ProductService.ReleaseReservation:input_type -> product.ReleaseReservationRequest
+	30, // 33: product.ProductService.ActivateProduct:input_type -> product.ActivateProductRequest
+	31, // 34: product.ProductService.DeactivateProduct:input_type -> product.DeactivateProductRequest
+	32, // 35: product.ProductService.MarkAsFeatured:input_type -> product.MarkAsFeaturedRequest
+	33, // 36: product.ProductService.UnmarkAsFeatured:input_type -> product.UnmarkAsFeaturedRequest
+	34, // 37: product.ProductService.IncrementViewCount:input_type -> product.IncrementViewCountRequest
+	3,  // 38: product.ProductService.CreateProduct:output_type -> product.ProductResponse
+	3,  // 39: product.ProductService.GetProduct:output_type -> product.ProductResponse
+	3,  // 40: product.ProductService.GetProductBySKU:output_type -> product.ProductResponse
+	3,  // 41: product.ProductService.UpdateProduct:output_type -> product.ProductResponse
+	8,  // 42: product.ProductService.DeleteProduct:output_type -> product.DeleteProductResponse
+	10, // 43: product.ProductService.ListProducts:output_type -> product.ListProductsResponse
+	10, // 44: product.ProductService.SearchProducts:output_type -> product.ListProductsResponse
+	10, // 45: product.ProductService.ListProductsByCategory:output_type -> product.ListProductsResponse
+	15, // 46: product.ProductService.GetCategoryTree:output_type -> product.CategoryTreeResponse
+	17, // 47: product.ProductService.GetCategory:output_type -> product.CategoryResponse
+	19, // 48: product.ProductService.UpdateStock:output_type -> product.UpdateStockResponse
+	21, // 49: product.ProductService.ReduceStock:output_type -> product.ReduceStockResponse
+	23, // 50: product.ProductService.IncreaseStock:output_type -> product.IncreaseStockResponse
+	29, // 51: product.ProductService.ReserveStock:output_type -> product.ReservationResponse
+	29, // 52: product.ProductService.ConfirmReservation:output_type -> product.ReservationResponse
+	29, // 53: product.ProductService.ReleaseReservation:output_type -> product.ReservationResponse
+	3,  // 54: product.ProductService.ActivateProduct:output_type -> product.ProductResponse
+	3,  // 55: product.ProductService.DeactivateProduct:output_type -> product.ProductResponse
+	3,  // 56: product.ProductService.MarkAsFeatured:output_type -> product.ProductResponse
+	3,  // 57: product.ProductService.UnmarkAsFeatured:output_type -> product.ProductResponse
+	35, // 58: product.ProductService.IncrementViewCount:output_type -> product.IncrementViewCountResponse
+	38, // [38:59] is the sub-list for method output_type
+	17, // [17:38] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
 }
 
 func init() { file_api_proto_product_product_proto_init() }
@@ -2198,15 +3507,26 @@ func file_api_proto_product_product_proto_init() {
 	if File_api_proto_product_product_proto != nil {
 		return
 	}
-	file_api_proto_product_product_proto_msgTypes[5].OneofWrappers = []any{}
-	file_api_proto_product_product_proto_msgTypes[10].OneofWrappers = []any{}
+	file_api_proto_product_product_proto_msgTypes[0].OneofWrappers = []any{}
+	file_api_proto_product_product_proto_msgTypes[2].OneofWrappers = []any{}
+	file_api_proto_product_product_proto_msgTypes[6].OneofWrappers = []any{}
+	file_api_proto_product_product_proto_msgTypes[11].OneofWrappers = []any{}
+	file_api_proto_product_product_proto_msgTypes[13].OneofWrappers = []any{}
+	file_api_proto_product_product_proto_msgTypes[16].OneofWrappers = []any{
+		(*GetCategoryRequest_Id)(nil),
+		(*GetCategoryRequest_Slug)(nil),
+	}
+	file_api_proto_product_product_proto_msgTypes[18].OneofWrappers = []any{}
+	file_api_proto_product_product_proto_msgTypes[20].OneofWrappers = []any{}
+	file_api_proto_product_product_proto_msgTypes[22].OneofWrappers = []any{}
+	file_api_proto_product_product_proto_msgTypes[24].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_proto_product_product_proto_rawDesc), len(file_api_proto_product_product_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   24,
+			NumMessages:   36,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -86,8 +86,7 @@ export class BasketService {
    */
   static async addItem(
     productId: number,
-    quantity: number,
-    unitPrice: number
+    quantity: number
   ): Promise<ApiResponse<Basket>> {
     const user = this.getCurrentUser();
     if (!user) {
@@ -98,7 +97,6 @@ export class BasketService {
       user_id: user.id,
       product_id: productId,
       quantity,
-      unit_price: unitPrice,
     };
 
     const response = await apiClient.post('/basket/items', data);
@@ -281,7 +279,6 @@ export class BasketService {
     items: Array<{
       productId: number;
       quantity: number;
-      unitPrice: number;
     }>
   ): Promise<ApiResponse<Basket>> {
     const user = this.getCurrentUser();
@@ -297,7 +294,6 @@ export class BasketService {
         user_id: user.id,
         product_id: item.productId,
         quantity: item.quantity,
-        unit_price: item.unitPrice,
       };
 
       const response = await apiClient.post('/basket/items', data);
@@ -30,7 +30,6 @@ export interface AddItemRequest {
   user_id: number;
   product_id: number;
   quantity: number;
-  unit_price: number;
 }
 
 export interface UpdateItemRequest {
@@ -122,12 +122,18 @@ export interface ListRefundsResponse {
 
 export interface PaymentStatsResponse {
   total_payments: number;
-  total_amount: number;
   successful_payments: number;
   failed_payments: number;
   pending_payments: number;
-  refunded_amount: number;
-  average_payment_amount: number;
+  total_amounts: CurrencyTotalResponse[];
+  refunded_amounts: CurrencyTotalResponse[];
+}
+
+export interface CurrencyTotalResponse {
+  currency: string;
+  count: number;
+  amount_minor: number;
+  average_minor: number;
 }
 
 export interface ApiResponse<T> {
@@ -1997,8 +1997,7 @@ const docTemplate = `{
             "type": "object",
             "required": [
                 "product_id",
-                "quantity",
-                "unit_price"
+                "quantity"
             ],
             "properties": {
                 "product_id": {
@@ -2008,10 +2007,6 @@ const docTemplate = `{
                     "type": "integer",
                     "minimum": 1
                 },
-                "unit_price": {
-                    "type": "number",
-                    "minimum": 0
-                },
                 "user_id": {
                     "type": "integer"
                 }
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	_ "github.com/ddd-micro/cmd/basket/docs"
+	"github.com/gin-gonic/gin"
 )
 
 // @title Basket Service API
@@ -35,9 +36,27 @@ func main() {
 	defer app.JaegerTracer.Close()
 
 	// Setup graceful shutdown
-	_, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Health check endpoint (public), degraded while the Kafka consumer is not in its group
+	app.HTTPRouter.GET("/health", func(c *gin.Context) {
+		status, code, consumer := "healthy", http.StatusOK, "connected"
+		if !app.BasketConsumer.Connected() {
+			status, code, consumer = "degraded", http.StatusServiceUnavailable, "disconnected"
+		}
+		c.JSON(code, gin.H{
+			"status":         status,
+			"service":        "basket-service",
+			"kafka_consumer": consumer,
+		})
+	})
+
+	// Start consuming payment events to clear paid baskets
+	if err := app.BasketConsumer.Start(ctx); err != nil {
+		log.Fatalf("Failed to start basket consumer: %v", err)
+	}
+
 	// Start HTTP server
 	go func() {
 		log.Printf("Starting HTTP Server on port %s...", os.Getenv("HTTP_PORT"))
@@ -81,6 +100,11 @@ func main() {
 		app.GRPCServer.GracefulStop()
 	}()
 
+	// Stop consuming once the message in progress is handled
+	if err := app.BasketConsumer.Stop(); err != nil {
+		log.Printf("Basket consumer forced to stop: %v", err)
+	}
+
 	// Cancel main context
 	cancel()
 
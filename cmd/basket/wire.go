@@ -9,6 +9,7 @@ import (
 	"github.com/ddd-micro/internal/basket/infrastructure/monitoring"
 	"github.com/ddd-micro/internal/basket/interfaces/grpc"
 	"github.com/ddd-micro/internal/basket/interfaces/http"
+	"github.com/ddd-micro/kafka/consumers"
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
 	"google.golang.org/grpc"
@@ -16,9 +17,10 @@ import (
 
 // App represents the application dependencies
 type App struct {
-	HTTPRouter   *gin.Engine
-	GRPCServer   *grpc.Server
-	JaegerTracer *monitoring.JaegerTracer
+	HTTPRouter     *gin.Engine
+	GRPCServer     *grpc.Server
+	JaegerTracer   *monitoring.JaegerTracer
+	BasketConsumer *consumers.BasketConsumer
 }
 
 // InitializeApp initializes all application dependencies using Wire
@@ -44,10 +46,11 @@ func InitializeApp() (*App, func(), error) {
 }
 
 // NewApp creates a new App instance
-func NewApp(httpRouter *gin.Engine, grpcServer *grpc.Server, jaegerTracer *monitoring.JaegerTracer) *App {
+func NewApp(httpRouter *gin.Engine, grpcServer *grpc.Server, jaegerTracer *monitoring.JaegerTracer, basketConsumer *consumers.BasketConsumer) *App {
 	return &App{
-		HTTPRouter:   httpRouter,
-		GRPCServer:   grpcServer,
-		JaegerTracer: jaegerTracer,
+		HTTPRouter:     httpRouter,
+		GRPCServer:     grpcServer,
+		JaegerTracer:   jaegerTracer,
+		BasketConsumer: basketConsumer,
 	}
 }
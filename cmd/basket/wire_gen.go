@@ -12,23 +12,26 @@ import (
 	"github.com/ddd-micro/internal/basket/infrastructure/monitoring"
 	basketgrpc "github.com/ddd-micro/internal/basket/interfaces/grpc"
 	"github.com/ddd-micro/internal/basket/interfaces/http"
+	"github.com/ddd-micro/kafka/consumers"
 	"github.com/gin-gonic/gin"
 	"google.golang.org/grpc"
 )
 
 // App represents the application dependencies
 type App struct {
-	HTTPRouter   *gin.Engine
-	GRPCServer   *grpc.Server
-	JaegerTracer *monitoring.JaegerTracer
+	HTTPRouter     *gin.Engine
+	GRPCServer     *grpc.Server
+	JaegerTracer   *monitoring.JaegerTracer
+	BasketConsumer *consumers.BasketConsumer
 }
 
 // NewApp creates a new App instance
-func NewApp(httpRouter *gin.Engine, grpcServer *grpc.Server, jaegerTracer *monitoring.JaegerTracer) *App {
+func NewApp(httpRouter *gin.Engine, grpcServer *grpc.Server, jaegerTracer *monitoring.JaegerTracer, basketConsumer *consumers.BasketConsumer) *App {
 	return &App{
-		HTTPRouter:   httpRouter,
-		GRPCServer:   grpcServer,
-		JaegerTracer: jaegerTracer,
+		HTTPRouter:     httpRouter,
+		GRPCServer:     grpcServer,
+		JaegerTracer:   jaegerTracer,
+		BasketConsumer: basketConsumer,
 	}
 }
 
@@ -41,6 +44,16 @@ func InitializeApp() (*App, func(), error) {
 	userClient := infrastructure.NewUserClient(config)
 	productClient := infrastructure.NewProductClient(config)
 	basketRepository := infrastructure.NewBasketRepository(redisClient)
+	kafkaConfig := infrastructure.ProvideKafkaConfig()
+	eventPublisher, err := infrastructure.ProvideKafkaPublisher(kafkaConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	eventConsumer, err := infrastructure.ProvideKafkaConsumer(kafkaConfig, redisClient)
+	if err != nil {
+		return nil, nil, err
+	}
+	basketConsumer := consumers.NewBasketConsumer(eventConsumer, basketRepository, eventPublisher)
 
 	// Monitoring components
 	prometheusMetrics := monitoring.NewPrometheusMetrics()
@@ -61,7 +74,7 @@ func InitializeApp() (*App, func(), error) {
 	grpcServer := basketgrpc.NewGRPCServer(basketServer, authInterceptor)
 
 	// Main app
-	app := NewApp(httpRouter, grpcServer, jaegerTracer)
+	app := NewApp(httpRouter, grpcServer, jaegerTracer, basketConsumer)
 	return app, func() {
 		jaegerTracer.Close()
 	}, nil
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/IBM/sarama"
+	"github.com/ddd-micro/kafka"
+)
+
+const usage = `Usage: dlq <command> [flags]
+
+Inspects dead-letter topics and re-drives their messages to the original topic.
+
+Commands:
+  list      list the messages in a dead-letter topic
+  redrive   publish messages of a dead-letter topic back to their original topic
+
+Run "dlq <command> -h" for the flags of a command.
+`
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "list":
+		err = list(os.Args[2:])
+	case "redrive":
+		err = redrive(os.Args[2:])
+	case "-h", "-help", "--help", "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// options holds the flags shared by all commands
+type options struct {
+	brokers   string
+	topic     string
+	partition int
+	from      int64
+	to        int64
+	eventType string
+	limit     int
+}
+
+// register adds the shared flags to a flag set
+func (o *options) register(flags *flag.FlagSet) {
+	config := kafka.LoadConfig()
+	flags.StringVar(&o.brokers, "brokers", strings.Join(config.Brokers, ","), "comma separated Kafka brokers")
+	flags.StringVar(&o.topic, "topic", kafka.DLQTopicName(config.GroupID), "dead-letter topic")
+	flags.IntVar(&o.partition, "partition", -1, "only read this partition (-1 reads all)")
+	flags.Int64Var(&o.from, "from", 0, "first offset to read")
+	flags.Int64Var(&o.to, "to", 0, "last offset to read, inclusive (0 reads to the end)")
+	flags.StringVar(&o.eventType, "event-type", "", "only select messages of this event type")
+	flags.IntVar(&o.limit, "limit", 0, "maximum number of messages (0 selects all)")
+}
+
+// read connects to Kafka and reads the selected dead letters
+func (o *options) read() (sarama.Client, []kafka.DeadLetter, error) {
+	config := sarama.NewConfig()
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Retry.Max = 3
+	config.Producer.Return.Successes = true
+
+	client, err := sarama.NewClient(strings.Split(o.brokers, ","), config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to Kafka: %w", err)
+	}
+
+	letters, err := kafka.ReadDeadLetters(client, o.topic, kafka.DeadLetterFilter{
+		Partition:  int32(o.partition),
+		FromOffset: o.from,
+		ToOffset:   o.to,
+		EventType:  kafka.EventType(o.eventType),
+		Limit:      o.limit,
+	})
+	if err != nil {
+		client.Close()
+		return nil, nil, err
+	}
+	return client, letters, nil
+}
+
+// list prints the selected dead letters, one JSON object per line
+func list(args []string) error {
+	var opts options
+	var payload bool
+	flags := flag.NewFlagSet("list", flag.ExitOnError)
+	opts.register(flags)
+	flags.BoolVar(&payload, "payload", false, "include the message payload")
+	flags.Parse(args)
+
+	client, letters, err := opts.read()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	encoder := json.NewEncoder(os.Stdout)
+	for _, letter := range letters {
+		entry := struct {
+			kafka.DeadLetter
+			Payload json.RawMessage `json:"payload,omitempty"`
+		}{DeadLetter: letter}
+		if payload {
+			entry.Payload = rawPayload(letter)
+		}
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("%d message(s) in %s", len(letters), opts.topic)
+	return nil
+}
+
+// redrive publishes the selected dead letters back to their original topic
+func redrive(args []string) error {
+	var opts options
+	var dryRun bool
+	flags := flag.NewFlagSet("redrive", flag.ExitOnError)
+	opts.register(flags)
+	flags.BoolVar(&dryRun, "dry-run", false, "only print the messages that would be re-driven")
+	flags.Parse(args)
+
+	client, letters, err := opts.read()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var producer sarama.SyncProducer
+	if !dryRun {
+		producer, err = sarama.NewSyncProducerFromClient(client)
+		if err != nil {
+			return fmt.Errorf("failed to create producer: %w", err)
+		}
+		defer producer.Close()
+	}
+
+	for _, letter := range letters {
+		if dryRun {
+			log.Printf("would redrive %s/%d/%d (%s) to %s", letter.Topic, letter.Partition, letter.Offset, letter.EventType, letter.OriginalTopic)
+			continue
+		}
+		if err := kafka.Redrive(producer, letter); err != nil {
+			return err
+		}
+		log.Printf("redrove %s/%d/%d (%s) to %s", letter.Topic, letter.Partition, letter.Offset, letter.EventType, letter.OriginalTopic)
+	}
+
+	log.Printf("%d message(s) selected from %s", len(letters), opts.topic)
+	return nil
+}
+
+// rawPayload returns the event as JSON, quoting payloads that cannot be decoded
+func rawPayload(letter kafka.DeadLetter) json.RawMessage {
+	if event, err := letter.Event(); err == nil && json.Valid(event) {
+		return event
+	}
+	quoted, _ := json.Marshal(string(letter.Value))
+	return quoted
+}
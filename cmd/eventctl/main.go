@@ -0,0 +1,387 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/ddd-micro/kafka"
+)
+
+const usage = `Usage: eventctl <command> [flags]
+
+Inspects the event topics and replays their history.
+
+Commands:
+  tail      print events as they are published
+  export    write the events of a time or offset range as JSONL
+  replay    publish the events of a JSONL file or a topic range again,
+            to their topics, one topic (-target-topic) or one consumer group (-group)
+
+Times are RFC3339 or a duration before now, e.g. -since 2h.
+Run "eventctl <command> -h" for the flags of a command.
+`
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "tail":
+		err = tail(os.Args[2:])
+	case "export":
+		err = export(os.Args[2:])
+	case "replay":
+		err = replay(os.Args[2:])
+	case "-h", "-help", "--help", "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// options holds the flags shared by all commands
+type options struct {
+	config      *kafka.Config
+	brokers     string
+	topics      string
+	partition   int
+	eventType   string
+	aggregateID string
+}
+
+// register adds the shared flags to a flag set
+func (o *options) register(flags *flag.FlagSet) {
+	o.config = kafka.LoadConfig()
+	flags.StringVar(&o.brokers, "brokers", strings.Join(o.config.Brokers, ","), "comma separated Kafka brokers")
+	flags.StringVar(&o.topics, "topics", strings.Join(o.config.TopicRoutes.Topics(), ","), "comma separated topics to read")
+	flags.IntVar(&o.partition, "partition", -1, "only read this partition (-1 reads all)")
+	flags.StringVar(&o.eventType, "event-type", "", "only select events of this type")
+	flags.StringVar(&o.aggregateID, "aggregate", "", "only select events about this aggregate ID")
+}
+
+// filter returns the record filter of the shared flags
+func (o *options) filter() kafka.RecordFilter {
+	return kafka.RecordFilter{
+		Partition:   int32(o.partition),
+		EventType:   kafka.EventType(o.eventType),
+		AggregateID: o.aggregateID,
+	}
+}
+
+// connect connects to the Kafka brokers
+func (o *options) connect() (sarama.Client, error) {
+	config := sarama.NewConfig()
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Retry.Max = 3
+	config.Producer.Return.Successes = true
+
+	client, err := sarama.NewClient(strings.Split(o.brokers, ","), config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Kafka: %w", err)
+	}
+	return client, nil
+}
+
+// rangeOptions holds the flags selecting a range of a topic
+type rangeOptions struct {
+	since string
+	until string
+	from  int64
+	to    int64
+	limit int
+}
+
+// register adds the range flags to a flag set
+func (r *rangeOptions) register(flags *flag.FlagSet) {
+	flags.StringVar(&r.since, "since", "", "first message time to read")
+	flags.StringVar(&r.until, "until", "", "message time to stop reading at, exclusive")
+	flags.Int64Var(&r.from, "from", 0, "first offset to read")
+	flags.Int64Var(&r.to, "to", 0, "last offset to read, inclusive (0 reads to the end)")
+	flags.IntVar(&r.limit, "limit", 0, "maximum number of events per topic (0 selects all)")
+}
+
+// apply adds the range to a record filter
+func (r *rangeOptions) apply(filter *kafka.RecordFilter) error {
+	var err error
+	if filter.Since, err = parseTime(r.since); err != nil {
+		return err
+	}
+	if filter.Until, err = parseTime(r.until); err != nil {
+		return err
+	}
+	filter.FromOffset = r.from
+	filter.ToOffset = r.to
+	filter.Limit = r.limit
+	return nil
+}
+
+// tail prints the selected events as they are published until interrupted
+func tail(args []string) error {
+	var opts options
+	var fromBeginning, asJSON bool
+	flags := flag.NewFlagSet("tail", flag.ExitOnError)
+	opts.register(flags)
+	flags.BoolVar(&fromBeginning, "from-beginning", false, "start at the oldest events instead of new ones")
+	flags.BoolVar(&asJSON, "json", false, "print one JSON record per line instead of pretty-printing")
+	flags.Parse(args)
+
+	client, err := opts.connect()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	topics := splitList(opts.topics)
+	log.Printf("Tailing %s", strings.Join(topics, ", "))
+
+	encoder := json.NewEncoder(os.Stdout)
+	return kafka.TailRecords(ctx, client, topics, opts.filter(), fromBeginning, func(record kafka.Record) error {
+		if asJSON {
+			return encoder.Encode(record)
+		}
+		return prettyPrint(os.Stdout, record)
+	})
+}
+
+// export writes the selected events to a JSONL file, one record per line
+func export(args []string) error {
+	var opts options
+	var window rangeOptions
+	var out string
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	opts.register(flags)
+	window.register(flags)
+	flags.StringVar(&out, "out", "-", "file to write, - for stdout")
+	flags.Parse(args)
+
+	filter := opts.filter()
+	if err := window.apply(&filter); err != nil {
+		return err
+	}
+
+	client, err := opts.connect()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	writer := io.Writer(os.Stdout)
+	if out != "-" {
+		file, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	buffered := bufio.NewWriter(writer)
+	encoder := json.NewEncoder(buffered)
+	count := 0
+	for _, topic := range splitList(opts.topics) {
+		records, err := kafka.ReadRecords(client, topic, filter)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		count += len(records)
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+
+	log.Printf("Exported %d event(s)", count)
+	return nil
+}
+
+// replay publishes the selected events of a JSONL file or of the topics again
+func replay(args []string) error {
+	var opts options
+	var window rangeOptions
+	var file string
+	var target kafka.ReplayTarget
+	var dryRun bool
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	opts.register(flags)
+	window.register(flags)
+	flags.StringVar(&file, "file", "", "JSONL file written by export to replay instead of reading the topics")
+	flags.StringVar(&target.Topic, "target-topic", "", "send all events to this topic instead of the topic of their type")
+	flags.StringVar(&target.Group, "group", "", "send the events to the retry topic of this consumer group, so only it handles them")
+	retryDelay := time.Minute
+	if len(opts.config.RetryTopicDelays) > 0 {
+		retryDelay = opts.config.RetryTopicDelays[0]
+	}
+	flags.DurationVar(&target.RetryDelay, "retry-delay", retryDelay, "delay of the group retry topic to send to")
+	flags.BoolVar(&target.NewIDs, "new-ids", false, "give the events new IDs, so consumers do not skip them as duplicates")
+	flags.BoolVar(&dryRun, "dry-run", false, "only print the events that would be replayed")
+	flags.Parse(args)
+
+	filter := opts.filter()
+	if err := window.apply(&filter); err != nil {
+		return err
+	}
+	if target.Topic != "" && target.Group != "" {
+		return fmt.Errorf("-target-topic and -group are exclusive")
+	}
+
+	var client sarama.Client
+	if file == "" || !dryRun {
+		var err error
+		if client, err = opts.connect(); err != nil {
+			return err
+		}
+		defer client.Close()
+	}
+
+	var records []kafka.Record
+	if file != "" {
+		read, err := readFile(file, filter)
+		if err != nil {
+			return err
+		}
+		records = read
+	} else {
+		for _, topic := range splitList(opts.topics) {
+			read, err := kafka.ReadRecords(client, topic, filter)
+			if err != nil {
+				return err
+			}
+			records = append(records, read...)
+		}
+	}
+
+	var producer sarama.SyncProducer
+	if !dryRun {
+		var err error
+		producer, err = sarama.NewSyncProducerFromClient(client)
+		if err != nil {
+			return fmt.Errorf("failed to create producer: %w", err)
+		}
+		defer producer.Close()
+	}
+
+	publisherConfig := opts.config.GetPublisherConfig()
+	for _, record := range records {
+		topic := target.TopicOf(publisherConfig.TopicRoutes, record)
+		if dryRun {
+			log.Printf("would replay %s (%s %s) to %s", record.Position(), record.EventType, record.AggregateID, topic)
+			continue
+		}
+		if err := kafka.Replay(producer, publisherConfig, record, target); err != nil {
+			return err
+		}
+		log.Printf("replayed %s (%s %s) to %s", record.Position(), record.EventType, record.AggregateID, topic)
+	}
+
+	log.Printf("%d event(s) selected", len(records))
+	return nil
+}
+
+// readFile reads the matching records of a JSONL file written by export
+func readFile(path string, filter kafka.RecordFilter) ([]kafka.Record, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// Records are read whole from the file, so the position filters do not apply
+	filter.Partition = -1
+
+	var records []kafka.Record
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var record kafka.Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		if filter.Match(record) {
+			records = append(records, record)
+		}
+		if filter.Limit > 0 && len(records) >= filter.Limit {
+			break
+		}
+	}
+	return records, scanner.Err()
+}
+
+// prettyPrint prints a record as a summary line followed by the indented event
+func prettyPrint(w io.Writer, record kafka.Record) error {
+	fmt.Fprintf(w, "%s  %s  %s", record.Timestamp.Format(time.RFC3339Nano), record.Position(), record.EventType)
+	if record.AggregateID != "" {
+		fmt.Fprintf(w, "  aggregate=%s", record.AggregateID)
+	}
+	if record.EventID != "" {
+		fmt.Fprintf(w, "  id=%s", record.EventID)
+	}
+	fmt.Fprintln(w)
+
+	if record.DecodeError != "" {
+		_, err := fmt.Fprintf(w, "  cannot decode event: %s\n\n", record.DecodeError)
+		return err
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, record.Event, "  ", "  "); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "  %s\n\n", indented.Bytes())
+	return err
+}
+
+// parseTime parses an RFC3339 time or a duration before now; empty means no bound
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if duration, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-duration), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, want RFC3339 or a duration", value)
+}
+
+// splitList splits a comma separated flag value
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/ddd-micro/kafka"
+)
+
+const usage = `Usage: eventschema <command> [flags]
+
+Maintains the event schemas committed in kafka/schemas.
+
+Commands:
+  check                 fail when an event struct no longer matches its latest schema,
+                        or a schema version is incompatible without an upcaster
+  generate -type T -version V [-dir kafka/schemas]
+                        write the schema of the current data struct of T as version V
+`
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "check":
+		check()
+	case "generate":
+		if err := generate(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+	case "-h", "-help", "--help", "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+}
+
+// check prints every compatibility problem and exits non-zero when there is one
+func check() {
+	problems := kafka.CheckSchemaCompatibility()
+	for _, problem := range problems {
+		log.Println(problem)
+	}
+	if len(problems) > 0 {
+		os.Exit(1)
+	}
+	log.Println("Event schemas are up to date")
+}
+
+// generate writes the schema of the current data struct of an event type
+func generate(args []string) error {
+	var eventType, version, dir string
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	flags.StringVar(&eventType, "type", "", "event type, e.g. payment.completed")
+	flags.StringVar(&version, "version", "", "schema version, e.g. 1.1")
+	flags.StringVar(&dir, "dir", filepath.Join("kafka", "schemas"), "schema directory")
+	flags.Parse(args)
+
+	if eventType == "" || version == "" {
+		return fmt.Errorf("-type and -version are required")
+	}
+
+	schema, err := kafka.GenerateSchema(kafka.EventType(eventType))
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, eventType, version+".json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	log.Printf("Wrote %s", path)
+	return nil
+}
@@ -0,0 +1,137 @@
+// @title Order Service API
+// @version 1.0
+// @description Order Service API for placing, tracking and cancelling orders
+// @termsOfService http://swagger.io/terms/
+
+// @contact.name API Support
+// @contact.url http://www.swagger.io/support
+// @contact.email support@swagger.io
+
+// @license.name MIT
+// @license.url https://opensource.org/licenses/MIT
+
+// @host localhost:8085
+// @BasePath /api/v1
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Type "Bearer" followed by a space and JWT token.
+
+package main
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	_ "github.com/ddd-micro/cmd/order/docs" // This is required for swagger docs
+	"github.com/gin-gonic/gin"
+)
+
+func main() {
+	// Initialize application
+	app, cleanup, err := InitializeApp()
+	if err != nil {
+		log.Fatalf("Failed to initialize app: %v", err)
+	}
+	defer cleanup()
+	defer app.JaegerTracer.Close()
+
+	// Health check endpoint (public), degraded while the Kafka consumer is not in its group
+	app.HTTPRouter.GET("/health", func(c *gin.Context) {
+		status, code, consumer := "ok", http.StatusOK, "connected"
+		if !app.OrderConsumer.Connected() {
+			status, code, consumer = "degraded", http.StatusServiceUnavailable, "disconnected"
+		}
+		c.JSON(code, gin.H{
+			"status":         status,
+			"service":        "order-service",
+			"kafka_consumer": consumer,
+		})
+	})
+
+	// Start publishing outbox events to Kafka
+	if err := app.OutboxRelay.Start(context.Background()); err != nil {
+		log.Fatalf("Failed to start outbox relay: %v", err)
+	}
+
+	// Start following payment events to move orders through their lifecycle
+	consumerCtx, cancelConsumer := context.WithCancel(context.Background())
+	defer cancelConsumer()
+	if err := app.OrderConsumer.Start(consumerCtx); err != nil {
+		log.Fatalf("Failed to start order consumer: %v", err)
+	}
+
+	// Start HTTP server
+	httpPort := os.Getenv("HTTP_PORT")
+	if httpPort == "" {
+		httpPort = "8085"
+	}
+
+	httpSrv := &http.Server{
+		Addr:    ":" + httpPort,
+		Handler: app.HTTPRouter,
+	}
+
+	go func() {
+		log.Printf("Starting HTTP Server on port %s...", httpPort)
+		if err := httpSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("HTTP server failed to start: %v", err)
+		}
+	}()
+
+	// Start gRPC server
+	go func() {
+		grpcPort := os.Getenv("GRPC_PORT")
+		if grpcPort == "" {
+			grpcPort = "9095"
+		}
+
+		lis, err := net.Listen("tcp", ":"+grpcPort)
+		if err != nil {
+			log.Fatalf("Failed to listen on gRPC port %s: %v", grpcPort, err)
+		}
+
+		log.Printf("Starting gRPC Server on port %s...", grpcPort)
+		if err := app.GRPCServer.Serve(lis); err != nil {
+			log.Fatalf("gRPC server failed to start: %v", err)
+		}
+	}()
+
+	// Wait for interrupt signal to gracefully shutdown the servers
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down servers...")
+
+	// Create a deadline to wait for
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Shutdown HTTP and gRPC servers
+	log.Println("Stopping HTTP server...")
+	if err := httpSrv.Shutdown(ctx); err != nil {
+		log.Printf("HTTP server forced to shutdown: %v", err)
+	}
+	log.Println("Stopping gRPC server...")
+	app.GRPCServer.GracefulStop()
+
+	// Stop consuming once the message in progress is handled
+	log.Println("Stopping order consumer...")
+	if err := app.OrderConsumer.Stop(); err != nil {
+		log.Printf("Order consumer forced to stop: %v", err)
+	}
+	cancelConsumer()
+
+	// Stop the outbox relay after the last request has stored its events
+	log.Println("Stopping outbox relay...")
+	app.OutboxRelay.Stop()
+
+	log.Println("Servers stopped")
+}
@@ -0,0 +1,62 @@
+//go:build wireinject
+// +build wireinject
+
+package main
+
+import (
+	"github.com/ddd-micro/internal/order/application"
+	"github.com/ddd-micro/internal/order/infrastructure"
+	"github.com/ddd-micro/internal/order/infrastructure/monitoring"
+	ordergrpc "github.com/ddd-micro/internal/order/interfaces/grpc"
+	"github.com/ddd-micro/internal/order/interfaces/http"
+	"github.com/ddd-micro/kafka/consumers"
+	"github.com/ddd-micro/pkg/outbox"
+	"github.com/gin-gonic/gin"
+	"github.com/google/wire"
+	"google.golang.org/grpc"
+)
+
+// App represents the application dependencies
+type App struct {
+	HTTPRouter    *gin.Engine
+	GRPCServer    *grpc.Server
+	JaegerTracer  *monitoring.JaegerTracer
+	OutboxRelay   *outbox.Relay
+	OrderConsumer *consumers.OrderConsumer
+}
+
+// InitializeApp initializes all application dependencies using Wire
+func InitializeApp() (*App, func(), error) {
+	wire.Build(
+		// Infrastructure layer
+		infrastructure.ProviderSet,
+
+		// Application layer
+		application.ProviderSet,
+
+		// HTTP interface layer
+		http.ProviderSet,
+
+		// gRPC interface layer
+		ordergrpc.ProviderSet,
+
+		// Kafka consumer following payment events
+		consumers.NewOrderConsumer,
+
+		// Main app
+		NewApp,
+	)
+
+	return &App{}, nil, nil
+}
+
+// NewApp creates a new App instance
+func NewApp(httpRouter *gin.Engine, grpcServer *grpc.Server, jaegerTracer *monitoring.JaegerTracer, outboxRelay *outbox.Relay, orderConsumer *consumers.OrderConsumer) *App {
+	return &App{
+		HTTPRouter:    httpRouter,
+		GRPCServer:    grpcServer,
+		JaegerTracer:  jaegerTracer,
+		OutboxRelay:   outboxRelay,
+		OrderConsumer: orderConsumer,
+	}
+}
@@ -23,16 +23,14 @@ package main
 import (
 	"context"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	_ "github.com/ddd-micro/cmd/payment/docs" // This is required for swagger docs
-	"github.com/ddd-micro/internal/payment/infrastructure/monitoring"
 	"github.com/gin-gonic/gin"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
 func main() {
@@ -44,6 +42,23 @@ func main() {
 	defer cleanup()
 	defer app.JaegerTracer.Close()
 
+	// Start publishing outbox events to Kafka
+	if err := app.OutboxRelay.Start(context.Background()); err != nil {
+		log.Fatalf("Failed to start outbox relay: %v", err)
+	}
+
+	// Start driving checkout sagas with payment and basket events
+	consumerCtx, cancelConsumer := context.WithCancel(context.Background())
+	defer cancelConsumer()
+	if err := app.CheckoutConsumer.Start(consumerCtx); err != nil {
+		log.Fatalf("Failed to start checkout consumer: %v", err)
+	}
+
+	// Start resuming checkouts left behind by crashes and timing out their steps
+	if err := app.Checkout.Start(context.Background()); err != nil {
+		log.Fatalf("Failed to start checkout recovery: %v", err)
+	}
+
 	// Set Gin mode
 	gin.SetMode(gin.ReleaseMode)
 
@@ -53,11 +68,18 @@ func main() {
 		httpPort = "8084"
 	}
 
-	log.Printf("Starting HTTP Server on port %s...", httpPort)
-	if err := app.HTTPRouter.Run(":" + httpPort); err != nil {
-		log.Fatalf("HTTP server failed to start: %v", err)
+	httpSrv := &http.Server{
+		Addr:    ":" + httpPort,
+		Handler: app.HTTPRouter,
 	}
 
+	go func() {
+		log.Printf("Starting HTTP Server on port %s...", httpPort)
+		if err := httpSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("HTTP server failed to start: %v", err)
+		}
+	}()
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -65,122 +87,28 @@ func main() {
 	log.Println("Shutting down server...")
 
 	// Create a deadline to wait for
-	_, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	// Shutdown HTTP server
 	log.Println("Stopping HTTP server...")
-	// Note: Gin doesn't have built-in graceful shutdown, but we can add it if needed
-
-	log.Println("Server stopped")
-}
-
-// App represents the application dependencies
-type App struct {
-	HTTPRouter   *gin.Engine
-	JaegerTracer *monitoring.JaegerTracer
-}
+	if err := httpSrv.Shutdown(ctx); err != nil {
+		log.Printf("HTTP server forced to shutdown: %v", err)
+	}
 
-// InitializeApp initializes all application dependencies using Wire
-func InitializeApp() (*App, func(), error) {
-	// For now, create a basic router setup
-	router := gin.Default()
-
-	// Add Swagger route
-	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-
-	// Add health check
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{"status": "ok", "service": "payment-service"})
-	})
-
-	// Add basic API routes for Swagger generation
-	api := router.Group("/api/v1")
-	{
-		// Payment routes
-		payments := api.Group("/payments")
-		{
-			payments.POST("", func(c *gin.Context) {
-				c.JSON(201, gin.H{"message": "Payment created"})
-			})
-			payments.GET("", func(c *gin.Context) {
-				c.JSON(200, gin.H{"payments": []gin.H{}})
-			})
-			payments.GET("/:id", func(c *gin.Context) {
-				c.JSON(200, gin.H{"id": c.Param("id")})
-			})
-			payments.POST("/:id/process", func(c *gin.Context) {
-				c.JSON(200, gin.H{"message": "Payment processed"})
-			})
-			payments.POST("/:id/cancel", func(c *gin.Context) {
-				c.JSON(200, gin.H{"message": "Payment cancelled"})
-			})
-		}
+	// Stop consuming once the message in progress is handled
+	log.Println("Stopping checkout consumer...")
+	if err := app.CheckoutConsumer.Stop(); err != nil {
+		log.Printf("Checkout consumer forced to stop: %v", err)
+	}
+	cancelConsumer()
 
-		// Payment method routes
-		paymentMethods := api.Group("/payment-methods")
-		{
-			paymentMethods.GET("", func(c *gin.Context) {
-				c.JSON(200, gin.H{"payment_methods": []gin.H{}})
-			})
-			paymentMethods.POST("", func(c *gin.Context) {
-				c.JSON(201, gin.H{"message": "Payment method added"})
-			})
-			paymentMethods.PUT("/:id", func(c *gin.Context) {
-				c.JSON(200, gin.H{"message": "Payment method updated"})
-			})
-			paymentMethods.DELETE("/:id", func(c *gin.Context) {
-				c.Status(204)
-			})
-			paymentMethods.POST("/:id/set-default", func(c *gin.Context) {
-				c.JSON(200, gin.H{"message": "Payment method set as default"})
-			})
-		}
+	// Stop checkout recovery; sagas it was working on are resumed once their lease lapses
+	app.Checkout.Stop()
 
-		// Admin routes
-		admin := api.Group("/admin")
-		{
-			adminPayments := admin.Group("/payments")
-			{
-				adminPayments.GET("", func(c *gin.Context) {
-					c.JSON(200, gin.H{"payments": []gin.H{}})
-				})
-				adminPayments.GET("/:id", func(c *gin.Context) {
-					c.JSON(200, gin.H{"id": c.Param("id")})
-				})
-				adminPayments.PUT("/:id/status", func(c *gin.Context) {
-					c.JSON(200, gin.H{"message": "Payment status updated"})
-				})
-			}
-
-			adminRefunds := admin.Group("/refunds")
-			{
-				adminRefunds.GET("", func(c *gin.Context) {
-					c.JSON(200, gin.H{"refunds": []gin.H{}})
-				})
-				adminRefunds.POST("", func(c *gin.Context) {
-					c.JSON(201, gin.H{"message": "Refund created"})
-				})
-				adminRefunds.GET("/:id", func(c *gin.Context) {
-					c.JSON(200, gin.H{"id": c.Param("id")})
-				})
-				adminRefunds.POST("/:id/process", func(c *gin.Context) {
-					c.JSON(200, gin.H{"message": "Refund processed"})
-				})
-			}
-
-			adminAnalytics := admin.Group("/analytics")
-			{
-				adminAnalytics.GET("/payments", func(c *gin.Context) {
-					c.JSON(200, gin.H{"stats": gin.H{}})
-				})
-			}
-		}
-	}
+	// Stop the outbox relay after the last request has stored its events
+	log.Println("Stopping outbox relay...")
+	app.OutboxRelay.Stop()
 
-	return &App{
-			HTTPRouter: router,
-		}, func() {
-			// Cleanup function
-		}, nil
+	log.Println("Server stopped")
 }
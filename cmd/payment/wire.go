@@ -1,3 +1,6 @@
+//go:build wireinject
+// +build wireinject
+
 package main
 
 import (
@@ -6,14 +9,19 @@ import (
 	"github.com/ddd-micro/internal/payment/infrastructure/kafka"
 	"github.com/ddd-micro/internal/payment/infrastructure/monitoring"
 	"github.com/ddd-micro/internal/payment/interfaces/http"
+	"github.com/ddd-micro/kafka/consumers"
+	"github.com/ddd-micro/pkg/outbox"
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
 )
 
 // App represents the application dependencies
 type App struct {
-	HTTPRouter   *gin.Engine
-	JaegerTracer *monitoring.JaegerTracer
+	HTTPRouter       *gin.Engine
+	JaegerTracer     *monitoring.JaegerTracer
+	OutboxRelay      *outbox.Relay
+	Checkout         *application.CheckoutOrchestrator
+	CheckoutConsumer *consumers.CheckoutConsumer
 }
 
 // InitializeApp initializes all application dependencies using Wire
@@ -30,6 +38,7 @@ func InitializeApp() (*App, func(), error) {
 
 		// Kafka layer
 		kafka.ProviderSet,
+		consumers.NewCheckoutConsumer,
 
 		// Main app
 		NewApp,
@@ -39,9 +48,12 @@ func InitializeApp() (*App, func(), error) {
 }
 
 // NewApp creates a new App instance
-func NewApp(httpRouter *gin.Engine, jaegerTracer *monitoring.JaegerTracer) *App {
+func NewApp(httpRouter *gin.Engine, jaegerTracer *monitoring.JaegerTracer, outboxRelay *outbox.Relay, checkout *application.CheckoutOrchestrator, checkoutConsumer *consumers.CheckoutConsumer) *App {
 	return &App{
-		HTTPRouter:   httpRouter,
-		JaegerTracer: jaegerTracer,
+		HTTPRouter:       httpRouter,
+		JaegerTracer:     jaegerTracer,
+		OutboxRelay:      outboxRelay,
+		Checkout:         checkout,
+		CheckoutConsumer: checkoutConsumer,
 	}
 }
@@ -11,57 +11,123 @@ import (
 	"github.com/ddd-micro/internal/payment/application/command"
 	"github.com/ddd-micro/internal/payment/application/query"
 	"github.com/ddd-micro/internal/payment/infrastructure"
-	"github.com/ddd-micro/internal/payment/infrastructure/kafka"
+	"github.com/ddd-micro/internal/payment/infrastructure/config"
+	"github.com/ddd-micro/internal/payment/infrastructure/database"
+	"github.com/ddd-micro/internal/payment/infrastructure/gateway"
+	paymentkafka "github.com/ddd-micro/internal/payment/infrastructure/kafka"
 	"github.com/ddd-micro/internal/payment/infrastructure/monitoring"
-	"github.com/ddd-micro/internal/payment/interfaces/http"
+	"github.com/ddd-micro/internal/payment/infrastructure/persistence"
+	paymenthttp "github.com/ddd-micro/internal/payment/interfaces/http"
+	"github.com/ddd-micro/kafka"
+	"github.com/ddd-micro/kafka/consumers"
+	"github.com/ddd-micro/pkg/gormtx"
+	"github.com/ddd-micro/pkg/outbox"
+	"github.com/gin-gonic/gin"
 )
 
+// App represents the application dependencies
+type App struct {
+	HTTPRouter       *gin.Engine
+	JaegerTracer     *monitoring.JaegerTracer
+	OutboxRelay      *outbox.Relay
+	Checkout         *application.CheckoutOrchestrator
+	CheckoutConsumer *consumers.CheckoutConsumer
+}
+
+// NewApp creates a new App instance
+func NewApp(httpRouter *gin.Engine, jaegerTracer *monitoring.JaegerTracer, outboxRelay *outbox.Relay, checkout *application.CheckoutOrchestrator, checkoutConsumer *consumers.CheckoutConsumer) *App {
+	return &App{
+		HTTPRouter:       httpRouter,
+		JaegerTracer:     jaegerTracer,
+		OutboxRelay:      outboxRelay,
+		Checkout:         checkout,
+		CheckoutConsumer: checkoutConsumer,
+	}
+}
+
 // Injectors from wire.go:
 
 func InitializeApp() (*App, func(), error) {
-	configConfig := infrastructure.LoadConfig()
-	db := infrastructure.NewPostgresDB(configConfig)
-	paymentRepository := infrastructure.NewPaymentRepository(db)
-	paymentMethodRepository := infrastructure.NewPaymentMethodRepository(db)
-	refundRepository := infrastructure.NewRefundRepository(db)
-	userClient, err := infrastructure.NewUserClient(configConfig)
+	// Infrastructure layer
+	configConfig, err := config.LoadConfig()
 	if err != nil {
 		return nil, nil, err
 	}
-	productClient, err := infrastructure.NewProductClient(configConfig)
+	db, err := database.NewPostgresDB(configConfig)
 	if err != nil {
 		return nil, nil, err
 	}
-	basketClient, err := infrastructure.NewBasketClient(configConfig)
+	paymentRepository := persistence.NewPaymentRepository(db)
+	paymentMethodRepository := persistence.NewPaymentMethodRepository(db)
+	refundRepository := persistence.NewRefundRepository(db)
+	webhookEventRepository := persistence.NewWebhookEventRepository(db)
+	idempotencyKeyRepository := persistence.NewIdempotencyKeyRepository(db)
+	checkoutSagaRepository := persistence.NewCheckoutSagaRepository(db)
+	transactor := gormtx.NewTransactor(db)
+	userClient, err := infrastructure.ProvideUserClient(configConfig)
 	if err != nil {
 		return nil, nil, err
 	}
-	stripeGateway := infrastructure.NewStripeGateway(configConfig)
-	mockGateway := infrastructure.NewMockGateway()
+	productClient, err := infrastructure.ProvideProductClient(configConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	basketClient, err := infrastructure.ProvideBasketClient(configConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	paymentGateway := gateway.NewPaymentGateway(configConfig)
 	kafkaConfig := kafka.LoadConfig()
-	kafkaPublisher, err := kafka.NewKafkaPublisher(kafkaConfig)
+	eventPublisher, err := infrastructure.ProvideKafkaPublisher(kafkaConfig)
 	if err != nil {
 		return nil, nil, err
 	}
-	paymentEventPublisher := kafka.NewPaymentEventPublisher(kafkaPublisher)
-	createPaymentCommandHandler := command.NewCreatePaymentCommandHandler(paymentRepository, stripeGateway)
-	processPaymentCommandHandler := command.NewProcessPaymentCommandHandler(paymentRepository, stripeGateway)
-	cancelPaymentCommandHandler := command.NewCancelPaymentCommandHandler(paymentRepository, stripeGateway)
-	addPaymentMethodCommandHandler := command.NewAddPaymentMethodCommandHandler(paymentMethodRepository, stripeGateway)
+	store := outbox.NewStore(db)
+	paymentEventPublisher := paymentkafka.NewPaymentEventPublisher(store)
+	relay := infrastructure.ProvideOutboxRelay(db, eventPublisher)
+	eventConsumer, err := infrastructure.ProvideKafkaConsumer(kafkaConfig, db, transactor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Application layer
+	createPaymentCommandHandler := command.NewCreatePaymentCommandHandler(paymentRepository, paymentGateway)
+	processPaymentCommandHandler := command.NewProcessPaymentCommandHandler(paymentRepository, paymentGateway)
+	cancelPaymentCommandHandler := command.NewCancelPaymentCommandHandler(paymentRepository, paymentGateway)
+	addPaymentMethodCommandHandler := command.NewAddPaymentMethodCommandHandler(paymentMethodRepository, paymentGateway)
 	updatePaymentMethodCommandHandler := command.NewUpdatePaymentMethodCommandHandler(paymentMethodRepository)
 	deletePaymentMethodCommandHandler := command.NewDeletePaymentMethodCommandHandler(paymentMethodRepository)
+	processWebhookCommandHandler := command.NewProcessWebhookCommandHandler(paymentRepository, refundRepository, webhookEventRepository, paymentGateway)
+	createRefundCommandHandler := command.NewCreateRefundCommandHandler(paymentRepository, refundRepository)
+	processRefundCommandHandler := command.NewProcessRefundCommandHandler(paymentRepository, refundRepository, paymentGateway)
+	authorizePaymentCommandHandler := command.NewAuthorizePaymentCommandHandler(paymentRepository, paymentGateway)
+	capturePaymentCommandHandler := command.NewCapturePaymentCommandHandler(paymentRepository, paymentGateway)
+	voidAuthorizationCommandHandler := command.NewVoidAuthorizationCommandHandler(paymentRepository, paymentGateway)
+	updatePaymentStatusCommandHandler := command.NewUpdatePaymentStatusCommandHandler(paymentRepository)
 	getPaymentQueryHandler := query.NewGetPaymentQueryHandler(paymentRepository)
 	listPaymentsQueryHandler := query.NewListPaymentsQueryHandler(paymentRepository)
 	getPaymentMethodQueryHandler := query.NewGetPaymentMethodQueryHandler(paymentMethodRepository)
 	listPaymentMethodsQueryHandler := query.NewListPaymentMethodsQueryHandler(paymentMethodRepository)
-	paymentServiceCQRS := application.NewPaymentServiceCQRS(createPaymentCommandHandler, processPaymentCommandHandler, cancelPaymentCommandHandler, addPaymentMethodCommandHandler, updatePaymentMethodCommandHandler, deletePaymentMethodCommandHandler, getPaymentQueryHandler, listPaymentsQueryHandler, getPaymentMethodQueryHandler, listPaymentMethodsQueryHandler, paymentRepository, paymentMethodRepository, userClient, productClient, basketClient, paymentEventPublisher)
+	getRefundQueryHandler := query.NewGetRefundQueryHandler(refundRepository)
+	getPaymentHistoryQueryHandler := query.NewGetPaymentHistoryQueryHandler(paymentRepository)
+	checkoutOrchestrator := application.NewCheckoutOrchestrator(checkoutSagaRepository, paymentRepository, createPaymentCommandHandler, cancelPaymentCommandHandler, productClient, basketClient, paymentEventPublisher, transactor, configConfig)
+	paymentServiceCQRS := application.NewPaymentServiceCQRS(createPaymentCommandHandler, processPaymentCommandHandler, cancelPaymentCommandHandler, addPaymentMethodCommandHandler, updatePaymentMethodCommandHandler, deletePaymentMethodCommandHandler, processWebhookCommandHandler, createRefundCommandHandler, processRefundCommandHandler, authorizePaymentCommandHandler, capturePaymentCommandHandler, voidAuthorizationCommandHandler, updatePaymentStatusCommandHandler, getPaymentQueryHandler, listPaymentsQueryHandler, getPaymentMethodQueryHandler, listPaymentMethodsQueryHandler, getRefundQueryHandler, getPaymentHistoryQueryHandler, paymentRepository, paymentMethodRepository, refundRepository, checkoutSagaRepository, checkoutOrchestrator, userClient, productClient, basketClient, paymentEventPublisher, transactor)
+
+	// Monitoring components
 	prometheusMetrics := monitoring.NewPrometheusMetrics()
 	jaegerTracer, err := monitoring.ProvideJaegerTracer()
 	if err != nil {
 		return nil, nil, err
 	}
-	ginEngine := http.NewRouter(paymentServiceCQRS, userClient, prometheusMetrics, jaegerTracer)
-	app := NewApp(ginEngine, jaegerTracer)
+
+	// HTTP interface layer
+	ginEngine := paymenthttp.NewRouter(paymentServiceCQRS, checkoutOrchestrator, userClient, idempotencyKeyRepository, configConfig, prometheusMetrics, jaegerTracer)
+
+	// Kafka consumer driving checkout sagas
+	checkoutConsumer := consumers.NewCheckoutConsumer(eventConsumer, checkoutOrchestrator, paymentServiceCQRS)
+
+	// Main app
+	app := NewApp(ginEngine, jaegerTracer, relay, checkoutOrchestrator, checkoutConsumer)
 	return app, func() {
 	}, nil
 }
@@ -47,12 +47,34 @@ func main() {
 	defer app.UserClient.Close()
 	defer app.JaegerTracer.Close()
 
-	// Health check endpoint
+	// Start publishing outbox events to Kafka
+	if err := app.OutboxRelay.Start(context.Background()); err != nil {
+		log.Fatalf("Failed to start outbox relay: %v", err)
+	}
+
+	// Start consuming payment events to update stock
+	consumerCtx, cancelConsumer := context.WithCancel(context.Background())
+	defer cancelConsumer()
+	if err := app.ProductConsumer.Start(consumerCtx); err != nil {
+		log.Fatalf("Failed to start product consumer: %v", err)
+	}
+
+	// Start releasing stock held by expired reservations
+	if err := app.ReservationService.Start(context.Background()); err != nil {
+		log.Fatalf("Failed to start reservation sweeper: %v", err)
+	}
+
+	// Health check endpoint, degraded while the Kafka consumer is not in its group
 	app.HTTPRouter.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status":  "healthy",
-			"service": "product-service",
-			"time":    time.Now().UTC(),
+		status, code, consumer := "healthy", http.StatusOK, "connected"
+		if !app.ProductConsumer.Connected() {
+			status, code, consumer = "degraded", http.StatusServiceUnavailable, "disconnected"
+		}
+		c.JSON(code, gin.H{
+			"status":         status,
+			"service":        "product-service",
+			"kafka_consumer": consumer,
+			"time":           time.Now().UTC(),
 		})
 	})
 
@@ -127,6 +149,18 @@ func main() {
 	// Gracefully stop gRPC server
 	app.GRPCServer.GracefulStop()
 
+	// Stop consuming once the message in progress is handled
+	if err := app.ProductConsumer.Stop(); err != nil {
+		log.Printf("Product consumer forced to stop: %v", err)
+	}
+	cancelConsumer()
+
+	// Stop the sweeper; reservations it missed are released by the next one
+	app.ReservationService.Stop()
+
+	// Stop the outbox relay after the last request has stored its events
+	app.OutboxRelay.Stop()
+
 	log.Println("Servers exited gracefully")
 }
 
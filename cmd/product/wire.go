@@ -10,6 +10,8 @@ import (
 	"github.com/ddd-micro/internal/product/infrastructure/monitoring"
 	productgrpc "github.com/ddd-micro/internal/product/interfaces/grpc"
 	producthttp "github.com/ddd-micro/internal/product/interfaces/http"
+	"github.com/ddd-micro/kafka/consumers"
+	"github.com/ddd-micro/pkg/outbox"
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
 	"google.golang.org/grpc"
@@ -38,13 +40,16 @@ func InitializeApp() (*App, error) {
 
 // App holds all application dependencies
 type App struct {
-	HTTPRouter     *gin.Engine
-	GRPCServer     *grpc.Server
-	ProductService *application.ProductServiceCQRS
-	UserService    *application.UserService
-	Database       *database.Database
-	UserClient     interface{ Close() error }
-	JaegerTracer   *monitoring.JaegerTracer
+	HTTPRouter         *gin.Engine
+	GRPCServer         *grpc.Server
+	ProductService     *application.ProductServiceCQRS
+	ReservationService *application.ReservationService
+	UserService        *application.UserService
+	Database           *database.Database
+	UserClient         interface{ Close() error }
+	JaegerTracer       *monitoring.JaegerTracer
+	OutboxRelay        *outbox.Relay
+	ProductConsumer    *consumers.ProductConsumer
 }
 
 // NewApp creates a new App instance
@@ -52,18 +57,24 @@ func NewApp(
 	httpRouter *gin.Engine,
 	grpcServer *grpc.Server,
 	productService *application.ProductServiceCQRS,
+	reservationService *application.ReservationService,
 	userService *application.UserService,
 	db *database.Database,
 	userClient interface{ Close() error },
 	jaegerTracer *monitoring.JaegerTracer,
+	outboxRelay *outbox.Relay,
+	productConsumer *consumers.ProductConsumer,
 ) *App {
 	return &App{
-		HTTPRouter:     httpRouter,
-		GRPCServer:     grpcServer,
-		ProductService: productService,
-		UserService:    userService,
-		Database:       db,
-		UserClient:     userClient,
-		JaegerTracer:   jaegerTracer,
+		HTTPRouter:         httpRouter,
+		GRPCServer:         grpcServer,
+		ProductService:     productService,
+		ReservationService: reservationService,
+		UserService:        userService,
+		Database:           db,
+		UserClient:         userClient,
+		JaegerTracer:       jaegerTracer,
+		OutboxRelay:        outboxRelay,
+		ProductConsumer:    productConsumer,
 	}
 }
@@ -8,13 +8,18 @@ package main
 
 import (
 	"github.com/ddd-micro/internal/product/application"
+	"github.com/ddd-micro/internal/product/infrastructure"
 	"github.com/ddd-micro/internal/product/infrastructure/client"
 	"github.com/ddd-micro/internal/product/infrastructure/config"
 	"github.com/ddd-micro/internal/product/infrastructure/database"
+	productkafka "github.com/ddd-micro/internal/product/infrastructure/kafka"
 	"github.com/ddd-micro/internal/product/infrastructure/monitoring"
 	"github.com/ddd-micro/internal/product/infrastructure/persistence"
 	productgrpc "github.com/ddd-micro/internal/product/interfaces/grpc"
 	producthttp "github.com/ddd-micro/internal/product/interfaces/http"
+	"github.com/ddd-micro/kafka/consumers"
+	"github.com/ddd-micro/pkg/gormtx"
+	"github.com/ddd-micro/pkg/outbox"
 	"github.com/gin-gonic/gin"
 	"google.golang.org/grpc"
 )
@@ -30,8 +35,35 @@ func InitializeApp() (*App, error) {
 		return nil, err
 	}
 
-	// Create product repository
+	// Create product, variant, category, stock reservation, stock ledger and warehouse repositories
 	productRepo := persistence.NewProductRepository(db.GetDB())
+	variantRepo := persistence.NewVariantRepository(db.GetDB())
+	categoryRepo := persistence.NewCategoryRepository(db.GetDB())
+	reservationRepo := persistence.NewStockReservationRepository(db.GetDB())
+	movementRepo := persistence.NewStockMovementRepository(db.GetDB())
+	warehouseRepo := persistence.NewWarehouseRepository(db.GetDB())
+	stockLevelRepo := persistence.NewStockLevelRepository(db.GetDB())
+	transactor := gormtx.NewTransactor(db.GetDB())
+
+	// Create Kafka publisher and the outbox relay feeding it
+	kafkaConfig := infrastructure.ProvideKafkaConfig()
+	eventPublisher, err := infrastructure.ProvideKafkaPublisher(kafkaConfig)
+	if err != nil {
+		return nil, err
+	}
+	outboxStore := outbox.NewStore(db.GetDB())
+	productEventPublisher := productkafka.NewProductEventPublisher(outboxStore)
+	outboxRelay := infrastructure.ProvideOutboxRelay(db.GetDB(), eventPublisher)
+
+	// Create reservation service holding stock for baskets and payments
+	reservationService := application.NewReservationService(productRepo, reservationRepo, productEventPublisher, transactor, cfg)
+
+	// Create Kafka consumer applying payment events to stock
+	eventConsumer, err := infrastructure.ProvideKafkaConsumer(kafkaConfig, db.GetDB(), transactor)
+	if err != nil {
+		return nil, err
+	}
+	productConsumer := consumers.NewProductConsumer(eventConsumer, productRepo, transactor, productEventPublisher, reservationService)
 
 	// Create user client
 	userClient, err := client.NewUserClientFromConfig(&cfg.Client)
@@ -40,8 +72,12 @@ func InitializeApp() (*App, error) {
 	}
 
 	// Create application services
-	productService := application.NewProductServiceCQRS(productRepo)
+	productService := application.NewProductServiceCQRS(productRepo, categoryRepo, productEventPublisher, transactor, cfg)
 	userService := application.NewUserService(userClient)
+	inventoryService := application.NewInventoryService(productRepo, movementRepo, stockLevelRepo, productEventPublisher, transactor)
+	warehouseService := application.NewWarehouseService(warehouseRepo, stockLevelRepo, transactor)
+	categoryService := application.NewCategoryService(categoryRepo, transactor)
+	variantService := application.NewVariantService(productRepo, variantRepo, productEventPublisher, transactor)
 
 	// Create monitoring components
 	prometheusMetrics := monitoring.NewPrometheusMetrics()
@@ -52,26 +88,33 @@ func InitializeApp() (*App, error) {
 
 	// Create HTTP handlers
 	productHandler := producthttp.NewProductHandler(productService, prometheusMetrics)
+	inventoryHandler := producthttp.NewInventoryHandler(inventoryService)
+	warehouseHandler := producthttp.NewWarehouseHandler(warehouseService)
+	categoryHandler := producthttp.NewCategoryHandler(categoryService)
+	variantHandler := producthttp.NewVariantHandler(variantService)
 	userHandler := producthttp.NewUserHandler(userService)
 	authMiddleware := producthttp.NewAuthMiddleware(userService)
 
 	// Create HTTP router
-	httpRouter := producthttp.NewHTTPRouter(productHandler, userHandler, authMiddleware, prometheusMetrics, jaegerTracer)
+	httpRouter := producthttp.NewHTTPRouter(productHandler, inventoryHandler, warehouseHandler, categoryHandler, variantHandler, userHandler, authMiddleware, prometheusMetrics, jaegerTracer)
 
 	// Create gRPC server
-	productServer := productgrpc.NewProductServer(productService)
+	productServer := productgrpc.NewProductServer(productService, reservationService, categoryService)
 	authInterceptor := productgrpc.NewAuthInterceptor(userService)
 	grpcServer := productgrpc.ProvideGRPCServer(productServer, authInterceptor)
 
 	// Create app
 	app := &App{
-		HTTPRouter:     httpRouter,
-		GRPCServer:     grpcServer,
-		ProductService: productService,
-		UserService:    userService,
-		Database:       db,
-		UserClient:     userClient,
-		JaegerTracer:   jaegerTracer,
+		HTTPRouter:         httpRouter,
+		GRPCServer:         grpcServer,
+		ProductService:     productService,
+		ReservationService: reservationService,
+		UserService:        userService,
+		Database:           db,
+		UserClient:         userClient,
+		JaegerTracer:       jaegerTracer,
+		OutboxRelay:        outboxRelay,
+		ProductConsumer:    productConsumer,
 	}
 
 	return app, nil
@@ -79,11 +122,14 @@ func InitializeApp() (*App, error) {
 
 // App holds all application dependencies
 type App struct {
-	HTTPRouter     *gin.Engine
-	GRPCServer     *grpc.Server
-	ProductService *application.ProductServiceCQRS
-	UserService    *application.UserService
-	Database       *database.Database
-	UserClient     interface{ Close() error }
-	JaegerTracer   *monitoring.JaegerTracer
+	HTTPRouter         *gin.Engine
+	GRPCServer         *grpc.Server
+	ProductService     *application.ProductServiceCQRS
+	ReservationService *application.ReservationService
+	UserService        *application.UserService
+	Database           *database.Database
+	UserClient         interface{ Close() error }
+	JaegerTracer       *monitoring.JaegerTracer
+	OutboxRelay        *outbox.Relay
+	ProductConsumer    *consumers.ProductConsumer
 }
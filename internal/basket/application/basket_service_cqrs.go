@@ -53,29 +53,31 @@ func (s *BasketServiceCQRS) AddItemHTTP(ctx context.Context, userID uint, req dt
 	cmd := command.AddItemCommand{
 		UserID:    userID,
 		ProductID: req.ProductID,
+		VariantID: req.VariantID,
 		Quantity:  req.Quantity,
-		UnitPrice: req.UnitPrice,
 	}
 
 	return s.addItemHandler.Handle(ctx, cmd)
 }
 
-// UpdateItem updates the quantity of an item in the basket (HTTP version)
-func (s *BasketServiceCQRS) UpdateItemHTTP(ctx context.Context, userID uint, productID uint, req dto.UpdateItemRequest) (*dto.BasketResponse, error) {
+// UpdateItem updates the quantity of an item, or a variant of it, in the basket (HTTP version)
+func (s *BasketServiceCQRS) UpdateItemHTTP(ctx context.Context, userID uint, productID uint, variantID *uint, req dto.UpdateItemRequest) (*dto.BasketResponse, error) {
 	cmd := command.UpdateItemCommand{
 		UserID:    userID,
 		ProductID: productID,
+		VariantID: variantID,
 		Quantity:  req.Quantity,
 	}
 
 	return s.updateItemHandler.Handle(ctx, cmd)
 }
 
-// RemoveItem removes an item from the basket (HTTP version)
-func (s *BasketServiceCQRS) RemoveItemHTTP(ctx context.Context, userID uint, productID uint) (*dto.BasketResponse, error) {
+// RemoveItem removes an item, or a variant of it, from the basket (HTTP version)
+func (s *BasketServiceCQRS) RemoveItemHTTP(ctx context.Context, userID uint, productID uint, variantID *uint) (*dto.BasketResponse, error) {
 	cmd := command.RemoveItemCommand{
 		UserID:    userID,
 		ProductID: productID,
+		VariantID: variantID,
 	}
 
 	return s.removeItemHandler.Handle(ctx, cmd)
@@ -115,18 +117,19 @@ func (s *BasketServiceCQRS) AddItem(ctx context.Context, req dto.AddItemRequest)
 	cmd := command.AddItemCommand{
 		UserID:    req.UserID,
 		ProductID: req.ProductID,
+		VariantID: req.VariantID,
 		Quantity:  req.Quantity,
-		UnitPrice: req.UnitPrice,
 	}
 
 	return s.addItemHandler.Handle(ctx, cmd)
 }
 
-// UpdateItem updates the quantity of an item in the basket (gRPC version)
-func (s *BasketServiceCQRS) UpdateItem(ctx context.Context, productID uint, req dto.UpdateItemRequest) (*dto.BasketResponse, error) {
+// UpdateItem updates the quantity of an item, or a variant of it, in the basket (gRPC version)
+func (s *BasketServiceCQRS) UpdateItem(ctx context.Context, productID uint, variantID *uint, req dto.UpdateItemRequest) (*dto.BasketResponse, error) {
 	cmd := command.UpdateItemCommand{
 		UserID:    req.UserID,
 		ProductID: productID,
+		VariantID: variantID,
 		Quantity:  req.Quantity,
 	}
 
@@ -138,6 +141,7 @@ func (s *BasketServiceCQRS) RemoveItem(ctx context.Context, req dto.RemoveItemRe
 	cmd := command.RemoveItemCommand{
 		UserID:    req.UserID,
 		ProductID: req.ProductID,
+		VariantID: req.VariantID,
 	}
 
 	return s.removeItemHandler.Handle(ctx, cmd)
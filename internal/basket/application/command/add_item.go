@@ -8,14 +8,15 @@ import (
 	"github.com/ddd-micro/internal/basket/application/dto"
 	"github.com/ddd-micro/internal/basket/domain"
 	"github.com/ddd-micro/internal/basket/infrastructure/client"
+	"github.com/ddd-micro/pkg/catalog"
 )
 
 // AddItemCommand represents the command to add an item to the basket
 type AddItemCommand struct {
 	UserID    uint
 	ProductID uint
+	VariantID *uint
 	Quantity  int
-	UnitPrice float64
 }
 
 // AddItemCommandHandler handles the AddItemCommand
@@ -34,26 +35,26 @@ func NewAddItemCommandHandler(basketRepo domain.BasketRepository, productClient
 
 // Handle handles the AddItemCommand
 func (h *AddItemCommandHandler) Handle(ctx context.Context, cmd AddItemCommand) (*dto.BasketResponse, error) {
-	// Validate product exists and is active
-	if err := h.productClient.ValidateProduct(ctx, cmd.ProductID); err != nil {
+	// Get the product once; the item is priced at its current price, or the effective
+	// price of the variant, never at a price chosen by the client
+	product, err := h.productClient.GetProduct(ctx, cmd.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	// Validate product, and the variant when given, is active
+	if err := client.ValidateProduct(product, cmd.VariantID); err != nil {
 		return nil, fmt.Errorf("product validation failed: %w", err)
 	}
 
 	// Check stock availability
-	if err := h.productClient.CheckStock(ctx, cmd.ProductID, cmd.Quantity); err != nil {
+	if err := client.CheckStock(product, cmd.VariantID, cmd.Quantity); err != nil {
 		return nil, fmt.Errorf("stock check failed: %w", err)
 	}
 
-	// Get product to get current price
-	product, err := h.productClient.GetProduct(ctx, cmd.ProductID)
+	unitPrice, err := catalog.ItemPrice(product, cmd.VariantID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get product: %w", err)
-	}
-
-	// Use current product price if not provided
-	unitPrice := cmd.UnitPrice
-	if unitPrice == 0 && product.Price != 0 {
-		unitPrice = float64(product.Price)
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidPrice, err)
 	}
 
 	// Get or create basket for user
@@ -64,7 +65,6 @@ func (h *AddItemCommandHandler) Handle(ctx context.Context, cmd AddItemCommand)
 			basket = &domain.Basket{
 				UserID: cmd.UserID,
 				Items:  []domain.BasketItem{},
-				Total:  0,
 			}
 			basket.SetExpiration(24 * time.Hour)
 
@@ -83,11 +83,13 @@ func (h *AddItemCommandHandler) Handle(ctx context.Context, cmd AddItemCommand)
 
 	// Create basket item
 	item := &domain.BasketItem{
-		BasketID:   basket.ID,
-		ProductID:  cmd.ProductID,
-		Quantity:   cmd.Quantity,
-		UnitPrice:  unitPrice,
-		TotalPrice: float64(cmd.Quantity) * unitPrice,
+		BasketID:        basket.ID,
+		ProductID:       cmd.ProductID,
+		VariantID:       cmd.VariantID,
+		Quantity:        cmd.Quantity,
+		UnitPriceMinor:  unitPrice.Amount,
+		TotalPriceMinor: unitPrice.Multiply(int64(cmd.Quantity)).Amount,
+		Currency:        unitPrice.Currency,
 	}
 
 	// Validate item
@@ -115,25 +117,30 @@ func (h *AddItemCommandHandler) mapToResponse(basket *domain.Basket) *dto.Basket
 	items := make([]dto.BasketItemResponse, len(basket.Items))
 	for i, item := range basket.Items {
 		items[i] = dto.BasketItemResponse{
-			ID:         item.ID,
-			ProductID:  item.ProductID,
-			Quantity:   item.Quantity,
-			UnitPrice:  item.UnitPrice,
-			TotalPrice: item.TotalPrice,
-			CreatedAt:  item.CreatedAt,
-			UpdatedAt:  item.UpdatedAt,
+			ID:              item.ID,
+			ProductID:       item.ProductID,
+			VariantID:       item.VariantID,
+			Quantity:        item.Quantity,
+			UnitPrice:       item.UnitPrice().Major(),
+			TotalPrice:      item.TotalPrice().Major(),
+			UnitPriceMinor:  item.UnitPriceMinor,
+			TotalPriceMinor: item.TotalPriceMinor,
+			CreatedAt:       item.CreatedAt,
+			UpdatedAt:       item.UpdatedAt,
 		}
 	}
 
 	return &dto.BasketResponse{
-		ID:        basket.ID,
-		UserID:    basket.UserID,
-		Items:     items,
-		Total:     basket.Total,
-		ItemCount: basket.GetItemCount(),
-		CreatedAt: basket.CreatedAt,
-		UpdatedAt: basket.UpdatedAt,
-		ExpiresAt: basket.ExpiresAt,
-		IsExpired: basket.IsExpired(),
+		ID:         basket.ID,
+		UserID:     basket.UserID,
+		Items:      items,
+		Total:      basket.Total().Major(),
+		TotalMinor: basket.TotalMinor,
+		Currency:   basket.Currency,
+		ItemCount:  basket.GetItemCount(),
+		CreatedAt:  basket.CreatedAt,
+		UpdatedAt:  basket.UpdatedAt,
+		ExpiresAt:  basket.ExpiresAt,
+		IsExpired:  basket.IsExpired(),
 	}
 }
@@ -48,7 +48,6 @@ func (h *CreateBasketCommandHandler) Handle(ctx context.Context, cmd CreateBaske
 		ID:        uuid.New().String(),
 		UserID:    cmd.UserID,
 		Items:     []domain.BasketItem{},
-		Total:     0,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
@@ -75,25 +74,30 @@ func (h *CreateBasketCommandHandler) mapToResponse(basket *domain.Basket) *dto.B
 	items := make([]dto.BasketItemResponse, len(basket.Items))
 	for i, item := range basket.Items {
 		items[i] = dto.BasketItemResponse{
-			ID:         item.ID,
-			ProductID:  item.ProductID,
-			Quantity:   item.Quantity,
-			UnitPrice:  item.UnitPrice,
-			TotalPrice: item.TotalPrice,
-			CreatedAt:  item.CreatedAt,
-			UpdatedAt:  item.UpdatedAt,
+			ID:              item.ID,
+			ProductID:       item.ProductID,
+			VariantID:       item.VariantID,
+			Quantity:        item.Quantity,
+			UnitPrice:       item.UnitPrice().Major(),
+			TotalPrice:      item.TotalPrice().Major(),
+			UnitPriceMinor:  item.UnitPriceMinor,
+			TotalPriceMinor: item.TotalPriceMinor,
+			CreatedAt:       item.CreatedAt,
+			UpdatedAt:       item.UpdatedAt,
 		}
 	}
 
 	return &dto.BasketResponse{
-		ID:        basket.ID,
-		UserID:    basket.UserID,
-		Items:     items,
-		Total:     basket.Total,
-		ItemCount: basket.GetItemCount(),
-		CreatedAt: basket.CreatedAt,
-		UpdatedAt: basket.UpdatedAt,
-		ExpiresAt: basket.ExpiresAt,
-		IsExpired: basket.IsExpired(),
+		ID:         basket.ID,
+		UserID:     basket.UserID,
+		Items:      items,
+		Total:      basket.Total().Major(),
+		TotalMinor: basket.TotalMinor,
+		Currency:   basket.Currency,
+		ItemCount:  basket.GetItemCount(),
+		CreatedAt:  basket.CreatedAt,
+		UpdatedAt:  basket.UpdatedAt,
+		ExpiresAt:  basket.ExpiresAt,
+		IsExpired:  basket.IsExpired(),
 	}
 }
@@ -11,6 +11,7 @@ import (
 type UpdateItemCommand struct {
 	UserID    uint
 	ProductID uint
+	VariantID *uint
 	Quantity  int
 }
 
@@ -43,6 +44,7 @@ func (h *UpdateItemCommandHandler) Handle(ctx context.Context, cmd UpdateItemCom
 	item := &domain.BasketItem{
 		BasketID:  basket.ID,
 		ProductID: cmd.ProductID,
+		VariantID: cmd.VariantID,
 		Quantity:  cmd.Quantity,
 	}
 
@@ -66,25 +68,30 @@ func (h *UpdateItemCommandHandler) mapToResponse(basket *domain.Basket) *dto.Bas
 	items := make([]dto.BasketItemResponse, len(basket.Items))
 	for i, item := range basket.Items {
 		items[i] = dto.BasketItemResponse{
-			ID:         item.ID,
-			ProductID:  item.ProductID,
-			Quantity:   item.Quantity,
-			UnitPrice:  item.UnitPrice,
-			TotalPrice: item.TotalPrice,
-			CreatedAt:  item.CreatedAt,
-			UpdatedAt:  item.UpdatedAt,
+			ID:              item.ID,
+			ProductID:       item.ProductID,
+			VariantID:       item.VariantID,
+			Quantity:        item.Quantity,
+			UnitPrice:       item.UnitPrice().Major(),
+			TotalPrice:      item.TotalPrice().Major(),
+			UnitPriceMinor:  item.UnitPriceMinor,
+			TotalPriceMinor: item.TotalPriceMinor,
+			CreatedAt:       item.CreatedAt,
+			UpdatedAt:       item.UpdatedAt,
 		}
 	}
 
 	return &dto.BasketResponse{
-		ID:        basket.ID,
-		UserID:    basket.UserID,
-		Items:     items,
-		Total:     basket.Total,
-		ItemCount: basket.GetItemCount(),
-		CreatedAt: basket.CreatedAt,
-		UpdatedAt: basket.UpdatedAt,
-		ExpiresAt: basket.ExpiresAt,
-		IsExpired: basket.IsExpired(),
+		ID:         basket.ID,
+		UserID:     basket.UserID,
+		Items:      items,
+		Total:      basket.Total().Major(),
+		TotalMinor: basket.TotalMinor,
+		Currency:   basket.Currency,
+		ItemCount:  basket.GetItemCount(),
+		CreatedAt:  basket.CreatedAt,
+		UpdatedAt:  basket.UpdatedAt,
+		ExpiresAt:  basket.ExpiresAt,
+		IsExpired:  basket.IsExpired(),
 	}
 }
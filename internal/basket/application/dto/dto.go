@@ -9,10 +9,10 @@ type CreateBasketRequest struct {
 
 // AddItemRequest represents the request to add an item to the basket
 type AddItemRequest struct {
-	UserID    uint    `json:"user_id"`
-	ProductID uint    `json:"product_id" binding:"required"`
-	Quantity  int     `json:"quantity" binding:"required,min=1"`
-	UnitPrice float64 `json:"unit_price" binding:"required,min=0"`
+	UserID    uint  `json:"user_id"`
+	ProductID uint  `json:"product_id" binding:"required"`
+	VariantID *uint `json:"variant_id" binding:"omitempty,min=1"`
+	Quantity  int   `json:"quantity" binding:"required,min=1"`
 }
 
 // UpdateItemRequest represents the request to update an item quantity
@@ -23,26 +23,31 @@ type UpdateItemRequest struct {
 
 // BasketResponse represents the response for basket operations
 type BasketResponse struct {
-	ID        string               `json:"id"`
-	UserID    uint                 `json:"user_id"`
-	Items     []BasketItemResponse `json:"items"`
-	Total     float64              `json:"total"`
-	ItemCount int                  `json:"item_count"`
-	CreatedAt time.Time            `json:"created_at"`
-	UpdatedAt time.Time            `json:"updated_at"`
-	ExpiresAt time.Time            `json:"expires_at"`
-	IsExpired bool                 `json:"is_expired"`
+	ID         string               `json:"id"`
+	UserID     uint                 `json:"user_id"`
+	Items      []BasketItemResponse `json:"items"`
+	Total      float64              `json:"total"`
+	TotalMinor int64                `json:"total_minor"`
+	Currency   string               `json:"currency"`
+	ItemCount  int                  `json:"item_count"`
+	CreatedAt  time.Time            `json:"created_at"`
+	UpdatedAt  time.Time            `json:"updated_at"`
+	ExpiresAt  time.Time            `json:"expires_at"`
+	IsExpired  bool                 `json:"is_expired"`
 }
 
 // BasketItemResponse represents the response for basket item operations
 type BasketItemResponse struct {
-	ID         uint      `json:"id"`
-	ProductID  uint      `json:"product_id"`
-	Quantity   int       `json:"quantity"`
-	UnitPrice  float64   `json:"unit_price"`
-	TotalPrice float64   `json:"total_price"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID              uint      `json:"id"`
+	ProductID       uint      `json:"product_id"`
+	VariantID       *uint     `json:"variant_id,omitempty"`
+	Quantity        int       `json:"quantity"`
+	UnitPrice       float64   `json:"unit_price"`
+	TotalPrice      float64   `json:"total_price"`
+	UnitPriceMinor  int64     `json:"unit_price_minor"`
+	TotalPriceMinor int64     `json:"total_price_minor"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
 }
 
 // ListBasketsResponse represents the response for listing baskets
@@ -60,8 +65,9 @@ type ErrorResponse struct {
 
 // RemoveItemRequest represents the request to remove an item from the basket
 type RemoveItemRequest struct {
-	UserID    uint `json:"user_id"`
-	ProductID uint `json:"product_id" binding:"required"`
+	UserID    uint  `json:"user_id"`
+	ProductID uint  `json:"product_id" binding:"required"`
+	VariantID *uint `json:"variant_id" binding:"omitempty,min=1"`
 }
 
 // ClearBasketRequest represents the request to clear the basket
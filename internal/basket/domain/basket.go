@@ -2,29 +2,37 @@ package domain
 
 import (
 	"time"
+
+	"github.com/ddd-micro/pkg/money"
 )
 
+// DefaultCurrency is the currency assumed for baskets stored before currencies were tracked
+const DefaultCurrency = "USD"
+
 // Basket represents a shopping basket/cart
 type Basket struct {
-	ID        string       `json:"id" gorm:"primaryKey;type:varchar(36)"`
-	UserID    uint         `json:"user_id" gorm:"not null;index"`
-	Items     []BasketItem `json:"items" gorm:"foreignKey:BasketID;constraint:OnDelete:CASCADE"`
-	Total     float64      `json:"total" gorm:"type:decimal(10,2);default:0"`
-	CreatedAt time.Time    `json:"created_at"`
-	UpdatedAt time.Time    `json:"updated_at"`
-	ExpiresAt time.Time    `json:"expires_at" gorm:"index"`
+	ID         string       `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID     uint         `json:"user_id" gorm:"not null;index"`
+	Items      []BasketItem `json:"items" gorm:"foreignKey:BasketID;constraint:OnDelete:CASCADE"`
+	TotalMinor int64        `json:"total_minor" gorm:"not null;default:0"` // Total in minor units of Currency
+	Currency   string       `json:"currency" gorm:"size:3"`
+	CreatedAt  time.Time    `json:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+	ExpiresAt  time.Time    `json:"expires_at" gorm:"index"`
 }
 
 // BasketItem represents an item in the basket
 type BasketItem struct {
-	ID         uint      `json:"id" gorm:"primaryKey;autoIncrement"`
-	BasketID   string    `json:"basket_id" gorm:"not null;index;type:varchar(36)"`
-	ProductID  uint      `json:"product_id" gorm:"not null;index"`
-	Quantity   int       `json:"quantity" gorm:"not null;default:1"`
-	UnitPrice  float64   `json:"unit_price" gorm:"type:decimal(10,2);not null"`
-	TotalPrice float64   `json:"total_price" gorm:"type:decimal(10,2);not null"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID              uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	BasketID        string    `json:"basket_id" gorm:"not null;index;type:varchar(36)"`
+	ProductID       uint      `json:"product_id" gorm:"not null;index"`
+	VariantID       *uint     `json:"variant_id,omitempty" gorm:"index"` // Variant of the product, nil for the product itself
+	Quantity        int       `json:"quantity" gorm:"not null;default:1"`
+	UnitPriceMinor  int64     `json:"unit_price_minor" gorm:"not null"`  // Unit price in minor units of Currency
+	TotalPriceMinor int64     `json:"total_price_minor" gorm:"not null"` // Total price in minor units of Currency
+	Currency        string    `json:"currency" gorm:"size:3"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
 }
 
 // TableName returns the table name for Basket
@@ -37,44 +45,71 @@ func (BasketItem) TableName() string {
 	return "basket_items"
 }
 
+// Total returns the total price of the basket
+func (b *Basket) Total() money.Money {
+	return money.New(b.TotalMinor, b.Currency)
+}
+
+// UnitPrice returns the price of a single unit of the item
+func (bi *BasketItem) UnitPrice() money.Money {
+	return money.New(bi.UnitPriceMinor, bi.Currency)
+}
+
+// TotalPrice returns the price of all units of the item
+func (bi *BasketItem) TotalPrice() money.Money {
+	return money.New(bi.TotalPriceMinor, bi.Currency)
+}
+
 // CalculateTotal calculates the total price of the basket
 func (b *Basket) CalculateTotal() {
-	total := 0.0
+	var total int64
 	for _, item := range b.Items {
-		total += item.TotalPrice
+		total += item.TotalPriceMinor
 	}
-	b.Total = total
+	b.TotalMinor = total
 }
 
-// AddItem adds an item to the basket or updates quantity if exists
-func (b *Basket) AddItem(productID uint, quantity int, unitPrice float64) {
+// AddItem adds an item to the basket or updates quantity if exists. Each variant of a
+// product is an item of its own. An empty basket takes the currency of its first item;
+// later items must use the same one.
+func (b *Basket) AddItem(productID uint, variantID *uint, quantity int, unitPrice money.Money) error {
+	if b.IsEmpty() {
+		b.Currency = unitPrice.Currency
+	}
+	if !unitPrice.SameCurrency(b.Total()) {
+		return ErrCurrencyMismatch
+	}
+
 	// Check if item already exists
 	for i, item := range b.Items {
-		if item.ProductID == productID {
+		if item.Is(productID, variantID) {
 			// Update existing item
 			b.Items[i].Quantity += quantity
-			b.Items[i].TotalPrice = float64(b.Items[i].Quantity) * b.Items[i].UnitPrice
+			b.Items[i].TotalPriceMinor = b.Items[i].UnitPrice().Multiply(int64(b.Items[i].Quantity)).Amount
 			b.CalculateTotal()
-			return
+			return nil
 		}
 	}
 
 	// Add new item
 	newItem := BasketItem{
-		BasketID:   b.ID,
-		ProductID:  productID,
-		Quantity:   quantity,
-		UnitPrice:  unitPrice,
-		TotalPrice: float64(quantity) * unitPrice,
+		BasketID:        b.ID,
+		ProductID:       productID,
+		VariantID:       variantID,
+		Quantity:        quantity,
+		UnitPriceMinor:  unitPrice.Amount,
+		TotalPriceMinor: unitPrice.Multiply(int64(quantity)).Amount,
+		Currency:        unitPrice.Currency,
 	}
 	b.Items = append(b.Items, newItem)
 	b.CalculateTotal()
+	return nil
 }
 
 // RemoveItem removes an item from the basket
-func (b *Basket) RemoveItem(productID uint) {
+func (b *Basket) RemoveItem(productID uint, variantID *uint) {
 	for i, item := range b.Items {
-		if item.ProductID == productID {
+		if item.Is(productID, variantID) {
 			// Remove item from slice
 			b.Items = append(b.Items[:i], b.Items[i+1:]...)
 			b.CalculateTotal()
@@ -84,15 +119,15 @@ func (b *Basket) RemoveItem(productID uint) {
 }
 
 // UpdateItemQuantity updates the quantity of an item
-func (b *Basket) UpdateItemQuantity(productID uint, quantity int) error {
+func (b *Basket) UpdateItemQuantity(productID uint, variantID *uint, quantity int) error {
 	if quantity <= 0 {
 		return ErrInvalidQuantity
 	}
 
 	for i, item := range b.Items {
-		if item.ProductID == productID {
+		if item.Is(productID, variantID) {
 			b.Items[i].Quantity = quantity
-			b.Items[i].TotalPrice = float64(quantity) * b.Items[i].UnitPrice
+			b.Items[i].TotalPriceMinor = b.Items[i].UnitPrice().Multiply(int64(quantity)).Amount
 			b.CalculateTotal()
 			return nil
 		}
@@ -104,7 +139,7 @@ func (b *Basket) UpdateItemQuantity(productID uint, quantity int) error {
 // Clear removes all items from the basket
 func (b *Basket) Clear() {
 	b.Items = []BasketItem{}
-	b.Total = 0
+	b.TotalMinor = 0
 }
 
 // IsEmpty checks if the basket is empty
@@ -131,16 +166,27 @@ func (b *Basket) SetExpiration(duration time.Duration) {
 	b.ExpiresAt = time.Now().Add(duration)
 }
 
-// GetItemByProductID finds an item by product ID
-func (b *Basket) GetItemByProductID(productID uint) *BasketItem {
+// GetItemByProductID finds an item by product ID and variant ID
+func (b *Basket) GetItemByProductID(productID uint, variantID *uint) *BasketItem {
 	for _, item := range b.Items {
-		if item.ProductID == productID {
+		if item.Is(productID, variantID) {
 			return &item
 		}
 	}
 	return nil
 }
 
+// Is reports whether the item holds the given product, or the given variant of it
+func (bi *BasketItem) Is(productID uint, variantID *uint) bool {
+	if bi.ProductID != productID {
+		return false
+	}
+	if bi.VariantID == nil || variantID == nil {
+		return bi.VariantID == nil && variantID == nil
+	}
+	return *bi.VariantID == *variantID
+}
+
 // Validate validates the basket
 func (b *Basket) Validate() error {
 	if b.UserID == 0 {
@@ -166,14 +212,22 @@ func (bi *BasketItem) Validate() error {
 		return ErrInvalidProductID
 	}
 
+	if bi.VariantID != nil && *bi.VariantID == 0 {
+		return ErrInvalidVariantID
+	}
+
 	if bi.Quantity <= 0 {
 		return ErrInvalidQuantity
 	}
 
-	if bi.UnitPrice < 0 {
+	if bi.UnitPriceMinor < 0 {
 		return ErrInvalidPrice
 	}
 
+	if !money.IsValidCurrency(bi.Currency) {
+		return ErrInvalidCurrency
+	}
+
 	if bi.BasketID == "" {
 		return ErrInvalidBasketID
 	}
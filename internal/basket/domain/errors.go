@@ -13,9 +13,12 @@ var (
 	// BasketItem errors
 	ErrItemNotFound      = errors.New("item not found in basket")
 	ErrInvalidProductID  = errors.New("invalid product ID")
+	ErrInvalidVariantID  = errors.New("invalid variant ID")
 	ErrInvalidQuantity   = errors.New("invalid quantity")
 	ErrInvalidPrice      = errors.New("invalid price")
 	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrInvalidCurrency   = errors.New("invalid currency")
+	ErrCurrencyMismatch  = errors.New("item currency does not match basket currency")
 
 	// General errors
 	ErrInvalidOperation   = errors.New("invalid operation")
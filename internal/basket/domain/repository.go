@@ -28,8 +28,8 @@ type BasketRepository interface {
 	// UpdateItem updates a basket item
 	UpdateItem(ctx context.Context, basketID string, item *BasketItem) error
 
-	// RemoveItem removes an item from the basket
-	RemoveItem(ctx context.Context, basketID string, productID uint) error
+	// RemoveItem removes an item, or a variant of it, from the basket
+	RemoveItem(ctx context.Context, basketID string, productID uint, variantID *uint) error
 
 	// ClearItems removes all items from the basket
 	ClearItems(ctx context.Context, basketID string) error
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	productpb "github.com/ddd-micro/api/proto/product"
+	"github.com/ddd-micro/pkg/catalog"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -12,8 +13,6 @@ import (
 // ProductClient interface for product service operations
 type ProductClient interface {
 	GetProduct(ctx context.Context, productID uint) (*productpb.Product, error)
-	ValidateProduct(ctx context.Context, productID uint) error
-	CheckStock(ctx context.Context, productID uint, quantity int) error
 	Close() error
 }
 
@@ -54,47 +53,40 @@ func (c *productClient) GetProduct(ctx context.Context, productID uint) (*produc
 	return resp.Product, nil
 }
 
-// ValidateProduct validates if a product exists and is active
-func (c *productClient) ValidateProduct(ctx context.Context, productID uint) error {
-	req := &productpb.GetProductRequest{
-		Id: uint32(productID),
+// ValidateProduct checks that a product, and the variant when given, is active
+func ValidateProduct(product *productpb.Product, variantID *uint) error {
+	if !product.IsActive {
+		return fmt.Errorf("product is not active")
 	}
 
-	resp, err := c.client.GetProduct(ctx, req)
-	if err != nil {
-		return fmt.Errorf("failed to validate product: %w", err)
+	if variantID == nil {
+		return nil
 	}
 
-	if resp.Product == nil {
-		return fmt.Errorf("product not found")
+	variant, err := catalog.Variant(product, *variantID)
+	if err != nil {
+		return err
 	}
-
-	// Check if product is active
-	if !resp.Product.IsActive {
-		return fmt.Errorf("product is not active")
+	if !variant.IsActive {
+		return fmt.Errorf("product variant is not active")
 	}
 
 	return nil
 }
 
-// CheckStock checks if there's enough stock for the requested quantity
-func (c *productClient) CheckStock(ctx context.Context, productID uint, quantity int) error {
-	req := &productpb.GetProductRequest{
-		Id: uint32(productID),
-	}
-
-	resp, err := c.client.GetProduct(ctx, req)
-	if err != nil {
-		return fmt.Errorf("failed to check stock: %w", err)
-	}
-
-	if resp.Product == nil {
-		return fmt.Errorf("product not found")
+// CheckStock checks if there's enough stock of the product, or of the variant when given,
+// that is not held by a reservation for the requested quantity
+func CheckStock(product *productpb.Product, variantID *uint, quantity int) error {
+	available := product.AvailableStock
+	if variantID != nil {
+		variant, err := catalog.Variant(product, *variantID)
+		if err != nil {
+			return err
+		}
+		available = variant.AvailableStock
 	}
-
-	// Check if there's enough stock
-	if resp.Product.Stock < int32(quantity) {
-		return fmt.Errorf("insufficient stock: requested %d, available %d", quantity, resp.Product.Stock)
+	if available < int32(quantity) {
+		return fmt.Errorf("insufficient stock: requested %d, available %d", quantity, available)
 	}
 
 	return nil
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/ddd-micro/internal/basket/domain"
+	"github.com/ddd-micro/pkg/money"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
@@ -78,13 +79,12 @@ func (r *BasketRepository) GetByID(ctx context.Context, basketID string) (*domai
 		return nil, fmt.Errorf("failed to get basket: %w", err)
 	}
 
-	var basket domain.Basket
-	err = json.Unmarshal([]byte(data), &basket)
+	basket, err := unmarshalBasket([]byte(data))
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal basket: %w", err)
 	}
 
-	return &basket, nil
+	return basket, nil
 }
 
 // GetByUserID retrieves a basket by user ID
@@ -178,7 +178,9 @@ func (r *BasketRepository) AddItem(ctx context.Context, basketID string, item *d
 		return err
 	}
 
-	basket.AddItem(item.ProductID, item.Quantity, item.UnitPrice)
+	if err := basket.AddItem(item.ProductID, item.VariantID, item.Quantity, item.UnitPrice()); err != nil {
+		return err
+	}
 
 	return r.Update(ctx, basket)
 }
@@ -190,7 +192,7 @@ func (r *BasketRepository) UpdateItem(ctx context.Context, basketID string, item
 		return err
 	}
 
-	err = basket.UpdateItemQuantity(item.ProductID, item.Quantity)
+	err = basket.UpdateItemQuantity(item.ProductID, item.VariantID, item.Quantity)
 	if err != nil {
 		return err
 	}
@@ -198,14 +200,14 @@ func (r *BasketRepository) UpdateItem(ctx context.Context, basketID string, item
 	return r.Update(ctx, basket)
 }
 
-// RemoveItem removes an item from the basket
-func (r *BasketRepository) RemoveItem(ctx context.Context, basketID string, productID uint) error {
+// RemoveItem removes an item, or a variant of it, from the basket
+func (r *BasketRepository) RemoveItem(ctx context.Context, basketID string, productID uint, variantID *uint) error {
 	basket, err := r.GetByID(ctx, basketID)
 	if err != nil {
 		return err
 	}
 
-	basket.RemoveItem(productID)
+	basket.RemoveItem(productID, variantID)
 
 	return r.Update(ctx, basket)
 }
@@ -294,20 +296,60 @@ func (r *BasketRepository) GetExpiredBaskets(ctx context.Context) ([]*domain.Bas
 			continue // Skip if can't retrieve
 		}
 
-		var basket domain.Basket
-		err = json.Unmarshal([]byte(data), &basket)
+		basket, err := unmarshalBasket([]byte(data))
 		if err != nil {
 			continue // Skip if can't unmarshal
 		}
 
 		if basket.IsExpired() {
-			baskets = append(baskets, &basket)
+			baskets = append(baskets, basket)
 		}
 	}
 
 	return baskets, nil
 }
 
+// legacyBasket holds the decimal item prices of baskets stored before minor unit prices; totals are recalculated
+type legacyBasket struct {
+	Items []struct {
+		UnitPrice float64 `json:"unit_price"`
+	} `json:"items"`
+}
+
+// unmarshalBasket decodes a stored basket, converting the decimal prices of
+// baskets written before currencies were tracked
+func unmarshalBasket(data []byte) (*domain.Basket, error) {
+	var basket domain.Basket
+	if err := json.Unmarshal(data, &basket); err != nil {
+		return nil, err
+	}
+	if basket.Currency != "" {
+		return &basket, nil
+	}
+
+	var legacy legacyBasket
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return nil, err
+	}
+
+	basket.Currency = domain.DefaultCurrency
+	for i := range basket.Items {
+		if i >= len(legacy.Items) {
+			break
+		}
+		unitPrice, err := money.FromMajor(legacy.Items[i].UnitPrice, domain.DefaultCurrency)
+		if err != nil {
+			return nil, err
+		}
+		basket.Items[i].Currency = unitPrice.Currency
+		basket.Items[i].UnitPriceMinor = unitPrice.Amount
+		basket.Items[i].TotalPriceMinor = unitPrice.Multiply(int64(basket.Items[i].Quantity)).Amount
+	}
+	basket.CalculateTotal()
+
+	return &basket, nil
+}
+
 // Helper methods for Redis key generation
 func (r *BasketRepository) getBasketKey(basketID string) string {
 	return fmt.Sprintf("basket:%s", basketID)
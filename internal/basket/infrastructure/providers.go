@@ -9,6 +9,8 @@ import (
 	"github.com/ddd-micro/internal/basket/infrastructure/database"
 	"github.com/ddd-micro/internal/basket/infrastructure/monitoring"
 	"github.com/ddd-micro/internal/basket/infrastructure/persistence"
+	"github.com/ddd-micro/kafka"
+	"github.com/ddd-micro/kafka/consumers"
 	"github.com/google/wire"
 )
 
@@ -25,6 +27,10 @@ var ProviderSet = wire.NewSet(
 	NewUserClient,
 	NewProductClient,
 	NewBasketRepository,
+	ProvideKafkaConfig,
+	ProvideKafkaPublisher,
+	ProvideKafkaConsumer,
+	consumers.NewBasketConsumer,
 	monitoring.ProviderSet,
 )
 
@@ -104,3 +110,25 @@ func NewProductClient(cfg *config.Config) client.ProductClient {
 func NewBasketRepository(db *database.Database) domain.BasketRepository {
 	return persistence.NewBasketRepository(db.GetClient())
 }
+
+// ProvideKafkaConfig provides Kafka configuration for the basket service
+func ProvideKafkaConfig() *kafka.Config {
+	return kafka.LoadServiceConfig("basket-service")
+}
+
+// ProvideKafkaPublisher provides Kafka event publisher
+func ProvideKafkaPublisher(cfg *kafka.Config) (kafka.EventPublisher, error) {
+	return kafka.NewPublisher(cfg.GetPublisherConfig())
+}
+
+// ProvideKafkaConsumer provides Kafka event consumer that skips already processed events
+func ProvideKafkaConsumer(cfg *kafka.Config, db *database.Database) (kafka.EventConsumer, error) {
+	consumer, err := kafka.NewConsumer(cfg.GetConsumerConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	store := kafka.NewRedisProcessedEventStore(db.GetClient(), cfg.DedupRetention, cfg.DedupClaimTimeout)
+	consumer.Use(kafka.Dedup(cfg.GroupID, store))
+	return consumer, nil
+}
@@ -2,10 +2,12 @@ package grpc
 
 import (
 	"context"
+	"errors"
 
 	basketpb "github.com/ddd-micro/api/proto/basket"
 	"github.com/ddd-micro/internal/basket/application"
 	"github.com/ddd-micro/internal/basket/application/dto"
+	"github.com/ddd-micro/internal/basket/domain"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -60,8 +62,8 @@ func (s *BasketServer) AddItem(ctx context.Context, req *basketpb.AddItemRequest
 	appReq := dto.AddItemRequest{
 		UserID:    uint(req.UserId),
 		ProductID: uint(req.ProductId),
+		VariantID: uint32ToUintPtr(req.VariantId),
 		Quantity:  int(req.Quantity),
-		UnitPrice: req.UnitPrice,
 	}
 
 	basketResp, err := s.basketService.AddItem(ctx, appReq)
@@ -72,9 +74,12 @@ func (s *BasketServer) AddItem(ctx context.Context, req *basketpb.AddItemRequest
 		if err == application.ErrInvalidQuantity {
 			return nil, status.Errorf(codes.InvalidArgument, "invalid quantity")
 		}
-		if err == application.ErrInvalidPrice {
+		if err == application.ErrInvalidPrice || errors.Is(err, domain.ErrInvalidPrice) {
 			return nil, status.Errorf(codes.InvalidArgument, "invalid price")
 		}
+		if errors.Is(err, domain.ErrCurrencyMismatch) {
+			return nil, status.Errorf(codes.FailedPrecondition, "%v", err)
+		}
 		return nil, status.Errorf(codes.Internal, "failed to add item: %v", err)
 	}
 
@@ -88,7 +93,7 @@ func (s *BasketServer) UpdateItem(ctx context.Context, req *basketpb.UpdateItemR
 		Quantity: int(req.Quantity),
 	}
 
-	basketResp, err := s.basketService.UpdateItem(ctx, uint(req.ProductId), appReq)
+	basketResp, err := s.basketService.UpdateItem(ctx, uint(req.ProductId), uint32ToUintPtr(req.VariantId), appReq)
 	if err != nil {
 		if err == application.ErrBasketNotFound {
 			return nil, status.Errorf(codes.NotFound, "basket not found")
@@ -110,6 +115,7 @@ func (s *BasketServer) RemoveItem(ctx context.Context, req *basketpb.RemoveItemR
 	appReq := dto.RemoveItemRequest{
 		UserID:    uint(req.UserId),
 		ProductID: uint(req.ProductId),
+		VariantID: uint32ToUintPtr(req.VariantId),
 	}
 
 	basketResp, err := s.basketService.RemoveItem(ctx, appReq)
@@ -215,27 +221,50 @@ func toProtoBasket(basket *dto.BasketResponse) *basketpb.BasketResponse {
 	items := make([]*basketpb.BasketItem, len(basket.Items))
 	for i, item := range basket.Items {
 		items[i] = &basketpb.BasketItem{
-			Id:         uint32(item.ID),
-			ProductId:  uint32(item.ProductID),
-			Quantity:   int32(item.Quantity),
-			UnitPrice:  item.UnitPrice,
-			TotalPrice: item.TotalPrice,
-			CreatedAt:  timestamppb.New(item.CreatedAt),
-			UpdatedAt:  timestamppb.New(item.UpdatedAt),
+			Id:              uint32(item.ID),
+			ProductId:       uint32(item.ProductID),
+			VariantId:       uintToUint32Ptr(item.VariantID),
+			Quantity:        int32(item.Quantity),
+			UnitPrice:       item.UnitPrice,
+			TotalPrice:      item.TotalPrice,
+			UnitPriceMinor:  item.UnitPriceMinor,
+			TotalPriceMinor: item.TotalPriceMinor,
+			CreatedAt:       timestamppb.New(item.CreatedAt),
+			UpdatedAt:       timestamppb.New(item.UpdatedAt),
 		}
 	}
 
 	return &basketpb.BasketResponse{
-		Id:        basket.ID,
-		UserId:    uint32(basket.UserID),
-		Items:     items,
-		Total:     basket.Total,
-		ItemCount: int32(basket.ItemCount),
-		CreatedAt: timestamppb.New(basket.CreatedAt),
-		UpdatedAt: timestamppb.New(basket.UpdatedAt),
-		ExpiresAt: timestamppb.New(basket.ExpiresAt),
-		IsExpired: basket.IsExpired,
+		Id:         basket.ID,
+		UserId:     uint32(basket.UserID),
+		Items:      items,
+		Total:      basket.Total,
+		TotalMinor: basket.TotalMinor,
+		Currency:   basket.Currency,
+		ItemCount:  int32(basket.ItemCount),
+		CreatedAt:  timestamppb.New(basket.CreatedAt),
+		UpdatedAt:  timestamppb.New(basket.UpdatedAt),
+		ExpiresAt:  timestamppb.New(basket.ExpiresAt),
+		IsExpired:  basket.IsExpired,
+	}
+}
+
+// Helper function to convert *uint32 to *uint
+func uint32ToUintPtr(i *uint32) *uint {
+	if i == nil {
+		return nil
+	}
+	val := uint(*i)
+	return &val
+}
+
+// Helper function to convert *uint to *uint32
+func uintToUint32Ptr(i *uint) *uint32 {
+	if i == nil {
+		return nil
 	}
+	val := uint32(*i)
+	return &val
 }
 
 func requireAdmin(ctx context.Context) error {
@@ -228,6 +228,7 @@ func (h *BasketHandler) AddItem(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param product_id path int true "Product ID"
+// @Param variant_id query int false "Variant ID"
 // @Param request body dto.UpdateItemRequest true "Update item request"
 // @Success 200 {object} dto.BasketResponse
 // @Failure 400 {object} dto.ErrorResponse
@@ -245,6 +246,11 @@ func (h *BasketHandler) UpdateItem(c *gin.Context) {
 		return
 	}
 
+	variantID, ok := variantIDQuery(c)
+	if !ok {
+		return
+	}
+
 	var req dto.UpdateItemRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
@@ -266,7 +272,7 @@ func (h *BasketHandler) UpdateItem(c *gin.Context) {
 
 	req.UserID = userID.(uint)
 
-	basket, err := h.basketService.UpdateItemHTTP(c.Request.Context(), userID.(uint), uint(productID), req)
+	basket, err := h.basketService.UpdateItemHTTP(c.Request.Context(), userID.(uint), uint(productID), variantID, req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error:   "Internal Server Error",
@@ -286,6 +292,7 @@ func (h *BasketHandler) UpdateItem(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param product_id path int true "Product ID"
+// @Param variant_id query int false "Variant ID"
 // @Success 200 {object} dto.BasketResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
@@ -302,6 +309,11 @@ func (h *BasketHandler) RemoveItem(c *gin.Context) {
 		return
 	}
 
+	variantID, ok := variantIDQuery(c)
+	if !ok {
+		return
+	}
+
 	// Get user ID from context (set by auth middleware)
 	userID, exists := c.Get("userID")
 	if !exists {
@@ -312,7 +324,7 @@ func (h *BasketHandler) RemoveItem(c *gin.Context) {
 		return
 	}
 
-	basket, err := h.basketService.RemoveItemHTTP(c.Request.Context(), userID.(uint), uint(productID))
+	basket, err := h.basketService.RemoveItemHTTP(c.Request.Context(), userID.(uint), uint(productID), variantID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error:   "Internal Server Error",
@@ -472,3 +484,23 @@ func (h *BasketHandler) AdminCleanupExpiredBaskets(c *gin.Context) {
 		Message: "Expired baskets cleaned up successfully",
 	})
 }
+
+// variantIDQuery parses the optional variant ID query parameter, responding with 400 when invalid
+func variantIDQuery(c *gin.Context) (*uint, bool) {
+	value := c.Query("variant_id")
+	if value == "" {
+		return nil, true
+	}
+
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Bad Request",
+			Message: "Invalid variant ID",
+		})
+		return nil, false
+	}
+
+	variantID := uint(id)
+	return &variantID, true
+}
@@ -16,13 +16,6 @@ func SetupRoutes(router *gin.Engine, basketHandler *BasketHandler, userHandler *
 
 	// Prometheus metrics endpoint
 	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
-	// Health check endpoint (public)
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status":  "healthy",
-			"service": "basket-service",
-		})
-	})
 
 	// Swagger documentation endpoint (public)
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
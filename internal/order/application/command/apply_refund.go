@@ -0,0 +1,47 @@
+package command
+
+import (
+	"context"
+
+	"github.com/ddd-micro/internal/order/domain"
+	"github.com/ddd-micro/pkg/money"
+)
+
+// ApplyRefundCommand represents the command to record a refund of the payment of an order
+type ApplyRefundCommand struct {
+	OrderID       string
+	PaymentID     string
+	TotalRefunded money.Money
+	Reason        string
+}
+
+// ApplyRefundCommandHandler handles the apply refund command
+type ApplyRefundCommandHandler struct {
+	orderRepo domain.OrderRepository
+}
+
+// NewApplyRefundCommandHandler creates a new apply refund command handler
+func NewApplyRefundCommandHandler(orderRepo domain.OrderRepository) *ApplyRefundCommandHandler {
+	return &ApplyRefundCommandHandler{
+		orderRepo: orderRepo,
+	}
+}
+
+// Handle handles the apply refund command; ctx must carry a transaction
+func (h *ApplyRefundCommandHandler) Handle(ctx context.Context, cmd ApplyRefundCommand) (*domain.Order, error) {
+	order, err := h.orderRepo.GetByIDForUpdate(ctx, cmd.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	change := domain.StatusChange{Actor: domain.PaymentActor(cmd.PaymentID), Reason: cmd.Reason}
+	if err := order.ApplyRefund(cmd.PaymentID, cmd.TotalRefunded, change); err != nil {
+		return nil, err
+	}
+
+	if err := h.orderRepo.Update(ctx, order); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
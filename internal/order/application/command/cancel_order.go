@@ -0,0 +1,51 @@
+package command
+
+import (
+	"context"
+
+	"github.com/ddd-micro/internal/order/domain"
+)
+
+// CancelOrderCommand represents the command to cancel an order
+type CancelOrderCommand struct {
+	OrderID string
+	// UserID restricts the command to the orders of a user; nil for admins
+	UserID *uint
+	Reason string
+	Actor  string
+}
+
+// CancelOrderCommandHandler handles the cancel order command
+type CancelOrderCommandHandler struct {
+	orderRepo domain.OrderRepository
+}
+
+// NewCancelOrderCommandHandler creates a new cancel order command handler
+func NewCancelOrderCommandHandler(orderRepo domain.OrderRepository) *CancelOrderCommandHandler {
+	return &CancelOrderCommandHandler{
+		orderRepo: orderRepo,
+	}
+}
+
+// Handle handles the cancel order command; ctx must carry a transaction
+func (h *CancelOrderCommandHandler) Handle(ctx context.Context, cmd CancelOrderCommand) (*domain.Order, error) {
+	order, err := h.orderRepo.GetByIDForUpdate(ctx, cmd.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if user owns the order
+	if cmd.UserID != nil && order.UserID != *cmd.UserID {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	if err := order.Cancel(cmd.Reason, domain.StatusChange{Actor: cmd.Actor, Reason: cmd.Reason}); err != nil {
+		return nil, err
+	}
+
+	if err := h.orderRepo.Update(ctx, order); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
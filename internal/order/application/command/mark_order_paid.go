@@ -0,0 +1,46 @@
+package command
+
+import (
+	"context"
+
+	"github.com/ddd-micro/internal/order/domain"
+	"github.com/ddd-micro/pkg/money"
+)
+
+// MarkOrderPaidCommand represents the command to record the payment of an order
+type MarkOrderPaidCommand struct {
+	OrderID   string
+	PaymentID string
+	Amount    money.Money
+}
+
+// MarkOrderPaidCommandHandler handles the mark order paid command
+type MarkOrderPaidCommandHandler struct {
+	orderRepo domain.OrderRepository
+}
+
+// NewMarkOrderPaidCommandHandler creates a new mark order paid command handler
+func NewMarkOrderPaidCommandHandler(orderRepo domain.OrderRepository) *MarkOrderPaidCommandHandler {
+	return &MarkOrderPaidCommandHandler{
+		orderRepo: orderRepo,
+	}
+}
+
+// Handle handles the mark order paid command; ctx must carry a transaction
+func (h *MarkOrderPaidCommandHandler) Handle(ctx context.Context, cmd MarkOrderPaidCommand) (*domain.Order, error) {
+	order, err := h.orderRepo.GetByIDForUpdate(ctx, cmd.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	change := domain.StatusChange{Actor: domain.PaymentActor(cmd.PaymentID), Reason: "payment completed"}
+	if err := order.MarkPaid(cmd.PaymentID, cmd.Amount, change); err != nil {
+		return nil, err
+	}
+
+	if err := h.orderRepo.Update(ctx, order); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
@@ -0,0 +1,45 @@
+package command
+
+import (
+	"context"
+
+	"github.com/ddd-micro/internal/order/domain"
+)
+
+// MarkPaymentFailedCommand represents the command to record a failed or cancelled payment of an order
+type MarkPaymentFailedCommand struct {
+	OrderID   string
+	PaymentID string
+	Reason    string
+}
+
+// MarkPaymentFailedCommandHandler handles the mark payment failed command
+type MarkPaymentFailedCommandHandler struct {
+	orderRepo domain.OrderRepository
+}
+
+// NewMarkPaymentFailedCommandHandler creates a new mark payment failed command handler
+func NewMarkPaymentFailedCommandHandler(orderRepo domain.OrderRepository) *MarkPaymentFailedCommandHandler {
+	return &MarkPaymentFailedCommandHandler{
+		orderRepo: orderRepo,
+	}
+}
+
+// Handle handles the mark payment failed command; ctx must carry a transaction
+func (h *MarkPaymentFailedCommandHandler) Handle(ctx context.Context, cmd MarkPaymentFailedCommand) (*domain.Order, error) {
+	order, err := h.orderRepo.GetByIDForUpdate(ctx, cmd.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	change := domain.StatusChange{Actor: domain.PaymentActor(cmd.PaymentID), Reason: cmd.Reason}
+	if err := order.MarkPaymentFailed(cmd.Reason, change); err != nil {
+		return nil, err
+	}
+
+	if err := h.orderRepo.Update(ctx, order); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
@@ -0,0 +1,46 @@
+package command
+
+import (
+	"context"
+
+	"github.com/ddd-micro/internal/order/domain"
+	"github.com/google/uuid"
+)
+
+// PlaceOrderCommand represents the command to place an order
+type PlaceOrderCommand struct {
+	UserID   uint
+	BasketID string
+	Currency string
+	Items    []domain.OrderItem
+	Shipping domain.Address
+	Billing  domain.Address
+}
+
+// PlaceOrderCommandHandler handles the place order command
+type PlaceOrderCommandHandler struct {
+	orderRepo domain.OrderRepository
+}
+
+// NewPlaceOrderCommandHandler creates a new place order command handler
+func NewPlaceOrderCommandHandler(orderRepo domain.OrderRepository) *PlaceOrderCommandHandler {
+	return &PlaceOrderCommandHandler{
+		orderRepo: orderRepo,
+	}
+}
+
+// Handle handles the place order command
+func (h *PlaceOrderCommandHandler) Handle(ctx context.Context, cmd PlaceOrderCommand) (*domain.Order, error) {
+	// Create pending order from the snapshotted items
+	order, err := domain.NewOrder(uuid.New().String(), cmd.UserID, cmd.BasketID, cmd.Currency, cmd.Items, cmd.Shipping, cmd.Billing)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save order to repository
+	if err := h.orderRepo.Create(ctx, order); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
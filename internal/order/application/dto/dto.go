@@ -0,0 +1,110 @@
+package dto
+
+import "time"
+
+// Order DTOs
+
+// Address represents a postal address of an order
+type Address struct {
+	Name    string `json:"name" binding:"required,max=100"`
+	Address string `json:"address" binding:"required,max=255"`
+	City    string `json:"city" binding:"required,max=100"`
+	State   string `json:"state,omitempty" binding:"max=100"`
+	ZipCode string `json:"zip_code,omitempty" binding:"max=20"`
+	Country string `json:"country" binding:"required,max=100"`
+	Phone   string `json:"phone,omitempty" binding:"max=30"`
+}
+
+// PlaceOrderRequest represents the request to order the items of the user's basket
+type PlaceOrderRequest struct {
+	ShippingAddress Address `json:"shipping_address" binding:"required"`
+	// Optional: defaults to the shipping address
+	BillingAddress *Address `json:"billing_address,omitempty"`
+}
+
+// CancelOrderRequest represents the request to cancel an order
+type CancelOrderRequest struct {
+	Reason string `json:"reason,omitempty" binding:"max=500"`
+}
+
+// ListOrdersRequest represents the request to list the orders of a user
+type ListOrdersRequest struct {
+	UserID uint   `json:"user_id"`
+	Page   int    `json:"page" form:"page"`
+	Limit  int    `json:"limit" form:"limit"`
+	Status string `json:"status" form:"status"`
+}
+
+// AdminListOrdersRequest represents the request to list all orders
+type AdminListOrdersRequest struct {
+	Page   int    `json:"page" form:"page"`
+	Limit  int    `json:"limit" form:"limit"`
+	UserID *uint  `json:"user_id" form:"user_id"`
+	Status string `json:"status" form:"status"`
+}
+
+// OrderItemResponse represents a line of an order
+type OrderItemResponse struct {
+	ID              uint    `json:"id"`
+	ProductID       uint    `json:"product_id"`
+	VariantID       *uint   `json:"variant_id,omitempty"`
+	SKU             string  `json:"sku"`
+	Name            string  `json:"name"`
+	Quantity        int     `json:"quantity"`
+	UnitPrice       float64 `json:"unit_price"`
+	TotalPrice      float64 `json:"total_price"`
+	UnitPriceMinor  int64   `json:"unit_price_minor"`
+	TotalPriceMinor int64   `json:"total_price_minor"`
+}
+
+// OrderResponse represents the response for an order
+type OrderResponse struct {
+	ID                 string              `json:"id"`
+	UserID             uint                `json:"user_id"`
+	BasketID           string              `json:"basket_id,omitempty"`
+	Status             string              `json:"status"`
+	Items              []OrderItemResponse `json:"items"`
+	ItemCount          int                 `json:"item_count"`
+	Total              float64             `json:"total"`
+	TotalMinor         int64               `json:"total_minor"`
+	Currency           string              `json:"currency"`
+	ShippingAddress    Address             `json:"shipping_address"`
+	BillingAddress     Address             `json:"billing_address"`
+	PaymentID          *string             `json:"payment_id,omitempty"`
+	PaymentError       string              `json:"payment_error,omitempty"`
+	Refunded           float64             `json:"refunded"`
+	RefundedMinor      int64               `json:"refunded_minor"`
+	CancellationReason string              `json:"cancellation_reason,omitempty"`
+	CreatedAt          time.Time           `json:"created_at"`
+	UpdatedAt          time.Time           `json:"updated_at"`
+	PaidAt             *time.Time          `json:"paid_at,omitempty"`
+	CancelledAt        *time.Time          `json:"cancelled_at,omitempty"`
+}
+
+// OrderListResponse represents the response for order list
+type OrderListResponse struct {
+	Orders     []OrderResponse `json:"orders"`
+	Total      int             `json:"total"`
+	Page       int             `json:"page"`
+	Limit      int             `json:"limit"`
+	TotalPages int             `json:"total_pages"`
+	HasNext    bool            `json:"has_next"`
+	HasPrev    bool            `json:"has_prev"`
+}
+
+// OrderStatusHistoryResponse represents a single order status transition
+type OrderStatusHistoryResponse struct {
+	ID         uint      `json:"id"`
+	OrderID    string    `json:"order_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Actor      string    `json:"actor"`
+	Reason     string    `json:"reason,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// OrderStatusHistoryListResponse represents the status history of an order
+type OrderStatusHistoryListResponse struct {
+	OrderID string                       `json:"order_id"`
+	History []OrderStatusHistoryResponse `json:"history"`
+}
@@ -0,0 +1,9 @@
+package application
+
+import "errors"
+
+// Application layer errors
+var (
+	ErrBasketEmpty        = errors.New("basket is empty")
+	ErrProductUnavailable = errors.New("product is unavailable")
+)
@@ -0,0 +1,496 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/ddd-micro/internal/order/application/command"
+	"github.com/ddd-micro/internal/order/application/dto"
+	"github.com/ddd-micro/internal/order/application/query"
+	"github.com/ddd-micro/internal/order/domain"
+	"github.com/ddd-micro/internal/order/infrastructure/client"
+	orderkafka "github.com/ddd-micro/internal/order/infrastructure/kafka"
+	"github.com/ddd-micro/kafka"
+	"github.com/ddd-micro/pkg/catalog"
+	"github.com/ddd-micro/pkg/gormtx"
+	"github.com/ddd-micro/pkg/money"
+)
+
+// OrderServiceCQRS represents the main order service using CQRS pattern
+type OrderServiceCQRS struct {
+	// Command handlers
+	placeOrderHandler        *command.PlaceOrderCommandHandler
+	cancelOrderHandler       *command.CancelOrderCommandHandler
+	markOrderPaidHandler     *command.MarkOrderPaidCommandHandler
+	markPaymentFailedHandler *command.MarkPaymentFailedCommandHandler
+	applyRefundHandler       *command.ApplyRefundCommandHandler
+
+	// Query handlers
+	getOrderHandler        *query.GetOrderQueryHandler
+	listOrdersHandler      *query.ListOrdersQueryHandler
+	getOrderHistoryHandler *query.GetOrderHistoryQueryHandler
+
+	// External service clients
+	productClient client.ProductClient
+	basketClient  client.BasketClient
+
+	// Kafka event publisher, writing to the outbox of the current transaction
+	eventPublisher *orderkafka.OrderEventPublisher
+	transactor     *gormtx.Transactor
+}
+
+// NewOrderServiceCQRS creates a new OrderServiceCQRS
+func NewOrderServiceCQRS(
+	placeOrderHandler *command.PlaceOrderCommandHandler,
+	cancelOrderHandler *command.CancelOrderCommandHandler,
+	markOrderPaidHandler *command.MarkOrderPaidCommandHandler,
+	markPaymentFailedHandler *command.MarkPaymentFailedCommandHandler,
+	applyRefundHandler *command.ApplyRefundCommandHandler,
+	getOrderHandler *query.GetOrderQueryHandler,
+	listOrdersHandler *query.ListOrdersQueryHandler,
+	getOrderHistoryHandler *query.GetOrderHistoryQueryHandler,
+	productClient client.ProductClient,
+	basketClient client.BasketClient,
+	eventPublisher *orderkafka.OrderEventPublisher,
+	transactor *gormtx.Transactor,
+) *OrderServiceCQRS {
+	return &OrderServiceCQRS{
+		placeOrderHandler:        placeOrderHandler,
+		cancelOrderHandler:       cancelOrderHandler,
+		markOrderPaidHandler:     markOrderPaidHandler,
+		markPaymentFailedHandler: markPaymentFailedHandler,
+		applyRefundHandler:       applyRefundHandler,
+		getOrderHandler:          getOrderHandler,
+		listOrdersHandler:        listOrdersHandler,
+		getOrderHistoryHandler:   getOrderHistoryHandler,
+		productClient:            productClient,
+		basketClient:             basketClient,
+		eventPublisher:           eventPublisher,
+		transactor:               transactor,
+	}
+}
+
+// Customer operations
+
+// PlaceOrder places a pending order for the items of the user's basket
+func (s *OrderServiceCQRS) PlaceOrder(ctx context.Context, userID uint, req dto.PlaceOrderRequest) (*dto.OrderResponse, error) {
+	basket, err := s.basketClient.GetBasket(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(basket.Items) == 0 {
+		return nil, ErrBasketEmpty
+	}
+
+	// Snapshot the items at the current product prices, as the checkout of the order's
+	// payment does, so later product and basket changes leave the order untouched
+	currency := client.BasketCurrency(basket)
+	items := make([]domain.OrderItem, 0, len(basket.Items))
+	for _, item := range basket.Items {
+		product, err := s.productClient.GetProduct(ctx, uint(item.ProductId))
+		if err != nil {
+			return nil, err
+		}
+		if !product.IsActive {
+			return nil, fmt.Errorf("%w: %s", ErrProductUnavailable, product.Name)
+		}
+
+		// A variant is snapshotted with its own SKU and name
+		sku, name := product.Sku, product.Name
+		variantID := client.BasketItemVariantID(item)
+		if variantID != nil {
+			variant, err := catalog.Variant(product, *variantID)
+			if err != nil || !variant.IsActive {
+				return nil, fmt.Errorf("%w: %s", ErrProductUnavailable, product.Name)
+			}
+			sku, name = variant.Sku, product.Name+" - "+variant.Name
+		}
+
+		unitPrice, err := catalog.ItemPrice(product, variantID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrProductUnavailable, product.Name, err)
+		}
+		if unitPrice.Currency != currency {
+			return nil, fmt.Errorf("%w: %s is not priced in %s", ErrProductUnavailable, product.Name, currency)
+		}
+
+		items = append(items, domain.OrderItem{
+			ProductID:      uint(item.ProductId),
+			VariantID:      variantID,
+			SKU:            sku,
+			Name:           name,
+			Quantity:       int(item.Quantity),
+			UnitPriceMinor: unitPrice.Amount,
+		})
+	}
+
+	// Billing defaults to the shipping address
+	shipping := toDomainAddress(req.ShippingAddress)
+	billing := shipping
+	if req.BillingAddress != nil {
+		billing = toDomainAddress(*req.BillingAddress)
+	}
+
+	cmd := command.PlaceOrderCommand{
+		UserID:   userID,
+		BasketID: basket.Id,
+		Currency: currency,
+		Items:    items,
+		Shipping: shipping,
+		Billing:  billing,
+	}
+
+	var order *domain.Order
+	err = s.transactor.Within(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.placeOrderHandler.Handle(ctx, cmd)
+		if err != nil {
+			return err
+		}
+
+		// The event commits together with the order so it cannot be lost
+		return s.publishOrderCreated(ctx, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toOrderResponse(order), nil
+}
+
+// GetOrder gets an order of the user by ID
+func (s *OrderServiceCQRS) GetOrder(ctx context.Context, userID uint, orderID string) (*dto.OrderResponse, error) {
+	order, err := s.getOrderHandler.Handle(ctx, query.GetOrderQuery{
+		OrderID: orderID,
+		UserID:  &userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toOrderResponse(order), nil
+}
+
+// ListOrders lists user's orders
+func (s *OrderServiceCQRS) ListOrders(ctx context.Context, req dto.ListOrdersRequest) (*dto.OrderListResponse, error) {
+	return s.listOrders(ctx, query.ListOrdersQuery{
+		Filter: domain.OrderFilter{UserID: &req.UserID, Status: req.Status},
+		Page:   req.Page,
+		Limit:  req.Limit,
+	})
+}
+
+// CancelOrder cancels an unpaid order of the user and its pending payments
+func (s *OrderServiceCQRS) CancelOrder(ctx context.Context, userID uint, orderID string, req dto.CancelOrderRequest) (*dto.OrderResponse, error) {
+	return s.cancelOrder(ctx, command.CancelOrderCommand{
+		OrderID: orderID,
+		UserID:  &userID,
+		Reason:  req.Reason,
+		Actor:   domain.UserActor(userID),
+	})
+}
+
+// Admin operations
+
+// AdminListOrders lists all orders (admin only)
+func (s *OrderServiceCQRS) AdminListOrders(ctx context.Context, req dto.AdminListOrdersRequest) (*dto.OrderListResponse, error) {
+	return s.listOrders(ctx, query.ListOrdersQuery{
+		Filter: domain.OrderFilter{UserID: req.UserID, Status: req.Status},
+		Page:   req.Page,
+		Limit:  req.Limit,
+	})
+}
+
+// AdminGetOrder gets any order by ID (admin only)
+func (s *OrderServiceCQRS) AdminGetOrder(ctx context.Context, orderID string) (*dto.OrderResponse, error) {
+	order, err := s.getOrderHandler.Handle(ctx, query.GetOrderQuery{OrderID: orderID})
+	if err != nil {
+		return nil, err
+	}
+
+	return toOrderResponse(order), nil
+}
+
+// AdminCancelOrder cancels any unpaid order and its pending payments (admin only)
+func (s *OrderServiceCQRS) AdminCancelOrder(ctx context.Context, adminID uint, orderID string, req dto.CancelOrderRequest) (*dto.OrderResponse, error) {
+	return s.cancelOrder(ctx, command.CancelOrderCommand{
+		OrderID: orderID,
+		Reason:  req.Reason,
+		Actor:   domain.AdminActor(adminID),
+	})
+}
+
+// AdminGetOrderHistory gets the status history of an order (admin only)
+func (s *OrderServiceCQRS) AdminGetOrderHistory(ctx context.Context, orderID string) (*dto.OrderStatusHistoryListResponse, error) {
+	history, err := s.getOrderHistoryHandler.Handle(ctx, query.GetOrderHistoryQuery{OrderID: orderID})
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to DTOs
+	entries := make([]dto.OrderStatusHistoryResponse, len(history))
+	for i, entry := range history {
+		entries[i] = dto.OrderStatusHistoryResponse{
+			ID:         entry.ID,
+			OrderID:    entry.OrderID,
+			FromStatus: string(entry.FromStatus),
+			ToStatus:   string(entry.ToStatus),
+			Actor:      entry.Actor,
+			Reason:     entry.Reason,
+			CreatedAt:  entry.CreatedAt,
+		}
+	}
+
+	return &dto.OrderStatusHistoryListResponse{
+		OrderID: orderID,
+		History: entries,
+	}, nil
+}
+
+// Payment event operations. Orders unknown to the service are ignored, since
+// clients that predate it still make up order IDs for their payments.
+
+// PaymentCompleted marks the order of a completed payment as paid. A payment the order
+// cannot take is rejected, so the payment service refunds it.
+func (s *OrderServiceCQRS) PaymentCompleted(ctx context.Context, orderID, paymentID string, amount money.Money) error {
+	err := s.transactor.Within(ctx, func(ctx context.Context) error {
+		_, err := s.markOrderPaidHandler.Handle(ctx, command.MarkOrderPaidCommand{
+			OrderID:   orderID,
+			PaymentID: paymentID,
+			Amount:    amount,
+		})
+		switch {
+		case errors.Is(err, domain.ErrPaymentAmountMismatch):
+			// Not retried, as a redelivery carries the same amount; the order records
+			// the failure so the customer can pay again
+			_, failErr := s.markPaymentFailedHandler.Handle(ctx, command.MarkPaymentFailedCommand{
+				OrderID:   orderID,
+				PaymentID: paymentID,
+				Reason:    err.Error(),
+			})
+			if failErr != nil && !errors.Is(failErr, domain.ErrInvalidStatusTransition) {
+				return failErr
+			}
+			return s.rejectPayment(ctx, orderID, paymentID, err)
+		case errors.Is(err, domain.ErrInvalidStatusTransition):
+			// The order was cancelled or paid by another payment in the meantime
+			return s.rejectPayment(ctx, orderID, paymentID, err)
+		}
+		return err
+	})
+	return ignoreUnknownOrder(orderID, err)
+}
+
+// PaymentFailed records a failed or cancelled payment of an order
+func (s *OrderServiceCQRS) PaymentFailed(ctx context.Context, orderID, paymentID, reason string) error {
+	err := s.transactor.Within(ctx, func(ctx context.Context) error {
+		_, err := s.markPaymentFailedHandler.Handle(ctx, command.MarkPaymentFailedCommand{
+			OrderID:   orderID,
+			PaymentID: paymentID,
+			Reason:    reason,
+		})
+		return err
+	})
+	if errors.Is(err, domain.ErrInvalidStatusTransition) {
+		// A failure of a payment that no longer matters, e.g. a retry of a paid order
+		log.Printf("Ignoring failed payment %s of order %s: %v", paymentID, orderID, err)
+		return nil
+	}
+	return ignoreUnknownOrder(orderID, err)
+}
+
+// PaymentRefunded records a refund of the payment of an order
+func (s *OrderServiceCQRS) PaymentRefunded(ctx context.Context, orderID, paymentID string, totalRefunded money.Money, reason string) error {
+	err := s.transactor.Within(ctx, func(ctx context.Context) error {
+		_, err := s.applyRefundHandler.Handle(ctx, command.ApplyRefundCommand{
+			OrderID:       orderID,
+			PaymentID:     paymentID,
+			TotalRefunded: totalRefunded,
+			Reason:        reason,
+		})
+		return err
+	})
+	if errors.Is(err, domain.ErrInvalidStatusTransition) || errors.Is(err, domain.ErrPaymentMismatch) {
+		log.Printf("Ignoring refund of payment %s for order %s: %v", paymentID, orderID, err)
+		return nil
+	}
+	return ignoreUnknownOrder(orderID, err)
+}
+
+// rejectPayment publishes the rejection of a completed payment the order cannot take
+func (s *OrderServiceCQRS) rejectPayment(ctx context.Context, orderID, paymentID string, reason error) error {
+	log.Printf("Order %s rejected payment %s, requesting a refund: %v", orderID, paymentID, reason)
+	return s.eventPublisher.PublishOrderPaymentRejected(ctx, kafka.OrderPaymentRejectedData{
+		OrderID:   orderID,
+		PaymentID: paymentID,
+		Reason:    reason.Error(),
+	})
+}
+
+// ignoreUnknownOrder drops the not found error of orders the service does not own
+func ignoreUnknownOrder(orderID string, err error) error {
+	if errors.Is(err, domain.ErrOrderNotFound) {
+		log.Printf("Ignoring payment event of unknown order %s", orderID)
+		return nil
+	}
+	return err
+}
+
+// cancelOrder cancels an order on behalf of the actor of the command
+func (s *OrderServiceCQRS) cancelOrder(ctx context.Context, cmd command.CancelOrderCommand) (*dto.OrderResponse, error) {
+	var order *domain.Order
+	err := s.transactor.Within(ctx, func(ctx context.Context) error {
+		var err error
+		order, err = s.cancelOrderHandler.Handle(ctx, cmd)
+		if err != nil {
+			return err
+		}
+
+		// The payment service cancels the pending payments of the order
+		return s.eventPublisher.PublishOrderCancelled(ctx, kafka.OrderCancelledData{
+			OrderID: order.ID,
+			UserID:  order.UserID,
+			Reason:  order.CancellationReason,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toOrderResponse(order), nil
+}
+
+// listOrders lists a page of orders
+func (s *OrderServiceCQRS) listOrders(ctx context.Context, q query.ListOrdersQuery) (*dto.OrderListResponse, error) {
+	result, err := s.listOrdersHandler.Handle(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]dto.OrderResponse, len(result.Orders))
+	for i, order := range result.Orders {
+		orders[i] = *toOrderResponse(order)
+	}
+
+	// Calculate pagination info
+	totalPages := (result.Total + result.Limit - 1) / result.Limit
+
+	return &dto.OrderListResponse{
+		Orders:     orders,
+		Total:      result.Total,
+		Page:       result.Page,
+		Limit:      result.Limit,
+		TotalPages: totalPages,
+		HasNext:    result.Page < totalPages,
+		HasPrev:    result.Page > 1,
+	}, nil
+}
+
+// publishOrderCreated publishes the order created event of a placed order
+func (s *OrderServiceCQRS) publishOrderCreated(ctx context.Context, order *domain.Order) error {
+	items := make([]kafka.PaymentItem, len(order.Items))
+	for i, item := range order.Items {
+		items[i] = kafka.PaymentItem{
+			ProductID:       item.ProductID,
+			VariantID:       item.VariantID,
+			Quantity:        item.Quantity,
+			UnitPriceMinor:  item.UnitPriceMinor,
+			TotalPriceMinor: item.TotalPriceMinor,
+		}
+	}
+
+	return s.eventPublisher.PublishOrderCreated(ctx, kafka.OrderCreatedData{
+		OrderID:     order.ID,
+		UserID:      order.UserID,
+		AmountMinor: order.TotalMinor,
+		Currency:    order.Currency,
+		Items:       items,
+		ShippingInfo: kafka.ShippingInfo{
+			Name:    order.Shipping.Name,
+			Address: order.Shipping.Address,
+			City:    order.Shipping.City,
+			State:   order.Shipping.State,
+			ZipCode: order.Shipping.ZipCode,
+			Country: order.Shipping.Country,
+			Phone:   order.Shipping.Phone,
+		},
+		BillingInfo: kafka.BillingInfo{
+			Name:    order.Billing.Name,
+			Address: order.Billing.Address,
+			City:    order.Billing.City,
+			State:   order.Billing.State,
+			ZipCode: order.Billing.ZipCode,
+			Country: order.Billing.Country,
+		},
+	})
+}
+
+// toDomainAddress converts an address DTO to a domain address
+func toDomainAddress(address dto.Address) domain.Address {
+	return domain.Address{
+		Name:    address.Name,
+		Address: address.Address,
+		City:    address.City,
+		State:   address.State,
+		ZipCode: address.ZipCode,
+		Country: address.Country,
+		Phone:   address.Phone,
+	}
+}
+
+// toAddressDTO converts a domain address to an address DTO
+func toAddressDTO(address domain.Address) dto.Address {
+	return dto.Address{
+		Name:    address.Name,
+		Address: address.Address,
+		City:    address.City,
+		State:   address.State,
+		ZipCode: address.ZipCode,
+		Country: address.Country,
+		Phone:   address.Phone,
+	}
+}
+
+// toOrderResponse converts an order to its DTO
+func toOrderResponse(order *domain.Order) *dto.OrderResponse {
+	items := make([]dto.OrderItemResponse, len(order.Items))
+	for i, item := range order.Items {
+		items[i] = dto.OrderItemResponse{
+			ID:              item.ID,
+			ProductID:       item.ProductID,
+			VariantID:       item.VariantID,
+			SKU:             item.SKU,
+			Name:            item.Name,
+			Quantity:        item.Quantity,
+			UnitPrice:       money.New(item.UnitPriceMinor, order.Currency).Major(),
+			TotalPrice:      money.New(item.TotalPriceMinor, order.Currency).Major(),
+			UnitPriceMinor:  item.UnitPriceMinor,
+			TotalPriceMinor: item.TotalPriceMinor,
+		}
+	}
+
+	return &dto.OrderResponse{
+		ID:                 order.ID,
+		UserID:             order.UserID,
+		BasketID:           order.BasketID,
+		Status:             string(order.Status),
+		Items:              items,
+		ItemCount:          order.ItemCount,
+		Total:              order.Total().Major(),
+		TotalMinor:         order.TotalMinor,
+		Currency:           order.Currency,
+		ShippingAddress:    toAddressDTO(order.Shipping),
+		BillingAddress:     toAddressDTO(order.Billing),
+		PaymentID:          order.PaymentID,
+		PaymentError:       order.PaymentError,
+		Refunded:           order.Refunded().Major(),
+		RefundedMinor:      order.RefundedMinor,
+		CancellationReason: order.CancellationReason,
+		CreatedAt:          order.CreatedAt,
+		UpdatedAt:          order.UpdatedAt,
+		PaidAt:             order.PaidAt,
+		CancelledAt:        order.CancelledAt,
+	}
+}
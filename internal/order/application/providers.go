@@ -0,0 +1,22 @@
+package application
+
+import (
+	"github.com/ddd-micro/internal/order/application/command"
+	"github.com/ddd-micro/internal/order/application/query"
+	"github.com/google/wire"
+)
+
+// ProviderSet is the Wire provider set for application layer
+var ProviderSet = wire.NewSet(
+	NewOrderServiceCQRS,
+	// Command handlers
+	command.NewPlaceOrderCommandHandler,
+	command.NewCancelOrderCommandHandler,
+	command.NewMarkOrderPaidCommandHandler,
+	command.NewMarkPaymentFailedCommandHandler,
+	command.NewApplyRefundCommandHandler,
+	// Query handlers
+	query.NewGetOrderQueryHandler,
+	query.NewListOrdersQueryHandler,
+	query.NewGetOrderHistoryQueryHandler,
+)
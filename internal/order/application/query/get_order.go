@@ -0,0 +1,41 @@
+package query
+
+import (
+	"context"
+
+	"github.com/ddd-micro/internal/order/domain"
+)
+
+// GetOrderQuery represents the query to get an order
+type GetOrderQuery struct {
+	OrderID string
+	// UserID restricts the query to the orders of a user; nil for admins
+	UserID *uint
+}
+
+// GetOrderQueryHandler handles the get order query
+type GetOrderQueryHandler struct {
+	orderRepo domain.OrderRepository
+}
+
+// NewGetOrderQueryHandler creates a new get order query handler
+func NewGetOrderQueryHandler(orderRepo domain.OrderRepository) *GetOrderQueryHandler {
+	return &GetOrderQueryHandler{
+		orderRepo: orderRepo,
+	}
+}
+
+// Handle handles the get order query
+func (h *GetOrderQueryHandler) Handle(ctx context.Context, query GetOrderQuery) (*domain.Order, error) {
+	order, err := h.orderRepo.GetByID(ctx, query.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if user owns the order
+	if query.UserID != nil && order.UserID != *query.UserID {
+		return nil, domain.ErrOrderNotFound
+	}
+
+	return order, nil
+}
@@ -0,0 +1,34 @@
+package query
+
+import (
+	"context"
+
+	"github.com/ddd-micro/internal/order/domain"
+)
+
+// GetOrderHistoryQuery represents the query to get the status history of an order
+type GetOrderHistoryQuery struct {
+	OrderID string
+}
+
+// GetOrderHistoryQueryHandler handles the get order history query
+type GetOrderHistoryQueryHandler struct {
+	orderRepo domain.OrderRepository
+}
+
+// NewGetOrderHistoryQueryHandler creates a new get order history query handler
+func NewGetOrderHistoryQueryHandler(orderRepo domain.OrderRepository) *GetOrderHistoryQueryHandler {
+	return &GetOrderHistoryQueryHandler{
+		orderRepo: orderRepo,
+	}
+}
+
+// Handle handles the get order history query
+func (h *GetOrderHistoryQueryHandler) Handle(ctx context.Context, query GetOrderHistoryQuery) ([]*domain.OrderStatusHistory, error) {
+	// Make sure the order exists
+	if _, err := h.orderRepo.GetByID(ctx, query.OrderID); err != nil {
+		return nil, err
+	}
+
+	return h.orderRepo.GetStatusHistory(ctx, query.OrderID)
+}
@@ -0,0 +1,63 @@
+package query
+
+import (
+	"context"
+
+	"github.com/ddd-micro/internal/order/domain"
+)
+
+// ListOrdersQuery represents the query to list orders
+type ListOrdersQuery struct {
+	Filter domain.OrderFilter
+	Page   int
+	Limit  int
+}
+
+// ListOrdersResult is a page of orders
+type ListOrdersResult struct {
+	Orders []*domain.Order
+	Total  int
+	Page   int
+	Limit  int
+}
+
+// ListOrdersQueryHandler handles the list orders query
+type ListOrdersQueryHandler struct {
+	orderRepo domain.OrderRepository
+}
+
+// NewListOrdersQueryHandler creates a new list orders query handler
+func NewListOrdersQueryHandler(orderRepo domain.OrderRepository) *ListOrdersQueryHandler {
+	return &ListOrdersQueryHandler{
+		orderRepo: orderRepo,
+	}
+}
+
+// Handle handles the list orders query
+func (h *ListOrdersQueryHandler) Handle(ctx context.Context, query ListOrdersQuery) (*ListOrdersResult, error) {
+	// Set defaults
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	if query.Limit <= 0 || query.Limit > 100 {
+		query.Limit = 20
+	}
+	if query.Filter.Status != "" && !domain.IsValidOrderStatus(domain.OrderStatus(query.Filter.Status)) {
+		return nil, domain.ErrInvalidStatus
+	}
+
+	// Calculate offset
+	offset := (query.Page - 1) * query.Limit
+
+	orders, total, err := h.orderRepo.List(ctx, query.Filter, query.Limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListOrdersResult{
+		Orders: orders,
+		Total:  total,
+		Page:   query.Page,
+		Limit:  query.Limit,
+	}, nil
+}
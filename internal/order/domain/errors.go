@@ -0,0 +1,19 @@
+package domain
+
+import "errors"
+
+// Domain layer errors
+var (
+	ErrOrderNotFound          = errors.New("order not found")
+	ErrInvalidOrderID         = errors.New("invalid order ID")
+	ErrInvalidUserID          = errors.New("invalid user ID")
+	ErrInvalidQuantity        = errors.New("invalid quantity")
+	ErrInvalidPrice           = errors.New("invalid price")
+	ErrInvalidCurrency        = errors.New("invalid currency")
+	ErrInvalidAddress         = errors.New("shipping address requires name, address, city and country")
+	ErrInvalidStatus          = errors.New("invalid status")
+	ErrEmptyOrder             = errors.New("order has no items")
+	ErrOrderCannotBeCancelled = errors.New("order cannot be cancelled")
+	ErrPaymentAmountMismatch  = errors.New("payment amount does not match order total")
+	ErrPaymentMismatch        = errors.New("payment does not belong to the order")
+)
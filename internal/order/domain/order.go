@@ -0,0 +1,232 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/ddd-micro/pkg/money"
+)
+
+// OrderStatus represents the status of an order
+type OrderStatus string
+
+const (
+	OrderStatusPending           OrderStatus = "pending"
+	OrderStatusPaid              OrderStatus = "paid"
+	OrderStatusPaymentFailed     OrderStatus = "payment_failed"
+	OrderStatusCancelled         OrderStatus = "cancelled"
+	OrderStatusPartiallyRefunded OrderStatus = "partially_refunded"
+	OrderStatusRefunded          OrderStatus = "refunded"
+)
+
+// Address is a postal address snapshotted into an order
+type Address struct {
+	Name    string `json:"name" gorm:"type:varchar(100)"`
+	Address string `json:"address" gorm:"type:varchar(255)"`
+	City    string `json:"city" gorm:"type:varchar(100)"`
+	State   string `json:"state" gorm:"type:varchar(100)"`
+	ZipCode string `json:"zip_code" gorm:"type:varchar(20)"`
+	Country string `json:"country" gorm:"type:varchar(100)"`
+	Phone   string `json:"phone" gorm:"type:varchar(30)"`
+}
+
+// Order is a customer's purchase of the items of a basket. Items, prices and addresses
+// are copied when the order is placed, so later product or basket changes leave it untouched.
+type Order struct {
+	ID         string      `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID     uint        `json:"user_id" gorm:"not null;index"`
+	BasketID   string      `json:"basket_id" gorm:"type:varchar(36);index"`
+	Status     OrderStatus `json:"status" gorm:"type:varchar(20);not null;default:'pending';index"`
+	Items      []OrderItem `json:"items" gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+	ItemCount  int         `json:"item_count" gorm:"not null"`
+	TotalMinor int64       `json:"total_minor" gorm:"not null"` // Total in minor units of Currency
+	Currency   string      `json:"currency" gorm:"type:varchar(3);not null"`
+	Shipping   Address     `json:"shipping_address" gorm:"embedded;embeddedPrefix:shipping_"`
+	Billing    Address     `json:"billing_address" gorm:"embedded;embeddedPrefix:billing_"`
+	// PaymentID is the payment that paid the order; failed payments are not kept
+	PaymentID          *string    `json:"payment_id" gorm:"type:varchar(36);index"`
+	PaymentError       string     `json:"payment_error" gorm:"type:text"`
+	RefundedMinor      int64      `json:"refunded_minor" gorm:"not null;default:0"`
+	CancellationReason string     `json:"cancellation_reason" gorm:"type:text"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+	PaidAt             *time.Time `json:"paid_at"`
+	CancelledAt        *time.Time `json:"cancelled_at"`
+
+	// statusHistory holds transitions not yet stored by the repository
+	statusHistory []*OrderStatusHistory
+}
+
+// OrderItem is a line of an order, priced in minor units of the order currency
+type OrderItem struct {
+	ID              uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	OrderID         string `json:"order_id" gorm:"type:varchar(36);not null;index"`
+	ProductID       uint   `json:"product_id" gorm:"not null;index"`
+	VariantID       *uint  `json:"variant_id,omitempty" gorm:"index"`
+	SKU             string `json:"sku" gorm:"type:varchar(100)"`
+	Name            string `json:"name" gorm:"type:varchar(255);not null"`
+	Quantity        int    `json:"quantity" gorm:"not null"`
+	UnitPriceMinor  int64  `json:"unit_price_minor" gorm:"not null"`
+	TotalPriceMinor int64  `json:"total_price_minor" gorm:"not null"`
+}
+
+// TableName returns the table name for Order
+func (Order) TableName() string {
+	return "orders"
+}
+
+// TableName returns the table name for OrderItem
+func (OrderItem) TableName() string {
+	return "order_items"
+}
+
+// NewOrder creates a pending order of the given items, totalling them
+func NewOrder(id string, userID uint, basketID, currency string, items []OrderItem, shipping, billing Address) (*Order, error) {
+	order := &Order{
+		ID:       id,
+		UserID:   userID,
+		BasketID: basketID,
+		Status:   OrderStatusPending,
+		Currency: currency,
+		Shipping: shipping,
+		Billing:  billing,
+	}
+
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return nil, ErrInvalidQuantity
+		}
+		if item.UnitPriceMinor <= 0 {
+			return nil, ErrInvalidPrice
+		}
+		item.OrderID = id
+		item.TotalPriceMinor = item.UnitPriceMinor * int64(item.Quantity)
+		order.Items = append(order.Items, item)
+		order.ItemCount += item.Quantity
+		order.TotalMinor += item.TotalPriceMinor
+	}
+
+	if err := order.Validate(); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// Total returns the order total as money
+func (o *Order) Total() money.Money {
+	return money.New(o.TotalMinor, o.Currency)
+}
+
+// Refunded returns the refunded amount as money
+func (o *Order) Refunded() money.Money {
+	return money.New(o.RefundedMinor, o.Currency)
+}
+
+// IsPending checks if the order is waiting for its payment
+func (o *Order) IsPending() bool {
+	return o.Status == OrderStatusPending
+}
+
+// IsPaid checks if the order is paid
+func (o *Order) IsPaid() bool {
+	return o.Status == OrderStatusPaid
+}
+
+// IsCancelled checks if the order is cancelled
+func (o *Order) IsCancelled() bool {
+	return o.Status == OrderStatusCancelled
+}
+
+// CanBeCancelled checks if the order can be cancelled; paid orders are refunded instead
+func (o *Order) CanBeCancelled() bool {
+	return o.Status == OrderStatusPending || o.Status == OrderStatusPaymentFailed
+}
+
+// Cancel cancels an unpaid order
+func (o *Order) Cancel(reason string, change StatusChange) error {
+	if !o.CanBeCancelled() {
+		return ErrOrderCannotBeCancelled
+	}
+	if err := o.TransitionTo(OrderStatusCancelled, change); err != nil {
+		return err
+	}
+	now := time.Now()
+	o.CancellationReason = reason
+	o.CancelledAt = &now
+	return nil
+}
+
+// MarkPaid records the payment that paid the order; it must cover the order total
+func (o *Order) MarkPaid(paymentID string, amount money.Money, change StatusChange) error {
+	// The payment already paid the order, which may have been refunded since
+	if o.PaymentID != nil && *o.PaymentID == paymentID {
+		return nil
+	}
+	if !amount.SameCurrency(o.Total()) || amount.Amount != o.TotalMinor {
+		return ErrPaymentAmountMismatch
+	}
+	if err := o.TransitionTo(OrderStatusPaid, change); err != nil {
+		return err
+	}
+	now := time.Now()
+	o.PaymentID = &paymentID
+	o.PaymentError = ""
+	o.PaidAt = &now
+	return nil
+}
+
+// MarkPaymentFailed records a failed or cancelled payment; the customer may pay again
+func (o *Order) MarkPaymentFailed(reason string, change StatusChange) error {
+	if err := o.TransitionTo(OrderStatusPaymentFailed, change); err != nil {
+		return err
+	}
+	o.PaymentError = reason
+	return nil
+}
+
+// ApplyRefund records the total refunded for the payment of the order
+func (o *Order) ApplyRefund(paymentID string, totalRefunded money.Money, change StatusChange) error {
+	if o.PaymentID == nil || *o.PaymentID != paymentID {
+		return ErrPaymentMismatch
+	}
+	if !totalRefunded.SameCurrency(o.Total()) {
+		return ErrPaymentAmountMismatch
+	}
+
+	status := OrderStatusPartiallyRefunded
+	if totalRefunded.Amount >= o.TotalMinor {
+		status = OrderStatusRefunded
+	}
+	// Further partial refunds only raise the refunded amount
+	if status != o.Status || status != OrderStatusPartiallyRefunded {
+		if err := o.TransitionTo(status, change); err != nil {
+			return err
+		}
+	}
+	o.RefundedMinor = max(o.RefundedMinor, totalRefunded.Amount)
+	return nil
+}
+
+// Validate validates the order
+func (o *Order) Validate() error {
+	if o.ID == "" {
+		return ErrInvalidOrderID
+	}
+
+	if o.UserID == 0 {
+		return ErrInvalidUserID
+	}
+
+	if len(o.Items) == 0 {
+		return ErrEmptyOrder
+	}
+
+	if !money.IsValidCurrency(o.Currency) {
+		return ErrInvalidCurrency
+	}
+
+	if o.Shipping.Name == "" || o.Shipping.Address == "" || o.Shipping.City == "" || o.Shipping.Country == "" {
+		return ErrInvalidAddress
+	}
+
+	return nil
+}
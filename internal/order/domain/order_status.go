@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidStatusTransition is matched by every InvalidStatusTransitionError
+var ErrInvalidStatusTransition = errors.New("invalid order status transition")
+
+// orderTransitions lists the statuses an order may move to from each status.
+// Cancelled and refunded orders are final.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending: {
+		OrderStatusPaid,
+		OrderStatusPaymentFailed,
+		OrderStatusCancelled,
+	},
+	OrderStatusPaymentFailed: {
+		// The customer may pay again, e.g. with another payment method
+		OrderStatusPaid,
+		OrderStatusCancelled,
+	},
+	OrderStatusPaid: {
+		OrderStatusPartiallyRefunded,
+		OrderStatusRefunded,
+	},
+	OrderStatusPartiallyRefunded: {
+		OrderStatusRefunded,
+	},
+	OrderStatusCancelled: {},
+	OrderStatusRefunded:  {},
+}
+
+// Status change actors
+const (
+	ActorSystem = "system"
+)
+
+// UserActor identifies a status change made by a customer
+func UserActor(userID uint) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+// AdminActor identifies a status change made by an administrator
+func AdminActor(adminID uint) string {
+	return fmt.Sprintf("admin:%d", adminID)
+}
+
+// PaymentActor identifies a status change following an event of a payment
+func PaymentActor(paymentID string) string {
+	return "payment:" + paymentID
+}
+
+// InvalidStatusTransitionError is returned when an order cannot move between two statuses
+type InvalidStatusTransitionError struct {
+	From OrderStatus
+	To   OrderStatus
+}
+
+// Error implements the error interface
+func (e *InvalidStatusTransitionError) Error() string {
+	return fmt.Sprintf("%s: %s -> %s", ErrInvalidStatusTransition, e.From, e.To)
+}
+
+// Is makes errors.Is(err, ErrInvalidStatusTransition) match
+func (e *InvalidStatusTransitionError) Is(target error) bool {
+	return target == ErrInvalidStatusTransition
+}
+
+// IsValidOrderStatus checks if the status is a known order status
+func IsValidOrderStatus(status OrderStatus) bool {
+	_, ok := orderTransitions[status]
+	return ok
+}
+
+// CanTransition checks if an order may move from one status to another
+func CanTransition(from, to OrderStatus) bool {
+	for _, allowed := range orderTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusChange describes who triggered a status transition and why
+type StatusChange struct {
+	Actor  string
+	Reason string
+}
+
+// OrderStatusHistory records a single order status transition
+type OrderStatusHistory struct {
+	ID         uint        `json:"id" gorm:"primaryKey;autoIncrement"`
+	OrderID    string      `json:"order_id" gorm:"not null;index;type:varchar(36)"`
+	FromStatus OrderStatus `json:"from_status" gorm:"type:varchar(20);not null"`
+	ToStatus   OrderStatus `json:"to_status" gorm:"type:varchar(20);not null"`
+	Actor      string      `json:"actor" gorm:"type:varchar(100);not null"`
+	Reason     string      `json:"reason" gorm:"type:text"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// TableName returns the table name for OrderStatusHistory
+func (OrderStatusHistory) TableName() string {
+	return "order_status_history"
+}
+
+// TransitionTo moves the order to a new status, rejecting moves the transition
+// table does not allow. Moving to the current status is a no-op.
+func (o *Order) TransitionTo(status OrderStatus, change StatusChange) error {
+	if o.Status == status {
+		return nil
+	}
+	if !CanTransition(o.Status, status) {
+		return &InvalidStatusTransitionError{From: o.Status, To: status}
+	}
+
+	entry := &OrderStatusHistory{
+		OrderID:    o.ID,
+		FromStatus: o.Status,
+		ToStatus:   status,
+		Actor:      change.Actor,
+		Reason:     change.Reason,
+		CreatedAt:  time.Now(),
+	}
+	if entry.Actor == "" {
+		entry.Actor = ActorSystem
+	}
+
+	o.Status = status
+	o.statusHistory = append(o.statusHistory, entry)
+	return nil
+}
+
+// PullStatusHistory returns the transitions recorded since the order was
+// loaded and clears them, so the repository stores each transition once
+func (o *Order) PullStatusHistory() []*OrderStatusHistory {
+	history := o.statusHistory
+	o.statusHistory = nil
+	return history
+}
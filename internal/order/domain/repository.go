@@ -0,0 +1,22 @@
+package domain
+
+import "context"
+
+// OrderFilter narrows down order listings
+type OrderFilter struct {
+	UserID *uint
+	Status string
+}
+
+// OrderRepository defines the interface for order data operations
+type OrderRepository interface {
+	// Create stores the order with its items and initial status
+	Create(ctx context.Context, order *Order) error
+	GetByID(ctx context.Context, orderID string) (*Order, error)
+	// GetByIDForUpdate gets the order and locks it until the transaction in ctx ends
+	GetByIDForUpdate(ctx context.Context, orderID string) (*Order, error)
+	List(ctx context.Context, filter OrderFilter, limit, offset int) ([]*Order, int, error)
+	// Update saves the order and the status transitions it recorded; items never change
+	Update(ctx context.Context, order *Order) error
+	GetStatusHistory(ctx context.Context, orderID string) ([]*OrderStatusHistory, error)
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	basketpb "github.com/ddd-micro/api/proto/basket"
+	"github.com/ddd-micro/pkg/money"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// BasketClient defines the interface for basket service operations
+type BasketClient interface {
+	GetBasket(ctx context.Context, userID uint) (*basketpb.BasketResponse, error)
+}
+
+// basketClient implements BasketClient interface
+type basketClient struct {
+	conn   *grpc.ClientConn
+	client basketpb.BasketServiceClient
+}
+
+// NewBasketClient creates a new basket client
+func NewBasketClient(basketServiceURL string) (BasketClient, error) {
+	conn, err := grpc.Dial(basketServiceURL, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to basket service: %w", err)
+	}
+
+	client := basketpb.NewBasketServiceClient(conn)
+
+	return &basketClient{
+		conn:   conn,
+		client: client,
+	}, nil
+}
+
+// GetBasket gets user's basket
+func (c *basketClient) GetBasket(ctx context.Context, userID uint) (*basketpb.BasketResponse, error) {
+	resp, err := c.client.GetBasket(ctx, &basketpb.GetBasketRequest{UserId: uint32(userID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get basket: %w", err)
+	}
+
+	return resp, nil
+}
+
+// Close closes the gRPC connection
+func (c *basketClient) Close() error {
+	return c.conn.Close()
+}
+
+// BasketCurrency returns the currency of a basket, falling back to the currency
+// assumed for basket services that predate currencies
+func BasketCurrency(basket *basketpb.BasketResponse) string {
+	if basket.Currency == "" {
+		return money.LegacyCurrency
+	}
+	return basket.Currency
+}
+
+// BasketItemVariantID returns the variant ID of a basket item, nil for the product itself
+func BasketItemVariantID(item *basketpb.BasketItem) *uint {
+	if item.VariantId == nil {
+		return nil
+	}
+	variantID := uint(*item.VariantId)
+	return &variantID
+}
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	productpb "github.com/ddd-micro/api/proto/product"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// ProductClient defines the interface for product service operations
+type ProductClient interface {
+	GetProduct(ctx context.Context, productID uint) (*productpb.Product, error)
+}
+
+// productClient implements ProductClient interface
+type productClient struct {
+	conn   *grpc.ClientConn
+	client productpb.ProductServiceClient
+}
+
+// NewProductClient creates a new product client
+func NewProductClient(productServiceURL string) (ProductClient, error) {
+	conn, err := grpc.Dial(productServiceURL, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to product service: %w", err)
+	}
+
+	client := productpb.NewProductServiceClient(conn)
+
+	return &productClient{
+		conn:   conn,
+		client: client,
+	}, nil
+}
+
+// GetProduct gets a single product by ID
+func (c *productClient) GetProduct(ctx context.Context, productID uint) (*productpb.Product, error) {
+	resp, err := c.client.GetProduct(ctx, &productpb.GetProductRequest{Id: uint32(productID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	return resp.Product, nil
+}
+
+// Close closes the gRPC connection
+func (c *productClient) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	userpb "github.com/ddd-micro/api/proto/user"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// UserClient defines the interface for user service operations
+type UserClient interface {
+	ValidateToken(ctx context.Context, token string) (*userpb.User, error)
+}
+
+// userClient implements UserClient interface
+type userClient struct {
+	conn   *grpc.ClientConn
+	client userpb.UserServiceClient
+}
+
+// NewUserClient creates a new user client
+func NewUserClient(userServiceURL string) (UserClient, error) {
+	conn, err := grpc.Dial(userServiceURL, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to user service: %w", err)
+	}
+
+	client := userpb.NewUserServiceClient(conn)
+
+	return &userClient{
+		conn:   conn,
+		client: client,
+	}, nil
+}
+
+// ValidateToken validates a JWT token by fetching the profile it belongs to
+func (c *userClient) ValidateToken(ctx context.Context, token string) (*userpb.User, error) {
+	md := metadata.Pairs("authorization", "Bearer "+token)
+	ctx = metadata.NewOutgoingContext(ctx, md)
+
+	resp, err := c.client.GetProfile(ctx, &userpb.GetProfileRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate token: %w", err)
+	}
+
+	return resp.User, nil
+}
+
+// Close closes the gRPC connection
+func (c *userClient) Close() error {
+	return c.conn.Close()
+}
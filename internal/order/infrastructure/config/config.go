@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Config holds all configuration for the order service
+type Config struct {
+	// Server configuration
+	HTTPPort string
+	GRPCPort string
+
+	// Database configuration
+	Database DatabaseConfig
+
+	// External services
+	UserServiceURL    string
+	ProductServiceURL string
+	BasketServiceURL  string
+}
+
+// DatabaseConfig holds database configuration
+type DatabaseConfig struct {
+	Host     string
+	Port     int
+	User     string
+	Password string
+	DBName   string
+	SSLMode  string
+}
+
+// LoadConfig loads configuration from environment variables
+func LoadConfig() (*Config, error) {
+	config := &Config{
+		HTTPPort: getEnv("HTTP_PORT", "8085"),
+		GRPCPort: getEnv("GRPC_PORT", "9095"),
+
+		Database: DatabaseConfig{
+			Host:     getEnv("DB_HOST", "localhost"),
+			Port:     getEnvAsInt("DB_PORT", 5432),
+			User:     getEnv("DB_USER", "postgres"),
+			Password: getEnv("DB_PASSWORD", "password"),
+			DBName:   getEnv("DB_NAME", "order_db"),
+			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+		},
+
+		UserServiceURL:    getEnv("USER_SERVICE_URL", "user-service:9090"),
+		ProductServiceURL: getEnv("PRODUCT_SERVICE_URL", "product-service:9091"),
+		BasketServiceURL:  getEnv("BASKET_SERVICE_URL", "basket-service:9093"),
+	}
+
+	return config, nil
+}
+
+// GetDatabaseDSN returns the database connection string
+func (c *Config) GetDatabaseDSN() string {
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		c.Database.Host,
+		c.Database.Port,
+		c.Database.User,
+		c.Database.Password,
+		c.Database.DBName,
+		c.Database.SSLMode,
+	)
+}
+
+// Helper functions
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvAsInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
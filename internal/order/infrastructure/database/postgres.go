@@ -0,0 +1,80 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ddd-micro/internal/order/domain"
+	"github.com/ddd-micro/internal/order/infrastructure/config"
+	"github.com/ddd-micro/kafka"
+	"github.com/ddd-micro/pkg/outbox"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// NewPostgresDB creates a new PostgreSQL database connection
+func NewPostgresDB(cfg *config.Config) (*gorm.DB, error) {
+	dsn := cfg.GetDatabaseDSN()
+
+	// Configure GORM
+	gormConfig := &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Info),
+		NowFunc: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+
+	// Connect to database
+	db, err := gorm.Open(postgres.Open(dsn), gormConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	// Get underlying sql.DB
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get underlying sql.DB: %w", err)
+	}
+
+	// Configure connection pool
+	sqlDB.SetMaxIdleConns(10)
+	sqlDB.SetMaxOpenConns(100)
+	sqlDB.SetConnMaxLifetime(time.Hour)
+
+	// Test connection
+	if err := sqlDB.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	log.Println("Successfully connected to PostgreSQL database")
+
+	if err := Migrate(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	return db, nil
+}
+
+// Migrate runs database migrations
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(
+		&domain.Order{},
+		&domain.OrderItem{},
+		&domain.OrderStatusHistory{},
+	); err != nil {
+		return err
+	}
+
+	if err := outbox.Migrate(db); err != nil {
+		return err
+	}
+
+	if err := kafka.MigrateProcessedEvents(db); err != nil {
+		return err
+	}
+
+	log.Println("Database migration completed")
+	return nil
+}
@@ -0,0 +1,54 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/ddd-micro/kafka"
+	"github.com/ddd-micro/pkg/outbox"
+)
+
+// aggregateOrder is the aggregate type of the order service events
+const aggregateOrder = "order"
+
+// OrderEventPublisher handles order-related Kafka events. Events are written to
+// the outbox within the transaction carried by ctx and published by the outbox relay.
+type OrderEventPublisher struct {
+	outbox *outbox.Store
+}
+
+// NewOrderEventPublisher creates a new order event publisher
+func NewOrderEventPublisher(store *outbox.Store) *OrderEventPublisher {
+	return &OrderEventPublisher{
+		outbox: store,
+	}
+}
+
+// PublishOrderCreated publishes an order created event
+func (p *OrderEventPublisher) PublishOrderCreated(ctx context.Context, data kafka.OrderCreatedData) error {
+	event := kafka.OrderCreatedEvent{
+		BaseEvent: kafka.NewBaseEvent(ctx, kafka.EventTypeOrderCreated, "order-service", data.OrderID),
+		Data:      data,
+	}
+
+	return p.outbox.Add(ctx, aggregateOrder, data.OrderID, event.Type, event)
+}
+
+// PublishOrderCancelled publishes an order cancelled event
+func (p *OrderEventPublisher) PublishOrderCancelled(ctx context.Context, data kafka.OrderCancelledData) error {
+	event := kafka.OrderCancelledEvent{
+		BaseEvent: kafka.NewBaseEvent(ctx, kafka.EventTypeOrderCancelled, "order-service", data.OrderID),
+		Data:      data,
+	}
+
+	return p.outbox.Add(ctx, aggregateOrder, data.OrderID, event.Type, event)
+}
+
+// PublishOrderPaymentRejected publishes an order payment rejected event
+func (p *OrderEventPublisher) PublishOrderPaymentRejected(ctx context.Context, data kafka.OrderPaymentRejectedData) error {
+	event := kafka.OrderPaymentRejectedEvent{
+		BaseEvent: kafka.NewBaseEvent(ctx, kafka.EventTypeOrderPaymentRejected, "order-service", data.OrderID),
+		Data:      data,
+	}
+
+	return p.outbox.Add(ctx, aggregateOrder, data.OrderID, event.Type, event)
+}
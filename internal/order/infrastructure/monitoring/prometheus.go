@@ -0,0 +1,116 @@
+package monitoring
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// PrometheusMetrics holds all the prometheus metrics for order service
+type PrometheusMetrics struct {
+	HTTPRequestsTotal     *prometheus.CounterVec
+	HTTPRequestDuration   *prometheus.HistogramVec
+	HTTPRequestsInFlight  *prometheus.GaugeVec
+	OrderPlacements       prometheus.Counter
+	OrderPlacementErrors  prometheus.Counter
+	OrderCancellations    prometheus.Counter
+	DatabaseQueryDuration *prometheus.HistogramVec
+}
+
+// NewPrometheusMetrics creates a new instance of PrometheusMetrics
+func NewPrometheusMetrics() *PrometheusMetrics {
+	return &PrometheusMetrics{
+		HTTPRequestsTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "order_service_http_requests_total",
+				Help: "Total number of HTTP requests to order service",
+			},
+			[]string{"method", "endpoint", "status_code"},
+		),
+		HTTPRequestDuration: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "order_service_http_request_duration_seconds",
+				Help:    "Duration of HTTP requests in seconds",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"method", "endpoint", "status_code"},
+		),
+		HTTPRequestsInFlight: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "order_service_http_requests_in_flight",
+				Help: "Current number of HTTP requests being processed",
+			},
+			[]string{"method", "endpoint"},
+		),
+		OrderPlacements: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "order_service_order_placements_total",
+				Help: "Total number of orders placed",
+			},
+		),
+		OrderPlacementErrors: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "order_service_order_placement_errors_total",
+				Help: "Total number of orders that could not be placed",
+			},
+		),
+		OrderCancellations: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "order_service_order_cancellations_total",
+				Help: "Total number of orders cancelled by customers and admins",
+			},
+		),
+		DatabaseQueryDuration: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "order_service_database_query_duration_seconds",
+				Help:    "Duration of database queries in seconds",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"operation", "table"},
+		),
+	}
+}
+
+// PrometheusMiddleware returns a Gin middleware for Prometheus metrics
+func PrometheusMiddleware(metrics *PrometheusMetrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		// Increment requests in flight
+		metrics.HTTPRequestsInFlight.WithLabelValues(c.Request.Method, c.FullPath()).Inc()
+		defer metrics.HTTPRequestsInFlight.WithLabelValues(c.Request.Method, c.FullPath()).Dec()
+
+		// Process request
+		c.Next()
+
+		// Record metrics
+		duration := time.Since(start).Seconds()
+		status := strconv.Itoa(c.Writer.Status())
+
+		metrics.HTTPRequestsTotal.WithLabelValues(c.Request.Method, c.FullPath(), status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(c.Request.Method, c.FullPath(), status).Observe(duration)
+	}
+}
+
+// RecordOrderPlacement increments the order placement counter
+func (m *PrometheusMetrics) RecordOrderPlacement() {
+	m.OrderPlacements.Inc()
+}
+
+// RecordOrderPlacementError increments the order placement error counter
+func (m *PrometheusMetrics) RecordOrderPlacementError() {
+	m.OrderPlacementErrors.Inc()
+}
+
+// RecordOrderCancellation increments the order cancellation counter
+func (m *PrometheusMetrics) RecordOrderCancellation() {
+	m.OrderCancellations.Inc()
+}
+
+// RecordDatabaseQueryDuration records database query duration
+func (m *PrometheusMetrics) RecordDatabaseQueryDuration(operation, table string, duration time.Duration) {
+	m.DatabaseQueryDuration.WithLabelValues(operation, table).Observe(duration.Seconds())
+}
@@ -0,0 +1,120 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ddd-micro/internal/order/domain"
+	"github.com/ddd-micro/pkg/gormtx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// orderRepository implements domain.OrderRepository
+type orderRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderRepository creates a new order repository
+func NewOrderRepository(db *gorm.DB) domain.OrderRepository {
+	return &orderRepository{
+		db: db,
+	}
+}
+
+// Create creates a new order with its items
+func (r *orderRepository) Create(ctx context.Context, order *domain.Order) error {
+	return gormtx.DB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(order).Error; err != nil {
+			return fmt.Errorf("failed to create order: %w", err)
+		}
+		return saveStatusHistory(tx, order)
+	})
+}
+
+// GetByID gets an order with its items by ID
+func (r *orderRepository) GetByID(ctx context.Context, orderID string) (*domain.Order, error) {
+	return r.get(gormtx.DB(ctx, r.db), orderID)
+}
+
+// GetByIDForUpdate gets an order with its items by ID, locking its row
+func (r *orderRepository) GetByIDForUpdate(ctx context.Context, orderID string) (*domain.Order, error) {
+	return r.get(gormtx.DB(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}), orderID)
+}
+
+// get gets an order with its items by ID
+func (r *orderRepository) get(db *gorm.DB, orderID string) (*domain.Order, error) {
+	var order domain.Order
+	if err := db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Where("id = ?", orderID).First(&order).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	return &order, nil
+}
+
+// List lists orders, newest first, with pagination and filters
+func (r *orderRepository) List(ctx context.Context, filter domain.OrderFilter, limit, offset int) ([]*domain.Order, int, error) {
+	var orders []*domain.Order
+	var total int64
+
+	query := gormtx.DB(ctx, r.db).Model(&domain.Order{})
+
+	// Apply filters if provided
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	// Get total count
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count orders: %w", err)
+	}
+
+	// Get orders with pagination
+	if err := query.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	}).Order("created_at DESC").Limit(limit).Offset(offset).Find(&orders).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	return orders, int(total), nil
+}
+
+// Update updates an order together with the status transitions made since it was loaded
+func (r *orderRepository) Update(ctx context.Context, order *domain.Order) error {
+	order.UpdatedAt = time.Now()
+	return gormtx.DB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(order).Error; err != nil {
+			return fmt.Errorf("failed to update order: %w", err)
+		}
+		return saveStatusHistory(tx, order)
+	})
+}
+
+// saveStatusHistory stores the pending status transitions of an order
+func saveStatusHistory(tx *gorm.DB, order *domain.Order) error {
+	history := order.PullStatusHistory()
+	if len(history) == 0 {
+		return nil
+	}
+	if err := tx.Create(&history).Error; err != nil {
+		return fmt.Errorf("failed to save order status history: %w", err)
+	}
+	return nil
+}
+
+// GetStatusHistory gets the status transitions of an order, oldest first
+func (r *orderRepository) GetStatusHistory(ctx context.Context, orderID string) ([]*domain.OrderStatusHistory, error) {
+	var history []*domain.OrderStatusHistory
+	if err := gormtx.DB(ctx, r.db).Where("order_id = ?", orderID).Order("created_at ASC, id ASC").Find(&history).Error; err != nil {
+		return nil, fmt.Errorf("failed to get order status history: %w", err)
+	}
+	return history, nil
+}
@@ -0,0 +1,89 @@
+package infrastructure
+
+import (
+	"github.com/ddd-micro/internal/order/infrastructure/client"
+	"github.com/ddd-micro/internal/order/infrastructure/config"
+	"github.com/ddd-micro/internal/order/infrastructure/database"
+	orderkafka "github.com/ddd-micro/internal/order/infrastructure/kafka"
+	"github.com/ddd-micro/internal/order/infrastructure/monitoring"
+	"github.com/ddd-micro/internal/order/infrastructure/persistence"
+	"github.com/ddd-micro/kafka"
+	"github.com/ddd-micro/pkg/gormtx"
+	"github.com/ddd-micro/pkg/outbox"
+	"github.com/google/wire"
+	"gorm.io/gorm"
+)
+
+// ProviderSet is the Wire provider set for infrastructure
+var ProviderSet = wire.NewSet(
+	// Configuration
+	config.LoadConfig,
+
+	// Database
+	database.NewPostgresDB,
+	gormtx.NewTransactor,
+
+	// Repositories
+	persistence.NewOrderRepository,
+
+	// External service clients
+	ProvideUserClient,
+	ProvideProductClient,
+	ProvideBasketClient,
+
+	// Kafka
+	ProvideKafkaConfig,
+	ProvideKafkaPublisher,
+	ProvideKafkaConsumer,
+	orderkafka.ProviderSet,
+
+	// Outbox
+	outbox.NewStore,
+	ProvideOutboxRelay,
+
+	// Monitoring
+	monitoring.ProviderSet,
+)
+
+// ProvideUserClient provides user client
+func ProvideUserClient(cfg *config.Config) (client.UserClient, error) {
+	return client.NewUserClient(cfg.UserServiceURL)
+}
+
+// ProvideProductClient provides product client
+func ProvideProductClient(cfg *config.Config) (client.ProductClient, error) {
+	return client.NewProductClient(cfg.ProductServiceURL)
+}
+
+// ProvideBasketClient provides basket client
+func ProvideBasketClient(cfg *config.Config) (client.BasketClient, error) {
+	return client.NewBasketClient(cfg.BasketServiceURL)
+}
+
+// ProvideKafkaConfig provides Kafka configuration for the order service consumer group
+func ProvideKafkaConfig() *kafka.Config {
+	return kafka.LoadServiceConfig("order-service")
+}
+
+// ProvideKafkaPublisher provides Kafka event publisher
+func ProvideKafkaPublisher(cfg *kafka.Config) (kafka.EventPublisher, error) {
+	return kafka.NewPublisher(cfg.GetPublisherConfig())
+}
+
+// ProvideKafkaConsumer provides Kafka event consumer that skips already processed events,
+// following payment events to move orders through their lifecycle
+func ProvideKafkaConsumer(cfg *kafka.Config, db *gorm.DB, transactor *gormtx.Transactor) (kafka.EventConsumer, error) {
+	consumer, err := kafka.NewConsumer(cfg.GetConsumerConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	store := kafka.NewPostgresProcessedEventStore(db, transactor, cfg.DedupRetention)
+	consumer.Use(kafka.Dedup(cfg.GroupID, store))
+	return consumer, nil
+}
+
+// ProvideOutboxRelay provides the relay publishing outbox events to Kafka
+func ProvideOutboxRelay(db *gorm.DB, publisher kafka.EventPublisher) *outbox.Relay {
+	return outbox.NewRelay(db, publisher, outbox.LoadRelayConfig("order-service"), outbox.NewMetrics("order_service"))
+}
@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ddd-micro/internal/order/infrastructure/client"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// AuthInterceptor handles authentication for gRPC requests
+type AuthInterceptor struct {
+	userClient client.UserClient
+}
+
+// NewAuthInterceptor creates a new auth interceptor
+func NewAuthInterceptor(userClient client.UserClient) *AuthInterceptor {
+	return &AuthInterceptor{
+		userClient: userClient,
+	}
+}
+
+// UnaryAuthInterceptor returns a unary server interceptor for authentication
+func (a *AuthInterceptor) UnaryAuthInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		// Extract token from metadata
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			return nil, status.Errorf(codes.Unauthenticated, "metadata not provided")
+		}
+
+		authHeader := md.Get("authorization")
+		if len(authHeader) == 0 {
+			return nil, status.Errorf(codes.Unauthenticated, "authorization header not provided")
+		}
+
+		token := strings.TrimPrefix(authHeader[0], "Bearer ")
+		if token == authHeader[0] {
+			return nil, status.Errorf(codes.Unauthenticated, "invalid authorization header format")
+		}
+
+		// Validate token with user service
+		user, err := a.userClient.ValidateToken(ctx, token)
+		if err != nil {
+			return nil, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+		}
+
+		// Add user info to context
+		ctx = context.WithValue(ctx, "user_id", uint(user.Id))
+		ctx = context.WithValue(ctx, "user_role", string(user.Role))
+		ctx = context.WithValue(ctx, "user_email", user.Email)
+
+		return handler(ctx, req)
+	}
+}
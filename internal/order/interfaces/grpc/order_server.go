@@ -0,0 +1,205 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	orderpb "github.com/ddd-micro/api/proto/order"
+	"github.com/ddd-micro/internal/order/application"
+	"github.com/ddd-micro/internal/order/application/dto"
+	"github.com/ddd-micro/internal/order/domain"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// OrderServer implements the gRPC OrderService
+type OrderServer struct {
+	orderpb.UnimplementedOrderServiceServer
+	orderService *application.OrderServiceCQRS
+}
+
+// NewOrderServer creates a new gRPC order server
+func NewOrderServer(orderService *application.OrderServiceCQRS) *OrderServer {
+	return &OrderServer{
+		orderService: orderService,
+	}
+}
+
+// GetOrder retrieves an order of a user
+func (s *OrderServer) GetOrder(ctx context.Context, req *orderpb.GetOrderRequest) (*orderpb.OrderResponse, error) {
+	order, err := s.orderService.GetOrder(ctx, uint(req.UserId), req.OrderId)
+	if err != nil {
+		return nil, toStatusError("failed to get order", err)
+	}
+
+	return toProtoOrder(order), nil
+}
+
+// ListOrders lists the orders of a user
+func (s *OrderServer) ListOrders(ctx context.Context, req *orderpb.ListOrdersRequest) (*orderpb.ListOrdersResponse, error) {
+	orders, err := s.orderService.ListOrders(ctx, dto.ListOrdersRequest{
+		UserID: uint(req.UserId),
+		Page:   int(req.Page),
+		Limit:  int(req.Limit),
+		Status: req.Status,
+	})
+	if err != nil {
+		return nil, toStatusError("failed to list orders", err)
+	}
+
+	return toProtoOrderList(orders), nil
+}
+
+// CancelOrder cancels an unpaid order of a user
+func (s *OrderServer) CancelOrder(ctx context.Context, req *orderpb.CancelOrderRequest) (*orderpb.OrderResponse, error) {
+	order, err := s.orderService.CancelOrder(ctx, uint(req.UserId), req.OrderId, dto.CancelOrderRequest{Reason: req.Reason})
+	if err != nil {
+		return nil, toStatusError("failed to cancel order", err)
+	}
+
+	return toProtoOrder(order), nil
+}
+
+// AdminGetOrder retrieves any order (admin only)
+func (s *OrderServer) AdminGetOrder(ctx context.Context, req *orderpb.AdminGetOrderRequest) (*orderpb.OrderResponse, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	order, err := s.orderService.AdminGetOrder(ctx, req.OrderId)
+	if err != nil {
+		return nil, toStatusError("failed to get order", err)
+	}
+
+	return toProtoOrder(order), nil
+}
+
+// AdminListOrders lists all orders (admin only)
+func (s *OrderServer) AdminListOrders(ctx context.Context, req *orderpb.AdminListOrdersRequest) (*orderpb.ListOrdersResponse, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	appReq := dto.AdminListOrdersRequest{
+		Page:   int(req.Page),
+		Limit:  int(req.Limit),
+		Status: req.Status,
+	}
+	if req.UserId != 0 {
+		userID := uint(req.UserId)
+		appReq.UserID = &userID
+	}
+
+	orders, err := s.orderService.AdminListOrders(ctx, appReq)
+	if err != nil {
+		return nil, toStatusError("failed to list orders", err)
+	}
+
+	return toProtoOrderList(orders), nil
+}
+
+// Helper functions
+
+func toStatusError(msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrOrderNotFound):
+		return status.Errorf(codes.NotFound, "order not found")
+	case errors.Is(err, domain.ErrInvalidStatus):
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	case errors.Is(err, domain.ErrOrderCannotBeCancelled),
+		errors.Is(err, domain.ErrInvalidStatusTransition):
+		return status.Errorf(codes.FailedPrecondition, "%v", err)
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", msg, err)
+	}
+}
+
+func toProtoOrder(order *dto.OrderResponse) *orderpb.OrderResponse {
+	items := make([]*orderpb.OrderItem, len(order.Items))
+	for i, item := range order.Items {
+		items[i] = &orderpb.OrderItem{
+			Id:              uint32(item.ID),
+			ProductId:       uint32(item.ProductID),
+			VariantId:       uintToUint32Ptr(item.VariantID),
+			Sku:             item.SKU,
+			Name:            item.Name,
+			Quantity:        int32(item.Quantity),
+			UnitPriceMinor:  item.UnitPriceMinor,
+			TotalPriceMinor: item.TotalPriceMinor,
+		}
+	}
+
+	resp := &orderpb.OrderResponse{
+		Id:                 order.ID,
+		UserId:             uint32(order.UserID),
+		BasketId:           order.BasketID,
+		Status:             order.Status,
+		Items:              items,
+		ItemCount:          int32(order.ItemCount),
+		TotalMinor:         order.TotalMinor,
+		Currency:           order.Currency,
+		ShippingAddress:    toProtoAddress(order.ShippingAddress),
+		BillingAddress:     toProtoAddress(order.BillingAddress),
+		PaymentError:       order.PaymentError,
+		RefundedMinor:      order.RefundedMinor,
+		CancellationReason: order.CancellationReason,
+		CreatedAt:          timestamppb.New(order.CreatedAt),
+		UpdatedAt:          timestamppb.New(order.UpdatedAt),
+	}
+	if order.PaymentID != nil {
+		resp.PaymentId = *order.PaymentID
+	}
+	if order.PaidAt != nil {
+		resp.PaidAt = timestamppb.New(*order.PaidAt)
+	}
+	if order.CancelledAt != nil {
+		resp.CancelledAt = timestamppb.New(*order.CancelledAt)
+	}
+
+	return resp
+}
+
+func toProtoAddress(address dto.Address) *orderpb.Address {
+	return &orderpb.Address{
+		Name:    address.Name,
+		Address: address.Address,
+		City:    address.City,
+		State:   address.State,
+		ZipCode: address.ZipCode,
+		Country: address.Country,
+		Phone:   address.Phone,
+	}
+}
+
+func toProtoOrderList(list *dto.OrderListResponse) *orderpb.ListOrdersResponse {
+	orders := make([]*orderpb.OrderResponse, len(list.Orders))
+	for i := range list.Orders {
+		orders[i] = toProtoOrder(&list.Orders[i])
+	}
+
+	return &orderpb.ListOrdersResponse{
+		Orders:     orders,
+		Total:      int32(list.Total),
+		Page:       int32(list.Page),
+		Limit:      int32(list.Limit),
+		TotalPages: int32(list.TotalPages),
+	}
+}
+
+// Helper function to convert *uint to *uint32
+func uintToUint32Ptr(i *uint) *uint32 {
+	if i == nil {
+		return nil
+	}
+	val := uint32(*i)
+	return &val
+}
+
+func requireAdmin(ctx context.Context) error {
+	role, ok := ctx.Value("user_role").(string)
+	if !ok || role != "admin" {
+		return status.Errorf(codes.PermissionDenied, "admin access required")
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package grpc
+
+import (
+	orderpb "github.com/ddd-micro/api/proto/order"
+	"github.com/google/wire"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+// Providers contains all gRPC-related dependencies
+type Providers struct {
+	OrderServer     *OrderServer
+	AuthInterceptor *AuthInterceptor
+	GRPCServer      *grpc.Server
+}
+
+// ProviderSet is the Wire provider set for gRPC
+var ProviderSet = wire.NewSet(
+	NewOrderServer,
+	NewAuthInterceptor,
+	NewGRPCServer,
+	NewProviders,
+)
+
+// NewProviders creates new gRPC providers
+func NewProviders(
+	orderServer *OrderServer,
+	authInterceptor *AuthInterceptor,
+	grpcServer *grpc.Server,
+) *Providers {
+	return &Providers{
+		OrderServer:     orderServer,
+		AuthInterceptor: authInterceptor,
+		GRPCServer:      grpcServer,
+	}
+}
+
+// NewGRPCServer creates a new gRPC server with interceptors
+func NewGRPCServer(
+	orderServer *OrderServer,
+	authInterceptor *AuthInterceptor,
+) *grpc.Server {
+	// Create gRPC server with interceptors
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(authInterceptor.UnaryAuthInterceptor()),
+	)
+
+	// Register order service
+	orderpb.RegisterOrderServiceServer(server, orderServer)
+
+	// Enable reflection for debugging
+	reflection.Register(server)
+
+	return server
+}
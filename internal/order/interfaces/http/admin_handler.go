@@ -0,0 +1,158 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ddd-micro/internal/order/application"
+	"github.com/ddd-micro/internal/order/application/dto"
+	"github.com/ddd-micro/internal/order/infrastructure/monitoring"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler handles admin-related order HTTP requests
+type AdminHandler struct {
+	orderService *application.OrderServiceCQRS
+	metrics      *monitoring.PrometheusMetrics
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(orderService *application.OrderServiceCQRS, metrics *monitoring.PrometheusMetrics) *AdminHandler {
+	return &AdminHandler{
+		orderService: orderService,
+		metrics:      metrics,
+	}
+}
+
+// ListAllOrders lists all orders (admin only)
+// @Summary List all orders
+// @Description Get a list of all orders in the system, newest first (admin only)
+// @Tags admin-orders
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param user_id query int false "Filter by user ID"
+// @Param status query string false "Filter by status"
+// @Success 200 {object} dto.OrderListResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/orders [get]
+func (h *AdminHandler) ListAllOrders(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	userIDStr := c.Query("user_id")
+	status := c.Query("status")
+
+	var userID *uint
+	if userIDStr != "" {
+		if id, err := strconv.ParseUint(userIDStr, 10, 32); err == nil {
+			uid := uint(id)
+			userID = &uid
+		}
+	}
+
+	req := dto.AdminListOrdersRequest{
+		Page:   page,
+		Limit:  limit,
+		UserID: userID,
+		Status: status,
+	}
+
+	orders, err := h.orderService.AdminListOrders(c.Request.Context(), req)
+	if err != nil {
+		writeOrderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, orders)
+}
+
+// GetOrderByID gets any order by ID (admin only)
+// @Summary Get order by ID (admin)
+// @Description Get order details by ID (admin only)
+// @Tags admin-orders
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} dto.OrderResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/orders/{id} [get]
+func (h *AdminHandler) GetOrderByID(c *gin.Context) {
+	orderID := c.Param("id")
+
+	order, err := h.orderService.AdminGetOrder(c.Request.Context(), orderID)
+	if err != nil {
+		writeOrderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// CancelOrder cancels any unpaid order (admin only)
+// @Summary Cancel order (admin)
+// @Description Cancel a pending order or an order whose payment failed (admin only)
+// @Tags admin-orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body dto.CancelOrderRequest false "Order cancellation request"
+// @Success 200 {object} dto.OrderResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/orders/{id}/cancel [post]
+func (h *AdminHandler) CancelOrder(c *gin.Context) {
+	adminID := c.GetUint("user_id")
+	orderID := c.Param("id")
+
+	var req dto.CancelOrderRequest
+	if err := bindOptionalJSON(c, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := h.orderService.AdminCancelOrder(c.Request.Context(), adminID, orderID, req)
+	if err != nil {
+		writeOrderError(c, err)
+		return
+	}
+
+	h.metrics.RecordOrderCancellation()
+	c.JSON(http.StatusOK, order)
+}
+
+// GetOrderHistory gets the status history of an order (admin only)
+// @Summary Get order status history
+// @Description Get every status transition of an order with its actor and reason (admin only)
+// @Tags admin-orders
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} dto.OrderStatusHistoryListResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/orders/{id}/history [get]
+func (h *AdminHandler) GetOrderHistory(c *gin.Context) {
+	orderID := c.Param("id")
+
+	history, err := h.orderService.AdminGetOrderHistory(c.Request.Context(), orderID)
+	if err != nil {
+		writeOrderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
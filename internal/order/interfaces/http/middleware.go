@@ -0,0 +1,62 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ddd-micro/internal/order/infrastructure/client"
+	"github.com/gin-gonic/gin"
+)
+
+// AuthMiddleware handles JWT authentication
+func AuthMiddleware(userClient client.UserClient) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+			c.Abort()
+			return
+		}
+
+		token := strings.TrimPrefix(authHeader, "Bearer ")
+		if token == authHeader {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
+			c.Abort()
+			return
+		}
+
+		user, err := userClient.ValidateToken(c.Request.Context(), token)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			c.Abort()
+			return
+		}
+
+		// Add user info to context
+		c.Set("user_id", uint(user.Id))
+		c.Set("user_role", user.Role)
+		c.Set("user_email", user.Email)
+
+		c.Next()
+	}
+}
+
+// AdminOnlyMiddleware ensures only admin users can access
+func AdminOnlyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userRole, exists := c.Get("user_role")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			c.Abort()
+			return
+		}
+
+		if userRole != "admin" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,208 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ddd-micro/internal/order/application"
+	"github.com/ddd-micro/internal/order/application/dto"
+	"github.com/ddd-micro/internal/order/domain"
+	"github.com/ddd-micro/internal/order/infrastructure/monitoring"
+	"github.com/gin-gonic/gin"
+)
+
+// OrderHandler handles order-related HTTP requests
+type OrderHandler struct {
+	orderService *application.OrderServiceCQRS
+	metrics      *monitoring.PrometheusMetrics
+}
+
+// NewOrderHandler creates a new order handler
+func NewOrderHandler(orderService *application.OrderServiceCQRS, metrics *monitoring.PrometheusMetrics) *OrderHandler {
+	return &OrderHandler{
+		orderService: orderService,
+		metrics:      metrics,
+	}
+}
+
+// PlaceOrder places an order for the items of the user's basket
+// @Summary Place an order
+// @Description Place a pending order for the items of the authenticated user's basket, snapshotting items, prices and addresses
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.PlaceOrderRequest true "Order placement request"
+// @Success 201 {object} dto.OrderResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /orders [post]
+func (h *OrderHandler) PlaceOrder(c *gin.Context) {
+	// Start tracing span
+	span, _ := monitoring.StartSpanFromGinContext(c, "order.place")
+	defer span.Finish()
+
+	userID := c.GetUint("user_id")
+
+	var req dto.PlaceOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		monitoring.LogSpanError(span, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	start := time.Now()
+	order, err := h.orderService.PlaceOrder(c.Request.Context(), userID, req)
+	duration := time.Since(start)
+
+	// Record database query duration
+	h.metrics.RecordDatabaseQueryDuration("place_order", "orders", duration)
+
+	if err != nil {
+		monitoring.LogSpanError(span, err)
+		h.metrics.RecordOrderPlacementError()
+		writeOrderError(c, err)
+		return
+	}
+
+	// Record successful order placement
+	h.metrics.RecordOrderPlacement()
+	monitoring.SetSpanTags(span, map[string]interface{}{
+		"order.id":    order.ID,
+		"order.total": order.Total,
+		"success":     true,
+	})
+
+	c.JSON(http.StatusCreated, order)
+}
+
+// ListOrders lists the orders of the user
+// @Summary List user orders
+// @Description Get the orders of the authenticated user, newest first
+// @Tags orders
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param status query string false "Filter by status"
+// @Success 200 {object} dto.OrderListResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /orders [get]
+func (h *OrderHandler) ListOrders(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	req := dto.ListOrdersRequest{
+		UserID: userID,
+		Page:   page,
+		Limit:  limit,
+		Status: c.Query("status"),
+	}
+
+	orders, err := h.orderService.ListOrders(c.Request.Context(), req)
+	if err != nil {
+		writeOrderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, orders)
+}
+
+// GetOrder gets an order of the user by ID
+// @Summary Get order by ID
+// @Description Get order details by ID for the authenticated user
+// @Tags orders
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} dto.OrderResponse
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /orders/{id} [get]
+func (h *OrderHandler) GetOrder(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	orderID := c.Param("id")
+
+	order, err := h.orderService.GetOrder(c.Request.Context(), userID, orderID)
+	if err != nil {
+		writeOrderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// CancelOrder cancels an unpaid order of the user
+// @Summary Cancel an order
+// @Description Cancel a pending order or an order whose payment failed; paid orders are refunded through the payment service
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body dto.CancelOrderRequest false "Order cancellation request"
+// @Success 200 {object} dto.OrderResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /orders/{id}/cancel [post]
+func (h *OrderHandler) CancelOrder(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	orderID := c.Param("id")
+
+	var req dto.CancelOrderRequest
+	if err := bindOptionalJSON(c, &req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := h.orderService.CancelOrder(c.Request.Context(), userID, orderID, req)
+	if err != nil {
+		writeOrderError(c, err)
+		return
+	}
+
+	h.metrics.RecordOrderCancellation()
+	c.JSON(http.StatusOK, order)
+}
+
+// bindOptionalJSON binds the JSON body of a request, if it has one
+func bindOptionalJSON(c *gin.Context, obj interface{}) error {
+	if c.Request.ContentLength == 0 {
+		return nil
+	}
+	return c.ShouldBindJSON(obj)
+}
+
+// writeOrderError maps order errors to HTTP responses
+func writeOrderError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrOrderNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+	case errors.Is(err, domain.ErrInvalidStatus),
+		errors.Is(err, domain.ErrInvalidAddress),
+		errors.Is(err, domain.ErrInvalidCurrency):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrOrderCannotBeCancelled),
+		errors.Is(err, domain.ErrInvalidStatusTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, application.ErrBasketEmpty),
+		errors.Is(err, application.ErrProductUnavailable),
+		errors.Is(err, domain.ErrEmptyOrder),
+		errors.Is(err, domain.ErrInvalidQuantity),
+		errors.Is(err, domain.ErrInvalidPrice):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
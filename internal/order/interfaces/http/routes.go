@@ -0,0 +1,67 @@
+package http
+
+import (
+	"github.com/ddd-micro/internal/order/application"
+	"github.com/ddd-micro/internal/order/infrastructure/client"
+	"github.com/ddd-micro/internal/order/infrastructure/monitoring"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+)
+
+// SetupRoutes sets up all HTTP routes
+func SetupRoutes(
+	router *gin.Engine,
+	orderService *application.OrderServiceCQRS,
+	userClient client.UserClient,
+	metrics *monitoring.PrometheusMetrics,
+	tracer *monitoring.JaegerTracer,
+) {
+	// Add monitoring middlewares
+	router.Use(monitoring.PrometheusMiddleware(metrics))
+	router.Use(monitoring.JaegerMiddleware(tracer))
+
+	// Prometheus metrics endpoint
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	// Swagger documentation
+	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	// Initialize handlers
+	orderHandler := NewOrderHandler(orderService, metrics)
+	adminHandler := NewAdminHandler(orderService, metrics)
+
+	// Initialize middleware
+	authMiddleware := AuthMiddleware(userClient)
+	adminOnlyMiddleware := AdminOnlyMiddleware()
+
+	// User routes (authentication required)
+	user := router.Group("/api/v1")
+	user.Use(authMiddleware)
+	{
+		// Order routes
+		orders := user.Group("/orders")
+		{
+			orders.POST("", orderHandler.PlaceOrder)             // POST /api/v1/orders
+			orders.GET("", orderHandler.ListOrders)              // GET /api/v1/orders
+			orders.GET("/:id", orderHandler.GetOrder)            // GET /api/v1/orders/:id
+			orders.POST("/:id/cancel", orderHandler.CancelOrder) // POST /api/v1/orders/:id/cancel
+		}
+	}
+
+	// Admin routes (admin authentication required)
+	admin := router.Group("/api/v1/admin")
+	admin.Use(authMiddleware)
+	admin.Use(adminOnlyMiddleware)
+	{
+		// Admin order routes
+		adminOrders := admin.Group("/orders")
+		{
+			adminOrders.GET("", adminHandler.ListAllOrders)               // GET /api/v1/admin/orders
+			adminOrders.GET("/:id", adminHandler.GetOrderByID)            // GET /api/v1/admin/orders/:id
+			adminOrders.POST("/:id/cancel", adminHandler.CancelOrder)     // POST /api/v1/admin/orders/:id/cancel
+			adminOrders.GET("/:id/history", adminHandler.GetOrderHistory) // GET /api/v1/admin/orders/:id/history
+		}
+	}
+}
@@ -0,0 +1,621 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	productpb "github.com/ddd-micro/api/proto/product"
+	"github.com/ddd-micro/internal/payment/application/command"
+	"github.com/ddd-micro/internal/payment/application/dto"
+	"github.com/ddd-micro/internal/payment/domain"
+	"github.com/ddd-micro/internal/payment/infrastructure/client"
+	"github.com/ddd-micro/internal/payment/infrastructure/config"
+	paymentkafka "github.com/ddd-micro/internal/payment/infrastructure/kafka"
+	"github.com/ddd-micro/pkg/catalog"
+	"github.com/ddd-micro/pkg/gormtx"
+	"github.com/ddd-micro/pkg/money"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CheckoutOrchestrator runs checkout sagas: it validates the basket, reserves its stock
+// and creates the payment, then waits for the payment and basket events to confirm the
+// payment and clear the basket. A failed step compensates the steps before it:
+//
+//	validate_basket  reads only, nothing to undo
+//	reserve_stock    releases the stock reservation of the payment
+//	create_payment   cancels the payment; a payment that was taken meanwhile fails the
+//	                 checkout for manual review, keeping its stock for the payment
+//	confirm_payment  nothing of its own; the payment is cancelled by create_payment
+//	clear_basket     runs after the payment was taken, so it is retried instead
+//
+// Recovery resumes sagas left behind by a crash, retries failed steps and times out
+// steps that waited too long for their event.
+type CheckoutOrchestrator struct {
+	sagaRepo             domain.CheckoutSagaRepository
+	paymentRepo          domain.PaymentRepository
+	createPaymentHandler *command.CreatePaymentCommandHandler
+	cancelPaymentHandler *command.CancelPaymentCommandHandler
+	productClient        client.ProductClient
+	basketClient         client.BasketClient
+	eventPublisher       *paymentkafka.PaymentEventPublisher
+	transactor           *gormtx.Transactor
+	config               config.CheckoutConfig
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewCheckoutOrchestrator creates a new checkout orchestrator
+func NewCheckoutOrchestrator(
+	sagaRepo domain.CheckoutSagaRepository,
+	paymentRepo domain.PaymentRepository,
+	createPaymentHandler *command.CreatePaymentCommandHandler,
+	cancelPaymentHandler *command.CancelPaymentCommandHandler,
+	productClient client.ProductClient,
+	basketClient client.BasketClient,
+	eventPublisher *paymentkafka.PaymentEventPublisher,
+	transactor *gormtx.Transactor,
+	cfg *config.Config,
+) *CheckoutOrchestrator {
+	return &CheckoutOrchestrator{
+		sagaRepo:             sagaRepo,
+		paymentRepo:          paymentRepo,
+		createPaymentHandler: createPaymentHandler,
+		cancelPaymentHandler: cancelPaymentHandler,
+		productClient:        productClient,
+		basketClient:         basketClient,
+		eventPublisher:       eventPublisher,
+		transactor:           transactor,
+		config:               cfg.Checkout,
+	}
+}
+
+// Checkout starts a checkout saga for the user's basket and runs it up to the payment.
+// The response carries the created payment; when a step fails, the saga is compensated
+// and the error is returned.
+func (o *CheckoutOrchestrator) Checkout(ctx context.Context, userID uint, req dto.CheckoutRequest) (*dto.CheckoutResponse, error) {
+	amount, err := money.FromMajor(req.Amount, req.Currency)
+	if err != nil {
+		if errors.Is(err, money.ErrUnknownCurrency) {
+			return nil, domain.ErrInvalidCurrency
+		}
+		return nil, domain.ErrInvalidAmount
+	}
+
+	saga := &domain.CheckoutSaga{
+		ID:              uuid.New().String(),
+		UserID:          userID,
+		BasketID:        req.BasketID,
+		OrderID:         req.OrderID,
+		PaymentID:       uuid.New().String(),
+		AmountMinor:     amount.Amount,
+		Currency:        amount.Currency,
+		PaymentMethod:   req.PaymentMethod,
+		PaymentMethodID: req.PaymentMethodID,
+		ReturnURL:       req.ReturnURL,
+		CancelURL:       req.CancelURL,
+		Status:          domain.CheckoutStatusRunning,
+	}
+	saga.Advance(domain.CheckoutStepValidateBasket, o.config.StepTimeout, o.config.Lease)
+	if err := o.sagaRepo.Create(ctx, saga); err != nil {
+		return nil, err
+	}
+
+	// The saga outlives the request: a client that disconnects must not leave it half done
+	ctx = context.WithoutCancel(ctx)
+
+	payment, err := o.run(ctx, saga)
+	if err != nil {
+		o.fail(ctx, saga, err, false)
+		return nil, fmt.Errorf("checkout %s failed at %s: %w", saga.ID, saga.Step, err)
+	}
+
+	response := toCheckoutResponse(saga)
+	response.Payment = payment
+	return response, nil
+}
+
+// GetCheckout gets the state of a checkout of the user
+func (o *CheckoutOrchestrator) GetCheckout(ctx context.Context, userID uint, sagaID string) (*dto.CheckoutResponse, error) {
+	saga, err := o.sagaRepo.GetByID(ctx, sagaID)
+	if err != nil {
+		return nil, err
+	}
+
+	if saga.UserID != userID {
+		return nil, domain.ErrCheckoutNotFound
+	}
+
+	return toCheckoutResponse(saga), nil
+}
+
+// run executes the steps of a running saga until it waits for an event. It returns the
+// payment created on the way, if any.
+func (o *CheckoutOrchestrator) run(ctx context.Context, saga *domain.CheckoutSaga) (*dto.PaymentResponse, error) {
+	var payment *dto.PaymentResponse
+	for saga.IsRunning() {
+		var err error
+		switch saga.Step {
+		case domain.CheckoutStepValidateBasket:
+			err = o.validateBasket(ctx, saga)
+		case domain.CheckoutStepReserveStock:
+			err = o.reserveStock(ctx, saga)
+		case domain.CheckoutStepCreatePayment:
+			payment, err = o.createPayment(ctx, saga)
+		default:
+			// The remaining steps wait for events
+			return payment, nil
+		}
+		if err != nil {
+			return payment, err
+		}
+	}
+	return payment, nil
+}
+
+// validateBasket checks the basket against the checkout amount and takes its items into the
+// saga. Items are priced by the product service, not by the prices stored in the basket.
+func (o *CheckoutOrchestrator) validateBasket(ctx context.Context, saga *domain.CheckoutSaga) error {
+	basket, err := o.basketClient.ValidateBasket(ctx, saga.UserID)
+	if err != nil {
+		return fmt.Errorf("basket validation failed: %w", err)
+	}
+	if basket.Id != saga.BasketID {
+		return fmt.Errorf("%w: basket %s is not the current basket of the user", ErrCheckoutRejected, saga.BasketID)
+	}
+
+	products := make(map[uint]*productpb.Product)
+	total := money.Zero(saga.Currency)
+	items := make([]domain.CheckoutItem, 0, len(basket.Items))
+	for _, item := range basket.Items {
+		productID := uint(item.ProductId)
+		product, ok := products[productID]
+		if !ok {
+			if product, err = o.productClient.GetProduct(ctx, productID); err != nil {
+				return fmt.Errorf("failed to price product %d: %w", productID, err)
+			}
+			products[productID] = product
+		}
+
+		if !product.IsActive {
+			return fmt.Errorf("%w: product %d is not active", ErrCheckoutRejected, productID)
+		}
+		variantID := client.BasketItemVariantID(item)
+		unitPrice, err := catalog.ItemPrice(product, variantID)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrCheckoutRejected, err)
+		}
+		if total, err = total.Add(unitPrice.Multiply(int64(item.Quantity))); err != nil {
+			return fmt.Errorf("%w: payment currency does not match product currency", ErrCheckoutRejected)
+		}
+		items = append(items, domain.CheckoutItem{
+			ProductID:      productID,
+			VariantID:      variantID,
+			Quantity:       int(item.Quantity),
+			UnitPriceMinor: unitPrice.Amount,
+		})
+	}
+	if total.Amount != saga.AmountMinor {
+		return fmt.Errorf("%w: payment amount does not match basket total", ErrCheckoutRejected)
+	}
+
+	saga.Items = items
+	saga.Advance(domain.CheckoutStepReserveStock, o.config.StepTimeout, o.config.Lease)
+	return o.sagaRepo.Update(ctx, saga)
+}
+
+// reserveStock holds the basket items in a stock reservation keyed by the payment ID. The
+// product service replaces the reservation of the same payment, so a retry after a lost
+// response or a crash holds the items once. The reservation outlives the payment timeout;
+// it is confirmed when the payment completes and released when the checkout fails.
+func (o *CheckoutOrchestrator) reserveStock(ctx context.Context, saga *domain.CheckoutSaga) error {
+	items := make([]client.ReservationItem, 0, len(saga.Items))
+	for _, item := range saga.Items {
+		items = append(items, client.ReservationItem{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	ttl := o.config.PaymentTimeout + o.config.StepTimeout
+	if err := o.productClient.ReserveStock(ctx, saga.PaymentID, items, ttl); err != nil {
+		return fmt.Errorf("failed to reserve stock for payment %s: %w", saga.PaymentID, err)
+	}
+
+	for i := range saga.Items {
+		saga.Items[i].Reserved = true
+	}
+	saga.Advance(domain.CheckoutStepCreatePayment, o.config.StepTimeout, o.config.Lease)
+	return o.sagaRepo.Update(ctx, saga)
+}
+
+// createPayment creates the payment of the checkout under the ID assigned to the saga,
+// so a retry after a crash finds the payment created before
+func (o *CheckoutOrchestrator) createPayment(ctx context.Context, saga *domain.CheckoutSaga) (*dto.PaymentResponse, error) {
+	var payment *dto.PaymentResponse
+	_, err := o.paymentRepo.GetByID(ctx, saga.PaymentID)
+	switch {
+	case err == nil:
+		// Created before the saga was stored; the payment URL is only returned on creation
+	case errors.Is(err, domain.ErrPaymentNotFound):
+		payment, err = o.createPaymentHandler.Handle(ctx, command.CreatePaymentCommand{
+			PaymentID:       saga.PaymentID,
+			UserID:          saga.UserID,
+			OrderID:         saga.OrderID,
+			Amount:          saga.Amount(),
+			PaymentMethod:   saga.PaymentMethod,
+			PaymentMethodID: saga.PaymentMethodID,
+			ReturnURL:       saga.ReturnURL,
+			CancelURL:       saga.CancelURL,
+			BasketID:        &saga.BasketID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create payment: %w", err)
+		}
+	default:
+		return nil, err
+	}
+
+	// Recovery looks at the saga again when the payment times out
+	saga.Advance(domain.CheckoutStepConfirmPayment, o.config.PaymentTimeout, o.config.PaymentTimeout)
+	if err := o.sagaRepo.Update(ctx, saga); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// PaymentCompleted moves the checkout of a completed payment on to clearing the basket
+func (o *CheckoutOrchestrator) PaymentCompleted(ctx context.Context, paymentID string) error {
+	saga, err := o.awaiting(ctx, paymentID, domain.CheckoutStepConfirmPayment)
+	if saga == nil || err != nil {
+		return err
+	}
+
+	// Recovery clears the basket itself if the basket cleared event does not arrive in time
+	saga.Advance(domain.CheckoutStepClearBasket, o.config.BasketTimeout, o.config.BasketTimeout)
+	return o.sagaRepo.Update(ctx, saga)
+}
+
+// PaymentAborted compensates the checkout of a failed or cancelled payment
+func (o *CheckoutOrchestrator) PaymentAborted(ctx context.Context, paymentID, reason string) error {
+	saga, err := o.awaiting(ctx, paymentID, domain.CheckoutStepConfirmPayment)
+	if saga == nil || err != nil {
+		return err
+	}
+
+	saga.Compensate(reason, o.config.Lease)
+	if err := o.sagaRepo.Update(ctx, saga); err != nil {
+		return err
+	}
+	return o.compensate(ctx, saga)
+}
+
+// BasketCleared completes the checkout whose basket was cleared. The basket is only cleared
+// for a completed payment, so this may also arrive before the payment completed event.
+func (o *CheckoutOrchestrator) BasketCleared(ctx context.Context, paymentID string) error {
+	saga, err := o.awaiting(ctx, paymentID, domain.CheckoutStepConfirmPayment, domain.CheckoutStepClearBasket)
+	if saga == nil || err != nil {
+		return err
+	}
+
+	saga.Complete()
+	return o.sagaRepo.Update(ctx, saga)
+}
+
+// awaiting gets the running saga of a payment waiting at one of the given steps. It returns
+// nil for payments without a checkout and for events the saga is already past, and an error
+// for events that arrive before the saga reached the steps, so they are retried.
+func (o *CheckoutOrchestrator) awaiting(ctx context.Context, paymentID string, steps ...domain.CheckoutStep) (*domain.CheckoutSaga, error) {
+	saga, err := o.sagaRepo.GetByPaymentID(ctx, paymentID)
+	if errors.Is(err, domain.ErrCheckoutNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !saga.IsRunning() {
+		return nil, nil
+	}
+	for _, step := range steps {
+		if saga.Step == step {
+			return saga, nil
+		}
+	}
+	if saga.Step.Before(steps[0]) {
+		return nil, fmt.Errorf("checkout %s has not reached %s yet", saga.ID, steps[0])
+	}
+	return nil, nil
+}
+
+// compensate undoes the steps of a compensating saga in reverse order. A failed
+// compensation is retried by recovery until it succeeds, unless the step cannot be
+// undone at all: then the saga fails and is left to manual review.
+func (o *CheckoutOrchestrator) compensate(ctx context.Context, saga *domain.CheckoutSaga) error {
+	for saga.IsCompensating() {
+		var err error
+		switch saga.Step {
+		case domain.CheckoutStepReserveStock:
+			err = o.releaseStock(ctx, saga)
+		case domain.CheckoutStepCreatePayment:
+			err = o.cancelPayment(ctx, saga)
+		}
+		if errors.Is(err, ErrCheckoutNeedsReview) {
+			saga.Fail(err)
+			if err := o.sagaRepo.Update(ctx, saga); err != nil {
+				return err
+			}
+			log.Printf("Checkout %s failed at %s and needs manual review: %v", saga.ID, saga.Step, err)
+			return nil
+		}
+		if err != nil {
+			return o.retry(ctx, saga, err)
+		}
+
+		saga.StepBack(o.config.Lease)
+		if err := o.sagaRepo.Update(ctx, saga); err != nil {
+			return err
+		}
+	}
+
+	if saga.Status == domain.CheckoutStatusCompensated {
+		log.Printf("Checkout %s compensated: %s", saga.ID, saga.FailureReason)
+	}
+	return nil
+}
+
+// releaseStock gives back the stock reservation of the checkout. A reservation that is not
+// active any more was released with a failed or cancelled payment, or expired.
+func (o *CheckoutOrchestrator) releaseStock(ctx context.Context, saga *domain.CheckoutSaga) error {
+	err := o.productClient.ReleaseReservation(ctx, saga.PaymentID)
+	if err != nil && status.Code(err) != codes.NotFound {
+		return fmt.Errorf("failed to release stock reserved for payment %s: %w", saga.PaymentID, err)
+	}
+
+	for i := range saga.Items {
+		saga.Items[i].Reserved = false
+	}
+	return nil
+}
+
+// cancelPayment cancels the payment of the checkout unless it never got created or already
+// ended. A payment that was taken in the meantime cannot be cancelled: the customer paid
+// for the reserved stock, which the product service confirms, so whether to refund or to
+// fulfil the order is left to manual review.
+func (o *CheckoutOrchestrator) cancelPayment(ctx context.Context, saga *domain.CheckoutSaga) error {
+	payment, err := o.paymentRepo.GetByID(ctx, saga.PaymentID)
+	if errors.Is(err, domain.ErrPaymentNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !payment.CanBeCancelled() {
+		if payment.IsCompleted() || payment.IsPartiallyRefunded() || payment.IsRefunded() {
+			return fmt.Errorf("%w: payment %s was %s during compensation", ErrCheckoutNeedsReview, payment.ID, payment.Status)
+		}
+		return nil
+	}
+
+	return o.transactor.Within(ctx, func(ctx context.Context) error {
+		cmd := command.CancelPaymentCommand{
+			PaymentID: payment.ID,
+			Actor:     domain.ActorSystem,
+		}
+		if _, err := o.cancelPaymentHandler.Handle(ctx, cmd); err != nil {
+			return err
+		}
+		return o.eventPublisher.PublishPaymentCancelled(ctx, payment.ID, payment.UserID, payment.OrderID,
+			payment.Amount(), string(payment.PaymentMethod), "checkout_failed", payment.BasketID)
+	})
+}
+
+// fail handles a step that failed. Failures that may go away are retried until the step
+// times out when retryTransient is set; all other failures compensate the saga.
+func (o *CheckoutOrchestrator) fail(ctx context.Context, saga *domain.CheckoutSaga, stepErr error, retryTransient bool) {
+	if errors.Is(stepErr, domain.ErrCheckoutConflict) {
+		// Someone else works on the saga now
+		return
+	}
+
+	if retryTransient && isTransient(stepErr) && !saga.TimedOut() {
+		o.retry(ctx, saga, stepErr)
+		return
+	}
+
+	saga.Compensate(stepErr.Error(), o.config.Lease)
+	if err := o.sagaRepo.Update(ctx, saga); err != nil {
+		log.Printf("Failed to store compensation of checkout %s: %v", saga.ID, err)
+		return
+	}
+	if err := o.compensate(ctx, saga); err != nil {
+		log.Printf("Failed to compensate checkout %s at %s: %v", saga.ID, saga.Step, err)
+	}
+}
+
+// retry records a failed attempt and leaves the saga to recovery after a backoff
+func (o *CheckoutOrchestrator) retry(ctx context.Context, saga *domain.CheckoutSaga, stepErr error) error {
+	backoff := o.config.RetryBackoff << min(saga.Attempts, 20)
+	if backoff <= 0 || backoff > o.config.MaxBackoff {
+		backoff = o.config.MaxBackoff
+	}
+
+	saga.Retry(stepErr, backoff)
+	if err := o.sagaRepo.Update(ctx, saga); err != nil {
+		return err
+	}
+	return stepErr
+}
+
+// isTransient reports whether a step failure may go away when the step is retried, as when a
+// service is unreachable; rejected checkouts and errors reported by the services are final
+func isTransient(err error) bool {
+	if errors.Is(err, ErrCheckoutRejected) {
+		return false
+	}
+	switch {
+	case errors.Is(err, domain.ErrInvalidAmount),
+		errors.Is(err, domain.ErrInvalidCurrency),
+		errors.Is(err, domain.ErrInvalidOrderID),
+		errors.Is(err, domain.ErrInvalidPaymentMethod):
+		return false
+	}
+
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.Aborted, codes.ResourceExhausted:
+			return true
+		}
+		return false
+	}
+	return true
+}
+
+// Recovery
+
+// Start starts resuming due sagas in the background
+func (o *CheckoutOrchestrator) Start(ctx context.Context) error {
+	ctx, o.cancel = context.WithCancel(ctx)
+
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+
+		ticker := time.NewTicker(o.config.RecoveryInterval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := o.ResumeDue(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Checkout recovery failed: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	log.Println("Checkout recovery started")
+	return nil
+}
+
+// Stop stops recovery and waits for the sagas being resumed
+func (o *CheckoutOrchestrator) Stop() error {
+	if o.cancel != nil {
+		o.cancel()
+	}
+	o.wg.Wait()
+	log.Println("Checkout recovery stopped")
+	return nil
+}
+
+// ResumeDue resumes one batch of due sagas and returns how many were resumed
+func (o *CheckoutOrchestrator) ResumeDue(ctx context.Context) (int, error) {
+	sagas, err := o.sagaRepo.ClaimDue(ctx, o.config.Lease, o.config.RecoveryBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, saga := range sagas {
+		if err := o.resume(ctx, saga); err != nil && ctx.Err() == nil {
+			log.Printf("Checkout %s failed at %s (attempt %d): %v", saga.ID, saga.Step, saga.Attempts, err)
+		}
+	}
+	return len(sagas), nil
+}
+
+// resume continues a claimed saga: after a crash, after the backoff of a failed
+// attempt, or when a step waited for its event until it timed out
+func (o *CheckoutOrchestrator) resume(ctx context.Context, saga *domain.CheckoutSaga) error {
+	if saga.IsCompensating() {
+		return o.compensate(ctx, saga)
+	}
+
+	switch saga.Step {
+	case domain.CheckoutStepConfirmPayment:
+		return o.settlePayment(ctx, saga)
+	case domain.CheckoutStepClearBasket:
+		return o.clearBasket(ctx, saga)
+	}
+
+	if _, err := o.run(ctx, saga); err != nil {
+		o.fail(ctx, saga, err, true)
+		return err
+	}
+	return nil
+}
+
+// settlePayment decides a checkout whose payment events did not arrive in time from the
+// payment itself: a payment that was taken moves on, any other is cancelled
+func (o *CheckoutOrchestrator) settlePayment(ctx context.Context, saga *domain.CheckoutSaga) error {
+	payment, err := o.paymentRepo.GetByID(ctx, saga.PaymentID)
+	if err != nil {
+		return o.retry(ctx, saga, err)
+	}
+
+	if payment.IsCompleted() || payment.IsPartiallyRefunded() || payment.IsRefunded() {
+		saga.Advance(domain.CheckoutStepClearBasket, o.config.BasketTimeout, o.config.BasketTimeout)
+		return o.sagaRepo.Update(ctx, saga)
+	}
+
+	saga.Compensate(fmt.Sprintf("payment %s within %s", payment.Status, o.config.PaymentTimeout), o.config.Lease)
+	if err := o.sagaRepo.Update(ctx, saga); err != nil {
+		return err
+	}
+	return o.compensate(ctx, saga)
+}
+
+// clearBasket clears the basket itself when the basket cleared event did not arrive in time
+func (o *CheckoutOrchestrator) clearBasket(ctx context.Context, saga *domain.CheckoutSaga) error {
+	if err := o.basketClient.ClearBasket(ctx, saga.UserID); err != nil {
+		return o.retry(ctx, saga, err)
+	}
+
+	saga.Complete()
+	return o.sagaRepo.Update(ctx, saga)
+}
+
+// toCheckoutResponse converts a checkout saga to its DTO
+func toCheckoutResponse(saga *domain.CheckoutSaga) *dto.CheckoutResponse {
+	items := make([]dto.CheckoutItemResponse, 0, len(saga.Items))
+	for _, item := range saga.Items {
+		items = append(items, dto.CheckoutItemResponse{
+			ProductID:      item.ProductID,
+			VariantID:      item.VariantID,
+			Quantity:       item.Quantity,
+			UnitPriceMinor: item.UnitPriceMinor,
+			Reserved:       item.Reserved,
+		})
+	}
+
+	return &dto.CheckoutResponse{
+		ID:            saga.ID,
+		UserID:        saga.UserID,
+		BasketID:      saga.BasketID,
+		OrderID:       saga.OrderID,
+		PaymentID:     saga.PaymentID,
+		Amount:        saga.Amount().Major(),
+		AmountMinor:   saga.AmountMinor,
+		Currency:      saga.Currency,
+		Status:        string(saga.Status),
+		Step:          string(saga.Step),
+		Items:         items,
+		FailureReason: saga.FailureReason,
+		LastError:     saga.LastError,
+		Attempts:      saga.Attempts,
+		StepDeadline:  saga.StepDeadline,
+		CreatedAt:     saga.CreatedAt,
+		UpdatedAt:     saga.UpdatedAt,
+		CompletedAt:   saga.CompletedAt,
+	}
+}
@@ -0,0 +1,93 @@
+package command
+
+import (
+	"context"
+
+	"github.com/ddd-micro/internal/payment/application/dto"
+	"github.com/ddd-micro/internal/payment/domain"
+)
+
+// AuthorizePaymentCommand represents the command to put a hold on the payment funds
+type AuthorizePaymentCommand struct {
+	PaymentID       string
+	PaymentMethodID string
+	Actor           string
+}
+
+// AuthorizePaymentCommandHandler handles the authorize payment command
+type AuthorizePaymentCommandHandler struct {
+	paymentRepo    domain.PaymentRepository
+	paymentGateway domain.PaymentGateway
+}
+
+// NewAuthorizePaymentCommandHandler creates a new authorize payment command handler
+func NewAuthorizePaymentCommandHandler(
+	paymentRepo domain.PaymentRepository,
+	paymentGateway domain.PaymentGateway,
+) *AuthorizePaymentCommandHandler {
+	return &AuthorizePaymentCommandHandler{
+		paymentRepo:    paymentRepo,
+		paymentGateway: paymentGateway,
+	}
+}
+
+// Handle handles the authorize payment command
+func (h *AuthorizePaymentCommandHandler) Handle(ctx context.Context, cmd AuthorizePaymentCommand) (*dto.PaymentResponse, error) {
+	// Get payment from repository
+	payment, err := h.paymentRepo.GetByID(ctx, cmd.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if payment can be authorized
+	if !payment.CanBeAuthorized() {
+		return nil, domain.ErrPaymentCannotBeAuthorized
+	}
+
+	// Authorize payment via gateway
+	gatewayResponse, err := h.paymentGateway.AuthorizePayment(ctx, payment, cmd.PaymentMethodID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update payment status
+	change := domain.StatusChange{Actor: cmd.Actor, Reason: "payment authorization"}
+	if gatewayResponse.Status == domain.PaymentStatusAuthorized {
+		err = payment.SetAuthorized(gatewayResponse.AuthorizationExpiresAt, change)
+	} else {
+		err = payment.TransitionTo(gatewayResponse.Status, change)
+	}
+	if err != nil {
+		return nil, err
+	}
+	payment.TransactionID = &gatewayResponse.TransactionID
+	payment.GatewayResponse = gatewayResponse.GatewayResponse
+
+	// Save updated payment
+	if err := h.paymentRepo.Update(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	// Convert to DTO
+	return &dto.PaymentResponse{
+		ID:              payment.ID,
+		UserID:          payment.UserID,
+		OrderID:         payment.OrderID,
+		Amount:          payment.Amount().Major(),
+		AmountMinor:     payment.AmountMinor,
+		Currency:        payment.Currency,
+		Status:          string(payment.Status),
+		PaymentMethod:   string(payment.PaymentMethod),
+		PaymentProvider: payment.PaymentProvider,
+		TransactionID:   payment.TransactionID,
+		GatewayResponse: payment.GatewayResponse,
+		ClientSecret:    gatewayResponse.ClientSecret,
+		CreatedAt:       payment.CreatedAt,
+		UpdatedAt:       payment.UpdatedAt,
+		CompletedAt:     payment.CompletedAt,
+		ExpiresAt:       payment.ExpiresAt,
+		CapturedAmount:  payment.Captured().Major(),
+		CapturedMinor:   payment.Captured().Amount,
+		AuthExpiresAt:   payment.AuthorizationExpiresAt,
+	}, nil
+}
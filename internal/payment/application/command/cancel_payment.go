@@ -10,6 +10,7 @@ import (
 // CancelPaymentCommand represents the command to cancel a payment
 type CancelPaymentCommand struct {
 	PaymentID string
+	Actor     string
 }
 
 // CancelPaymentCommandHandler handles the cancel payment command
@@ -49,7 +50,9 @@ func (h *CancelPaymentCommandHandler) Handle(ctx context.Context, cmd CancelPaym
 	}
 
 	// Update payment status
-	payment.SetCancelled()
+	if err := payment.SetCancelled(domain.StatusChange{Actor: cmd.Actor, Reason: "payment cancelled"}); err != nil {
+		return nil, err
+	}
 	payment.GatewayResponse = gatewayResponse.GatewayResponse
 
 	// Save updated payment
@@ -62,7 +65,8 @@ func (h *CancelPaymentCommandHandler) Handle(ctx context.Context, cmd CancelPaym
 		ID:              payment.ID,
 		UserID:          payment.UserID,
 		OrderID:         payment.OrderID,
-		Amount:          payment.Amount,
+		Amount:          payment.Amount().Major(),
+		AmountMinor:     payment.AmountMinor,
 		Currency:        payment.Currency,
 		Status:          string(payment.Status),
 		PaymentMethod:   string(payment.PaymentMethod),
@@ -73,5 +77,8 @@ func (h *CancelPaymentCommandHandler) Handle(ctx context.Context, cmd CancelPaym
 		UpdatedAt:       payment.UpdatedAt,
 		CompletedAt:     payment.CompletedAt,
 		ExpiresAt:       payment.ExpiresAt,
+		CapturedAmount:  payment.Captured().Major(),
+		CapturedMinor:   payment.Captured().Amount,
+		AuthExpiresAt:   payment.AuthorizationExpiresAt,
 	}, nil
 }
@@ -0,0 +1,110 @@
+package command
+
+import (
+	"context"
+
+	"github.com/ddd-micro/internal/payment/application/dto"
+	"github.com/ddd-micro/internal/payment/domain"
+	"github.com/ddd-micro/pkg/money"
+)
+
+// CapturePaymentCommand represents the command to charge authorized funds
+type CapturePaymentCommand struct {
+	PaymentID string
+	// Amount to capture; the full authorized amount is captured when nil
+	Amount *float64
+	Actor  string
+}
+
+// CapturePaymentCommandHandler handles the capture payment command
+type CapturePaymentCommandHandler struct {
+	paymentRepo    domain.PaymentRepository
+	paymentGateway domain.PaymentGateway
+}
+
+// NewCapturePaymentCommandHandler creates a new capture payment command handler
+func NewCapturePaymentCommandHandler(
+	paymentRepo domain.PaymentRepository,
+	paymentGateway domain.PaymentGateway,
+) *CapturePaymentCommandHandler {
+	return &CapturePaymentCommandHandler{
+		paymentRepo:    paymentRepo,
+		paymentGateway: paymentGateway,
+	}
+}
+
+// Handle handles the capture payment command
+func (h *CapturePaymentCommandHandler) Handle(ctx context.Context, cmd CapturePaymentCommand) (*dto.PaymentResponse, error) {
+	// Get payment from repository
+	payment, err := h.paymentRepo.GetByID(ctx, cmd.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if payment can be captured
+	if payment.IsAuthorized() && payment.IsAuthorizationExpired() {
+		return nil, domain.ErrAuthorizationExpired
+	}
+	if !payment.CanBeCaptured() {
+		return nil, domain.ErrPaymentCannotBeCaptured
+	}
+
+	amount := payment.Amount()
+	if cmd.Amount != nil {
+		amount, err = money.FromMajor(*cmd.Amount, payment.Currency)
+		if err != nil {
+			return nil, domain.ErrInvalidAmount
+		}
+	}
+	if !amount.IsPositive() {
+		return nil, domain.ErrInvalidAmount
+	}
+	if amount.Amount > payment.AmountMinor {
+		return nil, domain.ErrCaptureAmountExceedsHold
+	}
+
+	// Capture payment via gateway
+	gatewayResponse, err := h.paymentGateway.CapturePayment(ctx, payment, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update payment status
+	change := domain.StatusChange{Actor: cmd.Actor, Reason: "payment capture"}
+	if gatewayResponse.Status == domain.PaymentStatusCompleted {
+		err = payment.SetCaptured(amount, change)
+	} else {
+		err = payment.TransitionTo(gatewayResponse.Status, change)
+	}
+	if err != nil {
+		return nil, err
+	}
+	payment.GatewayResponse = gatewayResponse.GatewayResponse
+
+	// Save updated payment
+	if err := h.paymentRepo.Update(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	// Convert to DTO
+	return &dto.PaymentResponse{
+		ID:              payment.ID,
+		UserID:          payment.UserID,
+		OrderID:         payment.OrderID,
+		Amount:          payment.Amount().Major(),
+		AmountMinor:     payment.AmountMinor,
+		Currency:        payment.Currency,
+		Status:          string(payment.Status),
+		PaymentMethod:   string(payment.PaymentMethod),
+		PaymentProvider: payment.PaymentProvider,
+		TransactionID:   payment.TransactionID,
+		GatewayResponse: payment.GatewayResponse,
+		CreatedAt:       payment.CreatedAt,
+		UpdatedAt:       payment.UpdatedAt,
+		CompletedAt:     payment.CompletedAt,
+		ExpiresAt:       payment.ExpiresAt,
+		CapturedAmount:  payment.Captured().Major(),
+		CapturedMinor:   payment.Captured().Amount,
+		AuthExpiresAt:   payment.AuthorizationExpiresAt,
+	}, nil
+}
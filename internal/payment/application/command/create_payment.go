@@ -6,21 +6,25 @@ import (
 
 	"github.com/ddd-micro/internal/payment/application/dto"
 	"github.com/ddd-micro/internal/payment/domain"
+	"github.com/ddd-micro/pkg/money"
 	"github.com/google/uuid"
 )
 
 // CreatePaymentCommand represents the command to create a payment
 type CreatePaymentCommand struct {
+	// PaymentID is assigned by callers that must find the payment again after a retry;
+	// a new ID is generated when it is empty
+	PaymentID       string
 	UserID          uint
 	OrderID         string
-	Amount          float64
-	Currency        string
+	Amount          money.Money
 	PaymentMethod   string
 	PaymentMethodID string
 	ReturnURL       string
 	CancelURL       string
-	// Optional: Direct product purchase (without basket)
+	// Optional: Direct product purchase (without basket), of a variant when given
 	ProductID *uint
+	VariantID *uint
 	Quantity  *int
 	// Optional: Basket-based purchase
 	BasketID *string
@@ -45,19 +49,34 @@ func NewCreatePaymentCommandHandler(
 
 // Handle handles the create payment command
 func (h *CreatePaymentCommandHandler) Handle(ctx context.Context, cmd CreatePaymentCommand) (*dto.PaymentResponse, error) {
+	paymentID := cmd.PaymentID
+	if paymentID == "" {
+		paymentID = uuid.New().String()
+	}
+
 	// Create payment domain object
 	payment := &domain.Payment{
-		ID:              uuid.New().String(),
+		ID:              paymentID,
 		UserID:          cmd.UserID,
 		OrderID:         cmd.OrderID,
-		Amount:          cmd.Amount,
-		Currency:        cmd.Currency,
+		AmountMinor:     cmd.Amount.Amount,
+		Currency:        cmd.Amount.Currency,
 		Status:          domain.PaymentStatusPending,
 		PaymentMethod:   domain.PaymentMethod(cmd.PaymentMethod),
-		PaymentProvider: "stripe", // Default provider
+		PaymentProvider: h.paymentGateway.Provider(),
+		ProductID:       cmd.ProductID,
+		VariantID:       cmd.VariantID,
+		Quantity:        cmd.Quantity,
+		BasketID:        cmd.BasketID,
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
 	}
+	if cmd.ReturnURL != "" {
+		payment.ReturnURL = &cmd.ReturnURL
+	}
+	if cmd.CancelURL != "" {
+		payment.CancelURL = &cmd.CancelURL
+	}
 
 	// Set expiration time (24 hours)
 	payment.SetExpiration(24 * time.Hour)
@@ -87,7 +106,8 @@ func (h *CreatePaymentCommandHandler) Handle(ctx context.Context, cmd CreatePaym
 		ID:              payment.ID,
 		UserID:          payment.UserID,
 		OrderID:         payment.OrderID,
-		Amount:          payment.Amount,
+		Amount:          payment.Amount().Major(),
+		AmountMinor:     payment.AmountMinor,
 		Currency:        payment.Currency,
 		Status:          string(payment.Status),
 		PaymentMethod:   string(payment.PaymentMethod),
@@ -100,5 +120,8 @@ func (h *CreatePaymentCommandHandler) Handle(ctx context.Context, cmd CreatePaym
 		UpdatedAt:       payment.UpdatedAt,
 		CompletedAt:     payment.CompletedAt,
 		ExpiresAt:       payment.ExpiresAt,
+		CapturedAmount:  payment.Captured().Major(),
+		CapturedMinor:   payment.Captured().Amount,
+		AuthExpiresAt:   payment.AuthorizationExpiresAt,
 	}, nil
 }
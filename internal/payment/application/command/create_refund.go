@@ -0,0 +1,96 @@
+package command
+
+import (
+	"context"
+
+	"github.com/ddd-micro/internal/payment/application/dto"
+	"github.com/ddd-micro/internal/payment/domain"
+	"github.com/ddd-micro/pkg/money"
+	"github.com/google/uuid"
+)
+
+// CreateRefundCommand represents the command to create a refund
+type CreateRefundCommand struct {
+	PaymentID string
+	Amount    float64
+	Reason    string
+}
+
+// CreateRefundCommandHandler handles the create refund command
+type CreateRefundCommandHandler struct {
+	paymentRepo domain.PaymentRepository
+	refundRepo  domain.RefundRepository
+}
+
+// NewCreateRefundCommandHandler creates a new create refund command handler
+func NewCreateRefundCommandHandler(
+	paymentRepo domain.PaymentRepository,
+	refundRepo domain.RefundRepository,
+) *CreateRefundCommandHandler {
+	return &CreateRefundCommandHandler{
+		paymentRepo: paymentRepo,
+		refundRepo:  refundRepo,
+	}
+}
+
+// Handle handles the create refund command. ctx must carry a transaction, so that the
+// locked payment keeps concurrent refunds from exceeding what is left to refund.
+func (h *CreateRefundCommandHandler) Handle(ctx context.Context, cmd CreateRefundCommand) (*dto.RefundResponse, error) {
+	// Get payment from repository, locking it against concurrent refunds
+	payment, err := h.paymentRepo.GetByIDForUpdate(ctx, cmd.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if payment can be refunded
+	if !payment.CanBeRefunded() {
+		return nil, domain.ErrPaymentCannotBeRefunded
+	}
+
+	// Check the amount against what is left after previous refunds
+	refunds, err := h.refundRepo.GetByPaymentID(ctx, payment.ID)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := money.FromMajor(cmd.Amount, payment.Currency)
+	if err != nil {
+		return nil, domain.ErrInvalidAmount
+	}
+	if amount.Amount > payment.RefundableAmount(refunds).Amount {
+		return nil, domain.ErrRefundAmountExceedsPayment
+	}
+
+	refund := &domain.Refund{
+		ID:          uuid.New().String(),
+		PaymentID:   payment.ID,
+		AmountMinor: amount.Amount,
+		Currency:    amount.Currency,
+		Reason:      cmd.Reason,
+		Status:      domain.RefundStatusPending,
+	}
+
+	// Validate refund
+	if err := refund.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Save refund to repository
+	if err := h.refundRepo.Create(ctx, refund); err != nil {
+		return nil, err
+	}
+
+	// Convert to DTO
+	return &dto.RefundResponse{
+		ID:            refund.ID,
+		PaymentID:     refund.PaymentID,
+		Amount:        refund.Amount().Major(),
+		AmountMinor:   refund.AmountMinor,
+		Currency:      refund.Currency,
+		Reason:        refund.Reason,
+		Status:        refund.Status,
+		TransactionID: refund.TransactionID,
+		CreatedAt:     refund.CreatedAt,
+		UpdatedAt:     refund.UpdatedAt,
+		CompletedAt:   refund.CompletedAt,
+	}, nil
+}
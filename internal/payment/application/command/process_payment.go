@@ -12,6 +12,7 @@ type ProcessPaymentCommand struct {
 	PaymentID        string
 	PaymentMethodID  string
 	ConfirmationData map[string]interface{}
+	Actor            string
 }
 
 // ProcessPaymentCommandHandler handles the process payment command
@@ -39,6 +40,11 @@ func (h *ProcessPaymentCommandHandler) Handle(ctx context.Context, cmd ProcessPa
 		return nil, err
 	}
 
+	// Check if payment can still be charged
+	if !payment.CanBeProcessed() {
+		return nil, &domain.InvalidStatusTransitionError{From: payment.Status, To: domain.PaymentStatusCompleted}
+	}
+
 	// Process payment via gateway
 	gatewayResponse, err := h.paymentGateway.ProcessPayment(ctx, payment, cmd.PaymentMethodID)
 	if err != nil {
@@ -46,7 +52,15 @@ func (h *ProcessPaymentCommandHandler) Handle(ctx context.Context, cmd ProcessPa
 	}
 
 	// Update payment status
-	payment.Status = gatewayResponse.Status
+	change := domain.StatusChange{Actor: cmd.Actor, Reason: "payment processed"}
+	if gatewayResponse.Status == domain.PaymentStatusCompleted {
+		err = payment.SetCompleted(change)
+	} else {
+		err = payment.TransitionTo(gatewayResponse.Status, change)
+	}
+	if err != nil {
+		return nil, err
+	}
 	payment.TransactionID = &gatewayResponse.TransactionID
 	payment.GatewayResponse = gatewayResponse.GatewayResponse
 
@@ -60,7 +74,8 @@ func (h *ProcessPaymentCommandHandler) Handle(ctx context.Context, cmd ProcessPa
 		ID:              payment.ID,
 		UserID:          payment.UserID,
 		OrderID:         payment.OrderID,
-		Amount:          payment.Amount,
+		Amount:          payment.Amount().Major(),
+		AmountMinor:     payment.AmountMinor,
 		Currency:        payment.Currency,
 		Status:          string(payment.Status),
 		PaymentMethod:   string(payment.PaymentMethod),
@@ -71,5 +86,8 @@ func (h *ProcessPaymentCommandHandler) Handle(ctx context.Context, cmd ProcessPa
 		UpdatedAt:       payment.UpdatedAt,
 		CompletedAt:     payment.CompletedAt,
 		ExpiresAt:       payment.ExpiresAt,
+		CapturedAmount:  payment.Captured().Major(),
+		CapturedMinor:   payment.Captured().Amount,
+		AuthExpiresAt:   payment.AuthorizationExpiresAt,
 	}, nil
 }
@@ -0,0 +1,137 @@
+package command
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ddd-micro/internal/payment/domain"
+)
+
+// ProcessRefundCommand represents the command to process a pending refund
+type ProcessRefundCommand struct {
+	RefundID string
+	Actor    string
+}
+
+// ProcessRefundResult describes the outcome of submitting a refund to the gateway
+type ProcessRefundResult struct {
+	Refund  *domain.Refund
+	Payment *domain.Payment
+	// Refunds holds all refunds of the payment, including the processed one
+	Refunds []*domain.Refund
+	// GatewayError is why the gateway could not refund; the refund is then failed and
+	// stored like any other outcome, so the caller reports it after committing
+	GatewayError error
+}
+
+// ProcessRefundCommandHandler handles the process refund command
+type ProcessRefundCommandHandler struct {
+	paymentRepo    domain.PaymentRepository
+	refundRepo     domain.RefundRepository
+	paymentGateway domain.PaymentGateway
+}
+
+// NewProcessRefundCommandHandler creates a new process refund command handler
+func NewProcessRefundCommandHandler(
+	paymentRepo domain.PaymentRepository,
+	refundRepo domain.RefundRepository,
+	paymentGateway domain.PaymentGateway,
+) *ProcessRefundCommandHandler {
+	return &ProcessRefundCommandHandler{
+		paymentRepo:    paymentRepo,
+		refundRepo:     refundRepo,
+		paymentGateway: paymentGateway,
+	}
+}
+
+// Handle handles the process refund command. ctx must carry a transaction, so that the
+// locked payment keeps concurrent refunds from exceeding what is left to refund.
+func (h *ProcessRefundCommandHandler) Handle(ctx context.Context, cmd ProcessRefundCommand) (*ProcessRefundResult, error) {
+	// Get refund from repository
+	refund, err := h.refundRepo.GetByID(ctx, cmd.RefundID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only pending refunds can be submitted to the gateway
+	switch {
+	case refund.IsCompleted():
+		return nil, domain.ErrRefundAlreadyCompleted
+	case refund.IsFailed():
+		return nil, domain.ErrRefundAlreadyFailed
+	case !refund.IsPending():
+		return nil, domain.ErrInvalidStatus
+	}
+
+	// Lock the payment against concurrent refunds before checking the amount
+	payment, err := h.paymentRepo.GetByIDForUpdate(ctx, refund.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !payment.CanBeRefunded() {
+		return nil, domain.ErrPaymentCannotBeRefunded
+	}
+
+	// Re-check the amount against the other refunds of the payment
+	refunds, err := h.refundRepo.GetByPaymentID(ctx, payment.ID)
+	if err != nil {
+		return nil, err
+	}
+	otherRefunds := make([]*domain.Refund, 0, len(refunds))
+	for _, r := range refunds {
+		if r.ID != refund.ID {
+			otherRefunds = append(otherRefunds, r)
+		}
+	}
+	if refund.AmountMinor > payment.RefundableAmount(otherRefunds).Amount {
+		return nil, domain.ErrRefundAmountExceedsPayment
+	}
+
+	// Refund payment via gateway
+	gatewayResponse, gatewayErr := h.paymentGateway.RefundPayment(ctx, payment, refund)
+	if gatewayErr != nil {
+		refund.SetFailed()
+	} else {
+		transactionID := gatewayResponse.TransactionID
+		refund.TransactionID = &transactionID
+
+		switch gatewayResponse.Status {
+		case domain.PaymentStatusRefunded:
+			refund.SetCompleted()
+		case domain.PaymentStatusFailed:
+			refund.SetFailed()
+		default:
+			// Completed later by the charge.refunded webhook
+			refund.SetProcessing()
+		}
+	}
+
+	// Save updated refund
+	if err := h.refundRepo.Update(ctx, refund); err != nil {
+		return nil, err
+	}
+	if gatewayErr != nil {
+		return &ProcessRefundResult{
+			Refund:       refund,
+			Payment:      payment,
+			Refunds:      append(otherRefunds, refund),
+			GatewayError: fmt.Errorf("%w: %v", domain.ErrGatewayError, gatewayErr),
+		}, nil
+	}
+
+	// Move payment to refunded or partially refunded based on completed refunds
+	refunds = append(otherRefunds, refund)
+	if err := payment.ApplyRefunds(refunds, domain.StatusChange{Actor: cmd.Actor, Reason: refund.Reason}); err != nil {
+		return nil, err
+	}
+	if err := h.paymentRepo.Update(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	return &ProcessRefundResult{
+		Refund:  refund,
+		Payment: payment,
+		Refunds: refunds,
+	}, nil
+}
@@ -0,0 +1,213 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ddd-micro/internal/payment/domain"
+)
+
+// ProcessWebhookCommand represents the command to process a payment gateway webhook
+type ProcessWebhookCommand struct {
+	Provider  string
+	Payload   []byte
+	Signature string
+}
+
+// ProcessWebhookResult describes the outcome of applying a webhook event
+type ProcessWebhookResult struct {
+	Event          *domain.WebhookEvent
+	Payment        *domain.Payment
+	PreviousStatus domain.PaymentStatus
+	// Refunds completed by the event
+	Refunds []*domain.Refund
+	// Duplicate is set when the event was already processed before
+	Duplicate bool
+	// Ignored is set when the event does not affect any payment
+	Ignored bool
+}
+
+// StatusChanged reports whether the webhook moved the payment to a new status
+func (r *ProcessWebhookResult) StatusChanged() bool {
+	return r.Payment != nil && r.Payment.Status != r.PreviousStatus
+}
+
+// ProcessWebhookCommandHandler handles the process webhook command
+type ProcessWebhookCommandHandler struct {
+	paymentRepo      domain.PaymentRepository
+	refundRepo       domain.RefundRepository
+	webhookEventRepo domain.WebhookEventRepository
+	paymentGateway   domain.PaymentGateway
+}
+
+// NewProcessWebhookCommandHandler creates a new process webhook command handler
+func NewProcessWebhookCommandHandler(
+	paymentRepo domain.PaymentRepository,
+	refundRepo domain.RefundRepository,
+	webhookEventRepo domain.WebhookEventRepository,
+	paymentGateway domain.PaymentGateway,
+) *ProcessWebhookCommandHandler {
+	return &ProcessWebhookCommandHandler{
+		paymentRepo:      paymentRepo,
+		refundRepo:       refundRepo,
+		webhookEventRepo: webhookEventRepo,
+		paymentGateway:   paymentGateway,
+	}
+}
+
+// Handle handles the process webhook command
+func (h *ProcessWebhookCommandHandler) Handle(ctx context.Context, cmd ProcessWebhookCommand) (*ProcessWebhookResult, error) {
+	if cmd.Provider != h.paymentGateway.Provider() {
+		return nil, domain.ErrUnsupportedProvider
+	}
+
+	// Verify signature and parse event via gateway
+	event, err := h.paymentGateway.ProcessWebhook(ctx, cmd.Payload, cmd.Signature)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ProcessWebhookResult{Event: event}
+
+	// Ignore replays of events that were already applied
+	processed, err := h.webhookEventRepo.Exists(ctx, cmd.Provider, event.ID)
+	if err != nil {
+		return nil, err
+	}
+	if processed {
+		result.Duplicate = true
+		return result, nil
+	}
+
+	switch event.Type {
+	case domain.EventPaymentSucceeded, domain.EventPaymentFailed, domain.EventPaymentCancelled, domain.EventRefundSucceeded:
+		payment, err := h.findPayment(ctx, event)
+		if err != nil {
+			if !errors.Is(err, domain.ErrPaymentNotFound) {
+				return nil, err
+			}
+			result.Ignored = true
+			break
+		}
+
+		result.Payment = payment
+		result.PreviousStatus = payment.Status
+
+		if err := h.applyEvent(ctx, event, result); err != nil {
+			return nil, err
+		}
+	default:
+		result.Ignored = true
+	}
+
+	// Record event so provider retries are not applied twice
+	processedEvent := &domain.ProcessedWebhookEvent{
+		EventID:     event.ID,
+		Provider:    cmd.Provider,
+		Type:        event.ProviderType,
+		ProcessedAt: time.Now(),
+	}
+	if result.Payment != nil {
+		processedEvent.PaymentID = &result.Payment.ID
+	}
+	if err := h.webhookEventRepo.Create(ctx, processedEvent); err != nil {
+		return nil, err
+	}
+
+	event.Processed = true
+	return result, nil
+}
+
+// findPayment resolves the payment referenced by a webhook event
+func (h *ProcessWebhookCommandHandler) findPayment(ctx context.Context, event *domain.WebhookEvent) (*domain.Payment, error) {
+	if event.PaymentID != "" {
+		return h.paymentRepo.GetByID(ctx, event.PaymentID)
+	}
+	if event.TransactionID != "" {
+		return h.paymentRepo.GetByTransactionID(ctx, event.TransactionID)
+	}
+	return nil, domain.ErrPaymentNotFound
+}
+
+// applyEvent applies the webhook event to the payment and its refunds
+func (h *ProcessWebhookCommandHandler) applyEvent(ctx context.Context, event *domain.WebhookEvent, result *ProcessWebhookResult) error {
+	payment := result.Payment
+	change := domain.StatusChange{
+		Actor:          domain.GatewayActor(event.Provider),
+		Reason:         event.Reason,
+		GatewayEventID: event.ID,
+	}
+
+	amount := event.AmountIn(payment.Currency)
+
+	var err error
+	switch event.Type {
+	case domain.EventPaymentSucceeded:
+		switch {
+		case payment.IsAuthorized() && amount.IsPositive():
+			// Captured outside of the service, e.g. from the provider dashboard
+			err = payment.SetCaptured(amount, change)
+		case payment.CanBeProcessed():
+			err = payment.SetCompleted(change)
+		}
+	case domain.EventPaymentFailed:
+		if payment.IsPending() || payment.IsProcessing() {
+			err = payment.SetFailed(change)
+		}
+	case domain.EventPaymentCancelled:
+		if payment.CanBeCancelled() {
+			err = payment.SetCancelled(change)
+		}
+	case domain.EventRefundSucceeded:
+		refunds, completed, refundErr := h.completeOutstandingRefunds(ctx, payment.ID)
+		if refundErr != nil {
+			return refundErr
+		}
+		result.Refunds = completed
+
+		if payment.CanBeRefunded() {
+			if amount.SameCurrency(payment.ChargedAmount()) && amount.Amount >= payment.ChargedAmount().Amount {
+				err = payment.SetRefunded(change)
+			} else {
+				err = payment.ApplyRefunds(refunds, change)
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	// Keep the payment intent reference so later lookups by transaction ID succeed
+	if event.TransactionID != "" {
+		transactionID := event.TransactionID
+		payment.TransactionID = &transactionID
+	}
+	payment.GatewayResponse = event.Data
+
+	return h.paymentRepo.Update(ctx, payment)
+}
+
+// completeOutstandingRefunds marks the payment's refunds submitted to the gateway as completed.
+// It returns all refunds of the payment along with the ones it completed.
+func (h *ProcessWebhookCommandHandler) completeOutstandingRefunds(ctx context.Context, paymentID string) ([]*domain.Refund, []*domain.Refund, error) {
+	refunds, err := h.refundRepo.GetByPaymentID(ctx, paymentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var completed []*domain.Refund
+	for _, refund := range refunds {
+		if refund.Status != domain.RefundStatusProcessing {
+			continue
+		}
+
+		refund.SetCompleted()
+		if err := h.refundRepo.Update(ctx, refund); err != nil {
+			return nil, nil, err
+		}
+		completed = append(completed, refund)
+	}
+
+	return refunds, completed, nil
+}
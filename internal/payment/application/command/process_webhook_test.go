@@ -0,0 +1,144 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ddd-micro/internal/payment/domain"
+	"github.com/ddd-micro/internal/payment/infrastructure/config"
+	"github.com/ddd-micro/internal/payment/infrastructure/gateway"
+)
+
+const testWebhookSecret = "whsec_test"
+
+// fakePaymentRepository keeps payments in memory and counts updates
+type fakePaymentRepository struct {
+	domain.PaymentRepository
+	payments map[string]*domain.Payment
+	updates  int
+}
+
+func (r *fakePaymentRepository) GetByID(ctx context.Context, id string) (*domain.Payment, error) {
+	payment, ok := r.payments[id]
+	if !ok {
+		return nil, domain.ErrPaymentNotFound
+	}
+	return payment, nil
+}
+
+func (r *fakePaymentRepository) GetByTransactionID(ctx context.Context, transactionID string) (*domain.Payment, error) {
+	for _, payment := range r.payments {
+		if payment.TransactionID != nil && *payment.TransactionID == transactionID {
+			return payment, nil
+		}
+	}
+	return nil, domain.ErrPaymentNotFound
+}
+
+func (r *fakePaymentRepository) Update(ctx context.Context, payment *domain.Payment) error {
+	r.updates++
+	r.payments[payment.ID] = payment
+	return nil
+}
+
+// fakeWebhookEventRepository keeps processed webhook events in memory
+type fakeWebhookEventRepository struct {
+	events map[string]*domain.ProcessedWebhookEvent
+}
+
+func (r *fakeWebhookEventRepository) Exists(ctx context.Context, provider, eventID string) (bool, error) {
+	_, ok := r.events[provider+"/"+eventID]
+	return ok, nil
+}
+
+func (r *fakeWebhookEventRepository) Create(ctx context.Context, event *domain.ProcessedWebhookEvent) error {
+	r.events[event.Provider+"/"+event.EventID] = event
+	return nil
+}
+
+// paymentSucceededPayload returns a Stripe payment_intent.succeeded fixture for the payment
+func paymentSucceededPayload(eventID, paymentID string) []byte {
+	return []byte(fmt.Sprintf(`{
+  "id": %q,
+  "object": "event",
+  "type": "payment_intent.succeeded",
+  "created": 1700000000,
+  "data": {
+    "object": {
+      "id": "pi_test",
+      "object": "payment_intent",
+      "amount_received": 1999,
+      "currency": "usd",
+      "metadata": {"payment_id": %q}
+    }
+  }
+}`, eventID, paymentID))
+}
+
+func TestProcessWebhookSkipsDuplicateEventIDs(t *testing.T) {
+	paymentRepo := &fakePaymentRepository{payments: map[string]*domain.Payment{
+		"pay_test": {
+			ID:          "pay_test",
+			AmountMinor: 1999,
+			Currency:    "USD",
+			Status:      domain.PaymentStatusPending,
+		},
+	}}
+	webhookEventRepo := &fakeWebhookEventRepository{events: map[string]*domain.ProcessedWebhookEvent{}}
+	paymentGateway := gateway.NewMockGateway(&config.Config{Stripe: config.StripeConfig{WebhookSecret: testWebhookSecret}})
+	handler := NewProcessWebhookCommandHandler(paymentRepo, nil, webhookEventRepo, paymentGateway)
+
+	payload := paymentSucceededPayload("evt_duplicate", "pay_test")
+	cmd := ProcessWebhookCommand{
+		Provider:  domain.ProviderMock,
+		Payload:   payload,
+		Signature: gateway.SignWebhookPayload(payload, testWebhookSecret),
+	}
+
+	first, err := handler.Handle(t.Context(), cmd)
+	if err != nil {
+		t.Fatalf("first Handle() error = %v", err)
+	}
+	if first.Duplicate || !first.StatusChanged() {
+		t.Fatalf("first Handle() = duplicate %v, status changed %v; want a new status", first.Duplicate, first.StatusChanged())
+	}
+	if status := paymentRepo.payments["pay_test"].Status; status != domain.PaymentStatusCompleted {
+		t.Fatalf("payment status = %q, want %q", status, domain.PaymentStatusCompleted)
+	}
+
+	// The provider redelivers the same event with a fresh signature
+	cmd.Signature = gateway.SignWebhookPayload(payload, testWebhookSecret)
+	second, err := handler.Handle(t.Context(), cmd)
+	if err != nil {
+		t.Fatalf("second Handle() error = %v", err)
+	}
+	if !second.Duplicate {
+		t.Fatal("second Handle() was not reported as a duplicate")
+	}
+	if second.Payment != nil || second.StatusChanged() {
+		t.Fatal("second Handle() applied the event again")
+	}
+	if paymentRepo.updates != 1 {
+		t.Fatalf("payment updates = %d, want 1", paymentRepo.updates)
+	}
+	if len(webhookEventRepo.events) != 1 {
+		t.Fatalf("processed events = %d, want 1", len(webhookEventRepo.events))
+	}
+}
+
+func TestProcessWebhookRejectsUnsupportedProvider(t *testing.T) {
+	paymentGateway := gateway.NewMockGateway(&config.Config{Stripe: config.StripeConfig{WebhookSecret: testWebhookSecret}})
+	handler := NewProcessWebhookCommandHandler(nil, nil, nil, paymentGateway)
+
+	payload := paymentSucceededPayload("evt_provider", "pay_test")
+	_, err := handler.Handle(t.Context(), ProcessWebhookCommand{
+		Provider:  domain.ProviderStripe,
+		Payload:   payload,
+		Signature: gateway.SignWebhookPayload(payload, testWebhookSecret),
+	})
+	if !errors.Is(err, domain.ErrUnsupportedProvider) {
+		t.Fatalf("Handle() error = %v, want %v", err, domain.ErrUnsupportedProvider)
+	}
+}
@@ -0,0 +1,79 @@
+package command
+
+import (
+	"context"
+
+	"github.com/ddd-micro/internal/payment/application/dto"
+	"github.com/ddd-micro/internal/payment/domain"
+)
+
+// UpdatePaymentStatusCommand represents the command to change a payment status manually
+type UpdatePaymentStatusCommand struct {
+	PaymentID string
+	Status    domain.PaymentStatus
+	Reason    string
+	Actor     string
+}
+
+// UpdatePaymentStatusCommandHandler handles the update payment status command
+type UpdatePaymentStatusCommandHandler struct {
+	paymentRepo domain.PaymentRepository
+}
+
+// NewUpdatePaymentStatusCommandHandler creates a new update payment status command handler
+func NewUpdatePaymentStatusCommandHandler(paymentRepo domain.PaymentRepository) *UpdatePaymentStatusCommandHandler {
+	return &UpdatePaymentStatusCommandHandler{
+		paymentRepo: paymentRepo,
+	}
+}
+
+// Handle handles the update payment status command
+func (h *UpdatePaymentStatusCommandHandler) Handle(ctx context.Context, cmd UpdatePaymentStatusCommand) (*dto.PaymentResponse, error) {
+	if !domain.IsValidPaymentStatus(cmd.Status) {
+		return nil, domain.ErrInvalidStatus
+	}
+
+	// Get payment from repository
+	payment, err := h.paymentRepo.GetByID(ctx, cmd.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only moves allowed by the transition table are accepted
+	change := domain.StatusChange{Actor: cmd.Actor, Reason: cmd.Reason}
+	if cmd.Status == domain.PaymentStatusCompleted {
+		err = payment.SetCompleted(change)
+	} else {
+		err = payment.TransitionTo(cmd.Status, change)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Save updated payment
+	if err := h.paymentRepo.Update(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	// Convert to DTO
+	return &dto.PaymentResponse{
+		ID:              payment.ID,
+		UserID:          payment.UserID,
+		OrderID:         payment.OrderID,
+		Amount:          payment.Amount().Major(),
+		AmountMinor:     payment.AmountMinor,
+		Currency:        payment.Currency,
+		Status:          string(payment.Status),
+		PaymentMethod:   string(payment.PaymentMethod),
+		PaymentProvider: payment.PaymentProvider,
+		TransactionID:   payment.TransactionID,
+		GatewayResponse: payment.GatewayResponse,
+		CreatedAt:       payment.CreatedAt,
+		UpdatedAt:       payment.UpdatedAt,
+		CompletedAt:     payment.CompletedAt,
+		ExpiresAt:       payment.ExpiresAt,
+		CapturedAmount:  payment.Captured().Major(),
+		CapturedMinor:   payment.Captured().Amount,
+		AuthExpiresAt:   payment.AuthorizationExpiresAt,
+	}, nil
+}
@@ -0,0 +1,84 @@
+package command
+
+import (
+	"context"
+
+	"github.com/ddd-micro/internal/payment/application/dto"
+	"github.com/ddd-micro/internal/payment/domain"
+)
+
+// VoidAuthorizationCommand represents the command to release authorized funds
+type VoidAuthorizationCommand struct {
+	PaymentID string
+	Actor     string
+}
+
+// VoidAuthorizationCommandHandler handles the void authorization command
+type VoidAuthorizationCommandHandler struct {
+	paymentRepo    domain.PaymentRepository
+	paymentGateway domain.PaymentGateway
+}
+
+// NewVoidAuthorizationCommandHandler creates a new void authorization command handler
+func NewVoidAuthorizationCommandHandler(
+	paymentRepo domain.PaymentRepository,
+	paymentGateway domain.PaymentGateway,
+) *VoidAuthorizationCommandHandler {
+	return &VoidAuthorizationCommandHandler{
+		paymentRepo:    paymentRepo,
+		paymentGateway: paymentGateway,
+	}
+}
+
+// Handle handles the void authorization command
+func (h *VoidAuthorizationCommandHandler) Handle(ctx context.Context, cmd VoidAuthorizationCommand) (*dto.PaymentResponse, error) {
+	// Get payment from repository
+	payment, err := h.paymentRepo.GetByID(ctx, cmd.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if authorization can be voided
+	if !payment.CanBeVoided() {
+		return nil, domain.ErrPaymentCannotBeVoided
+	}
+
+	// Release hold via gateway
+	gatewayResponse, err := h.paymentGateway.VoidAuthorization(ctx, payment)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update payment status
+	if err := payment.SetCancelled(domain.StatusChange{Actor: cmd.Actor, Reason: "authorization voided"}); err != nil {
+		return nil, err
+	}
+	payment.GatewayResponse = gatewayResponse.GatewayResponse
+
+	// Save updated payment
+	if err := h.paymentRepo.Update(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	// Convert to DTO
+	return &dto.PaymentResponse{
+		ID:              payment.ID,
+		UserID:          payment.UserID,
+		OrderID:         payment.OrderID,
+		Amount:          payment.Amount().Major(),
+		AmountMinor:     payment.AmountMinor,
+		Currency:        payment.Currency,
+		Status:          string(payment.Status),
+		PaymentMethod:   string(payment.PaymentMethod),
+		PaymentProvider: payment.PaymentProvider,
+		TransactionID:   payment.TransactionID,
+		GatewayResponse: payment.GatewayResponse,
+		CreatedAt:       payment.CreatedAt,
+		UpdatedAt:       payment.UpdatedAt,
+		CompletedAt:     payment.CompletedAt,
+		ExpiresAt:       payment.ExpiresAt,
+		CapturedAmount:  payment.Captured().Major(),
+		CapturedMinor:   payment.Captured().Amount,
+		AuthExpiresAt:   payment.AuthorizationExpiresAt,
+	}, nil
+}
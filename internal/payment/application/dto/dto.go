@@ -13,8 +13,9 @@ type CreatePaymentRequest struct {
 	PaymentMethodID string  `json:"payment_method_id,omitempty"`
 	ReturnURL       string  `json:"return_url,omitempty"`
 	CancelURL       string  `json:"cancel_url,omitempty"`
-	// Optional: Direct product purchase (without basket)
+	// Optional: Direct product purchase (without basket), of a variant when given
 	ProductID *uint `json:"product_id,omitempty"`
+	VariantID *uint `json:"variant_id,omitempty"`
 	Quantity  *int  `json:"quantity,omitempty"`
 	// Optional: Basket-based purchase
 	BasketID *string `json:"basket_id,omitempty"`
@@ -26,12 +27,24 @@ type ProcessPaymentRequest struct {
 	ConfirmationData map[string]interface{} `json:"confirmation_data,omitempty"`
 }
 
+// AuthorizePaymentRequest represents the request to put a hold on payment funds
+type AuthorizePaymentRequest struct {
+	PaymentMethodID string `json:"payment_method_id" binding:"required"`
+}
+
+// CapturePaymentRequest represents the request to capture authorized funds
+type CapturePaymentRequest struct {
+	// Optional: partial capture amount, defaults to the authorized amount
+	Amount *float64 `json:"amount,omitempty" binding:"omitempty,gt=0"`
+}
+
 // PaymentResponse represents the response for payment operations
 type PaymentResponse struct {
 	ID              string                 `json:"id"`
 	UserID          uint                   `json:"user_id"`
 	OrderID         string                 `json:"order_id"`
 	Amount          float64                `json:"amount"`
+	AmountMinor     int64                  `json:"amount_minor"`
 	Currency        string                 `json:"currency"`
 	Status          string                 `json:"status"`
 	PaymentMethod   string                 `json:"payment_method"`
@@ -44,6 +57,9 @@ type PaymentResponse struct {
 	UpdatedAt       time.Time              `json:"updated_at"`
 	CompletedAt     *time.Time             `json:"completed_at,omitempty"`
 	ExpiresAt       *time.Time             `json:"expires_at,omitempty"`
+	CapturedAmount  float64                `json:"captured_amount,omitempty"`
+	CapturedMinor   int64                  `json:"captured_amount_minor,omitempty"`
+	AuthExpiresAt   *time.Time             `json:"authorization_expires_at,omitempty"`
 }
 
 // ListPaymentsRequest represents the request for listing payments
@@ -102,6 +118,53 @@ type PaymentMethodListResponse struct {
 	Total          int                     `json:"total"`
 }
 
+// Checkout DTOs
+
+// CheckoutRequest represents the request to check out the user's basket
+type CheckoutRequest struct {
+	BasketID        string  `json:"basket_id" binding:"required"`
+	OrderID         string  `json:"order_id" binding:"required"`
+	Amount          float64 `json:"amount" binding:"required,min=0.01"`
+	Currency        string  `json:"currency" binding:"required,len=3"`
+	PaymentMethod   string  `json:"payment_method" binding:"required"`
+	PaymentMethodID string  `json:"payment_method_id,omitempty"`
+	ReturnURL       string  `json:"return_url,omitempty"`
+	CancelURL       string  `json:"cancel_url,omitempty"`
+}
+
+// CheckoutItemResponse represents a basket item taken into a checkout
+type CheckoutItemResponse struct {
+	ProductID      uint  `json:"product_id"`
+	VariantID      *uint `json:"variant_id,omitempty"`
+	Quantity       int   `json:"quantity"`
+	UnitPriceMinor int64 `json:"unit_price_minor"`
+	Reserved       bool  `json:"reserved"`
+}
+
+// CheckoutResponse represents the state of a checkout saga
+type CheckoutResponse struct {
+	ID            string                 `json:"id"`
+	UserID        uint                   `json:"user_id"`
+	BasketID      string                 `json:"basket_id"`
+	OrderID       string                 `json:"order_id"`
+	PaymentID     string                 `json:"payment_id"`
+	Amount        float64                `json:"amount"`
+	AmountMinor   int64                  `json:"amount_minor"`
+	Currency      string                 `json:"currency"`
+	Status        string                 `json:"status"`
+	Step          string                 `json:"step"`
+	Items         []CheckoutItemResponse `json:"items"`
+	FailureReason string                 `json:"failure_reason,omitempty"`
+	LastError     string                 `json:"last_error,omitempty"`
+	Attempts      int                    `json:"attempts"`
+	StepDeadline  time.Time              `json:"step_deadline"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+	CompletedAt   *time.Time             `json:"completed_at,omitempty"`
+	// Payment is the payment created by the checkout, returned when the checkout starts
+	Payment *PaymentResponse `json:"payment,omitempty"`
+}
+
 // Refund DTOs
 
 // CreateRefundRequest represents the request to create a refund
@@ -113,14 +176,17 @@ type CreateRefundRequest struct {
 
 // RefundResponse represents the response for refund operations
 type RefundResponse struct {
-	ID          string     `json:"id"`
-	PaymentID   string     `json:"payment_id"`
-	Amount      float64    `json:"amount"`
-	Reason      string     `json:"reason"`
-	Status      string     `json:"status"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ID            string     `json:"id"`
+	PaymentID     string     `json:"payment_id"`
+	Amount        float64    `json:"amount"`
+	AmountMinor   int64      `json:"amount_minor"`
+	Currency      string     `json:"currency"`
+	Reason        string     `json:"reason"`
+	Status        string     `json:"status"`
+	TransactionID *string    `json:"transaction_id,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
 }
 
 // AdminListPaymentsRequest represents the request for admin listing payments
@@ -137,6 +203,22 @@ type UpdatePaymentStatusRequest struct {
 	Reason string `json:"reason,omitempty"`
 }
 
+// PaymentStatusHistoryResponse represents a single payment status transition
+type PaymentStatusHistoryResponse struct {
+	FromStatus     string    `json:"from_status"`
+	ToStatus       string    `json:"to_status"`
+	Actor          string    `json:"actor"`
+	Reason         string    `json:"reason,omitempty"`
+	GatewayEventID *string   `json:"gateway_event_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// PaymentStatusHistoryListResponse represents the status history of a payment
+type PaymentStatusHistoryListResponse struct {
+	PaymentID string                         `json:"payment_id"`
+	History   []PaymentStatusHistoryResponse `json:"history"`
+}
+
 // AdminListRefundsRequest represents the request for admin listing refunds
 type AdminListRefundsRequest struct {
 	Page      int    `json:"page"`
@@ -157,23 +239,29 @@ type RefundListResponse struct {
 
 // PaymentStatsResponse represents the response for payment statistics
 type PaymentStatsResponse struct {
-	TotalPayments      int     `json:"total_payments"`
-	TotalAmount        float64 `json:"total_amount"`
-	SuccessfulPayments int     `json:"successful_payments"`
-	FailedPayments     int     `json:"failed_payments"`
-	PendingPayments    int     `json:"pending_payments"`
-	RefundedAmount     float64 `json:"refunded_amount"`
-	AverageAmount      float64 `json:"average_amount"`
+	TotalPayments      int                     `json:"total_payments"`
+	SuccessfulPayments int                     `json:"successful_payments"`
+	FailedPayments     int                     `json:"failed_payments"`
+	PendingPayments    int                     `json:"pending_payments"`
+	TotalAmounts       []CurrencyTotalResponse `json:"total_amounts"`
+	RefundedAmounts    []CurrencyTotalResponse `json:"refunded_amounts"`
 }
 
 // RefundStatsResponse represents the response for refund statistics
 type RefundStatsResponse struct {
-	TotalRefunds     int     `json:"total_refunds"`
-	TotalAmount      float64 `json:"total_amount"`
-	CompletedRefunds int     `json:"completed_refunds"`
-	PendingRefunds   int     `json:"pending_refunds"`
-	FailedRefunds    int     `json:"failed_refunds"`
-	AverageAmount    float64 `json:"average_amount"`
+	TotalRefunds     int                     `json:"total_refunds"`
+	CompletedRefunds int                     `json:"completed_refunds"`
+	PendingRefunds   int                     `json:"pending_refunds"`
+	FailedRefunds    int                     `json:"failed_refunds"`
+	TotalAmounts     []CurrencyTotalResponse `json:"total_amounts"`
+}
+
+// CurrencyTotalResponse represents the totals of a single currency
+type CurrencyTotalResponse struct {
+	Currency     string `json:"currency"`
+	Count        int    `json:"count"`
+	AmountMinor  int64  `json:"amount_minor"`
+	AverageMinor int64  `json:"average_minor"`
 }
 
 // Webhook DTOs
@@ -187,8 +275,9 @@ type WebhookRequest struct {
 
 // WebhookResponse represents the response for webhook processing
 type WebhookResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
+	Success          bool   `json:"success"`
+	Message          string `json:"message"`
+	RefundsCompleted int    `json:"refunds_completed,omitempty"`
 }
 
 // Common DTOs
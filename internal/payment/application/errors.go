@@ -42,4 +42,7 @@ var (
 	ErrPaymentProcessingFailed    = errors.New("payment processing failed")
 	ErrPaymentCancellationFailed  = errors.New("payment cancellation failed")
 	ErrRefundProcessingFailed     = errors.New("refund processing failed")
+	ErrInvalidStatsPeriod         = errors.New("invalid statistics period")
+	ErrCheckoutRejected           = errors.New("checkout rejected")
+	ErrCheckoutNeedsReview        = errors.New("checkout needs manual review")
 )
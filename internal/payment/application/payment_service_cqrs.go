@@ -2,15 +2,21 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"time"
 
 	"github.com/ddd-micro/internal/payment/application/command"
 	"github.com/ddd-micro/internal/payment/application/dto"
 	"github.com/ddd-micro/internal/payment/application/query"
 	"github.com/ddd-micro/internal/payment/domain"
 	"github.com/ddd-micro/internal/payment/infrastructure/client"
-	"github.com/ddd-micro/internal/payment/infrastructure/kafka"
+	paymentkafka "github.com/ddd-micro/internal/payment/infrastructure/kafka"
 	"github.com/ddd-micro/kafka"
+	"github.com/ddd-micro/pkg/catalog"
+	"github.com/ddd-micro/pkg/gormtx"
+	"github.com/ddd-micro/pkg/money"
 )
 
 // PaymentServiceCQRS represents the main payment service using CQRS pattern
@@ -22,24 +28,39 @@ type PaymentServiceCQRS struct {
 	addPaymentMethodHandler    *command.AddPaymentMethodCommandHandler
 	updatePaymentMethodHandler *command.UpdatePaymentMethodCommandHandler
 	deletePaymentMethodHandler *command.DeletePaymentMethodCommandHandler
+	processWebhookHandler      *command.ProcessWebhookCommandHandler
+	createRefundHandler        *command.CreateRefundCommandHandler
+	processRefundHandler       *command.ProcessRefundCommandHandler
+	authorizePaymentHandler    *command.AuthorizePaymentCommandHandler
+	capturePaymentHandler      *command.CapturePaymentCommandHandler
+	voidAuthorizationHandler   *command.VoidAuthorizationCommandHandler
+	updatePaymentStatusHandler *command.UpdatePaymentStatusCommandHandler
 
 	// Query handlers
 	getPaymentHandler         *query.GetPaymentQueryHandler
 	listPaymentsHandler       *query.ListPaymentsQueryHandler
 	getPaymentMethodHandler   *query.GetPaymentMethodQueryHandler
 	listPaymentMethodsHandler *query.ListPaymentMethodsQueryHandler
+	getRefundHandler          *query.GetRefundQueryHandler
+	getPaymentHistoryHandler  *query.GetPaymentHistoryQueryHandler
 
 	// Repositories
 	paymentRepo       domain.PaymentRepository
 	paymentMethodRepo domain.PaymentMethodRepository
+	refundRepo        domain.RefundRepository
+	checkoutRepo      domain.CheckoutSagaRepository
+
+	// Checkout saga of basket-based payments
+	checkout *CheckoutOrchestrator
 
 	// External service clients
 	userClient    client.UserClient
 	productClient client.ProductClient
 	basketClient  client.BasketClient
 
-	// Kafka event publisher
-	eventPublisher *kafka.PaymentEventPublisher
+	// Kafka event publisher, writing to the outbox of the current transaction
+	eventPublisher *paymentkafka.PaymentEventPublisher
+	transactor     *gormtx.Transactor
 }
 
 // NewPaymentServiceCQRS creates a new PaymentServiceCQRS
@@ -50,16 +71,29 @@ func NewPaymentServiceCQRS(
 	addPaymentMethodHandler *command.AddPaymentMethodCommandHandler,
 	updatePaymentMethodHandler *command.UpdatePaymentMethodCommandHandler,
 	deletePaymentMethodHandler *command.DeletePaymentMethodCommandHandler,
+	processWebhookHandler *command.ProcessWebhookCommandHandler,
+	createRefundHandler *command.CreateRefundCommandHandler,
+	processRefundHandler *command.ProcessRefundCommandHandler,
+	authorizePaymentHandler *command.AuthorizePaymentCommandHandler,
+	capturePaymentHandler *command.CapturePaymentCommandHandler,
+	voidAuthorizationHandler *command.VoidAuthorizationCommandHandler,
+	updatePaymentStatusHandler *command.UpdatePaymentStatusCommandHandler,
 	getPaymentHandler *query.GetPaymentQueryHandler,
 	listPaymentsHandler *query.ListPaymentsQueryHandler,
 	getPaymentMethodHandler *query.GetPaymentMethodQueryHandler,
 	listPaymentMethodsHandler *query.ListPaymentMethodsQueryHandler,
+	getRefundHandler *query.GetRefundQueryHandler,
+	getPaymentHistoryHandler *query.GetPaymentHistoryQueryHandler,
 	paymentRepo domain.PaymentRepository,
 	paymentMethodRepo domain.PaymentMethodRepository,
+	refundRepo domain.RefundRepository,
+	checkoutRepo domain.CheckoutSagaRepository,
+	checkout *CheckoutOrchestrator,
 	userClient client.UserClient,
 	productClient client.ProductClient,
 	basketClient client.BasketClient,
-	eventPublisher *kafka.PaymentEventPublisher,
+	eventPublisher *paymentkafka.PaymentEventPublisher,
+	transactor *gormtx.Transactor,
 ) *PaymentServiceCQRS {
 	return &PaymentServiceCQRS{
 		createPaymentHandler:       createPaymentHandler,
@@ -68,16 +102,29 @@ func NewPaymentServiceCQRS(
 		addPaymentMethodHandler:    addPaymentMethodHandler,
 		updatePaymentMethodHandler: updatePaymentMethodHandler,
 		deletePaymentMethodHandler: deletePaymentMethodHandler,
+		processWebhookHandler:      processWebhookHandler,
+		createRefundHandler:        createRefundHandler,
+		processRefundHandler:       processRefundHandler,
+		authorizePaymentHandler:    authorizePaymentHandler,
+		capturePaymentHandler:      capturePaymentHandler,
+		voidAuthorizationHandler:   voidAuthorizationHandler,
+		updatePaymentStatusHandler: updatePaymentStatusHandler,
 		getPaymentHandler:          getPaymentHandler,
 		listPaymentsHandler:        listPaymentsHandler,
 		getPaymentMethodHandler:    getPaymentMethodHandler,
 		listPaymentMethodsHandler:  listPaymentMethodsHandler,
+		getRefundHandler:           getRefundHandler,
+		getPaymentHistoryHandler:   getPaymentHistoryHandler,
 		paymentRepo:                paymentRepo,
 		paymentMethodRepo:          paymentMethodRepo,
+		refundRepo:                 refundRepo,
+		checkoutRepo:               checkoutRepo,
+		checkout:                   checkout,
 		userClient:                 userClient,
 		productClient:              productClient,
 		basketClient:               basketClient,
 		eventPublisher:             eventPublisher,
+		transactor:                 transactor,
 	}
 }
 
@@ -85,26 +132,35 @@ func NewPaymentServiceCQRS(
 
 // CreatePayment creates a new payment
 func (s *PaymentServiceCQRS) CreatePayment(ctx context.Context, userID uint, req dto.CreatePaymentRequest) (*dto.PaymentResponse, error) {
+	amount, err := money.FromMajor(req.Amount, req.Currency)
+	if err != nil {
+		if errors.Is(err, money.ErrUnknownCurrency) {
+			return nil, domain.ErrInvalidCurrency
+		}
+		return nil, domain.ErrInvalidAmount
+	}
+
 	// Validate payment based on type
 	if req.BasketID != nil {
-		// Basket-based payment: validate basket
-		basket, err := s.basketClient.ValidateBasket(ctx, userID)
+		// Basket-based payment: the checkout saga validates the basket total,
+		// reserves its stock and creates the payment
+		checkout, err := s.checkout.Checkout(ctx, userID, dto.CheckoutRequest{
+			BasketID:        *req.BasketID,
+			OrderID:         req.OrderID,
+			Amount:          req.Amount,
+			Currency:        req.Currency,
+			PaymentMethod:   req.PaymentMethod,
+			PaymentMethodID: req.PaymentMethodID,
+			ReturnURL:       req.ReturnURL,
+			CancelURL:       req.CancelURL,
+		})
 		if err != nil {
-			return nil, fmt.Errorf("basket validation failed: %w", err)
-		}
-
-		// Calculate total amount from basket
-		var totalAmount float64
-		for _, item := range basket.Items {
-			totalAmount += float64(item.Quantity) * item.UnitPrice
+			return nil, err
 		}
-
-		// Validate amount matches basket total
-		if req.Amount != totalAmount {
-			return nil, fmt.Errorf("payment amount does not match basket total")
+		if checkout.Payment == nil {
+			return s.GetPayment(ctx, userID, checkout.PaymentID)
 		}
-
-		// Note: In a real implementation, you would reserve items here
+		return checkout.Payment, nil
 
 	} else if req.ProductID != nil && req.Quantity != nil {
 		// Direct product payment: validate product
@@ -118,16 +174,31 @@ func (s *PaymentServiceCQRS) CreatePayment(ctx context.Context, userID uint, req
 			return nil, fmt.Errorf("invalid quantity")
 		}
 
-		// Calculate total amount
-		totalAmount := float64(*req.Quantity) * product.Price
+		// Calculate total amount, at the effective price of the variant when given
+		unitPrice, err := catalog.ItemPrice(product, req.VariantID)
+		if err != nil {
+			return nil, fmt.Errorf("product validation failed: %w", err)
+		}
+		available := product.AvailableStock
+		if req.VariantID != nil {
+			variant, err := catalog.Variant(product, *req.VariantID)
+			if err != nil {
+				return nil, fmt.Errorf("product validation failed: %w", err)
+			}
+			available = variant.AvailableStock
+		}
+		if !unitPrice.SameCurrency(amount) {
+			return nil, fmt.Errorf("payment currency does not match product currency")
+		}
+		totalAmount := unitPrice.Multiply(int64(*req.Quantity))
 
 		// Validate amount matches product total
-		if req.Amount != totalAmount {
+		if totalAmount.Amount != amount.Amount {
 			return nil, fmt.Errorf("payment amount does not match product total")
 		}
 
 		// Check stock availability
-		if product.Stock < int32(*req.Quantity) {
+		if available < int32(*req.Quantity) {
 			return nil, fmt.Errorf("insufficient stock")
 		}
 
@@ -138,13 +209,13 @@ func (s *PaymentServiceCQRS) CreatePayment(ctx context.Context, userID uint, req
 	cmd := command.CreatePaymentCommand{
 		UserID:          userID,
 		OrderID:         req.OrderID,
-		Amount:          req.Amount,
-		Currency:        req.Currency,
+		Amount:          amount,
 		PaymentMethod:   req.PaymentMethod,
 		PaymentMethodID: req.PaymentMethodID,
 		ReturnURL:       req.ReturnURL,
 		CancelURL:       req.CancelURL,
 		ProductID:       req.ProductID,
+		VariantID:       req.VariantID,
 		Quantity:        req.Quantity,
 		BasketID:        req.BasketID,
 	}
@@ -179,49 +250,27 @@ func (s *PaymentServiceCQRS) ProcessPayment(ctx context.Context, userID uint, pa
 		PaymentID:        paymentID,
 		PaymentMethodID:  req.PaymentMethodID,
 		ConfirmationData: req.ConfirmationData,
+		Actor:            domain.UserActor(userID),
 	}
 
-	// Process payment
-	paymentResp, err := s.processPaymentHandler.Handle(ctx, cmd)
-	if err != nil {
-		return nil, err
-	}
-
-	// If payment is successful, publish events for stock update and basket clearing
-	if paymentResp.Status == "completed" {
-		// Convert payment items for Kafka events
-		var items []kafka.PaymentItem
-		if payment.ProductID != nil && payment.Quantity != nil {
-			// Direct product purchase
-			items = []kafka.PaymentItem{
-				{
-					ProductID:  *payment.ProductID,
-					Quantity:   *payment.Quantity,
-					UnitPrice:  payment.Amount / float64(*payment.Quantity),
-					TotalPrice: payment.Amount,
-				},
-			}
-		} else if payment.BasketID != nil {
-			// Basket-based purchase - get items from basket
-			basket, err := s.basketClient.GetBasket(ctx, userID)
-			if err == nil {
-				for _, item := range basket.Items {
-					items = append(items, kafka.PaymentItem{
-						ProductID:  uint(item.ProductId),
-						Quantity:   int(item.Quantity),
-						UnitPrice:  item.UnitPrice,
-						TotalPrice: float64(item.Quantity) * item.UnitPrice,
-					})
-				}
-			}
+	var paymentResp *dto.PaymentResponse
+	err = s.transactor.Within(ctx, func(ctx context.Context) error {
+		// Process payment
+		var err error
+		paymentResp, err = s.processPaymentHandler.Handle(ctx, cmd)
+		if err != nil {
+			return err
 		}
 
-		// Publish payment completed event
-		if err := s.eventPublisher.PublishPaymentCompleted(ctx, paymentID, userID, payment.OrderID,
-			payment.Amount, payment.Currency, string(payment.PaymentMethod), items, payment.BasketID); err != nil {
-			// Log error but don't fail the payment
-			// In production, you might want to implement compensation logic
+		// If payment is successful, publish events for stock update and basket clearing;
+		// the events commit together with the payment so they cannot be lost
+		if paymentResp.Status == string(domain.PaymentStatusCompleted) {
+			return s.publishPaymentCompleted(ctx, payment)
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return paymentResp, nil
@@ -240,21 +289,34 @@ func (s *PaymentServiceCQRS) CancelPayment(ctx context.Context, userID uint, pay
 		return nil, domain.ErrPaymentNotFound
 	}
 
+	return s.cancelPayment(ctx, payment, domain.UserActor(userID), "payment_cancelled")
+}
+
+// cancelPayment cancels a payment at the gateway and publishes its cancellation
+func (s *PaymentServiceCQRS) cancelPayment(ctx context.Context, payment *domain.Payment, actor, reason string) (*dto.PaymentResponse, error) {
 	cmd := command.CancelPaymentCommand{
-		PaymentID: paymentID,
+		PaymentID: payment.ID,
+		Actor:     actor,
 	}
 
-	// Cancel payment
-	paymentResp, err := s.cancelPaymentHandler.Handle(ctx, cmd)
+	var paymentResp *dto.PaymentResponse
+	err := s.transactor.Within(ctx, func(ctx context.Context) error {
+		// Cancel payment
+		var err error
+		paymentResp, err = s.cancelPaymentHandler.Handle(ctx, cmd)
+		if err != nil {
+			return err
+		}
+
+		// Anything held for the payment, such as the stock reserved by its checkout,
+		// is released when the event is consumed
+		return s.eventPublisher.PublishPaymentCancelled(ctx, payment.ID, payment.UserID, payment.OrderID,
+			payment.Amount(), string(payment.PaymentMethod), reason, payment.BasketID)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// Release reservations if payment was cancelled
-	if paymentResp.Status == "cancelled" {
-		// Note: In a real implementation, you would release reservations here
-	}
-
 	return paymentResp, nil
 }
 
@@ -270,6 +332,236 @@ func (s *PaymentServiceCQRS) ListPayments(ctx context.Context, req dto.ListPayme
 	return s.listPaymentsHandler.Handle(ctx, query)
 }
 
+// Authorize/capture operations
+
+// AuthorizePayment puts a hold on the payment funds without charging them
+func (s *PaymentServiceCQRS) AuthorizePayment(ctx context.Context, userID uint, paymentID string, req dto.AuthorizePaymentRequest) (*dto.PaymentResponse, error) {
+	// Check if user owns the payment
+	if _, err := s.getUserPayment(ctx, userID, paymentID); err != nil {
+		return nil, err
+	}
+
+	cmd := command.AuthorizePaymentCommand{
+		PaymentID:       paymentID,
+		PaymentMethodID: req.PaymentMethodID,
+		Actor:           domain.UserActor(userID),
+	}
+
+	return s.authorizePaymentHandler.Handle(ctx, cmd)
+}
+
+// CapturePayment charges the authorized funds of the user's payment
+func (s *PaymentServiceCQRS) CapturePayment(ctx context.Context, userID uint, paymentID string, req dto.CapturePaymentRequest) (*dto.PaymentResponse, error) {
+	// Check if user owns the payment
+	if _, err := s.getUserPayment(ctx, userID, paymentID); err != nil {
+		return nil, err
+	}
+
+	return s.capturePayment(ctx, domain.UserActor(userID), paymentID, req)
+}
+
+// VoidAuthorization releases the authorized funds of the user's payment
+func (s *PaymentServiceCQRS) VoidAuthorization(ctx context.Context, userID uint, paymentID string) (*dto.PaymentResponse, error) {
+	// Check if user owns the payment
+	if _, err := s.getUserPayment(ctx, userID, paymentID); err != nil {
+		return nil, err
+	}
+
+	return s.voidAuthorization(ctx, domain.UserActor(userID), paymentID)
+}
+
+// getUserPayment gets a payment and checks that it belongs to the user
+func (s *PaymentServiceCQRS) getUserPayment(ctx context.Context, userID uint, paymentID string) (*domain.Payment, error) {
+	payment, err := s.paymentRepo.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if payment.UserID != userID {
+		return nil, domain.ErrPaymentNotFound
+	}
+
+	return payment, nil
+}
+
+// publishPaymentCompleted publishes the payment completed event used for stock update and basket clearing
+func (s *PaymentServiceCQRS) publishPaymentCompleted(ctx context.Context, payment *domain.Payment) error {
+	saga, err := s.checkoutRepo.GetByPaymentID(ctx, payment.ID)
+	if err != nil && !errors.Is(err, domain.ErrCheckoutNotFound) {
+		return err
+	}
+
+	// Convert payment items for Kafka events
+	var items []kafka.PaymentItem
+	if saga != nil {
+		// Checkout payment - the items were taken from the basket when the checkout started,
+		// and the product service confirms the stock reserved for the payment
+		items = checkoutItems(saga)
+	} else if payment.ProductID != nil && payment.Quantity != nil {
+		// Direct product purchase
+		items = []kafka.PaymentItem{directPurchaseItem(payment)}
+	} else if payment.BasketID != nil {
+		// Basket-based purchase - get items from basket
+		basket, err := s.basketClient.GetBasket(ctx, payment.UserID)
+		if err == nil {
+			for _, item := range basket.Items {
+				unitPrice, err := money.FromMinorOrMajor(item.UnitPriceMinor, item.UnitPrice, basket.Currency)
+				if err != nil {
+					continue
+				}
+				items = append(items, kafka.PaymentItem{
+					ProductID:       uint(item.ProductId),
+					VariantID:       client.BasketItemVariantID(item),
+					Quantity:        int(item.Quantity),
+					UnitPriceMinor:  unitPrice.Amount,
+					TotalPriceMinor: unitPrice.Multiply(int64(item.Quantity)).Amount,
+				})
+			}
+		}
+	}
+
+	return s.eventPublisher.PublishPaymentCompleted(ctx, payment.ID, payment.UserID, payment.OrderID,
+		payment.ChargedAmount(), string(payment.PaymentMethod), items, payment.BasketID)
+}
+
+// publishPaymentRefunded publishes the payment refunded event used for restocking
+func (s *PaymentServiceCQRS) publishPaymentRefunded(ctx context.Context, payment *domain.Payment, refund *domain.Refund, totalRefunded money.Money) error {
+	data := kafka.PaymentRefundedData{
+		PaymentID:          payment.ID,
+		RefundID:           refund.ID,
+		UserID:             payment.UserID,
+		OrderID:            payment.OrderID,
+		AmountMinor:        refund.AmountMinor,
+		TotalRefundedMinor: totalRefunded.Amount,
+		Currency:           payment.Currency,
+		Reason:             refund.Reason,
+		FullRefund:         payment.IsRefunded(),
+		BasketID:           payment.BasketID,
+	}
+
+	// Stock is returned once the payment is fully refunded, from the items of its checkout or
+	// of the direct product purchase; items of other basket payments are not stored
+	if data.FullRefund {
+		saga, err := s.checkoutRepo.GetByPaymentID(ctx, payment.ID)
+		if err != nil && !errors.Is(err, domain.ErrCheckoutNotFound) {
+			return err
+		}
+
+		if saga != nil {
+			data.Items = checkoutItems(saga)
+		} else if payment.ProductID != nil && payment.Quantity != nil {
+			data.Items = []kafka.PaymentItem{directPurchaseItem(payment)}
+		}
+	}
+
+	return s.eventPublisher.PublishPaymentRefunded(ctx, data)
+}
+
+// checkoutItems builds the event items of a checkout payment
+func checkoutItems(saga *domain.CheckoutSaga) []kafka.PaymentItem {
+	items := make([]kafka.PaymentItem, 0, len(saga.Items))
+	for _, item := range saga.Items {
+		items = append(items, kafka.PaymentItem{
+			ProductID:       item.ProductID,
+			VariantID:       item.VariantID,
+			Quantity:        item.Quantity,
+			UnitPriceMinor:  item.UnitPriceMinor,
+			TotalPriceMinor: item.UnitPriceMinor * int64(item.Quantity),
+		})
+	}
+	return items
+}
+
+// directPurchaseItem builds the event item of a direct product purchase
+func directPurchaseItem(payment *domain.Payment) kafka.PaymentItem {
+	return kafka.PaymentItem{
+		ProductID:       *payment.ProductID,
+		VariantID:       payment.VariantID,
+		Quantity:        *payment.Quantity,
+		UnitPriceMinor:  payment.AmountMinor / int64(*payment.Quantity),
+		TotalPriceMinor: payment.AmountMinor,
+	}
+}
+
+// Webhook operations
+
+// ProcessWebhook applies a payment gateway webhook and publishes the resulting events
+func (s *PaymentServiceCQRS) ProcessWebhook(ctx context.Context, provider string, payload []byte, signature string) (*dto.WebhookResponse, error) {
+	cmd := command.ProcessWebhookCommand{
+		Provider:  provider,
+		Payload:   payload,
+		Signature: signature,
+	}
+
+	var result *command.ProcessWebhookResult
+	err := s.transactor.Within(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = s.processWebhookHandler.Handle(ctx, cmd)
+		if err != nil {
+			return err
+		}
+		if result.Duplicate || result.Ignored || (!result.StatusChanged() && len(result.Refunds) == 0) {
+			return nil
+		}
+
+		// Publish the event matching the new payment status; if it cannot be stored the
+		// whole change rolls back and the provider retries the webhook
+		payment := result.Payment
+		switch payment.Status {
+		case domain.PaymentStatusCompleted:
+			return s.publishPaymentCompleted(ctx, payment)
+		case domain.PaymentStatusFailed:
+			return s.eventPublisher.PublishPaymentFailed(ctx, payment.ID, payment.UserID, payment.OrderID,
+				payment.Amount(), string(payment.PaymentMethod), result.Event.Reason, payment.BasketID)
+		case domain.PaymentStatusCancelled:
+			return s.eventPublisher.PublishPaymentCancelled(ctx, payment.ID, payment.UserID, payment.OrderID,
+				payment.Amount(), string(payment.PaymentMethod), result.Event.Reason, payment.BasketID)
+		case domain.PaymentStatusRefunded, domain.PaymentStatusPartiallyRefunded:
+			return s.publishWebhookRefunds(ctx, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Duplicate {
+		return &dto.WebhookResponse{Success: true, Message: "event already processed"}, nil
+	}
+	if result.Ignored || (!result.StatusChanged() && len(result.Refunds) == 0) {
+		return &dto.WebhookResponse{Success: true, Message: "event ignored"}, nil
+	}
+
+	return &dto.WebhookResponse{Success: true, Message: "event processed", RefundsCompleted: len(result.Refunds)}, nil
+}
+
+// publishWebhookRefunds publishes refund events for the refunds completed by a webhook
+func (s *PaymentServiceCQRS) publishWebhookRefunds(ctx context.Context, result *command.ProcessWebhookResult) error {
+	payment := result.Payment
+	refunds := result.Refunds
+	amount := result.Event.AmountIn(payment.Currency)
+
+	// Refunds issued from the provider dashboard have no local refund record
+	if len(refunds) == 0 {
+		refunds = []*domain.Refund{{
+			ID:          result.Event.ID,
+			PaymentID:   payment.ID,
+			AmountMinor: amount.Amount,
+			Currency:    amount.Currency,
+			Reason:      result.Event.Reason,
+			Status:      domain.RefundStatusCompleted,
+		}}
+	}
+
+	// The provider reports the cumulative refunded amount of the charge
+	for _, refund := range refunds {
+		if err := s.publishPaymentRefunded(ctx, payment, refund, amount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Payment method operations
 
 // GetPaymentMethods gets user's payment methods
@@ -375,16 +667,119 @@ func (s *PaymentServiceCQRS) AdminGetPayment(ctx context.Context, paymentID stri
 	return nil, domain.ErrPaymentNotFound
 }
 
-// AdminUpdatePaymentStatus updates payment status (admin only)
-func (s *PaymentServiceCQRS) AdminUpdatePaymentStatus(ctx context.Context, paymentID string, req dto.UpdatePaymentStatusRequest) (*dto.PaymentResponse, error) {
-	// TODO: Implement admin update payment status
-	return nil, domain.ErrPaymentNotFound
+// AdminUpdatePaymentStatus moves a payment to another status, as allowed by the transition table (admin only)
+func (s *PaymentServiceCQRS) AdminUpdatePaymentStatus(ctx context.Context, adminID uint, paymentID string, req dto.UpdatePaymentStatusRequest) (*dto.PaymentResponse, error) {
+	cmd := command.UpdatePaymentStatusCommand{
+		PaymentID: paymentID,
+		Status:    domain.PaymentStatus(req.Status),
+		Reason:    req.Reason,
+		Actor:     domain.AdminActor(adminID),
+	}
+
+	return s.updatePaymentStatusHandler.Handle(ctx, cmd)
+}
+
+// AdminGetPaymentHistory gets the status history of a payment (admin only)
+func (s *PaymentServiceCQRS) AdminGetPaymentHistory(ctx context.Context, paymentID string) (*dto.PaymentStatusHistoryListResponse, error) {
+	query := query.GetPaymentHistoryQuery{
+		PaymentID: paymentID,
+	}
+
+	return s.getPaymentHistoryHandler.Handle(ctx, query)
+}
+
+// AdminCapturePayment captures an authorized payment, fully or partially (admin only)
+func (s *PaymentServiceCQRS) AdminCapturePayment(ctx context.Context, adminID uint, paymentID string, req dto.CapturePaymentRequest) (*dto.PaymentResponse, error) {
+	return s.capturePayment(ctx, domain.AdminActor(adminID), paymentID, req)
+}
+
+// capturePayment captures an authorized payment on behalf of the actor
+func (s *PaymentServiceCQRS) capturePayment(ctx context.Context, actor, paymentID string, req dto.CapturePaymentRequest) (*dto.PaymentResponse, error) {
+	cmd := command.CapturePaymentCommand{
+		PaymentID: paymentID,
+		Amount:    req.Amount,
+		Actor:     actor,
+	}
+
+	var paymentResp *dto.PaymentResponse
+	err := s.transactor.Within(ctx, func(ctx context.Context) error {
+		var err error
+		paymentResp, err = s.capturePaymentHandler.Handle(ctx, cmd)
+		if err != nil {
+			return err
+		}
+
+		// Funds are charged now, so stock can be updated and the basket cleared
+		if paymentResp.Status != string(domain.PaymentStatusCompleted) {
+			return nil
+		}
+		payment, err := s.paymentRepo.GetByID(ctx, paymentID)
+		if err != nil {
+			return err
+		}
+		return s.publishPaymentCompleted(ctx, payment)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return paymentResp, nil
+}
+
+// AdminVoidAuthorization releases the hold on an authorized payment (admin only)
+func (s *PaymentServiceCQRS) AdminVoidAuthorization(ctx context.Context, adminID uint, paymentID string) (*dto.PaymentResponse, error) {
+	return s.voidAuthorization(ctx, domain.AdminActor(adminID), paymentID)
+}
+
+// voidAuthorization releases the hold on an authorized payment on behalf of the actor
+func (s *PaymentServiceCQRS) voidAuthorization(ctx context.Context, actor, paymentID string) (*dto.PaymentResponse, error) {
+	cmd := command.VoidAuthorizationCommand{
+		PaymentID: paymentID,
+		Actor:     actor,
+	}
+
+	var paymentResp *dto.PaymentResponse
+	err := s.transactor.Within(ctx, func(ctx context.Context) error {
+		var err error
+		paymentResp, err = s.voidAuthorizationHandler.Handle(ctx, cmd)
+		if err != nil {
+			return err
+		}
+
+		// Release anything held for the payment
+		payment, err := s.paymentRepo.GetByID(ctx, paymentID)
+		if err != nil {
+			return err
+		}
+		return s.eventPublisher.PublishPaymentCancelled(ctx, payment.ID, payment.UserID, payment.OrderID,
+			payment.Amount(), string(payment.PaymentMethod), "authorization_voided", payment.BasketID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return paymentResp, nil
 }
 
 // CreateRefund creates a refund (admin only)
 func (s *PaymentServiceCQRS) CreateRefund(ctx context.Context, req dto.CreateRefundRequest) (*dto.RefundResponse, error) {
-	// TODO: Implement create refund
-	return nil, domain.ErrRefundNotFound
+	cmd := command.CreateRefundCommand{
+		PaymentID: req.PaymentID,
+		Amount:    req.Amount,
+		Reason:    req.Reason,
+	}
+
+	var refundResp *dto.RefundResponse
+	err := s.transactor.Within(ctx, func(ctx context.Context) error {
+		var err error
+		refundResp, err = s.createRefundHandler.Handle(ctx, cmd)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return refundResp, nil
 }
 
 // AdminListRefunds lists all refunds (admin only)
@@ -400,26 +795,197 @@ func (s *PaymentServiceCQRS) AdminListRefunds(ctx context.Context, req dto.Admin
 
 // AdminGetRefund gets refund by ID (admin only)
 func (s *PaymentServiceCQRS) AdminGetRefund(ctx context.Context, refundID string) (*dto.RefundResponse, error) {
-	// TODO: Implement admin get refund
-	return nil, domain.ErrRefundNotFound
+	query := query.GetRefundQuery{
+		RefundID: refundID,
+	}
+
+	return s.getRefundHandler.Handle(ctx, query)
 }
 
 // ProcessRefund processes a refund (admin only)
-func (s *PaymentServiceCQRS) ProcessRefund(ctx context.Context, refundID string) (*dto.RefundResponse, error) {
-	// TODO: Implement process refund
-	return nil, domain.ErrRefundNotFound
+func (s *PaymentServiceCQRS) ProcessRefund(ctx context.Context, adminID uint, refundID string) (*dto.RefundResponse, error) {
+	var result *command.ProcessRefundResult
+	err := s.transactor.Within(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = s.processRefund(ctx, domain.AdminActor(adminID), refundID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The failed refund is committed before the gateway error is reported
+	if result.GatewayError != nil {
+		return nil, result.GatewayError
+	}
+
+	refund := result.Refund
+
+	return &dto.RefundResponse{
+		ID:            refund.ID,
+		PaymentID:     refund.PaymentID,
+		Amount:        refund.Amount().Major(),
+		AmountMinor:   refund.AmountMinor,
+		Currency:      refund.Currency,
+		Reason:        refund.Reason,
+		Status:        refund.Status,
+		TransactionID: refund.TransactionID,
+		CreatedAt:     refund.CreatedAt,
+		UpdatedAt:     refund.UpdatedAt,
+		CompletedAt:   refund.CompletedAt,
+	}, nil
+}
+
+// processRefund submits a pending refund to the gateway; ctx must carry a transaction
+func (s *PaymentServiceCQRS) processRefund(ctx context.Context, actor, refundID string) (*command.ProcessRefundResult, error) {
+	result, err := s.processRefundHandler.Handle(ctx, command.ProcessRefundCommand{
+		RefundID: refundID,
+		Actor:    actor,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Publish refund event for restocking once the gateway confirmed the refund;
+	// refunds still processing are published when the webhook completes them
+	if result.Refund.IsCompleted() {
+		if err := s.publishPaymentRefunded(ctx, result.Payment, result.Refund, result.Payment.RefundedAmount(result.Refunds)); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Order event operations
+
+// OrderCancelled cancels the payments of a cancelled order that are still pending
+func (s *PaymentServiceCQRS) OrderCancelled(ctx context.Context, orderID string) error {
+	payments, err := s.paymentRepo.ListByOrderID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	for _, payment := range payments {
+		// Payments completed in the meantime are refunded once the order rejects them
+		if !payment.CanBeCancelled() {
+			continue
+		}
+		_, err := s.cancelPayment(ctx, payment, domain.OrderActor(orderID), "order_cancelled")
+		if err != nil && !errors.Is(err, domain.ErrPaymentCannotBeCancelled) {
+			return fmt.Errorf("failed to cancel payment %s: %w", payment.ID, err)
+		}
+	}
+	return nil
+}
+
+// OrderPaymentRejected refunds what is left of a completed payment its order rejected,
+// e.g. because the order was cancelled before the payment completed. The refund is
+// committed before it is submitted, so a redelivered event submits the same refund
+// under the same gateway idempotency key. A refund the gateway failed is stored as
+// failed and the error returned, so the event is retried with a new refund.
+func (s *PaymentServiceCQRS) OrderPaymentRejected(ctx context.Context, orderID, paymentID, reason string) error {
+	var pending []string
+	err := s.transactor.Within(ctx, func(ctx context.Context) error {
+		payment, err := s.paymentRepo.GetByIDForUpdate(ctx, paymentID)
+		if err != nil {
+			return err
+		}
+		if !payment.CanBeRefunded() {
+			log.Printf("Payment %s rejected by order %s is %s, nothing to refund", paymentID, orderID, payment.Status)
+			return nil
+		}
+
+		// Pending refunds, such as one of an earlier delivery of the event, already count
+		// against what is left and are submitted along with the new one
+		refunds, err := s.refundRepo.GetByPaymentID(ctx, payment.ID)
+		if err != nil {
+			return err
+		}
+		for _, refund := range refunds {
+			if refund.IsPending() {
+				pending = append(pending, refund.ID)
+			}
+		}
+		remaining := payment.RefundableAmount(refunds)
+		if remaining.IsZero() {
+			return nil
+		}
+
+		refund, err := s.createRefundHandler.Handle(ctx, command.CreateRefundCommand{
+			PaymentID: payment.ID,
+			Amount:    remaining.Major(),
+			Reason:    "payment rejected by order: " + reason,
+		})
+		if err != nil {
+			return err
+		}
+		pending = append(pending, refund.ID)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, refundID := range pending {
+		var result *command.ProcessRefundResult
+		err := s.transactor.Within(ctx, func(ctx context.Context) error {
+			var err error
+			result, err = s.processRefund(ctx, domain.OrderActor(orderID), refundID)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to process refund %s: %w", refundID, err)
+		}
+		if result.GatewayError != nil {
+			return result.GatewayError
+		}
+	}
+	return nil
 }
 
 // GetPaymentStats gets payment statistics (admin only)
 func (s *PaymentServiceCQRS) GetPaymentStats(ctx context.Context, period string) (*dto.PaymentStatsResponse, error) {
-	// TODO: Implement get payment stats
+	endDate := time.Now().UTC()
+	var startDate time.Time
+	switch period {
+	case "daily":
+		startDate = endDate.AddDate(0, 0, -1)
+	case "weekly":
+		startDate = endDate.AddDate(0, 0, -7)
+	case "monthly":
+		startDate = endDate.AddDate(0, -1, 0)
+	case "yearly":
+		startDate = endDate.AddDate(-1, 0, 0)
+	default:
+		return nil, ErrInvalidStatsPeriod
+	}
+
+	start := startDate.Format(time.RFC3339)
+	end := endDate.Format(time.RFC3339)
+	stats, err := s.paymentRepo.GetPaymentStats(ctx, nil, &start, &end)
+	if err != nil {
+		return nil, err
+	}
+
 	return &dto.PaymentStatsResponse{
-		TotalPayments:      0,
-		TotalAmount:        0,
-		SuccessfulPayments: 0,
-		FailedPayments:     0,
-		PendingPayments:    0,
-		RefundedAmount:     0,
-		AverageAmount:      0,
+		TotalPayments:      stats.TotalPayments,
+		SuccessfulPayments: stats.SuccessfulPayments,
+		FailedPayments:     stats.FailedPayments,
+		PendingPayments:    stats.PendingPayments,
+		TotalAmounts:       toCurrencyTotalResponses(stats.TotalAmounts),
+		RefundedAmounts:    toCurrencyTotalResponses(stats.RefundedAmounts),
 	}, nil
 }
+
+// toCurrencyTotalResponses converts per-currency totals to their response form
+func toCurrencyTotalResponses(totals []domain.CurrencyTotal) []dto.CurrencyTotalResponse {
+	responses := make([]dto.CurrencyTotalResponse, len(totals))
+	for i, total := range totals {
+		responses[i] = dto.CurrencyTotalResponse{
+			Currency:     total.Currency,
+			Count:        total.Count,
+			AmountMinor:  total.AmountMinor,
+			AverageMinor: total.AverageMinor,
+		}
+	}
+	return responses
+}
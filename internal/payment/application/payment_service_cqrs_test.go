@@ -0,0 +1,316 @@
+package application_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ddd-micro/internal/payment/application"
+	"github.com/ddd-micro/internal/payment/application/command"
+	"github.com/ddd-micro/internal/payment/application/dto"
+	"github.com/ddd-micro/internal/payment/domain"
+	paymentkafka "github.com/ddd-micro/internal/payment/infrastructure/kafka"
+	"github.com/ddd-micro/internal/payment/infrastructure/persistence"
+	"github.com/ddd-micro/kafka"
+	"github.com/ddd-micro/pkg/gormtx"
+	"github.com/ddd-micro/pkg/money"
+	"github.com/ddd-micro/pkg/outbox"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	testAdminID   = 1
+	testPaymentID = "pay_test"
+)
+
+// fakeGateway records the captures and voids sent to the payment provider
+type fakeGateway struct {
+	domain.PaymentGateway
+	captureStatus domain.PaymentStatus
+	captured      []money.Money
+	voids         int
+}
+
+func (g *fakeGateway) CapturePayment(ctx context.Context, payment *domain.Payment, amount money.Money) (*domain.PaymentGatewayResponse, error) {
+	g.captured = append(g.captured, amount)
+	return &domain.PaymentGatewayResponse{
+		Success:       g.captureStatus == domain.PaymentStatusCompleted,
+		TransactionID: *payment.TransactionID,
+		Status:        g.captureStatus,
+	}, nil
+}
+
+func (g *fakeGateway) VoidAuthorization(ctx context.Context, payment *domain.Payment) (*domain.PaymentGatewayResponse, error) {
+	g.voids++
+	return &domain.PaymentGatewayResponse{
+		Success:       true,
+		TransactionID: *payment.TransactionID,
+		Status:        domain.PaymentStatusCancelled,
+	}, nil
+}
+
+// authorizeFlow runs the payment service on an authorized payment of 19.99 USD
+type authorizeFlow struct {
+	db       *gorm.DB
+	gateway  *fakeGateway
+	payments *application.PaymentServiceCQRS
+	repo     domain.PaymentRepository
+}
+
+func newAuthorizeFlow(t *testing.T, status domain.PaymentStatus, authorizationExpiresAt time.Time) *authorizeFlow {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database: %v", err)
+	}
+	// Every connection to :memory: opens its own database
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&domain.Payment{}, &domain.PaymentStatusHistory{}, &domain.CheckoutSaga{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	if err := outbox.Migrate(db); err != nil {
+		t.Fatalf("failed to migrate outbox: %v", err)
+	}
+
+	flow := &authorizeFlow{
+		db:      db,
+		gateway: &fakeGateway{captureStatus: domain.PaymentStatusCompleted},
+		repo:    persistence.NewPaymentRepository(db),
+	}
+	flow.payments = application.NewPaymentServiceCQRS(
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		command.NewCapturePaymentCommandHandler(flow.repo, flow.gateway),
+		command.NewVoidAuthorizationCommandHandler(flow.repo, flow.gateway),
+		nil, nil, nil, nil, nil, nil, nil,
+		flow.repo, nil, nil, persistence.NewCheckoutSagaRepository(db), nil, nil, nil, nil,
+		paymentkafka.NewPaymentEventPublisher(outbox.NewStore(db)), gormtx.NewTransactor(db))
+
+	transactionID := "pi_test"
+	payment := &domain.Payment{
+		ID:                     testPaymentID,
+		UserID:                 7,
+		OrderID:                "order-1",
+		AmountMinor:            1999,
+		Currency:               "USD",
+		Status:                 status,
+		PaymentMethod:          domain.PaymentMethodCreditCard,
+		PaymentProvider:        domain.ProviderMock,
+		TransactionID:          &transactionID,
+		AuthorizationExpiresAt: &authorizationExpiresAt,
+	}
+	if err := flow.repo.Create(t.Context(), payment); err != nil {
+		t.Fatalf("failed to create payment: %v", err)
+	}
+	return flow
+}
+
+func (f *authorizeFlow) payment(t *testing.T) *domain.Payment {
+	t.Helper()
+	payment, err := f.repo.GetByID(t.Context(), testPaymentID)
+	if err != nil {
+		t.Fatalf("failed to get payment: %v", err)
+	}
+	return payment
+}
+
+// events returns the events stored in the outbox, in order
+func (f *authorizeFlow) events(t *testing.T) []outbox.Message {
+	t.Helper()
+	var messages []outbox.Message
+	if err := f.db.Order("id").Find(&messages).Error; err != nil {
+		t.Fatalf("failed to load outbox: %v", err)
+	}
+	return messages
+}
+
+func TestCapturePayment(t *testing.T) {
+	// The provider keeps the funds on hold for seven days after the authorization
+	holdExpires := time.Now().Add(7*24*time.Hour - time.Hour)
+	partial := 12.50
+	tooMuch := 25.00
+
+	tests := []struct {
+		name          string
+		amount        *float64
+		captureStatus domain.PaymentStatus
+		holdExpires   time.Time
+		wantErr       error
+		wantCaptured  int64
+		wantStatus    domain.PaymentStatus
+	}{
+		{
+			name:          "full amount",
+			captureStatus: domain.PaymentStatusCompleted,
+			holdExpires:   holdExpires,
+			wantCaptured:  1999,
+			wantStatus:    domain.PaymentStatusCompleted,
+		},
+		{
+			name:          "partial amount",
+			amount:        &partial,
+			captureStatus: domain.PaymentStatusCompleted,
+			holdExpires:   holdExpires,
+			wantCaptured:  1250,
+			wantStatus:    domain.PaymentStatusCompleted,
+		},
+		{
+			name:          "still processing at the provider",
+			captureStatus: domain.PaymentStatusProcessing,
+			holdExpires:   holdExpires,
+			wantCaptured:  1999,
+			wantStatus:    domain.PaymentStatusProcessing,
+		},
+		{
+			name:        "more than the hold",
+			amount:      &tooMuch,
+			holdExpires: holdExpires,
+			wantErr:     domain.ErrCaptureAmountExceedsHold,
+			wantStatus:  domain.PaymentStatusAuthorized,
+		},
+		{
+			name:        "hold expired",
+			holdExpires: time.Now().Add(-time.Minute),
+			wantErr:     domain.ErrAuthorizationExpired,
+			wantStatus:  domain.PaymentStatusAuthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flow := newAuthorizeFlow(t, domain.PaymentStatusAuthorized, tt.holdExpires)
+			flow.gateway.captureStatus = tt.captureStatus
+
+			resp, err := flow.payments.AdminCapturePayment(t.Context(), testAdminID, testPaymentID, dto.CapturePaymentRequest{Amount: tt.amount})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("AdminCapturePayment() error = %v, want %v", err, tt.wantErr)
+				}
+				if len(flow.gateway.captured) != 0 {
+					t.Errorf("captured %v at the provider, want nothing", flow.gateway.captured)
+				}
+				if len(flow.events(t)) != 0 {
+					t.Error("published events for a rejected capture")
+				}
+				if status := flow.payment(t).Status; status != tt.wantStatus {
+					t.Errorf("status = %s, want %s", status, tt.wantStatus)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AdminCapturePayment() error = %v", err)
+			}
+
+			// The provider is asked for exactly the amount to capture
+			if len(flow.gateway.captured) != 1 || flow.gateway.captured[0] != money.New(tt.wantCaptured, "USD") {
+				t.Fatalf("captured %v at the provider, want %d", flow.gateway.captured, tt.wantCaptured)
+			}
+
+			payment := flow.payment(t)
+			if payment.Status != tt.wantStatus || resp.Status != string(tt.wantStatus) {
+				t.Fatalf("status = %s (response %s), want %s", payment.Status, resp.Status, tt.wantStatus)
+			}
+			events := flow.events(t)
+			if tt.wantStatus != domain.PaymentStatusCompleted {
+				if len(events) != 0 {
+					t.Errorf("published %d events before the funds were captured, want none", len(events))
+				}
+				return
+			}
+
+			if payment.ChargedAmount().Amount != tt.wantCaptured || resp.CapturedMinor != tt.wantCaptured {
+				t.Errorf("charged %d (response %d), want %d", payment.ChargedAmount().Amount, resp.CapturedMinor, tt.wantCaptured)
+			}
+			if payment.AmountMinor != 1999 {
+				t.Errorf("authorized amount = %d, want it kept at 1999", payment.AmountMinor)
+			}
+
+			// Only the captured amount is reported as paid
+			if len(events) != 1 || events[0].EventType != string(kafka.EventTypePaymentCompleted) {
+				t.Fatalf("published %+v, want one payment completed event", events)
+			}
+			var event kafka.PaymentCompletedEvent
+			if err := json.Unmarshal(events[0].Payload, &event); err != nil {
+				t.Fatalf("failed to decode event: %v", err)
+			}
+			if event.Data.AmountMinor != tt.wantCaptured {
+				t.Errorf("payment completed amount = %d, want %d", event.Data.AmountMinor, tt.wantCaptured)
+			}
+		})
+	}
+}
+
+func TestVoidAuthorization(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    domain.PaymentStatus
+		wantErr   error
+		wantVoids int
+	}{
+		{name: "authorized", status: domain.PaymentStatusAuthorized, wantVoids: 1},
+		{name: "already captured", status: domain.PaymentStatusCompleted, wantErr: domain.ErrPaymentCannotBeVoided},
+		{name: "already voided", status: domain.PaymentStatusCancelled, wantErr: domain.ErrPaymentCannotBeVoided},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flow := newAuthorizeFlow(t, tt.status, time.Now().Add(24*time.Hour))
+
+			resp, err := flow.payments.AdminVoidAuthorization(t.Context(), testAdminID, testPaymentID)
+			if flow.gateway.voids != tt.wantVoids {
+				t.Errorf("voided %d times at the provider, want %d", flow.gateway.voids, tt.wantVoids)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("AdminVoidAuthorization() error = %v, want %v", err, tt.wantErr)
+				}
+				if status := flow.payment(t).Status; status != tt.status {
+					t.Errorf("status = %s, want it unchanged", status)
+				}
+				if len(flow.events(t)) != 0 {
+					t.Error("published events for a rejected void")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AdminVoidAuthorization() error = %v", err)
+			}
+
+			if payment := flow.payment(t); payment.Status != domain.PaymentStatusCancelled || resp.Status != string(domain.PaymentStatusCancelled) {
+				t.Fatalf("status = %s (response %s), want cancelled", payment.Status, resp.Status)
+			}
+
+			// The released hold is published so that whatever was held for the payment is released too
+			events := flow.events(t)
+			if len(events) != 1 || events[0].EventType != string(kafka.EventTypePaymentCancelled) {
+				t.Fatalf("published %+v, want one payment cancelled event", events)
+			}
+			var event kafka.PaymentCancelledEvent
+			if err := json.Unmarshal(events[0].Payload, &event); err != nil {
+				t.Fatalf("failed to decode event: %v", err)
+			}
+			if event.Data.Reason != "authorization_voided" || event.Data.AmountMinor != 1999 {
+				t.Errorf("payment cancelled data = %+v", event.Data)
+			}
+
+			var history []domain.PaymentStatusHistory
+			if err := flow.db.Where("payment_id = ?", testPaymentID).Order("id").Find(&history).Error; err != nil {
+				t.Fatalf("failed to load history: %v", err)
+			}
+			last := history[len(history)-1]
+			if last.ToStatus != domain.PaymentStatusCancelled || last.Actor != domain.AdminActor(testAdminID) {
+				t.Errorf("last transition = %+v, want cancelled by the admin", last)
+			}
+		})
+	}
+}
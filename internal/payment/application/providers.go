@@ -9,6 +9,7 @@ import (
 // ProviderSet is the Wire provider set for application layer
 var ProviderSet = wire.NewSet(
 	NewPaymentServiceCQRS,
+	NewCheckoutOrchestrator,
 	// Command handlers
 	command.NewCreatePaymentCommandHandler,
 	command.NewProcessPaymentCommandHandler,
@@ -16,9 +17,18 @@ var ProviderSet = wire.NewSet(
 	command.NewAddPaymentMethodCommandHandler,
 	command.NewUpdatePaymentMethodCommandHandler,
 	command.NewDeletePaymentMethodCommandHandler,
+	command.NewProcessWebhookCommandHandler,
+	command.NewCreateRefundCommandHandler,
+	command.NewProcessRefundCommandHandler,
+	command.NewAuthorizePaymentCommandHandler,
+	command.NewCapturePaymentCommandHandler,
+	command.NewVoidAuthorizationCommandHandler,
+	command.NewUpdatePaymentStatusCommandHandler,
 	// Query handlers
 	query.NewGetPaymentQueryHandler,
 	query.NewListPaymentsQueryHandler,
 	query.NewGetPaymentMethodQueryHandler,
 	query.NewListPaymentMethodsQueryHandler,
+	query.NewGetRefundQueryHandler,
+	query.NewGetPaymentHistoryQueryHandler,
 )
@@ -42,7 +42,8 @@ func (h *GetPaymentQueryHandler) Handle(ctx context.Context, query GetPaymentQue
 		ID:              payment.ID,
 		UserID:          payment.UserID,
 		OrderID:         payment.OrderID,
-		Amount:          payment.Amount,
+		Amount:          payment.Amount().Major(),
+		AmountMinor:     payment.AmountMinor,
 		Currency:        payment.Currency,
 		Status:          string(payment.Status),
 		PaymentMethod:   string(payment.PaymentMethod),
@@ -53,5 +54,8 @@ func (h *GetPaymentQueryHandler) Handle(ctx context.Context, query GetPaymentQue
 		UpdatedAt:       payment.UpdatedAt,
 		CompletedAt:     payment.CompletedAt,
 		ExpiresAt:       payment.ExpiresAt,
+		CapturedAmount:  payment.Captured().Major(),
+		CapturedMinor:   payment.Captured().Amount,
+		AuthExpiresAt:   payment.AuthorizationExpiresAt,
 	}, nil
 }
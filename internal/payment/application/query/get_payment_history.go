@@ -0,0 +1,56 @@
+package query
+
+import (
+	"context"
+
+	"github.com/ddd-micro/internal/payment/application/dto"
+	"github.com/ddd-micro/internal/payment/domain"
+)
+
+// GetPaymentHistoryQuery represents the query to get the status history of a payment
+type GetPaymentHistoryQuery struct {
+	PaymentID string
+}
+
+// GetPaymentHistoryQueryHandler handles the get payment history query
+type GetPaymentHistoryQueryHandler struct {
+	paymentRepo domain.PaymentRepository
+}
+
+// NewGetPaymentHistoryQueryHandler creates a new get payment history query handler
+func NewGetPaymentHistoryQueryHandler(paymentRepo domain.PaymentRepository) *GetPaymentHistoryQueryHandler {
+	return &GetPaymentHistoryQueryHandler{
+		paymentRepo: paymentRepo,
+	}
+}
+
+// Handle handles the get payment history query
+func (h *GetPaymentHistoryQueryHandler) Handle(ctx context.Context, query GetPaymentHistoryQuery) (*dto.PaymentStatusHistoryListResponse, error) {
+	// Make sure the payment exists
+	if _, err := h.paymentRepo.GetByID(ctx, query.PaymentID); err != nil {
+		return nil, err
+	}
+
+	history, err := h.paymentRepo.GetStatusHistory(ctx, query.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to DTOs
+	entries := make([]dto.PaymentStatusHistoryResponse, len(history))
+	for i, entry := range history {
+		entries[i] = dto.PaymentStatusHistoryResponse{
+			FromStatus:     string(entry.FromStatus),
+			ToStatus:       string(entry.ToStatus),
+			Actor:          entry.Actor,
+			Reason:         entry.Reason,
+			GatewayEventID: entry.GatewayEventID,
+			CreatedAt:      entry.CreatedAt,
+		}
+	}
+
+	return &dto.PaymentStatusHistoryListResponse{
+		PaymentID: query.PaymentID,
+		History:   entries,
+	}, nil
+}
@@ -0,0 +1,48 @@
+package query
+
+import (
+	"context"
+
+	"github.com/ddd-micro/internal/payment/application/dto"
+	"github.com/ddd-micro/internal/payment/domain"
+)
+
+// GetRefundQuery represents the query to get a refund
+type GetRefundQuery struct {
+	RefundID string
+}
+
+// GetRefundQueryHandler handles the get refund query
+type GetRefundQueryHandler struct {
+	refundRepo domain.RefundRepository
+}
+
+// NewGetRefundQueryHandler creates a new get refund query handler
+func NewGetRefundQueryHandler(refundRepo domain.RefundRepository) *GetRefundQueryHandler {
+	return &GetRefundQueryHandler{
+		refundRepo: refundRepo,
+	}
+}
+
+// Handle handles the get refund query
+func (h *GetRefundQueryHandler) Handle(ctx context.Context, query GetRefundQuery) (*dto.RefundResponse, error) {
+	refund, err := h.refundRepo.GetByID(ctx, query.RefundID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert to DTO
+	return &dto.RefundResponse{
+		ID:            refund.ID,
+		PaymentID:     refund.PaymentID,
+		Amount:        refund.Amount().Major(),
+		AmountMinor:   refund.AmountMinor,
+		Currency:      refund.Currency,
+		Reason:        refund.Reason,
+		Status:        refund.Status,
+		TransactionID: refund.TransactionID,
+		CreatedAt:     refund.CreatedAt,
+		UpdatedAt:     refund.UpdatedAt,
+		CompletedAt:   refund.CompletedAt,
+	}, nil
+}
@@ -45,7 +45,8 @@ func (h *ListPaymentsQueryHandler) Handle(ctx context.Context, query ListPayment
 			ID:              payment.ID,
 			UserID:          payment.UserID,
 			OrderID:         payment.OrderID,
-			Amount:          payment.Amount,
+			Amount:          payment.Amount().Major(),
+			AmountMinor:     payment.AmountMinor,
 			Currency:        payment.Currency,
 			Status:          string(payment.Status),
 			PaymentMethod:   string(payment.PaymentMethod),
@@ -56,6 +57,9 @@ func (h *ListPaymentsQueryHandler) Handle(ctx context.Context, query ListPayment
 			UpdatedAt:       payment.UpdatedAt,
 			CompletedAt:     payment.CompletedAt,
 			ExpiresAt:       payment.ExpiresAt,
+			CapturedAmount:  payment.Captured().Major(),
+			CapturedMinor:   payment.Captured().Amount,
+			AuthExpiresAt:   payment.AuthorizationExpiresAt,
 		}
 	}
 
@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"slices"
+	"time"
+
+	"github.com/ddd-micro/pkg/money"
+)
+
+// CheckoutStep is a step of the checkout saga
+type CheckoutStep string
+
+const (
+	CheckoutStepValidateBasket CheckoutStep = "validate_basket"
+	CheckoutStepReserveStock   CheckoutStep = "reserve_stock"
+	CheckoutStepCreatePayment  CheckoutStep = "create_payment"
+	CheckoutStepConfirmPayment CheckoutStep = "confirm_payment"
+	CheckoutStepClearBasket    CheckoutStep = "clear_basket"
+)
+
+// checkoutSteps lists the checkout steps in the order they run; compensation walks them backwards
+var checkoutSteps = []CheckoutStep{
+	CheckoutStepValidateBasket,
+	CheckoutStepReserveStock,
+	CheckoutStepCreatePayment,
+	CheckoutStepConfirmPayment,
+	CheckoutStepClearBasket,
+}
+
+// Before reports whether the step runs before another one
+func (s CheckoutStep) Before(other CheckoutStep) bool {
+	return slices.Index(checkoutSteps, s) < slices.Index(checkoutSteps, other)
+}
+
+// CheckoutStatus represents the status of a checkout saga
+type CheckoutStatus string
+
+const (
+	CheckoutStatusRunning      CheckoutStatus = "running"
+	CheckoutStatusCompensating CheckoutStatus = "compensating"
+	CheckoutStatusCompleted    CheckoutStatus = "completed"
+	CheckoutStatusCompensated  CheckoutStatus = "compensated"
+	// CheckoutStatusFailed marks a checkout that could not be compensated and needs manual review
+	CheckoutStatusFailed CheckoutStatus = "failed"
+)
+
+// CheckoutItem is a basket item taken into a checkout, priced in minor units of the checkout currency
+type CheckoutItem struct {
+	ProductID      uint  `json:"product_id"`
+	VariantID      *uint `json:"variant_id,omitempty"`
+	Quantity       int   `json:"quantity"`
+	UnitPriceMinor int64 `json:"unit_price_minor"`
+	// Reserved is set while the quantity is held in the stock reservation of the payment
+	Reserved bool `json:"reserved"`
+}
+
+// CheckoutSaga coordinates the checkout of a basket across the basket, product and payment
+// services. Its steps run in order; when one fails, the steps before it are compensated in
+// reverse order. The saga is stored after every change so another instance can resume it.
+type CheckoutSaga struct {
+	ID              string         `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	UserID          uint           `json:"user_id" gorm:"not null;index"`
+	BasketID        string         `json:"basket_id" gorm:"type:varchar(36);not null"`
+	OrderID         string         `json:"order_id" gorm:"type:varchar(36);not null"`
+	PaymentID       string         `json:"payment_id" gorm:"type:varchar(36);not null;uniqueIndex"` // Assigned up front so payment creation can be retried
+	AmountMinor     int64          `json:"amount_minor" gorm:"not null"`                            // Amount in minor units of Currency
+	Currency        string         `json:"currency" gorm:"type:varchar(3);not null"`
+	PaymentMethod   string         `json:"payment_method" gorm:"type:varchar(20);not null"`
+	PaymentMethodID string         `json:"payment_method_id" gorm:"type:varchar(36)"`
+	ReturnURL       string         `json:"return_url" gorm:"type:text"`
+	CancelURL       string         `json:"cancel_url" gorm:"type:text"`
+	Items           []CheckoutItem `json:"items" gorm:"type:jsonb;serializer:json"`
+	Step            CheckoutStep   `json:"step" gorm:"type:varchar(30);not null"`
+	Status          CheckoutStatus `json:"status" gorm:"type:varchar(20);not null;index:idx_checkout_sagas_due,priority:1"`
+	FailureReason   string         `json:"failure_reason" gorm:"type:text"`
+	Attempts        int            `json:"attempts" gorm:"not null;default:0"`
+	LastError       string         `json:"last_error" gorm:"type:text"`
+	// StepDeadline is when the current step times out
+	StepDeadline time.Time `json:"step_deadline"`
+	// NextAttemptAt is when recovery picks the saga up; whoever works on the saga pushes
+	// it ahead as a lease, so a crashed instance's sagas are resumed once it lapses
+	NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"index:idx_checkout_sagas_due,priority:2"`
+	Version       int        `json:"version" gorm:"not null;default:0"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	CompletedAt   *time.Time `json:"completed_at"`
+}
+
+// TableName returns the table name for CheckoutSaga
+func (CheckoutSaga) TableName() string {
+	return "checkout_sagas"
+}
+
+// Amount returns the checkout amount as money
+func (s *CheckoutSaga) Amount() money.Money {
+	return money.New(s.AmountMinor, s.Currency)
+}
+
+// IsRunning checks if the saga is still moving forward
+func (s *CheckoutSaga) IsRunning() bool {
+	return s.Status == CheckoutStatusRunning
+}
+
+// IsCompensating checks if the saga is undoing its steps
+func (s *CheckoutSaga) IsCompensating() bool {
+	return s.Status == CheckoutStatusCompensating
+}
+
+// IsFinished checks if the saga completed, was fully compensated or failed
+func (s *CheckoutSaga) IsFinished() bool {
+	return s.Status == CheckoutStatusCompleted || s.Status == CheckoutStatusCompensated || s.Status == CheckoutStatusFailed
+}
+
+// TimedOut checks if the current step ran past its deadline
+func (s *CheckoutSaga) TimedOut() bool {
+	return time.Now().After(s.StepDeadline)
+}
+
+// Advance moves the saga to the next step, which times out after timeout;
+// recovery looks at the saga again after wait
+func (s *CheckoutSaga) Advance(step CheckoutStep, timeout, wait time.Duration) {
+	now := time.Now().UTC()
+	s.Step = step
+	s.Attempts = 0
+	s.LastError = ""
+	s.StepDeadline = now.Add(timeout)
+	s.NextAttemptAt = now.Add(wait)
+}
+
+// Lease keeps recovery away from the saga while it is being worked on
+func (s *CheckoutSaga) Lease(lease time.Duration) {
+	s.NextAttemptAt = time.Now().UTC().Add(lease)
+}
+
+// Retry records a failed attempt of the current step and schedules the next one
+func (s *CheckoutSaga) Retry(err error, backoff time.Duration) {
+	s.Attempts++
+	s.LastError = err.Error()
+	s.NextAttemptAt = time.Now().UTC().Add(backoff)
+}
+
+// Compensate starts undoing the steps of the saga, beginning with the current one
+func (s *CheckoutSaga) Compensate(reason string, lease time.Duration) {
+	s.Status = CheckoutStatusCompensating
+	s.FailureReason = reason
+	s.Attempts = 0
+	s.LastError = ""
+	s.Lease(lease)
+}
+
+// StepBack moves a compensating saga to the step before the current one,
+// and marks it compensated once the first step is undone
+func (s *CheckoutSaga) StepBack(lease time.Duration) {
+	s.Attempts = 0
+	s.LastError = ""
+	s.Lease(lease)
+
+	index := slices.Index(checkoutSteps, s.Step)
+	if index <= 0 {
+		s.finish(CheckoutStatusCompensated)
+		return
+	}
+	s.Step = checkoutSteps[index-1]
+}
+
+// Complete marks the checkout as done
+func (s *CheckoutSaga) Complete() {
+	s.LastError = ""
+	s.finish(CheckoutStatusCompleted)
+}
+
+// Fail ends a compensating saga whose current step cannot be undone, leaving it to manual review
+func (s *CheckoutSaga) Fail(err error) {
+	s.LastError = err.Error()
+	s.finish(CheckoutStatusFailed)
+}
+
+// finish ends the saga in a final status
+func (s *CheckoutSaga) finish(status CheckoutStatus) {
+	now := time.Now().UTC()
+	s.Status = status
+	s.CompletedAt = &now
+}
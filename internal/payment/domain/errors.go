@@ -12,6 +12,7 @@ var (
 	ErrInvalidPaymentID           = errors.New("invalid payment ID")
 	ErrInvalidAmount              = errors.New("invalid amount")
 	ErrInvalidCurrency            = errors.New("invalid currency")
+	ErrCurrencyMismatch           = errors.New("amount currency does not match payment currency")
 	ErrInvalidPaymentMethod       = errors.New("invalid payment method")
 	ErrInvalidPaymentMethodType   = errors.New("invalid payment method type")
 	ErrInvalidPaymentProvider     = errors.New("invalid payment provider")
@@ -24,6 +25,11 @@ var (
 	ErrPaymentExpired             = errors.New("payment has expired")
 	ErrPaymentCannotBeRefunded    = errors.New("payment cannot be refunded")
 	ErrPaymentCannotBeCancelled   = errors.New("payment cannot be cancelled")
+	ErrPaymentCannotBeAuthorized  = errors.New("payment cannot be authorized")
+	ErrPaymentCannotBeCaptured    = errors.New("payment cannot be captured")
+	ErrPaymentCannotBeVoided      = errors.New("payment authorization cannot be voided")
+	ErrAuthorizationExpired       = errors.New("payment authorization has expired")
+	ErrCaptureAmountExceedsHold   = errors.New("capture amount exceeds authorized amount")
 	ErrRefundAlreadyCompleted     = errors.New("refund already completed")
 	ErrRefundAlreadyFailed        = errors.New("refund already failed")
 	ErrRefundAmountExceedsPayment = errors.New("refund amount exceeds payment amount")
@@ -34,4 +40,9 @@ var (
 	ErrInvalidGatewayResponse     = errors.New("invalid gateway response")
 	ErrTransactionNotFound        = errors.New("transaction not found")
 	ErrDuplicateTransaction       = errors.New("duplicate transaction")
+	ErrInvalidWebhookSignature    = errors.New("invalid webhook signature")
+	ErrInvalidWebhookPayload      = errors.New("invalid webhook payload")
+	ErrUnsupportedProvider        = errors.New("unsupported payment provider")
+	ErrCheckoutNotFound           = errors.New("checkout not found")
+	ErrCheckoutConflict           = errors.New("checkout was changed concurrently")
 )
@@ -1,14 +1,27 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
 
-// PaymentGateway defines the interface for payment gateway operations
+	"github.com/ddd-micro/pkg/money"
+)
+
+// PaymentGateway defines the interface for payment gateway operations. Operations that
+// move money are idempotent per payment or refund: the result of a call is stored in the
+// transaction that publishes its events, so a retry after that transaction rolled back
+// must get the original result instead of charging or refunding again.
 type PaymentGateway interface {
 	// Payment operations
 	CreatePayment(ctx context.Context, payment *Payment) (*PaymentGatewayResponse, error)
 	ProcessPayment(ctx context.Context, payment *Payment, paymentMethodID string) (*PaymentGatewayResponse, error)
 	CancelPayment(ctx context.Context, payment *Payment) (*PaymentGatewayResponse, error)
-	RefundPayment(ctx context.Context, payment *Payment, amount float64, reason string) (*PaymentGatewayResponse, error)
+	RefundPayment(ctx context.Context, payment *Payment, refund *Refund) (*PaymentGatewayResponse, error)
+
+	// Authorize/capture operations
+	AuthorizePayment(ctx context.Context, payment *Payment, paymentMethodID string) (*PaymentGatewayResponse, error)
+	CapturePayment(ctx context.Context, payment *Payment, amount money.Money) (*PaymentGatewayResponse, error)
+	VoidAuthorization(ctx context.Context, payment *Payment) (*PaymentGatewayResponse, error)
 
 	// Payment method operations
 	CreatePaymentMethod(ctx context.Context, userID uint, paymentMethod *PaymentMethodInfo) (*PaymentGatewayResponse, error)
@@ -19,6 +32,9 @@ type PaymentGateway interface {
 
 	// Health check
 	HealthCheck(ctx context.Context) error
+
+	// Provider returns the name of the payment provider backing the gateway
+	Provider() string
 }
 
 // PaymentGatewayResponse represents the response from payment gateway
@@ -31,6 +47,8 @@ type PaymentGatewayResponse struct {
 	Message         string                 `json:"message"`
 	GatewayResponse map[string]interface{} `json:"gateway_response"`
 	Error           *PaymentGatewayError   `json:"error,omitempty"`
+	// AuthorizationExpiresAt is set when funds are put on hold
+	AuthorizationExpiresAt *time.Time `json:"authorization_expires_at,omitempty"`
 }
 
 // PaymentGatewayError represents an error from payment gateway
@@ -49,6 +67,22 @@ type WebhookEvent struct {
 	Data      map[string]interface{} `json:"data"`
 	Created   int64                  `json:"created"`
 	Processed bool                   `json:"processed"`
+	// Normalized references extracted from the provider payload
+	Provider      string `json:"provider"`
+	ProviderType  string `json:"provider_type"`
+	PaymentID     string `json:"payment_id,omitempty"`
+	TransactionID string `json:"transaction_id,omitempty"`
+	AmountMinor   int64  `json:"amount_minor,omitempty"` // Amount in minor units of Currency
+	Currency      string `json:"currency,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+// AmountIn returns the event amount, assuming the given currency when the provider did not send one
+func (e *WebhookEvent) AmountIn(currency string) money.Money {
+	if e.Currency != "" {
+		currency = e.Currency
+	}
+	return money.New(e.AmountMinor, currency)
 }
 
 // PaymentGatewayConfig represents configuration for payment gateway
@@ -74,6 +108,7 @@ const (
 	ProviderPayPal   = "paypal"
 	ProviderSquare   = "square"
 	ProviderRazorpay = "razorpay"
+	ProviderMock     = "mock"
 )
 
 // Payment gateway event types
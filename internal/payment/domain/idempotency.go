@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// Idempotency key statuses
+const (
+	IdempotencyStatusInProgress = "in_progress"
+	IdempotencyStatusCompleted  = "completed"
+)
+
+// IdempotencyKey stores the outcome of a request sent with an Idempotency-Key header,
+// so that retries of the same request return the original response
+type IdempotencyKey struct {
+	UserID       uint      `json:"user_id" gorm:"primaryKey"`
+	Key          string    `json:"key" gorm:"primaryKey;type:varchar(255)"`
+	Fingerprint  string    `json:"fingerprint" gorm:"type:varchar(64);not null"`
+	Status       string    `json:"status" gorm:"type:varchar(20);not null"`
+	StatusCode   int       `json:"status_code"`
+	ResponseBody []byte    `json:"response_body" gorm:"type:bytea"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	ExpiresAt    time.Time `json:"expires_at" gorm:"not null;index"`
+}
+
+// TableName returns the table name for IdempotencyKey
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+// IsCompleted checks if the original request has finished
+func (k *IdempotencyKey) IsCompleted() bool {
+	return k.Status == IdempotencyStatusCompleted
+}
+
+// IsExpired checks if the key has outlived its TTL
+func (k *IdempotencyKey) IsExpired() bool {
+	return time.Now().After(k.ExpiresAt)
+}
+
+// Complete stores the response of the original request
+func (k *IdempotencyKey) Complete(statusCode int, body []byte) {
+	k.Status = IdempotencyStatusCompleted
+	k.StatusCode = statusCode
+	k.ResponseBody = body
+}
@@ -2,18 +2,31 @@ package domain
 
 import (
 	"time"
+
+	"github.com/ddd-micro/pkg/money"
+	"gorm.io/gorm"
 )
 
 // PaymentStatus represents the status of a payment
 type PaymentStatus string
 
 const (
-	PaymentStatusPending    PaymentStatus = "pending"
-	PaymentStatusProcessing PaymentStatus = "processing"
-	PaymentStatusCompleted  PaymentStatus = "completed"
-	PaymentStatusFailed     PaymentStatus = "failed"
-	PaymentStatusCancelled  PaymentStatus = "cancelled"
-	PaymentStatusRefunded   PaymentStatus = "refunded"
+	PaymentStatusPending           PaymentStatus = "pending"
+	PaymentStatusProcessing        PaymentStatus = "processing"
+	PaymentStatusAuthorized        PaymentStatus = "authorized"
+	PaymentStatusCompleted         PaymentStatus = "completed"
+	PaymentStatusFailed            PaymentStatus = "failed"
+	PaymentStatusCancelled         PaymentStatus = "cancelled"
+	PaymentStatusRefunded          PaymentStatus = "refunded"
+	PaymentStatusPartiallyRefunded PaymentStatus = "partially_refunded"
+)
+
+// Refund statuses
+const (
+	RefundStatusPending    = "pending"
+	RefundStatusProcessing = "processing"
+	RefundStatusCompleted  = "completed"
+	RefundStatusFailed     = "failed"
 )
 
 // PaymentMethod represents the payment method type
@@ -32,24 +45,35 @@ type Payment struct {
 	ID              string                 `json:"id" gorm:"primaryKey;type:varchar(36)"`
 	UserID          uint                   `json:"user_id" gorm:"not null;index"`
 	OrderID         string                 `json:"order_id" gorm:"not null;index;type:varchar(36)"`
-	Amount          float64                `json:"amount" gorm:"type:decimal(10,2);not null"`
+	AmountMinor     int64                  `json:"amount_minor" gorm:"not null;default:0"` // Amount in minor units of Currency
 	Currency        string                 `json:"currency" gorm:"type:varchar(3);not null;default:'USD'"`
 	Status          PaymentStatus          `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
 	PaymentMethod   PaymentMethod          `json:"payment_method" gorm:"type:varchar(20);not null"`
 	PaymentProvider string                 `json:"payment_provider" gorm:"type:varchar(50);not null"`
 	TransactionID   *string                `json:"transaction_id" gorm:"type:varchar(100);index"`
-	GatewayResponse map[string]interface{} `json:"gateway_response" gorm:"type:jsonb"`
+	GatewayResponse map[string]interface{} `json:"gateway_response" gorm:"type:jsonb;serializer:json"`
 	ReturnURL       *string                `json:"return_url" gorm:"type:text"`
 	CancelURL       *string                `json:"cancel_url" gorm:"type:text"`
-	// Optional: Direct product purchase (without basket)
+	// Optional: Direct product purchase (without basket), of a variant when set
 	ProductID *uint `json:"product_id" gorm:"index"`
+	VariantID *uint `json:"variant_id"`
 	Quantity  *int  `json:"quantity"`
 	// Optional: Basket-based purchase
-	BasketID    *string    `json:"basket_id" gorm:"type:varchar(36);index"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	CompletedAt *time.Time `json:"completed_at"`
-	ExpiresAt   *time.Time `json:"expires_at" gorm:"index"`
+	BasketID *string `json:"basket_id" gorm:"type:varchar(36);index"`
+	// Authorize/capture: funds on hold until captured, voided or expired
+	CapturedMinor          *int64     `json:"captured_amount_minor" gorm:"column:captured_amount_minor"`
+	AuthorizationExpiresAt *time.Time `json:"authorization_expires_at" gorm:"index"`
+	CreatedAt              time.Time  `json:"created_at"`
+	UpdatedAt              time.Time  `json:"updated_at"`
+	CompletedAt            *time.Time `json:"completed_at"`
+	ExpiresAt              *time.Time `json:"expires_at" gorm:"index"`
+
+	// Decimal copies of the amounts for readers of the original columns, written on save
+	AmountDecimal   float64  `json:"-" gorm:"column:amount;type:decimal(10,2);not null"`
+	CapturedDecimal *float64 `json:"-" gorm:"column:captured_amount;type:decimal(10,2)"`
+
+	// statusHistory holds transitions not yet stored by the repository
+	statusHistory []*PaymentStatusHistory
 }
 
 // PaymentMethodInfo represents a user's payment method
@@ -70,14 +94,19 @@ type PaymentMethodInfo struct {
 
 // Refund represents a refund transaction
 type Refund struct {
-	ID          string     `json:"id" gorm:"primaryKey;type:varchar(36)"`
-	PaymentID   string     `json:"payment_id" gorm:"not null;index;type:varchar(36)"`
-	Amount      float64    `json:"amount" gorm:"type:decimal(10,2);not null"`
-	Reason      string     `json:"reason" gorm:"type:text;not null"`
-	Status      string     `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	CompletedAt *time.Time `json:"completed_at"`
+	ID            string     `json:"id" gorm:"primaryKey;type:varchar(36)"`
+	PaymentID     string     `json:"payment_id" gorm:"not null;index;type:varchar(36)"`
+	AmountMinor   int64      `json:"amount_minor" gorm:"not null;default:0"` // Amount in minor units of Currency
+	Currency      string     `json:"currency" gorm:"type:varchar(3);not null;default:'USD'"`
+	Reason        string     `json:"reason" gorm:"type:text;not null"`
+	Status        string     `json:"status" gorm:"type:varchar(20);not null;default:'pending'"`
+	TransactionID *string    `json:"transaction_id" gorm:"type:varchar(100);index"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	CompletedAt   *time.Time `json:"completed_at"`
+
+	// Decimal copy of the amount for readers of the original column, written on save
+	AmountDecimal float64 `json:"-" gorm:"column:amount;type:decimal(10,2);not null"`
 }
 
 // TableName returns the table name for Payment
@@ -95,6 +124,41 @@ func (Refund) TableName() string {
 	return "refunds"
 }
 
+// BeforeSave keeps the decimal amount columns in sync with the minor unit amounts
+func (p *Payment) BeforeSave(tx *gorm.DB) error {
+	p.AmountDecimal = p.Amount().Major()
+	p.CapturedDecimal = nil
+	if p.CapturedMinor != nil {
+		captured := p.Captured().Major()
+		p.CapturedDecimal = &captured
+	}
+	return nil
+}
+
+// BeforeSave keeps the decimal amount column in sync with the minor unit amount
+func (r *Refund) BeforeSave(tx *gorm.DB) error {
+	r.AmountDecimal = r.Amount().Major()
+	return nil
+}
+
+// Amount returns the payment amount
+func (p *Payment) Amount() money.Money {
+	return money.New(p.AmountMinor, p.Currency)
+}
+
+// Captured returns the captured amount, which is zero until the payment is captured
+func (p *Payment) Captured() money.Money {
+	if p.CapturedMinor == nil {
+		return money.Zero(p.Currency)
+	}
+	return money.New(*p.CapturedMinor, p.Currency)
+}
+
+// Amount returns the refund amount
+func (r *Refund) Amount() money.Money {
+	return money.New(r.AmountMinor, r.Currency)
+}
+
 // IsCompleted checks if the payment is completed
 func (p *Payment) IsCompleted() bool {
 	return p.Status == PaymentStatusCompleted
@@ -125,41 +189,153 @@ func (p *Payment) IsRefunded() bool {
 	return p.Status == PaymentStatusRefunded
 }
 
+// IsAuthorized checks if the payment funds are on hold
+func (p *Payment) IsAuthorized() bool {
+	return p.Status == PaymentStatusAuthorized
+}
+
+// IsAuthorizationExpired checks if the hold on the payment funds has lapsed
+func (p *Payment) IsAuthorizationExpired() bool {
+	if p.AuthorizationExpiresAt == nil {
+		return false
+	}
+	return time.Now().After(*p.AuthorizationExpiresAt)
+}
+
+// CanBeAuthorized checks if the payment can be authorized
+func (p *Payment) CanBeAuthorized() bool {
+	return (p.IsPending() || p.IsProcessing()) && !p.IsExpired()
+}
+
+// CanBeCaptured checks if the payment can be captured
+func (p *Payment) CanBeCaptured() bool {
+	return p.IsAuthorized() && !p.IsAuthorizationExpired()
+}
+
+// CanBeVoided checks if the authorization can be voided
+func (p *Payment) CanBeVoided() bool {
+	return p.IsAuthorized()
+}
+
+// ChargedAmount returns the amount actually charged, which is lower than the
+// payment amount after a partial capture
+func (p *Payment) ChargedAmount() money.Money {
+	if p.CapturedMinor != nil {
+		return p.Captured()
+	}
+	return p.Amount()
+}
+
+// IsPartiallyRefunded checks if the payment is partially refunded
+func (p *Payment) IsPartiallyRefunded() bool {
+	return p.Status == PaymentStatusPartiallyRefunded
+}
+
 // CanBeRefunded checks if the payment can be refunded
 func (p *Payment) CanBeRefunded() bool {
-	return p.IsCompleted() && !p.IsRefunded()
+	return p.IsCompleted() || p.IsPartiallyRefunded()
+}
+
+// RefundedAmount returns the amount covered by completed refunds
+func (p *Payment) RefundedAmount(refunds []*Refund) money.Money {
+	var total int64
+	for _, refund := range refunds {
+		if refund.PaymentID == p.ID && refund.IsCompleted() {
+			total += refund.AmountMinor
+		}
+	}
+	return money.New(total, p.Currency)
+}
+
+// RefundableAmount returns the amount that can still be refunded, counting
+// completed and in-flight refunds against the captured amount
+func (p *Payment) RefundableAmount(refunds []*Refund) money.Money {
+	remaining := p.ChargedAmount().Amount
+	for _, refund := range refunds {
+		if refund.PaymentID == p.ID && !refund.IsFailed() {
+			remaining -= refund.AmountMinor
+		}
+	}
+	return money.New(max(remaining, 0), p.Currency)
+}
+
+// CanBeProcessed checks if the payment can still be charged
+func (p *Payment) CanBeProcessed() bool {
+	return p.IsPending() || p.IsProcessing() || p.IsFailed()
 }
 
 // CanBeCancelled checks if the payment can be cancelled
 func (p *Payment) CanBeCancelled() bool {
-	return p.IsPending() || p.IsProcessing()
+	return p.IsPending() || p.IsProcessing() || p.IsAuthorized()
 }
 
 // SetCompleted marks the payment as completed
-func (p *Payment) SetCompleted() {
-	p.Status = PaymentStatusCompleted
+func (p *Payment) SetCompleted(change StatusChange) error {
+	if err := p.TransitionTo(PaymentStatusCompleted, change); err != nil {
+		return err
+	}
 	now := time.Now()
 	p.CompletedAt = &now
+	return nil
 }
 
 // SetFailed marks the payment as failed
-func (p *Payment) SetFailed() {
-	p.Status = PaymentStatusFailed
+func (p *Payment) SetFailed(change StatusChange) error {
+	return p.TransitionTo(PaymentStatusFailed, change)
 }
 
 // SetProcessing marks the payment as processing
-func (p *Payment) SetProcessing() {
-	p.Status = PaymentStatusProcessing
+func (p *Payment) SetProcessing(change StatusChange) error {
+	return p.TransitionTo(PaymentStatusProcessing, change)
+}
+
+// SetAuthorized marks the payment funds as held until the given time
+func (p *Payment) SetAuthorized(expiresAt *time.Time, change StatusChange) error {
+	if err := p.TransitionTo(PaymentStatusAuthorized, change); err != nil {
+		return err
+	}
+	p.AuthorizationExpiresAt = expiresAt
+	return nil
+}
+
+// SetCaptured marks the authorized payment as completed for the captured amount
+func (p *Payment) SetCaptured(amount money.Money, change StatusChange) error {
+	if !amount.SameCurrency(p.Amount()) {
+		return ErrCurrencyMismatch
+	}
+	if err := p.SetCompleted(change); err != nil {
+		return err
+	}
+	captured := amount.Amount
+	p.CapturedMinor = &captured
+	return nil
 }
 
 // SetCancelled marks the payment as cancelled
-func (p *Payment) SetCancelled() {
-	p.Status = PaymentStatusCancelled
+func (p *Payment) SetCancelled(change StatusChange) error {
+	return p.TransitionTo(PaymentStatusCancelled, change)
 }
 
 // SetRefunded marks the payment as refunded
-func (p *Payment) SetRefunded() {
-	p.Status = PaymentStatusRefunded
+func (p *Payment) SetRefunded(change StatusChange) error {
+	return p.TransitionTo(PaymentStatusRefunded, change)
+}
+
+// SetPartiallyRefunded marks the payment as partially refunded
+func (p *Payment) SetPartiallyRefunded(change StatusChange) error {
+	return p.TransitionTo(PaymentStatusPartiallyRefunded, change)
+}
+
+// ApplyRefunds moves the payment to refunded or partially refunded based on completed refunds
+func (p *Payment) ApplyRefunds(refunds []*Refund, change StatusChange) error {
+	refunded := p.RefundedAmount(refunds)
+	switch {
+	case refunded.Amount >= p.ChargedAmount().Amount:
+		return p.SetRefunded(change)
+	case refunded.IsPositive():
+		return p.SetPartiallyRefunded(change)
+	}
+	return nil
 }
 
 // SetExpiration sets the expiration time for the payment
@@ -186,11 +362,11 @@ func (p *Payment) Validate() error {
 		return ErrInvalidOrderID
 	}
 
-	if p.Amount <= 0 {
+	if p.AmountMinor <= 0 {
 		return ErrInvalidAmount
 	}
 
-	if p.Currency == "" {
+	if !money.IsValidCurrency(p.Currency) {
 		return ErrInvalidCurrency
 	}
 
@@ -222,16 +398,52 @@ func (pm *PaymentMethodInfo) Validate() error {
 	return nil
 }
 
+// IsPending checks if the refund is waiting to be processed
+func (r *Refund) IsPending() bool {
+	return r.Status == RefundStatusPending
+}
+
+// IsCompleted checks if the refund is completed
+func (r *Refund) IsCompleted() bool {
+	return r.Status == RefundStatusCompleted
+}
+
+// IsFailed checks if the refund failed
+func (r *Refund) IsFailed() bool {
+	return r.Status == RefundStatusFailed
+}
+
+// SetProcessing marks the refund as submitted to the gateway
+func (r *Refund) SetProcessing() {
+	r.Status = RefundStatusProcessing
+}
+
+// SetCompleted marks the refund as completed
+func (r *Refund) SetCompleted() {
+	r.Status = RefundStatusCompleted
+	now := time.Now()
+	r.CompletedAt = &now
+}
+
+// SetFailed marks the refund as failed
+func (r *Refund) SetFailed() {
+	r.Status = RefundStatusFailed
+}
+
 // Validate validates the refund
 func (r *Refund) Validate() error {
 	if r.PaymentID == "" {
 		return ErrInvalidPaymentID
 	}
 
-	if r.Amount <= 0 {
+	if r.AmountMinor <= 0 {
 		return ErrInvalidAmount
 	}
 
+	if !money.IsValidCurrency(r.Currency) {
+		return ErrInvalidCurrency
+	}
+
 	if r.Reason == "" {
 		return ErrInvalidRefundReason
 	}
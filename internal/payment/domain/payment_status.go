@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidStatusTransition is matched by every InvalidStatusTransitionError
+var ErrInvalidStatusTransition = errors.New("invalid payment status transition")
+
+// paymentTransitions lists the statuses a payment may move to from each status.
+// Refunded and cancelled payments are final.
+var paymentTransitions = map[PaymentStatus][]PaymentStatus{
+	PaymentStatusPending: {
+		PaymentStatusProcessing,
+		PaymentStatusAuthorized,
+		PaymentStatusCompleted,
+		PaymentStatusFailed,
+		PaymentStatusCancelled,
+	},
+	PaymentStatusProcessing: {
+		PaymentStatusAuthorized,
+		PaymentStatusCompleted,
+		PaymentStatusFailed,
+		PaymentStatusCancelled,
+	},
+	PaymentStatusAuthorized: {
+		PaymentStatusProcessing,
+		PaymentStatusCompleted,
+		PaymentStatusFailed,
+		PaymentStatusCancelled,
+	},
+	PaymentStatusFailed: {
+		// The customer may retry with another payment method
+		PaymentStatusProcessing,
+		PaymentStatusAuthorized,
+		PaymentStatusCompleted,
+	},
+	PaymentStatusCompleted: {
+		PaymentStatusPartiallyRefunded,
+		PaymentStatusRefunded,
+	},
+	PaymentStatusPartiallyRefunded: {
+		PaymentStatusRefunded,
+	},
+	PaymentStatusCancelled: {},
+	PaymentStatusRefunded:  {},
+}
+
+// Status change actors
+const (
+	ActorSystem = "system"
+)
+
+// UserActor identifies a status change made by a customer
+func UserActor(userID uint) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+// AdminActor identifies a status change made by an administrator
+func AdminActor(adminID uint) string {
+	return fmt.Sprintf("admin:%d", adminID)
+}
+
+// GatewayActor identifies a status change reported by a payment provider
+func GatewayActor(provider string) string {
+	return "gateway:" + provider
+}
+
+// OrderActor identifies a status change requested by the order service for an order
+func OrderActor(orderID string) string {
+	return "order:" + orderID
+}
+
+// InvalidStatusTransitionError is returned when a payment cannot move between two statuses
+type InvalidStatusTransitionError struct {
+	From PaymentStatus
+	To   PaymentStatus
+}
+
+// Error implements the error interface
+func (e *InvalidStatusTransitionError) Error() string {
+	return fmt.Sprintf("%s: %s -> %s", ErrInvalidStatusTransition, e.From, e.To)
+}
+
+// Is makes errors.Is(err, ErrInvalidStatusTransition) match
+func (e *InvalidStatusTransitionError) Is(target error) bool {
+	return target == ErrInvalidStatusTransition
+}
+
+// IsValidPaymentStatus checks if the status is a known payment status
+func IsValidPaymentStatus(status PaymentStatus) bool {
+	_, ok := paymentTransitions[status]
+	return ok
+}
+
+// CanTransition checks if a payment may move from one status to another
+func CanTransition(from, to PaymentStatus) bool {
+	for _, allowed := range paymentTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusChange describes who triggered a status transition and why
+type StatusChange struct {
+	Actor          string
+	Reason         string
+	GatewayEventID string
+}
+
+// PaymentStatusHistory records a single payment status transition
+type PaymentStatusHistory struct {
+	ID             uint          `json:"id" gorm:"primaryKey;autoIncrement"`
+	PaymentID      string        `json:"payment_id" gorm:"not null;index;type:varchar(36)"`
+	FromStatus     PaymentStatus `json:"from_status" gorm:"type:varchar(20);not null"`
+	ToStatus       PaymentStatus `json:"to_status" gorm:"type:varchar(20);not null"`
+	Actor          string        `json:"actor" gorm:"type:varchar(100);not null"`
+	Reason         string        `json:"reason" gorm:"type:text"`
+	GatewayEventID *string       `json:"gateway_event_id" gorm:"type:varchar(255)"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+// TableName returns the table name for PaymentStatusHistory
+func (PaymentStatusHistory) TableName() string {
+	return "payment_status_history"
+}
+
+// TransitionTo moves the payment to a new status, rejecting moves the transition
+// table does not allow. Moving to the current status is a no-op.
+func (p *Payment) TransitionTo(status PaymentStatus, change StatusChange) error {
+	if p.Status == status {
+		return nil
+	}
+	if !CanTransition(p.Status, status) {
+		return &InvalidStatusTransitionError{From: p.Status, To: status}
+	}
+
+	entry := &PaymentStatusHistory{
+		PaymentID:  p.ID,
+		FromStatus: p.Status,
+		ToStatus:   status,
+		Actor:      change.Actor,
+		Reason:     change.Reason,
+		CreatedAt:  time.Now(),
+	}
+	if entry.Actor == "" {
+		entry.Actor = ActorSystem
+	}
+	if change.GatewayEventID != "" {
+		eventID := change.GatewayEventID
+		entry.GatewayEventID = &eventID
+	}
+
+	p.Status = status
+	p.statusHistory = append(p.statusHistory, entry)
+	return nil
+}
+
+// PullStatusHistory returns the transitions recorded since the payment was
+// loaded and clears them, so the repository stores each transition once
+func (p *Payment) PullStatusHistory() []*PaymentStatusHistory {
+	history := p.statusHistory
+	p.statusHistory = nil
+	return history
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+var allPaymentStatuses = []PaymentStatus{
+	PaymentStatusPending,
+	PaymentStatusProcessing,
+	PaymentStatusAuthorized,
+	PaymentStatusCompleted,
+	PaymentStatusFailed,
+	PaymentStatusCancelled,
+	PaymentStatusRefunded,
+	PaymentStatusPartiallyRefunded,
+}
+
+func TestTransitionTo(t *testing.T) {
+	// allowed lists every move between two different statuses a payment may make
+	allowed := map[PaymentStatus][]PaymentStatus{
+		PaymentStatusPending:           {PaymentStatusProcessing, PaymentStatusAuthorized, PaymentStatusCompleted, PaymentStatusFailed, PaymentStatusCancelled},
+		PaymentStatusProcessing:        {PaymentStatusAuthorized, PaymentStatusCompleted, PaymentStatusFailed, PaymentStatusCancelled},
+		PaymentStatusAuthorized:        {PaymentStatusProcessing, PaymentStatusCompleted, PaymentStatusFailed, PaymentStatusCancelled},
+		PaymentStatusFailed:            {PaymentStatusProcessing, PaymentStatusAuthorized, PaymentStatusCompleted},
+		PaymentStatusCompleted:         {PaymentStatusPartiallyRefunded, PaymentStatusRefunded},
+		PaymentStatusPartiallyRefunded: {PaymentStatusRefunded},
+	}
+	isAllowed := func(from, to PaymentStatus) bool {
+		for _, status := range allowed[from] {
+			if status == to {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, from := range allPaymentStatuses {
+		for _, to := range allPaymentStatuses {
+			t.Run(string(from)+" to "+string(to), func(t *testing.T) {
+				payment := &Payment{ID: "payment-1", Status: from}
+				err := payment.TransitionTo(to, StatusChange{Actor: AdminActor(7), Reason: "test"})
+				history := payment.PullStatusHistory()
+
+				switch {
+				case from == to:
+					if err != nil {
+						t.Fatalf("TransitionTo() error = %v, want a no-op", err)
+					}
+					if len(history) != 0 {
+						t.Fatalf("recorded %d transitions, want none", len(history))
+					}
+
+				case isAllowed(from, to):
+					if err != nil {
+						t.Fatalf("TransitionTo() error = %v", err)
+					}
+					if payment.Status != to {
+						t.Fatalf("status = %s, want %s", payment.Status, to)
+					}
+					if len(history) != 1 {
+						t.Fatalf("recorded %d transitions, want 1", len(history))
+					}
+					entry := history[0]
+					if entry.PaymentID != "payment-1" || entry.FromStatus != from || entry.ToStatus != to ||
+						entry.Actor != "admin:7" || entry.Reason != "test" {
+						t.Errorf("recorded %+v", entry)
+					}
+
+				default:
+					var transitionErr *InvalidStatusTransitionError
+					if !errors.As(err, &transitionErr) || transitionErr.From != from || transitionErr.To != to {
+						t.Fatalf("TransitionTo() error = %v, want an invalid transition from %s to %s", err, from, to)
+					}
+					if !errors.Is(err, ErrInvalidStatusTransition) {
+						t.Errorf("error %v does not match ErrInvalidStatusTransition", err)
+					}
+					if payment.Status != from {
+						t.Errorf("status = %s, want it unchanged", payment.Status)
+					}
+					if len(history) != 0 {
+						t.Errorf("recorded %d transitions, want none", len(history))
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestTransitionToRecordsHistoryOnce(t *testing.T) {
+	payment := &Payment{ID: "payment-1", Status: PaymentStatusPending}
+	if err := payment.TransitionTo(PaymentStatusProcessing, StatusChange{}); err != nil {
+		t.Fatalf("TransitionTo(processing) error = %v", err)
+	}
+	if err := payment.TransitionTo(PaymentStatusCompleted, StatusChange{GatewayEventID: "evt_1"}); err != nil {
+		t.Fatalf("TransitionTo(completed) error = %v", err)
+	}
+
+	history := payment.PullStatusHistory()
+	if len(history) != 2 {
+		t.Fatalf("recorded %d transitions, want 2", len(history))
+	}
+	if history[0].Actor != ActorSystem {
+		t.Errorf("actor = %q, want %q when none is given", history[0].Actor, ActorSystem)
+	}
+	if history[1].FromStatus != PaymentStatusProcessing || history[1].GatewayEventID == nil || *history[1].GatewayEventID != "evt_1" {
+		t.Errorf("recorded %+v, want processing to completed from event evt_1", history[1])
+	}
+
+	// Pulled transitions are handed to the repository once
+	if again := payment.PullStatusHistory(); len(again) != 0 {
+		t.Errorf("pulled %d transitions again, want none", len(again))
+	}
+}
+
+func TestIsValidPaymentStatus(t *testing.T) {
+	for _, status := range allPaymentStatuses {
+		if !IsValidPaymentStatus(status) {
+			t.Errorf("IsValidPaymentStatus(%s) = false", status)
+		}
+	}
+	if IsValidPaymentStatus("unknown") {
+		t.Error("IsValidPaymentStatus(unknown) = true")
+	}
+}
@@ -1,13 +1,20 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // PaymentRepository defines the interface for payment data operations
 type PaymentRepository interface {
 	// Payment operations
 	Create(ctx context.Context, payment *Payment) error
 	GetByID(ctx context.Context, paymentID string) (*Payment, error)
+	// GetByIDForUpdate gets the payment and locks it until the transaction in ctx ends
+	GetByIDForUpdate(ctx context.Context, paymentID string) (*Payment, error)
 	GetByOrderID(ctx context.Context, orderID string) (*Payment, error)
+	// ListByOrderID lists every payment of an order, oldest first
+	ListByOrderID(ctx context.Context, orderID string) ([]*Payment, error)
 	GetByUserID(ctx context.Context, userID uint, limit, offset int, status string) ([]*Payment, int, error)
 	GetByTransactionID(ctx context.Context, transactionID string) (*Payment, error)
 	Update(ctx context.Context, payment *Payment) error
@@ -16,10 +23,11 @@ type PaymentRepository interface {
 	// Payment status operations
 	UpdateStatus(ctx context.Context, paymentID string, status PaymentStatus) error
 	GetByStatus(ctx context.Context, status PaymentStatus, offset, limit int) ([]*Payment, int, error)
+	GetStatusHistory(ctx context.Context, paymentID string) ([]*PaymentStatusHistory, error)
 
 	// Payment statistics
 	GetPaymentStats(ctx context.Context, userID *uint, startDate, endDate *string) (*PaymentStats, error)
-	GetTotalAmountByStatus(ctx context.Context, status PaymentStatus, startDate, endDate *string) (float64, error)
+	GetTotalAmountByStatus(ctx context.Context, status PaymentStatus, startDate, endDate *string) ([]CurrencyTotal, error)
 
 	// Expired payments
 	GetExpiredPayments(ctx context.Context) ([]*Payment, error)
@@ -58,26 +66,60 @@ type RefundRepository interface {
 
 	// Refund statistics
 	GetRefundStats(ctx context.Context, userID *uint, startDate, endDate *string) (*RefundStats, error)
-	GetTotalRefundAmount(ctx context.Context, userID *uint, startDate, endDate *string) (float64, error)
+	GetTotalRefundAmount(ctx context.Context, userID *uint, startDate, endDate *string) ([]CurrencyTotal, error)
+}
+
+// WebhookEventRepository defines the interface for processed webhook event data operations
+type WebhookEventRepository interface {
+	Exists(ctx context.Context, provider, eventID string) (bool, error)
+	Create(ctx context.Context, event *ProcessedWebhookEvent) error
+}
+
+// IdempotencyKeyRepository defines the interface for idempotency key storage
+type IdempotencyKeyRepository interface {
+	// Acquire stores the key if it is unused or expired. When the key is already
+	// taken it returns the stored key and false.
+	Acquire(ctx context.Context, key *IdempotencyKey) (*IdempotencyKey, bool, error)
+	Complete(ctx context.Context, key *IdempotencyKey) error
+	Release(ctx context.Context, userID uint, key string) error
+}
+
+// CheckoutSagaRepository defines the interface for checkout saga storage
+type CheckoutSagaRepository interface {
+	Create(ctx context.Context, saga *CheckoutSaga) error
+	GetByID(ctx context.Context, sagaID string) (*CheckoutSaga, error)
+	GetByPaymentID(ctx context.Context, paymentID string) (*CheckoutSaga, error)
+	// Update saves the saga unless it was changed since it was read, in which
+	// case it returns ErrCheckoutConflict
+	Update(ctx context.Context, saga *CheckoutSaga) error
+	// ClaimDue leases up to limit unfinished sagas whose next attempt is due
+	ClaimDue(ctx context.Context, lease time.Duration, limit int) ([]*CheckoutSaga, error)
 }
 
 // PaymentStats represents payment statistics
 type PaymentStats struct {
-	TotalPayments      int     `json:"total_payments"`
-	TotalAmount        float64 `json:"total_amount"`
-	SuccessfulPayments int     `json:"successful_payments"`
-	FailedPayments     int     `json:"failed_payments"`
-	PendingPayments    int     `json:"pending_payments"`
-	RefundedAmount     float64 `json:"refunded_amount"`
-	AverageAmount      float64 `json:"average_amount"`
+	TotalPayments      int             `json:"total_payments"`
+	SuccessfulPayments int             `json:"successful_payments"`
+	FailedPayments     int             `json:"failed_payments"`
+	PendingPayments    int             `json:"pending_payments"`
+	TotalAmounts       []CurrencyTotal `json:"total_amounts"`
+	RefundedAmounts    []CurrencyTotal `json:"refunded_amounts"`
 }
 
 // RefundStats represents refund statistics
 type RefundStats struct {
-	TotalRefunds     int     `json:"total_refunds"`
-	TotalAmount      float64 `json:"total_amount"`
-	CompletedRefunds int     `json:"completed_refunds"`
-	PendingRefunds   int     `json:"pending_refunds"`
-	FailedRefunds    int     `json:"failed_refunds"`
-	AverageAmount    float64 `json:"average_amount"`
+	TotalRefunds     int             `json:"total_refunds"`
+	CompletedRefunds int             `json:"completed_refunds"`
+	PendingRefunds   int             `json:"pending_refunds"`
+	FailedRefunds    int             `json:"failed_refunds"`
+	TotalAmounts     []CurrencyTotal `json:"total_amounts"`
+}
+
+// CurrencyTotal is the sum of the amounts in a single currency. Amounts in different
+// currencies are never added up together.
+type CurrencyTotal struct {
+	Currency     string `json:"currency"`
+	Count        int    `json:"count"`
+	AmountMinor  int64  `json:"amount_minor"`
+	AverageMinor int64  `json:"average_minor"`
 }
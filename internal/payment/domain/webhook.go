@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// ProcessedWebhookEvent records a gateway webhook event that has already been applied
+type ProcessedWebhookEvent struct {
+	EventID     string    `json:"event_id" gorm:"primaryKey;type:varchar(255)"`
+	Provider    string    `json:"provider" gorm:"primaryKey;type:varchar(50)"`
+	Type        string    `json:"type" gorm:"type:varchar(100);not null"`
+	PaymentID   *string   `json:"payment_id" gorm:"type:varchar(36);index"`
+	ProcessedAt time.Time `json:"processed_at" gorm:"not null"`
+}
+
+// TableName returns the table name for ProcessedWebhookEvent
+func (ProcessedWebhookEvent) TableName() string {
+	return "processed_webhook_events"
+}
@@ -83,7 +83,7 @@ func (c *basketClient) ValidateBasket(ctx context.Context, userID uint) (*basket
 		if item.Quantity <= 0 {
 			return nil, fmt.Errorf("invalid quantity for product %d", item.ProductId)
 		}
-		if item.UnitPrice <= 0 {
+		if item.UnitPriceMinor <= 0 && item.UnitPrice <= 0 {
 			return nil, fmt.Errorf("invalid unit price for product %d", item.ProductId)
 		}
 	}
@@ -109,3 +109,12 @@ func (c *basketClient) ClearBasket(ctx context.Context, userID uint) error {
 func (c *basketClient) Close() error {
 	return c.conn.Close()
 }
+
+// BasketItemVariantID returns the variant ID of a basket item, nil for the product itself
+func BasketItemVariantID(item *basketpb.BasketItem) *uint {
+	if item.VariantId == nil {
+		return nil
+	}
+	variantID := uint(*item.VariantId)
+	return &variantID
+}
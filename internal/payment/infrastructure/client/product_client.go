@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"time"
 
 	productpb "github.com/ddd-micro/api/proto/product"
 	"google.golang.org/grpc"
@@ -14,7 +15,21 @@ type ProductClient interface {
 	GetProduct(ctx context.Context, productID uint) (*productpb.Product, error)
 	GetProducts(ctx context.Context, productIDs []uint) ([]*productpb.Product, error)
 	ValidateProducts(ctx context.Context, productIDs []uint) ([]*productpb.Product, error)
-	UpdateStock(ctx context.Context, productID uint, quantity int) error
+	UpdateStock(ctx context.Context, productID uint, variantID *uint, quantity int) error
+	ReduceStock(ctx context.Context, productID uint, variantID *uint, quantity int) error
+	IncreaseStock(ctx context.Context, productID uint, variantID *uint, quantity int) error
+	ReserveStock(ctx context.Context, paymentID string, items []ReservationItem, ttl time.Duration) error
+	ReleaseReservation(ctx context.Context, paymentID string) error
+}
+
+// reservationReferencePayment is the reference type of stock reservations held for a payment
+const reservationReferencePayment = "payment"
+
+// ReservationItem is a quantity of a product, or of a variant of it, to hold in a reservation
+type ReservationItem struct {
+	ProductID uint
+	VariantID *uint
+	Quantity  int
 }
 
 // productClient implements ProductClient interface
@@ -71,13 +86,8 @@ func (c *productClient) GetProducts(ctx context.Context, productIDs []uint) ([]*
 		if err != nil {
 			return nil, fmt.Errorf("failed to get product %d: %w", id, err)
 		}
-<<<<<<< HEAD
-		
-		products = append(products, resp.Product)
-=======
 
-		products = append(products, resp)
->>>>>>> 7e885f2da7e63ff5d1c890e11bf166547af369a4
+		products = append(products, resp.Product)
 	}
 
 	return products, nil
@@ -107,22 +117,18 @@ func (c *productClient) ValidateProducts(ctx context.Context, productIDs []uint)
 		if !resp.Product.IsActive {
 			return nil, fmt.Errorf("product %d is not active", id)
 		}
-<<<<<<< HEAD
-		
-		products = append(products, resp.Product)
-=======
 
-		products = append(products, resp)
->>>>>>> 7e885f2da7e63ff5d1c890e11bf166547af369a4
+		products = append(products, resp.Product)
 	}
 
 	return products, nil
 }
 
-// UpdateStock updates product stock
-func (c *productClient) UpdateStock(ctx context.Context, productID uint, quantity int) error {
+// UpdateStock updates the stock of a product, or of a variant of it
+func (c *productClient) UpdateStock(ctx context.Context, productID uint, variantID *uint, quantity int) error {
 	req := &productpb.UpdateStockRequest{
 		ProductId: uint32(productID),
+		VariantId: uintToUint32Ptr(variantID),
 		Stock:     int32(quantity),
 	}
 
@@ -134,7 +140,87 @@ func (c *productClient) UpdateStock(ctx context.Context, productID uint, quantit
 	return nil
 }
 
+// ReduceStock takes a quantity out of the stock of a product, or of a variant of it
+func (c *productClient) ReduceStock(ctx context.Context, productID uint, variantID *uint, quantity int) error {
+	req := &productpb.ReduceStockRequest{
+		ProductId: uint32(productID),
+		VariantId: uintToUint32Ptr(variantID),
+		Amount:    int32(quantity),
+	}
+
+	_, err := c.client.ReduceStock(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to reduce stock: %w", err)
+	}
+
+	return nil
+}
+
+// IncreaseStock puts a quantity back into the stock of a product, or of a variant of it
+func (c *productClient) IncreaseStock(ctx context.Context, productID uint, variantID *uint, quantity int) error {
+	req := &productpb.IncreaseStockRequest{
+		ProductId: uint32(productID),
+		VariantId: uintToUint32Ptr(variantID),
+		Amount:    int32(quantity),
+	}
+
+	_, err := c.client.IncreaseStock(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to increase stock: %w", err)
+	}
+
+	return nil
+}
+
+// ReserveStock holds the items for a payment until the reservation is confirmed, released
+// or expires. Reserving again for the same payment replaces its reservation.
+func (c *productClient) ReserveStock(ctx context.Context, paymentID string, items []ReservationItem, ttl time.Duration) error {
+	req := &productpb.ReserveStockRequest{
+		ReferenceType: reservationReferencePayment,
+		ReferenceId:   paymentID,
+		TtlSeconds:    int32(ttl / time.Second),
+	}
+	for _, item := range items {
+		req.Items = append(req.Items, &productpb.ReservationItem{
+			ProductId: uint32(item.ProductID),
+			VariantId: uintToUint32Ptr(item.VariantID),
+			Quantity:  int32(item.Quantity),
+		})
+	}
+
+	_, err := c.client.ReserveStock(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to reserve stock: %w", err)
+	}
+
+	return nil
+}
+
+// ReleaseReservation gives back the stock held for a payment
+func (c *productClient) ReleaseReservation(ctx context.Context, paymentID string) error {
+	req := &productpb.ReleaseReservationRequest{
+		ReferenceType: reservationReferencePayment,
+		ReferenceId:   paymentID,
+	}
+
+	_, err := c.client.ReleaseReservation(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to release reservation: %w", err)
+	}
+
+	return nil
+}
+
 // Close closes the gRPC connection
 func (c *productClient) Close() error {
 	return c.conn.Close()
 }
+
+// uintToUint32Ptr converts an optional ID to its protobuf form
+func uintToUint32Ptr(value *uint) *uint32 {
+	if value == nil {
+		return nil
+	}
+	converted := uint32(*value)
+	return &converted
+}
@@ -22,13 +22,20 @@ type Config struct {
 	BasketServiceURL  string
 
 	// Payment gateway configuration
-	Stripe StripeConfig
+	PaymentGateway string
+	Stripe         StripeConfig
 
 	// JWT configuration
 	JWT JWTConfig
 
 	// Redis configuration (for caching)
 	Redis RedisConfig
+
+	// Idempotency configuration
+	Idempotency IdempotencyConfig
+
+	// Checkout saga configuration
+	Checkout CheckoutConfig
 }
 
 // DatabaseConfig holds database configuration
@@ -62,6 +69,28 @@ type RedisConfig struct {
 	DB       int
 }
 
+// IdempotencyConfig holds Idempotency-Key configuration
+type IdempotencyConfig struct {
+	TTL time.Duration
+}
+
+// CheckoutConfig holds checkout saga configuration
+type CheckoutConfig struct {
+	// StepTimeout bounds the basket validation, stock reservation and payment creation steps
+	StepTimeout time.Duration
+	// PaymentTimeout is how long a checkout waits for its payment to complete
+	PaymentTimeout time.Duration
+	// BasketTimeout is how long a checkout waits for the basket cleared event
+	// before clearing the basket itself
+	BasketTimeout time.Duration
+	// Lease keeps other instances from resuming a saga that is being worked on
+	Lease            time.Duration
+	RetryBackoff     time.Duration
+	MaxBackoff       time.Duration
+	RecoveryInterval time.Duration
+	RecoveryBatch    int
+}
+
 // LoadConfig loads configuration from environment variables
 func LoadConfig() (*Config, error) {
 	config := &Config{
@@ -81,6 +110,8 @@ func LoadConfig() (*Config, error) {
 		ProductServiceURL: getEnv("PRODUCT_SERVICE_URL", "product-service:9092"),
 		BasketServiceURL:  getEnv("BASKET_SERVICE_URL", "basket-service:9093"),
 
+		PaymentGateway: getEnv("PAYMENT_GATEWAY", "stripe"),
+
 		Stripe: StripeConfig{
 			SecretKey:      getEnv("STRIPE_SECRET_KEY", ""),
 			PublishableKey: getEnv("STRIPE_PUBLISHABLE_KEY", ""),
@@ -98,6 +129,21 @@ func LoadConfig() (*Config, error) {
 			Password: getEnv("REDIS_PASSWORD", ""),
 			DB:       getEnvAsInt("REDIS_DB", 0),
 		},
+
+		Idempotency: IdempotencyConfig{
+			TTL: time.Duration(getEnvAsInt("IDEMPOTENCY_TTL_HOURS", 24)) * time.Hour,
+		},
+
+		Checkout: CheckoutConfig{
+			StepTimeout:      getEnvAsDuration("CHECKOUT_STEP_TIMEOUT", 2*time.Minute),
+			PaymentTimeout:   getEnvAsDuration("CHECKOUT_PAYMENT_TIMEOUT", 30*time.Minute),
+			BasketTimeout:    getEnvAsDuration("CHECKOUT_BASKET_TIMEOUT", time.Minute),
+			Lease:            getEnvAsDuration("CHECKOUT_LEASE", time.Minute),
+			RetryBackoff:     getEnvAsDuration("CHECKOUT_RETRY_BACKOFF", time.Second),
+			MaxBackoff:       getEnvAsDuration("CHECKOUT_MAX_BACKOFF", 5*time.Minute),
+			RecoveryInterval: getEnvAsDuration("CHECKOUT_RECOVERY_INTERVAL", 5*time.Second),
+			RecoveryBatch:    getEnvAsInt("CHECKOUT_RECOVERY_BATCH", 50),
+		},
 	}
 
 	return config, nil
@@ -136,3 +182,12 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
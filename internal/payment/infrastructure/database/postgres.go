@@ -5,7 +5,11 @@ import (
 	"log"
 	"time"
 
+	"github.com/ddd-micro/internal/payment/domain"
 	"github.com/ddd-micro/internal/payment/infrastructure/config"
+	"github.com/ddd-micro/kafka"
+	"github.com/ddd-micro/pkg/money"
+	"github.com/ddd-micro/pkg/outbox"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -46,14 +50,67 @@ func NewPostgresDB(cfg *config.Config) (*gorm.DB, error) {
 	}
 
 	log.Println("Successfully connected to PostgreSQL database")
+
+	if err := Migrate(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
 	return db, nil
 }
 
 // Migrate runs database migrations
 func Migrate(db *gorm.DB) error {
-	// Import domain models for migration
-	// This will be done in the main function to avoid circular imports
+	if err := db.AutoMigrate(
+		&domain.Payment{},
+		&domain.PaymentMethodInfo{},
+		&domain.Refund{},
+		&domain.ProcessedWebhookEvent{},
+		&domain.IdempotencyKey{},
+		&domain.PaymentStatusHistory{},
+		&domain.CheckoutSaga{},
+	); err != nil {
+		return err
+	}
+
+	if err := outbox.Migrate(db); err != nil {
+		return err
+	}
+
+	if err := kafka.MigrateProcessedEvents(db); err != nil {
+		return err
+	}
+
+	if err := backfillMinorUnitAmounts(db); err != nil {
+		return err
+	}
 
 	log.Println("Database migration completed")
 	return nil
 }
+
+// backfillMinorUnitAmounts fills the minor unit amount columns of rows written before
+// they existed from the decimal columns. Rows that already have minor unit amounts are skipped.
+func backfillMinorUnitAmounts(db *gorm.DB) error {
+	scale := fmt.Sprintf("POWER(10, %s)", money.ExponentSQL("currency"))
+	if err := db.Exec(fmt.Sprintf(`UPDATE payments SET amount_minor = ROUND(amount * %s)
+		WHERE amount_minor = 0 AND amount <> 0`, scale)).Error; err != nil {
+		return fmt.Errorf("failed to backfill payment amounts: %w", err)
+	}
+	if err := db.Exec(fmt.Sprintf(`UPDATE payments SET captured_amount_minor = ROUND(captured_amount * %s)
+		WHERE captured_amount_minor IS NULL AND captured_amount IS NOT NULL`, scale)).Error; err != nil {
+		return fmt.Errorf("failed to backfill captured amounts: %w", err)
+	}
+
+	// Refunds share the currency of their payment
+	if err := db.Exec(`UPDATE refunds SET currency = payments.currency
+		FROM payments
+		WHERE payments.id = refunds.payment_id AND refunds.amount_minor = 0`).Error; err != nil {
+		return fmt.Errorf("failed to backfill refund currencies: %w", err)
+	}
+	if err := db.Exec(fmt.Sprintf(`UPDATE refunds SET amount_minor = ROUND(amount * %s)
+		WHERE amount_minor = 0 AND amount <> 0`, scale)).Error; err != nil {
+		return fmt.Errorf("failed to backfill refund amounts: %w", err)
+	}
+
+	return nil
+}
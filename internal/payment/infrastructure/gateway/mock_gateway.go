@@ -6,15 +6,22 @@ import (
 	"time"
 
 	"github.com/ddd-micro/internal/payment/domain"
+	"github.com/ddd-micro/internal/payment/infrastructure/config"
+	"github.com/ddd-micro/pkg/money"
 	"github.com/google/uuid"
+	"github.com/stripe/stripe-go/v72/webhook"
 )
 
 // mockGateway implements domain.PaymentGateway for development/testing
-type mockGateway struct{}
+type mockGateway struct {
+	webhookSecret string
+}
 
 // NewMockGateway creates a new mock payment gateway
-func NewMockGateway() domain.PaymentGateway {
-	return &mockGateway{}
+func NewMockGateway(cfg *config.Config) domain.PaymentGateway {
+	return &mockGateway{
+		webhookSecret: cfg.Stripe.WebhookSecret,
+	}
 }
 
 // CreatePayment creates a mock payment
@@ -89,7 +96,7 @@ func (g *mockGateway) CancelPayment(ctx context.Context, payment *domain.Payment
 }
 
 // RefundPayment refunds a mock payment
-func (g *mockGateway) RefundPayment(ctx context.Context, payment *domain.Payment, amount float64, reason string) (*domain.PaymentGatewayResponse, error) {
+func (g *mockGateway) RefundPayment(ctx context.Context, payment *domain.Payment, refund *domain.Refund) (*domain.PaymentGatewayResponse, error) {
 	// Simulate some processing time
 	time.Sleep(300 * time.Millisecond)
 
@@ -111,13 +118,92 @@ func (g *mockGateway) RefundPayment(ctx context.Context, payment *domain.Payment
 		GatewayResponse: map[string]interface{}{
 			"refund_id": refundID,
 			"status":    string(status),
-			"amount":    amount,
-			"reason":    reason,
+			"amount":    refund.Amount().Major(),
+			"reason":    refund.Reason,
 			"success":   success,
 		},
 	}, nil
 }
 
+// AuthorizePayment authorizes a mock payment
+func (g *mockGateway) AuthorizePayment(ctx context.Context, payment *domain.Payment, paymentMethodID string) (*domain.PaymentGatewayResponse, error) {
+	// Simulate some processing time
+	time.Sleep(200 * time.Millisecond)
+
+	// Simulate 90% success rate
+	success := time.Now().UnixNano()%10 != 0
+
+	var status domain.PaymentStatus
+	var expiresAt *time.Time
+	if success {
+		status = domain.PaymentStatusAuthorized
+		expiry := time.Now().Add(authorizationHoldPeriod)
+		expiresAt = &expiry
+	} else {
+		status = domain.PaymentStatusFailed
+	}
+
+	transactionID := uuid.New().String()
+	if payment.TransactionID != nil {
+		transactionID = *payment.TransactionID
+	}
+
+	return &domain.PaymentGatewayResponse{
+		Success:                success,
+		TransactionID:          transactionID,
+		Status:                 status,
+		AuthorizationExpiresAt: expiresAt,
+		GatewayResponse: map[string]interface{}{
+			"transaction_id": transactionID,
+			"status":         string(status),
+			"success":        success,
+		},
+	}, nil
+}
+
+// CapturePayment captures a mock authorized payment
+func (g *mockGateway) CapturePayment(ctx context.Context, payment *domain.Payment, amount money.Money) (*domain.PaymentGatewayResponse, error) {
+	// Simulate some processing time
+	time.Sleep(200 * time.Millisecond)
+
+	transactionID := uuid.New().String()
+	if payment.TransactionID != nil {
+		transactionID = *payment.TransactionID
+	}
+
+	return &domain.PaymentGatewayResponse{
+		Success:       true,
+		TransactionID: transactionID,
+		Status:        domain.PaymentStatusCompleted,
+		GatewayResponse: map[string]interface{}{
+			"transaction_id":  transactionID,
+			"status":          string(domain.PaymentStatusCompleted),
+			"amount_captured": amount.Major(),
+		},
+	}, nil
+}
+
+// VoidAuthorization voids a mock authorized payment
+func (g *mockGateway) VoidAuthorization(ctx context.Context, payment *domain.Payment) (*domain.PaymentGatewayResponse, error) {
+	// Simulate some processing time
+	time.Sleep(100 * time.Millisecond)
+
+	transactionID := uuid.New().String()
+	if payment.TransactionID != nil {
+		transactionID = *payment.TransactionID
+	}
+
+	return &domain.PaymentGatewayResponse{
+		Success:       true,
+		TransactionID: transactionID,
+		Status:        domain.PaymentStatusCancelled,
+		GatewayResponse: map[string]interface{}{
+			"transaction_id": transactionID,
+			"status":         "voided",
+		},
+	}, nil
+}
+
 // CreatePaymentMethod creates a mock payment method
 func (g *mockGateway) CreatePaymentMethod(ctx context.Context, userID uint, paymentMethod *domain.PaymentMethodInfo) (*domain.PaymentGatewayResponse, error) {
 	// Simulate some processing time
@@ -145,22 +231,6 @@ func (g *mockGateway) CreatePaymentMethod(ctx context.Context, userID uint, paym
 	}, nil
 }
 
-// ValidateWebhook validates a mock webhook
-func (g *mockGateway) ValidateWebhook(ctx context.Context, payload []byte, signature string) (map[string]interface{}, error) {
-	// Simulate webhook validation
-	time.Sleep(50 * time.Millisecond)
-
-	return map[string]interface{}{
-		"type": "payment_intent.succeeded",
-		"data": map[string]interface{}{
-			"object": map[string]interface{}{
-				"id":     "pi_mock_" + uuid.New().String(),
-				"status": "succeeded",
-			},
-		},
-	}, nil
-}
-
 // DeletePaymentMethod deletes a payment method
 func (g *mockGateway) DeletePaymentMethod(ctx context.Context, paymentMethodID string) (*domain.PaymentGatewayResponse, error) {
 	// Simulate some processing time
@@ -170,11 +240,33 @@ func (g *mockGateway) DeletePaymentMethod(ctx context.Context, paymentMethodID s
 		TransactionID: paymentMethodID,
 		GatewayResponse: map[string]interface{}{
 			"payment_method_id": paymentMethodID,
-			"status":           "deleted",
+			"status":            "deleted",
 		},
 	}, nil
 }
 
+// ProcessWebhook verifies a mock webhook signed with SignWebhookPayload.
+// Payloads use the Stripe event format so the same fixtures work for both gateways;
+// the signature timestamp is not enforced so recorded fixtures can be replayed.
+func (g *mockGateway) ProcessWebhook(ctx context.Context, payload []byte, signature string) (*domain.WebhookEvent, error) {
+	event, err := webhook.ConstructEventIgnoringTolerance(payload, signature, g.webhookSecret)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidWebhookSignature, err)
+	}
+
+	return toWebhookEvent(g.Provider(), event)
+}
+
+// HealthCheck checks the health of the mock gateway
+func (g *mockGateway) HealthCheck(ctx context.Context) error {
+	return nil
+}
+
+// Provider returns the name of the payment provider
+func (g *mockGateway) Provider() string {
+	return domain.ProviderMock
+}
+
 // Helper functions
 func stringPtr(s string) *string {
 	return &s
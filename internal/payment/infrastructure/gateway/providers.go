@@ -0,0 +1,14 @@
+package gateway
+
+import (
+	"github.com/ddd-micro/internal/payment/domain"
+	"github.com/ddd-micro/internal/payment/infrastructure/config"
+)
+
+// NewPaymentGateway creates the payment gateway selected by configuration
+func NewPaymentGateway(cfg *config.Config) domain.PaymentGateway {
+	if cfg.PaymentGateway == domain.ProviderMock {
+		return NewMockGateway(cfg)
+	}
+	return NewStripeGateway(cfg)
+}
@@ -3,18 +3,24 @@ package gateway
 import (
 	"context"
 	"fmt"
-	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ddd-micro/internal/payment/domain"
 	"github.com/ddd-micro/internal/payment/infrastructure/config"
+	"github.com/ddd-micro/pkg/money"
 	"github.com/stripe/stripe-go/v72"
 	"github.com/stripe/stripe-go/v72/checkout/session"
 	"github.com/stripe/stripe-go/v72/customer"
 	"github.com/stripe/stripe-go/v72/paymentintent"
 	"github.com/stripe/stripe-go/v72/paymentmethod"
 	"github.com/stripe/stripe-go/v72/refund"
+	"github.com/stripe/stripe-go/v72/webhook"
 )
 
+// authorizationHoldPeriod is how long Stripe keeps uncaptured card funds on hold
+const authorizationHoldPeriod = 7 * 24 * time.Hour
+
 // stripeGateway implements domain.PaymentGateway
 type stripeGateway struct {
 	config *config.Config
@@ -44,7 +50,7 @@ func (g *stripeGateway) CreatePayment(ctx context.Context, payment *domain.Payme
 					ProductData: &stripe.CheckoutSessionLineItemPriceDataProductDataParams{
 						Name: stripe.String(fmt.Sprintf("Order %s", payment.OrderID)),
 					},
-					UnitAmount: stripe.Int64(int64(payment.Amount * 100)), // Convert to cents
+					UnitAmount: stripe.Int64(payment.AmountMinor), // Already in minor units
 				},
 				Quantity: stripe.Int64(1),
 			},
@@ -52,7 +58,14 @@ func (g *stripeGateway) CreatePayment(ctx context.Context, payment *domain.Payme
 		Mode:       stripe.String(string(stripe.CheckoutSessionModePayment)),
 		SuccessURL: payment.ReturnURL,
 		CancelURL:  payment.CancelURL,
+		// Link the resulting payment intent back to our payment for webhooks
+		PaymentIntentData: &stripe.CheckoutSessionPaymentIntentDataParams{
+			Metadata: map[string]string{
+				metadataPaymentID: payment.ID,
+			},
+		},
 	}
+	sessionParams.SetIdempotencyKey(idempotencyKey("checkout", payment.ID))
 
 	// Add customer if needed
 	// This would typically be handled differently in a real implementation
@@ -76,9 +89,11 @@ func (g *stripeGateway) CreatePayment(ctx context.Context, payment *domain.Payme
 func (g *stripeGateway) ProcessPayment(ctx context.Context, payment *domain.Payment, paymentMethodID string) (*domain.PaymentGatewayResponse, error) {
 	// Create payment intent
 	params := &stripe.PaymentIntentParams{
-		Amount:   stripe.Int64(int64(payment.Amount * 100)), // Convert to cents
+		Amount:   stripe.Int64(payment.AmountMinor), // Already in minor units
 		Currency: stripe.String(payment.Currency),
 	}
+	params.AddMetadata(metadataPaymentID, payment.ID)
+	params.SetIdempotencyKey(idempotencyKey("process", payment.ID, paymentMethodID))
 
 	// Add payment method if provided
 	if paymentMethodID != "" {
@@ -91,7 +106,9 @@ func (g *stripeGateway) ProcessPayment(ctx context.Context, payment *domain.Paym
 	}
 
 	// Confirm payment intent
-	confirmedPI, err := paymentintent.Confirm(pi.ID, &stripe.PaymentIntentConfirmParams{})
+	confirmParams := &stripe.PaymentIntentConfirmParams{}
+	confirmParams.SetIdempotencyKey(idempotencyKey("confirm", pi.ID))
+	confirmedPI, err := paymentintent.Confirm(pi.ID, confirmParams)
 	if err != nil {
 		return nil, fmt.Errorf("failed to confirm payment intent: %w", err)
 	}
@@ -129,7 +146,9 @@ func (g *stripeGateway) CancelPayment(ctx context.Context, payment *domain.Payme
 	}
 
 	// Cancel payment intent if it's still pending
-	pi, err := paymentintent.Cancel(*payment.TransactionID, nil)
+	params := &stripe.PaymentIntentCancelParams{}
+	params.SetIdempotencyKey(idempotencyKey("cancel", payment.ID))
+	pi, err := paymentintent.Cancel(*payment.TransactionID, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to cancel payment intent: %w", err)
 	}
@@ -144,8 +163,123 @@ func (g *stripeGateway) CancelPayment(ctx context.Context, payment *domain.Payme
 	}, nil
 }
 
-// RefundPayment refunds a payment via Stripe
-func (g *stripeGateway) RefundPayment(ctx context.Context, payment *domain.Payment, amount float64, reason string) (*domain.PaymentGatewayResponse, error) {
+// AuthorizePayment puts the payment amount on hold via a manual capture payment intent
+func (g *stripeGateway) AuthorizePayment(ctx context.Context, payment *domain.Payment, paymentMethodID string) (*domain.PaymentGatewayResponse, error) {
+	params := &stripe.PaymentIntentParams{
+		Amount:        stripe.Int64(payment.AmountMinor), // Already in minor units
+		Currency:      stripe.String(payment.Currency),
+		CaptureMethod: stripe.String(string(stripe.PaymentIntentCaptureMethodManual)),
+		Confirm:       stripe.Bool(true),
+	}
+	params.AddMetadata(metadataPaymentID, payment.ID)
+	params.SetIdempotencyKey(idempotencyKey("authorize", payment.ID, paymentMethodID))
+
+	// Add payment method if provided
+	if paymentMethodID != "" {
+		params.PaymentMethod = stripe.String(paymentMethodID)
+	}
+
+	pi, err := paymentintent.New(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authorize payment intent: %w", err)
+	}
+
+	// Determine status based on payment intent status
+	var status domain.PaymentStatus
+	var expiresAt *time.Time
+	switch pi.Status {
+	case stripe.PaymentIntentStatusRequiresCapture:
+		status = domain.PaymentStatusAuthorized
+		expiry := time.Unix(pi.Created, 0).Add(authorizationHoldPeriod)
+		expiresAt = &expiry
+	case stripe.PaymentIntentStatusRequiresPaymentMethod:
+		status = domain.PaymentStatusFailed
+	case stripe.PaymentIntentStatusRequiresAction, stripe.PaymentIntentStatusProcessing:
+		status = domain.PaymentStatusProcessing
+	default:
+		status = domain.PaymentStatusPending
+	}
+
+	return &domain.PaymentGatewayResponse{
+		Success:                status == domain.PaymentStatusAuthorized,
+		TransactionID:          pi.ID,
+		ClientSecret:           pi.ClientSecret,
+		Status:                 status,
+		AuthorizationExpiresAt: expiresAt,
+		GatewayResponse: map[string]interface{}{
+			"payment_intent_id": pi.ID,
+			"status":            pi.Status,
+			"amount_capturable": pi.AmountCapturable,
+		},
+	}, nil
+}
+
+// CapturePayment captures all or part of an authorized payment via Stripe
+func (g *stripeGateway) CapturePayment(ctx context.Context, payment *domain.Payment, amount money.Money) (*domain.PaymentGatewayResponse, error) {
+	if payment.TransactionID == nil {
+		return nil, fmt.Errorf("no transaction ID to capture")
+	}
+
+	// A payment intent is captured once, so the payment identifies the capture
+	params := &stripe.PaymentIntentCaptureParams{
+		AmountToCapture: stripe.Int64(amount.Amount), // Already in minor units
+	}
+	params.SetIdempotencyKey(idempotencyKey("capture", payment.ID))
+	pi, err := paymentintent.Capture(*payment.TransactionID, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture payment intent: %w", err)
+	}
+
+	var status domain.PaymentStatus
+	switch pi.Status {
+	case stripe.PaymentIntentStatusSucceeded:
+		status = domain.PaymentStatusCompleted
+	case stripe.PaymentIntentStatusProcessing:
+		status = domain.PaymentStatusProcessing
+	default:
+		status = domain.PaymentStatusFailed
+	}
+
+	return &domain.PaymentGatewayResponse{
+		Success:       status == domain.PaymentStatusCompleted,
+		TransactionID: pi.ID,
+		Status:        status,
+		GatewayResponse: map[string]interface{}{
+			"payment_intent_id": pi.ID,
+			"status":            pi.Status,
+			"amount_received":   pi.AmountReceived,
+		},
+	}, nil
+}
+
+// VoidAuthorization releases the hold on an authorized payment via Stripe
+func (g *stripeGateway) VoidAuthorization(ctx context.Context, payment *domain.Payment) (*domain.PaymentGatewayResponse, error) {
+	if payment.TransactionID == nil {
+		return nil, fmt.Errorf("no transaction ID to void")
+	}
+
+	params := &stripe.PaymentIntentCancelParams{
+		CancellationReason: stripe.String(string(stripe.PaymentIntentCancellationReasonRequestedByCustomer)),
+	}
+	params.SetIdempotencyKey(idempotencyKey("void", payment.ID))
+	pi, err := paymentintent.Cancel(*payment.TransactionID, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to void payment intent: %w", err)
+	}
+
+	return &domain.PaymentGatewayResponse{
+		Success:       true,
+		TransactionID: pi.ID,
+		Status:        domain.PaymentStatusCancelled,
+		GatewayResponse: map[string]interface{}{
+			"payment_intent_id": pi.ID,
+			"status":            pi.Status,
+		},
+	}, nil
+}
+
+// RefundPayment submits a refund of a payment to Stripe
+func (g *stripeGateway) RefundPayment(ctx context.Context, payment *domain.Payment, pending *domain.Refund) (*domain.PaymentGatewayResponse, error) {
 	if payment.TransactionID == nil {
 		return nil, fmt.Errorf("no transaction ID to refund")
 	}
@@ -153,9 +287,12 @@ func (g *stripeGateway) RefundPayment(ctx context.Context, payment *domain.Payme
 	// Create refund
 	refundParams := &stripe.RefundParams{
 		PaymentIntent: stripe.String(*payment.TransactionID),
-		Amount:        stripe.Int64(int64(amount * 100)), // Convert to cents
-		Reason:        stripe.String(reason),
+		Amount:        stripe.Int64(pending.AmountMinor), // Already in minor units
+		Reason:        stripe.String(stripeRefundReason(pending.Reason)),
 	}
+	refundParams.AddMetadata(metadataPaymentID, payment.ID)
+	refundParams.AddMetadata("reason", pending.Reason)
+	refundParams.SetIdempotencyKey(idempotencyKey("refund", pending.ID))
 
 	ref, err := refund.New(refundParams)
 	if err != nil {
@@ -186,6 +323,12 @@ func (g *stripeGateway) RefundPayment(ctx context.Context, payment *domain.Payme
 	}, nil
 }
 
+// idempotencyKey builds the Stripe idempotency key of an operation on a payment or
+// refund, so that Stripe answers a retried call with the result of the first one
+func idempotencyKey(operation string, ids ...string) string {
+	return operation + ":" + strings.Join(ids, ":")
+}
+
 // CreatePaymentMethod creates a payment method via Stripe
 func (g *stripeGateway) CreatePaymentMethod(ctx context.Context, userID uint, paymentMethod *domain.PaymentMethodInfo) (*domain.PaymentGatewayResponse, error) {
 	// Create Stripe customer if not exists
@@ -251,22 +394,33 @@ func (g *stripeGateway) DeletePaymentMethod(ctx context.Context, paymentMethodID
 	}, nil
 }
 
+// stripeRefundReason maps a free-text refund reason to one accepted by Stripe
+func stripeRefundReason(reason string) string {
+	switch stripe.RefundReason(reason) {
+	case stripe.RefundReasonDuplicate, stripe.RefundReasonFraudulent, stripe.RefundReasonRequestedByCustomer:
+		return reason
+	default:
+		return string(stripe.RefundReasonRequestedByCustomer)
+	}
+}
+
 // HealthCheck checks the health of the Stripe gateway
 func (g *stripeGateway) HealthCheck(ctx context.Context) error {
 	// Simple health check - in a real implementation, this would ping Stripe API
 	return nil
 }
 
-// ValidateWebhook validates Stripe webhook signature
-func (g *stripeGateway) ValidateWebhook(ctx context.Context, payload []byte, signature string) (map[string]interface{}, error) {
-	// This would typically use Stripe's webhook validation
-	// For now, we'll return a placeholder
-	return map[string]interface{}{
-		"type": "payment_intent.succeeded",
-		"data": map[string]interface{}{
-			"object": map[string]interface{}{
-				"id": "pi_test_123",
-			},
-		},
-	}, nil
+// ProcessWebhook verifies a Stripe webhook signature and parses the event
+func (g *stripeGateway) ProcessWebhook(ctx context.Context, payload []byte, signature string) (*domain.WebhookEvent, error) {
+	event, err := webhook.ConstructEvent(payload, signature, g.config.Stripe.WebhookSecret)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidWebhookSignature, err)
+	}
+
+	return toWebhookEvent(g.Provider(), event)
+}
+
+// Provider returns the name of the payment provider
+func (g *stripeGateway) Provider() string {
+	return domain.ProviderStripe
 }
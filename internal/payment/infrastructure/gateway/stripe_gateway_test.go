@@ -0,0 +1,182 @@
+package gateway
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ddd-micro/internal/payment/domain"
+	"github.com/ddd-micro/internal/payment/infrastructure/config"
+	"github.com/ddd-micro/pkg/money"
+	"github.com/stripe/stripe-go/v72"
+)
+
+// stripeRequest is a request the gateway sent to the Stripe stub
+type stripeRequest struct {
+	path           string
+	form           url.Values
+	idempotencyKey string
+}
+
+// stripeStub answers every Stripe API call with a payment intent in the given status
+type stripeStub struct {
+	status   stripe.PaymentIntentStatus
+	requests []stripeRequest
+}
+
+// newStripeStub points the Stripe client at a stub for the duration of the test
+func newStripeStub(t *testing.T, status stripe.PaymentIntentStatus) *stripeStub {
+	t.Helper()
+	stub := &stripeStub{status: status}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		stub.requests = append(stub.requests, stripeRequest{
+			path:           r.URL.Path,
+			form:           r.PostForm,
+			idempotencyKey: r.Header.Get("Idempotency-Key"),
+		})
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id": "pi_test", "object": "payment_intent", "status": %q, "created": 1700000000, "amount_capturable": 1999, "amount_received": 1250}`, stub.status)
+	}))
+	t.Cleanup(server.Close)
+
+	stripe.SetBackend(stripe.APIBackend, stripe.GetBackendWithConfig(stripe.APIBackend, &stripe.BackendConfig{
+		URL:               stripe.String(server.URL),
+		MaxNetworkRetries: stripe.Int64(0),
+		LeveledLogger:     &stripe.LeveledLogger{Level: stripe.LevelNull},
+	}))
+	t.Cleanup(func() { stripe.SetBackend(stripe.APIBackend, nil) })
+	return stub
+}
+
+// request returns the only request the gateway sent
+func (s *stripeStub) request(t *testing.T) stripeRequest {
+	t.Helper()
+	if len(s.requests) != 1 {
+		t.Fatalf("sent %d requests to Stripe, want 1", len(s.requests))
+	}
+	return s.requests[0]
+}
+
+// authorizedPayment returns a payment of 19.99 USD on hold at Stripe
+func authorizedPayment() *domain.Payment {
+	transactionID := "pi_test"
+	return &domain.Payment{
+		ID:            "pay_test",
+		AmountMinor:   1999,
+		Currency:      "USD",
+		Status:        domain.PaymentStatusAuthorized,
+		TransactionID: &transactionID,
+	}
+}
+
+func newTestStripeGateway() domain.PaymentGateway {
+	return NewStripeGateway(&config.Config{Stripe: config.StripeConfig{SecretKey: "sk_test"}})
+}
+
+func TestStripeAuthorizePaymentHoldsFundsForSevenDays(t *testing.T) {
+	stub := newStripeStub(t, stripe.PaymentIntentStatusRequiresCapture)
+	payment := authorizedPayment()
+	payment.Status = domain.PaymentStatusPending
+	payment.TransactionID = nil
+
+	resp, err := newTestStripeGateway().AuthorizePayment(t.Context(), payment, "pm_test")
+	if err != nil {
+		t.Fatalf("AuthorizePayment() error = %v", err)
+	}
+
+	request := stub.request(t)
+	if request.path != "/v1/payment_intents" || request.form.Get("capture_method") != "manual" || request.form.Get("amount") != "1999" {
+		t.Errorf("sent %s %v, want a manual capture intent of 1999", request.path, request.form)
+	}
+	if request.idempotencyKey != "authorize:pay_test:pm_test" {
+		t.Errorf("idempotency key = %q", request.idempotencyKey)
+	}
+
+	if resp.Status != domain.PaymentStatusAuthorized {
+		t.Fatalf("status = %s, want authorized", resp.Status)
+	}
+	want := time.Unix(1700000000, 0).Add(7 * 24 * time.Hour)
+	if resp.AuthorizationExpiresAt == nil || !resp.AuthorizationExpiresAt.Equal(want) {
+		t.Errorf("authorization expires at %v, want %v", resp.AuthorizationExpiresAt, want)
+	}
+}
+
+func TestStripeCapturePayment(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     stripe.PaymentIntentStatus
+		wantStatus domain.PaymentStatus
+	}{
+		{name: "succeeded", status: stripe.PaymentIntentStatusSucceeded, wantStatus: domain.PaymentStatusCompleted},
+		{name: "processing", status: stripe.PaymentIntentStatusProcessing, wantStatus: domain.PaymentStatusProcessing},
+		{name: "declined", status: stripe.PaymentIntentStatusRequiresPaymentMethod, wantStatus: domain.PaymentStatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := newStripeStub(t, tt.status)
+
+			resp, err := newTestStripeGateway().CapturePayment(t.Context(), authorizedPayment(), money.New(1250, "USD"))
+			if err != nil {
+				t.Fatalf("CapturePayment() error = %v", err)
+			}
+
+			// Only the amount to capture is charged; Stripe releases the rest of the hold
+			request := stub.request(t)
+			if request.path != "/v1/payment_intents/pi_test/capture" || request.form.Get("amount_to_capture") != "1250" {
+				t.Errorf("sent %s %v, want a capture of 1250", request.path, request.form)
+			}
+			if request.idempotencyKey != "capture:pay_test" {
+				t.Errorf("idempotency key = %q", request.idempotencyKey)
+			}
+			if resp.Status != tt.wantStatus || resp.Success != (tt.wantStatus == domain.PaymentStatusCompleted) {
+				t.Errorf("response = %+v, want status %s", resp, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestStripeVoidAuthorization(t *testing.T) {
+	stub := newStripeStub(t, stripe.PaymentIntentStatusCanceled)
+
+	resp, err := newTestStripeGateway().VoidAuthorization(t.Context(), authorizedPayment())
+	if err != nil {
+		t.Fatalf("VoidAuthorization() error = %v", err)
+	}
+
+	request := stub.request(t)
+	if request.path != "/v1/payment_intents/pi_test/cancel" || request.form.Get("cancellation_reason") != "requested_by_customer" {
+		t.Errorf("sent %s %v, want a cancellation of the intent", request.path, request.form)
+	}
+	if request.idempotencyKey != "void:pay_test" {
+		t.Errorf("idempotency key = %q", request.idempotencyKey)
+	}
+	if resp.Status != domain.PaymentStatusCancelled || !resp.Success {
+		t.Errorf("response = %+v, want cancelled", resp)
+	}
+}
+
+func TestStripeCaptureAndVoidNeedATransaction(t *testing.T) {
+	stub := newStripeStub(t, stripe.PaymentIntentStatusSucceeded)
+	payment := authorizedPayment()
+	payment.TransactionID = nil
+	gateway := newTestStripeGateway()
+
+	if _, err := gateway.CapturePayment(t.Context(), payment, payment.Amount()); err == nil {
+		t.Error("CapturePayment() without a transaction succeeded")
+	}
+	if _, err := gateway.VoidAuthorization(t.Context(), payment); err == nil {
+		t.Error("VoidAuthorization() without a transaction succeeded")
+	}
+	if len(stub.requests) != 0 {
+		t.Errorf("sent %d requests to Stripe, want none", len(stub.requests))
+	}
+}
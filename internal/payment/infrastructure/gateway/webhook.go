@@ -0,0 +1,80 @@
+package gateway
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ddd-micro/internal/payment/domain"
+	"github.com/stripe/stripe-go/v72"
+	"github.com/stripe/stripe-go/v72/webhook"
+)
+
+// Stripe webhook event types handled by the payment service
+const (
+	stripeEventPaymentIntentSucceeded = "payment_intent.succeeded"
+	stripeEventPaymentIntentFailed    = "payment_intent.payment_failed"
+	stripeEventPaymentIntentCanceled  = "payment_intent.canceled"
+	stripeEventChargeRefunded         = "charge.refunded"
+)
+
+// metadataPaymentID is the metadata key used to link gateway objects to payments
+const metadataPaymentID = "payment_id"
+
+// SignWebhookPayload signs a webhook payload the same way Stripe does, so that
+// fixture payloads can be replayed against the webhook endpoint
+func SignWebhookPayload(payload []byte, secret string) string {
+	now := time.Now()
+	signature := webhook.ComputeSignature(now, payload, secret)
+	return fmt.Sprintf("t=%d,v1=%s", now.Unix(), hex.EncodeToString(signature))
+}
+
+// toWebhookEvent converts a verified Stripe event into a domain webhook event
+func toWebhookEvent(provider string, event stripe.Event) (*domain.WebhookEvent, error) {
+	if event.ID == "" || event.Data == nil {
+		return nil, domain.ErrInvalidWebhookPayload
+	}
+
+	webhookEvent := &domain.WebhookEvent{
+		ID:           event.ID,
+		Type:         event.Type,
+		Data:         event.Data.Object,
+		Created:      event.Created,
+		Provider:     provider,
+		ProviderType: event.Type,
+		PaymentID:    event.GetObjectValue("metadata", metadataPaymentID),
+	}
+
+	switch event.Type {
+	case stripeEventPaymentIntentSucceeded:
+		webhookEvent.Type = domain.EventPaymentSucceeded
+		webhookEvent.TransactionID = event.GetObjectValue("id")
+		webhookEvent.AmountMinor = minorAmount(event.Data.Object["amount_received"])
+		webhookEvent.Currency = strings.ToUpper(event.GetObjectValue("currency"))
+	case stripeEventPaymentIntentFailed:
+		webhookEvent.Type = domain.EventPaymentFailed
+		webhookEvent.TransactionID = event.GetObjectValue("id")
+		webhookEvent.Reason = event.GetObjectValue("last_payment_error", "message")
+	case stripeEventPaymentIntentCanceled:
+		webhookEvent.Type = domain.EventPaymentCancelled
+		webhookEvent.TransactionID = event.GetObjectValue("id")
+		webhookEvent.Reason = event.GetObjectValue("cancellation_reason")
+	case stripeEventChargeRefunded:
+		webhookEvent.Type = domain.EventRefundSucceeded
+		webhookEvent.TransactionID = event.GetObjectValue("payment_intent")
+		webhookEvent.AmountMinor = minorAmount(event.Data.Object["amount_refunded"])
+		webhookEvent.Currency = strings.ToUpper(event.GetObjectValue("currency"))
+	}
+
+	return webhookEvent, nil
+}
+
+// minorAmount reads a Stripe amount, which is already in minor units
+func minorAmount(value interface{}) int64 {
+	amount, ok := value.(float64)
+	if !ok {
+		return 0
+	}
+	return int64(amount)
+}
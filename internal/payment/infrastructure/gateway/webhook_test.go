@@ -0,0 +1,134 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ddd-micro/internal/payment/domain"
+	"github.com/ddd-micro/internal/payment/infrastructure/config"
+	"github.com/stripe/stripe-go/v72/webhook"
+)
+
+const testWebhookSecret = "whsec_test"
+
+// paymentSucceededPayload returns a Stripe payment_intent.succeeded fixture
+func paymentSucceededPayload(eventID string) []byte {
+	return []byte(fmt.Sprintf(`{
+  "id": %q,
+  "object": "event",
+  "type": "payment_intent.succeeded",
+  "created": 1700000000,
+  "data": {
+    "object": {
+      "id": "pi_test",
+      "object": "payment_intent",
+      "amount_received": 1999,
+      "currency": "usd",
+      "metadata": {"payment_id": "pay_test"}
+    }
+  }
+}`, eventID))
+}
+
+// signWebhookPayloadAt signs a payload with the given signature timestamp
+func signWebhookPayloadAt(payload []byte, secret string, at time.Time) string {
+	signature := webhook.ComputeSignature(at, payload, secret)
+	return fmt.Sprintf("t=%d,v1=%s", at.Unix(), hex.EncodeToString(signature))
+}
+
+func testConfig() *config.Config {
+	return &config.Config{Stripe: config.StripeConfig{WebhookSecret: testWebhookSecret}}
+}
+
+func TestMockGatewayProcessWebhook(t *testing.T) {
+	gateway := NewMockGateway(testConfig())
+	payload := paymentSucceededPayload("evt_valid")
+	tampered := bytes.Replace(payload, []byte(`"amount_received": 1999`), []byte(`"amount_received": 1`), 1)
+
+	tests := []struct {
+		name      string
+		payload   []byte
+		signature string
+		wantErr   error
+	}{
+		{
+			name:      "valid",
+			payload:   payload,
+			signature: SignWebhookPayload(payload, testWebhookSecret),
+		},
+		{
+			name:      "tampered payload",
+			payload:   tampered,
+			signature: SignWebhookPayload(payload, testWebhookSecret),
+			wantErr:   domain.ErrInvalidWebhookSignature,
+		},
+		{
+			name:      "wrong secret",
+			payload:   payload,
+			signature: SignWebhookPayload(payload, "whsec_other"),
+			wantErr:   domain.ErrInvalidWebhookSignature,
+		},
+		{
+			name:      "missing signature",
+			payload:   payload,
+			signature: "",
+			wantErr:   domain.ErrInvalidWebhookSignature,
+		},
+		{
+			// Recorded fixtures are accepted regardless of their age; replays are
+			// caught by the processed event IDs instead
+			name:      "replayed fixture",
+			payload:   payload,
+			signature: signWebhookPayloadAt(payload, testWebhookSecret, time.Now().Add(-24*time.Hour)),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := gateway.ProcessWebhook(t.Context(), tt.payload, tt.signature)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ProcessWebhook() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProcessWebhook() error = %v", err)
+			}
+
+			if event.ID != "evt_valid" {
+				t.Errorf("event ID = %q, want %q", event.ID, "evt_valid")
+			}
+			if event.Type != domain.EventPaymentSucceeded {
+				t.Errorf("event type = %q, want %q", event.Type, domain.EventPaymentSucceeded)
+			}
+			if event.Provider != domain.ProviderMock {
+				t.Errorf("provider = %q, want %q", event.Provider, domain.ProviderMock)
+			}
+			if event.PaymentID != "pay_test" || event.TransactionID != "pi_test" {
+				t.Errorf("references = (%q, %q), want (pay_test, pi_test)", event.PaymentID, event.TransactionID)
+			}
+			if event.AmountMinor != 1999 || event.Currency != "USD" {
+				t.Errorf("amount = %d %s, want 1999 USD", event.AmountMinor, event.Currency)
+			}
+		})
+	}
+}
+
+func TestStripeGatewayRejectsReplayedWebhook(t *testing.T) {
+	gateway := NewStripeGateway(testConfig())
+	payload := paymentSucceededPayload("evt_replayed")
+
+	if _, err := gateway.ProcessWebhook(t.Context(), payload, SignWebhookPayload(payload, testWebhookSecret)); err != nil {
+		t.Fatalf("ProcessWebhook() fresh signature error = %v", err)
+	}
+
+	stale := signWebhookPayloadAt(payload, testWebhookSecret, time.Now().Add(-time.Hour))
+	if _, err := gateway.ProcessWebhook(t.Context(), payload, stale); !errors.Is(err, domain.ErrInvalidWebhookSignature) {
+		t.Fatalf("ProcessWebhook() stale signature error = %v, want %v", err, domain.ErrInvalidWebhookSignature)
+	}
+}
@@ -2,32 +2,43 @@ package kafka
 
 import (
 	"context"
+	"strconv"
 
 	"github.com/ddd-micro/kafka"
+	"github.com/ddd-micro/pkg/money"
+	"github.com/ddd-micro/pkg/outbox"
 )
 
-// PaymentEventPublisher handles payment-related Kafka events
+// Aggregate types of the payment service events
+const (
+	aggregatePayment = "payment"
+	aggregateProduct = "product"
+	aggregateBasket  = "basket"
+)
+
+// PaymentEventPublisher handles payment-related Kafka events. Events are written to
+// the outbox within the transaction carried by ctx and published by the outbox relay.
 type PaymentEventPublisher struct {
-	publisher kafka.EventPublisher
+	outbox *outbox.Store
 }
 
 // NewPaymentEventPublisher creates a new payment event publisher
-func NewPaymentEventPublisher(publisher kafka.EventPublisher) *PaymentEventPublisher {
+func NewPaymentEventPublisher(store *outbox.Store) *PaymentEventPublisher {
 	return &PaymentEventPublisher{
-		publisher: publisher,
+		outbox: store,
 	}
 }
 
 // PublishPaymentCompleted publishes a payment completed event
-func (p *PaymentEventPublisher) PublishPaymentCompleted(ctx context.Context, paymentID string, userID uint, orderID string, amount float64, currency string, paymentMethod string, items []kafka.PaymentItem, basketID *string) error {
+func (p *PaymentEventPublisher) PublishPaymentCompleted(ctx context.Context, paymentID string, userID uint, orderID string, amount money.Money, paymentMethod string, items []kafka.PaymentItem, basketID *string) error {
 	event := kafka.PaymentCompletedEvent{
-		BaseEvent: kafka.NewBaseEvent(kafka.EventTypePaymentCompleted, "payment-service"),
+		BaseEvent: kafka.NewBaseEvent(ctx, kafka.EventTypePaymentCompleted, "payment-service", paymentID),
 		Data: kafka.PaymentCompletedData{
 			PaymentID:     paymentID,
 			UserID:        userID,
 			OrderID:       orderID,
-			Amount:        amount,
-			Currency:      currency,
+			AmountMinor:   amount.Amount,
+			Currency:      amount.Currency,
 			PaymentMethod: paymentMethod,
 			Items:         items,
 			BasketID:      basketID,
@@ -38,53 +49,64 @@ func (p *PaymentEventPublisher) PublishPaymentCompleted(ctx context.Context, pay
 		},
 	}
 
-	return p.publisher.PublishPaymentCompleted(event)
+	return p.outbox.Add(ctx, aggregatePayment, paymentID, event.Type, event)
 }
 
 // PublishPaymentFailed publishes a payment failed event
-func (p *PaymentEventPublisher) PublishPaymentFailed(ctx context.Context, paymentID string, userID uint, orderID string, amount float64, currency string, paymentMethod string, reason string, basketID *string) error {
+func (p *PaymentEventPublisher) PublishPaymentFailed(ctx context.Context, paymentID string, userID uint, orderID string, amount money.Money, paymentMethod string, reason string, basketID *string) error {
 	event := kafka.PaymentFailedEvent{
-		BaseEvent: kafka.NewBaseEvent(kafka.EventTypePaymentFailed, "payment-service"),
+		BaseEvent: kafka.NewBaseEvent(ctx, kafka.EventTypePaymentFailed, "payment-service", paymentID),
 		Data: kafka.PaymentFailedData{
 			PaymentID:     paymentID,
 			UserID:        userID,
 			OrderID:       orderID,
-			Amount:        amount,
-			Currency:      currency,
+			AmountMinor:   amount.Amount,
+			Currency:      amount.Currency,
 			PaymentMethod: paymentMethod,
 			Reason:        reason,
 			BasketID:      basketID,
 		},
 	}
 
-	return p.publisher.PublishPaymentFailed(event)
+	return p.outbox.Add(ctx, aggregatePayment, paymentID, event.Type, event)
 }
 
 // PublishPaymentCancelled publishes a payment cancelled event
-func (p *PaymentEventPublisher) PublishPaymentCancelled(ctx context.Context, paymentID string, userID uint, orderID string, amount float64, currency string, paymentMethod string, reason string, basketID *string) error {
+func (p *PaymentEventPublisher) PublishPaymentCancelled(ctx context.Context, paymentID string, userID uint, orderID string, amount money.Money, paymentMethod string, reason string, basketID *string) error {
 	event := kafka.PaymentCancelledEvent{
-		BaseEvent: kafka.NewBaseEvent(kafka.EventTypePaymentCancelled, "payment-service"),
+		BaseEvent: kafka.NewBaseEvent(ctx, kafka.EventTypePaymentCancelled, "payment-service", paymentID),
 		Data: kafka.PaymentCancelledData{
 			PaymentID:     paymentID,
 			UserID:        userID,
 			OrderID:       orderID,
-			Amount:        amount,
-			Currency:      currency,
+			AmountMinor:   amount.Amount,
+			Currency:      amount.Currency,
 			PaymentMethod: paymentMethod,
 			Reason:        reason,
 			BasketID:      basketID,
 		},
 	}
 
-	return p.publisher.PublishPaymentCancelled(event)
+	return p.outbox.Add(ctx, aggregatePayment, paymentID, event.Type, event)
+}
+
+// PublishPaymentRefunded publishes a payment refunded event
+func (p *PaymentEventPublisher) PublishPaymentRefunded(ctx context.Context, data kafka.PaymentRefundedData) error {
+	event := kafka.PaymentRefundedEvent{
+		BaseEvent: kafka.NewBaseEvent(ctx, kafka.EventTypePaymentRefunded, "payment-service", data.PaymentID),
+		Data:      data,
+	}
+
+	return p.outbox.Add(ctx, aggregatePayment, data.PaymentID, event.Type, event)
 }
 
 // PublishStockUpdated publishes a stock updated event
-func (p *PaymentEventPublisher) PublishStockUpdated(ctx context.Context, productID uint, quantity int, newStock int, reason string, orderID *string, paymentID *string) error {
+func (p *PaymentEventPublisher) PublishStockUpdated(ctx context.Context, productID uint, variantID *uint, quantity int, newStock int, reason string, orderID *string, paymentID *string) error {
 	event := kafka.StockUpdatedEvent{
-		BaseEvent: kafka.NewBaseEvent(kafka.EventTypeStockUpdated, "payment-service"),
+		BaseEvent: kafka.NewBaseEvent(ctx, kafka.EventTypeStockUpdated, "payment-service", strconv.FormatUint(uint64(productID), 10)),
 		Data: kafka.StockUpdatedData{
 			ProductID: productID,
+			VariantID: variantID,
 			Quantity:  quantity,
 			NewStock:  newStock,
 			Reason:    reason,
@@ -93,13 +115,13 @@ func (p *PaymentEventPublisher) PublishStockUpdated(ctx context.Context, product
 		},
 	}
 
-	return p.publisher.PublishStockUpdated(event)
+	return p.outbox.Add(ctx, aggregateProduct, strconv.FormatUint(uint64(productID), 10), event.Type, event)
 }
 
 // PublishBasketCleared publishes a basket cleared event
 func (p *PaymentEventPublisher) PublishBasketCleared(ctx context.Context, userID uint, basketID string, items []kafka.PaymentItem, reason string, orderID *string, paymentID *string) error {
 	event := kafka.BasketClearedEvent{
-		BaseEvent: kafka.NewBaseEvent(kafka.EventTypeBasketCleared, "payment-service"),
+		BaseEvent: kafka.NewBaseEvent(ctx, kafka.EventTypeBasketCleared, "payment-service", basketID),
 		Data: kafka.BasketClearedData{
 			UserID:    userID,
 			BasketID:  basketID,
@@ -110,5 +132,5 @@ func (p *PaymentEventPublisher) PublishBasketCleared(ctx context.Context, userID
 		},
 	}
 
-	return p.publisher.PublishBasketCleared(event)
+	return p.outbox.Add(ctx, aggregateBasket, basketID, event.Type, event)
 }
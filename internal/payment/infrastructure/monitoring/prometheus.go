@@ -35,8 +35,7 @@ type PrometheusMetrics struct {
 	ExternalAPIDuration       *prometheus.HistogramVec
 	StripeAPICalls            *prometheus.CounterVec
 	StripeAPIDuration         *prometheus.HistogramVec
-	KafkaMessagesPublished    *prometheus.CounterVec
-	KafkaMessagesConsumed     *prometheus.CounterVec
+	WebhookEvents             *prometheus.CounterVec
 	CacheHits                 prometheus.Counter
 	CacheMisses               prometheus.Counter
 }
@@ -208,19 +207,12 @@ func NewPrometheusMetrics() *PrometheusMetrics {
 			},
 			[]string{"operation"},
 		),
-		KafkaMessagesPublished: promauto.NewCounterVec(
+		WebhookEvents: promauto.NewCounterVec(
 			prometheus.CounterOpts{
-				Name: "payment_service_kafka_messages_published_total",
-				Help: "Total number of Kafka messages published",
+				Name: "payment_service_webhook_events_total",
+				Help: "Total number of payment gateway webhook events received",
 			},
-			[]string{"topic", "status"},
-		),
-		KafkaMessagesConsumed: promauto.NewCounterVec(
-			prometheus.CounterOpts{
-				Name: "payment_service_kafka_messages_consumed_total",
-				Help: "Total number of Kafka messages consumed",
-			},
-			[]string{"topic", "status"},
+			[]string{"provider", "status"},
 		),
 		CacheHits: promauto.NewCounter(
 			prometheus.CounterOpts{
@@ -377,14 +369,9 @@ func (m *PrometheusMetrics) RecordStripeAPIDuration(operation string, duration t
 	m.StripeAPIDuration.WithLabelValues(operation).Observe(duration.Seconds())
 }
 
-// RecordKafkaMessagePublished records a published Kafka message
-func (m *PrometheusMetrics) RecordKafkaMessagePublished(topic, status string) {
-	m.KafkaMessagesPublished.WithLabelValues(topic, status).Inc()
-}
-
-// RecordKafkaMessageConsumed records a consumed Kafka message
-func (m *PrometheusMetrics) RecordKafkaMessageConsumed(topic, status string) {
-	m.KafkaMessagesConsumed.WithLabelValues(topic, status).Inc()
+// RecordWebhookEvent records a received webhook event
+func (m *PrometheusMetrics) RecordWebhookEvent(provider, status string) {
+	m.WebhookEvents.WithLabelValues(provider, status).Inc()
 }
 
 // RecordCacheHit increments the cache hit counter
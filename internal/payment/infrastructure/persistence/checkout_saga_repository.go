@@ -0,0 +1,109 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ddd-micro/internal/payment/domain"
+	"github.com/ddd-micro/pkg/gormtx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// checkoutSagaRepository implements domain.CheckoutSagaRepository
+type checkoutSagaRepository struct {
+	db *gorm.DB
+}
+
+// NewCheckoutSagaRepository creates a new checkout saga repository
+func NewCheckoutSagaRepository(db *gorm.DB) domain.CheckoutSagaRepository {
+	return &checkoutSagaRepository{
+		db: db,
+	}
+}
+
+// Create creates a new checkout saga
+func (r *checkoutSagaRepository) Create(ctx context.Context, saga *domain.CheckoutSaga) error {
+	if err := gormtx.DB(ctx, r.db).Create(saga).Error; err != nil {
+		return fmt.Errorf("failed to create checkout saga: %w", err)
+	}
+	return nil
+}
+
+// GetByID gets a checkout saga by ID
+func (r *checkoutSagaRepository) GetByID(ctx context.Context, sagaID string) (*domain.CheckoutSaga, error) {
+	var saga domain.CheckoutSaga
+	if err := gormtx.DB(ctx, r.db).Where("id = ?", sagaID).First(&saga).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrCheckoutNotFound
+		}
+		return nil, fmt.Errorf("failed to get checkout saga: %w", err)
+	}
+	return &saga, nil
+}
+
+// GetByPaymentID gets the checkout saga of a payment
+func (r *checkoutSagaRepository) GetByPaymentID(ctx context.Context, paymentID string) (*domain.CheckoutSaga, error) {
+	var saga domain.CheckoutSaga
+	if err := gormtx.DB(ctx, r.db).Where("payment_id = ?", paymentID).First(&saga).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, domain.ErrCheckoutNotFound
+		}
+		return nil, fmt.Errorf("failed to get checkout saga by payment ID: %w", err)
+	}
+	return &saga, nil
+}
+
+// Update saves the saga if its version is unchanged and bumps the version
+func (r *checkoutSagaRepository) Update(ctx context.Context, saga *domain.CheckoutSaga) error {
+	version := saga.Version
+	saga.Version++
+
+	result := gormtx.DB(ctx, r.db).Model(saga).
+		Where("version = ?", version).
+		Select("*").Omit("id", "created_at").
+		Updates(saga)
+	if result.Error != nil {
+		saga.Version = version
+		return fmt.Errorf("failed to update checkout saga: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		saga.Version = version
+		return domain.ErrCheckoutConflict
+	}
+	return nil
+}
+
+// ClaimDue leases due sagas, skipping those another instance is claiming at the same time
+func (r *checkoutSagaRepository) ClaimDue(ctx context.Context, lease time.Duration, limit int) ([]*domain.CheckoutSaga, error) {
+	var sagas []*domain.CheckoutSaga
+
+	err := gormtx.DB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ? AND next_attempt_at <= ?",
+				[]domain.CheckoutStatus{domain.CheckoutStatusRunning, domain.CheckoutStatusCompensating}, now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Find(&sagas).Error; err != nil {
+			return err
+		}
+
+		for _, saga := range sagas {
+			saga.Version++
+			saga.NextAttemptAt = now.Add(lease)
+			if err := tx.Model(saga).Updates(map[string]interface{}{
+				"version":         saga.Version,
+				"next_attempt_at": saga.NextAttemptAt,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due checkout sagas: %w", err)
+	}
+	return sagas, nil
+}
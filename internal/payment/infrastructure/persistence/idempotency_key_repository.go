@@ -0,0 +1,73 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ddd-micro/internal/payment/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// idempotencyKeyRepository implements domain.IdempotencyKeyRepository
+type idempotencyKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewIdempotencyKeyRepository creates a new idempotency key repository
+func NewIdempotencyKeyRepository(db *gorm.DB) domain.IdempotencyKeyRepository {
+	return &idempotencyKeyRepository{
+		db: db,
+	}
+}
+
+// Acquire stores the key unless an unexpired key with the same user and value exists
+func (r *idempotencyKeyRepository) Acquire(ctx context.Context, key *domain.IdempotencyKey) (*domain.IdempotencyKey, bool, error) {
+	var existing domain.IdempotencyKey
+	acquired := false
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Free the key if its previous use has expired
+		if err := tx.Where("user_id = ? AND key = ? AND expires_at < ?", key.UserID, key.Key, time.Now()).
+			Delete(&domain.IdempotencyKey{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(key)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 1 {
+			acquired = true
+			return nil
+		}
+
+		return tx.Where("user_id = ? AND key = ?", key.UserID, key.Key).First(&existing).Error
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to acquire idempotency key: %w", err)
+	}
+
+	if acquired {
+		return key, true, nil
+	}
+	return &existing, false, nil
+}
+
+// Complete stores the response of the request that owns the key
+func (r *idempotencyKeyRepository) Complete(ctx context.Context, key *domain.IdempotencyKey) error {
+	if err := r.db.WithContext(ctx).Save(key).Error; err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Release deletes a key so the request can be retried
+func (r *idempotencyKeyRepository) Release(ctx context.Context, userID uint, key string) error {
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND key = ?", userID, key).
+		Delete(&domain.IdempotencyKey{}).Error; err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
@@ -6,7 +6,9 @@ import (
 	"time"
 
 	"github.com/ddd-micro/internal/payment/domain"
+	"github.com/ddd-micro/pkg/gormtx"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // paymentRepository implements domain.PaymentRepository
@@ -23,16 +25,28 @@ func NewPaymentRepository(db *gorm.DB) domain.PaymentRepository {
 
 // Create creates a new payment
 func (r *paymentRepository) Create(ctx context.Context, payment *domain.Payment) error {
-	if err := r.db.WithContext(ctx).Create(payment).Error; err != nil {
-		return fmt.Errorf("failed to create payment: %w", err)
-	}
-	return nil
+	return gormtx.DB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(payment).Error; err != nil {
+			return fmt.Errorf("failed to create payment: %w", err)
+		}
+		return saveStatusHistory(tx, payment)
+	})
 }
 
 // GetByID gets a payment by ID
 func (r *paymentRepository) GetByID(ctx context.Context, paymentID string) (*domain.Payment, error) {
+	return r.get(gormtx.DB(ctx, r.db), paymentID)
+}
+
+// GetByIDForUpdate gets a payment by ID, locking its row
+func (r *paymentRepository) GetByIDForUpdate(ctx context.Context, paymentID string) (*domain.Payment, error) {
+	return r.get(gormtx.DB(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}), paymentID)
+}
+
+// get gets a payment by ID
+func (r *paymentRepository) get(db *gorm.DB, paymentID string) (*domain.Payment, error) {
 	var payment domain.Payment
-	if err := r.db.WithContext(ctx).Where("id = ?", paymentID).First(&payment).Error; err != nil {
+	if err := db.Where("id = ?", paymentID).First(&payment).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, domain.ErrPaymentNotFound
 		}
@@ -44,7 +58,7 @@ func (r *paymentRepository) GetByID(ctx context.Context, paymentID string) (*dom
 // GetByOrderID gets a payment by order ID
 func (r *paymentRepository) GetByOrderID(ctx context.Context, orderID string) (*domain.Payment, error) {
 	var payment domain.Payment
-	if err := r.db.WithContext(ctx).Where("order_id = ?", orderID).First(&payment).Error; err != nil {
+	if err := gormtx.DB(ctx, r.db).Where("order_id = ?", orderID).First(&payment).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, domain.ErrPaymentNotFound
 		}
@@ -53,12 +67,21 @@ func (r *paymentRepository) GetByOrderID(ctx context.Context, orderID string) (*
 	return &payment, nil
 }
 
+// ListByOrderID lists the payments of an order, oldest first
+func (r *paymentRepository) ListByOrderID(ctx context.Context, orderID string) ([]*domain.Payment, error) {
+	var payments []*domain.Payment
+	if err := gormtx.DB(ctx, r.db).Where("order_id = ?", orderID).Order("created_at ASC").Find(&payments).Error; err != nil {
+		return nil, fmt.Errorf("failed to list payments by order ID: %w", err)
+	}
+	return payments, nil
+}
+
 // GetByUserID gets payments by user ID with pagination and status filter
 func (r *paymentRepository) GetByUserID(ctx context.Context, userID uint, limit, offset int, status string) ([]*domain.Payment, int, error) {
 	var payments []*domain.Payment
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&domain.Payment{}).Where("user_id = ?", userID)
+	query := gormtx.DB(ctx, r.db).Model(&domain.Payment{}).Where("user_id = ?", userID)
 
 	// Apply status filter if provided
 	if status != "" {
@@ -81,7 +104,7 @@ func (r *paymentRepository) GetByUserID(ctx context.Context, userID uint, limit,
 // GetByTransactionID gets a payment by transaction ID
 func (r *paymentRepository) GetByTransactionID(ctx context.Context, transactionID string) (*domain.Payment, error) {
 	var payment domain.Payment
-	if err := r.db.WithContext(ctx).Where("transaction_id = ?", transactionID).First(&payment).Error; err != nil {
+	if err := gormtx.DB(ctx, r.db).Where("transaction_id = ?", transactionID).First(&payment).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, domain.ErrPaymentNotFound
 		}
@@ -90,18 +113,41 @@ func (r *paymentRepository) GetByTransactionID(ctx context.Context, transactionI
 	return &payment, nil
 }
 
-// Update updates a payment
+// Update updates a payment together with the status transitions made since it was loaded
 func (r *paymentRepository) Update(ctx context.Context, payment *domain.Payment) error {
 	payment.UpdatedAt = time.Now()
-	if err := r.db.WithContext(ctx).Save(payment).Error; err != nil {
-		return fmt.Errorf("failed to update payment: %w", err)
+	return gormtx.DB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(payment).Error; err != nil {
+			return fmt.Errorf("failed to update payment: %w", err)
+		}
+		return saveStatusHistory(tx, payment)
+	})
+}
+
+// saveStatusHistory stores the pending status transitions of a payment
+func saveStatusHistory(tx *gorm.DB, payment *domain.Payment) error {
+	history := payment.PullStatusHistory()
+	if len(history) == 0 {
+		return nil
+	}
+	if err := tx.Create(&history).Error; err != nil {
+		return fmt.Errorf("failed to save payment status history: %w", err)
 	}
 	return nil
 }
 
+// GetStatusHistory gets the status transitions of a payment, oldest first
+func (r *paymentRepository) GetStatusHistory(ctx context.Context, paymentID string) ([]*domain.PaymentStatusHistory, error) {
+	var history []*domain.PaymentStatusHistory
+	if err := gormtx.DB(ctx, r.db).Where("payment_id = ?", paymentID).Order("created_at ASC, id ASC").Find(&history).Error; err != nil {
+		return nil, fmt.Errorf("failed to get payment status history: %w", err)
+	}
+	return history, nil
+}
+
 // Delete deletes a payment
 func (r *paymentRepository) Delete(ctx context.Context, paymentID string) error {
-	if err := r.db.WithContext(ctx).Where("id = ?", paymentID).Delete(&domain.Payment{}).Error; err != nil {
+	if err := gormtx.DB(ctx, r.db).Where("id = ?", paymentID).Delete(&domain.Payment{}).Error; err != nil {
 		return fmt.Errorf("failed to delete payment: %w", err)
 	}
 	return nil
@@ -119,7 +165,7 @@ func (r *paymentRepository) UpdateStatus(ctx context.Context, paymentID string,
 		updates["completed_at"] = &now
 	}
 
-	if err := r.db.WithContext(ctx).Model(&domain.Payment{}).Where("id = ?", paymentID).Updates(updates).Error; err != nil {
+	if err := gormtx.DB(ctx, r.db).Model(&domain.Payment{}).Where("id = ?", paymentID).Updates(updates).Error; err != nil {
 		return fmt.Errorf("failed to update payment status: %w", err)
 	}
 	return nil
@@ -130,7 +176,7 @@ func (r *paymentRepository) GetByStatus(ctx context.Context, status domain.Payme
 	var payments []*domain.Payment
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&domain.Payment{}).Where("status = ?", status)
+	query := gormtx.DB(ctx, r.db).Model(&domain.Payment{}).Where("status = ?", status)
 
 	// Get total count
 	if err := query.Count(&total).Error; err != nil {
@@ -149,7 +195,7 @@ func (r *paymentRepository) GetByStatus(ctx context.Context, status domain.Payme
 func (r *paymentRepository) GetPaymentStats(ctx context.Context, userID *uint, startDate, endDate *string) (*domain.PaymentStats, error) {
 	var stats domain.PaymentStats
 
-	query := r.db.WithContext(ctx).Model(&domain.Payment{})
+	query := gormtx.DB(ctx, r.db).Model(&domain.Payment{})
 
 	// Apply user filter if provided
 	if userID != nil {
@@ -161,6 +207,9 @@ func (r *paymentRepository) GetPaymentStats(ctx context.Context, userID *uint, s
 		query = query.Where("created_at BETWEEN ? AND ?", *startDate, *endDate)
 	}
 
+	// Share the filters between the aggregate queries below without accumulating conditions
+	query = query.Session(&gorm.Session{})
+
 	// Get total payments and amount
 	var totalPayments int64
 	if err := query.Count(&totalPayments).Error; err != nil {
@@ -168,13 +217,19 @@ func (r *paymentRepository) GetPaymentStats(ctx context.Context, userID *uint, s
 	}
 	stats.TotalPayments = int(totalPayments)
 
-	if err := query.Select("COALESCE(SUM(amount), 0)").Scan(&stats.TotalAmount).Error; err != nil {
+	totalAmounts, err := sumByCurrency(query, "payments")
+	if err != nil {
 		return nil, fmt.Errorf("failed to get total amount: %w", err)
 	}
+	stats.TotalAmounts = totalAmounts
 
-	// Get successful payments
+	// Get successful payments, including the ones refunded afterwards
 	var successfulPayments int64
-	if err := query.Where("status = ?", domain.PaymentStatusCompleted).Count(&successfulPayments).Error; err != nil {
+	if err := query.Where("status IN ?", []domain.PaymentStatus{
+		domain.PaymentStatusCompleted,
+		domain.PaymentStatusPartiallyRefunded,
+		domain.PaymentStatusRefunded,
+	}).Count(&successfulPayments).Error; err != nil {
 		return nil, fmt.Errorf("failed to count successful payments: %w", err)
 	}
 	stats.SuccessfulPayments = int(successfulPayments)
@@ -193,35 +248,40 @@ func (r *paymentRepository) GetPaymentStats(ctx context.Context, userID *uint, s
 	}
 	stats.PendingPayments = int(pendingPayments)
 
-	// Get refunded amount
-	if err := query.Where("status = ?", domain.PaymentStatusRefunded).Select("COALESCE(SUM(amount), 0)").Scan(&stats.RefundedAmount).Error; err != nil {
-		return nil, fmt.Errorf("failed to get refunded amount: %w", err)
+	// Get refunded amount from completed refunds, so partial refunds are counted as well
+	refundQuery := gormtx.DB(ctx, r.db).Model(&domain.Refund{}).
+		Joins("JOIN payments ON refunds.payment_id = payments.id").
+		Where("refunds.status = ?", domain.RefundStatusCompleted)
+	if userID != nil {
+		refundQuery = refundQuery.Where("payments.user_id = ?", *userID)
 	}
-
-	// Calculate average amount
-	if stats.TotalPayments > 0 {
-		stats.AverageAmount = stats.TotalAmount / float64(stats.TotalPayments)
+	if startDate != nil && endDate != nil {
+		refundQuery = refundQuery.Where("payments.created_at BETWEEN ? AND ?", *startDate, *endDate)
+	}
+	refundedAmounts, err := sumByCurrency(refundQuery, "refunds")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refunded amount: %w", err)
 	}
+	stats.RefundedAmounts = refundedAmounts
 
 	return &stats, nil
 }
 
 // GetTotalAmountByStatus gets total amount by status
-func (r *paymentRepository) GetTotalAmountByStatus(ctx context.Context, status domain.PaymentStatus, startDate, endDate *string) (float64, error) {
-	var total float64
-
-	query := r.db.WithContext(ctx).Model(&domain.Payment{}).Where("status = ?", status)
+func (r *paymentRepository) GetTotalAmountByStatus(ctx context.Context, status domain.PaymentStatus, startDate, endDate *string) ([]domain.CurrencyTotal, error) {
+	query := gormtx.DB(ctx, r.db).Model(&domain.Payment{}).Where("status = ?", status)
 
 	// Apply date range filter if provided
 	if startDate != nil && endDate != nil {
 		query = query.Where("created_at BETWEEN ? AND ?", *startDate, *endDate)
 	}
 
-	if err := query.Select("COALESCE(SUM(amount), 0)").Scan(&total).Error; err != nil {
-		return 0, fmt.Errorf("failed to get total amount by status: %w", err)
+	totals, err := sumByCurrency(query, "payments")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total amount by status: %w", err)
 	}
 
-	return total, nil
+	return totals, nil
 }
 
 // GetExpiredPayments gets expired payments
@@ -229,7 +289,7 @@ func (r *paymentRepository) GetExpiredPayments(ctx context.Context) ([]*domain.P
 	var payments []*domain.Payment
 	now := time.Now()
 
-	if err := r.db.WithContext(ctx).Where("status = ? AND expires_at < ?", domain.PaymentStatusPending, now).Find(&payments).Error; err != nil {
+	if err := gormtx.DB(ctx, r.db).Where("status = ? AND expires_at < ?", domain.PaymentStatusPending, now).Find(&payments).Error; err != nil {
 		return nil, fmt.Errorf("failed to get expired payments: %w", err)
 	}
 
@@ -239,7 +299,7 @@ func (r *paymentRepository) GetExpiredPayments(ctx context.Context) ([]*domain.P
 // CleanupExpiredPayments cleans up expired payments
 func (r *paymentRepository) CleanupExpiredPayments(ctx context.Context) (int, error) {
 	now := time.Now()
-	result := r.db.WithContext(ctx).Where("status = ? AND expires_at < ?", domain.PaymentStatusPending, now).Delete(&domain.Payment{})
+	result := gormtx.DB(ctx, r.db).Where("status = ? AND expires_at < ?", domain.PaymentStatusPending, now).Delete(&domain.Payment{})
 
 	if result.Error != nil {
 		return 0, fmt.Errorf("failed to cleanup expired payments: %w", result.Error)
@@ -247,3 +307,24 @@ func (r *paymentRepository) CleanupExpiredPayments(ctx context.Context) (int, er
 
 	return int(result.RowsAffected), nil
 }
+
+// sumByCurrency sums amount_minor of the given table per currency. The average is
+// rounded down to a whole minor unit.
+func sumByCurrency(query *gorm.DB, table string) ([]domain.CurrencyTotal, error) {
+	var totals []domain.CurrencyTotal
+	err := query.
+		Select(fmt.Sprintf("%[1]s.currency AS currency, COUNT(*) AS count, COALESCE(SUM(%[1]s.amount_minor), 0) AS amount_minor", table)).
+		Group(table + ".currency").
+		Order(table + ".currency").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range totals {
+		if totals[i].Count > 0 {
+			totals[i].AverageMinor = totals[i].AmountMinor / int64(totals[i].Count)
+		}
+	}
+	return totals, nil
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/ddd-micro/internal/payment/domain"
+	"github.com/ddd-micro/pkg/gormtx"
 	"gorm.io/gorm"
 )
 
@@ -23,7 +24,7 @@ func NewRefundRepository(db *gorm.DB) domain.RefundRepository {
 
 // Create creates a new refund
 func (r *refundRepository) Create(ctx context.Context, refund *domain.Refund) error {
-	if err := r.db.WithContext(ctx).Create(refund).Error; err != nil {
+	if err := gormtx.DB(ctx, r.db).Create(refund).Error; err != nil {
 		return fmt.Errorf("failed to create refund: %w", err)
 	}
 	return nil
@@ -32,7 +33,7 @@ func (r *refundRepository) Create(ctx context.Context, refund *domain.Refund) er
 // GetByID gets a refund by ID
 func (r *refundRepository) GetByID(ctx context.Context, refundID string) (*domain.Refund, error) {
 	var refund domain.Refund
-	if err := r.db.WithContext(ctx).Where("id = ?", refundID).First(&refund).Error; err != nil {
+	if err := gormtx.DB(ctx, r.db).Where("id = ?", refundID).First(&refund).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, domain.ErrRefundNotFound
 		}
@@ -44,7 +45,7 @@ func (r *refundRepository) GetByID(ctx context.Context, refundID string) (*domai
 // GetByPaymentID gets refunds by payment ID
 func (r *refundRepository) GetByPaymentID(ctx context.Context, paymentID string) ([]*domain.Refund, error) {
 	var refunds []*domain.Refund
-	if err := r.db.WithContext(ctx).Where("payment_id = ?", paymentID).Order("created_at DESC").Find(&refunds).Error; err != nil {
+	if err := gormtx.DB(ctx, r.db).Where("payment_id = ?", paymentID).Order("created_at DESC").Find(&refunds).Error; err != nil {
 		return nil, fmt.Errorf("failed to get refunds by payment ID: %w", err)
 	}
 	return refunds, nil
@@ -56,7 +57,7 @@ func (r *refundRepository) GetByUserID(ctx context.Context, userID uint, limit,
 	var total int64
 
 	// Join with payments table to filter by user_id
-	query := r.db.WithContext(ctx).Model(&domain.Refund{}).
+	query := gormtx.DB(ctx, r.db).Model(&domain.Refund{}).
 		Joins("JOIN payments ON refunds.payment_id = payments.id").
 		Where("payments.user_id = ?", userID)
 
@@ -76,7 +77,7 @@ func (r *refundRepository) GetByUserID(ctx context.Context, userID uint, limit,
 // Update updates a refund
 func (r *refundRepository) Update(ctx context.Context, refund *domain.Refund) error {
 	refund.UpdatedAt = time.Now()
-	if err := r.db.WithContext(ctx).Save(refund).Error; err != nil {
+	if err := gormtx.DB(ctx, r.db).Save(refund).Error; err != nil {
 		return fmt.Errorf("failed to update refund: %w", err)
 	}
 	return nil
@@ -84,7 +85,7 @@ func (r *refundRepository) Update(ctx context.Context, refund *domain.Refund) er
 
 // Delete deletes a refund
 func (r *refundRepository) Delete(ctx context.Context, refundID string) error {
-	if err := r.db.WithContext(ctx).Where("id = ?", refundID).Delete(&domain.Refund{}).Error; err != nil {
+	if err := gormtx.DB(ctx, r.db).Where("id = ?", refundID).Delete(&domain.Refund{}).Error; err != nil {
 		return fmt.Errorf("failed to delete refund: %w", err)
 	}
 	return nil
@@ -102,7 +103,7 @@ func (r *refundRepository) UpdateStatus(ctx context.Context, refundID string, st
 		updates["completed_at"] = &now
 	}
 
-	if err := r.db.WithContext(ctx).Model(&domain.Refund{}).Where("id = ?", refundID).Updates(updates).Error; err != nil {
+	if err := gormtx.DB(ctx, r.db).Model(&domain.Refund{}).Where("id = ?", refundID).Updates(updates).Error; err != nil {
 		return fmt.Errorf("failed to update refund status: %w", err)
 	}
 	return nil
@@ -113,7 +114,7 @@ func (r *refundRepository) GetByStatus(ctx context.Context, status string, limit
 	var refunds []*domain.Refund
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&domain.Refund{}).Where("status = ?", status)
+	query := gormtx.DB(ctx, r.db).Model(&domain.Refund{}).Where("status = ?", status)
 
 	// Get total count
 	if err := query.Count(&total).Error; err != nil {
@@ -132,7 +133,7 @@ func (r *refundRepository) GetByStatus(ctx context.Context, status string, limit
 func (r *refundRepository) GetRefundStats(ctx context.Context, userID *uint, startDate, endDate *string) (*domain.RefundStats, error) {
 	var stats domain.RefundStats
 
-	query := r.db.WithContext(ctx).Model(&domain.Refund{})
+	query := gormtx.DB(ctx, r.db).Model(&domain.Refund{})
 
 	// Apply user filter if provided
 	if userID != nil {
@@ -145,6 +146,9 @@ func (r *refundRepository) GetRefundStats(ctx context.Context, userID *uint, sta
 		query = query.Where("refunds.created_at BETWEEN ? AND ?", *startDate, *endDate)
 	}
 
+	// Share the filters between the aggregate queries below without accumulating conditions
+	query = query.Session(&gorm.Session{})
+
 	// Get total refunds and amount
 	var totalRefunds int64
 	if err := query.Count(&totalRefunds).Error; err != nil {
@@ -152,9 +156,11 @@ func (r *refundRepository) GetRefundStats(ctx context.Context, userID *uint, sta
 	}
 	stats.TotalRefunds = int(totalRefunds)
 
-	if err := query.Select("COALESCE(SUM(amount), 0)").Scan(&stats.TotalAmount).Error; err != nil {
+	totalAmounts, err := sumByCurrency(query, "refunds")
+	if err != nil {
 		return nil, fmt.Errorf("failed to get total refund amount: %w", err)
 	}
+	stats.TotalAmounts = totalAmounts
 
 	// Get completed refunds
 	var completedRefunds int64
@@ -177,19 +183,12 @@ func (r *refundRepository) GetRefundStats(ctx context.Context, userID *uint, sta
 	}
 	stats.FailedRefunds = int(failedRefunds)
 
-	// Calculate average amount
-	if stats.TotalRefunds > 0 {
-		stats.AverageAmount = stats.TotalAmount / float64(stats.TotalRefunds)
-	}
-
 	return &stats, nil
 }
 
 // GetTotalRefundAmount gets total refund amount
-func (r *refundRepository) GetTotalRefundAmount(ctx context.Context, userID *uint, startDate, endDate *string) (float64, error) {
-	var total float64
-
-	query := r.db.WithContext(ctx).Model(&domain.Refund{})
+func (r *refundRepository) GetTotalRefundAmount(ctx context.Context, userID *uint, startDate, endDate *string) ([]domain.CurrencyTotal, error) {
+	query := gormtx.DB(ctx, r.db).Model(&domain.Refund{})
 
 	// Apply user filter if provided
 	if userID != nil {
@@ -202,9 +201,10 @@ func (r *refundRepository) GetTotalRefundAmount(ctx context.Context, userID *uin
 		query = query.Where("refunds.created_at BETWEEN ? AND ?", *startDate, *endDate)
 	}
 
-	if err := query.Select("COALESCE(SUM(amount), 0)").Scan(&total).Error; err != nil {
-		return 0, fmt.Errorf("failed to get total refund amount: %w", err)
+	totals, err := sumByCurrency(query, "refunds")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get total refund amount: %w", err)
 	}
 
-	return total, nil
+	return totals, nil
 }
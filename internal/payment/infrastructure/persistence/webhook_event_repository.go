@@ -0,0 +1,42 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ddd-micro/internal/payment/domain"
+	"github.com/ddd-micro/pkg/gormtx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// webhookEventRepository implements domain.WebhookEventRepository
+type webhookEventRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookEventRepository creates a new webhook event repository
+func NewWebhookEventRepository(db *gorm.DB) domain.WebhookEventRepository {
+	return &webhookEventRepository{
+		db: db,
+	}
+}
+
+// Exists checks whether a webhook event has already been processed
+func (r *webhookEventRepository) Exists(ctx context.Context, provider, eventID string) (bool, error) {
+	var count int64
+	if err := gormtx.DB(ctx, r.db).Model(&domain.ProcessedWebhookEvent{}).
+		Where("provider = ? AND event_id = ?", provider, eventID).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check webhook event: %w", err)
+	}
+	return count > 0, nil
+}
+
+// Create records a processed webhook event, ignoring events that are already recorded
+func (r *webhookEventRepository) Create(ctx context.Context, event *domain.ProcessedWebhookEvent) error {
+	if err := gormtx.DB(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(event).Error; err != nil {
+		return fmt.Errorf("failed to record webhook event: %w", err)
+	}
+	return nil
+}
@@ -8,7 +8,10 @@ import (
 	"github.com/ddd-micro/internal/payment/infrastructure/monitoring"
 	"github.com/ddd-micro/internal/payment/infrastructure/persistence"
 	"github.com/ddd-micro/kafka"
+	"github.com/ddd-micro/pkg/gormtx"
+	"github.com/ddd-micro/pkg/outbox"
 	"github.com/google/wire"
+	"gorm.io/gorm"
 )
 
 // ProviderSet is the Wire provider set for infrastructure
@@ -18,25 +21,71 @@ var ProviderSet = wire.NewSet(
 
 	// Database
 	database.NewPostgresDB,
+	gormtx.NewTransactor,
 
 	// Repositories
 	persistence.NewPaymentRepository,
 	persistence.NewPaymentMethodRepository,
 	persistence.NewRefundRepository,
+	persistence.NewWebhookEventRepository,
+	persistence.NewIdempotencyKeyRepository,
+	persistence.NewCheckoutSagaRepository,
 
 	// External service clients
-	client.NewUserClient,
-	client.NewProductClient,
-	client.NewBasketClient,
+	ProvideUserClient,
+	ProvideProductClient,
+	ProvideBasketClient,
 
-	// Payment gateways
-	gateway.NewStripeGateway,
-	gateway.NewMockGateway,
+	// Payment gateway
+	gateway.NewPaymentGateway,
 
 	// Kafka
 	kafka.LoadConfig,
-	kafka.NewKafkaPublisher,
+	ProvideKafkaPublisher,
+	ProvideKafkaConsumer,
+
+	// Outbox
+	outbox.NewStore,
+	ProvideOutboxRelay,
 
 	// Monitoring
 	monitoring.ProviderSet,
 )
+
+// ProvideUserClient provides user client
+func ProvideUserClient(cfg *config.Config) (client.UserClient, error) {
+	return client.NewUserClient(cfg.UserServiceURL)
+}
+
+// ProvideProductClient provides product client
+func ProvideProductClient(cfg *config.Config) (client.ProductClient, error) {
+	return client.NewProductClient(cfg.ProductServiceURL)
+}
+
+// ProvideBasketClient provides basket client
+func ProvideBasketClient(cfg *config.Config) (client.BasketClient, error) {
+	return client.NewBasketClient(cfg.BasketServiceURL)
+}
+
+// ProvideKafkaPublisher provides Kafka event publisher
+func ProvideKafkaPublisher(cfg *kafka.Config) (kafka.EventPublisher, error) {
+	return kafka.NewPublisher(cfg.GetPublisherConfig())
+}
+
+// ProvideKafkaConsumer provides Kafka event consumer that skips already processed events,
+// driving checkout sagas with payment and basket events
+func ProvideKafkaConsumer(cfg *kafka.Config, db *gorm.DB, transactor *gormtx.Transactor) (kafka.EventConsumer, error) {
+	consumer, err := kafka.NewConsumer(cfg.GetConsumerConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	store := kafka.NewPostgresProcessedEventStore(db, transactor, cfg.DedupRetention)
+	consumer.Use(kafka.Dedup(cfg.GroupID, store))
+	return consumer, nil
+}
+
+// ProvideOutboxRelay provides the relay publishing outbox events to Kafka
+func ProvideOutboxRelay(db *gorm.DB, publisher kafka.EventPublisher) *outbox.Relay {
+	return outbox.NewRelay(db, publisher, outbox.LoadRelayConfig("payment-service"), outbox.NewMetrics("payment_service"))
+}
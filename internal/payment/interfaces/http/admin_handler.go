@@ -1,23 +1,28 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/ddd-micro/internal/payment/application"
 	"github.com/ddd-micro/internal/payment/application/dto"
+	"github.com/ddd-micro/internal/payment/domain"
+	"github.com/ddd-micro/internal/payment/infrastructure/monitoring"
 	"github.com/gin-gonic/gin"
 )
 
 // AdminHandler handles admin-related payment HTTP requests
 type AdminHandler struct {
 	paymentService *application.PaymentServiceCQRS
+	metrics        *monitoring.PrometheusMetrics
 }
 
 // NewAdminHandler creates a new admin handler
-func NewAdminHandler(paymentService *application.PaymentServiceCQRS) *AdminHandler {
+func NewAdminHandler(paymentService *application.PaymentServiceCQRS, metrics *monitoring.PrometheusMetrics) *AdminHandler {
 	return &AdminHandler{
 		paymentService: paymentService,
+		metrics:        metrics,
 	}
 }
 
@@ -111,6 +116,7 @@ func (h *AdminHandler) GetPaymentByID(c *gin.Context) {
 // @Failure 401 {object} map[string]string
 // @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /admin/payments/{id}/status [put]
 func (h *AdminHandler) UpdatePaymentStatus(c *gin.Context) {
@@ -122,9 +128,44 @@ func (h *AdminHandler) UpdatePaymentStatus(c *gin.Context) {
 		return
 	}
 
-	payment, err := h.paymentService.AdminUpdatePaymentStatus(c.Request.Context(), paymentID, req)
+	adminID := c.GetUint("user_id")
+	payment, err := h.paymentService.AdminUpdatePaymentStatus(c.Request.Context(), adminID, paymentID, req)
 	if err != nil {
-		if err.Error() == "payment not found" {
+		switch {
+		case errors.Is(err, domain.ErrPaymentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+		case errors.Is(err, domain.ErrInvalidStatus):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrInvalidStatusTransition):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, payment)
+}
+
+// GetPaymentHistory gets the status history of a payment (admin only)
+// @Summary Get payment status history
+// @Description Get every status transition of a payment with its actor and reason (admin only)
+// @Tags admin-payments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Payment ID"
+// @Success 200 {object} dto.PaymentStatusHistoryListResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/payments/{id}/history [get]
+func (h *AdminHandler) GetPaymentHistory(c *gin.Context) {
+	paymentID := c.Param("id")
+
+	history, err := h.paymentService.AdminGetPaymentHistory(c.Request.Context(), paymentID)
+	if err != nil {
+		if errors.Is(err, domain.ErrPaymentNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
 			return
 		}
@@ -132,6 +173,70 @@ func (h *AdminHandler) UpdatePaymentStatus(c *gin.Context) {
 		return
 	}
 
+	c.JSON(http.StatusOK, history)
+}
+
+// CapturePayment captures an authorized payment (admin only)
+// @Summary Capture payment (admin)
+// @Description Charge all or part of the authorized payment funds, e.g. at shipment (admin only)
+// @Tags admin-payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Payment ID"
+// @Param request body dto.CapturePaymentRequest false "Payment capture request"
+// @Success 200 {object} dto.PaymentResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/payments/{id}/capture [post]
+func (h *AdminHandler) CapturePayment(c *gin.Context) {
+	paymentID := c.Param("id")
+
+	var req dto.CapturePaymentRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	payment, err := h.paymentService.AdminCapturePayment(c.Request.Context(), c.GetUint("user_id"), paymentID, req)
+	if err != nil {
+		writeAuthorizationError(c, err)
+		return
+	}
+
+	h.metrics.RecordPaymentCompletion()
+	c.JSON(http.StatusOK, payment)
+}
+
+// VoidAuthorization voids a payment authorization (admin only)
+// @Summary Void payment authorization (admin)
+// @Description Release the hold on the authorized payment funds (admin only)
+// @Tags admin-payments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Payment ID"
+// @Success 200 {object} dto.PaymentResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/payments/{id}/void [post]
+func (h *AdminHandler) VoidAuthorization(c *gin.Context) {
+	paymentID := c.Param("id")
+
+	payment, err := h.paymentService.AdminVoidAuthorization(c.Request.Context(), c.GetUint("user_id"), paymentID)
+	if err != nil {
+		writeAuthorizationError(c, err)
+		return
+	}
+
+	h.metrics.RecordPaymentCancellation()
 	c.JSON(http.StatusOK, payment)
 }
 
@@ -147,6 +252,7 @@ func (h *AdminHandler) UpdatePaymentStatus(c *gin.Context) {
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /admin/refunds [post]
 func (h *AdminHandler) CreateRefund(c *gin.Context) {
@@ -158,10 +264,11 @@ func (h *AdminHandler) CreateRefund(c *gin.Context) {
 
 	refund, err := h.paymentService.CreateRefund(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeRefundError(c, err)
 		return
 	}
 
+	h.metrics.RecordRefundCreation()
 	c.JSON(http.StatusCreated, refund)
 }
 
@@ -246,20 +353,28 @@ func (h *AdminHandler) GetRefundByID(c *gin.Context) {
 // @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Failure 502 {object} map[string]string
 // @Router /admin/refunds/{id}/process [post]
 func (h *AdminHandler) ProcessRefund(c *gin.Context) {
 	refundID := c.Param("id")
 
-	refund, err := h.paymentService.ProcessRefund(c.Request.Context(), refundID)
+	h.metrics.RecordRefundProcessing()
+	refund, err := h.paymentService.ProcessRefund(c.Request.Context(), c.GetUint("user_id"), refundID)
 	if err != nil {
-		if err.Error() == "refund not found" {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Refund not found"})
-			return
+		if errors.Is(err, domain.ErrGatewayError) {
+			h.metrics.RecordRefundFailure()
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		writeRefundError(c, err)
 		return
 	}
 
+	switch refund.Status {
+	case domain.RefundStatusCompleted:
+		h.metrics.RecordRefundCompletion()
+	case domain.RefundStatusFailed:
+		h.metrics.RecordRefundFailure()
+	}
+
 	c.JSON(http.StatusOK, refund)
 }
 
@@ -281,9 +396,37 @@ func (h *AdminHandler) GetPaymentStats(c *gin.Context) {
 
 	stats, err := h.paymentService.GetPaymentStats(c.Request.Context(), period)
 	if err != nil {
+		if errors.Is(err, application.ErrInvalidStatsPeriod) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, stats)
 }
+
+// writeRefundError maps refund errors to HTTP responses
+func writeRefundError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrRefundNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Refund not found"})
+	case errors.Is(err, domain.ErrPaymentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+	case errors.Is(err, domain.ErrPaymentCannotBeRefunded),
+		errors.Is(err, domain.ErrRefundAmountExceedsPayment),
+		errors.Is(err, domain.ErrRefundAlreadyCompleted),
+		errors.Is(err, domain.ErrRefundAlreadyFailed),
+		errors.Is(err, domain.ErrInvalidStatus),
+		errors.Is(err, domain.ErrInvalidAmount),
+		errors.Is(err, domain.ErrInvalidRefundReason):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidStatusTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrGatewayError):
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ddd-micro/internal/payment/application"
+	"github.com/ddd-micro/internal/payment/application/dto"
+	"github.com/ddd-micro/internal/payment/domain"
+	"github.com/ddd-micro/internal/payment/infrastructure/monitoring"
+	"github.com/gin-gonic/gin"
+)
+
+// CheckoutHandler handles checkout-related HTTP requests
+type CheckoutHandler struct {
+	checkout *application.CheckoutOrchestrator
+	metrics  *monitoring.PrometheusMetrics
+}
+
+// NewCheckoutHandler creates a new checkout handler
+func NewCheckoutHandler(checkout *application.CheckoutOrchestrator, metrics *monitoring.PrometheusMetrics) *CheckoutHandler {
+	return &CheckoutHandler{
+		checkout: checkout,
+		metrics:  metrics,
+	}
+}
+
+// StartCheckout checks out the basket of the user
+// @Summary Check out the basket
+// @Description Validate the basket, reserve its stock and create its payment; the checkout then follows the payment
+// @Tags checkouts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CheckoutRequest true "Checkout request"
+// @Param Idempotency-Key header string false "Key that makes retries of this request safe"
+// @Success 201 {object} dto.CheckoutResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /checkouts [post]
+func (h *CheckoutHandler) StartCheckout(c *gin.Context) {
+	span, _ := monitoring.StartSpanFromGinContext(c, "checkout.start")
+	defer span.Finish()
+
+	userID := c.GetUint("user_id")
+
+	var req dto.CheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		monitoring.LogSpanError(span, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	checkout, err := h.checkout.Checkout(c.Request.Context(), userID, req)
+	if err != nil {
+		monitoring.LogSpanError(span, err)
+		h.metrics.RecordPaymentFailure()
+		writeCheckoutError(c, err)
+		return
+	}
+
+	h.metrics.RecordPaymentCreation()
+	monitoring.SetSpanTags(span, map[string]interface{}{
+		"checkout.id": checkout.ID,
+		"payment.id":  checkout.PaymentID,
+		"success":     true,
+	})
+
+	c.JSON(http.StatusCreated, checkout)
+}
+
+// GetCheckout gets the state of a checkout
+// @Summary Get checkout by ID
+// @Description Get the step and status of a checkout of the authenticated user
+// @Tags checkouts
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Checkout ID"
+// @Success 200 {object} dto.CheckoutResponse
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /checkouts/{id} [get]
+func (h *CheckoutHandler) GetCheckout(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	checkout, err := h.checkout.GetCheckout(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		writeCheckoutError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, checkout)
+}
+
+// writeCheckoutError maps checkout errors to HTTP responses
+func writeCheckoutError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrCheckoutNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Checkout not found"})
+	case errors.Is(err, domain.ErrInvalidAmount),
+		errors.Is(err, domain.ErrInvalidCurrency):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, application.ErrCheckoutRejected):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,138 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ddd-micro/internal/payment/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// Idempotency headers
+const (
+	idempotencyKeyHeader      = "Idempotency-Key"
+	idempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	idempotencyResponseFormat = "application/json; charset=utf-8"
+)
+
+// idempotencyResponseWriter captures the response body so it can be stored with the key
+type idempotencyResponseWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+// Write writes the response and keeps a copy of it
+func (w *idempotencyResponseWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString writes the response and keeps a copy of it
+func (w *idempotencyResponseWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware makes requests carrying an Idempotency-Key header safe to retry.
+// The key is scoped to the authenticated user, so it must run after AuthMiddleware.
+func IdempotencyMiddleware(repo domain.IdempotencyKeyRepository, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key header is too long"})
+			c.Abort()
+			return
+		}
+
+		body, err := c.GetRawData()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		record := &domain.IdempotencyKey{
+			UserID:      c.GetUint("user_id"),
+			Key:         key,
+			Fingerprint: requestFingerprint(c.Request.Method, c.Request.URL.Path, body),
+			Status:      domain.IdempotencyStatusInProgress,
+			ExpiresAt:   time.Now().Add(ttl),
+		}
+
+		existing, acquired, err := repo.Acquire(c.Request.Context(), record)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check idempotency key"})
+			c.Abort()
+			return
+		}
+
+		if !acquired {
+			switch {
+			case existing.Fingerprint != record.Fingerprint:
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key was already used with a different request"})
+			case !existing.IsCompleted():
+				c.JSON(http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key is still in progress"})
+			default:
+				c.Header(idempotentReplayedHeader, "true")
+				c.Data(existing.StatusCode, idempotencyResponseFormat, existing.ResponseBody)
+			}
+			c.Abort()
+			return
+		}
+
+		// Store the outcome even if the client has already gone away
+		ctx := context.WithoutCancel(c.Request.Context())
+
+		// Release the key unless the response is kept, including when a handler panics,
+		// so that the client can retry instead of getting a conflict until the key expires
+		keep := false
+		defer func() {
+			if keep {
+				return
+			}
+			if err := repo.Release(ctx, record.UserID, record.Key); err != nil {
+				log.Printf("Failed to release idempotency key %s: %v", record.Key, err)
+			}
+		}()
+
+		writer := &idempotencyResponseWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = writer
+
+		c.Next()
+
+		// Server errors are not cached so that the client can retry them
+		if writer.Status() >= http.StatusInternalServerError {
+			return
+		}
+
+		keep = true
+		record.Complete(writer.Status(), writer.body.Bytes())
+		if err := repo.Complete(ctx, record); err != nil {
+			log.Printf("Failed to store idempotent response for key %s: %v", record.Key, err)
+		}
+	}
+}
+
+// requestFingerprint identifies a request by its method, path and body
+func requestFingerprint(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method))
+	hash.Write([]byte(" "))
+	hash.Write([]byte(path))
+	hash.Write([]byte("\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
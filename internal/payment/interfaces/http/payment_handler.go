@@ -1,12 +1,14 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/ddd-micro/internal/payment/application"
 	"github.com/ddd-micro/internal/payment/application/dto"
+	"github.com/ddd-micro/internal/payment/domain"
 	"github.com/ddd-micro/internal/payment/infrastructure/monitoring"
 	"github.com/gin-gonic/gin"
 )
@@ -33,9 +35,12 @@ func NewPaymentHandler(paymentService *application.PaymentServiceCQRS, metrics *
 // @Produce json
 // @Security BearerAuth
 // @Param request body dto.CreatePaymentRequest true "Payment creation request"
+// @Param Idempotency-Key header string false "Key that makes retries of this request safe"
 // @Success 201 {object} dto.PaymentResponse
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /payments [post]
 func (h *PaymentHandler) CreatePayment(c *gin.Context) {
@@ -116,10 +121,13 @@ func (h *PaymentHandler) GetPayment(c *gin.Context) {
 // @Security BearerAuth
 // @Param id path string true "Payment ID"
 // @Param request body dto.ProcessPaymentRequest true "Payment processing request"
+// @Param Idempotency-Key header string false "Key that makes retries of this request safe"
 // @Success 200 {object} dto.PaymentResponse
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /payments/{id}/process [post]
 func (h *PaymentHandler) ProcessPayment(c *gin.Context) {
@@ -196,6 +204,120 @@ func (h *PaymentHandler) CancelPayment(c *gin.Context) {
 	c.JSON(http.StatusOK, payment)
 }
 
+// AuthorizePayment puts a hold on the payment funds
+// @Summary Authorize a payment
+// @Description Put a hold on the payment funds; they are charged on capture
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Payment ID"
+// @Param request body dto.AuthorizePaymentRequest true "Payment authorization request"
+// @Param Idempotency-Key header string false "Key that makes retries of this request safe"
+// @Success 200 {object} dto.PaymentResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /payments/{id}/authorize [post]
+func (h *PaymentHandler) AuthorizePayment(c *gin.Context) {
+	// Start tracing span
+	span, _ := monitoring.StartSpanFromGinContext(c, "payment.authorize")
+	defer span.Finish()
+
+	userID := c.GetUint("user_id")
+	paymentID := c.Param("id")
+
+	var req dto.AuthorizePaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		monitoring.LogSpanError(span, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	payment, err := h.paymentService.AuthorizePayment(c.Request.Context(), userID, paymentID, req)
+	if err != nil {
+		monitoring.LogSpanError(span, err)
+		h.metrics.RecordPaymentFailure()
+		writeAuthorizationError(c, err)
+		return
+	}
+
+	monitoring.SetSpanTags(span, map[string]interface{}{
+		"payment.id":     payment.ID,
+		"payment.status": payment.Status,
+		"success":        true,
+	})
+
+	c.JSON(http.StatusOK, payment)
+}
+
+// CapturePayment charges the authorized funds of a payment
+// @Summary Capture a payment
+// @Description Charge all or part of the authorized payment funds
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Payment ID"
+// @Param request body dto.CapturePaymentRequest false "Payment capture request"
+// @Success 200 {object} dto.PaymentResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /payments/{id}/capture [post]
+func (h *PaymentHandler) CapturePayment(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	paymentID := c.Param("id")
+
+	var req dto.CapturePaymentRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	payment, err := h.paymentService.CapturePayment(c.Request.Context(), userID, paymentID, req)
+	if err != nil {
+		writeAuthorizationError(c, err)
+		return
+	}
+
+	h.metrics.RecordPaymentCompletion()
+	c.JSON(http.StatusOK, payment)
+}
+
+// VoidAuthorization releases the authorized funds of a payment
+// @Summary Void a payment authorization
+// @Description Release the hold on the authorized payment funds
+// @Tags payments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Payment ID"
+// @Success 200 {object} dto.PaymentResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /payments/{id}/void [post]
+func (h *PaymentHandler) VoidAuthorization(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	paymentID := c.Param("id")
+
+	payment, err := h.paymentService.VoidAuthorization(c.Request.Context(), userID, paymentID)
+	if err != nil {
+		writeAuthorizationError(c, err)
+		return
+	}
+
+	h.metrics.RecordPaymentCancellation()
+	c.JSON(http.StatusOK, payment)
+}
+
 // ListPayments lists user's payments
 // @Summary List user payments
 // @Description Get a list of payments for the authenticated user
@@ -402,3 +524,22 @@ func (h *PaymentHandler) SetDefaultPaymentMethod(c *gin.Context) {
 
 	c.JSON(http.StatusOK, paymentMethod)
 }
+
+// writeAuthorizationError maps authorize, capture and void errors to HTTP responses
+func writeAuthorizationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrPaymentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+	case errors.Is(err, domain.ErrPaymentCannotBeAuthorized),
+		errors.Is(err, domain.ErrPaymentCannotBeCaptured),
+		errors.Is(err, domain.ErrPaymentCannotBeVoided),
+		errors.Is(err, domain.ErrAuthorizationExpired),
+		errors.Is(err, domain.ErrCaptureAmountExceedsHold),
+		errors.Is(err, domain.ErrInvalidAmount):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidStatusTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -2,7 +2,9 @@ package http
 
 import (
 	"github.com/ddd-micro/internal/payment/application"
+	"github.com/ddd-micro/internal/payment/domain"
 	"github.com/ddd-micro/internal/payment/infrastructure/client"
+	"github.com/ddd-micro/internal/payment/infrastructure/config"
 	"github.com/ddd-micro/internal/payment/infrastructure/monitoring"
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
@@ -29,7 +31,10 @@ func NewProviders(router *gin.Engine) *Providers {
 // NewRouter creates a new Gin router with all routes configured
 func NewRouter(
 	paymentService *application.PaymentServiceCQRS,
+	checkout *application.CheckoutOrchestrator,
 	userClient client.UserClient,
+	idempotencyKeyRepo domain.IdempotencyKeyRepository,
+	cfg *config.Config,
 	metrics *monitoring.PrometheusMetrics,
 	tracer *monitoring.JaegerTracer,
 ) *gin.Engine {
@@ -44,8 +49,11 @@ func NewRouter(
 	router.Use(gin.Recovery())
 	router.Use(CORSMiddleware())
 
+	// Idempotency-Key support for retried payment requests
+	idempotencyMiddleware := IdempotencyMiddleware(idempotencyKeyRepo, cfg.Idempotency.TTL)
+
 	// Setup routes
-	SetupRoutes(router, paymentService, userClient, metrics, tracer)
+	SetupRoutes(router, paymentService, checkout, userClient, idempotencyMiddleware, metrics, tracer)
 
 	return router
 }
@@ -55,7 +63,7 @@ func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Credentials", "true")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, Idempotency-Key, accept, origin, Cache-Control, X-Requested-With")
 		c.Header("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
@@ -14,7 +14,9 @@ import (
 func SetupRoutes(
 	router *gin.Engine,
 	paymentService *application.PaymentServiceCQRS,
+	checkout *application.CheckoutOrchestrator,
 	userClient client.UserClient,
+	idempotencyMiddleware gin.HandlerFunc,
 	metrics *monitoring.PrometheusMetrics,
 	tracer *monitoring.JaegerTracer,
 ) {
@@ -30,12 +32,13 @@ func SetupRoutes(
 
 	// Initialize handlers
 	paymentHandler := NewPaymentHandler(paymentService, metrics)
-	adminHandler := NewAdminHandler(paymentService)
+	adminHandler := NewAdminHandler(paymentService, metrics)
+	webhookHandler := NewWebhookHandler(paymentService, metrics)
+	checkoutHandler := NewCheckoutHandler(checkout, metrics)
 
 	// Initialize middleware
 	authMiddleware := AuthMiddleware(userClient)
 	adminOnlyMiddleware := AdminOnlyMiddleware()
-	userOrAdminMiddleware := UserOrAdminMiddleware()
 
 	// Public routes (no authentication required)
 	public := router.Group("/api/v1")
@@ -44,6 +47,9 @@ func SetupRoutes(
 		public.GET("/health", func(c *gin.Context) {
 			c.JSON(200, gin.H{"status": "ok", "service": "payment-service"})
 		})
+
+		// Payment gateway webhooks (authenticated by signature)
+		public.POST("/webhooks/:provider", webhookHandler.HandleWebhook) // POST /api/v1/webhooks/:provider
 	}
 
 	// User routes (authentication required)
@@ -53,11 +59,21 @@ func SetupRoutes(
 		// Payment routes
 		payments := user.Group("/payments")
 		{
-			payments.POST("", paymentHandler.CreatePayment)              // POST /api/v1/payments
-			payments.GET("", paymentHandler.ListPayments)                // GET /api/v1/payments
-			payments.GET("/:id", paymentHandler.GetPayment)              // GET /api/v1/payments/:id
-			payments.POST("/:id/process", paymentHandler.ProcessPayment) // POST /api/v1/payments/:id/process
-			payments.POST("/:id/cancel", paymentHandler.CancelPayment)   // POST /api/v1/payments/:id/cancel
+			payments.POST("", idempotencyMiddleware, paymentHandler.CreatePayment)                  // POST /api/v1/payments
+			payments.GET("", paymentHandler.ListPayments)                                           // GET /api/v1/payments
+			payments.GET("/:id", paymentHandler.GetPayment)                                         // GET /api/v1/payments/:id
+			payments.POST("/:id/process", idempotencyMiddleware, paymentHandler.ProcessPayment)     // POST /api/v1/payments/:id/process
+			payments.POST("/:id/cancel", paymentHandler.CancelPayment)                              // POST /api/v1/payments/:id/cancel
+			payments.POST("/:id/authorize", idempotencyMiddleware, paymentHandler.AuthorizePayment) // POST /api/v1/payments/:id/authorize
+			payments.POST("/:id/capture", paymentHandler.CapturePayment)                            // POST /api/v1/payments/:id/capture
+			payments.POST("/:id/void", paymentHandler.VoidAuthorization)                            // POST /api/v1/payments/:id/void
+		}
+
+		// Checkout routes
+		checkouts := user.Group("/checkouts")
+		{
+			checkouts.POST("", idempotencyMiddleware, checkoutHandler.StartCheckout) // POST /api/v1/checkouts
+			checkouts.GET("/:id", checkoutHandler.GetCheckout)                       // GET /api/v1/checkouts/:id
 		}
 
 		// Payment method routes
@@ -82,6 +98,9 @@ func SetupRoutes(
 			adminPayments.GET("", adminHandler.ListAllPayments)                // GET /api/v1/admin/payments
 			adminPayments.GET("/:id", adminHandler.GetPaymentByID)             // GET /api/v1/admin/payments/:id
 			adminPayments.PUT("/:id/status", adminHandler.UpdatePaymentStatus) // PUT /api/v1/admin/payments/:id/status
+			adminPayments.POST("/:id/capture", adminHandler.CapturePayment)    // POST /api/v1/admin/payments/:id/capture
+			adminPayments.POST("/:id/void", adminHandler.VoidAuthorization)    // POST /api/v1/admin/payments/:id/void
+			adminPayments.GET("/:id/history", adminHandler.GetPaymentHistory)  // GET /api/v1/admin/payments/:id/history
 		}
 
 		// Admin refund routes
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ddd-micro/internal/payment/application"
+	"github.com/ddd-micro/internal/payment/domain"
+	"github.com/ddd-micro/internal/payment/infrastructure/monitoring"
+	"github.com/gin-gonic/gin"
+)
+
+// Signature headers sent by payment providers
+const (
+	stripeSignatureHeader  = "Stripe-Signature"
+	webhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// WebhookHandler handles payment gateway webhook callbacks
+type WebhookHandler struct {
+	paymentService *application.PaymentServiceCQRS
+	metrics        *monitoring.PrometheusMetrics
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(paymentService *application.PaymentServiceCQRS, metrics *monitoring.PrometheusMetrics) *WebhookHandler {
+	return &WebhookHandler{
+		paymentService: paymentService,
+		metrics:        metrics,
+	}
+}
+
+// HandleWebhook processes a payment gateway webhook
+// @Summary Receive payment gateway webhook
+// @Description Receive and apply a signed webhook event from a payment provider
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param provider path string true "Payment provider" Enums(stripe, mock)
+// @Param Stripe-Signature header string true "Webhook signature"
+// @Success 200 {object} dto.WebhookResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /webhooks/{provider} [post]
+func (h *WebhookHandler) HandleWebhook(c *gin.Context) {
+	// Start tracing span
+	span, _ := monitoring.StartSpanFromGinContext(c, "payment.webhook")
+	defer span.Finish()
+
+	provider := c.Param("provider")
+
+	payload, err := c.GetRawData()
+	if err != nil {
+		monitoring.LogSpanError(span, err)
+		h.metrics.RecordWebhookEvent(provider, "error")
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+		return
+	}
+
+	signature := c.GetHeader(stripeSignatureHeader)
+	if signature == "" {
+		signature = c.GetHeader(webhookSignatureHeader)
+	}
+
+	resp, err := h.paymentService.ProcessWebhook(c.Request.Context(), provider, payload, signature)
+	if err != nil {
+		monitoring.LogSpanError(span, err)
+		h.metrics.RecordWebhookEvent(provider, "error")
+		switch {
+		case errors.Is(err, domain.ErrUnsupportedProvider):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrInvalidWebhookSignature), errors.Is(err, domain.ErrInvalidWebhookPayload):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	h.metrics.RecordWebhookEvent(provider, "success")
+	for i := 0; i < resp.RefundsCompleted; i++ {
+		h.metrics.RecordRefundCompletion()
+	}
+	monitoring.SetSpanTags(span, map[string]interface{}{
+		"webhook.provider": provider,
+		"success":          true,
+	})
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,329 @@
+package application
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ddd-micro/internal/product/domain"
+	"github.com/ddd-micro/pkg/gormtx"
+)
+
+// CategoryService manages the category tree products are organised in
+type CategoryService struct {
+	categoryRepo domain.CategoryRepository
+	transactor   *gormtx.Transactor
+}
+
+// NewCategoryService creates a new category service
+func NewCategoryService(categoryRepo domain.CategoryRepository, transactor *gormtx.Transactor) *CategoryService {
+	return &CategoryService{
+		categoryRepo: categoryRepo,
+		transactor:   transactor,
+	}
+}
+
+// CreateCategory creates a new active category below its parent, or at the root, after
+// its siblings
+func (s *CategoryService) CreateCategory(ctx context.Context, req CreateCategoryRequest) (*CategoryResponse, error) {
+	slug := strings.TrimSpace(req.Slug)
+	if slug == "" {
+		slug = domain.Slugify(req.Name)
+	}
+
+	category := &domain.Category{
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		Slug:        slug,
+		ParentID:    req.ParentID,
+		Image:       req.Image,
+		Icon:        req.Icon,
+		SortOrder:   req.SortOrder,
+		IsActive:    true,
+	}
+
+	if err := category.ValidateCategory(); err != nil {
+		return nil, err
+	}
+
+	err := s.transactor.Within(ctx, func(ctx context.Context) error {
+		if err := s.ensureSlugFree(ctx, category.Slug, 0); err != nil {
+			return err
+		}
+		return s.categoryRepo.Create(ctx, category)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toCategoryResponse(category), nil
+}
+
+// GetCategory retrieves a category by ID with its subtree
+func (s *CategoryService) GetCategory(ctx context.Context, id uint, includeInactive bool) (*CategoryResponse, error) {
+	category, err := s.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.subtree(ctx, category, includeInactive)
+}
+
+// GetCategoryBySlug retrieves a category by slug with its subtree
+func (s *CategoryService) GetCategoryBySlug(ctx context.Context, slug string, includeInactive bool) (*CategoryResponse, error) {
+	category, err := s.categoryRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.subtree(ctx, category, includeInactive)
+}
+
+// GetCategoryTree retrieves the whole category tree. Without inactive categories, the
+// subtrees below an inactive category are left out as well.
+func (s *CategoryService) GetCategoryTree(ctx context.Context, includeInactive bool) (*CategoryTreeResponse, error) {
+	categories, err := s.categoryRepo.ListTree(ctx, nil, includeInactive)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CategoryTreeResponse{
+		Categories: buildCategoryTree(categories, nil),
+	}, nil
+}
+
+// UpdateCategory updates a category; its place in the tree is changed with MoveCategory
+func (s *CategoryService) UpdateCategory(ctx context.Context, id uint, req UpdateCategoryRequest) (*CategoryResponse, error) {
+	var category *domain.Category
+
+	err := s.transactor.Within(ctx, func(ctx context.Context) error {
+		var err error
+		category, err = s.categoryRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if req.Name != nil {
+			category.Name = strings.TrimSpace(*req.Name)
+		}
+		if req.Description != nil {
+			category.Description = *req.Description
+		}
+		if req.Slug != nil && *req.Slug != category.Slug {
+			if err := s.ensureSlugFree(ctx, *req.Slug, id); err != nil {
+				return err
+			}
+			category.Slug = *req.Slug
+		}
+		if req.Image != nil {
+			category.Image = *req.Image
+		}
+		if req.Icon != nil {
+			category.Icon = *req.Icon
+		}
+		if req.SortOrder != nil {
+			category.SetSortOrder(*req.SortOrder)
+		}
+		if req.IsActive != nil {
+			if *req.IsActive {
+				category.Activate()
+			} else {
+				category.Deactivate()
+			}
+		}
+
+		if err := category.ValidateCategory(); err != nil {
+			return err
+		}
+
+		return s.categoryRepo.Update(ctx, category)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toCategoryResponse(category), nil
+}
+
+// DeleteCategory soft deletes a category without child categories or products
+func (s *CategoryService) DeleteCategory(ctx context.Context, id uint) error {
+	return s.transactor.Within(ctx, func(ctx context.Context) error {
+		children, err := s.categoryRepo.ListChildren(ctx, &id)
+		if err != nil {
+			return err
+		}
+		if len(children) > 0 {
+			return domain.ErrCategoryNotEmpty
+		}
+
+		hasProducts, err := s.categoryRepo.HasProducts(ctx, id)
+		if err != nil {
+			return err
+		}
+		if hasProducts {
+			return domain.ErrCategoryNotEmpty
+		}
+
+		return s.categoryRepo.Delete(ctx, id)
+	})
+}
+
+// MoveCategory places a category with its subtree below a new parent, or at the root, and
+// at a position among its new siblings, renumbering their sort order
+func (s *CategoryService) MoveCategory(ctx context.Context, id uint, req MoveCategoryRequest) (*CategoryResponse, error) {
+	var category *domain.Category
+
+	err := s.transactor.Within(ctx, func(ctx context.Context) error {
+		var err error
+		category, err = s.categoryRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		var parent *domain.Category
+		if req.ParentID != nil {
+			parent, err = s.categoryRepo.GetByID(ctx, *req.ParentID)
+			if err != nil {
+				return err
+			}
+		}
+		if err := category.CanMoveTo(parent); err != nil {
+			return err
+		}
+
+		reparented := !sameParent(category.ParentID, req.ParentID)
+		if reparented {
+			if err := s.categoryRepo.Move(ctx, category, parent); err != nil {
+				return err
+			}
+		}
+
+		if !reparented && req.Position == nil {
+			return nil
+		}
+		return s.reorder(ctx, category, req.Position)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toCategoryResponse(category), nil
+}
+
+// reorder places a category at a position among its siblings, at the end when position is
+// nil, and numbers the siblings' sort order from zero
+func (s *CategoryService) reorder(ctx context.Context, category *domain.Category, position *int) error {
+	siblings, err := s.categoryRepo.ListChildren(ctx, category.ParentID)
+	if err != nil {
+		return err
+	}
+
+	ordered := make([]*domain.Category, 0, len(siblings))
+	for _, sibling := range siblings {
+		if sibling.ID != category.ID {
+			ordered = append(ordered, sibling)
+		}
+	}
+
+	index := len(ordered)
+	if position != nil && *position < index {
+		index = *position
+	}
+	ordered = append(ordered[:index], append([]*domain.Category{category}, ordered[index:]...)...)
+
+	orders := make(map[uint]int)
+	for i, sibling := range ordered {
+		if sibling.SortOrder != i || sibling.ID == category.ID {
+			orders[sibling.ID] = i
+		}
+	}
+	category.SetSortOrder(index)
+
+	return s.categoryRepo.UpdateSortOrders(ctx, orders)
+}
+
+// ensureSlugFree fails with ErrCategorySlugTaken when another category uses the slug
+func (s *CategoryService) ensureSlugFree(ctx context.Context, slug string, excludeID uint) error {
+	exists, err := s.categoryRepo.SlugExists(ctx, slug, excludeID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return domain.ErrCategorySlugTaken
+	}
+	return nil
+}
+
+// subtree loads the descendants of a category into its response
+func (s *CategoryService) subtree(ctx context.Context, category *domain.Category, includeInactive bool) (*CategoryResponse, error) {
+	if !includeInactive && !category.IsActive {
+		return nil, domain.ErrCategoryNotFound
+	}
+
+	categories, err := s.categoryRepo.ListTree(ctx, category, includeInactive)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := buildCategoryTree(categories, category)
+	if len(tree) == 0 {
+		return nil, domain.ErrCategoryNotFound
+	}
+	return &tree[0], nil
+}
+
+// sameParent checks if two parent IDs name the same parent, nil being the root
+func sameParent(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// buildCategoryTree nests categories ordered by depth below their parents, starting from
+// root, or from the root categories when root is nil. Categories whose parent is missing
+// are left out with their subtree.
+func buildCategoryTree(categories []*domain.Category, root *domain.Category) []CategoryResponse {
+	children := make(map[uint][]*domain.Category)
+	var top []*domain.Category
+	for _, category := range categories {
+		switch {
+		case root != nil && category.ID == root.ID, root == nil && category.IsRootCategory():
+			top = append(top, category)
+		case category.ParentID != nil:
+			children[*category.ParentID] = append(children[*category.ParentID], category)
+		}
+	}
+
+	var build func(category *domain.Category) CategoryResponse
+	build = func(category *domain.Category) CategoryResponse {
+		resp := *toCategoryResponse(category)
+		for _, child := range children[category.ID] {
+			resp.Children = append(resp.Children, build(child))
+		}
+		return resp
+	}
+
+	tree := make([]CategoryResponse, 0, len(top))
+	for _, category := range top {
+		tree = append(tree, build(category))
+	}
+	return tree
+}
+
+// toCategoryResponse converts domain.Category to CategoryResponse without its children
+func toCategoryResponse(category *domain.Category) *CategoryResponse {
+	return &CategoryResponse{
+		ID:          category.ID,
+		Name:        category.Name,
+		Description: category.Description,
+		Slug:        category.Slug,
+		ParentID:    category.ParentID,
+		Depth:       category.Depth,
+		Image:       category.Image,
+		Icon:        category.Icon,
+		SortOrder:   category.SortOrder,
+		IsActive:    category.IsActive,
+		CreatedAt:   category.CreatedAt,
+		UpdatedAt:   category.UpdatedAt,
+	}
+}
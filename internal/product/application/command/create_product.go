@@ -14,11 +14,13 @@ type CreateProductCommand struct {
 	Price            float64 `json:"price"`
 	ComparePrice     float64 `json:"compare_price"`
 	CostPrice        float64 `json:"cost_price"`
+	Currency         string  `json:"currency"`
 	Stock            int     `json:"stock"`
 	MinStock         int     `json:"min_stock"`
 	MaxStock         int     `json:"max_stock"`
 	Category         string  `json:"category"`
 	SubCategory      string  `json:"sub_category"`
+	CategoryID       *uint   `json:"category_id"`
 	Brand            string  `json:"brand"`
 	SKU              string  `json:"sku"`
 	Barcode          string  `json:"barcode"`
@@ -33,17 +35,20 @@ type CreateProductCommand struct {
 	IsFeatured       bool    `json:"is_featured"`
 	IsOnSale         bool    `json:"is_on_sale"`
 	SortOrder        int     `json:"sort_order"`
+	Actor            string  `json:"-"`
 }
 
 // CreateProductHandler handles the create product command
 type CreateProductHandler struct {
-	repo domain.ProductRepository
+	repo         domain.ProductRepository
+	categoryRepo domain.CategoryRepository
 }
 
 // NewCreateProductHandler creates a new create product handler
-func NewCreateProductHandler(repo domain.ProductRepository) *CreateProductHandler {
+func NewCreateProductHandler(repo domain.ProductRepository, categoryRepo domain.CategoryRepository) *CreateProductHandler {
 	return &CreateProductHandler{
-		repo: repo,
+		repo:         repo,
+		categoryRepo: categoryRepo,
 	}
 }
 
@@ -54,10 +59,6 @@ func (h *CreateProductHandler) Handle(ctx context.Context, cmd CreateProductComm
 		Name:             cmd.Name,
 		Description:      cmd.Description,
 		ShortDescription: cmd.ShortDescription,
-		Price:            cmd.Price,
-		ComparePrice:     cmd.ComparePrice,
-		CostPrice:        cmd.CostPrice,
-		Stock:            cmd.Stock,
 		MinStock:         cmd.MinStock,
 		MaxStock:         cmd.MaxStock,
 		Category:         cmd.Category,
@@ -79,6 +80,28 @@ func (h *CreateProductHandler) Handle(ctx context.Context, cmd CreateProductComm
 		IsActive:         true,
 	}
 
+	// Link the product to the category tree
+	if cmd.CategoryID != nil {
+		category, err := h.categoryRepo.GetByID(ctx, *cmd.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		product.AssignCategory(category)
+	}
+
+	// Convert prices to minor units of the currency
+	if err := product.ApplyPrices(cmd.Currency, &cmd.Price, &cmd.ComparePrice, &cmd.CostPrice); err != nil {
+		return nil, err
+	}
+
+	// Record the initial stock in the stock ledger, kept in the first warehouse by priority
+	if err := h.repo.LoadStockLevels(ctx, product); err != nil {
+		return nil, err
+	}
+	if err := product.SetStock(cmd.Stock, domain.StockChange{Reason: domain.StockMovementManual, Actor: cmd.Actor}); err != nil {
+		return nil, err
+	}
+
 	// Validate product
 	if err := product.ValidateProduct(); err != nil {
 		return nil, err
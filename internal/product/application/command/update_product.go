@@ -15,11 +15,13 @@ type UpdateProductCommand struct {
 	Price            *float64 `json:"price"`
 	ComparePrice     *float64 `json:"compare_price"`
 	CostPrice        *float64 `json:"cost_price"`
+	Currency         *string  `json:"currency"`
 	Stock            *int     `json:"stock"`
 	MinStock         *int     `json:"min_stock"`
 	MaxStock         *int     `json:"max_stock"`
 	Category         *string  `json:"category"`
 	SubCategory      *string  `json:"sub_category"`
+	CategoryID       *uint    `json:"category_id"` // 0 unlinks the product from the category tree
 	Brand            *string  `json:"brand"`
 	Barcode          *string  `json:"barcode"`
 	Weight           *float64 `json:"weight"`
@@ -34,17 +36,20 @@ type UpdateProductCommand struct {
 	IsFeatured       *bool    `json:"is_featured"`
 	IsOnSale         *bool    `json:"is_on_sale"`
 	SortOrder        *int     `json:"sort_order"`
+	Actor            string   `json:"-"`
 }
 
 // UpdateProductHandler handles the update product command
 type UpdateProductHandler struct {
-	repo domain.ProductRepository
+	repo         domain.ProductRepository
+	categoryRepo domain.CategoryRepository
 }
 
 // NewUpdateProductHandler creates a new update product handler
-func NewUpdateProductHandler(repo domain.ProductRepository) *UpdateProductHandler {
+func NewUpdateProductHandler(repo domain.ProductRepository, categoryRepo domain.CategoryRepository) *UpdateProductHandler {
 	return &UpdateProductHandler{
-		repo: repo,
+		repo:         repo,
+		categoryRepo: categoryRepo,
 	}
 }
 
@@ -66,17 +71,19 @@ func (h *UpdateProductHandler) Handle(ctx context.Context, cmd UpdateProductComm
 	if cmd.ShortDescription != nil {
 		product.ShortDescription = *cmd.ShortDescription
 	}
-	if cmd.Price != nil {
-		product.Price = *cmd.Price
-	}
-	if cmd.ComparePrice != nil {
-		product.ComparePrice = *cmd.ComparePrice
-	}
-	if cmd.CostPrice != nil {
-		product.CostPrice = *cmd.CostPrice
+	if cmd.Price != nil || cmd.ComparePrice != nil || cmd.CostPrice != nil || cmd.Currency != nil {
+		currency := ""
+		if cmd.Currency != nil {
+			currency = *cmd.Currency
+		}
+		if err := product.ApplyPrices(currency, cmd.Price, cmd.ComparePrice, cmd.CostPrice); err != nil {
+			return nil, err
+		}
 	}
 	if cmd.Stock != nil {
-		product.Stock = *cmd.Stock
+		if err := product.SetStock(*cmd.Stock, domain.StockChange{Reason: domain.StockMovementManual, Actor: cmd.Actor}); err != nil {
+			return nil, err
+		}
 	}
 	if cmd.MinStock != nil {
 		product.MinStock = *cmd.MinStock
@@ -90,6 +97,17 @@ func (h *UpdateProductHandler) Handle(ctx context.Context, cmd UpdateProductComm
 	if cmd.SubCategory != nil {
 		product.SubCategory = *cmd.SubCategory
 	}
+	if cmd.CategoryID != nil {
+		if *cmd.CategoryID == 0 {
+			product.AssignCategory(nil)
+		} else {
+			category, err := h.categoryRepo.GetByID(ctx, *cmd.CategoryID)
+			if err != nil {
+				return nil, err
+			}
+			product.AssignCategory(category)
+		}
+	}
 	if cmd.Brand != nil {
 		product.Brand = *cmd.Brand
 	}
@@ -8,8 +8,10 @@ import (
 
 // UpdateStockCommand represents the command to update product stock
 type UpdateStockCommand struct {
-	ProductID uint `json:"product_id"`
-	Stock     int  `json:"stock"`
+	ProductID uint   `json:"product_id"`
+	VariantID *uint  `json:"variant_id"` // Variant to count; nil for the product itself
+	Stock     int    `json:"stock"`
+	Actor     string `json:"-"`
 }
 
 // UpdateStockHandler handles the update stock command
@@ -24,15 +26,31 @@ func NewUpdateStockHandler(repo domain.ProductRepository) *UpdateStockHandler {
 	}
 }
 
-// Handle executes the update stock command
+// Handle executes the update stock command, recording the difference to the counted
+// stock as a manual stock movement. A count ships nothing, so it is allocated by priority.
 func (h *UpdateStockHandler) Handle(ctx context.Context, cmd UpdateStockCommand) error {
-	return h.repo.UpdateStock(ctx, cmd.ProductID, cmd.Stock)
+	product, err := h.repo.GetByID(ctx, cmd.ProductID)
+	if err != nil {
+		return err
+	}
+
+	change := domain.StockChange{VariantID: cmd.VariantID, Reason: domain.StockMovementManual, Actor: cmd.Actor}
+	if err := product.SetStock(cmd.Stock, change); err != nil {
+		return err
+	}
+
+	return h.repo.Update(ctx, product)
 }
 
 // ReduceStockCommand represents the command to reduce product stock
 type ReduceStockCommand struct {
-	ProductID uint `json:"product_id"`
-	Amount    int  `json:"amount"`
+	ProductID   uint                      `json:"product_id"`
+	VariantID   *uint                     `json:"variant_id"` // Variant to take from; nil for the product itself
+	Amount      int                       `json:"amount"`
+	WarehouseID *uint                     `json:"warehouse_id"` // Only warehouse to take from; nil to allocate
+	Destination *domain.GeoPoint          `json:"destination"`  // Where the stock goes, for nearest-first allocation
+	Actor       string                    `json:"-"`
+	Strategy    domain.AllocationStrategy `json:"-"`
 }
 
 // ReduceStockHandler handles the reduce stock command
@@ -47,14 +65,23 @@ func NewReduceStockHandler(repo domain.ProductRepository) *ReduceStockHandler {
 	}
 }
 
-// Handle executes the reduce stock command
+// Handle executes the reduce stock command, taking the stock from the warehouses in
+// allocation order
 func (h *ReduceStockHandler) Handle(ctx context.Context, cmd ReduceStockCommand) error {
 	product, err := h.repo.GetByID(ctx, cmd.ProductID)
 	if err != nil {
 		return err
 	}
 
-	if err := product.ReduceStock(cmd.Amount); err != nil {
+	change := domain.StockChange{
+		VariantID:   cmd.VariantID,
+		Reason:      domain.StockMovementManual,
+		Actor:       cmd.Actor,
+		WarehouseID: cmd.WarehouseID,
+		Strategy:    cmd.Strategy,
+		Destination: cmd.Destination,
+	}
+	if err := product.ReduceStock(cmd.Amount, change); err != nil {
 		return err
 	}
 
@@ -63,8 +90,11 @@ func (h *ReduceStockHandler) Handle(ctx context.Context, cmd ReduceStockCommand)
 
 // IncreaseStockCommand represents the command to increase product stock
 type IncreaseStockCommand struct {
-	ProductID uint `json:"product_id"`
-	Amount    int  `json:"amount"`
+	ProductID   uint   `json:"product_id"`
+	VariantID   *uint  `json:"variant_id"` // Variant to put into; nil for the product itself
+	Amount      int    `json:"amount"`
+	WarehouseID *uint  `json:"warehouse_id"` // Warehouse to put the stock into; nil for the first by priority
+	Actor       string `json:"-"`
 }
 
 // IncreaseStockHandler handles the increase stock command
@@ -86,7 +116,8 @@ func (h *IncreaseStockHandler) Handle(ctx context.Context, cmd IncreaseStockComm
 		return err
 	}
 
-	if err := product.IncreaseStock(cmd.Amount); err != nil {
+	change := domain.StockChange{VariantID: cmd.VariantID, Reason: domain.StockMovementManual, Actor: cmd.Actor, WarehouseID: cmd.WarehouseID}
+	if err := product.IncreaseStock(cmd.Amount, change); err != nil {
 		return err
 	}
 
@@ -14,11 +14,13 @@ type CreateProductRequest struct {
 	Price            float64 `json:"price" binding:"required,min=0"`
 	ComparePrice     float64 `json:"compare_price"`
 	CostPrice        float64 `json:"cost_price"`
+	Currency         string  `json:"currency" binding:"omitempty,len=3"`
 	Stock            int     `json:"stock" binding:"min=0"`
 	MinStock         int     `json:"min_stock"`
 	MaxStock         int     `json:"max_stock"`
 	Category         string  `json:"category"`
 	SubCategory      string  `json:"sub_category"`
+	CategoryID       *uint   `json:"category_id"`
 	Brand            string  `json:"brand"`
 	SKU              string  `json:"sku" binding:"required"`
 	Barcode          string  `json:"barcode"`
@@ -43,11 +45,13 @@ type UpdateProductRequest struct {
 	Price            *float64 `json:"price"`
 	ComparePrice     *float64 `json:"compare_price"`
 	CostPrice        *float64 `json:"cost_price"`
+	Currency         *string  `json:"currency" binding:"omitempty,len=3"`
 	Stock            *int     `json:"stock"`
 	MinStock         *int     `json:"min_stock"`
 	MaxStock         *int     `json:"max_stock"`
 	Category         *string  `json:"category"`
 	SubCategory      *string  `json:"sub_category"`
+	CategoryID       *uint    `json:"category_id"` // 0 unlinks the product from the category tree
 	Brand            *string  `json:"brand"`
 	Barcode          *string  `json:"barcode"`
 	Weight           *float64 `json:"weight"`
@@ -69,38 +73,55 @@ type UpdateStockRequest struct {
 	Stock int `json:"stock" binding:"required,min=0"`
 }
 
+// StockTarget picks the stock and warehouses a stock change applies to
+type StockTarget struct {
+	VariantID   *uint    `json:"variant_id"`   // Variant whose stock changes; nil for the product itself
+	WarehouseID *uint    `json:"warehouse_id"` // Only warehouse to use; nil to allocate
+	Latitude    *float64 `json:"latitude"`     // Destination of taken stock, for nearest-first allocation
+	Longitude   *float64 `json:"longitude"`
+}
+
 // ProductResponse represents the product response
 type ProductResponse struct {
-	ID               uint      `json:"id"`
-	Name             string    `json:"name"`
-	Description      string    `json:"description"`
-	ShortDescription string    `json:"short_description"`
-	Price            float64   `json:"price"`
-	ComparePrice     float64   `json:"compare_price"`
-	CostPrice        float64   `json:"cost_price"`
-	Stock            int       `json:"stock"`
-	MinStock         int       `json:"min_stock"`
-	MaxStock         int       `json:"max_stock"`
-	Category         string    `json:"category"`
-	SubCategory      string    `json:"sub_category"`
-	Brand            string    `json:"brand"`
-	SKU              string    `json:"sku"`
-	Barcode          string    `json:"barcode"`
-	Weight           float64   `json:"weight"`
-	Dimensions       string    `json:"dimensions"`
-	Color            string    `json:"color"`
-	Size             string    `json:"size"`
-	Material         string    `json:"material"`
-	Tags             string    `json:"tags"`
-	Images           string    `json:"images"`
-	IsActive         bool      `json:"is_active"`
-	IsDigital        bool      `json:"is_digital"`
-	IsFeatured       bool      `json:"is_featured"`
-	IsOnSale         bool      `json:"is_on_sale"`
-	SortOrder        int       `json:"sort_order"`
-	ViewCount        int       `json:"view_count"`
-	CreatedAt        time.Time `json:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at"`
+	ID                uint                 `json:"id"`
+	Name              string               `json:"name"`
+	Description       string               `json:"description"`
+	ShortDescription  string               `json:"short_description"`
+	Price             float64              `json:"price"`
+	ComparePrice      float64              `json:"compare_price"`
+	CostPrice         float64              `json:"cost_price"`
+	PriceMinor        int64                `json:"price_minor"`
+	ComparePriceMinor int64                `json:"compare_price_minor"`
+	CostPriceMinor    int64                `json:"cost_price_minor"`
+	Currency          string               `json:"currency"`
+	Stock             int                  `json:"stock"`
+	ReservedStock     int                  `json:"reserved_stock"`
+	AvailableStock    int                  `json:"available_stock"` // Stock less reserved stock
+	MinStock          int                  `json:"min_stock"`
+	MaxStock          int                  `json:"max_stock"`
+	Category          string               `json:"category"`
+	SubCategory       string               `json:"sub_category"`
+	CategoryID        *uint                `json:"category_id"`
+	Brand             string               `json:"brand"`
+	SKU               string               `json:"sku"`
+	Barcode           string               `json:"barcode"`
+	Weight            float64              `json:"weight"`
+	Dimensions        string               `json:"dimensions"`
+	Color             string               `json:"color"`
+	Size              string               `json:"size"`
+	Material          string               `json:"material"`
+	Tags              string               `json:"tags"`
+	Images            string               `json:"images"`
+	IsActive          bool                 `json:"is_active"`
+	IsDigital         bool                 `json:"is_digital"`
+	IsFeatured        bool                 `json:"is_featured"`
+	IsOnSale          bool                 `json:"is_on_sale"`
+	SortOrder         int                  `json:"sort_order"`
+	ViewCount         int                  `json:"view_count"`
+	CreatedAt         time.Time            `json:"created_at"`
+	UpdatedAt         time.Time            `json:"updated_at"`
+	StockLevels       []StockLevelResponse `json:"stock_levels,omitempty"` // Per warehouse, when a single product is retrieved
+	Variants          []VariantResponse    `json:"variants,omitempty"`
 }
 
 // ListProductsResponse represents the paginated list of products
@@ -132,7 +153,7 @@ type SearchProductsRequest struct {
 type CreateCategoryRequest struct {
 	Name        string `json:"name" binding:"required"`
 	Description string `json:"description"`
-	Slug        string `json:"slug" binding:"required"`
+	Slug        string `json:"slug"` // Derived from the name when empty
 	ParentID    *uint  `json:"parent_id"`
 	Image       string `json:"image"`
 	Icon        string `json:"icon"`
@@ -144,7 +165,6 @@ type UpdateCategoryRequest struct {
 	Name        *string `json:"name"`
 	Description *string `json:"description"`
 	Slug        *string `json:"slug"`
-	ParentID    *uint   `json:"parent_id"`
 	Image       *string `json:"image"`
 	Icon        *string `json:"icon"`
 	SortOrder   *int    `json:"sort_order"`
@@ -158,6 +178,7 @@ type CategoryResponse struct {
 	Description string             `json:"description"`
 	Slug        string             `json:"slug"`
 	ParentID    *uint              `json:"parent_id"`
+	Depth       int                `json:"depth"`
 	Parent      *CategoryResponse  `json:"parent,omitempty"`
 	Children    []CategoryResponse `json:"children,omitempty"`
 	Image       string             `json:"image"`
@@ -176,16 +197,26 @@ type ListCategoriesResponse struct {
 	Limit      int                `json:"limit"`
 }
 
+// MoveCategoryRequest represents the request to move a category within the tree
+type MoveCategoryRequest struct {
+	ParentID *uint `json:"parent_id"`                          // New parent; nil for the root
+	Position *int  `json:"position" binding:"omitempty,min=0"` // Index among the siblings; nil keeps the order, or appends when the parent changes
+}
+
+// CategoryTreeResponse represents the category tree from its root categories
+type CategoryTreeResponse struct {
+	Categories []CategoryResponse `json:"categories"`
+}
+
 // ========== VARIANT DTOs ==========
 
 // CreateVariantRequest represents the request to create a new product variant
 type CreateVariantRequest struct {
-	ProductID uint    `json:"product_id" binding:"required"`
 	Name      string  `json:"name" binding:"required"`
 	SKU       string  `json:"sku" binding:"required"`
-	Price     float64 `json:"price"`
+	Price     float64 `json:"price" binding:"min=0"` // Zero for the product price
 	Stock     int     `json:"stock" binding:"min=0"`
-	Weight    float64 `json:"weight"`
+	Weight    float64 `json:"weight" binding:"min=0"` // Zero for the product weight
 	Color     string  `json:"color"`
 	Size      string  `json:"size"`
 	Material  string  `json:"material"`
@@ -197,9 +228,9 @@ type CreateVariantRequest struct {
 type UpdateVariantRequest struct {
 	Name      *string  `json:"name"`
 	SKU       *string  `json:"sku"`
-	Price     *float64 `json:"price"`
-	Stock     *int     `json:"stock"`
-	Weight    *float64 `json:"weight"`
+	Price     *float64 `json:"price" binding:"omitempty,min=0"`
+	Stock     *int     `json:"stock" binding:"omitempty,min=0"` // Counted stock; the difference is recorded as a manual movement
+	Weight    *float64 `json:"weight" binding:"omitempty,min=0"`
 	Color     *string  `json:"color"`
 	Size      *string  `json:"size"`
 	Material  *string  `json:"material"`
@@ -210,27 +241,224 @@ type UpdateVariantRequest struct {
 
 // VariantResponse represents the product variant response
 type VariantResponse struct {
+	ID             uint                 `json:"id"`
+	ProductID      uint                 `json:"product_id"`
+	Name           string               `json:"name"`
+	SKU            string               `json:"sku"`
+	Price          float64              `json:"price"`       // Effective price: the variant price or else the product price
+	PriceMinor     int64                `json:"price_minor"` // Effective price in minor units of Currency
+	Currency       string               `json:"currency"`
+	OwnPrice       bool                 `json:"own_price"` // Whether the variant overrides the product price
+	Stock          int                  `json:"stock"`
+	ReservedStock  int                  `json:"reserved_stock"`
+	AvailableStock int                  `json:"available_stock"` // Stock less reserved stock
+	Weight         float64              `json:"weight"`          // Effective weight: the variant weight or else the product weight
+	Color          string               `json:"color"`
+	Size           string               `json:"size"`
+	Material       string               `json:"material"`
+	Image          string               `json:"image"`
+	IsActive       bool                 `json:"is_active"`
+	SortOrder      int                  `json:"sort_order"`
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+	StockLevels    []StockLevelResponse `json:"stock_levels,omitempty"` // Per warehouse, when a single product is retrieved
+}
+
+// ListVariantsResponse represents the variants of a product
+type ListVariantsResponse struct {
+	Variants []VariantResponse `json:"variants"`
+	Total    int               `json:"total"`
+}
+
+// ========== RESERVATION DTOs ==========
+
+// ReserveStockRequest represents the request to hold stock for a basket or payment
+type ReserveStockRequest struct {
+	ReferenceType string                   `json:"reference_type" binding:"required,oneof=basket payment"`
+	ReferenceID   string                   `json:"reference_id" binding:"required"`
+	Items         []ReservationItemRequest `json:"items" binding:"required,min=1,dive"`
+	TTL           time.Duration            `json:"ttl"` // Zero for the default TTL
+}
+
+// ReservationItemRequest represents a product or variant quantity to hold
+type ReservationItemRequest struct {
+	ProductID uint  `json:"product_id" binding:"required"`
+	VariantID *uint `json:"variant_id"` // Nil for the product itself
+	Quantity  int   `json:"quantity" binding:"required,min=1"`
+}
+
+// ReservationItemResponse represents a product or variant quantity held by a reservation
+type ReservationItemResponse struct {
+	ProductID uint  `json:"product_id"`
+	VariantID *uint `json:"variant_id,omitempty"`
+	Quantity  int   `json:"quantity"`
+}
+
+// ReservationResponse represents the stock reservation response
+type ReservationResponse struct {
+	ID            string                    `json:"id"`
+	ReferenceType string                    `json:"reference_type"`
+	ReferenceID   string                    `json:"reference_id"`
+	Status        string                    `json:"status"`
+	Items         []ReservationItemResponse `json:"items"`
+	ExpiresAt     time.Time                 `json:"expires_at"`
+	CreatedAt     time.Time                 `json:"created_at"`
+	ConfirmedAt   *time.Time                `json:"confirmed_at,omitempty"`
+	ReleasedAt    *time.Time                `json:"released_at,omitempty"`
+}
+
+// ========== INVENTORY DTOs ==========
+
+// ListStockMovementsRequest represents the filters of the stock ledger listing
+type ListStockMovementsRequest struct {
+	ProductID   *uint  `form:"product_id"`
+	VariantID   *uint  `form:"variant_id"`
+	WarehouseID *uint  `form:"warehouse_id"`
+	Reason      string `form:"reason" binding:"omitempty,oneof=sale refund manual import reservation"`
+	OrderID     string `form:"order_id"`
+	PaymentID   string `form:"payment_id"`
+	Offset      int    `form:"offset" binding:"min=0"`
+	Limit       int    `form:"limit" binding:"min=0,max=100"`
+}
+
+// StockMovementResponse represents an entry of the stock ledger
+type StockMovementResponse struct {
+	ID            uint      `json:"id"`
+	ProductID     uint      `json:"product_id"`
+	VariantID     *uint     `json:"variant_id,omitempty"`
+	WarehouseID   *uint     `json:"warehouse_id,omitempty"`
+	Delta         int       `json:"delta"`
+	Reason        string    `json:"reason"`
+	OrderID       *string   `json:"order_id,omitempty"`
+	PaymentID     *string   `json:"payment_id,omitempty"`
+	ReservationID *string   `json:"reservation_id,omitempty"`
+	Actor         string    `json:"actor"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ListStockMovementsResponse represents the paginated list of stock movements
+type ListStockMovementsResponse struct {
+	Movements []StockMovementResponse `json:"movements"`
+	Total     int                     `json:"total"`
+	Offset    int                     `json:"offset"`
+	Limit     int                     `json:"limit"`
+}
+
+// RebuildStockResponse represents the stock of a product, or of one of its variants,
+// rebuilt from the stock ledger
+type RebuildStockResponse struct {
+	ProductID     uint                   `json:"product_id"`
+	VariantID     *uint                  `json:"variant_id,omitempty"`
+	PreviousStock int                    `json:"previous_stock"`
+	Stock         int                    `json:"stock"`
+	Drift         int                    `json:"drift"`              // Previous stock less the ledger balance
+	Variants      []RebuildStockResponse `json:"variants,omitempty"` // Variants whose stock drifted
+}
+
+// RebuildAllStockResponse represents the products whose stock was rebuilt
+type RebuildAllStockResponse struct {
+	Checked   int                    `json:"checked"`
+	Corrected []RebuildStockResponse `json:"corrected"`
+}
+
+// StockLevelResponse represents the stock of a product or variant in a warehouse
+type StockLevelResponse struct {
+	ID            uint      `json:"id"`
+	ProductID     uint      `json:"product_id"`
+	VariantID     *uint     `json:"variant_id,omitempty"`
+	WarehouseID   uint      `json:"warehouse_id"`
+	WarehouseCode string    `json:"warehouse_code"`
+	WarehouseName string    `json:"warehouse_name"`
+	Stock         int       `json:"stock"`
+	MinStock      int       `json:"min_stock"`
+	IsLowStock    bool      `json:"is_low_stock"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// ProductStockLevelsResponse represents the stock of a product across warehouses
+type ProductStockLevelsResponse struct {
+	ProductID      uint                 `json:"product_id"`
+	Stock          int                  `json:"stock"` // Sum of the product levels, variant levels excluded
+	ReservedStock  int                  `json:"reserved_stock"`
+	AvailableStock int                  `json:"available_stock"`
+	Levels         []StockLevelResponse `json:"levels"`
+}
+
+// SetStockLevelRequest represents the request to count the stock of a product, or of one
+// of its variants, in a warehouse
+type SetStockLevelRequest struct {
+	VariantID *uint `json:"variant_id"` // Nil for the stock of the product itself
+	Stock     *int  `json:"stock" binding:"omitempty,min=0"`
+	MinStock  *int  `json:"min_stock" binding:"omitempty,min=0"`
+}
+
+// ListLowStockRequest represents the filters of the low stock listing
+type ListLowStockRequest struct {
+	WarehouseID *uint `form:"warehouse_id"`
+	Offset      int   `form:"offset" binding:"min=0"`
+	Limit       int   `form:"limit" binding:"min=0,max=100"`
+}
+
+// ListLowStockResponse represents the paginated list of stock levels at or below their threshold
+type ListLowStockResponse struct {
+	Levels []StockLevelResponse `json:"levels"`
+	Total  int                  `json:"total"`
+	Offset int                  `json:"offset"`
+	Limit  int                  `json:"limit"`
+}
+
+// ========== WAREHOUSE DTOs ==========
+
+// CreateWarehouseRequest represents the request to create a new warehouse
+type CreateWarehouseRequest struct {
+	Code      string   `json:"code" binding:"required,max=50"`
+	Name      string   `json:"name" binding:"required,max=255"`
+	Address   string   `json:"address"`
+	City      string   `json:"city"`
+	Country   string   `json:"country"`
+	Latitude  *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
+	Priority  int      `json:"priority"`
+}
+
+// UpdateWarehouseRequest represents the request to update a warehouse
+type UpdateWarehouseRequest struct {
+	Name      *string  `json:"name" binding:"omitempty,max=255"`
+	Address   *string  `json:"address"`
+	City      *string  `json:"city"`
+	Country   *string  `json:"country"`
+	Latitude  *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
+	Priority  *int     `json:"priority"`
+	IsActive  *bool    `json:"is_active"`
+}
+
+// ListWarehousesRequest represents the pagination of the warehouse listing
+type ListWarehousesRequest struct {
+	Offset int `form:"offset" binding:"min=0"`
+	Limit  int `form:"limit" binding:"min=0,max=100"`
+}
+
+// WarehouseResponse represents the warehouse response
+type WarehouseResponse struct {
 	ID        uint      `json:"id"`
-	ProductID uint      `json:"product_id"`
+	Code      string    `json:"code"`
 	Name      string    `json:"name"`
-	SKU       string    `json:"sku"`
-	Price     float64   `json:"price"`
-	Stock     int       `json:"stock"`
-	Weight    float64   `json:"weight"`
-	Color     string    `json:"color"`
-	Size      string    `json:"size"`
-	Material  string    `json:"material"`
-	Image     string    `json:"image"`
+	Address   string    `json:"address"`
+	City      string    `json:"city"`
+	Country   string    `json:"country"`
+	Latitude  *float64  `json:"latitude,omitempty"`
+	Longitude *float64  `json:"longitude,omitempty"`
+	Priority  int       `json:"priority"`
 	IsActive  bool      `json:"is_active"`
-	SortOrder int       `json:"sort_order"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
-// ListVariantsResponse represents the paginated list of variants
-type ListVariantsResponse struct {
-	Variants []VariantResponse `json:"variants"`
-	Total    int               `json:"total"`
-	Offset   int               `json:"offset"`
-	Limit    int               `json:"limit"`
+// ListWarehousesResponse represents the paginated list of warehouses
+type ListWarehousesResponse struct {
+	Warehouses []WarehouseResponse `json:"warehouses"`
+	Total      int                 `json:"total"`
+	Offset     int                 `json:"offset"`
+	Limit      int                 `json:"limit"`
 }
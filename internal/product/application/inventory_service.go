@@ -0,0 +1,338 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/ddd-micro/internal/product/domain"
+	productkafka "github.com/ddd-micro/internal/product/infrastructure/kafka"
+	"github.com/ddd-micro/pkg/gormtx"
+)
+
+// rebuildBatch is how many products are rebuilt per batch when rebuilding all stock
+const rebuildBatch = 100
+
+// InventoryService manages the stock of products per warehouse, reads the stock ledger
+// and rebuilds the stock projection from it. Movements themselves are recorded by the
+// product aggregate whenever its stock changes.
+type InventoryService struct {
+	productRepo    domain.ProductRepository
+	movementRepo   domain.StockMovementRepository
+	stockLevelRepo domain.StockLevelRepository
+	eventPublisher *productkafka.ProductEventPublisher
+	transactor     *gormtx.Transactor
+}
+
+// NewInventoryService creates a new inventory service
+func NewInventoryService(
+	productRepo domain.ProductRepository,
+	movementRepo domain.StockMovementRepository,
+	stockLevelRepo domain.StockLevelRepository,
+	eventPublisher *productkafka.ProductEventPublisher,
+	transactor *gormtx.Transactor,
+) *InventoryService {
+	return &InventoryService{
+		productRepo:    productRepo,
+		movementRepo:   movementRepo,
+		stockLevelRepo: stockLevelRepo,
+		eventPublisher: eventPublisher,
+		transactor:     transactor,
+	}
+}
+
+// ListStockLevels retrieves the stock of a product and its variants in every warehouse
+func (s *InventoryService) ListStockLevels(ctx context.Context, productID uint) (*ProductStockLevelsResponse, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	levels, err := s.stockLevelRepo.ListByProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProductStockLevelsResponse{
+		ProductID:      product.ID,
+		Stock:          product.Stock,
+		ReservedStock:  product.ReservedStock,
+		AvailableStock: product.AvailableStock(),
+		Levels:         toStockLevelResponses(levels),
+	}, nil
+}
+
+// SetStockLevel counts the stock of a product, or of one of its variants, in a warehouse
+// and sets its low stock threshold there. The difference is recorded as a manual stock
+// movement.
+func (s *InventoryService) SetStockLevel(ctx context.Context, productID, warehouseID uint, req SetStockLevelRequest, actor string) (*StockLevelResponse, error) {
+	var resp StockLevelResponse
+
+	err := s.transactor.Within(ctx, func(ctx context.Context) error {
+		product, err := s.productRepo.GetByIDForUpdate(ctx, productID)
+		if err != nil {
+			return err
+		}
+
+		available := product.AvailableStocks()
+		if req.Stock != nil {
+			change := domain.StockChange{VariantID: req.VariantID, Reason: domain.StockMovementManual, Actor: actor}
+			if err := product.SetWarehouseStock(warehouseID, *req.Stock, change); err != nil {
+				return err
+			}
+		}
+		if req.MinStock != nil {
+			if err := product.SetWarehouseMinStock(req.VariantID, warehouseID, *req.MinStock); err != nil {
+				return err
+			}
+		}
+
+		if err := s.productRepo.Update(ctx, product); err != nil {
+			return err
+		}
+
+		level, ok := product.StockLevel(req.VariantID, warehouseID)
+		if !ok {
+			return domain.ErrWarehouseNotFound
+		}
+		resp = toStockLevelResponse(level)
+
+		return publishStockChanges(ctx, s.eventPublisher, product, available, "stock_set", nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// ListLowStock lists the stock levels at or below the low stock threshold of their warehouse
+func (s *InventoryService) ListLowStock(ctx context.Context, req ListLowStockRequest) (*ListLowStockResponse, error) {
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+
+	levels, total, err := s.stockLevelRepo.ListLowStock(ctx, req.WarehouseID, req.Offset, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListLowStockResponse{
+		Levels: toStockLevelResponses(levels),
+		Total:  total,
+		Offset: req.Offset,
+		Limit:  req.Limit,
+	}, nil
+}
+
+// ListStockMovements lists stock movements, newest first
+func (s *InventoryService) ListStockMovements(ctx context.Context, req ListStockMovementsRequest) (*ListStockMovementsResponse, error) {
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+
+	filter := domain.StockMovementFilter{
+		ProductID:   req.ProductID,
+		VariantID:   req.VariantID,
+		WarehouseID: req.WarehouseID,
+		Reason:      domain.StockMovementReason(req.Reason),
+		OrderID:     req.OrderID,
+		PaymentID:   req.PaymentID,
+	}
+
+	movements, total, err := s.movementRepo.List(ctx, filter, req.Offset, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]StockMovementResponse, 0, len(movements))
+	for _, movement := range movements {
+		responses = append(responses, toStockMovementResponse(movement))
+	}
+
+	return &ListStockMovementsResponse{
+		Movements: responses,
+		Total:     total,
+		Offset:    req.Offset,
+		Limit:     req.Limit,
+	}, nil
+}
+
+// RebuildStock replaces the stock of a product and of its variants in every warehouse with
+// the balance of their stock movements there. The product is locked first, so no movement
+// can be added while the balances are taken.
+func (s *InventoryService) RebuildStock(ctx context.Context, productID uint) (*RebuildStockResponse, error) {
+	var resp *RebuildStockResponse
+
+	err := s.transactor.Within(ctx, func(ctx context.Context) error {
+		product, err := s.productRepo.GetByIDForUpdate(ctx, productID)
+		if err != nil {
+			return err
+		}
+
+		available := product.AvailableStocks()
+		rebuilt := false
+		for _, variantID := range stockItemIDs(product) {
+			balances, err := s.movementRepo.Balances(ctx, productID, variantID)
+			if err != nil {
+				return err
+			}
+
+			drift, itemRebuilt, err := product.RebuildStock(variantID, balances)
+			if err != nil {
+				return err
+			}
+			rebuilt = rebuilt || itemRebuilt
+
+			item := RebuildStockResponse{
+				ProductID: productID,
+				VariantID: variantID,
+				Stock:     product.StockOf(variantID),
+				Drift:     drift,
+			}
+			item.PreviousStock = item.Stock + drift
+			if variantID == nil {
+				resp = &item
+			} else if drift != 0 {
+				resp.Variants = append(resp.Variants, item)
+			}
+		}
+		if !rebuilt {
+			return nil
+		}
+
+		if err := s.productRepo.Update(ctx, product); err != nil {
+			return err
+		}
+
+		return publishStockChanges(ctx, s.eventPublisher, product, available, "stock_rebuilt", nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Drift != 0 {
+		log.Printf("Stock of product %d rebuilt from the ledger: %d -> %d", productID, resp.PreviousStock, resp.Stock)
+	}
+	for _, variant := range resp.Variants {
+		log.Printf("Stock of variant %d of product %d rebuilt from the ledger: %d -> %d", *variant.VariantID, productID, variant.PreviousStock, variant.Stock)
+	}
+	return resp, nil
+}
+
+// RebuildAllStock rebuilds the stock of every product, one transaction per product, and
+// returns the products whose stock drifted from the ledger
+func (s *InventoryService) RebuildAllStock(ctx context.Context) (*RebuildAllStockResponse, error) {
+	resp := &RebuildAllStockResponse{Corrected: []RebuildStockResponse{}}
+
+	var afterID uint
+	for {
+		ids, err := s.productRepo.ListIDs(ctx, afterID, rebuildBatch)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			return resp, nil
+		}
+
+		for _, id := range ids {
+			rebuilt, err := s.RebuildStock(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			resp.Checked++
+			if rebuilt.Drift != 0 || len(rebuilt.Variants) > 0 {
+				resp.Corrected = append(resp.Corrected, *rebuilt)
+			}
+		}
+		afterID = ids[len(ids)-1]
+	}
+}
+
+// publishStockChanges stores a stock updated event for the product and for each of its
+// variants whose available stock changed since it was taken by AvailableStocks
+func publishStockChanges(ctx context.Context, publisher *productkafka.ProductEventPublisher, product *domain.Product, before map[uint]int, reason string, paymentID *string) error {
+	after := product.AvailableStocks()
+	for _, variantID := range stockItemIDs(product) {
+		key := uint(0)
+		if variantID != nil {
+			key = *variantID
+		}
+
+		quantity := after[key] - before[key]
+		if quantity == 0 {
+			continue
+		}
+		if err := publisher.PublishStockUpdated(ctx, product.ID, variantID, quantity, after[key], reason, nil, paymentID); err != nil {
+			return fmt.Errorf("failed to publish stock updated event for product %d: %w", product.ID, err)
+		}
+	}
+	return nil
+}
+
+// stockItemIDs returns nil, naming the stock of the product itself, followed by the IDs of
+// its variants in sort order
+func stockItemIDs(product *domain.Product) []*uint {
+	ids := []*uint{nil}
+	for _, variant := range product.Variants {
+		id := variant.ID
+		ids = append(ids, &id)
+	}
+	return ids
+}
+
+// toStockMovementResponse converts domain.StockMovement to StockMovementResponse
+func toStockMovementResponse(movement *domain.StockMovement) StockMovementResponse {
+	return StockMovementResponse{
+		ID:            movement.ID,
+		ProductID:     movement.ProductID,
+		VariantID:     movement.VariantID,
+		WarehouseID:   movement.WarehouseID,
+		Delta:         movement.Delta,
+		Reason:        string(movement.Reason),
+		OrderID:       movement.OrderID,
+		PaymentID:     movement.PaymentID,
+		ReservationID: movement.ReservationID,
+		Actor:         movement.Actor,
+		CreatedAt:     movement.CreatedAt,
+	}
+}
+
+// toStockLevelResponses converts stored stock levels to StockLevelResponses; levels the
+// product has never held stock in are left out
+func toStockLevelResponses(levels []*domain.StockLevel) []StockLevelResponse {
+	var responses []StockLevelResponse
+	for _, level := range levels {
+		if level.ID != 0 {
+			responses = append(responses, toStockLevelResponse(level))
+		}
+	}
+	return responses
+}
+
+// toStockLevelResponse converts domain.StockLevel to StockLevelResponse
+func toStockLevelResponse(level *domain.StockLevel) StockLevelResponse {
+	resp := StockLevelResponse{
+		ID:          level.ID,
+		ProductID:   level.ProductID,
+		VariantID:   level.VariantID,
+		WarehouseID: level.WarehouseID,
+		Stock:       level.Stock,
+		MinStock:    level.MinStock,
+		IsLowStock:  level.IsLowStock(),
+		UpdatedAt:   level.UpdatedAt,
+	}
+	if level.Warehouse != nil {
+		resp.WarehouseCode = level.Warehouse.Code
+		resp.WarehouseName = level.Warehouse.Name
+	}
+	return resp
+}
+
+// destination returns the destination of the target, if both coordinates are given
+func (t StockTarget) destination() *domain.GeoPoint {
+	if t.Latitude == nil || t.Longitude == nil {
+		return nil
+	}
+	return &domain.GeoPoint{Latitude: *t.Latitude, Longitude: *t.Longitude}
+}
@@ -34,10 +34,6 @@ func (s *ProductService) CreateProduct(ctx context.Context, req CreateProductReq
 		Name:             req.Name,
 		Description:      req.Description,
 		ShortDescription: req.ShortDescription,
-		Price:            req.Price,
-		ComparePrice:     req.ComparePrice,
-		CostPrice:        req.CostPrice,
-		Stock:            req.Stock,
 		MinStock:         req.MinStock,
 		MaxStock:         req.MaxStock,
 		Category:         req.Category,
@@ -59,6 +55,19 @@ func (s *ProductService) CreateProduct(ctx context.Context, req CreateProductReq
 		IsActive:         true,
 	}
 
+	// Record the initial stock in the stock ledger, kept in the first warehouse by priority
+	if err := s.repo.LoadStockLevels(ctx, product); err != nil {
+		return nil, err
+	}
+	if err := product.SetStock(req.Stock, domain.StockChange{Reason: domain.StockMovementManual}); err != nil {
+		return nil, err
+	}
+
+	// Convert prices to minor units of the currency
+	if err := product.ApplyPrices(req.Currency, &req.Price, &req.ComparePrice, &req.CostPrice); err != nil {
+		return nil, err
+	}
+
 	// Validate product
 	if err := product.ValidateProduct(); err != nil {
 		return nil, err
@@ -110,17 +119,19 @@ func (s *ProductService) UpdateProduct(ctx context.Context, id uint, req UpdateP
 	if req.ShortDescription != nil {
 		product.ShortDescription = *req.ShortDescription
 	}
-	if req.Price != nil {
-		product.Price = *req.Price
-	}
-	if req.ComparePrice != nil {
-		product.ComparePrice = *req.ComparePrice
-	}
-	if req.CostPrice != nil {
-		product.CostPrice = *req.CostPrice
+	if req.Price != nil || req.ComparePrice != nil || req.CostPrice != nil || req.Currency != nil {
+		currency := ""
+		if req.Currency != nil {
+			currency = *req.Currency
+		}
+		if err := product.ApplyPrices(currency, req.Price, req.ComparePrice, req.CostPrice); err != nil {
+			return nil, err
+		}
 	}
 	if req.Stock != nil {
-		product.Stock = *req.Stock
+		if err := product.SetStock(*req.Stock, domain.StockChange{Reason: domain.StockMovementManual}); err != nil {
+			return nil, err
+		}
 	}
 	if req.MinStock != nil {
 		product.MinStock = *req.MinStock
@@ -257,7 +268,16 @@ func (s *ProductService) SearchProducts(ctx context.Context, name string, offset
 
 // UpdateStock updates the stock of a product
 func (s *ProductService) UpdateStock(ctx context.Context, id uint, stock int) error {
-	return s.repo.UpdateStock(ctx, id, stock)
+	product, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := product.SetStock(stock, domain.StockChange{Reason: domain.StockMovementManual}); err != nil {
+		return err
+	}
+
+	return s.repo.Update(ctx, product)
 }
 
 // ReduceStock reduces the stock of a product
@@ -267,7 +287,7 @@ func (s *ProductService) ReduceStock(ctx context.Context, id uint, amount int) e
 		return err
 	}
 
-	if err := product.ReduceStock(amount); err != nil {
+	if err := product.ReduceStock(amount, domain.StockChange{Reason: domain.StockMovementManual}); err != nil {
 		return err
 	}
 
@@ -281,7 +301,7 @@ func (s *ProductService) IncreaseStock(ctx context.Context, id uint, amount int)
 		return err
 	}
 
-	if err := product.IncreaseStock(amount); err != nil {
+	if err := product.IncreaseStock(amount, domain.StockChange{Reason: domain.StockMovementManual}); err != nil {
 		return err
 	}
 
@@ -367,35 +387,39 @@ func (s *ProductService) IncrementViewCount(ctx context.Context, id uint) error
 // toProductResponse converts domain.Product to ProductResponse
 func (s *ProductService) toProductResponse(product *domain.Product) *ProductResponse {
 	return &ProductResponse{
-		ID:               product.ID,
-		Name:             product.Name,
-		Description:      product.Description,
-		ShortDescription: product.ShortDescription,
-		Price:            product.Price,
-		ComparePrice:     product.ComparePrice,
-		CostPrice:        product.CostPrice,
-		Stock:            product.Stock,
-		MinStock:         product.MinStock,
-		MaxStock:         product.MaxStock,
-		Category:         product.Category,
-		SubCategory:      product.SubCategory,
-		Brand:            product.Brand,
-		SKU:              product.SKU,
-		Barcode:          product.Barcode,
-		Weight:           product.Weight,
-		Dimensions:       product.Dimensions,
-		Color:            product.Color,
-		Size:             product.Size,
-		Material:         product.Material,
-		Tags:             product.Tags,
-		Images:           product.Images,
-		IsActive:         product.IsActive,
-		IsDigital:        product.IsDigital,
-		IsFeatured:       product.IsFeatured,
-		IsOnSale:         product.IsOnSale,
-		SortOrder:        product.SortOrder,
-		ViewCount:        product.ViewCount,
-		CreatedAt:        product.CreatedAt,
-		UpdatedAt:        product.UpdatedAt,
+		ID:                product.ID,
+		Name:              product.Name,
+		Description:       product.Description,
+		ShortDescription:  product.ShortDescription,
+		Price:             product.Price().Major(),
+		ComparePrice:      product.ComparePrice().Major(),
+		CostPrice:         product.CostPrice().Major(),
+		PriceMinor:        product.PriceMinor,
+		ComparePriceMinor: product.ComparePriceMinor,
+		CostPriceMinor:    product.CostPriceMinor,
+		Currency:          product.Currency,
+		Stock:             product.Stock,
+		MinStock:          product.MinStock,
+		MaxStock:          product.MaxStock,
+		Category:          product.Category,
+		SubCategory:       product.SubCategory,
+		Brand:             product.Brand,
+		SKU:               product.SKU,
+		Barcode:           product.Barcode,
+		Weight:            product.Weight,
+		Dimensions:        product.Dimensions,
+		Color:             product.Color,
+		Size:              product.Size,
+		Material:          product.Material,
+		Tags:              product.Tags,
+		Images:            product.Images,
+		IsActive:          product.IsActive,
+		IsDigital:         product.IsDigital,
+		IsFeatured:        product.IsFeatured,
+		IsOnSale:          product.IsOnSale,
+		SortOrder:         product.SortOrder,
+		ViewCount:         product.ViewCount,
+		CreatedAt:         product.CreatedAt,
+		UpdatedAt:         product.UpdatedAt,
 	}
 }
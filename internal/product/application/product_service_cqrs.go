@@ -6,6 +6,9 @@ import (
 	"github.com/ddd-micro/internal/product/application/command"
 	"github.com/ddd-micro/internal/product/application/query"
 	"github.com/ddd-micro/internal/product/domain"
+	"github.com/ddd-micro/internal/product/infrastructure/config"
+	productkafka "github.com/ddd-micro/internal/product/infrastructure/kafka"
+	"github.com/ddd-micro/pkg/gormtx"
 )
 
 // ProductServiceCQRS handles product business logic using CQRS pattern
@@ -29,14 +32,20 @@ type ProductServiceCQRS struct {
 	listProductsHandler           *query.ListProductsHandler
 	listProductsByCategoryHandler *query.ListProductsByCategoryHandler
 	searchProductsHandler         *query.SearchProductsHandler
+
+	// Stock changes and their events are stored in one transaction
+	repo           domain.ProductRepository
+	eventPublisher *productkafka.ProductEventPublisher
+	transactor     *gormtx.Transactor
+	allocation     domain.AllocationStrategy
 }
 
 // NewProductServiceCQRS creates a new CQRS-based product service
-func NewProductServiceCQRS(repo domain.ProductRepository) *ProductServiceCQRS {
+func NewProductServiceCQRS(repo domain.ProductRepository, categoryRepo domain.CategoryRepository, eventPublisher *productkafka.ProductEventPublisher, transactor *gormtx.Transactor, cfg *config.Config) *ProductServiceCQRS {
 	return &ProductServiceCQRS{
 		// Initialize command handlers
-		createProductHandler:      command.NewCreateProductHandler(repo),
-		updateProductHandler:      command.NewUpdateProductHandler(repo),
+		createProductHandler:      command.NewCreateProductHandler(repo, categoryRepo),
+		updateProductHandler:      command.NewUpdateProductHandler(repo, categoryRepo),
 		deleteProductHandler:      command.NewDeleteProductHandler(repo),
 		updateStockHandler:        command.NewUpdateStockHandler(repo),
 		reduceStockHandler:        command.NewReduceStockHandler(repo),
@@ -51,15 +60,20 @@ func NewProductServiceCQRS(repo domain.ProductRepository) *ProductServiceCQRS {
 		getProductByIDHandler:         query.NewGetProductByIDHandler(repo),
 		getProductBySKUHandler:        query.NewGetProductBySKUHandler(repo),
 		listProductsHandler:           query.NewListProductsHandler(repo),
-		listProductsByCategoryHandler: query.NewListProductsByCategoryHandler(repo),
+		listProductsByCategoryHandler: query.NewListProductsByCategoryHandler(repo, categoryRepo),
 		searchProductsHandler:         query.NewSearchProductsHandler(repo),
+
+		repo:           repo,
+		eventPublisher: eventPublisher,
+		transactor:     transactor,
+		allocation:     cfg.Inventory.AllocationStrategy,
 	}
 }
 
 // ========== COMMAND METHODS ==========
 
 // CreateProduct creates a new product
-func (s *ProductServiceCQRS) CreateProduct(ctx context.Context, req CreateProductRequest) (*ProductResponse, error) {
+func (s *ProductServiceCQRS) CreateProduct(ctx context.Context, req CreateProductRequest, actor string) (*ProductResponse, error) {
 	cmd := command.CreateProductCommand{
 		Name:             req.Name,
 		Description:      req.Description,
@@ -67,11 +81,13 @@ func (s *ProductServiceCQRS) CreateProduct(ctx context.Context, req CreateProduc
 		Price:            req.Price,
 		ComparePrice:     req.ComparePrice,
 		CostPrice:        req.CostPrice,
+		Currency:         req.Currency,
 		Stock:            req.Stock,
 		MinStock:         req.MinStock,
 		MaxStock:         req.MaxStock,
 		Category:         req.Category,
 		SubCategory:      req.SubCategory,
+		CategoryID:       req.CategoryID,
 		Brand:            req.Brand,
 		SKU:              req.SKU,
 		Barcode:          req.Barcode,
@@ -86,6 +102,7 @@ func (s *ProductServiceCQRS) CreateProduct(ctx context.Context, req CreateProduc
 		IsFeatured:       req.IsFeatured,
 		IsOnSale:         req.IsOnSale,
 		SortOrder:        req.SortOrder,
+		Actor:            actor,
 	}
 
 	product, err := s.createProductHandler.Handle(ctx, cmd)
@@ -96,8 +113,9 @@ func (s *ProductServiceCQRS) CreateProduct(ctx context.Context, req CreateProduc
 	return s.toProductResponse(product), nil
 }
 
-// UpdateProduct updates an existing product
-func (s *ProductServiceCQRS) UpdateProduct(ctx context.Context, id uint, req UpdateProductRequest) (*ProductResponse, error) {
+// UpdateProduct updates an existing product; a stock change is locked and published
+// like UpdateStock
+func (s *ProductServiceCQRS) UpdateProduct(ctx context.Context, id uint, req UpdateProductRequest, actor string) (*ProductResponse, error) {
 	cmd := command.UpdateProductCommand{
 		ProductID:        id,
 		Name:             req.Name,
@@ -106,11 +124,13 @@ func (s *ProductServiceCQRS) UpdateProduct(ctx context.Context, id uint, req Upd
 		Price:            req.Price,
 		ComparePrice:     req.ComparePrice,
 		CostPrice:        req.CostPrice,
+		Currency:         req.Currency,
 		Stock:            req.Stock,
 		MinStock:         req.MinStock,
 		MaxStock:         req.MaxStock,
 		Category:         req.Category,
 		SubCategory:      req.SubCategory,
+		CategoryID:       req.CategoryID,
 		Brand:            req.Brand,
 		Barcode:          req.Barcode,
 		Weight:           req.Weight,
@@ -125,9 +145,22 @@ func (s *ProductServiceCQRS) UpdateProduct(ctx context.Context, id uint, req Upd
 		IsFeatured:       req.IsFeatured,
 		IsOnSale:         req.IsOnSale,
 		SortOrder:        req.SortOrder,
+		Actor:            actor,
+	}
+
+	var product *domain.Product
+	update := func(ctx context.Context) error {
+		var err error
+		product, err = s.updateProductHandler.Handle(ctx, cmd)
+		return err
 	}
 
-	product, err := s.updateProductHandler.Handle(ctx, cmd)
+	var err error
+	if cmd.Stock != nil {
+		err = s.changeStock(ctx, id, "stock_set", update)
+	} else {
+		err = update(ctx)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -144,34 +177,74 @@ func (s *ProductServiceCQRS) DeleteProduct(ctx context.Context, id uint) error {
 	return s.deleteProductHandler.Handle(ctx, cmd)
 }
 
-// UpdateStock updates the stock of a product
-func (s *ProductServiceCQRS) UpdateStock(ctx context.Context, id uint, stock int) error {
+// UpdateStock updates the stock of a product, or of one of its variants
+func (s *ProductServiceCQRS) UpdateStock(ctx context.Context, id uint, variantID *uint, stock int, actor string) error {
 	cmd := command.UpdateStockCommand{
 		ProductID: id,
+		VariantID: variantID,
 		Stock:     stock,
+		Actor:     actor,
 	}
 
-	return s.updateStockHandler.Handle(ctx, cmd)
+	return s.changeStock(ctx, id, "stock_set", func(ctx context.Context) error {
+		return s.updateStockHandler.Handle(ctx, cmd)
+	})
 }
 
-// ReduceStock reduces the stock of a product
-func (s *ProductServiceCQRS) ReduceStock(ctx context.Context, id uint, amount int) error {
+// ReduceStock reduces the stock of a product, or of the target variant, taking it from the
+// target warehouse or from the warehouses picked by the configured allocation strategy.
+// Nearest-first allocation needs the target's destination.
+func (s *ProductServiceCQRS) ReduceStock(ctx context.Context, id uint, amount int, target StockTarget, actor string) error {
 	cmd := command.ReduceStockCommand{
-		ProductID: id,
-		Amount:    amount,
-	}
-
-	return s.reduceStockHandler.Handle(ctx, cmd)
+		ProductID:   id,
+		VariantID:   target.VariantID,
+		Amount:      amount,
+		WarehouseID: target.WarehouseID,
+		Destination: target.destination(),
+		Actor:       actor,
+		Strategy:    s.allocation,
+	}
+
+	return s.changeStock(ctx, id, "stock_reduced", func(ctx context.Context) error {
+		return s.reduceStockHandler.Handle(ctx, cmd)
+	})
 }
 
-// IncreaseStock increases the stock of a product
-func (s *ProductServiceCQRS) IncreaseStock(ctx context.Context, id uint, amount int) error {
+// IncreaseStock increases the stock of a product, or of the target variant, in the target
+// warehouse, or in the first warehouse by priority
+func (s *ProductServiceCQRS) IncreaseStock(ctx context.Context, id uint, amount int, target StockTarget, actor string) error {
 	cmd := command.IncreaseStockCommand{
-		ProductID: id,
-		Amount:    amount,
+		ProductID:   id,
+		VariantID:   target.VariantID,
+		Amount:      amount,
+		WarehouseID: target.WarehouseID,
+		Actor:       actor,
 	}
 
-	return s.increaseStockHandler.Handle(ctx, cmd)
+	return s.changeStock(ctx, id, "stock_increased", func(ctx context.Context) error {
+		return s.increaseStockHandler.Handle(ctx, cmd)
+	})
+}
+
+// changeStock applies a stock change and stores its stock updated event in the same transaction
+func (s *ProductServiceCQRS) changeStock(ctx context.Context, id uint, reason string, change func(ctx context.Context) error) error {
+	return s.transactor.Within(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if err := change(ctx); err != nil {
+			return err
+		}
+
+		after, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return publishStockChanges(ctx, s.eventPublisher, after, before.AvailableStocks(), reason, nil)
+	})
 }
 
 // ActivateProduct activates a product
@@ -294,7 +367,7 @@ func (s *ProductServiceCQRS) ListProducts(ctx context.Context, offset, limit int
 	}, nil
 }
 
-// ListProductsByCategory retrieves products by category with pagination
+// ListProductsByCategory retrieves products of a category and its descendants with pagination
 func (s *ProductServiceCQRS) ListProductsByCategory(ctx context.Context, category string, offset, limit int) (*ListProductsResponse, error) {
 	q := query.ListProductsByCategoryQuery{
 		Category: category,
@@ -351,35 +424,44 @@ func (s *ProductServiceCQRS) SearchProducts(ctx context.Context, name string, of
 // toProductResponse converts domain.Product to ProductResponse
 func (s *ProductServiceCQRS) toProductResponse(product *domain.Product) *ProductResponse {
 	return &ProductResponse{
-		ID:               product.ID,
-		Name:             product.Name,
-		Description:      product.Description,
-		ShortDescription: product.ShortDescription,
-		Price:            product.Price,
-		ComparePrice:     product.ComparePrice,
-		CostPrice:        product.CostPrice,
-		Stock:            product.Stock,
-		MinStock:         product.MinStock,
-		MaxStock:         product.MaxStock,
-		Category:         product.Category,
-		SubCategory:      product.SubCategory,
-		Brand:            product.Brand,
-		SKU:              product.SKU,
-		Barcode:          product.Barcode,
-		Weight:           product.Weight,
-		Dimensions:       product.Dimensions,
-		Color:            product.Color,
-		Size:             product.Size,
-		Material:         product.Material,
-		Tags:             product.Tags,
-		Images:           product.Images,
-		IsActive:         product.IsActive,
-		IsDigital:        product.IsDigital,
-		IsFeatured:       product.IsFeatured,
-		IsOnSale:         product.IsOnSale,
-		SortOrder:        product.SortOrder,
-		ViewCount:        product.ViewCount,
-		CreatedAt:        product.CreatedAt,
-		UpdatedAt:        product.UpdatedAt,
+		ID:                product.ID,
+		Name:              product.Name,
+		Description:       product.Description,
+		ShortDescription:  product.ShortDescription,
+		Price:             product.Price().Major(),
+		ComparePrice:      product.ComparePrice().Major(),
+		CostPrice:         product.CostPrice().Major(),
+		PriceMinor:        product.PriceMinor,
+		ComparePriceMinor: product.ComparePriceMinor,
+		CostPriceMinor:    product.CostPriceMinor,
+		Currency:          product.Currency,
+		Stock:             product.Stock,
+		ReservedStock:     product.ReservedStock,
+		AvailableStock:    product.AvailableStock(),
+		MinStock:          product.MinStock,
+		MaxStock:          product.MaxStock,
+		Category:          product.Category,
+		SubCategory:       product.SubCategory,
+		CategoryID:        product.CategoryID,
+		Brand:             product.Brand,
+		SKU:               product.SKU,
+		Barcode:           product.Barcode,
+		Weight:            product.Weight,
+		Dimensions:        product.Dimensions,
+		Color:             product.Color,
+		Size:              product.Size,
+		Material:          product.Material,
+		Tags:              product.Tags,
+		Images:            product.Images,
+		IsActive:          product.IsActive,
+		IsDigital:         product.IsDigital,
+		IsFeatured:        product.IsFeatured,
+		IsOnSale:          product.IsOnSale,
+		SortOrder:         product.SortOrder,
+		ViewCount:         product.ViewCount,
+		CreatedAt:         product.CreatedAt,
+		UpdatedAt:         product.UpdatedAt,
+		StockLevels:       toStockLevelResponses(product.StockLevelsOf(nil)),
+		Variants:          toVariantResponses(product),
 	}
 }
@@ -8,5 +8,10 @@ import (
 var ProviderSet = wire.NewSet(
 	NewProductService,
 	NewProductServiceCQRS,
+	NewReservationService,
+	NewInventoryService,
+	NewWarehouseService,
+	NewCategoryService,
+	NewVariantService,
 	NewUserService,
 )
@@ -2,6 +2,7 @@ package query
 
 import (
 	"context"
+	"errors"
 
 	"github.com/ddd-micro/internal/product/domain"
 )
@@ -47,7 +48,8 @@ func (h *ListProductsHandler) Handle(ctx context.Context, q ListProductsQuery) (
 	}, nil
 }
 
-// ListProductsByCategoryQuery represents the query to list products by category
+// ListProductsByCategoryQuery represents the query to list products by category. The
+// category is a category slug or name; products of its descendant categories are included.
 type ListProductsByCategoryQuery struct {
 	Category string `json:"category"`
 	Offset   int    `json:"offset"`
@@ -64,19 +66,32 @@ type ListProductsByCategoryResult struct {
 
 // ListProductsByCategoryHandler handles the list products by category query
 type ListProductsByCategoryHandler struct {
-	repo domain.ProductRepository
+	repo         domain.ProductRepository
+	categoryRepo domain.CategoryRepository
 }
 
 // NewListProductsByCategoryHandler creates a new list products by category handler
-func NewListProductsByCategoryHandler(repo domain.ProductRepository) *ListProductsByCategoryHandler {
+func NewListProductsByCategoryHandler(repo domain.ProductRepository, categoryRepo domain.CategoryRepository) *ListProductsByCategoryHandler {
 	return &ListProductsByCategoryHandler{
-		repo: repo,
+		repo:         repo,
+		categoryRepo: categoryRepo,
 	}
 }
 
-// Handle executes the list products by category query
+// Handle executes the list products by category query. Products not linked to the
+// category tree yet are matched by their legacy category name when no category matches.
 func (h *ListProductsByCategoryHandler) Handle(ctx context.Context, q ListProductsByCategoryQuery) (*ListProductsByCategoryResult, error) {
-	products, err := h.repo.ListByCategory(ctx, q.Category, q.Offset, q.Limit)
+	category, err := h.findCategory(ctx, q.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	var products []*domain.Product
+	if category != nil {
+		products, err = h.repo.ListByCategoryTree(ctx, category, q.Offset, q.Limit)
+	} else {
+		products, err = h.repo.ListByCategory(ctx, q.Category, q.Offset, q.Limit)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -89,6 +104,24 @@ func (h *ListProductsByCategoryHandler) Handle(ctx context.Context, q ListProduc
 	}, nil
 }
 
+// findCategory looks a category up by slug, then by the slug of its name; it returns nil
+// when no category matches
+func (h *ListProductsByCategoryHandler) findCategory(ctx context.Context, name string) (*domain.Category, error) {
+	for _, slug := range []string{name, domain.Slugify(name)} {
+		if slug == "" {
+			continue
+		}
+		category, err := h.categoryRepo.GetBySlug(ctx, slug)
+		if err == nil {
+			return category, nil
+		}
+		if !errors.Is(err, domain.ErrCategoryNotFound) {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
 // SearchProductsQuery represents the query to search products
 type SearchProductsQuery struct {
 	Name   string `json:"name"`
@@ -0,0 +1,404 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ddd-micro/internal/product/domain"
+	"github.com/ddd-micro/internal/product/infrastructure/config"
+	productkafka "github.com/ddd-micro/internal/product/infrastructure/kafka"
+	"github.com/ddd-micro/pkg/gormtx"
+	"github.com/google/uuid"
+)
+
+// Stock updated event reasons of reservations
+const (
+	reasonStockReserved       = "stock_reserved"
+	reasonReservationReleased = "reservation_released"
+	reasonReservationExpired  = "reservation_expired"
+)
+
+// ReservationService holds stock for baskets and payments until it is sold or given back.
+// A reservation, the products it holds stock of and the resulting stock updated events
+// are stored in one transaction; products are locked in ascending ID order so concurrent
+// reservations cannot deadlock. The sweeper releases reservations that expired.
+type ReservationService struct {
+	productRepo     domain.ProductRepository
+	reservationRepo domain.StockReservationRepository
+	eventPublisher  *productkafka.ProductEventPublisher
+	transactor      *gormtx.Transactor
+	config          config.ReservationConfig
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewReservationService creates a new reservation service
+func NewReservationService(
+	productRepo domain.ProductRepository,
+	reservationRepo domain.StockReservationRepository,
+	eventPublisher *productkafka.ProductEventPublisher,
+	transactor *gormtx.Transactor,
+	cfg *config.Config,
+) *ReservationService {
+	return &ReservationService{
+		productRepo:     productRepo,
+		reservationRepo: reservationRepo,
+		eventPublisher:  eventPublisher,
+		transactor:      transactor,
+		config:          cfg.Reservation,
+	}
+}
+
+// ReserveStock holds the items for the basket or payment. An active reservation of the
+// same reference is replaced, so a basket can hold its current items with a fresh TTL.
+func (s *ReservationService) ReserveStock(ctx context.Context, req ReserveStockRequest) (*ReservationResponse, error) {
+	now := time.Now()
+	items := make([]domain.StockReservationItem, 0, len(req.Items))
+	for _, item := range req.Items {
+		items = append(items, domain.StockReservationItem{ProductID: item.ProductID, VariantID: item.VariantID, Quantity: item.Quantity})
+	}
+
+	reservation, err := domain.NewStockReservation(uuid.New().String(), domain.ReservationReference(req.ReferenceType), req.ReferenceID, items, now.Add(s.ttl(req.TTL)))
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.transactor.Within(ctx, func(ctx context.Context) error {
+		previous, err := s.reservationRepo.GetActiveByReferenceForUpdate(ctx, reservation.ReferenceType, reservation.ReferenceID)
+		if err != nil && !errors.Is(err, domain.ErrReservationNotFound) {
+			return err
+		}
+
+		productIDs := reservation.ProductIDs()
+		if previous != nil {
+			productIDs = mergeProductIDs(productIDs, previous.ProductIDs())
+		}
+		products, available, err := s.lockProducts(ctx, productIDs)
+		if err != nil {
+			return err
+		}
+
+		if previous != nil {
+			if err := previous.Release(now); err != nil {
+				return err
+			}
+			if err := s.reservationRepo.Update(ctx, previous); err != nil {
+				return err
+			}
+			for _, item := range previous.Items {
+				if err := products[item.ProductID].ReleaseReserved(item.VariantID, item.Quantity); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, item := range reservation.Items {
+			product := products[item.ProductID]
+			if !product.IsActive {
+				return fmt.Errorf("product %d: %w", product.ID, domain.ErrProductNotActive)
+			}
+			if item.VariantID != nil {
+				variant, ok := product.Variant(*item.VariantID)
+				if !ok {
+					return fmt.Errorf("product %d: %w", product.ID, domain.ErrVariantNotFound)
+				}
+				if !variant.IsActive {
+					return fmt.Errorf("variant %d: %w", variant.ID, domain.ErrProductNotActive)
+				}
+			}
+			if err := product.Reserve(item.VariantID, item.Quantity); err != nil {
+				return fmt.Errorf("product %d: %w", product.ID, err)
+			}
+		}
+
+		if err := s.reservationRepo.Create(ctx, reservation); err != nil {
+			return err
+		}
+		return s.saveProducts(ctx, productIDs, products, available, reasonStockReserved, reservation)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toReservationResponse(reservation), nil
+}
+
+// ConfirmReservation takes the stock held for the basket or payment out of stock, by
+// warehouse priority as reservations carry no shipping destination. A reservation found
+// expired is released and ErrReservationExpired is returned.
+func (s *ReservationService) ConfirmReservation(ctx context.Context, referenceType, referenceID, actor string) (*ReservationResponse, error) {
+	var reservation *domain.StockReservation
+	expired := false
+
+	err := s.transactor.Within(ctx, func(ctx context.Context) error {
+		var err error
+		reservation, err = s.reservationRepo.GetActiveByReferenceForUpdate(ctx, domain.ReservationReference(referenceType), referenceID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if reservation.IsExpired(now) {
+			expired = true
+			return s.release(ctx, reservation, reasonReservationExpired, reservation.Expire)
+		}
+
+		products, _, err := s.lockProducts(ctx, reservation.ProductIDs())
+		if err != nil {
+			return err
+		}
+
+		if err := reservation.Confirm(now); err != nil {
+			return err
+		}
+		if err := s.reservationRepo.Update(ctx, reservation); err != nil {
+			return err
+		}
+
+		// Sold stock was not available before either, so no stock updated event is stored
+		for _, item := range reservation.Items {
+			change := domain.StockChange{
+				VariantID:     item.VariantID,
+				Reason:        domain.StockMovementReservation,
+				Actor:         actor,
+				PaymentID:     paymentIDOf(reservation),
+				ReservationID: &reservation.ID,
+			}
+			if err := products[item.ProductID].ConfirmReserved(item.Quantity, change); err != nil {
+				return fmt.Errorf("product %d: %w", item.ProductID, err)
+			}
+		}
+		for _, id := range reservation.ProductIDs() {
+			if err := s.productRepo.Update(ctx, products[id]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if expired {
+		return nil, domain.ErrReservationExpired
+	}
+
+	return toReservationResponse(reservation), nil
+}
+
+// ReleaseReservation gives the stock held for the basket or payment back
+func (s *ReservationService) ReleaseReservation(ctx context.Context, referenceType, referenceID string) (*ReservationResponse, error) {
+	var reservation *domain.StockReservation
+
+	err := s.transactor.Within(ctx, func(ctx context.Context) error {
+		var err error
+		reservation, err = s.reservationRepo.GetActiveByReferenceForUpdate(ctx, domain.ReservationReference(referenceType), referenceID)
+		if err != nil {
+			return err
+		}
+
+		return s.release(ctx, reservation, reasonReservationReleased, reservation.Release)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toReservationResponse(reservation), nil
+}
+
+// release ends the reservation with end and makes its stock available again
+func (s *ReservationService) release(ctx context.Context, reservation *domain.StockReservation, reason string, end func(now time.Time) error) error {
+	productIDs := reservation.ProductIDs()
+	products, available, err := s.lockProducts(ctx, productIDs)
+	if err != nil {
+		return err
+	}
+
+	if err := end(time.Now()); err != nil {
+		return err
+	}
+	if err := s.reservationRepo.Update(ctx, reservation); err != nil {
+		return err
+	}
+
+	for _, item := range reservation.Items {
+		if err := products[item.ProductID].ReleaseReserved(item.VariantID, item.Quantity); err != nil {
+			return err
+		}
+	}
+	return s.saveProducts(ctx, productIDs, products, available, reason, reservation)
+}
+
+// lockProducts locks the products in the given order and returns them with the available
+// stock of each product and its variants before the change
+func (s *ReservationService) lockProducts(ctx context.Context, productIDs []uint) (map[uint]*domain.Product, map[uint]map[uint]int, error) {
+	products := make(map[uint]*domain.Product, len(productIDs))
+	available := make(map[uint]map[uint]int, len(productIDs))
+	for _, id := range productIDs {
+		product, err := s.productRepo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return nil, nil, fmt.Errorf("product %d: %w", id, err)
+		}
+		products[id] = product
+		available[id] = product.AvailableStocks()
+	}
+	return products, available, nil
+}
+
+// saveProducts saves the products and stores a stock updated event for each product or
+// variant whose available stock changed
+func (s *ReservationService) saveProducts(ctx context.Context, productIDs []uint, products map[uint]*domain.Product, available map[uint]map[uint]int, reason string, reservation *domain.StockReservation) error {
+	paymentID := paymentIDOf(reservation)
+	for _, id := range productIDs {
+		product := products[id]
+		if err := s.productRepo.Update(ctx, product); err != nil {
+			return err
+		}
+
+		if err := publishStockChanges(ctx, s.eventPublisher, product, available[id], reason, paymentID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// paymentIDOf returns the ID of the payment the reservation is keyed by, if any
+func paymentIDOf(reservation *domain.StockReservation) *string {
+	if reservation.ReferenceType != domain.ReservationReferencePayment {
+		return nil
+	}
+	return &reservation.ReferenceID
+}
+
+// ttl returns the requested TTL bounded by the configuration, or the default TTL
+func (s *ReservationService) ttl(requested time.Duration) time.Duration {
+	if requested <= 0 {
+		return s.config.TTL
+	}
+	return min(requested, s.config.MaxTTL)
+}
+
+// Sweeper
+
+// Start starts releasing expired reservations in the background
+func (s *ReservationService) Start(ctx context.Context) error {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.config.SweepInterval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := s.ExpireDue(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Reservation sweep failed: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	log.Println("Reservation sweeper started")
+	return nil
+}
+
+// Stop stops the sweeper and waits for the reservations being released
+func (s *ReservationService) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+	log.Println("Reservation sweeper stopped")
+	return nil
+}
+
+// ExpireDue releases one batch of expired reservations, each in its own transaction,
+// and returns how many were released
+func (s *ReservationService) ExpireDue(ctx context.Context) (int, error) {
+	reservations, err := s.reservationRepo.ListExpired(ctx, time.Now(), s.config.SweepBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, listed := range reservations {
+		err := s.transactor.Within(ctx, func(ctx context.Context) error {
+			reservation, err := s.reservationRepo.GetByIDForUpdate(ctx, listed.ID)
+			if err != nil {
+				return err
+			}
+			// Confirmed, released or extended since it was listed
+			if !reservation.IsExpired(time.Now()) {
+				return nil
+			}
+
+			if err := s.release(ctx, reservation, reasonReservationExpired, reservation.Expire); err != nil {
+				return err
+			}
+			expired++
+			return nil
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return expired, ctx.Err()
+			}
+			log.Printf("Failed to expire stock reservation %s: %v", listed.ID, err)
+		}
+	}
+	return expired, nil
+}
+
+// mergeProductIDs merges two ascending lists of product IDs into one without duplicates
+func mergeProductIDs(a, b []uint) []uint {
+	merged := make([]uint, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j == len(b) || (i < len(a) && a[i] < b[j]):
+			merged = append(merged, a[i])
+			i++
+		case i == len(a) || b[j] < a[i]:
+			merged = append(merged, b[j])
+			j++
+		default:
+			merged = append(merged, a[i])
+			i++
+			j++
+		}
+	}
+	return merged
+}
+
+// toReservationResponse converts domain.StockReservation to ReservationResponse
+func toReservationResponse(reservation *domain.StockReservation) *ReservationResponse {
+	items := make([]ReservationItemResponse, 0, len(reservation.Items))
+	for _, item := range reservation.Items {
+		items = append(items, ReservationItemResponse{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Quantity:  item.Quantity,
+		})
+	}
+
+	return &ReservationResponse{
+		ID:            reservation.ID,
+		ReferenceType: string(reservation.ReferenceType),
+		ReferenceID:   reservation.ReferenceID,
+		Status:        string(reservation.Status),
+		Items:         items,
+		ExpiresAt:     reservation.ExpiresAt,
+		CreatedAt:     reservation.CreatedAt,
+		ConfirmedAt:   reservation.ConfirmedAt,
+		ReleasedAt:    reservation.ReleasedAt,
+	}
+}
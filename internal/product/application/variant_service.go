@@ -0,0 +1,273 @@
+package application
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ddd-micro/internal/product/domain"
+	productkafka "github.com/ddd-micro/internal/product/infrastructure/kafka"
+	"github.com/ddd-micro/pkg/gormtx"
+)
+
+// VariantService manages the variants of products. A variant keeps its own stock, changed
+// through its product like the stock of the product itself, so its product is locked for
+// every change.
+type VariantService struct {
+	productRepo    domain.ProductRepository
+	variantRepo    domain.VariantRepository
+	eventPublisher *productkafka.ProductEventPublisher
+	transactor     *gormtx.Transactor
+}
+
+// NewVariantService creates a new variant service
+func NewVariantService(
+	productRepo domain.ProductRepository,
+	variantRepo domain.VariantRepository,
+	eventPublisher *productkafka.ProductEventPublisher,
+	transactor *gormtx.Transactor,
+) *VariantService {
+	return &VariantService{
+		productRepo:    productRepo,
+		variantRepo:    variantRepo,
+		eventPublisher: eventPublisher,
+		transactor:     transactor,
+	}
+}
+
+// CreateVariant creates a new active variant of a product. Its initial stock is recorded
+// as a manual stock movement.
+func (s *VariantService) CreateVariant(ctx context.Context, productID uint, req CreateVariantRequest, actor string) (*VariantResponse, error) {
+	var product *domain.Product
+	variant := &domain.ProductVariant{
+		Name:      strings.TrimSpace(req.Name),
+		SKU:       strings.TrimSpace(req.SKU),
+		Weight:    req.Weight,
+		Color:     req.Color,
+		Size:      req.Size,
+		Material:  req.Material,
+		Image:     req.Image,
+		IsActive:  true,
+		SortOrder: req.SortOrder,
+	}
+
+	err := s.transactor.Within(ctx, func(ctx context.Context) error {
+		var err error
+		product, err = s.productRepo.GetByIDForUpdate(ctx, productID)
+		if err != nil {
+			return err
+		}
+
+		product.AddVariant(variant)
+		if err := variant.SetPrice(req.Price); err != nil {
+			return err
+		}
+		if err := variant.ValidateVariant(); err != nil {
+			return err
+		}
+		if err := s.variantRepo.Create(ctx, variant); err != nil {
+			return err
+		}
+
+		// Attach the empty levels of the new variant, so its stock can be put into them
+		if err := s.productRepo.LoadStockLevels(ctx, product); err != nil {
+			return err
+		}
+
+		available := product.AvailableStocks()
+		change := domain.StockChange{VariantID: &variant.ID, Reason: domain.StockMovementManual, Actor: actor}
+		if err := product.SetStock(req.Stock, change); err != nil {
+			return err
+		}
+		if err := s.productRepo.Update(ctx, product); err != nil {
+			return err
+		}
+
+		return publishStockChanges(ctx, s.eventPublisher, product, available, "stock_set", nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toVariantResponse(variant, product.StockLevelsOf(&variant.ID)), nil
+}
+
+// GetVariant retrieves a variant of a product with its stock per warehouse
+func (s *VariantService) GetVariant(ctx context.Context, productID, id uint) (*VariantResponse, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	variant, ok := product.Variant(id)
+	if !ok {
+		return nil, domain.ErrVariantNotFound
+	}
+
+	return toVariantResponse(variant, product.StockLevelsOf(&variant.ID)), nil
+}
+
+// ListVariants retrieves the variants of a product in sort order
+func (s *VariantService) ListVariants(ctx context.Context, productID uint) (*ListVariantsResponse, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	variants := toVariantResponses(product)
+	return &ListVariantsResponse{
+		Variants: variants,
+		Total:    len(variants),
+	}, nil
+}
+
+// UpdateVariant updates a variant of a product. A counted stock is set like the stock of
+// the product, recording the difference as a manual stock movement.
+func (s *VariantService) UpdateVariant(ctx context.Context, productID, id uint, req UpdateVariantRequest, actor string) (*VariantResponse, error) {
+	var product *domain.Product
+	var variant *domain.ProductVariant
+
+	err := s.transactor.Within(ctx, func(ctx context.Context) error {
+		var err error
+		product, err = s.productRepo.GetByIDForUpdate(ctx, productID)
+		if err != nil {
+			return err
+		}
+
+		var ok bool
+		variant, ok = product.Variant(id)
+		if !ok {
+			return domain.ErrVariantNotFound
+		}
+
+		if req.Name != nil {
+			variant.Name = strings.TrimSpace(*req.Name)
+		}
+		if req.SKU != nil && strings.TrimSpace(*req.SKU) != variant.SKU {
+			sku := strings.TrimSpace(*req.SKU)
+			exists, err := s.variantRepo.ExistsBySKU(ctx, sku)
+			if err != nil {
+				return err
+			}
+			if exists {
+				return domain.ErrVariantAlreadyExists
+			}
+			variant.SKU = sku
+		}
+		if req.Price != nil {
+			if err := variant.SetPrice(*req.Price); err != nil {
+				return err
+			}
+		}
+		if req.Weight != nil {
+			variant.Weight = *req.Weight
+		}
+		if req.Color != nil {
+			variant.Color = *req.Color
+		}
+		if req.Size != nil {
+			variant.Size = *req.Size
+		}
+		if req.Material != nil {
+			variant.Material = *req.Material
+		}
+		if req.Image != nil {
+			variant.Image = *req.Image
+		}
+		if req.SortOrder != nil {
+			variant.SetSortOrder(*req.SortOrder)
+		}
+		if req.IsActive != nil {
+			if *req.IsActive {
+				variant.Activate()
+			} else {
+				variant.Deactivate()
+			}
+		}
+
+		if err := variant.ValidateVariant(); err != nil {
+			return err
+		}
+		if err := s.variantRepo.Update(ctx, variant); err != nil {
+			return err
+		}
+
+		if req.Stock == nil {
+			return nil
+		}
+
+		available := product.AvailableStocks()
+		change := domain.StockChange{VariantID: &variant.ID, Reason: domain.StockMovementManual, Actor: actor}
+		if err := product.SetStock(*req.Stock, change); err != nil {
+			return err
+		}
+		if err := s.productRepo.Update(ctx, product); err != nil {
+			return err
+		}
+
+		return publishStockChanges(ctx, s.eventPublisher, product, available, "stock_set", nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toVariantResponse(variant, product.StockLevelsOf(&variant.ID)), nil
+}
+
+// DeleteVariant soft deletes a variant of a product that holds no stock
+func (s *VariantService) DeleteVariant(ctx context.Context, productID, id uint) error {
+	return s.transactor.Within(ctx, func(ctx context.Context) error {
+		product, err := s.productRepo.GetByIDForUpdate(ctx, productID)
+		if err != nil {
+			return err
+		}
+
+		variant, ok := product.Variant(id)
+		if !ok {
+			return domain.ErrVariantNotFound
+		}
+		if !variant.IsEmpty() {
+			return domain.ErrVariantNotEmpty
+		}
+
+		return s.variantRepo.Delete(ctx, productID, id)
+	})
+}
+
+// toVariantResponses converts the variants of a product to VariantResponses with their
+// stock per warehouse
+func toVariantResponses(product *domain.Product) []VariantResponse {
+	responses := make([]VariantResponse, 0, len(product.Variants))
+	for _, variant := range product.Variants {
+		responses = append(responses, *toVariantResponse(variant, product.StockLevelsOf(&variant.ID)))
+	}
+	return responses
+}
+
+// toVariantResponse converts domain.ProductVariant to VariantResponse with its effective
+// price and weight
+func toVariantResponse(variant *domain.ProductVariant, levels []*domain.StockLevel) *VariantResponse {
+	price := variant.GetEffectivePrice()
+	return &VariantResponse{
+		ID:             variant.ID,
+		ProductID:      variant.ProductID,
+		Name:           variant.Name,
+		SKU:            variant.SKU,
+		Price:          price.Major(),
+		PriceMinor:     price.Amount,
+		Currency:       price.Currency,
+		OwnPrice:       variant.PriceMinor > 0,
+		Stock:          variant.Stock,
+		ReservedStock:  variant.ReservedStock,
+		AvailableStock: variant.AvailableStock(),
+		Weight:         variant.GetEffectiveWeight(),
+		Color:          variant.Color,
+		Size:           variant.Size,
+		Material:       variant.Material,
+		Image:          variant.Image,
+		IsActive:       variant.IsActive,
+		SortOrder:      variant.SortOrder,
+		CreatedAt:      variant.CreatedAt,
+		UpdatedAt:      variant.UpdatedAt,
+		StockLevels:    toStockLevelResponses(levels),
+	}
+}
@@ -0,0 +1,181 @@
+package application
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ddd-micro/internal/product/domain"
+	"github.com/ddd-micro/pkg/gormtx"
+)
+
+// WarehouseService manages the warehouses stock is kept in
+type WarehouseService struct {
+	warehouseRepo  domain.WarehouseRepository
+	stockLevelRepo domain.StockLevelRepository
+	transactor     *gormtx.Transactor
+}
+
+// NewWarehouseService creates a new warehouse service
+func NewWarehouseService(warehouseRepo domain.WarehouseRepository, stockLevelRepo domain.StockLevelRepository, transactor *gormtx.Transactor) *WarehouseService {
+	return &WarehouseService{
+		warehouseRepo:  warehouseRepo,
+		stockLevelRepo: stockLevelRepo,
+		transactor:     transactor,
+	}
+}
+
+// CreateWarehouse creates a new active warehouse
+func (s *WarehouseService) CreateWarehouse(ctx context.Context, req CreateWarehouseRequest) (*WarehouseResponse, error) {
+	warehouse := &domain.Warehouse{
+		Code:      strings.ToUpper(strings.TrimSpace(req.Code)),
+		Name:      req.Name,
+		Address:   req.Address,
+		City:      req.City,
+		Country:   req.Country,
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+		Priority:  req.Priority,
+		IsActive:  true,
+	}
+
+	if err := warehouse.ValidateWarehouse(); err != nil {
+		return nil, err
+	}
+
+	if err := s.warehouseRepo.Create(ctx, warehouse); err != nil {
+		return nil, err
+	}
+
+	return toWarehouseResponse(warehouse), nil
+}
+
+// GetWarehouse retrieves a warehouse by ID
+func (s *WarehouseService) GetWarehouse(ctx context.Context, id uint) (*WarehouseResponse, error) {
+	warehouse, err := s.warehouseRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return toWarehouseResponse(warehouse), nil
+}
+
+// ListWarehouses retrieves warehouses by priority with pagination
+func (s *WarehouseService) ListWarehouses(ctx context.Context, req ListWarehousesRequest) (*ListWarehousesResponse, error) {
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+
+	warehouses, total, err := s.warehouseRepo.List(ctx, req.Offset, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]WarehouseResponse, 0, len(warehouses))
+	for _, warehouse := range warehouses {
+		responses = append(responses, *toWarehouseResponse(warehouse))
+	}
+
+	return &ListWarehousesResponse{
+		Warehouses: responses,
+		Total:      total,
+		Offset:     req.Offset,
+		Limit:      req.Limit,
+	}, nil
+}
+
+// UpdateWarehouse updates a warehouse. A warehouse can only be deactivated once it holds
+// no stock, so all stock counted as available can be shipped.
+func (s *WarehouseService) UpdateWarehouse(ctx context.Context, id uint, req UpdateWarehouseRequest) (*WarehouseResponse, error) {
+	var warehouse *domain.Warehouse
+
+	err := s.transactor.Within(ctx, func(ctx context.Context) error {
+		var err error
+		warehouse, err = s.warehouseRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if req.Name != nil {
+			warehouse.Name = *req.Name
+		}
+		if req.Address != nil {
+			warehouse.Address = *req.Address
+		}
+		if req.City != nil {
+			warehouse.City = *req.City
+		}
+		if req.Country != nil {
+			warehouse.Country = *req.Country
+		}
+		if req.Latitude != nil {
+			warehouse.Latitude = req.Latitude
+		}
+		if req.Longitude != nil {
+			warehouse.Longitude = req.Longitude
+		}
+		if req.Priority != nil {
+			warehouse.Priority = *req.Priority
+		}
+		if req.IsActive != nil && *req.IsActive != warehouse.IsActive {
+			if *req.IsActive {
+				warehouse.Activate()
+			} else {
+				if err := s.ensureEmpty(ctx, id); err != nil {
+					return err
+				}
+				warehouse.Deactivate()
+			}
+		}
+
+		if err := warehouse.ValidateWarehouse(); err != nil {
+			return err
+		}
+
+		return s.warehouseRepo.Update(ctx, warehouse)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toWarehouseResponse(warehouse), nil
+}
+
+// DeleteWarehouse soft deletes a warehouse that holds no stock
+func (s *WarehouseService) DeleteWarehouse(ctx context.Context, id uint) error {
+	return s.transactor.Within(ctx, func(ctx context.Context) error {
+		if err := s.ensureEmpty(ctx, id); err != nil {
+			return err
+		}
+		return s.warehouseRepo.Delete(ctx, id)
+	})
+}
+
+// ensureEmpty fails with ErrWarehouseNotEmpty while the warehouse holds stock
+func (s *WarehouseService) ensureEmpty(ctx context.Context, id uint) error {
+	total, err := s.stockLevelRepo.TotalByWarehouse(ctx, id)
+	if err != nil {
+		return err
+	}
+	if total != 0 {
+		return domain.ErrWarehouseNotEmpty
+	}
+	return nil
+}
+
+// toWarehouseResponse converts domain.Warehouse to WarehouseResponse
+func toWarehouseResponse(warehouse *domain.Warehouse) *WarehouseResponse {
+	return &WarehouseResponse{
+		ID:        warehouse.ID,
+		Code:      warehouse.Code,
+		Name:      warehouse.Name,
+		Address:   warehouse.Address,
+		City:      warehouse.City,
+		Country:   warehouse.Country,
+		Latitude:  warehouse.Latitude,
+		Longitude: warehouse.Longitude,
+		Priority:  warehouse.Priority,
+		IsActive:  warehouse.IsActive,
+		CreatedAt: warehouse.CreatedAt,
+		UpdatedAt: warehouse.UpdatedAt,
+	}
+}
@@ -1,20 +1,30 @@
 package domain
 
 import (
+	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 )
 
-// Category represents the product category domain entity
+// slugPattern matches lowercase words joined by single hyphens
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Category represents the product category domain entity. Categories form a tree; Path
+// lists the IDs from the root down to the category, e.g. "/1/4/9/", so a subtree is
+// every category whose path starts with the path of its root.
 type Category struct {
 	ID          uint           `gorm:"primaryKey" json:"id"`
 	Name        string         `gorm:"not null;size:255" json:"name"`
 	Description string         `gorm:"type:text" json:"description"`
-	Slug        string         `gorm:"uniqueIndex;not null;size:255" json:"slug"`
+	Slug        string         `gorm:"not null;size:255;uniqueIndex:idx_categories_slug,where:deleted_at IS NULL" json:"slug"`
 	ParentID    *uint          `gorm:"index" json:"parent_id"`
 	Parent      *Category      `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
 	Children    []Category     `gorm:"foreignKey:ParentID" json:"children,omitempty"`
+	Path        string         `gorm:"not null;size:1000;default:'';index" json:"path"` // Maintained by the repository
+	Depth       int            `gorm:"not null;default:0" json:"depth"`                 // Zero for root categories
 	Image       string         `gorm:"size:500" json:"image"`
 	Icon        string         `gorm:"size:100" json:"icon"`
 	SortOrder   int            `gorm:"default:0" json:"sort_order"`
@@ -36,7 +46,7 @@ func (c *Category) IsValidName() bool {
 
 // IsValidSlug checks if the slug is valid
 func (c *Category) IsValidSlug() bool {
-	return len(c.Slug) > 0 && len(c.Slug) <= 255
+	return len(c.Slug) > 0 && len(c.Slug) <= 255 && slugPattern.MatchString(c.Slug)
 }
 
 // IsRootCategory checks if this is a root category
@@ -68,3 +78,68 @@ func (c *Category) Deactivate() {
 func (c *Category) SetSortOrder(order int) {
 	c.SortOrder = order
 }
+
+// IsDescendantOf checks if the category lies in the subtree below another category
+func (c *Category) IsDescendantOf(other *Category) bool {
+	return other.Path != "" && c.Path != other.Path && strings.HasPrefix(c.Path, other.Path)
+}
+
+// CanMoveTo checks that the category can be placed under a parent, nil for the root;
+// a category cannot be placed under itself or one of its descendants
+func (c *Category) CanMoveTo(parent *Category) error {
+	if parent == nil {
+		return nil
+	}
+	if parent.ID == c.ID || parent.IsDescendantOf(c) {
+		return ErrInvalidCategoryMove
+	}
+	return nil
+}
+
+// ChildPath returns the path of a child of the category with the given ID
+func (c *Category) ChildPath(childID uint) string {
+	return CategoryPath(c.Path, childID)
+}
+
+// CategoryPath returns the path of a category below a parent path, "" for the root
+func CategoryPath(parentPath string, id uint) string {
+	if parentPath == "" {
+		parentPath = "/"
+	}
+	return fmt.Sprintf("%s%d/", parentPath, id)
+}
+
+// ValidateCategory validates all category fields
+func (c *Category) ValidateCategory() error {
+	if !c.IsValidName() {
+		return ErrInvalidCategoryData
+	}
+	if !c.IsValidSlug() {
+		return ErrInvalidCategoryData
+	}
+	return nil
+}
+
+// Slugify turns a name into a slug: lowercase ASCII letters and digits joined by hyphens
+func Slugify(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			hyphen = false
+		default:
+			hyphen = true
+		}
+	}
+
+	slug := b.String()
+	if len(slug) > 255 {
+		slug = strings.TrimRight(slug[:255], "-")
+	}
+	return slug
+}
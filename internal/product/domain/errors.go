@@ -3,10 +3,30 @@ package domain
 import "errors"
 
 var (
-	ErrProductNotFound      = errors.New("product not found")
-	ErrProductAlreadyExists = errors.New("product with this SKU already exists")
-	ErrInvalidStockAmount   = errors.New("invalid stock amount")
-	ErrInsufficientStock    = errors.New("insufficient stock")
-	ErrInvalidProductData   = errors.New("invalid product data")
-	ErrProductNotActive     = errors.New("product is not active")
+	ErrProductNotFound         = errors.New("product not found")
+	ErrProductAlreadyExists    = errors.New("product with this SKU already exists")
+	ErrInvalidStockAmount      = errors.New("invalid stock amount")
+	ErrInsufficientStock       = errors.New("insufficient stock")
+	ErrInvalidProductData      = errors.New("invalid product data")
+	ErrProductNotActive        = errors.New("product is not active")
+	ErrReservationNotFound     = errors.New("stock reservation not found")
+	ErrReservationExpired      = errors.New("stock reservation expired")
+	ErrReservationNotActive    = errors.New("stock reservation is no longer active")
+	ErrInvalidReservation      = errors.New("invalid stock reservation")
+	ErrInvalidStockMovement    = errors.New("invalid stock movement")
+	ErrStockMovementAppendOnly = errors.New("stock movements cannot be changed or deleted")
+	ErrWarehouseNotFound       = errors.New("warehouse not found")
+	ErrWarehouseAlreadyExists  = errors.New("warehouse with this code already exists")
+	ErrInvalidWarehouseData    = errors.New("invalid warehouse data")
+	ErrWarehouseNotEmpty       = errors.New("warehouse still holds stock")
+	ErrNoWarehouse             = errors.New("no active warehouse to hold the stock")
+	ErrDestinationRequired     = errors.New("nearest-first allocation requires a destination")
+	ErrCategoryNotFound        = errors.New("category not found")
+	ErrCategorySlugTaken       = errors.New("category with this slug already exists")
+	ErrInvalidCategoryData     = errors.New("invalid category data")
+	ErrInvalidCategoryMove     = errors.New("category cannot be moved below itself")
+	ErrCategoryNotEmpty        = errors.New("category still has subcategories or products")
+	ErrVariantNotFound         = errors.New("product variant not found")
+	ErrVariantAlreadyExists    = errors.New("product variant with this SKU already exists")
+	ErrVariantNotEmpty         = errors.New("product variant still holds stock")
 )
@@ -1,44 +1,67 @@
 package domain
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/ddd-micro/pkg/money"
 	"gorm.io/gorm"
 )
 
+// DefaultCurrency is the currency of products created without one
+const DefaultCurrency = "USD"
+
 // Product represents the product domain entity
 type Product struct {
-	ID               uint           `gorm:"primaryKey" json:"id"`
-	Name             string         `gorm:"not null;size:255" json:"name"`
-	Description      string         `gorm:"type:text" json:"description"`
-	ShortDescription string         `gorm:"size:500" json:"short_description"`
-	Price            float64        `gorm:"not null;type:decimal(10,2)" json:"price"`
-	ComparePrice     float64        `gorm:"type:decimal(10,2)" json:"compare_price"` // Original price for discount display
-	CostPrice        float64        `gorm:"type:decimal(10,2)" json:"cost_price"`    // Cost price for profit calculation
-	Stock            int            `gorm:"not null;default:0" json:"stock"`
-	MinStock         int            `gorm:"default:0" json:"min_stock"` // Minimum stock alert
-	MaxStock         int            `gorm:"default:0" json:"max_stock"` // Maximum stock limit
-	Category         string         `gorm:"size:100" json:"category"`
-	SubCategory      string         `gorm:"size:100" json:"sub_category"`
-	Brand            string         `gorm:"size:100" json:"brand"`
-	SKU              string         `gorm:"uniqueIndex;not null;size:100" json:"sku"`
-	Barcode          string         `gorm:"size:50" json:"barcode"`
-	Weight           float64        `gorm:"type:decimal(8,3)" json:"weight"` // Weight in kg
-	Dimensions       string         `gorm:"size:100" json:"dimensions"`      // LxWxH format
-	Color            string         `gorm:"size:50" json:"color"`
-	Size             string         `gorm:"size:50" json:"size"`
-	Material         string         `gorm:"size:100" json:"material"`
-	Tags             string         `gorm:"type:text" json:"tags"`   // Comma-separated tags
-	Images           string         `gorm:"type:text" json:"images"` // JSON array of image URLs
-	IsActive         bool           `gorm:"default:true" json:"is_active"`
-	IsDigital        bool           `gorm:"default:false" json:"is_digital"`  // Digital product flag
-	IsFeatured       bool           `gorm:"default:false" json:"is_featured"` // Featured product flag
-	IsOnSale         bool           `gorm:"default:false" json:"is_on_sale"`  // On sale flag
-	SortOrder        int            `gorm:"default:0" json:"sort_order"`      // For custom sorting
-	ViewCount        int            `gorm:"default:0" json:"view_count"`      // Product view counter
-	CreatedAt        time.Time      `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt        time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
-	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                uint           `gorm:"primaryKey" json:"id"`
+	Name              string         `gorm:"not null;size:255" json:"name"`
+	Description       string         `gorm:"type:text" json:"description"`
+	ShortDescription  string         `gorm:"size:500" json:"short_description"`
+	PriceMinor        int64          `gorm:"not null;default:0" json:"price_minor"`         // Price in minor units of Currency
+	ComparePriceMinor int64          `gorm:"not null;default:0" json:"compare_price_minor"` // Original price for discount display
+	CostPriceMinor    int64          `gorm:"not null;default:0" json:"cost_price_minor"`    // Cost price for profit calculation
+	Currency          string         `gorm:"size:3;not null;default:'USD'" json:"currency"`
+	Stock             int            `gorm:"not null;default:0" json:"stock"`
+	ReservedStock     int            `gorm:"not null;default:0" json:"reserved_stock"` // Held by active reservations, still part of Stock
+	MinStock          int            `gorm:"default:0" json:"min_stock"`               // Minimum stock alert
+	MaxStock          int            `gorm:"default:0" json:"max_stock"`               // Maximum stock limit
+	Category          string         `gorm:"size:100" json:"category"`
+	SubCategory       string         `gorm:"size:100" json:"sub_category"`
+	CategoryID        *uint          `gorm:"index" json:"category_id"` // Node of the category tree; Category and SubCategory are its legacy names
+	Brand             string         `gorm:"size:100" json:"brand"`
+	SKU               string         `gorm:"uniqueIndex;not null;size:100" json:"sku"`
+	Barcode           string         `gorm:"size:50" json:"barcode"`
+	Weight            float64        `gorm:"type:decimal(8,3)" json:"weight"` // Weight in kg
+	Dimensions        string         `gorm:"size:100" json:"dimensions"`      // LxWxH format
+	Color             string         `gorm:"size:50" json:"color"`
+	Size              string         `gorm:"size:50" json:"size"`
+	Material          string         `gorm:"size:100" json:"material"`
+	Tags              string         `gorm:"type:text" json:"tags"`   // Comma-separated tags
+	Images            string         `gorm:"type:text" json:"images"` // JSON array of image URLs
+	IsActive          bool           `gorm:"default:true" json:"is_active"`
+	IsDigital         bool           `gorm:"default:false" json:"is_digital"`  // Digital product flag
+	IsFeatured        bool           `gorm:"default:false" json:"is_featured"` // Featured product flag
+	IsOnSale          bool           `gorm:"default:false" json:"is_on_sale"`  // On sale flag
+	SortOrder         int            `gorm:"default:0" json:"sort_order"`      // For custom sorting
+	ViewCount         int            `gorm:"default:0" json:"view_count"`      // Product view counter
+	CreatedAt         time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Decimal copies of the prices for readers of the original columns, written on save
+	PriceDecimal        float64 `gorm:"column:price;not null;type:decimal(10,2)" json:"-"`
+	ComparePriceDecimal float64 `gorm:"column:compare_price;type:decimal(10,2)" json:"-"`
+	CostPriceDecimal    float64 `gorm:"column:cost_price;type:decimal(10,2)" json:"-"`
+
+	// Variants holds the variants of the product in sort order
+	Variants []*ProductVariant `gorm:"foreignKey:ProductID" json:"-"`
+
+	// StockLevels holds the stock of the product and its variants per warehouse, loaded by
+	// the repository when a single product is retrieved
+	StockLevels []*StockLevel `gorm:"-" json:"-"`
+
+	// stockMovements holds stock movements not yet stored by the repository
+	stockMovements []*StockMovement
 }
 
 // TableName specifies the table name for Product entity
@@ -46,6 +69,80 @@ func (Product) TableName() string {
 	return "products"
 }
 
+// BeforeSave keeps the decimal price columns in sync with the minor unit prices
+func (p *Product) BeforeSave(tx *gorm.DB) error {
+	p.PriceDecimal = p.Price().Major()
+	p.ComparePriceDecimal = p.ComparePrice().Major()
+	p.CostPriceDecimal = p.CostPrice().Major()
+	return nil
+}
+
+// AfterFind links loaded variants back to the product, which their price and weight
+// fall back to
+func (p *Product) AfterFind(tx *gorm.DB) error {
+	for _, variant := range p.Variants {
+		variant.Product = p
+	}
+	return nil
+}
+
+// Price returns the selling price
+func (p *Product) Price() money.Money {
+	return money.New(p.PriceMinor, p.Currency)
+}
+
+// ComparePrice returns the original price shown next to a discounted price
+func (p *Product) ComparePrice() money.Money {
+	return money.New(p.ComparePriceMinor, p.Currency)
+}
+
+// CostPrice returns the purchase cost of the product
+func (p *Product) CostPrice() money.Money {
+	return money.New(p.CostPriceMinor, p.Currency)
+}
+
+// ApplyPrices sets the prices from decimal amounts in the given currency. Nil amounts
+// keep the current price; when the currency changes they keep the same decimal value.
+func (p *Product) ApplyPrices(currency string, price, comparePrice, costPrice *float64) error {
+	if currency == "" {
+		currency = p.Currency
+	}
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	if !money.IsValidCurrency(currency) {
+		return fmt.Errorf("%w: unknown currency %q", ErrInvalidProductData, currency)
+	}
+
+	prices := []struct {
+		current money.Money
+		amount  *float64
+		target  *int64
+	}{
+		{p.Price(), price, &p.PriceMinor},
+		{p.ComparePrice(), comparePrice, &p.ComparePriceMinor},
+		{p.CostPrice(), costPrice, &p.CostPriceMinor},
+	}
+
+	for _, entry := range prices {
+		var converted money.Money
+		var err error
+		switch {
+		case entry.amount != nil:
+			converted, err = money.FromMajor(*entry.amount, currency)
+		default:
+			converted, err = money.Parse(entry.current.Decimal(), currency)
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidProductData, err)
+		}
+		*entry.target = converted.Amount
+	}
+
+	p.Currency = money.New(0, currency).Currency
+	return nil
+}
+
 // IsValidName checks if the product name is valid
 func (p *Product) IsValidName() bool {
 	return len(p.Name) > 0 && len(p.Name) <= 255
@@ -53,7 +150,7 @@ func (p *Product) IsValidName() bool {
 
 // IsValidPrice checks if the price is valid
 func (p *Product) IsValidPrice() bool {
-	return p.Price >= 0
+	return p.PriceMinor >= 0
 }
 
 // IsValidStock checks if the stock is valid
@@ -88,12 +185,12 @@ func (p *Product) IsValidShortDescription() bool {
 
 // IsValidComparePrice checks if the compare price is valid
 func (p *Product) IsValidComparePrice() bool {
-	return p.ComparePrice >= 0 && p.ComparePrice >= p.Price
+	return p.ComparePriceMinor >= 0 && p.ComparePriceMinor >= p.PriceMinor
 }
 
 // IsValidCostPrice checks if the cost price is valid
 func (p *Product) IsValidCostPrice() bool {
-	return p.CostPrice >= 0
+	return p.CostPriceMinor >= 0
 }
 
 // IsValidMinStock checks if the minimum stock is valid
@@ -116,30 +213,179 @@ func (p *Product) Deactivate() {
 	p.IsActive = false
 }
 
-// ReduceStock reduces the stock by the specified amount
-func (p *Product) ReduceStock(amount int) error {
+// ReduceStock reduces the stock of the product, or of the variant of the change, by the
+// specified amount, taking it from the warehouses in the allocation order of the change
+func (p *Product) ReduceStock(amount int, change StockChange) error {
 	if amount <= 0 {
 		return ErrInvalidStockAmount
 	}
-	if p.Stock < amount {
+	item, err := p.stockItem(change.VariantID)
+	if err != nil {
+		return err
+	}
+	if item.available() < amount {
 		return ErrInsufficientStock
 	}
-	p.Stock -= amount
+	return p.takeStock(item, amount, change)
+}
+
+// IncreaseStock increases the stock of the product, or of the variant of the change, by
+// the specified amount, putting it into the warehouse of the change or the first one in
+// allocation order
+func (p *Product) IncreaseStock(amount int, change StockChange) error {
+	if amount <= 0 {
+		return ErrInvalidStockAmount
+	}
+	item, err := p.stockItem(change.VariantID)
+	if err != nil {
+		return err
+	}
+	return p.putStock(item, amount, change)
+}
+
+// SetStock sets the stock of the product, or of the variant of the change, to a counted
+// amount, recording the difference
+func (p *Product) SetStock(stock int, change StockChange) error {
+	if stock < 0 {
+		return ErrInvalidStockAmount
+	}
+	item, err := p.stockItem(change.VariantID)
+	if err != nil {
+		return err
+	}
+	switch current := *item.stock; {
+	case stock > current:
+		return p.putStock(item, stock-current, change)
+	case stock < current:
+		return p.takeStock(item, current-stock, change)
+	}
 	return nil
 }
 
-// IncreaseStock increases the stock by the specified amount
-func (p *Product) IncreaseStock(amount int) error {
+// RebuildStock replaces the stock of the product, or of one of its variants, in every
+// warehouse with its balance in the stock ledger. It returns the drift, the stock the
+// projection had beyond the ledger, and whether any warehouse was corrected, which may
+// happen without the total drifting. Balances are keyed by warehouse; movements recorded
+// without a warehouse are keyed by 0.
+func (p *Product) RebuildStock(variantID *uint, balances map[uint]int) (int, bool, error) {
+	item, err := p.stockItem(variantID)
+	if err != nil {
+		return 0, false, err
+	}
+
+	previous := *item.stock
+	*item.stock = 0
+	for warehouseID, balance := range balances {
+		*item.stock += balance
+		if warehouseID == 0 {
+			continue
+		}
+		if _, ok := p.StockLevel(variantID, warehouseID); !ok {
+			p.StockLevels = append(p.StockLevels, &StockLevel{ProductID: p.ID, VariantID: item.variantID, WarehouseID: warehouseID})
+		}
+	}
+
+	rebuilt := previous != *item.stock
+	for _, level := range p.StockLevelsOf(variantID) {
+		if balance := balances[level.WarehouseID]; level.Stock != balance {
+			level.Stock = balance
+			level.changed = true
+			rebuilt = true
+		}
+	}
+	return previous - *item.stock, rebuilt, nil
+}
+
+// AvailableStock returns the stock that is neither sold nor held by a reservation
+func (p *Product) AvailableStock() int {
+	return max(p.Stock-p.ReservedStock, 0)
+}
+
+// StockOf returns the stock of the product, or of one of its variants; an unknown variant
+// has none
+func (p *Product) StockOf(variantID *uint) int {
+	item, err := p.stockItem(variantID)
+	if err != nil {
+		return 0
+	}
+	return *item.stock
+}
+
+// AvailableStockOf returns the available stock of the product, or of one of its variants;
+// an unknown variant has none
+func (p *Product) AvailableStockOf(variantID *uint) int {
+	item, err := p.stockItem(variantID)
+	if err != nil {
+		return 0
+	}
+	return item.available()
+}
+
+// AvailableStocks returns the available stock of the product, keyed by 0, and of each of
+// its variants, keyed by variant ID
+func (p *Product) AvailableStocks() map[uint]int {
+	stocks := make(map[uint]int, len(p.Variants)+1)
+	stocks[0] = p.AvailableStock()
+	for _, variant := range p.Variants {
+		stocks[variant.ID] = variant.AvailableStock()
+	}
+	return stocks
+}
+
+// Reserve holds the specified amount of the available stock of the product, or of one of
+// its variants
+func (p *Product) Reserve(variantID *uint, amount int) error {
+	if amount <= 0 {
+		return ErrInvalidStockAmount
+	}
+	item, err := p.stockItem(variantID)
+	if err != nil {
+		return err
+	}
+	if item.available() < amount {
+		return ErrInsufficientStock
+	}
+	*item.reserved += amount
+	return nil
+}
+
+// ReleaseReserved makes the specified amount of held stock of the product, or of one of
+// its variants, available again
+func (p *Product) ReleaseReserved(variantID *uint, amount int) error {
+	if amount <= 0 {
+		return ErrInvalidStockAmount
+	}
+	item, err := p.stockItem(variantID)
+	if err != nil {
+		return err
+	}
+	*item.reserved = max(*item.reserved-amount, 0)
+	return nil
+}
+
+// ConfirmReserved takes the specified amount of held stock of the product, or of the
+// variant of the change, out of stock
+func (p *Product) ConfirmReserved(amount int, change StockChange) error {
 	if amount <= 0 {
 		return ErrInvalidStockAmount
 	}
-	p.Stock += amount
+	item, err := p.stockItem(change.VariantID)
+	if err != nil {
+		return err
+	}
+	if *item.stock < amount {
+		return ErrInsufficientStock
+	}
+	if err := p.takeStock(item, amount, change); err != nil {
+		return err
+	}
+	*item.reserved = max(*item.reserved-amount, 0)
 	return nil
 }
 
 // IsInStock checks if the product is in stock
 func (p *Product) IsInStock() bool {
-	return p.Stock > 0 && p.IsActive
+	return p.AvailableStock() > 0 && p.IsActive
 }
 
 // IsAvailable checks if the product is available for purchase
@@ -147,9 +393,10 @@ func (p *Product) IsAvailable() bool {
 	return p.IsActive && p.IsInStock()
 }
 
-// IsLowStock checks if the product stock is below minimum threshold
+// IsLowStock checks if the product stock is below minimum threshold, overall or in
+// any warehouse
 func (p *Product) IsLowStock() bool {
-	return p.Stock <= p.MinStock && p.MinStock > 0
+	return (p.Stock <= p.MinStock && p.MinStock > 0) || len(p.LowStockLevels()) > 0
 }
 
 // IsOverStock checks if the product stock exceeds maximum limit
@@ -159,23 +406,23 @@ func (p *Product) IsOverStock() bool {
 
 // GetDiscountPercentage calculates the discount percentage
 func (p *Product) GetDiscountPercentage() float64 {
-	if p.ComparePrice <= 0 || p.ComparePrice <= p.Price {
+	if p.ComparePriceMinor <= 0 || p.ComparePriceMinor <= p.PriceMinor {
 		return 0
 	}
-	return ((p.ComparePrice - p.Price) / p.ComparePrice) * 100
+	return float64(p.ComparePriceMinor-p.PriceMinor) / float64(p.ComparePriceMinor) * 100
 }
 
 // GetProfitMargin calculates the profit margin
 func (p *Product) GetProfitMargin() float64 {
-	if p.CostPrice <= 0 {
+	if p.CostPriceMinor <= 0 {
 		return 0
 	}
-	return ((p.Price - p.CostPrice) / p.CostPrice) * 100
+	return float64(p.PriceMinor-p.CostPriceMinor) / float64(p.CostPriceMinor) * 100
 }
 
 // GetProfitAmount calculates the profit amount per unit
-func (p *Product) GetProfitAmount() float64 {
-	return p.Price - p.CostPrice
+func (p *Product) GetProfitAmount() money.Money {
+	return money.New(p.PriceMinor-p.CostPriceMinor, p.Currency)
 }
 
 // MarkAsFeatured marks the product as featured
@@ -208,6 +455,16 @@ func (p *Product) SetSortOrder(order int) {
 	p.SortOrder = order
 }
 
+// AssignCategory links the product to a node of the category tree, nil to unlink it
+func (p *Product) AssignCategory(category *Category) {
+	if category == nil {
+		p.CategoryID = nil
+		return
+	}
+	id := category.ID
+	p.CategoryID = &id
+}
+
 // IsDigitalProduct checks if the product is digital
 func (p *Product) IsDigitalProduct() bool {
 	return p.IsDigital
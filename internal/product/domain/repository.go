@@ -1,36 +1,167 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // ProductRepository defines the interface for product data operations
 type ProductRepository interface {
-	// Create creates a new product
+	// Create creates a new product with its stock levels and pending stock movements
 	Create(ctx context.Context, product *Product) error
 
-	// GetByID retrieves a product by ID
+	// GetByID retrieves a product by ID with its variants and stock levels
 	GetByID(ctx context.Context, id uint) (*Product, error)
 
+	// GetByIDForUpdate retrieves a product by ID with its variants and stock levels and
+	// locks it for the current transaction
+	GetByIDForUpdate(ctx context.Context, id uint) (*Product, error)
+
 	// GetBySKU retrieves a product by SKU
 	GetBySKU(ctx context.Context, sku string) (*Product, error)
 
-	// Update updates an existing product
+	// Update updates an existing product and stores the stock of its variants, its changed
+	// stock levels and pending stock movements
 	Update(ctx context.Context, product *Product) error
 
+	// LoadStockLevels attaches the stock levels of a product and its variants in every warehouse
+	LoadStockLevels(ctx context.Context, product *Product) error
+
 	// Delete soft deletes a product
 	Delete(ctx context.Context, id uint) error
 
 	// List retrieves all products with pagination
 	List(ctx context.Context, offset, limit int) ([]*Product, error)
 
-	// ListByCategory retrieves products by category with pagination
+	// ListByCategory retrieves products by legacy category name with pagination
 	ListByCategory(ctx context.Context, category string, offset, limit int) ([]*Product, error)
 
+	// ListByCategoryTree retrieves products of a category and of its descendants with pagination
+	ListByCategoryTree(ctx context.Context, category *Category, offset, limit int) ([]*Product, error)
+
 	// SearchByName searches products by name with pagination
 	SearchByName(ctx context.Context, name string, offset, limit int) ([]*Product, error)
 
 	// Exists checks if a product exists by SKU
 	Exists(ctx context.Context, sku string) (bool, error)
 
-	// UpdateStock updates the stock of a product
-	UpdateStock(ctx context.Context, id uint, stock int) error
+	// ListIDs retrieves the IDs of products after the given ID in ascending order
+	ListIDs(ctx context.Context, afterID uint, limit int) ([]uint, error)
+}
+
+// VariantRepository defines the interface for product variant data operations. Variants
+// are read with their product, and their stock is stored with it by ProductRepository.
+type VariantRepository interface {
+	// Create creates a new variant without stock
+	Create(ctx context.Context, variant *ProductVariant) error
+
+	// Update updates the fields of a variant, leaving its stock unchanged
+	Update(ctx context.Context, variant *ProductVariant) error
+
+	// Delete soft deletes a variant of a product and removes its stock levels
+	Delete(ctx context.Context, productID, id uint) error
+
+	// ExistsBySKU checks if a variant exists by SKU, including deleted ones
+	ExistsBySKU(ctx context.Context, sku string) (bool, error)
+}
+
+// StockReservationRepository defines the interface for stock reservation data operations
+type StockReservationRepository interface {
+	// Create creates a new reservation with its items
+	Create(ctx context.Context, reservation *StockReservation) error
+
+	// GetByIDForUpdate retrieves a reservation with its items and locks it for the current transaction
+	GetByIDForUpdate(ctx context.Context, id string) (*StockReservation, error)
+
+	// GetActiveByReferenceForUpdate retrieves the active reservation of a basket or payment
+	// with its items and locks it for the current transaction
+	GetActiveByReferenceForUpdate(ctx context.Context, referenceType ReservationReference, referenceID string) (*StockReservation, error)
+
+	// Update updates the status of a reservation
+	Update(ctx context.Context, reservation *StockReservation) error
+
+	// ListExpired retrieves active reservations that expired at or before now, oldest first
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]*StockReservation, error)
+}
+
+// StockMovementRepository defines the interface for the append-only stock ledger
+type StockMovementRepository interface {
+	// List retrieves stock movements, newest first, with pagination and filters
+	List(ctx context.Context, filter StockMovementFilter, offset, limit int) ([]*StockMovement, int, error)
+
+	// Balances sums the movements of the stock of a product, or of one of its variants, per
+	// warehouse; movements without a warehouse are summed under 0
+	Balances(ctx context.Context, productID uint, variantID *uint) (map[uint]int, error)
+}
+
+// CategoryRepository defines the interface for category tree data operations
+type CategoryRepository interface {
+	// Create creates a new category below its parent, setting its path and depth
+	Create(ctx context.Context, category *Category) error
+
+	// GetByID retrieves a category by ID
+	GetByID(ctx context.Context, id uint) (*Category, error)
+
+	// GetBySlug retrieves a category by slug
+	GetBySlug(ctx context.Context, slug string) (*Category, error)
+
+	// Update updates the fields of a category; its place in the tree is changed by Move
+	Update(ctx context.Context, category *Category) error
+
+	// Delete soft deletes a category
+	Delete(ctx context.Context, id uint) error
+
+	// SlugExists checks if another category uses the slug
+	SlugExists(ctx context.Context, slug string, excludeID uint) (bool, error)
+
+	// ListChildren retrieves the children of a category, or the root categories for nil,
+	// in sort order
+	ListChildren(ctx context.Context, parentID *uint) ([]*Category, error)
+
+	// ListTree retrieves a category and its descendants, or every category for nil,
+	// ordered by depth and sort order
+	ListTree(ctx context.Context, root *Category, includeInactive bool) ([]*Category, error)
+
+	// Move places a category and its subtree below a parent, or at the root for nil
+	Move(ctx context.Context, category *Category, parent *Category) error
+
+	// UpdateSortOrders sets the sort order of categories by ID
+	UpdateSortOrders(ctx context.Context, orders map[uint]int) error
+
+	// HasProducts checks if products are linked to the category
+	HasProducts(ctx context.Context, id uint) (bool, error)
+}
+
+// WarehouseRepository defines the interface for warehouse data operations
+type WarehouseRepository interface {
+	// Create creates a new warehouse
+	Create(ctx context.Context, warehouse *Warehouse) error
+
+	// GetByID retrieves a warehouse by ID
+	GetByID(ctx context.Context, id uint) (*Warehouse, error)
+
+	// Update updates an existing warehouse
+	Update(ctx context.Context, warehouse *Warehouse) error
+
+	// Delete soft deletes a warehouse and its empty stock levels
+	Delete(ctx context.Context, id uint) error
+
+	// List retrieves warehouses by priority with pagination
+	List(ctx context.Context, offset, limit int) ([]*Warehouse, int, error)
+
+	// ExistsByCode checks if a warehouse exists by code
+	ExistsByCode(ctx context.Context, code string) (bool, error)
+}
+
+// StockLevelRepository defines the interface for reading stock levels; they are written
+// with their product by ProductRepository
+type StockLevelRepository interface {
+	// ListByProduct retrieves the stock levels of a product and its variants with their warehouses
+	ListByProduct(ctx context.Context, productID uint) ([]*StockLevel, error)
+
+	// ListLowStock retrieves stock levels at or below their threshold with pagination
+	ListLowStock(ctx context.Context, warehouseID *uint, offset, limit int) ([]*StockLevel, int, error)
+
+	// TotalByWarehouse sums the stock held in a warehouse
+	TotalByWarehouse(ctx context.Context, warehouseID uint) (int, error)
 }
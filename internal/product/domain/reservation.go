@@ -0,0 +1,187 @@
+package domain
+
+import (
+	"slices"
+	"sort"
+	"time"
+)
+
+// ReservationStatus represents the status of a stock reservation
+type ReservationStatus string
+
+const (
+	ReservationStatusActive    ReservationStatus = "active"
+	ReservationStatusConfirmed ReservationStatus = "confirmed"
+	ReservationStatusReleased  ReservationStatus = "released"
+	ReservationStatusExpired   ReservationStatus = "expired"
+)
+
+// ReservationReference is the kind of ID a stock reservation is keyed by
+type ReservationReference string
+
+const (
+	ReservationReferenceBasket  ReservationReference = "basket"
+	ReservationReferencePayment ReservationReference = "payment"
+)
+
+// IsValid checks if the reference type is known
+func (r ReservationReference) IsValid() bool {
+	return r == ReservationReferenceBasket || r == ReservationReferencePayment
+}
+
+// StockReservation holds stock of its products for a basket or payment until it is
+// confirmed, released or expires. Held stock stays part of Product.Stock but is counted
+// in Product.ReservedStock, so it cannot be sold to anyone else.
+type StockReservation struct {
+	ID            string                 `gorm:"primaryKey;type:varchar(36)" json:"id"`
+	ReferenceType ReservationReference   `gorm:"type:varchar(20);not null;uniqueIndex:idx_stock_reservations_active_reference,where:status = 'active'" json:"reference_type"`
+	ReferenceID   string                 `gorm:"type:varchar(36);not null;uniqueIndex:idx_stock_reservations_active_reference" json:"reference_id"`
+	Status        ReservationStatus      `gorm:"type:varchar(20);not null;default:'active';index" json:"status"`
+	Items         []StockReservationItem `gorm:"foreignKey:ReservationID;constraint:OnDelete:CASCADE" json:"items"`
+	ExpiresAt     time.Time              `gorm:"not null;index" json:"expires_at"`
+	CreatedAt     time.Time              `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time              `gorm:"autoUpdateTime" json:"updated_at"`
+	ConfirmedAt   *time.Time             `json:"confirmed_at"`
+	ReleasedAt    *time.Time             `json:"released_at"` // Set when released or expired
+}
+
+// StockReservationItem is the quantity of a product, or of one of its variants, held by
+// a reservation
+type StockReservationItem struct {
+	ID            uint   `gorm:"primaryKey" json:"id"`
+	ReservationID string `gorm:"type:varchar(36);not null;index" json:"reservation_id"`
+	ProductID     uint   `gorm:"not null;index" json:"product_id"`
+	VariantID     *uint  `json:"variant_id"` // Nil for the product itself
+	Quantity      int    `gorm:"not null" json:"quantity"`
+}
+
+// TableName specifies the table name for StockReservation entity
+func (StockReservation) TableName() string {
+	return "stock_reservations"
+}
+
+// TableName specifies the table name for StockReservationItem entity
+func (StockReservationItem) TableName() string {
+	return "stock_reservation_items"
+}
+
+// NewStockReservation creates an active reservation of the given items. Items of the
+// same product and variant are merged and sorted by product, the order their stock is
+// locked in, and then by variant.
+func NewStockReservation(id string, referenceType ReservationReference, referenceID string, items []StockReservationItem, expiresAt time.Time) (*StockReservation, error) {
+	if id == "" || !referenceType.IsValid() || referenceID == "" || len(items) == 0 {
+		return nil, ErrInvalidReservation
+	}
+
+	type key struct {
+		productID uint
+		variantID uint // 0 for the product itself
+	}
+	quantities := make(map[key]int)
+	for _, item := range items {
+		if item.ProductID == 0 || (item.VariantID != nil && *item.VariantID == 0) {
+			return nil, ErrInvalidReservation
+		}
+		if item.Quantity <= 0 {
+			return nil, ErrInvalidStockAmount
+		}
+		k := key{productID: item.ProductID}
+		if item.VariantID != nil {
+			k.variantID = *item.VariantID
+		}
+		quantities[k] += item.Quantity
+	}
+
+	reservation := &StockReservation{
+		ID:            id,
+		ReferenceType: referenceType,
+		ReferenceID:   referenceID,
+		Status:        ReservationStatusActive,
+		ExpiresAt:     expiresAt,
+	}
+	for k, quantity := range quantities {
+		item := StockReservationItem{
+			ReservationID: id,
+			ProductID:     k.productID,
+			Quantity:      quantity,
+		}
+		if k.variantID != 0 {
+			variantID := k.variantID
+			item.VariantID = &variantID
+		}
+		reservation.Items = append(reservation.Items, item)
+	}
+	sort.Slice(reservation.Items, func(i, j int) bool {
+		a, b := reservation.Items[i], reservation.Items[j]
+		if a.ProductID != b.ProductID {
+			return a.ProductID < b.ProductID
+		}
+		return variantOrder(a.VariantID) < variantOrder(b.VariantID)
+	})
+
+	return reservation, nil
+}
+
+// IsActive checks if the reservation still holds its stock
+func (r *StockReservation) IsActive() bool {
+	return r.Status == ReservationStatusActive
+}
+
+// IsExpired checks if an active reservation has passed its expiry time
+func (r *StockReservation) IsExpired(now time.Time) bool {
+	return r.IsActive() && !now.Before(r.ExpiresAt)
+}
+
+// ProductIDs returns the IDs of the reserved products in ascending order, once each
+func (r *StockReservation) ProductIDs() []uint {
+	ids := make([]uint, 0, len(r.Items))
+	for _, item := range r.Items {
+		ids = append(ids, item.ProductID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return slices.Compact(ids)
+}
+
+// variantOrder orders the stock of a product before the stock of its variants
+func variantOrder(variantID *uint) uint {
+	if variantID == nil {
+		return 0
+	}
+	return *variantID
+}
+
+// Confirm marks the held stock as sold
+func (r *StockReservation) Confirm(now time.Time) error {
+	if !r.IsActive() {
+		return ErrReservationNotActive
+	}
+	if r.IsExpired(now) {
+		return ErrReservationExpired
+	}
+	r.Status = ReservationStatusConfirmed
+	r.ConfirmedAt = &now
+	return nil
+}
+
+// Release gives the held stock back before the reservation expires
+func (r *StockReservation) Release(now time.Time) error {
+	return r.end(ReservationStatusReleased, now)
+}
+
+// Expire gives the held stock back once the reservation has expired
+func (r *StockReservation) Expire(now time.Time) error {
+	if !r.IsExpired(now) {
+		return ErrReservationNotActive
+	}
+	return r.end(ReservationStatusExpired, now)
+}
+
+// end ends an active reservation without selling its stock
+func (r *StockReservation) end(status ReservationStatus, now time.Time) error {
+	if !r.IsActive() {
+		return ErrReservationNotActive
+	}
+	r.Status = status
+	r.ReleasedAt = &now
+	return nil
+}
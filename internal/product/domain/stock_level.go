@@ -0,0 +1,311 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// AllocationStrategy decides which warehouses stock is taken from first
+type AllocationStrategy string
+
+const (
+	// AllocationPriority takes stock from the warehouse with the lowest priority first
+	AllocationPriority AllocationStrategy = "priority"
+	// AllocationNearest takes stock from the warehouse nearest to the destination first.
+	// Changes without a destination are rejected.
+	AllocationNearest AllocationStrategy = "nearest"
+)
+
+// IsValid checks if the strategy is known
+func (s AllocationStrategy) IsValid() bool {
+	return s == AllocationPriority || s == AllocationNearest
+}
+
+// StockLevel is the stock of a product, or of one of its variants, kept in a warehouse.
+// The stock of the product is the sum of its levels across warehouses.
+type StockLevel struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	ProductID   uint       `gorm:"not null;index;uniqueIndex:idx_stock_levels_product_warehouse,where:variant_id IS NULL" json:"product_id"`
+	VariantID   *uint      `gorm:"uniqueIndex:idx_stock_levels_variant_warehouse,where:variant_id IS NOT NULL" json:"variant_id"` // Nil for the stock of the product itself
+	WarehouseID uint       `gorm:"not null;index;uniqueIndex:idx_stock_levels_product_warehouse;uniqueIndex:idx_stock_levels_variant_warehouse" json:"warehouse_id"`
+	Warehouse   *Warehouse `gorm:"foreignKey:WarehouseID" json:"warehouse,omitempty"`
+	Stock       int        `gorm:"not null;default:0" json:"stock"`
+	MinStock    int        `gorm:"not null;default:0" json:"min_stock"` // Low stock alert threshold of the warehouse
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// changed marks levels the repository has to store
+	changed bool
+}
+
+// TableName specifies the table name for StockLevel entity
+func (StockLevel) TableName() string {
+	return "stock_levels"
+}
+
+// IsLowStock checks if the stock of the warehouse is at or below its threshold
+func (l *StockLevel) IsLowStock() bool {
+	return l.MinStock > 0 && l.Stock <= l.MinStock
+}
+
+// IsShippable checks if stock can be put into or taken from the warehouse
+func (l *StockLevel) IsShippable() bool {
+	return l.Warehouse != nil && l.Warehouse.IsActive
+}
+
+// stockItem is the stock the product keeps of itself or of one of its variants
+type stockItem struct {
+	variantID *uint // Nil for the product itself
+	stock     *int
+	reserved  *int
+}
+
+// available returns the stock of the item that is neither sold nor held by a reservation
+func (i stockItem) available() int {
+	return max(*i.stock-*i.reserved, 0)
+}
+
+// stockItem returns the stock of the product for a nil variant ID, or of the variant
+func (p *Product) stockItem(variantID *uint) (stockItem, error) {
+	if variantID == nil {
+		return stockItem{stock: &p.Stock, reserved: &p.ReservedStock}, nil
+	}
+	variant, ok := p.Variant(*variantID)
+	if !ok {
+		return stockItem{}, ErrVariantNotFound
+	}
+	return variantStockItem(variant), nil
+}
+
+// stockItems returns the stock of the product followed by the stock of its variants
+func (p *Product) stockItems() []stockItem {
+	items := []stockItem{{stock: &p.Stock, reserved: &p.ReservedStock}}
+	for _, variant := range p.Variants {
+		items = append(items, variantStockItem(variant))
+	}
+	return items
+}
+
+// variantStockItem returns the stock of a variant
+func variantStockItem(variant *ProductVariant) stockItem {
+	id := variant.ID
+	return stockItem{variantID: &id, stock: &variant.Stock, reserved: &variant.ReservedStock}
+}
+
+// sameVariant checks if two variant IDs name the same stock, nil being the product itself
+func sameVariant(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// AttachStockLevels sets the stock levels of the product and its variants. Active
+// warehouses the product or a variant has no level in get an empty one, so stock can be
+// put into any of them.
+func (p *Product) AttachStockLevels(levels []*StockLevel, warehouses []*Warehouse) {
+	p.StockLevels = levels
+	for _, item := range p.stockItems() {
+		for _, warehouse := range warehouses {
+			if _, ok := p.StockLevel(item.variantID, warehouse.ID); !ok && warehouse.IsActive {
+				p.StockLevels = append(p.StockLevels, &StockLevel{
+					ProductID:   p.ID,
+					VariantID:   item.variantID,
+					WarehouseID: warehouse.ID,
+					Warehouse:   warehouse,
+				})
+			}
+		}
+	}
+}
+
+// StockLevel returns the level of the product, or of one of its variants, in a warehouse
+func (p *Product) StockLevel(variantID *uint, warehouseID uint) (*StockLevel, bool) {
+	for _, level := range p.StockLevels {
+		if level.WarehouseID == warehouseID && sameVariant(level.VariantID, variantID) {
+			return level, true
+		}
+	}
+	return nil, false
+}
+
+// StockLevelsOf returns the levels of the product, or of one of its variants, across
+// warehouses
+func (p *Product) StockLevelsOf(variantID *uint) []*StockLevel {
+	var levels []*StockLevel
+	for _, level := range p.StockLevels {
+		if sameVariant(level.VariantID, variantID) {
+			levels = append(levels, level)
+		}
+	}
+	return levels
+}
+
+// PullChangedStockLevels returns the stock levels changed since the product was loaded
+// and clears their mark, so the repository stores each change once
+func (p *Product) PullChangedStockLevels() []*StockLevel {
+	var changed []*StockLevel
+	for _, level := range p.StockLevels {
+		if level.changed {
+			level.ProductID = p.ID
+			level.changed = false
+			changed = append(changed, level)
+		}
+	}
+	return changed
+}
+
+// LowStockLevels returns the warehouses the stock of the product or of one of its
+// variants is low in
+func (p *Product) LowStockLevels() []*StockLevel {
+	var low []*StockLevel
+	for _, level := range p.StockLevels {
+		if level.IsLowStock() {
+			low = append(low, level)
+		}
+	}
+	return low
+}
+
+// SetWarehouseStock sets the stock of the product, or of the variant of the change, in a
+// warehouse to a counted amount, recording the difference
+func (p *Product) SetWarehouseStock(warehouseID uint, stock int, change StockChange) error {
+	if stock < 0 {
+		return ErrInvalidStockAmount
+	}
+	item, err := p.stockItem(change.VariantID)
+	if err != nil {
+		return err
+	}
+	level, ok := p.StockLevel(item.variantID, warehouseID)
+	if !ok || !level.IsShippable() {
+		return ErrWarehouseNotFound
+	}
+	if stock == level.Stock {
+		return nil
+	}
+
+	delta := stock - level.Stock
+	if err := p.recordStockMovement(item.variantID, delta, level.WarehouseID, change); err != nil {
+		return err
+	}
+	level.Stock = stock
+	level.changed = true
+	*item.stock += delta
+	return nil
+}
+
+// SetWarehouseMinStock sets the low stock threshold of the product, or of one of its
+// variants, in a warehouse
+func (p *Product) SetWarehouseMinStock(variantID *uint, warehouseID uint, minStock int) error {
+	if minStock < 0 {
+		return ErrInvalidStockAmount
+	}
+	if _, err := p.stockItem(variantID); err != nil {
+		return err
+	}
+	level, ok := p.StockLevel(variantID, warehouseID)
+	if !ok {
+		return ErrWarehouseNotFound
+	}
+	level.MinStock = minStock
+	level.changed = true
+	return nil
+}
+
+// allocationOrder returns the levels of the item stock is taken from or put into, first
+// one first. A change naming a warehouse only uses that warehouse.
+func (p *Product) allocationOrder(item stockItem, change StockChange) ([]*StockLevel, error) {
+	if change.WarehouseID != nil {
+		level, ok := p.StockLevel(item.variantID, *change.WarehouseID)
+		if !ok || !level.IsShippable() {
+			return nil, ErrWarehouseNotFound
+		}
+		return []*StockLevel{level}, nil
+	}
+
+	var levels []*StockLevel
+	for _, level := range p.StockLevelsOf(item.variantID) {
+		if level.IsShippable() {
+			levels = append(levels, level)
+		}
+	}
+	if len(levels) == 0 {
+		return nil, ErrNoWarehouse
+	}
+
+	byPriority := func(a, b *Warehouse) bool {
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return a.ID < b.ID
+	}
+	sort.SliceStable(levels, func(i, j int) bool {
+		a, b := levels[i].Warehouse, levels[j].Warehouse
+		if change.Strategy == AllocationNearest && change.Destination != nil {
+			da, db := a.DistanceTo(*change.Destination), b.DistanceTo(*change.Destination)
+			if da != db {
+				return da < db
+			}
+		}
+		return byPriority(a, b)
+	})
+	return levels, nil
+}
+
+// takeStock takes an amount of the item out of the warehouses in allocation order,
+// recording a movement per warehouse
+func (p *Product) takeStock(item stockItem, amount int, change StockChange) error {
+	if change.Strategy == AllocationNearest && change.WarehouseID == nil && change.Destination == nil {
+		return ErrDestinationRequired
+	}
+	levels, err := p.allocationOrder(item, change)
+	if err != nil {
+		return err
+	}
+
+	shippable := 0
+	for _, level := range levels {
+		shippable += level.Stock
+	}
+	if shippable < amount {
+		return ErrInsufficientStock
+	}
+
+	remaining := amount
+	for _, level := range levels {
+		taken := min(level.Stock, remaining)
+		if taken <= 0 {
+			continue
+		}
+		if err := p.recordStockMovement(item.variantID, -taken, level.WarehouseID, change); err != nil {
+			return err
+		}
+		level.Stock -= taken
+		level.changed = true
+		remaining -= taken
+		if remaining == 0 {
+			break
+		}
+	}
+
+	*item.stock -= amount
+	return nil
+}
+
+// putStock puts an amount of the item into the first warehouse in allocation order
+func (p *Product) putStock(item stockItem, amount int, change StockChange) error {
+	levels, err := p.allocationOrder(item, change)
+	if err != nil {
+		return err
+	}
+
+	level := levels[0]
+	if err := p.recordStockMovement(item.variantID, amount, level.WarehouseID, change); err != nil {
+		return err
+	}
+	level.Stock += amount
+	level.changed = true
+	*item.stock += amount
+	return nil
+}
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StockMovementReason is why the stock of a product or variant moved
+type StockMovementReason string
+
+const (
+	StockMovementSale        StockMovementReason = "sale"
+	StockMovementRefund      StockMovementReason = "refund"
+	StockMovementManual      StockMovementReason = "manual"
+	StockMovementImport      StockMovementReason = "import"
+	StockMovementReservation StockMovementReason = "reservation"
+)
+
+// IsValid checks if the reason is known
+func (r StockMovementReason) IsValid() bool {
+	switch r {
+	case StockMovementSale, StockMovementRefund, StockMovementManual, StockMovementImport, StockMovementReservation:
+		return true
+	}
+	return false
+}
+
+// Actors of stock movements
+const (
+	ActorSystem = "system"
+)
+
+// UserActor identifies a stock movement made by a customer
+func UserActor(userID uint) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+// AdminActor identifies a stock movement made by an administrator
+func AdminActor(adminID uint) string {
+	return fmt.Sprintf("admin:%d", adminID)
+}
+
+// PaymentActor identifies a stock movement following an event of a payment
+func PaymentActor(paymentID string) string {
+	return "payment:" + paymentID
+}
+
+// StockChange describes why the stock changes, who changed it and what for, and which
+// stock and warehouses it applies to
+type StockChange struct {
+	VariantID     *uint // Variant whose stock changes; nil for the product itself
+	Reason        StockMovementReason
+	Actor         string
+	OrderID       *string
+	PaymentID     *string
+	ReservationID *string
+	WarehouseID   *uint              // Only warehouse to use; nil to allocate by Strategy
+	Strategy      AllocationStrategy // Empty for priority
+	Destination   *GeoPoint          // Where taken stock goes, for nearest-first allocation
+}
+
+// StockMovement is an entry of the append-only stock ledger. The stock of a product
+// or variant is the sum of its movements; Product.Stock is kept as a projection of it.
+type StockMovement struct {
+	ID            uint                `gorm:"primaryKey" json:"id"`
+	ProductID     uint                `gorm:"not null;index:idx_stock_movements_item" json:"product_id"`
+	VariantID     *uint               `gorm:"index:idx_stock_movements_item" json:"variant_id"` // Nil for the stock of the product itself
+	WarehouseID   *uint               `gorm:"index" json:"warehouse_id"`
+	Delta         int                 `gorm:"not null" json:"delta"`
+	Reason        StockMovementReason `gorm:"type:varchar(20);not null;index" json:"reason"`
+	OrderID       *string             `gorm:"type:varchar(36);index" json:"order_id"`
+	PaymentID     *string             `gorm:"type:varchar(36);index" json:"payment_id"`
+	ReservationID *string             `gorm:"type:varchar(36)" json:"reservation_id"`
+	Actor         string              `gorm:"type:varchar(100);not null" json:"actor"`
+	CreatedAt     time.Time           `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+// TableName specifies the table name for StockMovement entity
+func (StockMovement) TableName() string {
+	return "stock_movements"
+}
+
+// BeforeUpdate keeps stored movements from being changed
+func (StockMovement) BeforeUpdate(tx *gorm.DB) error {
+	return ErrStockMovementAppendOnly
+}
+
+// BeforeDelete keeps stored movements from being deleted
+func (StockMovement) BeforeDelete(tx *gorm.DB) error {
+	return ErrStockMovementAppendOnly
+}
+
+// StockMovementFilter narrows down the listed stock movements
+type StockMovementFilter struct {
+	ProductID   *uint
+	VariantID   *uint
+	WarehouseID *uint
+	Reason      StockMovementReason
+	OrderID     string
+	PaymentID   string
+}
+
+// newStockMovement creates a movement of the given delta in the warehouse for the change
+func newStockMovement(productID uint, variantID *uint, warehouseID uint, delta int, change StockChange) (*StockMovement, error) {
+	if !change.Reason.IsValid() {
+		return nil, ErrInvalidStockMovement
+	}
+
+	movement := &StockMovement{
+		ProductID:     productID,
+		VariantID:     variantID,
+		WarehouseID:   &warehouseID,
+		Delta:         delta,
+		Reason:        change.Reason,
+		OrderID:       change.OrderID,
+		PaymentID:     change.PaymentID,
+		ReservationID: change.ReservationID,
+		Actor:         change.Actor,
+		CreatedAt:     time.Now(),
+	}
+	if movement.Actor == "" {
+		movement.Actor = ActorSystem
+	}
+	return movement, nil
+}
+
+// recordStockMovement records a movement of the stock of the product, or of one of its
+// variants, in a warehouse to be stored with the product
+func (p *Product) recordStockMovement(variantID *uint, delta int, warehouseID uint, change StockChange) error {
+	movement, err := newStockMovement(p.ID, variantID, warehouseID, delta, change)
+	if err != nil {
+		return err
+	}
+	p.stockMovements = append(p.stockMovements, movement)
+	return nil
+}
+
+// PullStockMovements returns the stock movements recorded since the product was loaded
+// and clears them, so the repository stores each movement once
+func (p *Product) PullStockMovements() []*StockMovement {
+	movements := p.stockMovements
+	p.stockMovements = nil
+	for _, movement := range movements {
+		movement.ProductID = p.ID
+	}
+	return movements
+}
@@ -1,30 +1,36 @@
 package domain
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/ddd-micro/pkg/money"
 	"gorm.io/gorm"
 )
 
 // ProductVariant represents the product variant domain entity
 type ProductVariant struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	ProductID uint           `gorm:"not null;index" json:"product_id"`
-	Product   *Product       `gorm:"foreignKey:ProductID" json:"product,omitempty"`
-	Name      string         `gorm:"not null;size:255" json:"name"`
-	SKU       string         `gorm:"uniqueIndex;not null;size:100" json:"sku"`
-	Price     float64        `gorm:"type:decimal(10,2)" json:"price"` // Override product price if set
-	Stock     int            `gorm:"not null;default:0" json:"stock"`
-	Weight    float64        `gorm:"type:decimal(8,3)" json:"weight"` // Override product weight if set
-	Color     string         `gorm:"size:50" json:"color"`
-	Size      string         `gorm:"size:50" json:"size"`
-	Material  string         `gorm:"size:100" json:"material"`
-	Image     string         `gorm:"size:500" json:"image"`
-	IsActive  bool           `gorm:"default:true" json:"is_active"`
-	SortOrder int            `gorm:"default:0" json:"sort_order"`
-	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID            uint           `gorm:"primaryKey" json:"id"`
+	ProductID     uint           `gorm:"not null;index" json:"product_id"`
+	Product       *Product       `gorm:"foreignKey:ProductID" json:"product,omitempty"`
+	Name          string         `gorm:"not null;size:255" json:"name"`
+	SKU           string         `gorm:"uniqueIndex;not null;size:100" json:"sku"`
+	PriceMinor    int64          `gorm:"not null;default:0" json:"price_minor"` // Override product price if set, in minor units
+	Stock         int            `gorm:"not null;default:0" json:"stock"`
+	ReservedStock int            `gorm:"not null;default:0" json:"reserved_stock"` // Held by active reservations, still part of Stock
+	Weight        float64        `gorm:"type:decimal(8,3)" json:"weight"`          // Override product weight if set
+	Color         string         `gorm:"size:50" json:"color"`
+	Size          string         `gorm:"size:50" json:"size"`
+	Material      string         `gorm:"size:100" json:"material"`
+	Image         string         `gorm:"size:500" json:"image"`
+	IsActive      bool           `gorm:"default:true" json:"is_active"`
+	SortOrder     int            `gorm:"default:0" json:"sort_order"`
+	CreatedAt     time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Decimal copy of the price for readers of the original column, written on save
+	PriceDecimal float64 `gorm:"column:price;type:decimal(10,2)" json:"-"`
 }
 
 // TableName specifies the table name for ProductVariant entity
@@ -32,6 +38,31 @@ func (ProductVariant) TableName() string {
 	return "product_variants"
 }
 
+// BeforeSave keeps the decimal price column in sync with the minor unit price
+func (v *ProductVariant) BeforeSave(tx *gorm.DB) error {
+	v.PriceDecimal = money.New(v.PriceMinor, v.currency()).Major()
+	return nil
+}
+
+// currency returns the currency of the variant, which is the one of its product
+func (v *ProductVariant) currency() string {
+	if v.Product != nil && v.Product.Currency != "" {
+		return v.Product.Currency
+	}
+	return DefaultCurrency
+}
+
+// SetPrice sets the price override from a decimal amount in the currency of the product;
+// zero makes the variant sell at the product price
+func (v *ProductVariant) SetPrice(price float64) error {
+	converted, err := money.FromMajor(price, v.currency())
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidProductData, err)
+	}
+	v.PriceMinor = converted.Amount
+	return nil
+}
+
 // IsValidName checks if the variant name is valid
 func (v *ProductVariant) IsValidName() bool {
 	return len(v.Name) > 0 && len(v.Name) <= 255
@@ -44,7 +75,7 @@ func (v *ProductVariant) IsValidSKU() bool {
 
 // IsValidPrice checks if the price is valid
 func (v *ProductVariant) IsValidPrice() bool {
-	return v.Price >= 0
+	return v.PriceMinor >= 0
 }
 
 // IsValidStock checks if the stock is valid
@@ -58,14 +89,14 @@ func (v *ProductVariant) IsValidWeight() bool {
 }
 
 // GetEffectivePrice returns the variant price if set, otherwise product price
-func (v *ProductVariant) GetEffectivePrice() float64 {
-	if v.Price > 0 {
-		return v.Price
+func (v *ProductVariant) GetEffectivePrice() money.Money {
+	if v.PriceMinor > 0 {
+		return money.New(v.PriceMinor, v.currency())
 	}
 	if v.Product != nil {
-		return v.Product.Price
+		return v.Product.Price()
 	}
-	return 0
+	return money.Zero(v.currency())
 }
 
 // GetEffectiveWeight returns the variant weight if set, otherwise product weight
@@ -79,9 +110,14 @@ func (v *ProductVariant) GetEffectiveWeight() float64 {
 	return 0
 }
 
+// AvailableStock returns the stock that is neither sold nor held by a reservation
+func (v *ProductVariant) AvailableStock() int {
+	return max(v.Stock-v.ReservedStock, 0)
+}
+
 // IsInStock checks if the variant is in stock
 func (v *ProductVariant) IsInStock() bool {
-	return v.Stock > 0 && v.IsActive
+	return v.AvailableStock() > 0 && v.IsActive
 }
 
 // IsAvailable checks if the variant is available for purchase
@@ -89,6 +125,11 @@ func (v *ProductVariant) IsAvailable() bool {
 	return v.IsActive && v.IsInStock()
 }
 
+// IsEmpty checks if the variant holds no stock, sold or reserved, so it can be deleted
+func (v *ProductVariant) IsEmpty() bool {
+	return v.Stock == 0 && v.ReservedStock == 0
+}
+
 // ReduceStock reduces the stock by the specified amount
 func (v *ProductVariant) ReduceStock(amount int) error {
 	if amount <= 0 {
@@ -144,3 +185,21 @@ func (v *ProductVariant) ValidateVariant() error {
 	}
 	return nil
 }
+
+// Variant returns a variant of the product
+func (p *Product) Variant(id uint) (*ProductVariant, bool) {
+	for _, variant := range p.Variants {
+		if variant.ID == id {
+			return variant, true
+		}
+	}
+	return nil, false
+}
+
+// AddVariant attaches a stored variant to the product, so its stock can be changed
+// through the product
+func (p *Product) AddVariant(variant *ProductVariant) {
+	variant.ProductID = p.ID
+	variant.Product = p
+	p.Variants = append(p.Variants, variant)
+}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultWarehouseCode is the code of the warehouse existing stock is moved into when
+// warehouses are introduced
+const DefaultWarehouseCode = "DEFAULT"
+
+// Warehouse represents a location stock is kept in and shipped from
+type Warehouse struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	Code      string         `gorm:"uniqueIndex;not null;size:50" json:"code"`
+	Name      string         `gorm:"not null;size:255" json:"name"`
+	Address   string         `gorm:"size:500" json:"address"`
+	City      string         `gorm:"size:100" json:"city"`
+	Country   string         `gorm:"size:100" json:"country"`
+	Latitude  *float64       `gorm:"type:decimal(9,6)" json:"latitude"`
+	Longitude *float64       `gorm:"type:decimal(9,6)" json:"longitude"`
+	Priority  int            `gorm:"not null;default:0" json:"priority"` // Lower ships first
+	IsActive  bool           `gorm:"default:true" json:"is_active"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName specifies the table name for Warehouse entity
+func (Warehouse) TableName() string {
+	return "warehouses"
+}
+
+// GeoPoint is a location on earth in decimal degrees
+type GeoPoint struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// IsValid checks if the coordinates are within range
+func (g GeoPoint) IsValid() bool {
+	return g.Latitude >= -90 && g.Latitude <= 90 && g.Longitude >= -180 && g.Longitude <= 180
+}
+
+// IsValidCode checks if the warehouse code is valid
+func (w *Warehouse) IsValidCode() bool {
+	return len(w.Code) > 0 && len(w.Code) <= 50
+}
+
+// IsValidName checks if the warehouse name is valid
+func (w *Warehouse) IsValidName() bool {
+	return len(w.Name) > 0 && len(w.Name) <= 255
+}
+
+// IsValidLocation checks that the coordinates are both set and within range, or both unset
+func (w *Warehouse) IsValidLocation() bool {
+	if w.Latitude == nil || w.Longitude == nil {
+		return w.Latitude == nil && w.Longitude == nil
+	}
+	return GeoPoint{Latitude: *w.Latitude, Longitude: *w.Longitude}.IsValid()
+}
+
+// Location returns the coordinates of the warehouse, if known
+func (w *Warehouse) Location() (GeoPoint, bool) {
+	if w.Latitude == nil || w.Longitude == nil {
+		return GeoPoint{}, false
+	}
+	return GeoPoint{Latitude: *w.Latitude, Longitude: *w.Longitude}, true
+}
+
+// DistanceTo returns the great-circle distance in kilometres from the warehouse to a point.
+// Warehouses without a location are infinitely far away.
+func (w *Warehouse) DistanceTo(point GeoPoint) float64 {
+	location, ok := w.Location()
+	if !ok {
+		return math.Inf(1)
+	}
+
+	const earthRadiusKm = 6371.0
+	lat1, lat2 := location.Latitude*math.Pi/180, point.Latitude*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (point.Longitude - location.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Activate activates the warehouse
+func (w *Warehouse) Activate() {
+	w.IsActive = true
+}
+
+// Deactivate deactivates the warehouse so stock is no longer put into or taken from it
+func (w *Warehouse) Deactivate() {
+	w.IsActive = false
+}
+
+// ValidateWarehouse validates all warehouse fields
+func (w *Warehouse) ValidateWarehouse() error {
+	if !w.IsValidCode() {
+		return ErrInvalidWarehouseData
+	}
+	if !w.IsValidName() {
+		return ErrInvalidWarehouseData
+	}
+	if !w.IsValidLocation() {
+		return ErrInvalidWarehouseData
+	}
+	return nil
+}
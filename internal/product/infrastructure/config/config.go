@@ -2,13 +2,37 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"time"
 
+	"github.com/ddd-micro/internal/product/domain"
 	"github.com/ddd-micro/internal/product/infrastructure/database"
 )
 
 type Config struct {
-	Database database.Config
-	Client   ClientConfig
+	Database    database.Config
+	Client      ClientConfig
+	Reservation ReservationConfig
+	Inventory   InventoryConfig
+}
+
+// ReservationConfig holds stock reservation configuration
+type ReservationConfig struct {
+	// TTL is how long a reservation holds its stock when the caller does not say
+	TTL time.Duration
+	// MaxTTL bounds the TTL a caller may ask for
+	MaxTTL time.Duration
+	// SweepInterval is how often expired reservations are released
+	SweepInterval time.Duration
+	SweepBatch    int
+}
+
+// InventoryConfig holds multi-warehouse inventory configuration
+type InventoryConfig struct {
+	// AllocationStrategy picks the warehouses ReduceStock takes stock from: priority, or
+	// nearest to the destination each call must then give. Sales from payments and
+	// reservations carry no destination and are always allocated by priority.
+	AllocationStrategy domain.AllocationStrategy
 }
 
 // LoadConfig loads configuration from environment variables
@@ -23,6 +47,15 @@ func LoadConfig() *Config {
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 		},
 		Client: *LoadClientConfig(),
+		Reservation: ReservationConfig{
+			TTL:           getEnvAsDuration("RESERVATION_TTL", 15*time.Minute),
+			MaxTTL:        getEnvAsDuration("RESERVATION_MAX_TTL", 2*time.Hour),
+			SweepInterval: getEnvAsDuration("RESERVATION_SWEEP_INTERVAL", 30*time.Second),
+			SweepBatch:    getEnvAsInt("RESERVATION_SWEEP_BATCH", 100),
+		},
+		Inventory: InventoryConfig{
+			AllocationStrategy: getEnvAsAllocationStrategy("INVENTORY_ALLOCATION_STRATEGY", domain.AllocationPriority),
+		},
 	}
 }
 
@@ -32,3 +65,28 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvAsInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsAllocationStrategy(key string, defaultValue domain.AllocationStrategy) domain.AllocationStrategy {
+	if strategy := domain.AllocationStrategy(os.Getenv(key)); strategy.IsValid() {
+		return strategy
+	}
+	return defaultValue
+}
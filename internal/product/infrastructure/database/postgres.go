@@ -1,12 +1,18 @@
 package database
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
+	"github.com/ddd-micro/internal/product/domain"
+	"github.com/ddd-micro/kafka"
+	"github.com/ddd-micro/pkg/money"
+	"github.com/ddd-micro/pkg/outbox"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 )
 
@@ -64,9 +70,310 @@ func NewPostgresConnection(config Config) (*Database, error) {
 
 	log.Println("Product Service Database connection established successfully")
 
+	if err := Migrate(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
 	return &Database{DB: db}, nil
 }
 
+// Migrate runs database migrations
+func Migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(
+		&domain.Category{},
+		&domain.Product{},
+		&domain.ProductVariant{},
+		&domain.StockReservation{},
+		&domain.StockReservationItem{},
+		&domain.StockMovement{},
+		&domain.Warehouse{},
+		&domain.StockLevel{},
+		&schemaMigration{},
+	); err != nil {
+		return err
+	}
+
+	if err := outbox.Migrate(db); err != nil {
+		return err
+	}
+
+	if err := kafka.MigrateProcessedEvents(db); err != nil {
+		return err
+	}
+
+	if err := backfillMinorUnitPrices(db); err != nil {
+		return err
+	}
+
+	warehouse, err := defaultWarehouse(db)
+	if err != nil {
+		return err
+	}
+	if warehouse != nil {
+		if err := backfillStockLedger(db, warehouse.ID); err != nil {
+			return err
+		}
+
+		if err := backfillStockLevels(db, warehouse.ID); err != nil {
+			return err
+		}
+
+		if err := runOnce(db, "stock_movement_warehouses", func(tx *gorm.DB) error {
+			return backfillStockMovementWarehouses(tx, warehouse.ID)
+		}); err != nil {
+			return err
+		}
+	}
+
+	if err := backfillCategoryPaths(db); err != nil {
+		return err
+	}
+
+	if err := backfillProductCategories(db); err != nil {
+		return err
+	}
+
+	log.Println("Database migration completed")
+	return nil
+}
+
+// backfillMinorUnitPrices fills the minor unit price columns of rows written before they
+// existed from the decimal columns. Rows that already have minor unit prices are skipped.
+func backfillMinorUnitPrices(db *gorm.DB) error {
+	scale := fmt.Sprintf("POWER(10, %s)", money.ExponentSQL("currency"))
+	if err := db.Exec(fmt.Sprintf(`UPDATE products SET
+		price_minor = ROUND(COALESCE(price, 0) * %[1]s),
+		compare_price_minor = ROUND(COALESCE(compare_price, 0) * %[1]s),
+		cost_price_minor = ROUND(COALESCE(cost_price, 0) * %[1]s)
+		WHERE price_minor = 0 AND compare_price_minor = 0 AND cost_price_minor = 0
+		AND (COALESCE(price, 0) <> 0 OR COALESCE(compare_price, 0) <> 0 OR COALESCE(cost_price, 0) <> 0)`, scale)).Error; err != nil {
+		return fmt.Errorf("failed to backfill product prices: %w", err)
+	}
+
+	variantScale := fmt.Sprintf("POWER(10, %s)", money.ExponentSQL("products.currency"))
+	if err := db.Exec(fmt.Sprintf(`UPDATE product_variants SET
+		price_minor = ROUND(product_variants.price * %s)
+		FROM products
+		WHERE products.id = product_variants.product_id
+		AND product_variants.price_minor = 0 AND COALESCE(product_variants.price, 0) <> 0`, variantScale)).Error; err != nil {
+		return fmt.Errorf("failed to backfill variant prices: %w", err)
+	}
+
+	return nil
+}
+
+// schemaMigration records a one-time data migration that was applied
+type schemaMigration struct {
+	Name      string    `gorm:"primaryKey;type:varchar(100)"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName specifies the table name for schemaMigration
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// runOnce applies a data migration that must not run again, such as one rewriting
+// append-only rows, and records it in the same transaction. Of instances starting at
+// once, the first records the migration and the others wait for it and skip it.
+func runOnce(db *gorm.DB, name string, migrate func(tx *gorm.DB) error) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&schemaMigration{Name: name, AppliedAt: time.Now()})
+		if result.Error != nil {
+			return fmt.Errorf("failed to record migration %s: %w", name, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		if err := migrate(tx); err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", name, err)
+		}
+		log.Printf("Applied migration %s", name)
+		return nil
+	})
+}
+
+// defaultWarehouse returns the warehouse stock kept before warehouses existed is moved
+// into, creating it when there is no warehouse at all. It returns nil when other
+// warehouses exist but the default one does not.
+func defaultWarehouse(db *gorm.DB) (*domain.Warehouse, error) {
+	var count int64
+	if err := db.Unscoped().Model(&domain.Warehouse{}).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to count warehouses: %w", err)
+	}
+	if count == 0 {
+		warehouse := domain.Warehouse{Code: domain.DefaultWarehouseCode, Name: "Default warehouse", IsActive: true}
+		if err := db.Create(&warehouse).Error; err != nil {
+			return nil, fmt.Errorf("failed to create default warehouse: %w", err)
+		}
+	}
+
+	var warehouse domain.Warehouse
+	if err := db.Unscoped().Where("code = ?", domain.DefaultWarehouseCode).First(&warehouse).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get default warehouse: %w", err)
+	}
+	return &warehouse, nil
+}
+
+// backfillStockLedger records the stock of products and variants that have no stock
+// movements yet as an opening import movement into the default warehouse, so the ledger
+// balances their stock
+func backfillStockLedger(db *gorm.DB, warehouseID uint) error {
+	if err := db.Exec(`INSERT INTO stock_movements (product_id, warehouse_id, delta, reason, actor, created_at)
+		SELECT products.id, ?, products.stock, ?, ?, NOW() FROM products
+		WHERE products.stock <> 0 AND NOT EXISTS (
+			SELECT 1 FROM stock_movements
+			WHERE stock_movements.product_id = products.id AND stock_movements.variant_id IS NULL)`,
+		warehouseID, domain.StockMovementImport, domain.ActorSystem).Error; err != nil {
+		return fmt.Errorf("failed to backfill product stock movements: %w", err)
+	}
+
+	if err := db.Exec(`INSERT INTO stock_movements (product_id, variant_id, warehouse_id, delta, reason, actor, created_at)
+		SELECT product_variants.product_id, product_variants.id, ?, product_variants.stock, ?, ?, NOW() FROM product_variants
+		WHERE product_variants.stock <> 0 AND NOT EXISTS (
+			SELECT 1 FROM stock_movements WHERE stock_movements.variant_id = product_variants.id)`,
+		warehouseID, domain.StockMovementImport, domain.ActorSystem).Error; err != nil {
+		return fmt.Errorf("failed to backfill variant stock movements: %w", err)
+	}
+
+	return nil
+}
+
+// backfillStockLevels moves stock kept before warehouses existed into the default
+// warehouse. Products and variants that already have a stock level are skipped.
+func backfillStockLevels(db *gorm.DB, warehouseID uint) error {
+	if err := db.Exec(`INSERT INTO stock_levels (product_id, warehouse_id, stock, min_stock, created_at, updated_at)
+		SELECT products.id, ?, products.stock, 0, NOW(), NOW() FROM products
+		WHERE products.stock <> 0 AND NOT EXISTS (
+			SELECT 1 FROM stock_levels
+			WHERE stock_levels.product_id = products.id AND stock_levels.variant_id IS NULL)`,
+		warehouseID).Error; err != nil {
+		return fmt.Errorf("failed to backfill product stock levels: %w", err)
+	}
+
+	if err := db.Exec(`INSERT INTO stock_levels (product_id, variant_id, warehouse_id, stock, min_stock, created_at, updated_at)
+		SELECT product_variants.product_id, product_variants.id, ?, product_variants.stock, 0, NOW(), NOW() FROM product_variants
+		WHERE product_variants.stock <> 0 AND NOT EXISTS (
+			SELECT 1 FROM stock_levels WHERE stock_levels.variant_id = product_variants.id)`,
+		warehouseID).Error; err != nil {
+		return fmt.Errorf("failed to backfill variant stock levels: %w", err)
+	}
+
+	return nil
+}
+
+// backfillStockMovementWarehouses puts the movements stored before warehouses existed into
+// the default warehouse. It rewrites ledger rows, so it only runs once.
+func backfillStockMovementWarehouses(db *gorm.DB, warehouseID uint) error {
+	if err := db.Exec(`UPDATE stock_movements SET warehouse_id = ? WHERE warehouse_id IS NULL`, warehouseID).Error; err != nil {
+		return fmt.Errorf("failed to backfill stock movement warehouses: %w", err)
+	}
+	return nil
+}
+
+// backfillCategoryPaths sets the path and depth of categories written before the tree
+// was stored as materialized paths, walking down from the roots
+func backfillCategoryPaths(db *gorm.DB) error {
+	if err := db.Exec(`WITH RECURSIVE tree AS (
+			SELECT id, '/' || id || '/' AS path, 0 AS depth FROM categories WHERE parent_id IS NULL
+			UNION ALL
+			SELECT categories.id, tree.path || categories.id || '/', tree.depth + 1
+			FROM categories JOIN tree ON categories.parent_id = tree.id
+		)
+		UPDATE categories SET path = tree.path, depth = tree.depth
+		FROM tree WHERE categories.id = tree.id AND categories.path = ''`).Error; err != nil {
+		return fmt.Errorf("failed to backfill category paths: %w", err)
+	}
+
+	return nil
+}
+
+// backfillProductCategories links products that only carry the legacy category and
+// sub category names to the category tree. Each category name becomes a root category
+// and each sub category a child of it; categories are matched by slug, so existing
+// categories are reused. Names that do not produce a slug are left unlinked.
+func backfillProductCategories(db *gorm.DB) error {
+	type legacyCategory struct {
+		Category    string
+		SubCategory string
+	}
+
+	var pairs []legacyCategory
+	if err := db.Model(&domain.Product{}).
+		Distinct("category", "sub_category").
+		Where("category_id IS NULL AND category <> ''").
+		Scan(&pairs).Error; err != nil {
+		return fmt.Errorf("failed to list product categories: %w", err)
+	}
+
+	for _, pair := range pairs {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			category, err := findOrCreateCategory(tx, pair.Category, domain.Slugify(pair.Category), nil)
+			if err != nil || category == nil {
+				return err
+			}
+
+			if pair.SubCategory != "" {
+				sub, err := findOrCreateCategory(tx, pair.SubCategory, domain.Slugify(pair.Category+" "+pair.SubCategory), category)
+				if err != nil {
+					return err
+				}
+				if sub != nil {
+					category = sub
+				}
+			}
+
+			return tx.Model(&domain.Product{}).
+				Where("category_id IS NULL AND category = ? AND sub_category = ?", pair.Category, pair.SubCategory).
+				Update("category_id", category.ID).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to link products to category %q: %w", pair.Category, err)
+		}
+	}
+
+	return nil
+}
+
+// findOrCreateCategory returns the category with the slug, creating it below the parent
+// when it does not exist. It returns nil when the slug is empty.
+func findOrCreateCategory(tx *gorm.DB, name, slug string, parent *domain.Category) (*domain.Category, error) {
+	if slug == "" {
+		return nil, nil
+	}
+
+	var category domain.Category
+	err := tx.Where("slug = ?", slug).First(&category).Error
+	if err == nil {
+		return &category, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	category = domain.Category{Name: name, Slug: slug, IsActive: true}
+	parentPath := ""
+	if parent != nil {
+		category.ParentID = &parent.ID
+		category.Depth = parent.Depth + 1
+		parentPath = parent.Path
+	}
+	if err := tx.Omit("Parent", "Children").Create(&category).Error; err != nil {
+		return nil, err
+	}
+
+	category.Path = domain.CategoryPath(parentPath, category.ID)
+	if err := tx.Model(&category).Update("path", category.Path).Error; err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
 // Close closes the database connection
 func (d *Database) Close() error {
 	sqlDB, err := d.DB.DB()
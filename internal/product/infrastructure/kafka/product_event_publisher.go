@@ -0,0 +1,43 @@
+package kafka
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/ddd-micro/kafka"
+	"github.com/ddd-micro/pkg/outbox"
+)
+
+// aggregateProduct is the aggregate type of the product service events
+const aggregateProduct = "product"
+
+// ProductEventPublisher handles product-related Kafka events. Events are written to
+// the outbox within the transaction carried by ctx and published by the outbox relay.
+type ProductEventPublisher struct {
+	outbox *outbox.Store
+}
+
+// NewProductEventPublisher creates a new product event publisher
+func NewProductEventPublisher(store *outbox.Store) *ProductEventPublisher {
+	return &ProductEventPublisher{
+		outbox: store,
+	}
+}
+
+// PublishStockUpdated publishes a stock updated event
+func (p *ProductEventPublisher) PublishStockUpdated(ctx context.Context, productID uint, variantID *uint, quantity int, newStock int, reason string, orderID *string, paymentID *string) error {
+	event := kafka.StockUpdatedEvent{
+		BaseEvent: kafka.NewBaseEvent(ctx, kafka.EventTypeStockUpdated, "product-service", strconv.FormatUint(uint64(productID), 10)),
+		Data: kafka.StockUpdatedData{
+			ProductID: productID,
+			VariantID: variantID,
+			Quantity:  quantity,
+			NewStock:  newStock,
+			Reason:    reason,
+			OrderID:   orderID,
+			PaymentID: paymentID,
+		},
+	}
+
+	return p.outbox.Add(ctx, aggregateProduct, strconv.FormatUint(uint64(productID), 10), event.Type, event)
+}
@@ -0,0 +1,230 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ddd-micro/internal/product/domain"
+	"github.com/ddd-micro/pkg/gormtx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CategoryRepository is the concrete implementation of domain.CategoryRepository. The
+// tree is stored as materialized paths, so subtrees are read and moved with one query.
+type CategoryRepository struct {
+	db *gorm.DB
+}
+
+// NewCategoryRepository creates a new instance of CategoryRepository
+func NewCategoryRepository(db *gorm.DB) domain.CategoryRepository {
+	return &CategoryRepository{
+		db: db,
+	}
+}
+
+// Create creates a new category below its parent, setting its path and depth
+func (r *CategoryRepository) Create(ctx context.Context, category *domain.Category) error {
+	return gormtx.DB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		parentPath, depth := "", 0
+		if category.ParentID != nil {
+			var parent domain.Category
+			if err := tx.First(&parent, *category.ParentID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return domain.ErrCategoryNotFound
+				}
+				return err
+			}
+			parentPath, depth = parent.Path, parent.Depth+1
+		}
+
+		if err := tx.Omit(clause.Associations).Create(category).Error; err != nil {
+			return err
+		}
+
+		category.Path = domain.CategoryPath(parentPath, category.ID)
+		category.Depth = depth
+		return tx.Model(category).Updates(map[string]interface{}{
+			"path":  category.Path,
+			"depth": category.Depth,
+		}).Error
+	})
+}
+
+// GetByID retrieves a category by ID
+func (r *CategoryRepository) GetByID(ctx context.Context, id uint) (*domain.Category, error) {
+	var category domain.Category
+	result := gormtx.DB(ctx, r.db).First(&category, id)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrCategoryNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &category, nil
+}
+
+// GetBySlug retrieves a category by slug
+func (r *CategoryRepository) GetBySlug(ctx context.Context, slug string) (*domain.Category, error) {
+	var category domain.Category
+	result := gormtx.DB(ctx, r.db).Where("slug = ?", slug).First(&category)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrCategoryNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &category, nil
+}
+
+// Update updates the fields of a category, leaving its place in the tree unchanged
+func (r *CategoryRepository) Update(ctx context.Context, category *domain.Category) error {
+	result := gormtx.DB(ctx, r.db).
+		Omit(clause.Associations, "parent_id", "path", "depth").
+		Save(category)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrCategoryNotFound
+	}
+
+	return nil
+}
+
+// Delete soft deletes a category
+func (r *CategoryRepository) Delete(ctx context.Context, id uint) error {
+	result := gormtx.DB(ctx, r.db).Delete(&domain.Category{}, id)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrCategoryNotFound
+	}
+
+	return nil
+}
+
+// SlugExists checks if another category uses the slug
+func (r *CategoryRepository) SlugExists(ctx context.Context, slug string, excludeID uint) (bool, error) {
+	var count int64
+	result := gormtx.DB(ctx, r.db).
+		Model(&domain.Category{}).
+		Where("slug = ? AND id <> ?", slug, excludeID).
+		Count(&count)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return count > 0, nil
+}
+
+// ListChildren retrieves the children of a category, or the root categories, in sort order
+func (r *CategoryRepository) ListChildren(ctx context.Context, parentID *uint) ([]*domain.Category, error) {
+	var categories []*domain.Category
+
+	query := gormtx.DB(ctx, r.db)
+	if parentID != nil {
+		query = query.Where("parent_id = ?", *parentID)
+	} else {
+		query = query.Where("parent_id IS NULL")
+	}
+
+	if err := query.Order("sort_order ASC, id ASC").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// ListTree retrieves a category and its descendants, or every category, ordered by depth
+// and sort order
+func (r *CategoryRepository) ListTree(ctx context.Context, root *domain.Category, includeInactive bool) ([]*domain.Category, error) {
+	var categories []*domain.Category
+
+	query := gormtx.DB(ctx, r.db)
+	if root != nil {
+		query = query.Where("path LIKE ?", root.Path+"%")
+	}
+	if !includeInactive {
+		query = query.Where("is_active = ?", true)
+	}
+
+	if err := query.Order("depth ASC, sort_order ASC, id ASC").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// Move places a category and its subtree below a parent, or at the root, rewriting the
+// paths and depths of the whole subtree
+func (r *CategoryRepository) Move(ctx context.Context, category *domain.Category, parent *domain.Category) error {
+	var parentID *uint
+	parentPath, depth := "", 0
+	if parent != nil {
+		parentID = &parent.ID
+		parentPath, depth = parent.Path, parent.Depth+1
+	}
+
+	oldPath, oldDepth := category.Path, category.Depth
+	newPath := domain.CategoryPath(parentPath, category.ID)
+
+	return gormtx.DB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.Category{}).
+			Where("id = ?", category.ID).
+			Update("parent_id", parentID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&domain.Category{}).
+			Where("path LIKE ?", oldPath+"%").
+			Updates(map[string]interface{}{
+				"path":  gorm.Expr("? || SUBSTRING(path FROM ?)", newPath, len(oldPath)+1),
+				"depth": gorm.Expr("depth + ?", depth-oldDepth),
+			}).Error; err != nil {
+			return err
+		}
+
+		category.ParentID = parentID
+		category.Path = newPath
+		category.Depth = depth
+		return nil
+	})
+}
+
+// UpdateSortOrders sets the sort order of categories by ID
+func (r *CategoryRepository) UpdateSortOrders(ctx context.Context, orders map[uint]int) error {
+	return gormtx.DB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for id, order := range orders {
+			if err := tx.Model(&domain.Category{}).Where("id = ?", id).Update("sort_order", order).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// HasProducts checks if products are linked to the category
+func (r *CategoryRepository) HasProducts(ctx context.Context, id uint) (bool, error) {
+	var count int64
+	result := gormtx.DB(ctx, r.db).
+		Model(&domain.Product{}).
+		Where("category_id = ?", id).
+		Count(&count)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return count > 0, nil
+}
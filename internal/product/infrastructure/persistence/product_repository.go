@@ -3,13 +3,16 @@ package persistence
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/ddd-micro/internal/product/domain"
+	"github.com/ddd-micro/pkg/gormtx"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var (
-	ErrProductNotFound      = errors.New("product not found")
+	ErrProductNotFound      = domain.ErrProductNotFound
 	ErrProductAlreadyExists = errors.New("product with this SKU already exists")
 )
 
@@ -36,18 +39,40 @@ func (r *ProductRepository) Create(ctx context.Context, product *domain.Product)
 		return ErrProductAlreadyExists
 	}
 
-	result := r.db.WithContext(ctx).Create(product)
+	return gormtx.DB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(product).Error; err != nil {
+			return err
+		}
+		if err := saveStockLevels(tx, product); err != nil {
+			return err
+		}
+		return saveStockMovements(tx, product)
+	})
+}
+
+// GetByID retrieves a product by ID
+func (r *ProductRepository) GetByID(ctx context.Context, id uint) (*domain.Product, error) {
+	var product domain.Product
+	result := withVariants(gormtx.DB(ctx, r.db)).First(&product, id)
+
 	if result.Error != nil {
-		return result.Error
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, result.Error
 	}
 
-	return nil
+	if err := r.LoadStockLevels(ctx, &product); err != nil {
+		return nil, err
+	}
+
+	return &product, nil
 }
 
-// GetByID retrieves a product by ID
-func (r *ProductRepository) GetByID(ctx context.Context, id uint) (*domain.Product, error) {
+// GetByIDForUpdate retrieves a product by ID and locks its row until the transaction ends
+func (r *ProductRepository) GetByIDForUpdate(ctx context.Context, id uint) (*domain.Product, error) {
 	var product domain.Product
-	result := r.db.WithContext(ctx).First(&product, id)
+	result := withVariants(gormtx.DB(ctx, r.db)).Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, id)
 
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -56,13 +81,17 @@ func (r *ProductRepository) GetByID(ctx context.Context, id uint) (*domain.Produ
 		return nil, result.Error
 	}
 
+	if err := r.LoadStockLevels(ctx, &product); err != nil {
+		return nil, err
+	}
+
 	return &product, nil
 }
 
 // GetBySKU retrieves a product by SKU
 func (r *ProductRepository) GetBySKU(ctx context.Context, sku string) (*domain.Product, error) {
 	var product domain.Product
-	result := r.db.WithContext(ctx).Where("sku = ?", sku).First(&product)
+	result := withVariants(gormtx.DB(ctx, r.db)).Where("sku = ?", sku).First(&product)
 
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -74,23 +103,94 @@ func (r *ProductRepository) GetBySKU(ctx context.Context, sku string) (*domain.P
 	return &product, nil
 }
 
-// Update updates an existing product
+// Update updates an existing product and stores the stock of its variants and its pending
+// stock movements in the same transaction, keeping the stock columns a projection of the
+// ledger
 func (r *ProductRepository) Update(ctx context.Context, product *domain.Product) error {
-	result := r.db.WithContext(ctx).Save(product)
-	if result.Error != nil {
-		return result.Error
+	return gormtx.DB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Omit(clause.Associations).Save(product)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return ErrProductNotFound
+		}
+
+		if err := saveVariantStock(tx, product); err != nil {
+			return err
+		}
+		if err := saveStockLevels(tx, product); err != nil {
+			return err
+		}
+		return saveStockMovements(tx, product)
+	})
+}
+
+// LoadStockLevels attaches the stock levels of a product and its variants in every
+// warehouse, with an empty level for active warehouses they have no stock in yet
+func (r *ProductRepository) LoadStockLevels(ctx context.Context, product *domain.Product) error {
+	db := gormtx.DB(ctx, r.db)
+
+	var levels []*domain.StockLevel
+	if product.ID != 0 {
+		if err := db.Preload("Warehouse").
+			Where("product_id = ?", product.ID).
+			Find(&levels).Error; err != nil {
+			return fmt.Errorf("failed to load stock levels: %w", err)
+		}
 	}
 
-	if result.RowsAffected == 0 {
-		return ErrProductNotFound
+	var warehouses []*domain.Warehouse
+	if err := db.Where("is_active = ?", true).Find(&warehouses).Error; err != nil {
+		return fmt.Errorf("failed to load warehouses: %w", err)
+	}
+
+	product.AttachStockLevels(levels, warehouses)
+	return nil
+}
+
+// saveVariantStock stores the stock of the variants of a product; their other fields are
+// stored by VariantRepository
+func saveVariantStock(tx *gorm.DB, product *domain.Product) error {
+	for _, variant := range product.Variants {
+		if err := tx.Model(&domain.ProductVariant{}).
+			Where("id = ?", variant.ID).
+			UpdateColumns(map[string]interface{}{
+				"stock":          variant.Stock,
+				"reserved_stock": variant.ReservedStock,
+			}).Error; err != nil {
+			return fmt.Errorf("failed to save variant stock: %w", err)
+		}
+	}
+	return nil
+}
+
+// saveStockLevels stores the changed stock levels of a product
+func saveStockLevels(tx *gorm.DB, product *domain.Product) error {
+	for _, level := range product.PullChangedStockLevels() {
+		if err := tx.Omit("Warehouse").Save(level).Error; err != nil {
+			return fmt.Errorf("failed to save stock level: %w", err)
+		}
 	}
+	return nil
+}
 
+// saveStockMovements stores the pending stock movements of a product
+func saveStockMovements(tx *gorm.DB, product *domain.Product) error {
+	movements := product.PullStockMovements()
+	if len(movements) == 0 {
+		return nil
+	}
+	if err := tx.Create(&movements).Error; err != nil {
+		return fmt.Errorf("failed to save stock movements: %w", err)
+	}
 	return nil
 }
 
 // Delete soft deletes a product by ID
 func (r *ProductRepository) Delete(ctx context.Context, id uint) error {
-	result := r.db.WithContext(ctx).Delete(&domain.Product{}, id)
+	result := gormtx.DB(ctx, r.db).Delete(&domain.Product{}, id)
 
 	if result.Error != nil {
 		return result.Error
@@ -107,7 +207,7 @@ func (r *ProductRepository) Delete(ctx context.Context, id uint) error {
 func (r *ProductRepository) List(ctx context.Context, offset, limit int) ([]*domain.Product, error) {
 	var products []*domain.Product
 
-	result := r.db.WithContext(ctx).
+	result := withVariants(gormtx.DB(ctx, r.db)).
 		Offset(offset).
 		Limit(limit).
 		Find(&products)
@@ -123,7 +223,7 @@ func (r *ProductRepository) List(ctx context.Context, offset, limit int) ([]*dom
 func (r *ProductRepository) ListByCategory(ctx context.Context, category string, offset, limit int) ([]*domain.Product, error) {
 	var products []*domain.Product
 
-	result := r.db.WithContext(ctx).
+	result := withVariants(gormtx.DB(ctx, r.db)).
 		Where("category = ?", category).
 		Offset(offset).
 		Limit(limit).
@@ -136,11 +236,31 @@ func (r *ProductRepository) ListByCategory(ctx context.Context, category string,
 	return products, nil
 }
 
+// ListByCategoryTree retrieves products of a category and of its descendants with pagination
+func (r *ProductRepository) ListByCategoryTree(ctx context.Context, category *domain.Category, offset, limit int) ([]*domain.Product, error) {
+	var products []*domain.Product
+
+	db := gormtx.DB(ctx, r.db)
+	subtree := db.Model(&domain.Category{}).Select("id").Where("path LIKE ?", category.Path+"%")
+
+	result := withVariants(db).
+		Where("category_id IN (?)", subtree).
+		Offset(offset).
+		Limit(limit).
+		Find(&products)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return products, nil
+}
+
 // SearchByName searches products by name with pagination
 func (r *ProductRepository) SearchByName(ctx context.Context, name string, offset, limit int) ([]*domain.Product, error) {
 	var products []*domain.Product
 
-	result := r.db.WithContext(ctx).
+	result := withVariants(gormtx.DB(ctx, r.db)).
 		Where("name ILIKE ?", "%"+name+"%").
 		Offset(offset).
 		Limit(limit).
@@ -156,7 +276,7 @@ func (r *ProductRepository) SearchByName(ctx context.Context, name string, offse
 // Exists checks if a product exists by SKU
 func (r *ProductRepository) Exists(ctx context.Context, sku string) (bool, error) {
 	var count int64
-	result := r.db.WithContext(ctx).
+	result := gormtx.DB(ctx, r.db).
 		Model(&domain.Product{}).
 		Where("sku = ?", sku).
 		Count(&count)
@@ -168,20 +288,27 @@ func (r *ProductRepository) Exists(ctx context.Context, sku string) (bool, error
 	return count > 0, nil
 }
 
-// UpdateStock updates the stock of a product
-func (r *ProductRepository) UpdateStock(ctx context.Context, id uint, stock int) error {
-	result := r.db.WithContext(ctx).
+// ListIDs retrieves the IDs of products after the given ID in ascending order
+func (r *ProductRepository) ListIDs(ctx context.Context, afterID uint, limit int) ([]uint, error) {
+	var ids []uint
+
+	result := gormtx.DB(ctx, r.db).
 		Model(&domain.Product{}).
-		Where("id = ?", id).
-		Update("stock", stock)
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Pluck("id", &ids)
 
 	if result.Error != nil {
-		return result.Error
+		return nil, result.Error
 	}
 
-	if result.RowsAffected == 0 {
-		return ErrProductNotFound
-	}
+	return ids, nil
+}
 
-	return nil
+// withVariants preloads the variants of the retrieved products in sort order
+func withVariants(db *gorm.DB) *gorm.DB {
+	return db.Preload("Variants", func(db *gorm.DB) *gorm.DB {
+		return db.Order("sort_order ASC, id ASC")
+	})
 }
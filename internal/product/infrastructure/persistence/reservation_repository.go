@@ -0,0 +1,96 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ddd-micro/internal/product/domain"
+	"github.com/ddd-micro/pkg/gormtx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StockReservationRepository is the concrete implementation of domain.StockReservationRepository
+type StockReservationRepository struct {
+	db *gorm.DB
+}
+
+// NewStockReservationRepository creates a new instance of StockReservationRepository
+func NewStockReservationRepository(db *gorm.DB) domain.StockReservationRepository {
+	return &StockReservationRepository{
+		db: db,
+	}
+}
+
+// Create creates a new reservation with its items
+func (r *StockReservationRepository) Create(ctx context.Context, reservation *domain.StockReservation) error {
+	return gormtx.DB(ctx, r.db).Create(reservation).Error
+}
+
+// GetByIDForUpdate retrieves a reservation with its items and locks it for the current transaction
+func (r *StockReservationRepository) GetByIDForUpdate(ctx context.Context, id string) (*domain.StockReservation, error) {
+	return r.getForUpdate(ctx, "id = ?", id)
+}
+
+// GetActiveByReferenceForUpdate retrieves the active reservation of a basket or payment
+// with its items and locks it for the current transaction
+func (r *StockReservationRepository) GetActiveByReferenceForUpdate(ctx context.Context, referenceType domain.ReservationReference, referenceID string) (*domain.StockReservation, error) {
+	return r.getForUpdate(ctx, "reference_type = ? AND reference_id = ? AND status = ?", referenceType, referenceID, domain.ReservationStatusActive)
+}
+
+// getForUpdate retrieves the reservation matching the condition and locks it
+func (r *StockReservationRepository) getForUpdate(ctx context.Context, query string, args ...interface{}) (*domain.StockReservation, error) {
+	var reservation domain.StockReservation
+	result := gormtx.DB(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("product_id ASC")
+		}).
+		Where(query, args...).
+		First(&reservation)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrReservationNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &reservation, nil
+}
+
+// Update updates the status of a reservation; its items never change
+func (r *StockReservationRepository) Update(ctx context.Context, reservation *domain.StockReservation) error {
+	result := gormtx.DB(ctx, r.db).
+		Model(reservation).
+		Select("status", "expires_at", "confirmed_at", "released_at", "updated_at").
+		Updates(reservation)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrReservationNotFound
+	}
+
+	return nil
+}
+
+// ListExpired retrieves active reservations that expired at or before now, oldest first
+func (r *StockReservationRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]*domain.StockReservation, error) {
+	var reservations []*domain.StockReservation
+
+	result := gormtx.DB(ctx, r.db).
+		Where("status = ? AND expires_at <= ?", domain.ReservationStatusActive, now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&reservations)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return reservations, nil
+}
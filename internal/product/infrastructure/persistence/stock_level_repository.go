@@ -0,0 +1,86 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/ddd-micro/internal/product/domain"
+	"github.com/ddd-micro/pkg/gormtx"
+	"gorm.io/gorm"
+)
+
+// StockLevelRepository is the concrete implementation of domain.StockLevelRepository.
+// Levels are written by ProductRepository together with their product.
+type StockLevelRepository struct {
+	db *gorm.DB
+}
+
+// NewStockLevelRepository creates a new instance of StockLevelRepository
+func NewStockLevelRepository(db *gorm.DB) domain.StockLevelRepository {
+	return &StockLevelRepository{
+		db: db,
+	}
+}
+
+// ListByProduct retrieves the stock levels of a product and its variants with their warehouses
+func (r *StockLevelRepository) ListByProduct(ctx context.Context, productID uint) ([]*domain.StockLevel, error) {
+	var levels []*domain.StockLevel
+
+	result := gormtx.DB(ctx, r.db).
+		Preload("Warehouse").
+		Where("product_id = ?", productID).
+		Order("variant_id ASC NULLS FIRST, warehouse_id ASC").
+		Find(&levels)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return levels, nil
+}
+
+// ListLowStock retrieves stock levels at or below their threshold, lowest stock first
+func (r *StockLevelRepository) ListLowStock(ctx context.Context, warehouseID *uint, offset, limit int) ([]*domain.StockLevel, int, error) {
+	var levels []*domain.StockLevel
+	var total int64
+
+	query := gormtx.DB(ctx, r.db).
+		Model(&domain.StockLevel{}).
+		Where("min_stock > 0 AND stock <= min_stock")
+	if warehouseID != nil {
+		query = query.Where("warehouse_id = ?", *warehouseID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	result := query.
+		Preload("Warehouse").
+		Order("stock ASC, id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&levels)
+
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return levels, int(total), nil
+}
+
+// TotalByWarehouse sums the stock held in a warehouse
+func (r *StockLevelRepository) TotalByWarehouse(ctx context.Context, warehouseID uint) (int, error) {
+	var total int64
+
+	result := gormtx.DB(ctx, r.db).
+		Model(&domain.StockLevel{}).
+		Select("COALESCE(SUM(stock), 0)").
+		Where("warehouse_id = ?", warehouseID).
+		Scan(&total)
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return int(total), nil
+}
@@ -0,0 +1,92 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/ddd-micro/internal/product/domain"
+	"github.com/ddd-micro/pkg/gormtx"
+	"gorm.io/gorm"
+)
+
+// StockMovementRepository is the concrete implementation of domain.StockMovementRepository.
+// Movements are written by ProductRepository together with the stock they move.
+type StockMovementRepository struct {
+	db *gorm.DB
+}
+
+// NewStockMovementRepository creates a new instance of StockMovementRepository
+func NewStockMovementRepository(db *gorm.DB) domain.StockMovementRepository {
+	return &StockMovementRepository{
+		db: db,
+	}
+}
+
+// List retrieves stock movements, newest first, with pagination and filters
+func (r *StockMovementRepository) List(ctx context.Context, filter domain.StockMovementFilter, offset, limit int) ([]*domain.StockMovement, int, error) {
+	var movements []*domain.StockMovement
+	var total int64
+
+	query := gormtx.DB(ctx, r.db).Model(&domain.StockMovement{})
+	if filter.ProductID != nil {
+		query = query.Where("product_id = ?", *filter.ProductID)
+	}
+	if filter.VariantID != nil {
+		query = query.Where("variant_id = ?", *filter.VariantID)
+	}
+	if filter.WarehouseID != nil {
+		query = query.Where("warehouse_id = ?", *filter.WarehouseID)
+	}
+	if filter.Reason != "" {
+		query = query.Where("reason = ?", filter.Reason)
+	}
+	if filter.OrderID != "" {
+		query = query.Where("order_id = ?", filter.OrderID)
+	}
+	if filter.PaymentID != "" {
+		query = query.Where("payment_id = ?", filter.PaymentID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	result := query.
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&movements)
+
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return movements, int(total), nil
+}
+
+// Balances sums the movements of the stock of a product, or of one of its variants, per warehouse
+func (r *StockMovementRepository) Balances(ctx context.Context, productID uint, variantID *uint) (map[uint]int, error) {
+	var rows []struct {
+		WarehouseID uint
+		Balance     int
+	}
+
+	query := gormtx.DB(ctx, r.db).
+		Model(&domain.StockMovement{}).
+		Select("COALESCE(warehouse_id, 0) AS warehouse_id, SUM(delta) AS balance").
+		Where("product_id = ?", productID)
+	if variantID != nil {
+		query = query.Where("variant_id = ?", *variantID)
+	} else {
+		query = query.Where("variant_id IS NULL")
+	}
+
+	if err := query.Group("COALESCE(warehouse_id, 0)").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	balances := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		balances[row.WarehouseID] = row.Balance
+	}
+	return balances, nil
+}
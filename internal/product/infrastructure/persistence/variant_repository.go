@@ -0,0 +1,86 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/ddd-micro/internal/product/domain"
+	"github.com/ddd-micro/pkg/gormtx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// VariantRepository is the concrete implementation of domain.VariantRepository. Variants
+// are read with their product; their stock is stored by ProductRepository.
+type VariantRepository struct {
+	db *gorm.DB
+}
+
+// NewVariantRepository creates a new instance of VariantRepository
+func NewVariantRepository(db *gorm.DB) domain.VariantRepository {
+	return &VariantRepository{
+		db: db,
+	}
+}
+
+// Create creates a new variant without stock
+func (r *VariantRepository) Create(ctx context.Context, variant *domain.ProductVariant) error {
+	exists, err := r.ExistsBySKU(ctx, variant.SKU)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return domain.ErrVariantAlreadyExists
+	}
+
+	return gormtx.DB(ctx, r.db).Omit(clause.Associations, "stock", "reserved_stock").Create(variant).Error
+}
+
+// Update updates the fields of a variant, leaving its stock to its product
+func (r *VariantRepository) Update(ctx context.Context, variant *domain.ProductVariant) error {
+	result := gormtx.DB(ctx, r.db).
+		Omit(clause.Associations, "product_id", "stock", "reserved_stock").
+		Save(variant)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrVariantNotFound
+	}
+
+	return nil
+}
+
+// Delete soft deletes a variant of a product and removes its stock levels
+func (r *VariantRepository) Delete(ctx context.Context, productID, id uint) error {
+	return gormtx.DB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("product_id = ?", productID).Delete(&domain.ProductVariant{}, id)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return domain.ErrVariantNotFound
+		}
+
+		return tx.Where("variant_id = ?", id).Delete(&domain.StockLevel{}).Error
+	})
+}
+
+// ExistsBySKU checks if a variant exists by SKU, including deleted ones
+func (r *VariantRepository) ExistsBySKU(ctx context.Context, sku string) (bool, error) {
+	var count int64
+	result := gormtx.DB(ctx, r.db).
+		Unscoped().
+		Model(&domain.ProductVariant{}).
+		Where("sku = ?", sku).
+		Count(&count)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return count > 0, nil
+}
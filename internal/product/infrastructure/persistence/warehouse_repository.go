@@ -0,0 +1,121 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ddd-micro/internal/product/domain"
+	"github.com/ddd-micro/pkg/gormtx"
+	"gorm.io/gorm"
+)
+
+// WarehouseRepository is the concrete implementation of domain.WarehouseRepository
+type WarehouseRepository struct {
+	db *gorm.DB
+}
+
+// NewWarehouseRepository creates a new instance of WarehouseRepository
+func NewWarehouseRepository(db *gorm.DB) domain.WarehouseRepository {
+	return &WarehouseRepository{
+		db: db,
+	}
+}
+
+// Create creates a new warehouse
+func (r *WarehouseRepository) Create(ctx context.Context, warehouse *domain.Warehouse) error {
+	exists, err := r.ExistsByCode(ctx, warehouse.Code)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return domain.ErrWarehouseAlreadyExists
+	}
+
+	return gormtx.DB(ctx, r.db).Create(warehouse).Error
+}
+
+// GetByID retrieves a warehouse by ID
+func (r *WarehouseRepository) GetByID(ctx context.Context, id uint) (*domain.Warehouse, error) {
+	var warehouse domain.Warehouse
+	result := gormtx.DB(ctx, r.db).First(&warehouse, id)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrWarehouseNotFound
+		}
+		return nil, result.Error
+	}
+
+	return &warehouse, nil
+}
+
+// Update updates an existing warehouse
+func (r *WarehouseRepository) Update(ctx context.Context, warehouse *domain.Warehouse) error {
+	result := gormtx.DB(ctx, r.db).Save(warehouse)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrWarehouseNotFound
+	}
+
+	return nil
+}
+
+// Delete soft deletes a warehouse and removes its empty stock levels
+func (r *WarehouseRepository) Delete(ctx context.Context, id uint) error {
+	return gormtx.DB(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&domain.Warehouse{}, id)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return domain.ErrWarehouseNotFound
+		}
+
+		return tx.Where("warehouse_id = ? AND stock = 0", id).Delete(&domain.StockLevel{}).Error
+	})
+}
+
+// List retrieves warehouses by priority with pagination
+func (r *WarehouseRepository) List(ctx context.Context, offset, limit int) ([]*domain.Warehouse, int, error) {
+	var warehouses []*domain.Warehouse
+	var total int64
+
+	query := gormtx.DB(ctx, r.db).Model(&domain.Warehouse{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	result := query.
+		Order("priority ASC, id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&warehouses)
+
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return warehouses, int(total), nil
+}
+
+// ExistsByCode checks if a warehouse exists by code, including deleted ones
+func (r *WarehouseRepository) ExistsByCode(ctx context.Context, code string) (bool, error) {
+	var count int64
+	result := gormtx.DB(ctx, r.db).
+		Unscoped().
+		Model(&domain.Warehouse{}).
+		Where("code = ?", code).
+		Count(&count)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return count > 0, nil
+}
@@ -4,9 +4,15 @@ import (
 	"github.com/ddd-micro/internal/product/infrastructure/client"
 	"github.com/ddd-micro/internal/product/infrastructure/config"
 	"github.com/ddd-micro/internal/product/infrastructure/database"
+	productkafka "github.com/ddd-micro/internal/product/infrastructure/kafka"
 	"github.com/ddd-micro/internal/product/infrastructure/monitoring"
 	"github.com/ddd-micro/internal/product/infrastructure/persistence"
+	"github.com/ddd-micro/kafka"
+	"github.com/ddd-micro/kafka/consumers"
+	"github.com/ddd-micro/pkg/gormtx"
+	"github.com/ddd-micro/pkg/outbox"
 	"github.com/google/wire"
+	"gorm.io/gorm"
 )
 
 // ProviderSet is the infrastructure layer providers
@@ -17,13 +23,65 @@ var ProviderSet = wire.NewSet(
 
 	// Database providers
 	database.NewPostgresConnection,
+	ProvideGormDB,
+	gormtx.NewTransactor,
 
 	// Persistence providers
 	persistence.NewProductRepository,
+	persistence.NewStockReservationRepository,
+	persistence.NewStockMovementRepository,
+	persistence.NewWarehouseRepository,
+	persistence.NewCategoryRepository,
+	persistence.NewVariantRepository,
+	persistence.NewStockLevelRepository,
 
 	// Client providers
 	client.ProviderSet,
 
+	// Kafka providers
+	ProvideKafkaConfig,
+	ProvideKafkaPublisher,
+	ProvideKafkaConsumer,
+	productkafka.ProviderSet,
+	consumers.NewProductConsumer,
+
+	// Outbox providers
+	outbox.NewStore,
+	ProvideOutboxRelay,
+
 	// Monitoring providers
 	monitoring.ProviderSet,
 )
+
+// ProvideGormDB provides the GORM database instance
+func ProvideGormDB(db *database.Database) *gorm.DB {
+	return db.GetDB()
+}
+
+// ProvideKafkaConfig provides Kafka configuration for the product service
+func ProvideKafkaConfig() *kafka.Config {
+	return kafka.LoadServiceConfig("product-service")
+}
+
+// ProvideKafkaPublisher provides Kafka event publisher
+func ProvideKafkaPublisher(cfg *kafka.Config) (kafka.EventPublisher, error) {
+	return kafka.NewPublisher(cfg.GetPublisherConfig())
+}
+
+// ProvideKafkaConsumer provides Kafka event consumer that skips already processed events,
+// marking them processed in the transaction of the handler
+func ProvideKafkaConsumer(cfg *kafka.Config, db *gorm.DB, transactor *gormtx.Transactor) (kafka.EventConsumer, error) {
+	consumer, err := kafka.NewConsumer(cfg.GetConsumerConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	store := kafka.NewPostgresProcessedEventStore(db, transactor, cfg.DedupRetention)
+	consumer.Use(kafka.Dedup(cfg.GroupID, store))
+	return consumer, nil
+}
+
+// ProvideOutboxRelay provides the relay publishing outbox events to Kafka
+func ProvideOutboxRelay(db *gorm.DB, publisher kafka.EventPublisher) *outbox.Relay {
+	return outbox.NewRelay(db, publisher, outbox.LoadRelayConfig("product-service"), outbox.NewMetrics("product_service"))
+}
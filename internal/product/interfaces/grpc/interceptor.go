@@ -101,6 +101,8 @@ func (a *AuthInterceptor) isPublicMethod(method string) bool {
 		"/product.ProductService/ListProducts":           true,
 		"/product.ProductService/SearchProducts":         true,
 		"/product.ProductService/ListProductsByCategory": true,
+		"/product.ProductService/GetCategoryTree":        true,
+		"/product.ProductService/GetCategory":            true,
 		"/product.ProductService/IncrementViewCount":     true,
 	}
 	return publicMethods[method]
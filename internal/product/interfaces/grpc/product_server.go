@@ -2,9 +2,13 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	productpb "github.com/ddd-micro/api/proto/product"
 	"github.com/ddd-micro/internal/product/application"
+	"github.com/ddd-micro/internal/product/domain"
+	"github.com/ddd-micro/pkg/money"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -13,30 +17,41 @@ import (
 // ProductServer implements the gRPC ProductService
 type ProductServer struct {
 	productpb.UnimplementedProductServiceServer
-	productService *application.ProductServiceCQRS
+	productService     *application.ProductServiceCQRS
+	reservationService *application.ReservationService
+	categoryService    *application.CategoryService
 }
 
 // NewProductServer creates a new gRPC product server
-func NewProductServer(productService *application.ProductServiceCQRS) *ProductServer {
+func NewProductServer(productService *application.ProductServiceCQRS, reservationService *application.ReservationService, categoryService *application.CategoryService) *ProductServer {
 	return &ProductServer{
-		productService: productService,
+		productService:     productService,
+		reservationService: reservationService,
+		categoryService:    categoryService,
 	}
 }
 
 // CreateProduct handles product creation
 func (s *ProductServer) CreateProduct(ctx context.Context, req *productpb.CreateProductRequest) (*productpb.ProductResponse, error) {
+	currency := req.Currency
+	if currency == "" {
+		currency = domain.DefaultCurrency
+	}
+
 	appReq := application.CreateProductRequest{
 		Name:             req.Name,
 		Description:      req.Description,
 		ShortDescription: req.ShortDescription,
-		Price:            req.Price,
-		ComparePrice:     req.ComparePrice,
-		CostPrice:        req.CostPrice,
+		Price:            priceFromProto(req.PriceMinor, req.Price, currency),
+		ComparePrice:     priceFromProto(req.ComparePriceMinor, req.ComparePrice, currency),
+		CostPrice:        priceFromProto(req.CostPriceMinor, req.CostPrice, currency),
+		Currency:         currency,
 		Stock:            int(req.Stock),
 		MinStock:         int(req.MinStock),
 		MaxStock:         int(req.MaxStock),
 		Category:         req.Category,
 		SubCategory:      req.SubCategory,
+		CategoryID:       uint32ToUintPtr(req.CategoryId),
 		Brand:            req.Brand,
 		SKU:              req.Sku,
 		Barcode:          req.Barcode,
@@ -53,8 +68,11 @@ func (s *ProductServer) CreateProduct(ctx context.Context, req *productpb.Create
 		SortOrder:        int(req.SortOrder),
 	}
 
-	productResp, err := s.productService.CreateProduct(ctx, appReq)
+	productResp, err := s.productService.CreateProduct(ctx, appReq, actorFromContext(ctx))
 	if err != nil {
+		if errors.Is(err, domain.ErrCategoryNotFound) {
+			return nil, status.Errorf(codes.NotFound, "failed to create product: %v", err)
+		}
 		return nil, status.Errorf(codes.Internal, "failed to create product: %v", err)
 	}
 
@@ -89,18 +107,30 @@ func (s *ProductServer) GetProductBySKU(ctx context.Context, req *productpb.GetP
 
 // UpdateProduct handles product updates
 func (s *ProductServer) UpdateProduct(ctx context.Context, req *productpb.UpdateProductRequest) (*productpb.ProductResponse, error) {
+	// Minor unit prices are expressed in the new currency, or the current one if unchanged
+	currency := req.GetCurrency()
+	if currency == "" && (req.PriceMinor != nil || req.ComparePriceMinor != nil || req.CostPriceMinor != nil) {
+		current, err := s.productService.GetProductByID(ctx, uint(req.Id))
+		if err != nil {
+			return nil, status.Errorf(codes.NotFound, "product not found")
+		}
+		currency = current.Currency
+	}
+
 	appReq := application.UpdateProductRequest{
 		Name:             req.Name,
 		Description:      req.Description,
 		ShortDescription: req.ShortDescription,
-		Price:            req.Price,
-		ComparePrice:     req.ComparePrice,
-		CostPrice:        req.CostPrice,
+		Price:            optionalPriceFromProto(req.PriceMinor, req.Price, currency),
+		ComparePrice:     optionalPriceFromProto(req.ComparePriceMinor, req.ComparePrice, currency),
+		CostPrice:        optionalPriceFromProto(req.CostPriceMinor, req.CostPrice, currency),
+		Currency:         req.Currency,
 		Stock:            int32ToIntPtr(req.Stock),
 		MinStock:         int32ToIntPtr(req.MinStock),
 		MaxStock:         int32ToIntPtr(req.MaxStock),
 		Category:         req.Category,
 		SubCategory:      req.SubCategory,
+		CategoryID:       uint32ToUintPtr(req.CategoryId),
 		Brand:            req.Brand,
 		Barcode:          req.Barcode,
 		Weight:           req.Weight,
@@ -117,8 +147,11 @@ func (s *ProductServer) UpdateProduct(ctx context.Context, req *productpb.Update
 		SortOrder:        int32ToIntPtr(req.SortOrder),
 	}
 
-	productResp, err := s.productService.UpdateProduct(ctx, uint(req.Id), appReq)
+	productResp, err := s.productService.UpdateProduct(ctx, uint(req.Id), appReq, actorFromContext(ctx))
 	if err != nil {
+		if errors.Is(err, domain.ErrCategoryNotFound) {
+			return nil, status.Errorf(codes.NotFound, "failed to update product: %v", err)
+		}
 		return nil, status.Errorf(codes.Internal, "failed to update product: %v", err)
 	}
 
@@ -199,9 +232,50 @@ func (s *ProductServer) ListProductsByCategory(ctx context.Context, req *product
 	}, nil
 }
 
+// GetCategoryTree handles retrieval of the active category tree
+func (s *ProductServer) GetCategoryTree(ctx context.Context, req *productpb.GetCategoryTreeRequest) (*productpb.CategoryTreeResponse, error) {
+	tree, err := s.categoryService.GetCategoryTree(ctx, false)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get category tree: %v", err)
+	}
+
+	categories := make([]*productpb.Category, len(tree.Categories))
+	for i := range tree.Categories {
+		categories[i] = toProtoCategory(&tree.Categories[i])
+	}
+
+	return &productpb.CategoryTreeResponse{
+		Categories: categories,
+	}, nil
+}
+
+// GetCategory handles retrieval of an active category with its subtree by ID or slug
+func (s *ProductServer) GetCategory(ctx context.Context, req *productpb.GetCategoryRequest) (*productpb.CategoryResponse, error) {
+	var category *application.CategoryResponse
+	var err error
+	switch key := req.Key.(type) {
+	case *productpb.GetCategoryRequest_Id:
+		category, err = s.categoryService.GetCategory(ctx, uint(key.Id), false)
+	case *productpb.GetCategoryRequest_Slug:
+		category, err = s.categoryService.GetCategoryBySlug(ctx, key.Slug, false)
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "category id or slug is required")
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrCategoryNotFound) {
+			return nil, status.Errorf(codes.NotFound, "category not found")
+		}
+		return nil, status.Errorf(codes.Internal, "failed to get category: %v", err)
+	}
+
+	return &productpb.CategoryResponse{
+		Category: toProtoCategory(category),
+	}, nil
+}
+
 // UpdateStock handles stock updates
 func (s *ProductServer) UpdateStock(ctx context.Context, req *productpb.UpdateStockRequest) (*productpb.UpdateStockResponse, error) {
-	err := s.productService.UpdateStock(ctx, uint(req.ProductId), int(req.Stock))
+	err := s.productService.UpdateStock(ctx, uint(req.ProductId), uint32ToUintPtr(req.VariantId), int(req.Stock), actorFromContext(ctx))
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to update stock: %v", err)
 	}
@@ -213,7 +287,20 @@ func (s *ProductServer) UpdateStock(ctx context.Context, req *productpb.UpdateSt
 
 // ReduceStock handles stock reduction
 func (s *ProductServer) ReduceStock(ctx context.Context, req *productpb.ReduceStockRequest) (*productpb.ReduceStockResponse, error) {
-	err := s.productService.ReduceStock(ctx, uint(req.ProductId), int(req.Amount))
+	target := application.StockTarget{
+		VariantID: uint32ToUintPtr(req.VariantId),
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+	}
+	if req.WarehouseId != nil {
+		warehouseID := uint(req.GetWarehouseId())
+		target.WarehouseID = &warehouseID
+	}
+
+	err := s.productService.ReduceStock(ctx, uint(req.ProductId), int(req.Amount), target, actorFromContext(ctx))
+	if errors.Is(err, domain.ErrDestinationRequired) {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to reduce stock: %v", err)
+	}
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to reduce stock: %v", err)
 	}
@@ -225,7 +312,15 @@ func (s *ProductServer) ReduceStock(ctx context.Context, req *productpb.ReduceSt
 
 // IncreaseStock handles stock increase
 func (s *ProductServer) IncreaseStock(ctx context.Context, req *productpb.IncreaseStockRequest) (*productpb.IncreaseStockResponse, error) {
-	err := s.productService.IncreaseStock(ctx, uint(req.ProductId), int(req.Amount))
+	target := application.StockTarget{
+		VariantID: uint32ToUintPtr(req.VariantId),
+	}
+	if req.WarehouseId != nil {
+		warehouseID := uint(req.GetWarehouseId())
+		target.WarehouseID = &warehouseID
+	}
+
+	err := s.productService.IncreaseStock(ctx, uint(req.ProductId), int(req.Amount), target, actorFromContext(ctx))
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to increase stock: %v", err)
 	}
@@ -235,6 +330,55 @@ func (s *ProductServer) IncreaseStock(ctx context.Context, req *productpb.Increa
 	}, nil
 }
 
+// ReserveStock handles holding stock for a basket or payment
+func (s *ProductServer) ReserveStock(ctx context.Context, req *productpb.ReserveStockRequest) (*productpb.ReservationResponse, error) {
+	reserveReq := application.ReserveStockRequest{
+		ReferenceType: req.ReferenceType,
+		ReferenceID:   req.ReferenceId,
+		TTL:           time.Duration(req.TtlSeconds) * time.Second,
+	}
+	for _, item := range req.Items {
+		reserveReq.Items = append(reserveReq.Items, application.ReservationItemRequest{
+			ProductID: uint(item.ProductId),
+			VariantID: uint32ToUintPtr(item.VariantId),
+			Quantity:  int(item.Quantity),
+		})
+	}
+
+	reservation, err := s.reservationService.ReserveStock(ctx, reserveReq)
+	if err != nil {
+		return nil, reservationError("failed to reserve stock", err)
+	}
+
+	return &productpb.ReservationResponse{
+		Reservation: toProtoReservation(reservation),
+	}, nil
+}
+
+// ConfirmReservation handles selling the stock held for a basket or payment
+func (s *ProductServer) ConfirmReservation(ctx context.Context, req *productpb.ConfirmReservationRequest) (*productpb.ReservationResponse, error) {
+	reservation, err := s.reservationService.ConfirmReservation(ctx, req.ReferenceType, req.ReferenceId, actorFromContext(ctx))
+	if err != nil {
+		return nil, reservationError("failed to confirm reservation", err)
+	}
+
+	return &productpb.ReservationResponse{
+		Reservation: toProtoReservation(reservation),
+	}, nil
+}
+
+// ReleaseReservation handles giving back the stock held for a basket or payment
+func (s *ProductServer) ReleaseReservation(ctx context.Context, req *productpb.ReleaseReservationRequest) (*productpb.ReservationResponse, error) {
+	reservation, err := s.reservationService.ReleaseReservation(ctx, req.ReferenceType, req.ReferenceId)
+	if err != nil {
+		return nil, reservationError("failed to release reservation", err)
+	}
+
+	return &productpb.ReservationResponse{
+		Reservation: toProtoReservation(reservation),
+	}, nil
+}
+
 // ActivateProduct handles product activation
 func (s *ProductServer) ActivateProduct(ctx context.Context, req *productpb.ActivateProductRequest) (*productpb.ProductResponse, error) {
 	productResp, err := s.productService.ActivateProduct(ctx, uint(req.ProductId))
@@ -304,38 +448,182 @@ func int32ToIntPtr(i *int32) *int {
 	return &val
 }
 
+// Helper function to convert *uint32 to *uint
+func uint32ToUintPtr(i *uint32) *uint {
+	if i == nil {
+		return nil
+	}
+	val := uint(*i)
+	return &val
+}
+
+// Helper function to convert *uint to *uint32
+func uintToUint32Ptr(i *uint) *uint32 {
+	if i == nil {
+		return nil
+	}
+	val := uint32(*i)
+	return &val
+}
+
+// priceFromProto prefers the minor unit price and falls back to the deprecated decimal one
+func priceFromProto(minor int64, decimal float64, currency string) float64 {
+	if minor != 0 {
+		return money.New(minor, currency).Major()
+	}
+	return decimal
+}
+
+// optionalPriceFromProto prefers the minor unit price and falls back to the deprecated decimal one
+func optionalPriceFromProto(minor *int64, decimal *float64, currency string) *float64 {
+	if minor != nil {
+		price := money.New(*minor, currency).Major()
+		return &price
+	}
+	return decimal
+}
+
 // Helper function to convert application.ProductResponse to proto.Product
 func toProtoProduct(p *application.ProductResponse) *productpb.Product {
 	return &productpb.Product{
-		Id:               uint32(p.ID),
-		Name:             p.Name,
-		Description:      p.Description,
-		ShortDescription: p.ShortDescription,
-		Price:            p.Price,
-		ComparePrice:     p.ComparePrice,
-		CostPrice:        p.CostPrice,
-		Stock:            int32(p.Stock),
-		MinStock:         int32(p.MinStock),
-		MaxStock:         int32(p.MaxStock),
-		Category:         p.Category,
-		SubCategory:      p.SubCategory,
-		Brand:            p.Brand,
-		Sku:              p.SKU,
-		Barcode:          p.Barcode,
-		Weight:           p.Weight,
-		Dimensions:       p.Dimensions,
-		Color:            p.Color,
-		Size:             p.Size,
-		Material:         p.Material,
-		Tags:             p.Tags,
-		Images:           p.Images,
-		IsActive:         p.IsActive,
-		IsDigital:        p.IsDigital,
-		IsFeatured:       p.IsFeatured,
-		IsOnSale:         p.IsOnSale,
-		SortOrder:        int32(p.SortOrder),
-		ViewCount:        int32(p.ViewCount),
-		CreatedAt:        timestamppb.New(p.CreatedAt),
-		UpdatedAt:        timestamppb.New(p.UpdatedAt),
+		Id:                uint32(p.ID),
+		Name:              p.Name,
+		Description:       p.Description,
+		ShortDescription:  p.ShortDescription,
+		Price:             p.Price,
+		ComparePrice:      p.ComparePrice,
+		CostPrice:         p.CostPrice,
+		PriceMinor:        p.PriceMinor,
+		ComparePriceMinor: p.ComparePriceMinor,
+		CostPriceMinor:    p.CostPriceMinor,
+		Currency:          p.Currency,
+		Stock:             int32(p.Stock),
+		ReservedStock:     int32(p.ReservedStock),
+		AvailableStock:    int32(p.AvailableStock),
+		CategoryId:        uintToUint32Ptr(p.CategoryID),
+		MinStock:          int32(p.MinStock),
+		MaxStock:          int32(p.MaxStock),
+		Category:          p.Category,
+		SubCategory:       p.SubCategory,
+		Brand:             p.Brand,
+		Sku:               p.SKU,
+		Barcode:           p.Barcode,
+		Weight:            p.Weight,
+		Dimensions:        p.Dimensions,
+		Color:             p.Color,
+		Size:              p.Size,
+		Material:          p.Material,
+		Tags:              p.Tags,
+		Images:            p.Images,
+		IsActive:          p.IsActive,
+		IsDigital:         p.IsDigital,
+		IsFeatured:        p.IsFeatured,
+		IsOnSale:          p.IsOnSale,
+		SortOrder:         int32(p.SortOrder),
+		ViewCount:         int32(p.ViewCount),
+		CreatedAt:         timestamppb.New(p.CreatedAt),
+		UpdatedAt:         timestamppb.New(p.UpdatedAt),
+		Variants:          toProtoVariants(p.Variants),
+	}
+}
+
+// Helper function to convert application.VariantResponses to proto.ProductVariants
+func toProtoVariants(variants []application.VariantResponse) []*productpb.ProductVariant {
+	messages := make([]*productpb.ProductVariant, 0, len(variants))
+	for _, v := range variants {
+		messages = append(messages, &productpb.ProductVariant{
+			Id:             uint32(v.ID),
+			ProductId:      uint32(v.ProductID),
+			Name:           v.Name,
+			Sku:            v.SKU,
+			PriceMinor:     v.PriceMinor,
+			Weight:         v.Weight,
+			Stock:          int32(v.Stock),
+			ReservedStock:  int32(v.ReservedStock),
+			AvailableStock: int32(v.AvailableStock),
+			Color:          v.Color,
+			Size:           v.Size,
+			Material:       v.Material,
+			Image:          v.Image,
+			IsActive:       v.IsActive,
+			SortOrder:      int32(v.SortOrder),
+			CreatedAt:      timestamppb.New(v.CreatedAt),
+			UpdatedAt:      timestamppb.New(v.UpdatedAt),
+		})
+	}
+	return messages
+}
+
+// Helper function to convert application.CategoryResponse to proto.Category with its children
+func toProtoCategory(c *application.CategoryResponse) *productpb.Category {
+	children := make([]*productpb.Category, len(c.Children))
+	for i := range c.Children {
+		children[i] = toProtoCategory(&c.Children[i])
+	}
+
+	return &productpb.Category{
+		Id:          uint32(c.ID),
+		Name:        c.Name,
+		Description: c.Description,
+		Slug:        c.Slug,
+		ParentId:    uintToUint32Ptr(c.ParentID),
+		Depth:       int32(c.Depth),
+		Image:       c.Image,
+		Icon:        c.Icon,
+		SortOrder:   int32(c.SortOrder),
+		IsActive:    c.IsActive,
+		Children:    children,
+		CreatedAt:   timestamppb.New(c.CreatedAt),
+		UpdatedAt:   timestamppb.New(c.UpdatedAt),
+	}
+}
+
+// actorFromContext identifies the authenticated caller for the stock ledger
+func actorFromContext(ctx context.Context) string {
+	userID, ok := ctx.Value("user_id").(uint32)
+	if !ok {
+		return domain.ActorSystem
+	}
+	if role, _ := ctx.Value("user_role").(string); role == "admin" {
+		return domain.AdminActor(uint(userID))
+	}
+	return domain.UserActor(uint(userID))
+}
+
+// reservationError maps reservation errors to gRPC status codes
+func reservationError(msg string, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrInvalidReservation), errors.Is(err, domain.ErrInvalidStockAmount):
+		return status.Errorf(codes.InvalidArgument, "%s: %v", msg, err)
+	case errors.Is(err, domain.ErrReservationNotFound), errors.Is(err, domain.ErrProductNotFound),
+		errors.Is(err, domain.ErrVariantNotFound):
+		return status.Errorf(codes.NotFound, "%s: %v", msg, err)
+	case errors.Is(err, domain.ErrInsufficientStock), errors.Is(err, domain.ErrProductNotActive),
+		errors.Is(err, domain.ErrReservationExpired), errors.Is(err, domain.ErrReservationNotActive):
+		return status.Errorf(codes.FailedPrecondition, "%s: %v", msg, err)
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", msg, err)
+	}
+}
+
+// Helper function to convert application.ReservationResponse to proto.Reservation
+func toProtoReservation(r *application.ReservationResponse) *productpb.Reservation {
+	items := make([]*productpb.ReservationItem, 0, len(r.Items))
+	for _, item := range r.Items {
+		items = append(items, &productpb.ReservationItem{
+			ProductId: uint32(item.ProductID),
+			VariantId: uintToUint32Ptr(item.VariantID),
+			Quantity:  int32(item.Quantity),
+		})
+	}
+
+	return &productpb.Reservation{
+		Id:            r.ID,
+		ReferenceType: r.ReferenceType,
+		ReferenceId:   r.ReferenceID,
+		Status:        r.Status,
+		Items:         items,
+		ExpiresAt:     timestamppb.New(r.ExpiresAt),
+		CreatedAt:     timestamppb.New(r.CreatedAt),
 	}
 }
@@ -0,0 +1,307 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ddd-micro/internal/product/application"
+	"github.com/ddd-micro/internal/product/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// CategoryHandler handles category HTTP requests
+type CategoryHandler struct {
+	categoryService *application.CategoryService
+}
+
+// NewCategoryHandler creates a new category handler
+func NewCategoryHandler(categoryService *application.CategoryService) *CategoryHandler {
+	return &CategoryHandler{
+		categoryService: categoryService,
+	}
+}
+
+// GetCategoryTree retrieves the active category tree
+// @Summary Get category tree
+// @Description Get the tree of active categories from the root categories (Public)
+// @Tags categories
+// @Produce json
+// @Success 200 {object} application.CategoryTreeResponse
+// @Failure 500 {object} map[string]string
+// @Router /categories [get]
+func (h *CategoryHandler) GetCategoryTree(c *gin.Context) {
+	resp, err := h.categoryService.GetCategoryTree(c.Request.Context(), false)
+	if err != nil {
+		writeCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetCategory retrieves an active category with its subtree
+// @Summary Get category
+// @Description Get an active category by ID with its active subcategories (Public)
+// @Tags categories
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} application.CategoryResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /categories/{id} [get]
+func (h *CategoryHandler) GetCategory(c *gin.Context) {
+	id, ok := categoryIDParam(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.categoryService.GetCategory(c.Request.Context(), id, false)
+	if err != nil {
+		writeCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetCategoryBySlug retrieves an active category by slug with its subtree
+// @Summary Get category by slug
+// @Description Get an active category by slug with its active subcategories (Public)
+// @Tags categories
+// @Produce json
+// @Param slug path string true "Category slug"
+// @Success 200 {object} application.CategoryResponse
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /categories/slug/{slug} [get]
+func (h *CategoryHandler) GetCategoryBySlug(c *gin.Context) {
+	resp, err := h.categoryService.GetCategoryBySlug(c.Request.Context(), c.Param("slug"), false)
+	if err != nil {
+		writeCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// CreateCategory creates a new category
+// @Summary Create category
+// @Description Create a new category below a parent or at the root; the slug is derived from the name when empty (Admin only)
+// @Tags admin-categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body application.CreateCategoryRequest true "Category"
+// @Success 201 {object} application.CategoryResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/categories [post]
+func (h *CategoryHandler) CreateCategory(c *gin.Context) {
+	var req application.CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.categoryService.CreateCategory(c.Request.Context(), req)
+	if err != nil {
+		writeCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// GetAdminCategoryTree retrieves the whole category tree
+// @Summary Get full category tree
+// @Description Get the tree of all categories, inactive ones included (Admin only)
+// @Tags admin-categories
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} application.CategoryTreeResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/categories [get]
+func (h *CategoryHandler) GetAdminCategoryTree(c *gin.Context) {
+	resp, err := h.categoryService.GetCategoryTree(c.Request.Context(), true)
+	if err != nil {
+		writeCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetAdminCategory retrieves a category with its whole subtree
+// @Summary Get category subtree
+// @Description Get a category by ID with all its subcategories, inactive ones included (Admin only)
+// @Tags admin-categories
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Category ID"
+// @Success 200 {object} application.CategoryResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/categories/{id} [get]
+func (h *CategoryHandler) GetAdminCategory(c *gin.Context) {
+	id, ok := categoryIDParam(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.categoryService.GetCategory(c.Request.Context(), id, true)
+	if err != nil {
+		writeCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateCategory updates a category
+// @Summary Update category
+// @Description Update a category; its place in the tree is changed by moving it (Admin only)
+// @Tags admin-categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Category ID"
+// @Param request body application.UpdateCategoryRequest true "Category changes"
+// @Success 200 {object} application.CategoryResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/categories/{id} [put]
+func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
+	id, ok := categoryIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req application.UpdateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.categoryService.UpdateCategory(c.Request.Context(), id, req)
+	if err != nil {
+		writeCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// MoveCategory moves a category within the tree
+// @Summary Move category
+// @Description Move a category with its subtree below another parent or to the root, and place it among its siblings (Admin only)
+// @Tags admin-categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Category ID"
+// @Param request body application.MoveCategoryRequest true "New parent and position"
+// @Success 200 {object} application.CategoryResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/categories/{id}/move [post]
+func (h *CategoryHandler) MoveCategory(c *gin.Context) {
+	id, ok := categoryIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req application.MoveCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.categoryService.MoveCategory(c.Request.Context(), id, req)
+	if err != nil {
+		writeCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteCategory deletes a category
+// @Summary Delete category
+// @Description Delete a category without subcategories or products (Admin only)
+// @Tags admin-categories
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Category ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/categories/{id} [delete]
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	id, ok := categoryIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.categoryService.DeleteCategory(c.Request.Context(), id); err != nil {
+		writeCategoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Category deleted successfully",
+	})
+}
+
+// categoryIDParam parses the category ID path parameter, responding with 400 when invalid
+func categoryIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid category ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// writeCategoryError maps category errors to HTTP responses
+func writeCategoryError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrCategoryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidCategoryData), errors.Is(err, domain.ErrInvalidCategoryMove):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrCategorySlugTaken), errors.Is(err, domain.ErrCategoryNotEmpty):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
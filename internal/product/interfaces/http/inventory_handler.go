@@ -0,0 +1,294 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ddd-micro/internal/product/application"
+	"github.com/ddd-micro/internal/product/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// InventoryHandler handles stock level and stock ledger HTTP requests
+type InventoryHandler struct {
+	inventoryService *application.InventoryService
+}
+
+// NewInventoryHandler creates a new inventory handler
+func NewInventoryHandler(inventoryService *application.InventoryService) *InventoryHandler {
+	return &InventoryHandler{
+		inventoryService: inventoryService,
+	}
+}
+
+// ListStockMovements lists the stock ledger
+// @Summary List stock movements
+// @Description Get stock movements, newest first, optionally filtered (Admin only)
+// @Tags admin-inventory
+// @Produce json
+// @Security BearerAuth
+// @Param product_id query int false "Filter by product ID"
+// @Param variant_id query int false "Filter by variant ID"
+// @Param warehouse_id query int false "Filter by warehouse ID"
+// @Param reason query string false "Filter by reason (sale, refund, manual, import, reservation)"
+// @Param order_id query string false "Filter by order ID"
+// @Param payment_id query string false "Filter by payment ID"
+// @Param offset query int false "Offset for pagination" default(0)
+// @Param limit query int false "Limit for pagination" default(20)
+// @Success 200 {object} application.ListStockMovementsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/inventory/movements [get]
+func (h *InventoryHandler) ListStockMovements(c *gin.Context) {
+	var req application.ListStockMovementsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	h.listStockMovements(c, req)
+}
+
+// ListProductStockMovements lists the stock ledger of a product
+// @Summary List stock movements of a product
+// @Description Get the stock movements of a product, newest first (Admin only)
+// @Tags admin-inventory
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param reason query string false "Filter by reason (sale, refund, manual, import, reservation)"
+// @Param offset query int false "Offset for pagination" default(0)
+// @Param limit query int false "Limit for pagination" default(20)
+// @Success 200 {object} application.ListStockMovementsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/products/{id}/stock-movements [get]
+func (h *InventoryHandler) ListProductStockMovements(c *gin.Context) {
+	id, ok := productIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req application.ListStockMovementsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	req.ProductID = &id
+
+	h.listStockMovements(c, req)
+}
+
+// listStockMovements responds with the stock movements matching the request
+func (h *InventoryHandler) listStockMovements(c *gin.Context, req application.ListStockMovementsRequest) {
+	resp, err := h.inventoryService.ListStockMovements(c.Request.Context(), req)
+	if err != nil {
+		writeInventoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RebuildStock rebuilds the stock of a product from the stock ledger
+// @Summary Rebuild product stock
+// @Description Replace the stock of a product with the balance of its stock movements (Admin only)
+// @Tags admin-inventory
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Success 200 {object} application.RebuildStockResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/products/{id}/stock/rebuild [post]
+func (h *InventoryHandler) RebuildStock(c *gin.Context) {
+	id, ok := productIDParam(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.inventoryService.RebuildStock(c.Request.Context(), id)
+	if err != nil {
+		writeInventoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RebuildAllStock rebuilds the stock of every product from the stock ledger
+// @Summary Rebuild all stock
+// @Description Replace the stock of every product with the balance of its stock movements and list the products that drifted (Admin only)
+// @Tags admin-inventory
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} application.RebuildAllStockResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/inventory/rebuild [post]
+func (h *InventoryHandler) RebuildAllStock(c *gin.Context) {
+	resp, err := h.inventoryService.RebuildAllStock(c.Request.Context())
+	if err != nil {
+		writeInventoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListStockLevels lists the stock of a product per warehouse
+// @Summary List stock levels of a product
+// @Description Get the stock of a product and its variants in every warehouse (Admin only)
+// @Tags admin-inventory
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Success 200 {object} application.ProductStockLevelsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/products/{id}/stock-levels [get]
+func (h *InventoryHandler) ListStockLevels(c *gin.Context) {
+	id, ok := productIDParam(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.inventoryService.ListStockLevels(c.Request.Context(), id)
+	if err != nil {
+		writeInventoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// SetStockLevel counts the stock of a product or variant in a warehouse
+// @Summary Set stock level of a product in a warehouse
+// @Description Set the counted stock and the low stock threshold of a product, or of the variant given in the body, in a warehouse; the difference is recorded as a manual stock movement (Admin only)
+// @Tags admin-inventory
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param warehouse_id path int true "Warehouse ID"
+// @Param request body application.SetStockLevelRequest true "Stock level"
+// @Success 200 {object} application.StockLevelResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/products/{id}/stock-levels/{warehouse_id} [put]
+func (h *InventoryHandler) SetStockLevel(c *gin.Context) {
+	id, ok := productIDParam(c)
+	if !ok {
+		return
+	}
+
+	warehouseID, err := strconv.ParseUint(c.Param("warehouse_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid warehouse ID",
+		})
+		return
+	}
+
+	var req application.SetStockLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.inventoryService.SetStockLevel(c.Request.Context(), id, uint(warehouseID), req, adminActor(c))
+	if err != nil {
+		writeInventoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListLowStock lists stock levels at or below their warehouse threshold
+// @Summary List low stock
+// @Description Get the stock levels at or below the low stock threshold of their warehouse, lowest first (Admin only)
+// @Tags admin-inventory
+// @Produce json
+// @Security BearerAuth
+// @Param warehouse_id query int false "Filter by warehouse ID"
+// @Param offset query int false "Offset for pagination" default(0)
+// @Param limit query int false "Limit for pagination" default(20)
+// @Success 200 {object} application.ListLowStockResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/inventory/low-stock [get]
+func (h *InventoryHandler) ListLowStock(c *gin.Context) {
+	var req application.ListLowStockRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.inventoryService.ListLowStock(c.Request.Context(), req)
+	if err != nil {
+		writeInventoryError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// productIDParam parses the product ID path parameter, responding with 400 when invalid
+func productIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid product ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// adminActor identifies the authenticated administrator as the actor of stock movements
+func adminActor(c *gin.Context) string {
+	value, _ := c.Get("user_id")
+	if userID, ok := value.(uint32); ok {
+		return domain.AdminActor(uint(userID))
+	}
+	return domain.ActorSystem
+}
+
+// writeInventoryError maps inventory errors to HTTP responses
+func writeInventoryError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrProductNotFound), errors.Is(err, domain.ErrWarehouseNotFound),
+		errors.Is(err, domain.ErrVariantNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidStockAmount), errors.Is(err, domain.ErrInvalidStockMovement):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -9,17 +9,21 @@ import (
 // ProviderSet is the Wire provider set for HTTP interface layer
 var ProviderSet = wire.NewSet(
 	NewProductHandler,
+	NewInventoryHandler,
+	NewWarehouseHandler,
+	NewCategoryHandler,
+	NewVariantHandler,
 	NewUserHandler,
 	NewAuthMiddleware,
 	NewHTTPRouter,
 )
 
 // NewHTTPRouter creates a new HTTP router with all routes
-func NewHTTPRouter(productHandler *ProductHandler, userHandler *UserHandler, authMiddleware *AuthMiddleware, metrics *monitoring.PrometheusMetrics, tracer *monitoring.JaegerTracer) *gin.Engine {
+func NewHTTPRouter(productHandler *ProductHandler, inventoryHandler *InventoryHandler, warehouseHandler *WarehouseHandler, categoryHandler *CategoryHandler, variantHandler *VariantHandler, userHandler *UserHandler, authMiddleware *AuthMiddleware, metrics *monitoring.PrometheusMetrics, tracer *monitoring.JaegerTracer) *gin.Engine {
 	router := gin.Default()
 
 	// Setup routes
-	SetupRoutes(router, productHandler, userHandler, authMiddleware, metrics, tracer)
+	SetupRoutes(router, productHandler, inventoryHandler, warehouseHandler, categoryHandler, variantHandler, userHandler, authMiddleware, metrics, tracer)
 
 	return router
 }
@@ -7,7 +7,7 @@ import (
 )
 
 // SetupRoutes sets up all HTTP routes with RBAC
-func SetupRoutes(router *gin.Engine, productHandler *ProductHandler, userHandler *UserHandler, authMiddleware *AuthMiddleware, metrics *monitoring.PrometheusMetrics, tracer *monitoring.JaegerTracer) {
+func SetupRoutes(router *gin.Engine, productHandler *ProductHandler, inventoryHandler *InventoryHandler, warehouseHandler *WarehouseHandler, categoryHandler *CategoryHandler, variantHandler *VariantHandler, userHandler *UserHandler, authMiddleware *AuthMiddleware, metrics *monitoring.PrometheusMetrics, tracer *monitoring.JaegerTracer) {
 	// Add monitoring middlewares
 	router.Use(monitoring.PrometheusMiddleware(metrics))
 	router.Use(monitoring.JaegerMiddleware(tracer))
@@ -27,6 +27,14 @@ func SetupRoutes(router *gin.Engine, productHandler *ProductHandler, userHandler
 			public.POST("/:id/view", productHandler.IncrementViewCount)
 		}
 
+		// Public category routes (no authentication required)
+		categories := v1.Group("/categories")
+		{
+			categories.GET("", categoryHandler.GetCategoryTree)
+			categories.GET("/slug/:slug", categoryHandler.GetCategoryBySlug)
+			categories.GET("/:id", categoryHandler.GetCategory)
+		}
+
 		// User routes (authentication required)
 		users := v1.Group("/users")
 		users.Use(authMiddleware.AuthRequired())
@@ -49,6 +57,47 @@ func SetupRoutes(router *gin.Engine, productHandler *ProductHandler, userHandler
 			admin.POST("/:id/deactivate", productHandler.DeactivateProduct)
 			admin.POST("/:id/featured", productHandler.MarkAsFeatured)
 			admin.DELETE("/:id/featured", productHandler.UnmarkAsFeatured)
+			admin.GET("/:id/stock-movements", inventoryHandler.ListProductStockMovements)
+			admin.POST("/:id/stock/rebuild", inventoryHandler.RebuildStock)
+			admin.GET("/:id/stock-levels", inventoryHandler.ListStockLevels)
+			admin.PUT("/:id/stock-levels/:warehouse_id", inventoryHandler.SetStockLevel)
+			admin.POST("/:id/variants", variantHandler.CreateVariant)
+			admin.GET("/:id/variants", variantHandler.ListVariants)
+			admin.GET("/:id/variants/:variant_id", variantHandler.GetVariant)
+			admin.PUT("/:id/variants/:variant_id", variantHandler.UpdateVariant)
+			admin.DELETE("/:id/variants/:variant_id", variantHandler.DeleteVariant)
+		}
+
+		// Admin inventory routes (admin access required)
+		inventory := v1.Group("/admin/inventory")
+		inventory.Use(authMiddleware.AdminRequired())
+		{
+			inventory.GET("/movements", inventoryHandler.ListStockMovements)
+			inventory.POST("/rebuild", inventoryHandler.RebuildAllStock)
+			inventory.GET("/low-stock", inventoryHandler.ListLowStock)
+		}
+
+		// Admin warehouse routes (admin access required)
+		warehouses := v1.Group("/admin/warehouses")
+		warehouses.Use(authMiddleware.AdminRequired())
+		{
+			warehouses.POST("", warehouseHandler.CreateWarehouse)
+			warehouses.GET("", warehouseHandler.ListWarehouses)
+			warehouses.GET("/:id", warehouseHandler.GetWarehouse)
+			warehouses.PUT("/:id", warehouseHandler.UpdateWarehouse)
+			warehouses.DELETE("/:id", warehouseHandler.DeleteWarehouse)
+		}
+
+		// Admin category routes (admin access required)
+		adminCategories := v1.Group("/admin/categories")
+		adminCategories.Use(authMiddleware.AdminRequired())
+		{
+			adminCategories.POST("", categoryHandler.CreateCategory)
+			adminCategories.GET("", categoryHandler.GetAdminCategoryTree)
+			adminCategories.GET("/:id", categoryHandler.GetAdminCategory)
+			adminCategories.PUT("/:id", categoryHandler.UpdateCategory)
+			adminCategories.DELETE("/:id", categoryHandler.DeleteCategory)
+			adminCategories.POST("/:id/move", categoryHandler.MoveCategory)
 		}
 	}
 }
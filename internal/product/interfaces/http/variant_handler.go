@@ -0,0 +1,234 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ddd-micro/internal/product/application"
+	"github.com/ddd-micro/internal/product/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// VariantHandler handles product variant HTTP requests
+type VariantHandler struct {
+	variantService *application.VariantService
+}
+
+// NewVariantHandler creates a new variant handler
+func NewVariantHandler(variantService *application.VariantService) *VariantHandler {
+	return &VariantHandler{
+		variantService: variantService,
+	}
+}
+
+// CreateVariant creates a new variant of a product
+// @Summary Create product variant
+// @Description Create a new variant of a product; a zero price or weight falls back to the product's, and the initial stock is recorded as a manual stock movement (Admin only)
+// @Tags admin-variants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param request body application.CreateVariantRequest true "Variant"
+// @Success 201 {object} application.VariantResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/products/{id}/variants [post]
+func (h *VariantHandler) CreateVariant(c *gin.Context) {
+	productID, ok := productIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req application.CreateVariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.variantService.CreateVariant(c.Request.Context(), productID, req, adminActor(c))
+	if err != nil {
+		writeVariantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListVariants lists the variants of a product
+// @Summary List product variants
+// @Description Get the variants of a product in sort order, with their stock per warehouse (Admin only)
+// @Tags admin-variants
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Success 200 {object} application.ListVariantsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/products/{id}/variants [get]
+func (h *VariantHandler) ListVariants(c *gin.Context) {
+	productID, ok := productIDParam(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.variantService.ListVariants(c.Request.Context(), productID)
+	if err != nil {
+		writeVariantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetVariant retrieves a variant of a product
+// @Summary Get product variant
+// @Description Get a variant of a product with its stock per warehouse (Admin only)
+// @Tags admin-variants
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param variant_id path int true "Variant ID"
+// @Success 200 {object} application.VariantResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/products/{id}/variants/{variant_id} [get]
+func (h *VariantHandler) GetVariant(c *gin.Context) {
+	productID, ok := productIDParam(c)
+	if !ok {
+		return
+	}
+	id, ok := variantIDParam(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.variantService.GetVariant(c.Request.Context(), productID, id)
+	if err != nil {
+		writeVariantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateVariant updates a variant of a product
+// @Summary Update product variant
+// @Description Update a variant of a product; a counted stock is set with the difference recorded as a manual stock movement (Admin only)
+// @Tags admin-variants
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param variant_id path int true "Variant ID"
+// @Param request body application.UpdateVariantRequest true "Variant changes"
+// @Success 200 {object} application.VariantResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/products/{id}/variants/{variant_id} [put]
+func (h *VariantHandler) UpdateVariant(c *gin.Context) {
+	productID, ok := productIDParam(c)
+	if !ok {
+		return
+	}
+	id, ok := variantIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req application.UpdateVariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.variantService.UpdateVariant(c.Request.Context(), productID, id, req, adminActor(c))
+	if err != nil {
+		writeVariantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteVariant deletes a variant of a product
+// @Summary Delete product variant
+// @Description Delete a variant of a product that holds no stock, sold or reserved (Admin only)
+// @Tags admin-variants
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param variant_id path int true "Variant ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/products/{id}/variants/{variant_id} [delete]
+func (h *VariantHandler) DeleteVariant(c *gin.Context) {
+	productID, ok := productIDParam(c)
+	if !ok {
+		return
+	}
+	id, ok := variantIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.variantService.DeleteVariant(c.Request.Context(), productID, id); err != nil {
+		writeVariantError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Variant deleted successfully",
+	})
+}
+
+// variantIDParam parses the variant ID path parameter, responding with 400 when invalid
+func variantIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("variant_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid variant ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// writeVariantError maps variant errors to HTTP responses
+func writeVariantError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrProductNotFound), errors.Is(err, domain.ErrVariantNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidProductData), errors.Is(err, domain.ErrInvalidStockAmount):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrVariantAlreadyExists), errors.Is(err, domain.ErrVariantNotEmpty),
+		errors.Is(err, domain.ErrNoWarehouse), errors.Is(err, domain.ErrInsufficientStock):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
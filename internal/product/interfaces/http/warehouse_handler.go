@@ -0,0 +1,214 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ddd-micro/internal/product/application"
+	"github.com/ddd-micro/internal/product/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// WarehouseHandler handles warehouse HTTP requests
+type WarehouseHandler struct {
+	warehouseService *application.WarehouseService
+}
+
+// NewWarehouseHandler creates a new warehouse handler
+func NewWarehouseHandler(warehouseService *application.WarehouseService) *WarehouseHandler {
+	return &WarehouseHandler{
+		warehouseService: warehouseService,
+	}
+}
+
+// CreateWarehouse creates a new warehouse
+// @Summary Create warehouse
+// @Description Create a new warehouse to keep stock in (Admin only)
+// @Tags admin-warehouses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body application.CreateWarehouseRequest true "Warehouse"
+// @Success 201 {object} application.WarehouseResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/warehouses [post]
+func (h *WarehouseHandler) CreateWarehouse(c *gin.Context) {
+	var req application.CreateWarehouseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.warehouseService.CreateWarehouse(c.Request.Context(), req)
+	if err != nil {
+		writeWarehouseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListWarehouses lists warehouses
+// @Summary List warehouses
+// @Description Get warehouses ordered by priority (Admin only)
+// @Tags admin-warehouses
+// @Produce json
+// @Security BearerAuth
+// @Param offset query int false "Offset for pagination" default(0)
+// @Param limit query int false "Limit for pagination" default(20)
+// @Success 200 {object} application.ListWarehousesResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/warehouses [get]
+func (h *WarehouseHandler) ListWarehouses(c *gin.Context) {
+	var req application.ListWarehousesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.warehouseService.ListWarehouses(c.Request.Context(), req)
+	if err != nil {
+		writeWarehouseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetWarehouse retrieves a warehouse
+// @Summary Get warehouse
+// @Description Get a warehouse by ID (Admin only)
+// @Tags admin-warehouses
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Warehouse ID"
+// @Success 200 {object} application.WarehouseResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/warehouses/{id} [get]
+func (h *WarehouseHandler) GetWarehouse(c *gin.Context) {
+	id, ok := warehouseIDParam(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.warehouseService.GetWarehouse(c.Request.Context(), id)
+	if err != nil {
+		writeWarehouseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateWarehouse updates a warehouse
+// @Summary Update warehouse
+// @Description Update a warehouse; it can only be deactivated once it holds no stock (Admin only)
+// @Tags admin-warehouses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Warehouse ID"
+// @Param request body application.UpdateWarehouseRequest true "Warehouse changes"
+// @Success 200 {object} application.WarehouseResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/warehouses/{id} [put]
+func (h *WarehouseHandler) UpdateWarehouse(c *gin.Context) {
+	id, ok := warehouseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req application.UpdateWarehouseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.warehouseService.UpdateWarehouse(c.Request.Context(), id, req)
+	if err != nil {
+		writeWarehouseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteWarehouse deletes a warehouse
+// @Summary Delete warehouse
+// @Description Delete a warehouse that holds no stock (Admin only)
+// @Tags admin-warehouses
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Warehouse ID"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /admin/warehouses/{id} [delete]
+func (h *WarehouseHandler) DeleteWarehouse(c *gin.Context) {
+	id, ok := warehouseIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.warehouseService.DeleteWarehouse(c.Request.Context(), id); err != nil {
+		writeWarehouseError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Warehouse deleted successfully",
+	})
+}
+
+// warehouseIDParam parses the warehouse ID path parameter, responding with 400 when invalid
+func warehouseIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid warehouse ID",
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// writeWarehouseError maps warehouse errors to HTTP responses
+func writeWarehouseError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrWarehouseNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidWarehouseData):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrWarehouseAlreadyExists), errors.Is(err, domain.ErrWarehouseNotEmpty):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
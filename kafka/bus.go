@@ -0,0 +1,37 @@
+package kafka
+
+import "fmt"
+
+// Bus selects the transport events are published and consumed on
+type Bus string
+
+const (
+	// BusKafka sends events through the Kafka brokers
+	BusKafka Bus = "kafka"
+	// BusMemory sends events through the process-wide memory bus, see DefaultMemoryBus
+	BusMemory Bus = "memory"
+)
+
+// NewPublisher creates the event publisher of the configured bus
+func NewPublisher(config *PublisherConfig) (EventPublisher, error) {
+	switch config.Bus {
+	case BusKafka, "":
+		return NewKafkaPublisher(config)
+	case BusMemory:
+		return NewMemoryPublisher(DefaultMemoryBus(), config), nil
+	default:
+		return nil, fmt.Errorf("unknown event bus %q", config.Bus)
+	}
+}
+
+// NewConsumer creates the event consumer of the configured bus
+func NewConsumer(config *ConsumerConfig) (EventConsumer, error) {
+	switch config.Bus {
+	case BusKafka, "":
+		return NewKafkaConsumer(config)
+	case BusMemory:
+		return NewMemoryConsumer(DefaultMemoryBus(), config), nil
+	default:
+		return nil, fmt.Errorf("unknown event bus %q", config.Bus)
+	}
+}
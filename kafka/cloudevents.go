@@ -0,0 +1,116 @@
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/IBM/sarama"
+)
+
+// CloudEvents attributes shared by all events
+const (
+	CloudEventsSpecVersion = "1.0"
+	ContentTypeJSON        = "application/json"
+	// ContentTypeCloudEventsJSON is the content type of events in structured mode
+	ContentTypeCloudEventsJSON = "application/cloudevents+json"
+)
+
+// Headers of the CloudEvents Kafka protocol binding
+const (
+	HeaderContentType = "content-type"
+	// cloudEventsHeaderPrefix prefixes the attributes of events in binary mode
+	cloudEventsHeaderPrefix = "ce_"
+	headerSpecVersion       = cloudEventsHeaderPrefix + "specversion"
+	headerCloudEventType    = cloudEventsHeaderPrefix + "type"
+)
+
+// CloudEventsMode selects how events are laid out in Kafka messages
+type CloudEventsMode string
+
+const (
+	// CloudEventsStructured puts the whole event, attributes and data, in the message value
+	CloudEventsStructured CloudEventsMode = "structured"
+	// CloudEventsBinary puts the attributes in ce_ headers and only the data in the message value
+	CloudEventsBinary CloudEventsMode = "binary"
+)
+
+// encodeCloudEvent lays out a serialized event in a Kafka message in the given mode
+func encodeCloudEvent(mode CloudEventsMode, payload []byte) ([]sarama.RecordHeader, []byte, error) {
+	if mode != CloudEventsBinary {
+		return []sarama.RecordHeader{
+			{Key: []byte(HeaderContentType), Value: []byte(ContentTypeCloudEventsJSON)},
+		}, payload, nil
+	}
+
+	var attributes map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &attributes); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal event attributes: %w", err)
+	}
+
+	var headers []sarama.RecordHeader
+	for name, raw := range attributes {
+		if name == "data" {
+			continue
+		}
+
+		// String attributes are sent unquoted, anything else as its JSON literal
+		value := string(raw)
+		var text string
+		if err := json.Unmarshal(raw, &text); err == nil {
+			value = text
+		}
+
+		key := cloudEventsHeaderPrefix + name
+		if name == "datacontenttype" {
+			key = HeaderContentType
+		}
+		headers = append(headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+	}
+
+	return headers, attributes["data"], nil
+}
+
+// decodeCloudEvent returns a consumed event in structured form, whichever mode it was sent in
+func decodeCloudEvent(headers []*sarama.RecordHeader, value []byte) ([]byte, error) {
+	if headerValue(headers, headerSpecVersion) == "" {
+		// Structured mode, or an event published before CloudEvents
+		return value, nil
+	}
+
+	event := make(map[string]interface{})
+	for _, header := range headers {
+		key := string(header.Key)
+		switch {
+		case key == HeaderContentType:
+			event["datacontenttype"] = string(header.Value)
+		case strings.HasPrefix(key, cloudEventsHeaderPrefix):
+			event[strings.TrimPrefix(key, cloudEventsHeaderPrefix)] = string(header.Value)
+		}
+	}
+	if len(value) > 0 {
+		event["data"] = json.RawMessage(value)
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal binary mode event: %w", err)
+	}
+	return data, nil
+}
+
+// messageEventType returns the event type of a consumed message in either mode
+func messageEventType(headers []*sarama.RecordHeader, value []byte) EventType {
+	if eventType := headerValue(headers, "event-type"); eventType != "" {
+		return EventType(eventType)
+	}
+	if eventType := headerValue(headers, headerCloudEventType); eventType != "" {
+		return EventType(eventType)
+	}
+
+	var event BaseEvent
+	if err := json.Unmarshal(value, &event); err != nil {
+		return ""
+	}
+	return event.Type
+}
@@ -0,0 +1,96 @@
+package kafka
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/IBM/sarama"
+)
+
+// Codec selects how the data of events is encoded in Kafka messages
+type Codec string
+
+const (
+	// CodecJSON sends events as JSON, in structured or binary CloudEvents mode
+	CodecJSON Codec = "json"
+	// CodecProtobuf sends the data as an api/proto/events message, in binary CloudEvents mode
+	CodecProtobuf Codec = "protobuf"
+)
+
+// ContentTypeProtobuf is the content type of event data encoded with the protobuf codec
+const ContentTypeProtobuf = "application/protobuf"
+
+// encodeMessage lays out a structured JSON event in a Kafka message with the given codec
+func encodeMessage(codec Codec, mode CloudEventsMode, eventType EventType, payload []byte) ([]sarama.RecordHeader, []byte, error) {
+	if codec != CodecProtobuf {
+		return encodeCloudEvent(mode, payload)
+	}
+
+	headers, data, err := encodeCloudEvent(CloudEventsBinary, payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range headers {
+		if string(headers[i].Key) == HeaderContentType {
+			headers[i].Value = []byte(ContentTypeProtobuf)
+		}
+	}
+
+	value, err := marshalProtoData(eventType, data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return headers, value, nil
+}
+
+// decodeMessage returns a consumed event as structured JSON, whichever codec and
+// CloudEvents mode it was sent with
+func decodeMessage(headers []*sarama.RecordHeader, value []byte) ([]byte, error) {
+	if headerValue(headers, HeaderContentType) != ContentTypeProtobuf {
+		return decodeCloudEvent(headers, value)
+	}
+
+	eventType := EventType(headerValue(headers, headerCloudEventType))
+	data, err := unmarshalProtoData(eventType, value)
+	if err != nil {
+		return nil, err
+	}
+
+	// The decoded data is JSON, so the structured event must say so
+	jsonHeaders := make([]*sarama.RecordHeader, 0, len(headers))
+	for _, header := range headers {
+		if string(header.Key) == HeaderContentType {
+			header = &sarama.RecordHeader{Key: header.Key, Value: []byte(ContentTypeJSON)}
+		}
+		jsonHeaders = append(jsonHeaders, header)
+	}
+	return decodeCloudEvent(jsonHeaders, data)
+}
+
+// parseCodecs parses per-topic codecs such as "payment-events=protobuf,stock-events=json"
+func parseCodecs(value string) (map[string]Codec, error) {
+	codecs := make(map[string]Codec)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		topic, codec, found := strings.Cut(part, "=")
+		if !found || (Codec(codec) != CodecJSON && Codec(codec) != CodecProtobuf) {
+			return nil, fmt.Errorf("invalid topic codec %q", part)
+		}
+		codecs[strings.TrimSpace(topic)] = Codec(codec)
+	}
+	return codecs, nil
+}
+
+// codecFor returns the codec configured for a topic
+func (c *PublisherConfig) codecFor(topic string) Codec {
+	if codec, exists := c.TopicCodecs[topic]; exists {
+		return codec
+	}
+	if c.Codec != "" {
+		return c.Codec
+	}
+	return CodecJSON
+}
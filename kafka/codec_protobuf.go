@@ -0,0 +1,383 @@
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+
+	eventspb "github.com/ddd-micro/api/proto/events"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// protoConverter converts the JSON data of an event type to and from its protobuf message
+type protoConverter struct {
+	marshal   func(data []byte) ([]byte, error)
+	unmarshal func(value []byte) ([]byte, error)
+}
+
+// protoConverters holds the protobuf conversion of the data of every event type
+var protoConverters = map[EventType]protoConverter{
+	EventTypePaymentCompleted:     newProtoConverter(paymentCompletedToProto, paymentCompletedFromProto),
+	EventTypePaymentFailed:        newProtoConverter(paymentFailedToProto, paymentFailedFromProto),
+	EventTypePaymentCancelled:     newProtoConverter(paymentCancelledToProto, paymentCancelledFromProto),
+	EventTypePaymentRefunded:      newProtoConverter(paymentRefundedToProto, paymentRefundedFromProto),
+	EventTypeStockUpdated:         newProtoConverter(stockUpdatedToProto, stockUpdatedFromProto),
+	EventTypeBasketCleared:        newProtoConverter(basketClearedToProto, basketClearedFromProto),
+	EventTypeOrderCreated:         newProtoConverter(orderCreatedToProto, orderCreatedFromProto),
+	EventTypeOrderCancelled:       newProtoConverter(orderCancelledToProto, orderCancelledFromProto),
+	EventTypeOrderPaymentRejected: newProtoConverter(orderPaymentRejectedToProto, orderPaymentRejectedFromProto),
+}
+
+// newProtoConverter builds the converter of a data struct D and its protobuf message M
+func newProtoConverter[D any, M proto.Message](toProto func(D) (M, error), fromProto func(M) D) protoConverter {
+	return protoConverter{
+		marshal: func(data []byte) ([]byte, error) {
+			var value D
+			if err := json.Unmarshal(data, &value); err != nil {
+				return nil, err
+			}
+			message, err := toProto(value)
+			if err != nil {
+				return nil, err
+			}
+			return proto.Marshal(message)
+		},
+		unmarshal: func(value []byte) ([]byte, error) {
+			var message M
+			message = message.ProtoReflect().Type().New().Interface().(M)
+			if err := proto.Unmarshal(value, message); err != nil {
+				return nil, err
+			}
+			return json.Marshal(fromProto(message))
+		},
+	}
+}
+
+// marshalProtoData encodes the JSON data of an event as its protobuf message
+func marshalProtoData(eventType EventType, data []byte) ([]byte, error) {
+	converter, exists := protoConverters[eventType]
+	if !exists {
+		return nil, fmt.Errorf("no protobuf message for %s events", eventType)
+	}
+	value, err := converter.marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s data as protobuf: %w", eventType, err)
+	}
+	return value, nil
+}
+
+// unmarshalProtoData decodes the protobuf message of an event into its JSON data
+func unmarshalProtoData(eventType EventType, value []byte) ([]byte, error) {
+	converter, exists := protoConverters[eventType]
+	if !exists {
+		return nil, fmt.Errorf("no protobuf message for %s events", eventType)
+	}
+	data, err := converter.unmarshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s protobuf data: %w", eventType, err)
+	}
+	return data, nil
+}
+
+func paymentItemsToProto(items []PaymentItem) []*eventspb.PaymentItem {
+	var messages []*eventspb.PaymentItem
+	for _, item := range items {
+		messages = append(messages, &eventspb.PaymentItem{
+			ProductId:       uint32(item.ProductID),
+			VariantId:       uintToUint32Ptr(item.VariantID),
+			Quantity:        int32(item.Quantity),
+			UnitPriceMinor:  item.UnitPriceMinor,
+			TotalPriceMinor: item.TotalPriceMinor,
+		})
+	}
+	return messages
+}
+
+func paymentItemsFromProto(messages []*eventspb.PaymentItem) []PaymentItem {
+	var items []PaymentItem
+	for _, message := range messages {
+		items = append(items, PaymentItem{
+			ProductID:       uint(message.ProductId),
+			VariantID:       uint32ToUintPtr(message.VariantId),
+			Quantity:        int(message.Quantity),
+			UnitPriceMinor:  message.UnitPriceMinor,
+			TotalPriceMinor: message.TotalPriceMinor,
+		})
+	}
+	return items
+}
+
+func paymentCompletedToProto(data PaymentCompletedData) (*eventspb.PaymentCompletedData, error) {
+	message := &eventspb.PaymentCompletedData{
+		PaymentId:     data.PaymentID,
+		UserId:        uint32(data.UserID),
+		OrderId:       data.OrderID,
+		AmountMinor:   data.AmountMinor,
+		Currency:      data.Currency,
+		PaymentMethod: data.PaymentMethod,
+		Items:         paymentItemsToProto(data.Items),
+		BasketId:      data.BasketID,
+	}
+	if data.Metadata != nil {
+		metadata, err := structpb.NewStruct(data.Metadata)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metadata: %w", err)
+		}
+		message.Metadata = metadata
+	}
+	return message, nil
+}
+
+func paymentCompletedFromProto(message *eventspb.PaymentCompletedData) PaymentCompletedData {
+	data := PaymentCompletedData{
+		PaymentID:     message.PaymentId,
+		UserID:        uint(message.UserId),
+		OrderID:       message.OrderId,
+		AmountMinor:   message.AmountMinor,
+		Currency:      message.Currency,
+		PaymentMethod: message.PaymentMethod,
+		Items:         paymentItemsFromProto(message.Items),
+		BasketID:      message.BasketId,
+	}
+	if message.Metadata != nil {
+		data.Metadata = message.Metadata.AsMap()
+	}
+	return data
+}
+
+func paymentFailedToProto(data PaymentFailedData) (*eventspb.PaymentFailedData, error) {
+	return &eventspb.PaymentFailedData{
+		PaymentId:     data.PaymentID,
+		UserId:        uint32(data.UserID),
+		OrderId:       data.OrderID,
+		AmountMinor:   data.AmountMinor,
+		Currency:      data.Currency,
+		PaymentMethod: data.PaymentMethod,
+		Reason:        data.Reason,
+		BasketId:      data.BasketID,
+	}, nil
+}
+
+func paymentFailedFromProto(message *eventspb.PaymentFailedData) PaymentFailedData {
+	return PaymentFailedData{
+		PaymentID:     message.PaymentId,
+		UserID:        uint(message.UserId),
+		OrderID:       message.OrderId,
+		AmountMinor:   message.AmountMinor,
+		Currency:      message.Currency,
+		PaymentMethod: message.PaymentMethod,
+		Reason:        message.Reason,
+		BasketID:      message.BasketId,
+	}
+}
+
+func paymentCancelledToProto(data PaymentCancelledData) (*eventspb.PaymentCancelledData, error) {
+	return &eventspb.PaymentCancelledData{
+		PaymentId:     data.PaymentID,
+		UserId:        uint32(data.UserID),
+		OrderId:       data.OrderID,
+		AmountMinor:   data.AmountMinor,
+		Currency:      data.Currency,
+		PaymentMethod: data.PaymentMethod,
+		Reason:        data.Reason,
+		BasketId:      data.BasketID,
+	}, nil
+}
+
+func paymentCancelledFromProto(message *eventspb.PaymentCancelledData) PaymentCancelledData {
+	return PaymentCancelledData{
+		PaymentID:     message.PaymentId,
+		UserID:        uint(message.UserId),
+		OrderID:       message.OrderId,
+		AmountMinor:   message.AmountMinor,
+		Currency:      message.Currency,
+		PaymentMethod: message.PaymentMethod,
+		Reason:        message.Reason,
+		BasketID:      message.BasketId,
+	}
+}
+
+func paymentRefundedToProto(data PaymentRefundedData) (*eventspb.PaymentRefundedData, error) {
+	return &eventspb.PaymentRefundedData{
+		PaymentId:          data.PaymentID,
+		RefundId:           data.RefundID,
+		UserId:             uint32(data.UserID),
+		OrderId:            data.OrderID,
+		AmountMinor:        data.AmountMinor,
+		TotalRefundedMinor: data.TotalRefundedMinor,
+		Currency:           data.Currency,
+		Reason:             data.Reason,
+		FullRefund:         data.FullRefund,
+		Items:              paymentItemsToProto(data.Items),
+		BasketId:           data.BasketID,
+	}, nil
+}
+
+func paymentRefundedFromProto(message *eventspb.PaymentRefundedData) PaymentRefundedData {
+	return PaymentRefundedData{
+		PaymentID:          message.PaymentId,
+		RefundID:           message.RefundId,
+		UserID:             uint(message.UserId),
+		OrderID:            message.OrderId,
+		AmountMinor:        message.AmountMinor,
+		TotalRefundedMinor: message.TotalRefundedMinor,
+		Currency:           message.Currency,
+		Reason:             message.Reason,
+		FullRefund:         message.FullRefund,
+		Items:              paymentItemsFromProto(message.Items),
+		BasketID:           message.BasketId,
+	}
+}
+
+func stockUpdatedToProto(data StockUpdatedData) (*eventspb.StockUpdatedData, error) {
+	return &eventspb.StockUpdatedData{
+		ProductId: uint32(data.ProductID),
+		VariantId: uintToUint32Ptr(data.VariantID),
+		Quantity:  int32(data.Quantity),
+		NewStock:  int32(data.NewStock),
+		Reason:    data.Reason,
+		OrderId:   data.OrderID,
+		PaymentId: data.PaymentID,
+	}, nil
+}
+
+func stockUpdatedFromProto(message *eventspb.StockUpdatedData) StockUpdatedData {
+	return StockUpdatedData{
+		ProductID: uint(message.ProductId),
+		VariantID: uint32ToUintPtr(message.VariantId),
+		Quantity:  int(message.Quantity),
+		NewStock:  int(message.NewStock),
+		Reason:    message.Reason,
+		OrderID:   message.OrderId,
+		PaymentID: message.PaymentId,
+	}
+}
+
+func basketClearedToProto(data BasketClearedData) (*eventspb.BasketClearedData, error) {
+	return &eventspb.BasketClearedData{
+		UserId:    uint32(data.UserID),
+		BasketId:  data.BasketID,
+		Items:     paymentItemsToProto(data.Items),
+		Reason:    data.Reason,
+		OrderId:   data.OrderID,
+		PaymentId: data.PaymentID,
+	}, nil
+}
+
+func basketClearedFromProto(message *eventspb.BasketClearedData) BasketClearedData {
+	return BasketClearedData{
+		UserID:    uint(message.UserId),
+		BasketID:  message.BasketId,
+		Items:     paymentItemsFromProto(message.Items),
+		Reason:    message.Reason,
+		OrderID:   message.OrderId,
+		PaymentID: message.PaymentId,
+	}
+}
+
+func orderCreatedToProto(data OrderCreatedData) (*eventspb.OrderCreatedData, error) {
+	return &eventspb.OrderCreatedData{
+		OrderId:     data.OrderID,
+		UserId:      uint32(data.UserID),
+		PaymentId:   data.PaymentID,
+		AmountMinor: data.AmountMinor,
+		Currency:    data.Currency,
+		Items:       paymentItemsToProto(data.Items),
+		ShippingInfo: &eventspb.Address{
+			Name:    data.ShippingInfo.Name,
+			Address: data.ShippingInfo.Address,
+			City:    data.ShippingInfo.City,
+			State:   data.ShippingInfo.State,
+			ZipCode: data.ShippingInfo.ZipCode,
+			Country: data.ShippingInfo.Country,
+			Phone:   data.ShippingInfo.Phone,
+		},
+		BillingInfo: &eventspb.Address{
+			Name:    data.BillingInfo.Name,
+			Address: data.BillingInfo.Address,
+			City:    data.BillingInfo.City,
+			State:   data.BillingInfo.State,
+			ZipCode: data.BillingInfo.ZipCode,
+			Country: data.BillingInfo.Country,
+		},
+	}, nil
+}
+
+func orderCreatedFromProto(message *eventspb.OrderCreatedData) OrderCreatedData {
+	shipping, billing := message.GetShippingInfo(), message.GetBillingInfo()
+	return OrderCreatedData{
+		OrderID:     message.OrderId,
+		UserID:      uint(message.UserId),
+		PaymentID:   message.PaymentId,
+		AmountMinor: message.AmountMinor,
+		Currency:    message.Currency,
+		Items:       paymentItemsFromProto(message.Items),
+		ShippingInfo: ShippingInfo{
+			Name:    shipping.GetName(),
+			Address: shipping.GetAddress(),
+			City:    shipping.GetCity(),
+			State:   shipping.GetState(),
+			ZipCode: shipping.GetZipCode(),
+			Country: shipping.GetCountry(),
+			Phone:   shipping.GetPhone(),
+		},
+		BillingInfo: BillingInfo{
+			Name:    billing.GetName(),
+			Address: billing.GetAddress(),
+			City:    billing.GetCity(),
+			State:   billing.GetState(),
+			ZipCode: billing.GetZipCode(),
+			Country: billing.GetCountry(),
+		},
+	}
+}
+
+func orderCancelledToProto(data OrderCancelledData) (*eventspb.OrderCancelledData, error) {
+	return &eventspb.OrderCancelledData{
+		OrderId: data.OrderID,
+		UserId:  uint32(data.UserID),
+		Reason:  data.Reason,
+	}, nil
+}
+
+func orderCancelledFromProto(message *eventspb.OrderCancelledData) OrderCancelledData {
+	return OrderCancelledData{
+		OrderID: message.OrderId,
+		UserID:  uint(message.UserId),
+		Reason:  message.Reason,
+	}
+}
+
+func orderPaymentRejectedToProto(data OrderPaymentRejectedData) (*eventspb.OrderPaymentRejectedData, error) {
+	return &eventspb.OrderPaymentRejectedData{
+		OrderId:   data.OrderID,
+		PaymentId: data.PaymentID,
+		Reason:    data.Reason,
+	}, nil
+}
+
+func orderPaymentRejectedFromProto(message *eventspb.OrderPaymentRejectedData) OrderPaymentRejectedData {
+	return OrderPaymentRejectedData{
+		OrderID:   message.OrderId,
+		PaymentID: message.PaymentId,
+		Reason:    message.Reason,
+	}
+}
+
+// uintToUint32Ptr converts an optional ID to its protobuf field
+func uintToUint32Ptr(value *uint) *uint32 {
+	if value == nil {
+		return nil
+	}
+	converted := uint32(*value)
+	return &converted
+}
+
+// uint32ToUintPtr converts an optional protobuf ID field to an ID
+func uint32ToUintPtr(value *uint32) *uint {
+	if value == nil {
+		return nil
+	}
+	converted := uint(*value)
+	return &converted
+}
@@ -11,46 +11,95 @@ import (
 
 // Config holds Kafka configuration
 type Config struct {
-	Brokers       []string
-	Topic         string
+	// Bus selects Kafka or the in-process memory bus
+	Bus     Bus
+	Brokers []string
+	// TopicRoutes overrides the topic of single event types, see DefaultTopicRoutes
+	TopicRoutes   TopicRoutes
 	GroupID       string
 	Offset        int64
 	RetryAttempts int
 	RetryDelay    time.Duration
-	Timeout       time.Duration
+	MaxRetryDelay time.Duration
+	// RetryTopicDelays lists the delays of the retry topics a failed message passes
+	// through, in order, before it ends in the dead-letter topic
+	RetryTopicDelays []time.Duration
+	DLQEnabled       bool
+	Timeout          time.Duration
+	// DedupRetention is how long processed event IDs are remembered for deduplication
+	DedupRetention time.Duration
+	// DedupClaimTimeout is how long a handler may hold an event before another delivery may handle it
+	DedupClaimTimeout time.Duration
+	// CloudEventsMode selects structured or binary CloudEvents messages when publishing
+	CloudEventsMode CloudEventsMode
+	// Codec is the default codec when publishing; TopicCodecs overrides it per topic.
+	// Consumers read both codecs, telling them apart by the content-type header.
+	Codec       Codec
+	TopicCodecs map[string]Codec
 }
 
 // LoadConfig loads Kafka configuration from environment variables
 func LoadConfig() *Config {
 	return &Config{
+		Bus:           Bus(getEnv("EVENT_BUS", string(BusKafka))),
 		Brokers:       getEnvAsSlice("KAFKA_BROKERS", []string{"localhost:9092"}),
-		Topic:         getEnv("KAFKA_TOPIC", "payment-events"),
+		TopicRoutes:   getEnvAsTopicRoutes("KAFKA_TOPIC_ROUTES"),
 		GroupID:       getEnv("KAFKA_GROUP_ID", "payment-service"),
 		Offset:        getEnvAsInt64("KAFKA_OFFSET", sarama.OffsetNewest),
 		RetryAttempts: getEnvAsInt("KAFKA_RETRY_ATTEMPTS", 3),
 		RetryDelay:    getEnvAsDuration("KAFKA_RETRY_DELAY", 5*time.Second),
-		Timeout:       getEnvAsDuration("KAFKA_TIMEOUT", 30*time.Second),
+		MaxRetryDelay: getEnvAsDuration("KAFKA_RETRY_MAX_DELAY", time.Minute),
+		RetryTopicDelays: getEnvAsDelays("KAFKA_RETRY_TOPICS", []time.Duration{
+			time.Minute,
+			10 * time.Minute,
+		}),
+		DLQEnabled: getEnvAsBool("KAFKA_DLQ_ENABLED", true),
+		Timeout:    getEnvAsDuration("KAFKA_TIMEOUT", 30*time.Second),
+
+		DedupRetention:    getEnvAsDuration("KAFKA_DEDUP_RETENTION", 7*24*time.Hour),
+		DedupClaimTimeout: getEnvAsDuration("KAFKA_DEDUP_CLAIM_TIMEOUT", 5*time.Minute),
+
+		CloudEventsMode: CloudEventsMode(getEnv("KAFKA_CLOUDEVENTS_MODE", string(CloudEventsStructured))),
+		Codec:           Codec(getEnv("KAFKA_CODEC", string(CodecJSON))),
+		TopicCodecs:     getEnvAsCodecs("KAFKA_TOPIC_CODECS"),
 	}
 }
 
+// LoadServiceConfig loads Kafka configuration for a service, consuming in a
+// consumer group named after the service unless KAFKA_GROUP_ID is set
+func LoadServiceConfig(service string) *Config {
+	config := LoadConfig()
+	config.GroupID = getEnv("KAFKA_GROUP_ID", service)
+	return config
+}
+
 // GetPublisherConfig returns publisher configuration
 func (c *Config) GetPublisherConfig() *PublisherConfig {
 	return &PublisherConfig{
-		Brokers: c.Brokers,
-		Topic:   c.Topic,
-		Timeout: c.Timeout,
+		Bus:             c.Bus,
+		Brokers:         c.Brokers,
+		TopicRoutes:     c.TopicRoutes,
+		Timeout:         c.Timeout,
+		CloudEventsMode: c.CloudEventsMode,
+		Codec:           c.Codec,
+		TopicCodecs:     c.TopicCodecs,
 	}
 }
 
 // GetConsumerConfig returns consumer configuration
 func (c *Config) GetConsumerConfig() *ConsumerConfig {
 	return &ConsumerConfig{
-		Brokers:       c.Brokers,
-		Topic:         c.Topic,
-		GroupID:       c.GroupID,
-		Offset:        c.Offset,
-		RetryAttempts: c.RetryAttempts,
-		RetryDelay:    c.RetryDelay,
+		Bus:              c.Bus,
+		Brokers:          c.Brokers,
+		TopicRoutes:      c.TopicRoutes,
+		GroupID:          c.GroupID,
+		Offset:           c.Offset,
+		RetryAttempts:    c.RetryAttempts,
+		RetryDelay:       c.RetryDelay,
+		MaxRetryDelay:    c.MaxRetryDelay,
+		RetryTopicDelays: c.RetryTopicDelays,
+		DLQEnabled:       c.DLQEnabled,
+		Timeout:          c.Timeout,
 	}
 }
 
@@ -90,6 +139,42 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	return defaultValue
 }
 
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsDelays(key string, defaultValue []time.Duration) []time.Duration {
+	if value, ok := os.LookupEnv(key); ok {
+		if delays, err := parseDelays(value); err == nil {
+			return delays
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsTopicRoutes(key string) TopicRoutes {
+	if value := os.Getenv(key); value != "" {
+		if routes, err := parseTopicRoutes(value); err == nil {
+			return routes
+		}
+	}
+	return nil
+}
+
+func getEnvAsCodecs(key string) map[string]Codec {
+	if value := os.Getenv(key); value != "" {
+		if codecs, err := parseCodecs(value); err == nil {
+			return codecs
+		}
+	}
+	return nil
+}
+
 func getEnvAsSlice(key string, defaultValue []string) []string {
 	if value := os.Getenv(key); value != "" {
 		return strings.Split(value, ",")
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/IBM/sarama"
@@ -14,21 +16,35 @@ import (
 // kafkaConsumer implements EventConsumer interface
 type kafkaConsumer struct {
 	consumer sarama.ConsumerGroup
+	// producer forwards failed messages to the retry and dead-letter topics
+	producer sarama.SyncProducer
 	config   *ConsumerConfig
-	handlers map[EventType]func([]byte) error
-	ctx      context.Context
-	cancel   context.CancelFunc
-	wg       sync.WaitGroup
+	stages   []retryStage
+	handlerRegistry
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	// connected is set while the consumer is a member of an active group session
+	connected atomic.Bool
 }
 
 // ConsumerConfig holds configuration for the Kafka consumer
 type ConsumerConfig struct {
-	Brokers       []string
-	Topic         string
-	GroupID       string
-	Offset        int64
+	Bus         Bus
+	Brokers     []string
+	TopicRoutes TopicRoutes
+	GroupID     string
+	Offset      int64
+	// RetryAttempts is the number of in-process attempts per message and retry stage
 	RetryAttempts int
+	// RetryDelay is the backoff before the first in-process retry, doubling up to MaxRetryDelay
 	RetryDelay    time.Duration
+	MaxRetryDelay time.Duration
+	// RetryTopicDelays lists the retry topics, by delay, a failed message passes through
+	RetryTopicDelays []time.Duration
+	// DLQEnabled sends messages that failed every retry stage to the dead-letter topic
+	DLQEnabled bool
+	Timeout    time.Duration
 }
 
 // NewKafkaConsumer creates a new Kafka consumer
@@ -47,91 +63,45 @@ func NewKafkaConsumer(config *ConsumerConfig) (EventConsumer, error) {
 		return nil, fmt.Errorf("failed to create Kafka consumer group: %w", err)
 	}
 
+	// Create producer for failed messages
+	var producer sarama.SyncProducer
+	if len(config.RetryTopicDelays) > 0 || config.DLQEnabled {
+		producerConfig := sarama.NewConfig()
+		producerConfig.Producer.RequiredAcks = sarama.WaitForAll
+		producerConfig.Producer.Retry.Max = 3
+		producerConfig.Producer.Return.Successes = true
+		if config.Timeout > 0 {
+			producerConfig.Producer.Timeout = config.Timeout
+		}
+
+		producer, err = sarama.NewSyncProducer(config.Brokers, producerConfig)
+		if err != nil {
+			consumer.Close()
+			return nil, fmt.Errorf("failed to create Kafka retry producer: %w", err)
+		}
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &kafkaConsumer{
 		consumer: consumer,
+		producer: producer,
 		config:   config,
-		handlers: make(map[EventType]func([]byte) error),
+		stages:   config.retryStages(),
 		ctx:      ctx,
 		cancel:   cancel,
-	}, nil
-}
-
-// ConsumePaymentCompleted registers a handler for payment completed events
-func (c *kafkaConsumer) ConsumePaymentCompleted(handler func(PaymentCompletedEvent) error) error {
-	c.handlers[EventTypePaymentCompleted] = func(data []byte) error {
-		var event PaymentCompletedEvent
-		if err := json.Unmarshal(data, &event); err != nil {
-			return fmt.Errorf("failed to unmarshal payment completed event: %w", err)
-		}
-		return handler(event)
-	}
-	return nil
-}
-
-// ConsumePaymentFailed registers a handler for payment failed events
-func (c *kafkaConsumer) ConsumePaymentFailed(handler func(PaymentFailedEvent) error) error {
-	c.handlers[EventTypePaymentFailed] = func(data []byte) error {
-		var event PaymentFailedEvent
-		if err := json.Unmarshal(data, &event); err != nil {
-			return fmt.Errorf("failed to unmarshal payment failed event: %w", err)
-		}
-		return handler(event)
-	}
-	return nil
-}
-
-// ConsumePaymentCancelled registers a handler for payment cancelled events
-func (c *kafkaConsumer) ConsumePaymentCancelled(handler func(PaymentCancelledEvent) error) error {
-	c.handlers[EventTypePaymentCancelled] = func(data []byte) error {
-		var event PaymentCancelledEvent
-		if err := json.Unmarshal(data, &event); err != nil {
-			return fmt.Errorf("failed to unmarshal payment cancelled event: %w", err)
-		}
-		return handler(event)
-	}
-	return nil
-}
-
-// ConsumeStockUpdated registers a handler for stock updated events
-func (c *kafkaConsumer) ConsumeStockUpdated(handler func(StockUpdatedEvent) error) error {
-	c.handlers[EventTypeStockUpdated] = func(data []byte) error {
-		var event StockUpdatedEvent
-		if err := json.Unmarshal(data, &event); err != nil {
-			return fmt.Errorf("failed to unmarshal stock updated event: %w", err)
-		}
-		return handler(event)
-	}
-	return nil
-}
-
-// ConsumeBasketCleared registers a handler for basket cleared events
-func (c *kafkaConsumer) ConsumeBasketCleared(handler func(BasketClearedEvent) error) error {
-	c.handlers[EventTypeBasketCleared] = func(data []byte) error {
-		var event BasketClearedEvent
-		if err := json.Unmarshal(data, &event); err != nil {
-			return fmt.Errorf("failed to unmarshal basket cleared event: %w", err)
-		}
-		return handler(event)
-	}
-	return nil
-}
 
-// ConsumeOrderCreated registers a handler for order created events
-func (c *kafkaConsumer) ConsumeOrderCreated(handler func(OrderCreatedEvent) error) error {
-	c.handlers[EventTypeOrderCreated] = func(data []byte) error {
-		var event OrderCreatedEvent
-		if err := json.Unmarshal(data, &event); err != nil {
-			return fmt.Errorf("failed to unmarshal order created event: %w", err)
-		}
-		return handler(event)
-	}
-	return nil
+		handlerRegistry: newHandlerRegistry(),
+	}, nil
 }
 
 // Start starts the consumer
 func (c *kafkaConsumer) Start() error {
+	if err := c.wrapHandlers(); err != nil {
+		return err
+	}
+	topics := c.topics(c.config.TopicRoutes, c.stages)
+
 	c.wg.Add(1)
 	go func() {
 		defer c.wg.Done()
@@ -142,7 +112,8 @@ func (c *kafkaConsumer) Start() error {
 				return
 			default:
 				// Start consuming
-				if err := c.consumer.Consume(c.ctx, []string{c.config.Topic}, c); err != nil {
+				if err := c.consumer.Consume(c.ctx, topics, c); err != nil {
+					c.connected.Store(false)
 					log.Printf("Error from consumer: %v", err)
 					time.Sleep(c.config.RetryDelay)
 				}
@@ -164,7 +135,7 @@ func (c *kafkaConsumer) Start() error {
 		}
 	}()
 
-	log.Printf("Kafka consumer started for topic: %s, group: %s", c.config.Topic, c.config.GroupID)
+	log.Printf("Kafka consumer started for topics: %v, group: %s", topics, c.config.GroupID)
 	return nil
 }
 
@@ -172,23 +143,47 @@ func (c *kafkaConsumer) Start() error {
 func (c *kafkaConsumer) Stop() error {
 	c.cancel()
 	c.wg.Wait()
+	c.connected.Store(false)
+	if c.producer != nil {
+		if err := c.producer.Close(); err != nil {
+			log.Printf("Failed to close retry producer: %v", err)
+		}
+	}
 	return c.consumer.Close()
 }
 
+// Connected reports whether the consumer has joined its consumer group
+func (c *kafkaConsumer) Connected() bool {
+	return c.connected.Load()
+}
+
 // Setup is run at the beginning of a new session, before ConsumeClaim
 func (c *kafkaConsumer) Setup(sarama.ConsumerGroupSession) error {
 	log.Println("Consumer group session setup")
+	metrics.Rebalances.WithLabelValues(c.config.GroupID).Inc()
+	c.connected.Store(true)
 	return nil
 }
 
 // Cleanup is run at the end of a session, once all ConsumeClaim goroutines have exited
 func (c *kafkaConsumer) Cleanup(sarama.ConsumerGroupSession) error {
 	log.Println("Consumer group session cleanup")
+	c.connected.Store(false)
 	return nil
 }
 
 // ConsumeClaim must start a consumer loop of ConsumerGroupClaim's Messages()
 func (c *kafkaConsumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	ctx := session.Context()
+	stage := stageOf(c.stages, claim.Topic())
+
+	// Keep the lag current while a handler is slow or the partition is idle
+	var next atomic.Int64
+	next.Store(-1)
+	done := make(chan struct{})
+	defer close(done)
+	go c.trackLag(claim, &next, done)
+
 	for {
 		select {
 		case message := <-claim.Messages():
@@ -196,20 +191,26 @@ func (c *kafkaConsumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim
 				return nil
 			}
 
-			// Get event type from headers
-			eventType := EventType("")
-			for _, header := range message.Headers {
-				if string(header.Key) == "event-type" {
-					eventType = EventType(header.Value)
-					break
-				}
+			// Messages in a retry topic wait until their delay has passed
+			if stage > 0 && !waitForRetry(ctx, message, c.stages[stage].delay) {
+				return nil
 			}
 
+			// Get event type from headers
+			eventType := messageEventType(message.Headers, message.Value)
+
 			// Process message
 			if handler, exists := c.handlers[eventType]; exists {
-				if err := c.processMessage(handler, message.Value); err != nil {
+				if err := handleMessage(ctx, c.config, handler, eventType, message); err != nil {
+					if ctx.Err() != nil {
+						// Shutting down or rebalancing; the message is consumed again later
+						return nil
+					}
 					log.Printf("Error processing message: %v", err)
-					// In production, you might want to implement retry logic or dead letter queue
+					if err := c.forwardFailed(message, stage, err); err != nil {
+						// Leave the message unmarked so it is consumed again
+						return err
+					}
 				}
 			} else {
 				log.Printf("No handler found for event type: %s", eventType)
@@ -217,47 +218,173 @@ func (c *kafkaConsumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim
 
 			// Mark message as processed
 			session.MarkMessage(message, "")
+			next.Store(message.Offset + 1)
+			setLag(c.config.GroupID, message.Topic, message.Partition, claim.HighWaterMarkOffset(), message.Offset+1)
 
-		case <-session.Context().Done():
+		case <-ctx.Done():
 			return nil
 		}
 	}
 }
 
-// processMessage processes a single message with retry logic
-func (c *kafkaConsumer) processMessage(handler func([]byte) error, data []byte) error {
-	var lastErr error
-	for attempt := 0; attempt < c.config.RetryAttempts; attempt++ {
-		if err := handler(data); err != nil {
-			lastErr = err
-			if attempt < c.config.RetryAttempts-1 {
-				log.Printf("Handler failed (attempt %d/%d): %v, retrying in %v",
-					attempt+1, c.config.RetryAttempts, err, c.config.RetryDelay)
-				time.Sleep(c.config.RetryDelay)
+// trackLag updates the lag of a claimed partition until done is closed, then removes it
+// so the member the partition moves to reports it
+func (c *kafkaConsumer) trackLag(claim sarama.ConsumerGroupClaim, next *atomic.Int64, done <-chan struct{}) {
+	ticker := time.NewTicker(lagInterval)
+	defer ticker.Stop()
+	defer metrics.ConsumerLag.DeleteLabelValues(c.config.GroupID, claim.Topic(), strconv.Itoa(int(claim.Partition())))
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			offset := next.Load()
+			if offset < 0 {
+				offset = claim.InitialOffset()
+			}
+			if offset >= 0 {
+				setLag(c.config.GroupID, claim.Topic(), claim.Partition(), claim.HighWaterMarkOffset(), offset)
 			}
-		} else {
+		}
+	}
+}
+
+// handleMessage decodes a message of either codec and CloudEvents mode, upcasts it to the latest
+// schema version and processes it
+func handleMessage(ctx context.Context, config *ConsumerConfig, handler Handler, eventType EventType, message *sarama.ConsumerMessage) (err error) {
+	data, err := decodeMessage(message.Headers, message.Value)
+	var event BaseEvent
+	if err == nil {
+		json.Unmarshal(data, &event)
+	}
+
+	// Trace the handler, and everything it publishes, as part of the producer's trace
+	span, ctx := startConsumerSpan(ctx, message, eventType, event.TraceParent)
+	if event.ID != "" {
+		span.SetTag("event.id", event.ID)
+	}
+	defer func() { finishSpan(span, err) }()
+
+	if err != nil {
+		return err
+	}
+	if data, err = Upcast(data); err != nil {
+		return err
+	}
+	metrics.MessagesConsumed.WithLabelValues(config.GroupID, message.Topic, string(eventType)).Inc()
+	return processMessage(ctx, config, handler, data, newHandlerMetrics(config.GroupID, message.Topic, eventType))
+}
+
+// processMessage processes a single message, retrying with exponential backoff and jitter
+func processMessage(ctx context.Context, config *ConsumerConfig, handler Handler, data []byte, handlerMetrics handlerMetrics) error {
+	attempts := max(config.RetryAttempts, 1)
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		start := time.Now()
+		lastErr = handler(ctx, data)
+		handlerMetrics.observe(start, lastErr)
+		if lastErr == nil {
 			return nil
 		}
+		if attempt == attempts {
+			break
+		}
+
+		delay := config.backoff(attempt)
+		log.Printf("Handler failed (attempt %d/%d): %v, retrying in %v", attempt, attempts, lastErr, delay)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+	return fmt.Errorf("handler failed after %d attempts: %w", attempts, lastErr)
+}
+
+// stageOf returns the retry stage of a topic, 0 for the event topics
+func stageOf(stages []retryStage, topic string) int {
+	for i := 1; i < len(stages); i++ {
+		if stages[i].topic == topic {
+			return i
+		}
+	}
+	return 0
+}
+
+// waitForRetry blocks until a retry message is due and reports false when ctx ends first
+func waitForRetry(ctx context.Context, message *sarama.ConsumerMessage, delay time.Duration) bool {
+	retryAt := message.Timestamp.Add(delay)
+	if value := headerValue(message.Headers, HeaderRetryAt); value != "" {
+		if parsed, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			retryAt = parsed
+		}
+	}
+
+	wait := time.Until(retryAt)
+	if wait <= 0 {
+		return true
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// forwardFailed sends a message whose handler kept failing to the next retry topic,
+// or to the dead-letter topic once every retry stage is used up
+func (c *kafkaConsumer) forwardFailed(message *sarama.ConsumerMessage, stage int, handlerErr error) error {
+	topic, retryAt := failureTopic(c.config, c.stages, stage)
+	if topic == "" {
+		log.Printf("Dropping message %s/%d/%d after all retries", message.Topic, message.Partition, message.Offset)
+		return nil
+	}
+
+	failed := failedMessage(message, topic, max(c.config.RetryAttempts, 1), handlerErr, retryAt)
+	if _, _, err := c.producer.SendMessage(failed); err != nil {
+		return fmt.Errorf("failed to forward message %s/%d/%d to %s: %w",
+			message.Topic, message.Partition, message.Offset, topic, err)
+	}
+
+	log.Printf("Forwarded failed message %s/%d/%d to %s", message.Topic, message.Partition, message.Offset, topic)
+	return nil
+}
+
+// failureTopic returns the topic a message that failed in a retry stage moves to, and when
+// it is due there; the topic is empty when the message is dropped
+func failureTopic(config *ConsumerConfig, stages []retryStage, stage int) (string, time.Time) {
+	switch next := stage + 1; {
+	case next < len(stages):
+		return stages[next].topic, time.Now().Add(stages[next].delay)
+	case config.DLQEnabled:
+		return DLQTopicName(config.GroupID), time.Time{}
+	default:
+		return "", time.Time{}
 	}
-	return fmt.Errorf("handler failed after %d attempts: %w", c.config.RetryAttempts, lastErr)
 }
 
 // ConsumerGroup represents a consumer group
 type ConsumerGroup struct {
 	consumer EventConsumer
-	handlers map[EventType]func([]byte) error
+	handlers map[EventType]Handler
 }
 
 // NewConsumerGroup creates a new consumer group
 func NewConsumerGroup(consumer EventConsumer) *ConsumerGroup {
 	return &ConsumerGroup{
 		consumer: consumer,
-		handlers: make(map[EventType]func([]byte) error),
+		handlers: make(map[EventType]Handler),
 	}
 }
 
 // RegisterHandler registers an event handler
-func (cg *ConsumerGroup) RegisterHandler(eventType EventType, handler func([]byte) error) {
+func (cg *ConsumerGroup) RegisterHandler(eventType EventType, handler Handler) {
 	cg.handlers[eventType] = handler
 }
 
@@ -2,20 +2,27 @@ package consumers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 
+	"github.com/ddd-micro/internal/basket/domain"
+	"github.com/ddd-micro/internal/basket/infrastructure/persistence"
 	"github.com/ddd-micro/kafka"
 )
 
 // BasketConsumer handles basket-related Kafka events
 type BasketConsumer struct {
+	consumer       kafka.EventConsumer
+	repo           domain.BasketRepository
 	eventPublisher kafka.EventPublisher
 }
 
 // NewBasketConsumer creates a new basket consumer
-func NewBasketConsumer(eventPublisher kafka.EventPublisher) *BasketConsumer {
+func NewBasketConsumer(consumer kafka.EventConsumer, repo domain.BasketRepository, eventPublisher kafka.EventPublisher) *BasketConsumer {
 	return &BasketConsumer{
+		consumer:       consumer,
+		repo:           repo,
 		eventPublisher: eventPublisher,
 	}
 }
@@ -26,20 +33,27 @@ func (c *BasketConsumer) HandlePaymentCompleted(ctx context.Context, event kafka
 
 	// Clear basket if it was a basket-based payment
 	if event.Data.BasketID != nil {
+		// A basket that already expired has nothing left to clear
+		err := c.repo.ClearItems(ctx, *event.Data.BasketID)
+		if err != nil && !errors.Is(err, persistence.ErrBasketNotFound) {
+			return fmt.Errorf("failed to clear basket %s: %w", *event.Data.BasketID, err)
+		}
+
 		// Convert payment items to basket items for the cleared event
 		var basketItems []kafka.PaymentItem
 		for _, item := range event.Data.Items {
 			basketItems = append(basketItems, kafka.PaymentItem{
-				ProductID:  item.ProductID,
-				Quantity:   item.Quantity,
-				UnitPrice:  item.UnitPrice,
-				TotalPrice: item.TotalPrice,
+				ProductID:       item.ProductID,
+				VariantID:       item.VariantID,
+				Quantity:        item.Quantity,
+				UnitPriceMinor:  item.UnitPriceMinor,
+				TotalPriceMinor: item.TotalPriceMinor,
 			})
 		}
 
 		// Publish basket cleared event
 		basketClearedEvent := kafka.BasketClearedEvent{
-			BaseEvent: kafka.NewBaseEvent(kafka.EventTypeBasketCleared, "basket-service"),
+			BaseEvent: kafka.NewBaseEvent(ctx, kafka.EventTypeBasketCleared, "basket-service", *event.Data.BasketID),
 			Data: kafka.BasketClearedData{
 				UserID:    event.Data.UserID,
 				BasketID:  *event.Data.BasketID,
@@ -60,49 +74,38 @@ func (c *BasketConsumer) HandlePaymentCompleted(ctx context.Context, event kafka
 	return nil
 }
 
-// HandlePaymentFailed handles payment failed events
-func (c *BasketConsumer) HandlePaymentFailed(ctx context.Context, event kafka.PaymentFailedEvent) error {
-	log.Printf("Processing payment failed event: %s", event.Data.PaymentID)
+// Start subscribes to payment events and starts consuming. A basket holds no stock of
+// its own, so failed and cancelled payments are left to the product service, which
+// releases the reservations they held.
+func (c *BasketConsumer) Start(ctx context.Context) error {
+	log.Println("Starting basket consumer...")
 
-	// Release basket reservation if it was a basket-based payment
-	if event.Data.BasketID != nil {
-		log.Printf("Payment failed for basket %s, reservation should be released", *event.Data.BasketID)
-		// Here you would implement basket reservation release logic
-		// This might involve calling the basket service API or publishing another event
+	handlers := []error{
+		c.consumer.ConsumePaymentCompleted(func(ctx context.Context, event kafka.PaymentCompletedEvent) error {
+			return c.HandlePaymentCompleted(ctx, event)
+		}),
 	}
-
-	return nil
-}
-
-// HandlePaymentCancelled handles payment cancelled events
-func (c *BasketConsumer) HandlePaymentCancelled(ctx context.Context, event kafka.PaymentCancelledEvent) error {
-	log.Printf("Processing payment cancelled event: %s", event.Data.PaymentID)
-
-	// Release basket reservation if it was a basket-based payment
-	if event.Data.BasketID != nil {
-		log.Printf("Payment cancelled for basket %s, reservation should be released", *event.Data.BasketID)
-		// Here you would implement basket reservation release logic
+	for _, err := range handlers {
+		if err != nil {
+			return fmt.Errorf("failed to register basket consumer handler: %w", err)
+		}
 	}
 
-	return nil
-}
-
-// Start starts the basket consumer
-func (c *BasketConsumer) Start(ctx context.Context) error {
-	log.Println("Starting basket consumer...")
-
-	// In a real implementation, you would:
-	// 1. Create a Kafka consumer
-	// 2. Subscribe to payment events
-	// 3. Handle events in a loop
+	if err := c.consumer.Start(); err != nil {
+		return fmt.Errorf("failed to start basket consumer: %w", err)
+	}
 
-	// For now, this is a placeholder
 	log.Println("Basket consumer started successfully")
 	return nil
 }
 
-// Stop stops the basket consumer
+// Stop stops the basket consumer, waiting for the message in progress
 func (c *BasketConsumer) Stop() error {
 	log.Println("Stopping basket consumer...")
-	return nil
+	return c.consumer.Stop()
+}
+
+// Connected reports whether the consumer has joined its consumer group
+func (c *BasketConsumer) Connected() bool {
+	return c.consumer.Connected()
 }
@@ -0,0 +1,127 @@
+package consumers
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/ddd-micro/internal/payment/application"
+	"github.com/ddd-micro/kafka"
+)
+
+// CheckoutConsumer drives checkout sagas with payment and basket events, and settles
+// the payments of orders with order events
+type CheckoutConsumer struct {
+	consumer kafka.EventConsumer
+	checkout *application.CheckoutOrchestrator
+	payments *application.PaymentServiceCQRS
+}
+
+// NewCheckoutConsumer creates a new checkout consumer
+func NewCheckoutConsumer(consumer kafka.EventConsumer, checkout *application.CheckoutOrchestrator, payments *application.PaymentServiceCQRS) *CheckoutConsumer {
+	return &CheckoutConsumer{
+		consumer: consumer,
+		checkout: checkout,
+		payments: payments,
+	}
+}
+
+// HandlePaymentCompleted moves the checkout of the payment on to clearing the basket
+func (c *CheckoutConsumer) HandlePaymentCompleted(ctx context.Context, event kafka.PaymentCompletedEvent) error {
+	log.Printf("Processing payment completed event for checkout: %s", event.Data.PaymentID)
+	return c.checkout.PaymentCompleted(ctx, event.Data.PaymentID)
+}
+
+// HandlePaymentFailed compensates the checkout of the payment
+func (c *CheckoutConsumer) HandlePaymentFailed(ctx context.Context, event kafka.PaymentFailedEvent) error {
+	log.Printf("Processing payment failed event for checkout: %s", event.Data.PaymentID)
+	return c.checkout.PaymentAborted(ctx, event.Data.PaymentID, "payment failed: "+event.Data.Reason)
+}
+
+// HandlePaymentCancelled compensates the checkout of the payment
+func (c *CheckoutConsumer) HandlePaymentCancelled(ctx context.Context, event kafka.PaymentCancelledEvent) error {
+	log.Printf("Processing payment cancelled event for checkout: %s", event.Data.PaymentID)
+	return c.checkout.PaymentAborted(ctx, event.Data.PaymentID, "payment cancelled: "+event.Data.Reason)
+}
+
+// HandleBasketCleared completes the checkout whose basket was cleared
+func (c *CheckoutConsumer) HandleBasketCleared(ctx context.Context, event kafka.BasketClearedEvent) error {
+	// Baskets cleared by their owner or by expiry belong to no payment
+	if event.Data.PaymentID == nil {
+		return nil
+	}
+
+	log.Printf("Processing basket cleared event for checkout: %s", *event.Data.PaymentID)
+	return c.checkout.BasketCleared(ctx, *event.Data.PaymentID)
+}
+
+// HandleOrderCancelled cancels the pending payments of the cancelled order
+func (c *CheckoutConsumer) HandleOrderCancelled(ctx context.Context, event kafka.OrderCancelledEvent) error {
+	log.Printf("Processing order cancelled event: %s", event.Data.OrderID)
+
+	if err := c.payments.OrderCancelled(ctx, event.Data.OrderID); err != nil {
+		return fmt.Errorf("failed to cancel payments of order %s: %w", event.Data.OrderID, err)
+	}
+
+	return nil
+}
+
+// HandleOrderPaymentRejected refunds the payment the order rejected
+func (c *CheckoutConsumer) HandleOrderPaymentRejected(ctx context.Context, event kafka.OrderPaymentRejectedEvent) error {
+	log.Printf("Processing order payment rejected event: %s", event.Data.PaymentID)
+
+	if err := c.payments.OrderPaymentRejected(ctx, event.Data.OrderID, event.Data.PaymentID, event.Data.Reason); err != nil {
+		return fmt.Errorf("failed to refund payment %s of order %s: %w", event.Data.PaymentID, event.Data.OrderID, err)
+	}
+
+	return nil
+}
+
+// Start subscribes to payment, basket and order events and starts consuming
+func (c *CheckoutConsumer) Start(ctx context.Context) error {
+	log.Println("Starting checkout consumer...")
+
+	handlers := []error{
+		c.consumer.ConsumePaymentCompleted(func(ctx context.Context, event kafka.PaymentCompletedEvent) error {
+			return c.HandlePaymentCompleted(ctx, event)
+		}),
+		c.consumer.ConsumePaymentFailed(func(ctx context.Context, event kafka.PaymentFailedEvent) error {
+			return c.HandlePaymentFailed(ctx, event)
+		}),
+		c.consumer.ConsumePaymentCancelled(func(ctx context.Context, event kafka.PaymentCancelledEvent) error {
+			return c.HandlePaymentCancelled(ctx, event)
+		}),
+		c.consumer.ConsumeBasketCleared(func(ctx context.Context, event kafka.BasketClearedEvent) error {
+			return c.HandleBasketCleared(ctx, event)
+		}),
+		c.consumer.ConsumeOrderCancelled(func(ctx context.Context, event kafka.OrderCancelledEvent) error {
+			return c.HandleOrderCancelled(ctx, event)
+		}),
+		c.consumer.ConsumeOrderPaymentRejected(func(ctx context.Context, event kafka.OrderPaymentRejectedEvent) error {
+			return c.HandleOrderPaymentRejected(ctx, event)
+		}),
+	}
+	for _, err := range handlers {
+		if err != nil {
+			return fmt.Errorf("failed to register checkout consumer handler: %w", err)
+		}
+	}
+
+	if err := c.consumer.Start(); err != nil {
+		return fmt.Errorf("failed to start checkout consumer: %w", err)
+	}
+
+	log.Println("Checkout consumer started successfully")
+	return nil
+}
+
+// Stop stops the checkout consumer, waiting for the message in progress
+func (c *CheckoutConsumer) Stop() error {
+	log.Println("Stopping checkout consumer...")
+	return c.consumer.Stop()
+}
+
+// Connected reports whether the consumer has joined its consumer group
+func (c *CheckoutConsumer) Connected() bool {
+	return c.consumer.Connected()
+}
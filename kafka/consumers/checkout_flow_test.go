@@ -0,0 +1,647 @@
+package consumers_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	basketpb "github.com/ddd-micro/api/proto/basket"
+	productpb "github.com/ddd-micro/api/proto/product"
+	basketdomain "github.com/ddd-micro/internal/basket/domain"
+	orderapp "github.com/ddd-micro/internal/order/application"
+	ordercommand "github.com/ddd-micro/internal/order/application/command"
+	orderdto "github.com/ddd-micro/internal/order/application/dto"
+	orderdomain "github.com/ddd-micro/internal/order/domain"
+	orderkafka "github.com/ddd-micro/internal/order/infrastructure/kafka"
+	orderpersistence "github.com/ddd-micro/internal/order/infrastructure/persistence"
+	paymentapp "github.com/ddd-micro/internal/payment/application"
+	"github.com/ddd-micro/internal/payment/application/command"
+	"github.com/ddd-micro/internal/payment/application/dto"
+	paymentdomain "github.com/ddd-micro/internal/payment/domain"
+	"github.com/ddd-micro/internal/payment/infrastructure/client"
+	paymentconfig "github.com/ddd-micro/internal/payment/infrastructure/config"
+	paymentkafka "github.com/ddd-micro/internal/payment/infrastructure/kafka"
+	paymentpersistence "github.com/ddd-micro/internal/payment/infrastructure/persistence"
+	productapp "github.com/ddd-micro/internal/product/application"
+	productdomain "github.com/ddd-micro/internal/product/domain"
+	productconfig "github.com/ddd-micro/internal/product/infrastructure/config"
+	productkafka "github.com/ddd-micro/internal/product/infrastructure/kafka"
+	productpersistence "github.com/ddd-micro/internal/product/infrastructure/persistence"
+	productgrpc "github.com/ddd-micro/internal/product/interfaces/grpc"
+	"github.com/ddd-micro/kafka"
+	"github.com/ddd-micro/kafka/consumers"
+	"github.com/ddd-micro/pkg/gormtx"
+	"github.com/ddd-micro/pkg/money"
+	"github.com/ddd-micro/pkg/outbox"
+	gosqlite "github.com/glebarez/go-sqlite"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const (
+	checkoutUserID   = 7
+	checkoutBasketID = "basket-1"
+)
+
+// relayMetrics is shared by the tests, as the relay metrics register globally
+var relayMetrics = outbox.NewMetrics("checkout_flow_test")
+
+func init() {
+	// The outbox relay takes a postgres advisory lock; the tests run one relay on one
+	// connection, so it always gets the lock
+	gosqlite.MustRegisterScalarFunction("pg_try_advisory_xact_lock", 1, func(ctx *gosqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		return true, nil
+	})
+}
+
+// consumerGroups are the consumer groups of the services of a checkout
+var consumerGroups = []string{"product-service", "basket-service", "payment-service", "order-service"}
+
+// checkoutFlow runs the payment, product, basket and order services of a checkout on one
+// database and one memory bus, each consuming with its own consumer group
+type checkoutFlow struct {
+	db          *gorm.DB
+	bus         *kafka.MemoryBus
+	relay       *outbox.Relay
+	checkout    *paymentapp.CheckoutOrchestrator
+	payments    *paymentapp.PaymentServiceCQRS
+	paymentRepo paymentdomain.PaymentRepository
+	orders      *orderapp.OrderServiceCQRS
+	orderRepo   orderdomain.OrderRepository
+	products    productdomain.ProductRepository
+	sagas       paymentdomain.CheckoutSagaRepository
+	gateway     *fakeGateway
+	baskets     *fakeBasketRepository
+}
+
+func newCheckoutFlow(t *testing.T) *checkoutFlow {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database: %v", err)
+	}
+	// Every connection to :memory: opens its own database
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(
+		&productdomain.Product{}, &productdomain.ProductVariant{}, &productdomain.Warehouse{},
+		&productdomain.StockLevel{}, &productdomain.StockMovement{},
+		&productdomain.StockReservation{}, &productdomain.StockReservationItem{},
+		&paymentdomain.Payment{}, &paymentdomain.PaymentStatusHistory{}, &paymentdomain.Refund{}, &paymentdomain.CheckoutSaga{},
+		&orderdomain.Order{}, &orderdomain.OrderItem{}, &orderdomain.OrderStatusHistory{},
+	); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	if err := outbox.Migrate(db); err != nil {
+		t.Fatalf("failed to migrate outbox: %v", err)
+	}
+
+	bus := kafka.NewMemoryBus(3)
+	t.Cleanup(bus.Close)
+	transactor := gormtx.NewTransactor(db)
+	store := outbox.NewStore(db)
+	flow := &checkoutFlow{
+		db:      db,
+		bus:     bus,
+		relay:   outbox.NewRelay(db, kafka.NewMemoryPublisher(bus, &kafka.PublisherConfig{}), outbox.RelayConfig{BatchSize: 100}, relayMetrics),
+		gateway: &fakeGateway{status: paymentdomain.PaymentStatusCompleted},
+		baskets: &fakeBasketRepository{},
+	}
+
+	// Product service
+	productCfg := &productconfig.Config{
+		Reservation: productconfig.ReservationConfig{TTL: 15 * time.Minute, MaxTTL: 2 * time.Hour},
+		Inventory:   productconfig.InventoryConfig{AllocationStrategy: productdomain.AllocationPriority},
+	}
+	flow.products = productpersistence.NewProductRepository(db)
+	productEvents := productkafka.NewProductEventPublisher(store)
+	reservations := productapp.NewReservationService(flow.products, productpersistence.NewStockReservationRepository(db), productEvents, transactor, productCfg)
+	productService := productapp.NewProductServiceCQRS(flow.products, productpersistence.NewCategoryRepository(db), productEvents, transactor, productCfg)
+	productServer := productgrpc.NewProductServer(productService, reservations, nil)
+	startConsumer(t, consumers.NewProductConsumer(newMemoryConsumer(t, bus, "product-service"), flow.products, transactor, productEvents, reservations))
+
+	// Basket service
+	startConsumer(t, consumers.NewBasketConsumer(newMemoryConsumer(t, bus, "basket-service"), flow.baskets, kafka.NewMemoryPublisher(bus, &kafka.PublisherConfig{})))
+
+	// Payment service
+	paymentCfg := &paymentconfig.Config{Checkout: paymentconfig.CheckoutConfig{
+		StepTimeout:    time.Minute,
+		PaymentTimeout: 30 * time.Minute,
+		BasketTimeout:  time.Minute,
+		Lease:          time.Minute,
+		RetryBackoff:   time.Second,
+		MaxBackoff:     time.Minute,
+		RecoveryBatch:  10,
+	}}
+	paymentRepo := paymentpersistence.NewPaymentRepository(db)
+	refundRepo := paymentpersistence.NewRefundRepository(db)
+	flow.paymentRepo = paymentRepo
+	flow.sagas = paymentpersistence.NewCheckoutSagaRepository(db)
+	paymentEvents := paymentkafka.NewPaymentEventPublisher(store)
+	productClient := &serverProductClient{server: productServer}
+	basketClient := &fakeBasketClient{baskets: flow.baskets}
+	createPayment := command.NewCreatePaymentCommandHandler(paymentRepo, flow.gateway)
+	cancelPayment := command.NewCancelPaymentCommandHandler(paymentRepo, flow.gateway)
+	flow.checkout = paymentapp.NewCheckoutOrchestrator(flow.sagas, paymentRepo, createPayment, cancelPayment,
+		productClient, basketClient, paymentEvents, transactor, paymentCfg)
+	flow.payments = paymentapp.NewPaymentServiceCQRS(createPayment, command.NewProcessPaymentCommandHandler(paymentRepo, flow.gateway), cancelPayment,
+		nil, nil, nil, nil,
+		command.NewCreateRefundCommandHandler(paymentRepo, refundRepo), command.NewProcessRefundCommandHandler(paymentRepo, refundRepo, flow.gateway),
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		paymentRepo, nil, refundRepo, flow.sagas, flow.checkout, nil, productClient, basketClient, paymentEvents, transactor)
+	startConsumer(t, consumers.NewCheckoutConsumer(newMemoryConsumer(t, bus, "payment-service"), flow.checkout, flow.payments))
+
+	// Order service
+	flow.orderRepo = orderpersistence.NewOrderRepository(db)
+	flow.orders = orderapp.NewOrderServiceCQRS(nil,
+		ordercommand.NewCancelOrderCommandHandler(flow.orderRepo),
+		ordercommand.NewMarkOrderPaidCommandHandler(flow.orderRepo),
+		ordercommand.NewMarkPaymentFailedCommandHandler(flow.orderRepo),
+		ordercommand.NewApplyRefundCommandHandler(flow.orderRepo),
+		nil, nil, nil, nil, nil, orderkafka.NewOrderEventPublisher(store), transactor)
+	startConsumer(t, consumers.NewOrderConsumer(newMemoryConsumer(t, bus, "order-service"), flow.orders))
+
+	return flow
+}
+
+// newMemoryConsumer creates a consumer of the group on the bus that retries a failed
+// message a few times, as events of a saga may arrive before it is ready for them
+func newMemoryConsumer(t *testing.T, bus *kafka.MemoryBus, group string) kafka.EventConsumer {
+	t.Helper()
+	return kafka.NewMemoryConsumer(bus, &kafka.ConsumerConfig{
+		GroupID:       group,
+		Offset:        sarama.OffsetOldest,
+		RetryAttempts: 5,
+		RetryDelay:    10 * time.Millisecond,
+		DLQEnabled:    true,
+	})
+}
+
+// startConsumer starts a service consumer and stops it when the test ends
+func startConsumer(t *testing.T, consumer interface {
+	Start(ctx context.Context) error
+	Stop() error
+}) {
+	t.Helper()
+	if err := consumer.Start(context.Background()); err != nil {
+		t.Fatalf("failed to start consumer: %v", err)
+	}
+	t.Cleanup(func() { consumer.Stop() })
+}
+
+// createProduct creates an active product priced in USD with stock in one warehouse
+func (f *checkoutFlow) createProduct(t *testing.T, sku string, priceMinor int64, stock int) *productdomain.Product {
+	t.Helper()
+	ctx := context.Background()
+
+	var warehouses int64
+	if err := f.db.Model(&productdomain.Warehouse{}).Count(&warehouses).Error; err != nil {
+		t.Fatalf("failed to count warehouses: %v", err)
+	}
+	if warehouses == 0 {
+		warehouse := &productdomain.Warehouse{Code: productdomain.DefaultWarehouseCode, Name: "Default warehouse", IsActive: true}
+		if err := f.db.Create(warehouse).Error; err != nil {
+			t.Fatalf("failed to create warehouse: %v", err)
+		}
+	}
+
+	product := &productdomain.Product{Name: sku, SKU: sku, PriceMinor: priceMinor, Currency: "USD", IsActive: true}
+	if err := f.products.LoadStockLevels(ctx, product); err != nil {
+		t.Fatalf("failed to load stock levels: %v", err)
+	}
+	if err := product.IncreaseStock(stock, productdomain.StockChange{Reason: productdomain.StockMovementImport, Actor: productdomain.ActorSystem}); err != nil {
+		t.Fatalf("failed to stock product: %v", err)
+	}
+	if err := f.products.Create(ctx, product); err != nil {
+		t.Fatalf("failed to create product: %v", err)
+	}
+	return product
+}
+
+// stock returns the stock and the reserved stock of a product
+func (f *checkoutFlow) stock(t *testing.T, productID uint) (int, int) {
+	t.Helper()
+	product, err := f.products.GetByID(context.Background(), productID)
+	if err != nil {
+		t.Fatalf("failed to get product %d: %v", productID, err)
+	}
+	return product.Stock, product.ReservedStock
+}
+
+// deliver relays the outbox to the bus until every consumer handled every event,
+// including the events the handlers stored in turn
+func (f *checkoutFlow) deliver(t *testing.T) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	for {
+		relayed, err := f.relay.RelayPending(ctx)
+		if err != nil {
+			t.Fatalf("failed to relay outbox: %v", err)
+		}
+		if err := f.bus.Wait(ctx); err != nil {
+			t.Fatalf("bus did not drain: %v", err)
+		}
+		if relayed == 0 {
+			break
+		}
+	}
+
+	for _, group := range consumerGroups {
+		if messages := f.bus.Messages(kafka.DLQTopicName(group)); len(messages) > 0 {
+			t.Fatalf("%s dead-lettered %d events", group, len(messages))
+		}
+	}
+}
+
+// createOrder places a pending order of the checkout user for the product
+func (f *checkoutFlow) createOrder(t *testing.T, id string, product *productdomain.Product, quantity int) *orderdomain.Order {
+	t.Helper()
+	items := []orderdomain.OrderItem{{ProductID: product.ID, SKU: product.SKU, Name: product.Name, Quantity: quantity, UnitPriceMinor: product.PriceMinor}}
+	shipping := orderdomain.Address{Name: "Jo Doe", Address: "1 Main St", City: "Springfield", Country: "US"}
+	order, err := orderdomain.NewOrder(id, checkoutUserID, checkoutBasketID, "USD", items, shipping, shipping)
+	if err != nil {
+		t.Fatalf("failed to build order: %v", err)
+	}
+	if err := f.orderRepo.Create(context.Background(), order); err != nil {
+		t.Fatalf("failed to create order: %v", err)
+	}
+	return order
+}
+
+// order gets the order
+func (f *checkoutFlow) order(t *testing.T, id string) *orderdomain.Order {
+	t.Helper()
+	order, err := f.orderRepo.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("failed to get order %s: %v", id, err)
+	}
+	return order
+}
+
+// payment gets the payment
+func (f *checkoutFlow) payment(t *testing.T, id string) *paymentdomain.Payment {
+	t.Helper()
+	payment, err := f.paymentRepo.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("failed to get payment %s: %v", id, err)
+	}
+	return payment
+}
+
+// saga gets the checkout saga
+func (f *checkoutFlow) saga(t *testing.T, id string) *paymentdomain.CheckoutSaga {
+	t.Helper()
+	saga, err := f.sagas.GetByID(context.Background(), id)
+	if err != nil {
+		t.Fatalf("failed to get checkout %s: %v", id, err)
+	}
+	return saga
+}
+
+func TestCheckoutCompletesAcrossServices(t *testing.T) {
+	flow := newCheckoutFlow(t)
+	ctx := context.Background()
+	shirt := flow.createProduct(t, "SHIRT", 2500, 10)
+	mug := flow.createProduct(t, "MUG", 1200, 5)
+	flow.baskets.items = []*basketpb.BasketItem{
+		{ProductId: uint32(shirt.ID), Quantity: 2},
+		{ProductId: uint32(mug.ID), Quantity: 1},
+	}
+
+	checkout, err := flow.checkout.Checkout(ctx, checkoutUserID, dto.CheckoutRequest{
+		BasketID:      checkoutBasketID,
+		OrderID:       "order-1",
+		Amount:        62,
+		Currency:      "USD",
+		PaymentMethod: string(paymentdomain.PaymentMethodCreditCard),
+	})
+	if err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	if checkout.Step != string(paymentdomain.CheckoutStepConfirmPayment) {
+		t.Fatalf("checkout waits at %s, want %s", checkout.Step, paymentdomain.CheckoutStepConfirmPayment)
+	}
+	if stock, reserved := flow.stock(t, shirt.ID); stock != 10 || reserved != 2 {
+		t.Fatalf("shirt stock = %d reserved %d, want 10 reserved 2", stock, reserved)
+	}
+
+	if _, err := flow.payments.ProcessPayment(ctx, checkoutUserID, checkout.PaymentID, dto.ProcessPaymentRequest{}); err != nil {
+		t.Fatalf("ProcessPayment() error = %v", err)
+	}
+	flow.deliver(t)
+
+	// The product service sells the reserved stock and the basket service clears the basket,
+	// whose cleared event completes the checkout
+	for _, tt := range []struct {
+		product   *productdomain.Product
+		wantStock int
+	}{
+		{product: shirt, wantStock: 8},
+		{product: mug, wantStock: 4},
+	} {
+		if stock, reserved := flow.stock(t, tt.product.ID); stock != tt.wantStock || reserved != 0 {
+			t.Errorf("%s stock = %d reserved %d, want %d reserved 0", tt.product.SKU, stock, reserved, tt.wantStock)
+		}
+	}
+	if cleared := flow.baskets.clearedBaskets(); len(cleared) != 1 || cleared[0] != checkoutBasketID {
+		t.Errorf("cleared baskets %v, want %s", cleared, checkoutBasketID)
+	}
+	if saga := flow.saga(t, checkout.ID); saga.Status != paymentdomain.CheckoutStatusCompleted {
+		t.Errorf("checkout status = %s at %s, want completed", saga.Status, saga.Step)
+	}
+}
+
+func TestCheckoutReleasesStockWhenPaymentIsCancelled(t *testing.T) {
+	flow := newCheckoutFlow(t)
+	ctx := context.Background()
+	shirt := flow.createProduct(t, "SHIRT", 2500, 10)
+	flow.baskets.items = []*basketpb.BasketItem{{ProductId: uint32(shirt.ID), Quantity: 3}}
+
+	checkout, err := flow.checkout.Checkout(ctx, checkoutUserID, dto.CheckoutRequest{
+		BasketID:      checkoutBasketID,
+		OrderID:       "order-1",
+		Amount:        75,
+		Currency:      "USD",
+		PaymentMethod: string(paymentdomain.PaymentMethodCreditCard),
+	})
+	if err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+
+	if _, err := flow.payments.CancelPayment(ctx, checkoutUserID, checkout.PaymentID); err != nil {
+		t.Fatalf("CancelPayment() error = %v", err)
+	}
+	flow.deliver(t)
+
+	if stock, reserved := flow.stock(t, shirt.ID); stock != 10 || reserved != 0 {
+		t.Errorf("shirt stock = %d reserved %d, want 10 reserved 0", stock, reserved)
+	}
+	if cleared := flow.baskets.clearedBaskets(); len(cleared) != 0 {
+		t.Errorf("cleared baskets %v, want none", cleared)
+	}
+	saga := flow.saga(t, checkout.ID)
+	if saga.Status != paymentdomain.CheckoutStatusCompensated {
+		t.Errorf("checkout status = %s at %s, want compensated", saga.Status, saga.Step)
+	}
+	for _, item := range saga.Items {
+		if item.Reserved {
+			t.Errorf("product %d still reserved by the checkout", item.ProductID)
+		}
+	}
+}
+
+func TestCheckoutRejectsAmountBelowProductPrices(t *testing.T) {
+	flow := newCheckoutFlow(t)
+	shirt := flow.createProduct(t, "SHIRT", 2500, 10)
+	// The basket carries a price the product service does not charge
+	flow.baskets.items = []*basketpb.BasketItem{{ProductId: uint32(shirt.ID), Quantity: 1, UnitPriceMinor: 100}}
+
+	_, err := flow.checkout.Checkout(context.Background(), checkoutUserID, dto.CheckoutRequest{
+		BasketID:      checkoutBasketID,
+		OrderID:       "order-1",
+		Amount:        1,
+		Currency:      "USD",
+		PaymentMethod: string(paymentdomain.PaymentMethodCreditCard),
+	})
+	if !errors.Is(err, paymentapp.ErrCheckoutRejected) {
+		t.Fatalf("Checkout() error = %v, want %v", err, paymentapp.ErrCheckoutRejected)
+	}
+	if stock, reserved := flow.stock(t, shirt.ID); stock != 10 || reserved != 0 {
+		t.Errorf("shirt stock = %d reserved %d, want 10 reserved 0", stock, reserved)
+	}
+}
+
+func TestCancelledOrderCancelsItsPendingPayment(t *testing.T) {
+	flow := newCheckoutFlow(t)
+	ctx := context.Background()
+	shirt := flow.createProduct(t, "SHIRT", 2500, 10)
+	flow.baskets.items = []*basketpb.BasketItem{{ProductId: uint32(shirt.ID), Quantity: 2}}
+	order := flow.createOrder(t, "order-1", shirt, 2)
+
+	checkout, err := flow.checkout.Checkout(ctx, checkoutUserID, dto.CheckoutRequest{
+		BasketID:      checkoutBasketID,
+		OrderID:       order.ID,
+		Amount:        50,
+		Currency:      "USD",
+		PaymentMethod: string(paymentdomain.PaymentMethodCreditCard),
+	})
+	if err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+
+	if _, err := flow.orders.CancelOrder(ctx, checkoutUserID, order.ID, orderdto.CancelOrderRequest{Reason: "changed my mind"}); err != nil {
+		t.Fatalf("CancelOrder() error = %v", err)
+	}
+	flow.deliver(t)
+
+	if payment := flow.payment(t, checkout.PaymentID); payment.Status != paymentdomain.PaymentStatusCancelled {
+		t.Errorf("payment status = %s, want cancelled", payment.Status)
+	}
+	if stock, reserved := flow.stock(t, shirt.ID); stock != 10 || reserved != 0 {
+		t.Errorf("shirt stock = %d reserved %d, want 10 reserved 0", stock, reserved)
+	}
+	if order := flow.order(t, order.ID); order.Status != orderdomain.OrderStatusCancelled {
+		t.Errorf("order status = %s, want cancelled", order.Status)
+	}
+}
+
+func TestOrderRejectsPaymentsItCannotTake(t *testing.T) {
+	tests := []struct {
+		name string
+		// orderQuantity differs from the checkout quantity for an underpaid order
+		orderQuantity   int
+		cancelOrder     bool
+		wantOrderStatus orderdomain.OrderStatus
+		wantOrderError  bool
+	}{
+		{
+			name:            "payment completed after the order was cancelled",
+			orderQuantity:   1,
+			cancelOrder:     true,
+			wantOrderStatus: orderdomain.OrderStatusCancelled,
+		},
+		{
+			name:            "payment below the order total",
+			orderQuantity:   3,
+			wantOrderStatus: orderdomain.OrderStatusPaymentFailed,
+			wantOrderError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flow := newCheckoutFlow(t)
+			ctx := context.Background()
+			shirt := flow.createProduct(t, "SHIRT", 2500, 10)
+			flow.baskets.items = []*basketpb.BasketItem{{ProductId: uint32(shirt.ID), Quantity: 1}}
+			order := flow.createOrder(t, "order-1", shirt, tt.orderQuantity)
+
+			checkout, err := flow.checkout.Checkout(ctx, checkoutUserID, dto.CheckoutRequest{
+				BasketID:      checkoutBasketID,
+				OrderID:       order.ID,
+				Amount:        25,
+				Currency:      "USD",
+				PaymentMethod: string(paymentdomain.PaymentMethodCreditCard),
+			})
+			if err != nil {
+				t.Fatalf("Checkout() error = %v", err)
+			}
+
+			// The order is cancelled before its cancellation reaches the payment service
+			if tt.cancelOrder {
+				if _, err := flow.orders.CancelOrder(ctx, checkoutUserID, order.ID, orderdto.CancelOrderRequest{Reason: "changed my mind"}); err != nil {
+					t.Fatalf("CancelOrder() error = %v", err)
+				}
+			}
+			if _, err := flow.payments.ProcessPayment(ctx, checkoutUserID, checkout.PaymentID, dto.ProcessPaymentRequest{}); err != nil {
+				t.Fatalf("ProcessPayment() error = %v", err)
+			}
+			flow.deliver(t)
+
+			// The payment is refunded in full and the sold stock returned
+			payment := flow.payment(t, checkout.PaymentID)
+			if payment.Status != paymentdomain.PaymentStatusRefunded {
+				t.Errorf("payment status = %s, want refunded", payment.Status)
+			}
+			if refunds := flow.gateway.refundedAmounts(); len(refunds) != 1 || refunds[0] != money.New(2500, "USD") {
+				t.Errorf("gateway refunded %v, want 25.00 USD once", refunds)
+			}
+			if stock, reserved := flow.stock(t, shirt.ID); stock != 10 || reserved != 0 {
+				t.Errorf("shirt stock = %d reserved %d, want 10 reserved 0", stock, reserved)
+			}
+
+			order = flow.order(t, order.ID)
+			if order.Status != tt.wantOrderStatus {
+				t.Errorf("order status = %s, want %s", order.Status, tt.wantOrderStatus)
+			}
+			if order.PaymentID != nil {
+				t.Errorf("order paid by payment %s, want unpaid", *order.PaymentID)
+			}
+			if got := order.PaymentError != ""; got != tt.wantOrderError {
+				t.Errorf("order payment error = %q, want recorded %v", order.PaymentError, tt.wantOrderError)
+			}
+		})
+	}
+}
+
+// serverProductClient calls the product gRPC server in process
+type serverProductClient struct {
+	client.ProductClient
+	server *productgrpc.ProductServer
+}
+
+func (c *serverProductClient) GetProduct(ctx context.Context, productID uint) (*productpb.Product, error) {
+	resp, err := c.server.GetProduct(ctx, &productpb.GetProductRequest{Id: uint32(productID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	return resp.Product, nil
+}
+
+func (c *serverProductClient) ReserveStock(ctx context.Context, paymentID string, items []client.ReservationItem, ttl time.Duration) error {
+	req := &productpb.ReserveStockRequest{ReferenceType: "payment", ReferenceId: paymentID, TtlSeconds: int32(ttl / time.Second)}
+	for _, item := range items {
+		reservationItem := &productpb.ReservationItem{ProductId: uint32(item.ProductID), Quantity: int32(item.Quantity)}
+		if item.VariantID != nil {
+			variantID := uint32(*item.VariantID)
+			reservationItem.VariantId = &variantID
+		}
+		req.Items = append(req.Items, reservationItem)
+	}
+	if _, err := c.server.ReserveStock(ctx, req); err != nil {
+		return fmt.Errorf("failed to reserve stock: %w", err)
+	}
+	return nil
+}
+
+func (c *serverProductClient) ReleaseReservation(ctx context.Context, paymentID string) error {
+	if _, err := c.server.ReleaseReservation(ctx, &productpb.ReleaseReservationRequest{ReferenceType: "payment", ReferenceId: paymentID}); err != nil {
+		return fmt.Errorf("failed to release reservation: %w", err)
+	}
+	return nil
+}
+
+// fakeBasketClient serves the basket of the checkout user from the basket repository
+type fakeBasketClient struct {
+	client.BasketClient
+	baskets *fakeBasketRepository
+}
+
+func (c *fakeBasketClient) ValidateBasket(ctx context.Context, userID uint) (*basketpb.BasketResponse, error) {
+	return &basketpb.BasketResponse{Id: checkoutBasketID, UserId: uint32(userID), Currency: "USD", Items: c.baskets.items}, nil
+}
+
+// fakeBasketRepository holds the basket of the checkout user and records the baskets
+// cleared by the basket consumer
+type fakeBasketRepository struct {
+	basketdomain.BasketRepository
+	items []*basketpb.BasketItem
+
+	mu      sync.Mutex
+	cleared []string
+}
+
+func (r *fakeBasketRepository) ClearItems(ctx context.Context, basketID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cleared = append(r.cleared, basketID)
+	return nil
+}
+
+func (r *fakeBasketRepository) clearedBaskets() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.cleared...)
+}
+
+// fakeGateway accepts every payment and completes it with the configured status
+type fakeGateway struct {
+	paymentdomain.PaymentGateway
+	status paymentdomain.PaymentStatus
+
+	mu       sync.Mutex
+	refunded []money.Money
+}
+
+func (g *fakeGateway) Provider() string {
+	return "mock"
+}
+
+func (g *fakeGateway) CreatePayment(ctx context.Context, payment *paymentdomain.Payment) (*paymentdomain.PaymentGatewayResponse, error) {
+	return &paymentdomain.PaymentGatewayResponse{TransactionID: "txn-" + payment.ID, Status: paymentdomain.PaymentStatusPending}, nil
+}
+
+func (g *fakeGateway) ProcessPayment(ctx context.Context, payment *paymentdomain.Payment, paymentMethodID string) (*paymentdomain.PaymentGatewayResponse, error) {
+	return &paymentdomain.PaymentGatewayResponse{TransactionID: *payment.TransactionID, Status: g.status}, nil
+}
+
+func (g *fakeGateway) CancelPayment(ctx context.Context, payment *paymentdomain.Payment) (*paymentdomain.PaymentGatewayResponse, error) {
+	return &paymentdomain.PaymentGatewayResponse{TransactionID: *payment.TransactionID, Status: paymentdomain.PaymentStatusCancelled}, nil
+}
+
+func (g *fakeGateway) RefundPayment(ctx context.Context, payment *paymentdomain.Payment, refund *paymentdomain.Refund) (*paymentdomain.PaymentGatewayResponse, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.refunded = append(g.refunded, refund.Amount())
+	return &paymentdomain.PaymentGatewayResponse{TransactionID: "refund-" + payment.ID, Status: paymentdomain.PaymentStatusRefunded}, nil
+}
+
+func (g *fakeGateway) refundedAmounts() []money.Money {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]money.Money(nil), g.refunded...)
+}
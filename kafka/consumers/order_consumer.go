@@ -0,0 +1,115 @@
+package consumers
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/ddd-micro/internal/order/application"
+	"github.com/ddd-micro/kafka"
+	"github.com/ddd-micro/pkg/money"
+)
+
+// OrderConsumer moves orders through their lifecycle as their payments progress
+type OrderConsumer struct {
+	consumer     kafka.EventConsumer
+	orderService *application.OrderServiceCQRS
+}
+
+// NewOrderConsumer creates a new order consumer
+func NewOrderConsumer(consumer kafka.EventConsumer, orderService *application.OrderServiceCQRS) *OrderConsumer {
+	return &OrderConsumer{
+		consumer:     consumer,
+		orderService: orderService,
+	}
+}
+
+// HandlePaymentCompleted handles payment completed events
+func (c *OrderConsumer) HandlePaymentCompleted(ctx context.Context, event kafka.PaymentCompletedEvent) error {
+	log.Printf("Processing payment completed event: %s", event.Data.PaymentID)
+
+	amount := money.New(event.Data.AmountMinor, event.Data.Currency)
+	if err := c.orderService.PaymentCompleted(ctx, event.Data.OrderID, event.Data.PaymentID, amount); err != nil {
+		return fmt.Errorf("failed to mark order %s paid: %w", event.Data.OrderID, err)
+	}
+
+	return nil
+}
+
+// HandlePaymentFailed handles payment failed events
+func (c *OrderConsumer) HandlePaymentFailed(ctx context.Context, event kafka.PaymentFailedEvent) error {
+	log.Printf("Processing payment failed event: %s", event.Data.PaymentID)
+
+	if err := c.orderService.PaymentFailed(ctx, event.Data.OrderID, event.Data.PaymentID, event.Data.Reason); err != nil {
+		return fmt.Errorf("failed to record failed payment of order %s: %w", event.Data.OrderID, err)
+	}
+
+	return nil
+}
+
+// HandlePaymentCancelled handles payment cancelled events
+func (c *OrderConsumer) HandlePaymentCancelled(ctx context.Context, event kafka.PaymentCancelledEvent) error {
+	log.Printf("Processing payment cancelled event: %s", event.Data.PaymentID)
+
+	// The order stays open, so the customer can pay it again
+	if err := c.orderService.PaymentFailed(ctx, event.Data.OrderID, event.Data.PaymentID, event.Data.Reason); err != nil {
+		return fmt.Errorf("failed to record cancelled payment of order %s: %w", event.Data.OrderID, err)
+	}
+
+	return nil
+}
+
+// HandlePaymentRefunded handles payment refunded events
+func (c *OrderConsumer) HandlePaymentRefunded(ctx context.Context, event kafka.PaymentRefundedEvent) error {
+	log.Printf("Processing payment refunded event: %s", event.Data.PaymentID)
+
+	totalRefunded := money.New(event.Data.TotalRefundedMinor, event.Data.Currency)
+	if err := c.orderService.PaymentRefunded(ctx, event.Data.OrderID, event.Data.PaymentID, totalRefunded, event.Data.Reason); err != nil {
+		return fmt.Errorf("failed to record refund of order %s: %w", event.Data.OrderID, err)
+	}
+
+	return nil
+}
+
+// Start subscribes to payment events and starts consuming
+func (c *OrderConsumer) Start(ctx context.Context) error {
+	log.Println("Starting order consumer...")
+
+	handlers := []error{
+		c.consumer.ConsumePaymentCompleted(func(ctx context.Context, event kafka.PaymentCompletedEvent) error {
+			return c.HandlePaymentCompleted(ctx, event)
+		}),
+		c.consumer.ConsumePaymentFailed(func(ctx context.Context, event kafka.PaymentFailedEvent) error {
+			return c.HandlePaymentFailed(ctx, event)
+		}),
+		c.consumer.ConsumePaymentCancelled(func(ctx context.Context, event kafka.PaymentCancelledEvent) error {
+			return c.HandlePaymentCancelled(ctx, event)
+		}),
+		c.consumer.ConsumePaymentRefunded(func(ctx context.Context, event kafka.PaymentRefundedEvent) error {
+			return c.HandlePaymentRefunded(ctx, event)
+		}),
+	}
+	for _, err := range handlers {
+		if err != nil {
+			return fmt.Errorf("failed to register order consumer handler: %w", err)
+		}
+	}
+
+	if err := c.consumer.Start(); err != nil {
+		return fmt.Errorf("failed to start order consumer: %w", err)
+	}
+
+	log.Println("Order consumer started successfully")
+	return nil
+}
+
+// Stop stops the order consumer, waiting for the message in progress
+func (c *OrderConsumer) Stop() error {
+	log.Println("Stopping order consumer...")
+	return c.consumer.Stop()
+}
+
+// Connected reports whether the consumer has joined its consumer group
+func (c *OrderConsumer) Connected() bool {
+	return c.consumer.Connected()
+}
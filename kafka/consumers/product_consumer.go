@@ -2,21 +2,40 @@ package consumers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 
+	"github.com/ddd-micro/internal/product/application"
+	"github.com/ddd-micro/internal/product/domain"
+	productkafka "github.com/ddd-micro/internal/product/infrastructure/kafka"
 	"github.com/ddd-micro/kafka"
+	"github.com/ddd-micro/pkg/gormtx"
 )
 
 // ProductConsumer handles product-related Kafka events
 type ProductConsumer struct {
-	eventPublisher kafka.EventPublisher
+	consumer       kafka.EventConsumer
+	repo           domain.ProductRepository
+	transactor     *gormtx.Transactor
+	eventPublisher *productkafka.ProductEventPublisher
+	reservations   *application.ReservationService
 }
 
 // NewProductConsumer creates a new product consumer
-func NewProductConsumer(eventPublisher kafka.EventPublisher) *ProductConsumer {
+func NewProductConsumer(
+	consumer kafka.EventConsumer,
+	repo domain.ProductRepository,
+	transactor *gormtx.Transactor,
+	eventPublisher *productkafka.ProductEventPublisher,
+	reservations *application.ReservationService,
+) *ProductConsumer {
 	return &ProductConsumer{
+		consumer:       consumer,
+		repo:           repo,
+		transactor:     transactor,
 		eventPublisher: eventPublisher,
+		reservations:   reservations,
 	}
 }
 
@@ -24,84 +43,180 @@ func NewProductConsumer(eventPublisher kafka.EventPublisher) *ProductConsumer {
 func (c *ProductConsumer) HandlePaymentCompleted(ctx context.Context, event kafka.PaymentCompletedEvent) error {
 	log.Printf("Processing payment completed event for stock update: %s", event.Data.PaymentID)
 
-	// Update stock for each item in the payment
-	for _, item := range event.Data.Items {
-		// Calculate new stock (assuming we have the current stock)
-		// In a real implementation, you would fetch current stock from the product service
-		newStock := 100 - item.Quantity // Placeholder calculation
-
-		// Publish stock updated event
-		stockUpdatedEvent := kafka.StockUpdatedEvent{
-			BaseEvent: kafka.NewBaseEvent(kafka.EventTypeStockUpdated, "product-service"),
-			Data: kafka.StockUpdatedData{
-				ProductID: item.ProductID,
-				Quantity:  -item.Quantity, // Negative because we're reducing stock
-				NewStock:  newStock,
-				Reason:    "payment_completed",
-				OrderID:   &event.Data.OrderID,
-				PaymentID: &event.Data.PaymentID,
-			},
+	return c.transactor.Within(ctx, func(ctx context.Context) error {
+		// Stock held for the payment, or else for its basket, is sold as it was held
+		confirmed, err := c.confirmReservation(ctx, event.Data.PaymentID, event.Data.BasketID)
+		if err != nil || confirmed {
+			return err
 		}
 
-		if err := c.eventPublisher.PublishStockUpdated(stockUpdatedEvent); err != nil {
-			return fmt.Errorf("failed to publish stock updated event for product %d: %w", item.ProductID, err)
+		// Reduce stock for every item at once, so a failing item leaves all stock untouched
+		for _, item := range event.Data.Items {
+			if err := c.changeStock(ctx, item, -item.Quantity, domain.StockMovementSale, "payment_completed", event.Data.OrderID, event.Data.PaymentID); err != nil {
+				return err
+			}
 		}
-
-		log.Printf("Stock updated for product %d: -%d units, new stock: %d",
-			item.ProductID, item.Quantity, newStock)
-	}
-
-	return nil
+		return nil
+	})
 }
 
 // HandlePaymentFailed handles payment failed events
 func (c *ProductConsumer) HandlePaymentFailed(ctx context.Context, event kafka.PaymentFailedEvent) error {
 	log.Printf("Processing payment failed event for stock restoration: %s", event.Data.PaymentID)
 
-	// In case of payment failure, we might need to restore reserved stock
-	// This would depend on your business logic - whether you reserve stock before payment
-	// or only update stock after successful payment
-
-	log.Printf("Payment failed, no stock changes needed for payment %s", event.Data.PaymentID)
-	return nil
+	// Stock held for the basket stays held, so the customer can pay again
+	return c.releaseReservation(ctx, event.Data.PaymentID)
 }
 
 // HandlePaymentCancelled handles payment cancelled events
 func (c *ProductConsumer) HandlePaymentCancelled(ctx context.Context, event kafka.PaymentCancelledEvent) error {
 	log.Printf("Processing payment cancelled event for stock restoration: %s", event.Data.PaymentID)
 
-	// Similar to payment failed, restore any reserved stock
-	log.Printf("Payment cancelled, no stock changes needed for payment %s", event.Data.PaymentID)
+	return c.releaseReservation(ctx, event.Data.PaymentID)
+}
+
+// HandlePaymentRefunded handles payment refunded events
+func (c *ProductConsumer) HandlePaymentRefunded(ctx context.Context, event kafka.PaymentRefundedEvent) error {
+	log.Printf("Processing payment refunded event for stock restoration: %s", event.Data.PaymentID)
+
+	// Restock each refunded item
+	return c.transactor.Within(ctx, func(ctx context.Context) error {
+		for _, item := range event.Data.Items {
+			if err := c.changeStock(ctx, item, item.Quantity, domain.StockMovementRefund, "payment_refunded", event.Data.OrderID, event.Data.PaymentID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// confirmReservation sells the stock held for the payment, or else for its basket, and
+// reports whether a reservation was confirmed
+func (c *ProductConsumer) confirmReservation(ctx context.Context, paymentID string, basketID *string) (bool, error) {
+	confirmed, err := c.confirmReservationOf(ctx, domain.ReservationReferencePayment, paymentID, paymentID)
+	if err != nil || confirmed || basketID == nil || *basketID == "" {
+		return confirmed, err
+	}
+	return c.confirmReservationOf(ctx, domain.ReservationReferenceBasket, *basketID, paymentID)
+}
+
+// confirmReservationOf confirms the active reservation of the reference. A missing
+// reservation is not an error, and neither is an expired one: it has been released, so
+// the items are taken from the available stock instead.
+func (c *ProductConsumer) confirmReservationOf(ctx context.Context, referenceType domain.ReservationReference, referenceID, paymentID string) (bool, error) {
+	_, err := c.reservations.ConfirmReservation(ctx, string(referenceType), referenceID, domain.PaymentActor(paymentID))
+	switch {
+	case err == nil:
+		log.Printf("Stock reservation of %s %s confirmed for payment %s", referenceType, referenceID, paymentID)
+		return true, nil
+	case errors.Is(err, domain.ErrReservationExpired):
+		log.Printf("Stock reservation of %s %s expired before payment %s", referenceType, referenceID, paymentID)
+		return false, nil
+	case errors.Is(err, domain.ErrReservationNotFound):
+		return false, nil
+	default:
+		return false, fmt.Errorf("failed to confirm stock reservation of %s %s: %w", referenceType, referenceID, err)
+	}
+}
+
+// releaseReservation gives back the stock held for the payment, if any
+func (c *ProductConsumer) releaseReservation(ctx context.Context, paymentID string) error {
+	_, err := c.reservations.ReleaseReservation(ctx, string(domain.ReservationReferencePayment), paymentID)
+	if errors.Is(err, domain.ErrReservationNotFound) {
+		log.Printf("No stock reserved for payment %s", paymentID)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to release stock reservation of payment %s: %w", paymentID, err)
+	}
+
+	log.Printf("Stock reservation of payment %s released", paymentID)
 	return nil
 }
 
-// HandleStockUpdated handles stock updated events (from other services)
-func (c *ProductConsumer) HandleStockUpdated(ctx context.Context, event kafka.StockUpdatedEvent) error {
-	log.Printf("Processing stock updated event for product %d: %+d units, new stock: %d",
-		event.Data.ProductID, event.Data.Quantity, event.Data.NewStock)
+// changeStock applies a stock change for a payment item and stores its stock movement
+// and the stock updated event with the resulting stock in the same transaction. Payments
+// carry no shipping destination, so the stock is allocated by warehouse priority.
+func (c *ProductConsumer) changeStock(ctx context.Context, item kafka.PaymentItem, quantity int, movement domain.StockMovementReason, reason, orderID, paymentID string) error {
+	product, err := c.repo.GetByIDForUpdate(ctx, item.ProductID)
+	if err != nil {
+		return fmt.Errorf("failed to get product %d: %w", item.ProductID, err)
+	}
+
+	change := domain.StockChange{
+		VariantID: item.VariantID,
+		Reason:    movement,
+		Actor:     domain.PaymentActor(paymentID),
+		PaymentID: &paymentID,
+	}
+	if orderID != "" {
+		change.OrderID = &orderID
+	}
+	if quantity < 0 {
+		err = product.ReduceStock(-quantity, change)
+	} else {
+		err = product.IncreaseStock(quantity, change)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update stock of product %d: %w", item.ProductID, err)
+	}
+
+	if err := c.repo.Update(ctx, product); err != nil {
+		return fmt.Errorf("failed to save stock of product %d: %w", item.ProductID, err)
+	}
 
-	// Here you would update the actual stock in your product database
-	// This is where the real stock update logic would go
+	available := product.AvailableStockOf(item.VariantID)
+	if err := c.eventPublisher.PublishStockUpdated(ctx, product.ID, item.VariantID, quantity, available, reason, &orderID, &paymentID); err != nil {
+		return fmt.Errorf("failed to publish stock updated event for product %d: %w", item.ProductID, err)
+	}
 
+	if item.VariantID != nil {
+		log.Printf("Stock updated for variant %d of product %d: %+d units, available stock: %d", *item.VariantID, product.ID, quantity, available)
+	} else {
+		log.Printf("Stock updated for product %d: %+d units, available stock: %d", product.ID, quantity, available)
+	}
 	return nil
 }
 
-// Start starts the product consumer
+// Start subscribes to payment events and starts consuming
 func (c *ProductConsumer) Start(ctx context.Context) error {
 	log.Println("Starting product consumer...")
 
-	// In a real implementation, you would:
-	// 1. Create a Kafka consumer
-	// 2. Subscribe to payment and stock events
-	// 3. Handle events in a loop
+	handlers := []error{
+		c.consumer.ConsumePaymentCompleted(func(ctx context.Context, event kafka.PaymentCompletedEvent) error {
+			return c.HandlePaymentCompleted(ctx, event)
+		}),
+		c.consumer.ConsumePaymentFailed(func(ctx context.Context, event kafka.PaymentFailedEvent) error {
+			return c.HandlePaymentFailed(ctx, event)
+		}),
+		c.consumer.ConsumePaymentCancelled(func(ctx context.Context, event kafka.PaymentCancelledEvent) error {
+			return c.HandlePaymentCancelled(ctx, event)
+		}),
+		c.consumer.ConsumePaymentRefunded(func(ctx context.Context, event kafka.PaymentRefundedEvent) error {
+			return c.HandlePaymentRefunded(ctx, event)
+		}),
+	}
+	for _, err := range handlers {
+		if err != nil {
+			return fmt.Errorf("failed to register product consumer handler: %w", err)
+		}
+	}
+
+	if err := c.consumer.Start(); err != nil {
+		return fmt.Errorf("failed to start product consumer: %w", err)
+	}
 
-	// For now, this is a placeholder
 	log.Println("Product consumer started successfully")
 	return nil
 }
 
-// Stop stops the product consumer
+// Stop stops the product consumer, waiting for the message in progress
 func (c *ProductConsumer) Stop() error {
 	log.Println("Stopping product consumer...")
-	return nil
+	return c.consumer.Stop()
+}
+
+// Connected reports whether the consumer has joined its consumer group
+func (c *ProductConsumer) Connected() bool {
+	return c.consumer.Connected()
 }
@@ -0,0 +1,42 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+// ProcessedEventStore records which events a consumer group has processed
+type ProcessedEventStore interface {
+	// Process runs fn unless the group already processed the event, and marks the event
+	// processed once fn succeeds. It reports false when the event was a duplicate.
+	Process(ctx context.Context, group, eventID string, fn func(ctx context.Context) error) (bool, error)
+}
+
+// Dedup skips events the consumer group already processed, so redelivered events
+// have no effect. Events without an ID are always handled.
+func Dedup(group string, store ProcessedEventStore) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, data []byte) error {
+			var event BaseEvent
+			if err := json.Unmarshal(data, &event); err != nil {
+				return fmt.Errorf("failed to unmarshal event: %w", err)
+			}
+			if event.ID == "" {
+				return next(ctx, data)
+			}
+
+			processed, err := store.Process(ctx, group, event.ID, func(ctx context.Context) error {
+				return next(ctx, data)
+			})
+			if err != nil {
+				return err
+			}
+			if !processed {
+				log.Printf("Skipping duplicate %s event %s for group %s", event.Type, event.ID, group)
+			}
+			return nil
+		}
+	}
+}
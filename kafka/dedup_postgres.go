@@ -0,0 +1,94 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ddd-micro/pkg/gormtx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProcessedEvent is an event a consumer group has processed
+type ProcessedEvent struct {
+	ConsumerGroup string    `gorm:"primaryKey;size:255"`
+	EventID       string    `gorm:"primaryKey;size:255"`
+	ProcessedAt   time.Time `gorm:"not null;index"`
+}
+
+// TableName specifies the table name for GORM
+func (ProcessedEvent) TableName() string {
+	return "processed_events"
+}
+
+// MigrateProcessedEvents creates the processed events table
+func MigrateProcessedEvents(db *gorm.DB) error {
+	return db.AutoMigrate(&ProcessedEvent{})
+}
+
+// PostgresProcessedEventStore records processed events in the transaction of the handler,
+// so an event is marked processed exactly when the handler's changes are committed
+type PostgresProcessedEventStore struct {
+	db         *gorm.DB
+	transactor *gormtx.Transactor
+	retention  time.Duration
+
+	mu         sync.Mutex
+	lastPurged time.Time
+}
+
+// NewPostgresProcessedEventStore creates a store keeping processed events for the retention window
+func NewPostgresProcessedEventStore(db *gorm.DB, transactor *gormtx.Transactor, retention time.Duration) *PostgresProcessedEventStore {
+	return &PostgresProcessedEventStore{
+		db:         db,
+		transactor: transactor,
+		retention:  retention,
+	}
+}
+
+// Process runs fn in a transaction that also marks the event processed.
+// A concurrent delivery of the same event waits on the marker row until the
+// first one commits, and is then skipped.
+func (s *PostgresProcessedEventStore) Process(ctx context.Context, group, eventID string, fn func(ctx context.Context) error) (bool, error) {
+	processed := false
+	err := s.transactor.Within(ctx, func(ctx context.Context) error {
+		result := gormtx.DB(ctx, s.db).
+			Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&ProcessedEvent{ConsumerGroup: group, EventID: eventID, ProcessedAt: time.Now().UTC()})
+		if result.Error != nil {
+			return fmt.Errorf("failed to mark event %s processed: %w", eventID, result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		processed = true
+		return fn(ctx)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	s.purgeExpired(ctx)
+	return processed, nil
+}
+
+// purgeExpired deletes processed events older than the retention window, at most once
+// per tenth of the window
+func (s *PostgresProcessedEventStore) purgeExpired(ctx context.Context) {
+	s.mu.Lock()
+	if time.Since(s.lastPurged) < s.retention/10 {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPurged = time.Now()
+	s.mu.Unlock()
+
+	cutoff := time.Now().UTC().Add(-s.retention)
+	if err := s.db.WithContext(ctx).Where("processed_at < ?", cutoff).Delete(&ProcessedEvent{}).Error; err != nil {
+		log.Printf("Failed to purge processed events: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Values of the processed event keys in Redis
+const (
+	redisEventProcessing = "processing"
+	redisEventProcessed  = "processed"
+)
+
+// RedisProcessedEventStore records processed events in Redis. Redis cannot share a
+// transaction with the handler, so the event is claimed before the handler runs and
+// released again when it fails; a crash in between leaves the claim until it expires.
+type RedisProcessedEventStore struct {
+	client    *redis.Client
+	retention time.Duration
+	// claimTimeout bounds how long a claim of a crashed handler blocks redelivery
+	claimTimeout time.Duration
+}
+
+// NewRedisProcessedEventStore creates a store keeping processed events for the retention window
+func NewRedisProcessedEventStore(client *redis.Client, retention, claimTimeout time.Duration) *RedisProcessedEventStore {
+	return &RedisProcessedEventStore{
+		client:       client,
+		retention:    retention,
+		claimTimeout: claimTimeout,
+	}
+}
+
+// Process claims the event, runs fn and marks the event processed for the retention window
+func (s *RedisProcessedEventStore) Process(ctx context.Context, group, eventID string, fn func(ctx context.Context) error) (bool, error) {
+	key := fmt.Sprintf("processed_event:%s:%s", group, eventID)
+
+	claimed, err := s.client.SetNX(ctx, key, redisEventProcessing, s.claimTimeout).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim event %s: %w", eventID, err)
+	}
+	if !claimed {
+		state, err := s.client.Get(ctx, key).Result()
+		if err != nil && err != redis.Nil {
+			return false, fmt.Errorf("failed to get state of event %s: %w", eventID, err)
+		}
+		if state != redisEventProcessed {
+			// Another delivery is still handling the event; retry once it has finished
+			return false, fmt.Errorf("event %s is being processed by another consumer", eventID)
+		}
+		return false, nil
+	}
+
+	if err := fn(ctx); err != nil {
+		// Release the claim so the redelivered event is handled again
+		if delErr := s.client.Del(context.WithoutCancel(ctx), key).Err(); delErr != nil {
+			return false, fmt.Errorf("%w (and failed to release event %s: %v)", err, eventID, delErr)
+		}
+		return false, err
+	}
+
+	if err := s.client.Set(context.WithoutCancel(ctx), key, redisEventProcessed, s.retention).Err(); err != nil {
+		return false, fmt.Errorf("failed to mark event %s processed: %w", eventID, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,235 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/ddd-micro/pkg/gormtx"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// memoryProcessedEventStore records processed events in memory
+type memoryProcessedEventStore struct {
+	mu        sync.Mutex
+	processed map[string]bool
+}
+
+func newMemoryProcessedEventStore() *memoryProcessedEventStore {
+	return &memoryProcessedEventStore{processed: make(map[string]bool)}
+}
+
+func (s *memoryProcessedEventStore) Process(ctx context.Context, group, eventID string, fn func(ctx context.Context) error) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := group + "/" + eventID
+	if s.processed[key] {
+		return false, nil
+	}
+	if err := fn(ctx); err != nil {
+		return false, err
+	}
+	s.processed[key] = true
+	return true, nil
+}
+
+func (s *memoryProcessedEventStore) isProcessed(group, eventID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.processed[group+"/"+eventID]
+}
+
+func TestDedupSkipsRedeliveredEvents(t *testing.T) {
+	bus := NewMemoryBus(1)
+	t.Cleanup(bus.Close)
+	publisher := NewMemoryPublisher(bus, &PublisherConfig{})
+	store := newMemoryProcessedEventStore()
+
+	var mu sync.Mutex
+	handled := make(map[string]int)
+	for _, group := range []string{"product-service", "order-service"} {
+		consumer := NewMemoryConsumer(bus, &ConsumerConfig{GroupID: group, Offset: sarama.OffsetOldest, RetryAttempts: 1})
+		consumer.Use(Dedup(group, store))
+		if err := consumer.ConsumeStockUpdated(func(ctx context.Context, event StockUpdatedEvent) error {
+			mu.Lock()
+			defer mu.Unlock()
+			handled[group]++
+			return nil
+		}); err != nil {
+			t.Fatalf("failed to register handler: %v", err)
+		}
+		if err := consumer.Start(); err != nil {
+			t.Fatalf("failed to start consumer: %v", err)
+		}
+		t.Cleanup(func() { consumer.Stop() })
+	}
+
+	// The producer retried the publish, so the same event arrives twice
+	event := StockUpdatedEvent{
+		BaseEvent: NewBaseEvent(context.Background(), EventTypeStockUpdated, "test", "1"),
+		Data:      StockUpdatedData{ProductID: 1, Quantity: 1, NewStock: 1, Reason: "test"},
+	}
+	for range 2 {
+		if err := publisher.PublishStockUpdated(event); err != nil {
+			t.Fatalf("failed to publish: %v", err)
+		}
+	}
+	waitForBus(t, bus)
+
+	mu.Lock()
+	defer mu.Unlock()
+	// Each group handles the event once; a group's processed events never affect another group
+	for _, group := range []string{"product-service", "order-service"} {
+		if handled[group] != 1 {
+			t.Errorf("%s handled the event %d times, want 1", group, handled[group])
+		}
+		if !store.isProcessed(group, event.ID) {
+			t.Errorf("%s did not mark the event processed", group)
+		}
+	}
+}
+
+func TestDedupMarksEventsProcessedOnlyWhenHandled(t *testing.T) {
+	errHandler := errors.New("handler failed")
+	store := newMemoryProcessedEventStore()
+
+	calls := 0
+	failures := 1
+	handler := Dedup("payment-service", store)(func(ctx context.Context, data []byte) error {
+		calls++
+		if calls <= failures {
+			return errHandler
+		}
+		return nil
+	})
+
+	event := NewBaseEvent(context.Background(), EventTypeOrderCreated, "test", "order-1")
+	data := mustMarshal(t, event)
+
+	// A failed delivery leaves the event unprocessed, so the redelivery runs the handler again
+	if err := handler(context.Background(), data); !errors.Is(err, errHandler) {
+		t.Fatalf("first delivery error = %v, want %v", err, errHandler)
+	}
+	if store.isProcessed("payment-service", event.ID) {
+		t.Fatal("failed delivery marked the event processed")
+	}
+	if err := handler(context.Background(), data); err != nil {
+		t.Fatalf("redelivery error = %v", err)
+	}
+	if !store.isProcessed("payment-service", event.ID) {
+		t.Fatal("handled redelivery did not mark the event processed")
+	}
+
+	// Later redeliveries are skipped
+	if err := handler(context.Background(), data); err != nil {
+		t.Fatalf("duplicate delivery error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("handler ran %d times, want 2", calls)
+	}
+
+	// Events without an ID cannot be recognised again, so they are always handled
+	anonymous := mustMarshal(t, BaseEvent{Type: EventTypeOrderCreated})
+	for range 2 {
+		if err := handler(context.Background(), anonymous); err != nil {
+			t.Fatalf("event without ID error = %v", err)
+		}
+	}
+	if calls != 4 {
+		t.Errorf("handler ran %d times, want 4", calls)
+	}
+}
+
+func TestPostgresProcessedEventStore(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database: %v", err)
+	}
+	// Every connection to :memory: opens its own database
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := MigrateProcessedEvents(db); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+
+	const group = "order-service"
+	ctx := context.Background()
+	store := NewPostgresProcessedEventStore(db, gormtx.NewTransactor(db), time.Hour)
+
+	// An event processed before the retention window is purged by the next event
+	expired := ProcessedEvent{ConsumerGroup: group, EventID: "expired", ProcessedAt: time.Now().UTC().Add(-2 * time.Hour)}
+	if err := db.Create(&expired).Error; err != nil {
+		t.Fatalf("failed to store processed event: %v", err)
+	}
+
+	// The handler's own change stands in for the aggregate it updates
+	change := func(ctx context.Context) error {
+		return gormtx.DB(ctx, db).Create(&ProcessedEvent{ConsumerGroup: "change", EventID: "event-1", ProcessedAt: time.Now().UTC()}).Error
+	}
+	errHandler := errors.New("handler failed")
+
+	// A failed handler rolls back both its change and the processed marker
+	processed, err := store.Process(ctx, group, "event-1", func(ctx context.Context) error {
+		if err := change(ctx); err != nil {
+			return err
+		}
+		return errHandler
+	})
+	if !errors.Is(err, errHandler) || processed {
+		t.Fatalf("Process() = %v, %v, want the handler error", processed, err)
+	}
+	if got := countProcessedEvents(t, db); got != 0 {
+		t.Fatalf("failed handler left %d rows, want none", got)
+	}
+
+	processed, err = store.Process(ctx, group, "event-1", change)
+	if err != nil || !processed {
+		t.Fatalf("Process() = %v, %v, want the event processed", processed, err)
+	}
+
+	// The redelivered event is skipped
+	processed, err = store.Process(ctx, group, "event-1", func(ctx context.Context) error {
+		t.Error("handler ran for a duplicate event")
+		return nil
+	})
+	if err != nil || processed {
+		t.Fatalf("Process() = %v, %v, want the duplicate skipped", processed, err)
+	}
+
+	var events []ProcessedEvent
+	if err := db.Order("consumer_group").Find(&events).Error; err != nil {
+		t.Fatalf("failed to load processed events: %v", err)
+	}
+	if len(events) != 2 || events[0].ConsumerGroup != "change" || events[1].ConsumerGroup != group || events[1].EventID != "event-1" {
+		t.Errorf("processed events = %+v, want the change and the marker of event-1", events)
+	}
+}
+
+func countProcessedEvents(t *testing.T, db *gorm.DB) int64 {
+	t.Helper()
+	var count int64
+	if err := db.Model(&ProcessedEvent{}).Where("event_id <> ?", "expired").Count(&count).Error; err != nil {
+		t.Fatalf("failed to count processed events: %v", err)
+	}
+	return count
+}
+
+func mustMarshal(t *testing.T, event interface{}) []byte {
+	t.Helper()
+	data, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("failed to marshal event: %v", err)
+	}
+	return data
+}
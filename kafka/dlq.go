@@ -0,0 +1,178 @@
+package kafka
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// HeaderRedrivenFrom marks a message re-driven from a dead-letter topic with its DLQ position
+const HeaderRedrivenFrom = "x-redriven-from"
+
+// DeadLetter is a message in a dead-letter topic with its failure metadata
+type DeadLetter struct {
+	Topic             string            `json:"topic"`
+	Partition         int32             `json:"partition"`
+	Offset            int64             `json:"offset"`
+	Key               string            `json:"key,omitempty"`
+	EventType         EventType         `json:"event_type"`
+	Error             string            `json:"error"`
+	Attempts          int               `json:"attempts"`
+	OriginalTopic     string            `json:"original_topic"`
+	OriginalPartition int32             `json:"original_partition"`
+	OriginalOffset    int64             `json:"original_offset"`
+	FailedAt          time.Time         `json:"failed_at"`
+	Headers           map[string]string `json:"headers"`
+	Value             []byte            `json:"-"`
+
+	headers []*sarama.RecordHeader
+}
+
+// DeadLetterFilter selects dead letters by position and event type; zero values match everything
+type DeadLetterFilter struct {
+	// Partition limits reading to one partition; -1 reads all partitions
+	Partition  int32
+	FromOffset int64
+	// ToOffset is inclusive; 0 reads up to the end of the partition
+	ToOffset  int64
+	EventType EventType
+	// Limit caps the number of dead letters returned; 0 returns all
+	Limit int
+}
+
+// ReadDeadLetters reads the dead letters currently in a dead-letter topic
+func ReadDeadLetters(client sarama.Client, topic string, filter DeadLetterFilter) ([]DeadLetter, error) {
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consumer: %w", err)
+	}
+	defer consumer.Close()
+
+	partitions, err := consumer.Partitions(topic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partitions of %s: %w", topic, err)
+	}
+
+	var letters []DeadLetter
+	for _, partition := range partitions {
+		if filter.Partition >= 0 && partition != filter.Partition {
+			continue
+		}
+
+		read, err := readPartition(client, consumer, topic, partition, filter, filter.Limit-len(letters))
+		if err != nil {
+			return nil, err
+		}
+		letters = append(letters, read...)
+		if filter.Limit > 0 && len(letters) >= filter.Limit {
+			break
+		}
+	}
+	return letters, nil
+}
+
+// readPartition reads the matching dead letters of one partition up to its current end
+func readPartition(client sarama.Client, consumer sarama.Consumer, topic string, partition int32, filter DeadLetterFilter, limit int) ([]DeadLetter, error) {
+	oldest, err := client.GetOffset(topic, partition, sarama.OffsetOldest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get oldest offset of %s/%d: %w", topic, partition, err)
+	}
+	newest, err := client.GetOffset(topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get newest offset of %s/%d: %w", topic, partition, err)
+	}
+
+	from := max(oldest, filter.FromOffset)
+	to := newest - 1
+	if filter.ToOffset > 0 {
+		to = min(to, filter.ToOffset)
+	}
+	if from > to {
+		return nil, nil
+	}
+
+	partitionConsumer, err := consumer.ConsumePartition(topic, partition, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume %s/%d: %w", topic, partition, err)
+	}
+	defer partitionConsumer.Close()
+
+	var letters []DeadLetter
+	for message := range partitionConsumer.Messages() {
+		letter := newDeadLetter(message)
+		if filter.EventType == "" || letter.EventType == filter.EventType {
+			letters = append(letters, letter)
+		}
+		if message.Offset >= to || (limit > 0 && len(letters) >= limit) {
+			break
+		}
+	}
+	return letters, nil
+}
+
+// newDeadLetter decodes the failure metadata of a dead-letter message
+func newDeadLetter(message *sarama.ConsumerMessage) DeadLetter {
+	letter := DeadLetter{
+		Topic:     message.Topic,
+		Partition: message.Partition,
+		Offset:    message.Offset,
+		Key:       string(message.Key),
+		Headers:   make(map[string]string, len(message.Headers)),
+		Value:     message.Value,
+		headers:   message.Headers,
+	}
+	for _, header := range message.Headers {
+		letter.Headers[string(header.Key)] = string(header.Value)
+	}
+
+	letter.EventType = messageEventType(message.Headers, message.Value)
+	letter.Error = letter.Headers[HeaderError]
+	letter.Attempts, _ = strconv.Atoi(letter.Headers[HeaderAttempts])
+	letter.OriginalTopic = letter.Headers[HeaderOriginalTopic]
+	if partition, err := strconv.ParseInt(letter.Headers[HeaderOriginalPartition], 10, 32); err == nil {
+		letter.OriginalPartition = int32(partition)
+	}
+	letter.OriginalOffset, _ = strconv.ParseInt(letter.Headers[HeaderOriginalOffset], 10, 64)
+	letter.FailedAt, _ = time.Parse(time.RFC3339, letter.Headers[HeaderFailedAt])
+	return letter
+}
+
+// Event returns the dead letter as a structured JSON event, whichever codec it was sent with
+func (l DeadLetter) Event() ([]byte, error) {
+	return decodeMessage(l.headers, l.Value)
+}
+
+// Redrive publishes a dead letter back to its original topic without its failure metadata
+func Redrive(producer sarama.SyncProducer, letter DeadLetter) error {
+	if letter.OriginalTopic == "" {
+		return fmt.Errorf("dead letter %s/%d/%d has no original topic", letter.Topic, letter.Partition, letter.Offset)
+	}
+
+	var headers []sarama.RecordHeader
+	for _, header := range letter.headers {
+		if !failureHeaders[string(header.Key)] && string(header.Key) != HeaderRedrivenFrom {
+			headers = append(headers, *header)
+		}
+	}
+	headers = append(headers, sarama.RecordHeader{
+		Key:   []byte(HeaderRedrivenFrom),
+		Value: []byte(fmt.Sprintf("%s/%d/%d", letter.Topic, letter.Partition, letter.Offset)),
+	})
+
+	message := &sarama.ProducerMessage{
+		Topic:   letter.OriginalTopic,
+		Value:   sarama.ByteEncoder(letter.Value),
+		Headers: headers,
+	}
+	if letter.Key != "" {
+		message.Key = sarama.StringEncoder(letter.Key)
+	}
+
+	if _, _, err := producer.SendMessage(message); err != nil {
+		return fmt.Errorf("failed to redrive %s/%d/%d to %s: %w",
+			letter.Topic, letter.Partition, letter.Offset, letter.OriginalTopic, err)
+	}
+	return nil
+}
@@ -1,29 +1,44 @@
 package kafka
 
 import (
+	"context"
 	"encoding/json"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // EventType represents the type of event
 type EventType string
 
 const (
-	EventTypePaymentCompleted EventType = "payment.completed"
-	EventTypePaymentFailed    EventType = "payment.failed"
-	EventTypePaymentCancelled EventType = "payment.cancelled"
-	EventTypeStockUpdated     EventType = "stock.updated"
-	EventTypeBasketCleared    EventType = "basket.cleared"
-	EventTypeOrderCreated     EventType = "order.created"
+	EventTypePaymentCompleted     EventType = "payment.completed"
+	EventTypePaymentFailed        EventType = "payment.failed"
+	EventTypePaymentCancelled     EventType = "payment.cancelled"
+	EventTypePaymentRefunded      EventType = "payment.refunded"
+	EventTypeStockUpdated         EventType = "stock.updated"
+	EventTypeBasketCleared        EventType = "basket.cleared"
+	EventTypeOrderCreated         EventType = "order.created"
+	EventTypeOrderCancelled       EventType = "order.cancelled"
+	EventTypeOrderPaymentRejected EventType = "order.payment_rejected"
 )
 
-// BaseEvent represents the base structure for all events
+// BaseEvent holds the CloudEvents 1.0 attributes shared by all events
 type BaseEvent struct {
-	ID        string    `json:"id"`
-	Type      EventType `json:"type"`
-	Timestamp time.Time `json:"timestamp"`
-	Source    string    `json:"source"`
-	Version   string    `json:"version"`
+	SpecVersion string    `json:"specversion"`
+	ID          string    `json:"id"`
+	Type        EventType `json:"type"`
+	Source      string    `json:"source"`
+	// Subject is the ID of the aggregate the event is about
+	Subject         string    `json:"subject,omitempty"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	// Version is the schema version of the event data in kafka/schemas, as an extension attribute
+	Version string `json:"version"`
+	// TraceParent and TraceState carry the W3C trace context of the producer,
+	// following the CloudEvents distributed tracing extension
+	TraceParent string `json:"traceparent,omitempty"`
+	TraceState  string `json:"tracestate,omitempty"`
 }
 
 // PaymentCompletedEvent represents a payment completion event
@@ -37,7 +52,7 @@ type PaymentCompletedData struct {
 	PaymentID     string                 `json:"payment_id"`
 	UserID        uint                   `json:"user_id"`
 	OrderID       string                 `json:"order_id"`
-	Amount        float64                `json:"amount"`
+	AmountMinor   int64                  `json:"amount_minor"` // In minor units of Currency
 	Currency      string                 `json:"currency"`
 	PaymentMethod string                 `json:"payment_method"`
 	Items         []PaymentItem          `json:"items"`
@@ -45,12 +60,13 @@ type PaymentCompletedData struct {
 	Metadata      map[string]interface{} `json:"metadata,omitempty"`
 }
 
-// PaymentItem represents an item in the payment
+// PaymentItem represents an item in the payment, priced in minor units of the payment currency
 type PaymentItem struct {
-	ProductID  uint    `json:"product_id"`
-	Quantity   int     `json:"quantity"`
-	UnitPrice  float64 `json:"unit_price"`
-	TotalPrice float64 `json:"total_price"`
+	ProductID       uint  `json:"product_id"`
+	VariantID       *uint `json:"variant_id,omitempty"` // Variant of the product, if one was bought
+	Quantity        int   `json:"quantity"`
+	UnitPriceMinor  int64 `json:"unit_price_minor"`
+	TotalPriceMinor int64 `json:"total_price_minor"`
 }
 
 // PaymentFailedEvent represents a payment failure event
@@ -64,7 +80,7 @@ type PaymentFailedData struct {
 	PaymentID     string  `json:"payment_id"`
 	UserID        uint    `json:"user_id"`
 	OrderID       string  `json:"order_id"`
-	Amount        float64 `json:"amount"`
+	AmountMinor   int64   `json:"amount_minor"` // In minor units of Currency
 	Currency      string  `json:"currency"`
 	PaymentMethod string  `json:"payment_method"`
 	Reason        string  `json:"reason"`
@@ -82,13 +98,34 @@ type PaymentCancelledData struct {
 	PaymentID     string  `json:"payment_id"`
 	UserID        uint    `json:"user_id"`
 	OrderID       string  `json:"order_id"`
-	Amount        float64 `json:"amount"`
+	AmountMinor   int64   `json:"amount_minor"` // In minor units of Currency
 	Currency      string  `json:"currency"`
 	PaymentMethod string  `json:"payment_method"`
 	Reason        string  `json:"reason"`
 	BasketID      *string `json:"basket_id,omitempty"`
 }
 
+// PaymentRefundedEvent represents a payment refund event
+type PaymentRefundedEvent struct {
+	BaseEvent
+	Data PaymentRefundedData `json:"data"`
+}
+
+// PaymentRefundedData contains the payment refund data
+type PaymentRefundedData struct {
+	PaymentID          string        `json:"payment_id"`
+	RefundID           string        `json:"refund_id"`
+	UserID             uint          `json:"user_id"`
+	OrderID            string        `json:"order_id"`
+	AmountMinor        int64         `json:"amount_minor"` // In minor units of Currency
+	TotalRefundedMinor int64         `json:"total_refunded_minor"`
+	Currency           string        `json:"currency"`
+	Reason             string        `json:"reason"`
+	FullRefund         bool          `json:"full_refund"`
+	Items              []PaymentItem `json:"items,omitempty"` // Items to restock, if known
+	BasketID           *string       `json:"basket_id,omitempty"`
+}
+
 // StockUpdatedEvent represents a stock update event
 type StockUpdatedEvent struct {
 	BaseEvent
@@ -98,8 +135,9 @@ type StockUpdatedEvent struct {
 // StockUpdatedData contains the stock update data
 type StockUpdatedData struct {
 	ProductID uint    `json:"product_id"`
-	Quantity  int     `json:"quantity"` // Positive for increase, negative for decrease
-	NewStock  int     `json:"new_stock"`
+	VariantID *uint   `json:"variant_id,omitempty"` // Set when the stock of a variant of the product changed
+	Quantity  int     `json:"quantity"`             // Change of NewStock, positive for increase, negative for decrease
+	NewStock  int     `json:"new_stock"`            // Stock available for sale, net of reserved stock
 	Reason    string  `json:"reason"`
 	OrderID   *string `json:"order_id,omitempty"`
 	PaymentID *string `json:"payment_id,omitempty"`
@@ -132,13 +170,39 @@ type OrderCreatedData struct {
 	OrderID      string        `json:"order_id"`
 	UserID       uint          `json:"user_id"`
 	PaymentID    string        `json:"payment_id"`
-	Amount       float64       `json:"amount"`
+	AmountMinor  int64         `json:"amount_minor"` // In minor units of Currency
 	Currency     string        `json:"currency"`
 	Items        []PaymentItem `json:"items"`
 	ShippingInfo ShippingInfo  `json:"shipping_info"`
 	BillingInfo  BillingInfo   `json:"billing_info"`
 }
 
+// OrderCancelledEvent represents an order cancellation event
+type OrderCancelledEvent struct {
+	BaseEvent
+	Data OrderCancelledData `json:"data"`
+}
+
+// OrderCancelledData contains the order cancellation data
+type OrderCancelledData struct {
+	OrderID string `json:"order_id"`
+	UserID  uint   `json:"user_id"`
+	Reason  string `json:"reason"`
+}
+
+// OrderPaymentRejectedEvent represents the rejection of a completed payment by its order
+type OrderPaymentRejectedEvent struct {
+	BaseEvent
+	Data OrderPaymentRejectedData `json:"data"`
+}
+
+// OrderPaymentRejectedData contains the rejected payment, which is to be refunded in full
+type OrderPaymentRejectedData struct {
+	OrderID   string `json:"order_id"`
+	PaymentID string `json:"payment_id"`
+	Reason    string `json:"reason"`
+}
+
 // ShippingInfo represents shipping information
 type ShippingInfo struct {
 	Name    string `json:"name"`
@@ -170,33 +234,50 @@ type EventPublisher interface {
 	PublishPaymentCompleted(event PaymentCompletedEvent) error
 	PublishPaymentFailed(event PaymentFailedEvent) error
 	PublishPaymentCancelled(event PaymentCancelledEvent) error
+	PublishPaymentRefunded(event PaymentRefundedEvent) error
 	PublishStockUpdated(event StockUpdatedEvent) error
 	PublishBasketCleared(event BasketClearedEvent) error
 	PublishOrderCreated(event OrderCreatedEvent) error
+	PublishOrderCancelled(event OrderCancelledEvent) error
+	PublishOrderPaymentRejected(event OrderPaymentRejectedEvent) error
+	// Publish publishes an already serialized event; events with the same key keep their order
+	Publish(eventType EventType, key string, payload []byte) error
 }
 
 // EventConsumer defines the interface for event consumers
 type EventConsumer interface {
-	ConsumePaymentCompleted(handler func(PaymentCompletedEvent) error) error
-	ConsumePaymentFailed(handler func(PaymentFailedEvent) error) error
-	ConsumePaymentCancelled(handler func(PaymentCancelledEvent) error) error
-	ConsumeStockUpdated(handler func(StockUpdatedEvent) error) error
-	ConsumeBasketCleared(handler func(BasketClearedEvent) error) error
-	ConsumeOrderCreated(handler func(OrderCreatedEvent) error) error
+	ConsumePaymentCompleted(handler func(context.Context, PaymentCompletedEvent) error) error
+	ConsumePaymentFailed(handler func(context.Context, PaymentFailedEvent) error) error
+	ConsumePaymentCancelled(handler func(context.Context, PaymentCancelledEvent) error) error
+	ConsumePaymentRefunded(handler func(context.Context, PaymentRefundedEvent) error) error
+	ConsumeStockUpdated(handler func(context.Context, StockUpdatedEvent) error) error
+	ConsumeBasketCleared(handler func(context.Context, BasketClearedEvent) error) error
+	ConsumeOrderCreated(handler func(context.Context, OrderCreatedEvent) error) error
+	ConsumeOrderCancelled(handler func(context.Context, OrderCancelledEvent) error) error
+	ConsumeOrderPaymentRejected(handler func(context.Context, OrderPaymentRejectedEvent) error) error
+	// Use adds middleware wrapping every registered handler; call it before Start
+	Use(middleware ...Middleware)
 	Start() error
 	Stop() error
+	// Connected reports whether the consumer has joined its consumer group
+	Connected() bool
 }
 
 // Helper functions
 
-// NewBaseEvent creates a new base event
-func NewBaseEvent(eventType EventType, source string) BaseEvent {
+// NewBaseEvent creates a new base event about the aggregate identified by subject,
+// carrying the trace context of the span in ctx
+func NewBaseEvent(ctx context.Context, eventType EventType, source, subject string) BaseEvent {
 	return BaseEvent{
-		ID:        generateEventID(),
-		Type:      eventType,
-		Timestamp: time.Now().UTC(),
-		Source:    source,
-		Version:   "1.0",
+		SpecVersion:     CloudEventsSpecVersion,
+		ID:              generateEventID(),
+		Type:            eventType,
+		Source:          source,
+		Subject:         subject,
+		Time:            time.Now().UTC(),
+		DataContentType: ContentTypeJSON,
+		Version:         LatestSchemaVersion(eventType),
+		TraceParent:     traceParent(ctx),
 	}
 }
 
@@ -210,17 +291,7 @@ func FromJSON(data []byte, event interface{}) error {
 	return json.Unmarshal(data, event)
 }
 
-// generateEventID generates a unique event ID
+// generateEventID generates a UUIDv7 event ID, unique and ordered by creation time
 func generateEventID() string {
-	return time.Now().Format("20060102150405") + "-" + randomString(8)
-}
-
-// randomString generates a random string of specified length
-func randomString(length int) string {
-	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	b := make([]byte, length)
-	for i := range b {
-		b[i] = charset[time.Now().UnixNano()%int64(len(charset))]
-	}
-	return string(b)
+	return uuid.Must(uuid.NewV7()).String()
 }
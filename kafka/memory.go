@@ -0,0 +1,436 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// DefaultMemoryPartitions is the number of partitions of every topic of the default memory bus
+const DefaultMemoryPartitions = 3
+
+// errMemoryBusClosed is returned when publishing to a closed memory bus
+var errMemoryBusClosed = errors.New("memory bus closed")
+
+// MemoryBus is an in-process event bus with the delivery semantics of Kafka: messages are
+// partitioned by key, every consumer group receives every message, and within a group each
+// partition is handled in order by one member, moving on only once the message is handled.
+// Services use the process-wide DefaultMemoryBus when EVENT_BUS=memory.
+type MemoryBus struct {
+	partitions int
+	mu         sync.Mutex
+	// cond is broadcast whenever messages, members or offsets change
+	cond   *sync.Cond
+	topics map[string][][]*sarama.ConsumerMessage
+	groups map[string]*memoryGroup
+	closed bool
+}
+
+// memoryGroup is a consumer group of a memory bus
+type memoryGroup struct {
+	// members are ordered by the time they joined
+	members []*memoryConsumer
+	// offsets holds the next offset to deliver of every partition the group reads
+	offsets map[memoryPartition]int64
+	// busy counts the messages being handled
+	busy int
+}
+
+// memoryPartition identifies a partition of a memory bus topic
+type memoryPartition struct {
+	topic     string
+	partition int32
+}
+
+var defaultMemoryBus = sync.OnceValue(func() *MemoryBus {
+	return NewMemoryBus(DefaultMemoryPartitions)
+})
+
+// DefaultMemoryBus returns the memory bus shared by all publishers and consumers of the process
+func DefaultMemoryBus() *MemoryBus {
+	return defaultMemoryBus()
+}
+
+// NewMemoryBus creates a memory bus whose topics have the given number of partitions
+func NewMemoryBus(partitions int) *MemoryBus {
+	bus := &MemoryBus{
+		partitions: max(partitions, 1),
+		topics:     make(map[string][][]*sarama.ConsumerMessage),
+		groups:     make(map[string]*memoryGroup),
+	}
+	bus.cond = sync.NewCond(&bus.mu)
+	return bus
+}
+
+// log returns the partitions of a topic, creating the topic on first use; callers hold mu
+func (b *MemoryBus) log(topic string) [][]*sarama.ConsumerMessage {
+	partitions, exists := b.topics[topic]
+	if !exists {
+		partitions = make([][]*sarama.ConsumerMessage, b.partitions)
+		b.topics[topic] = partitions
+	}
+	return partitions
+}
+
+// append adds a message to the partition of its key and returns its partition and offset
+func (b *MemoryBus) append(topic string, key, value []byte, headers []sarama.RecordHeader) (int32, int64, error) {
+	hash := fnv.New32a()
+	hash.Write(key)
+	partition := int32(hash.Sum32() % uint32(b.partitions))
+
+	message := &sarama.ConsumerMessage{
+		Topic:     topic,
+		Partition: partition,
+		Key:       key,
+		Value:     value,
+		Timestamp: time.Now(),
+	}
+	for i := range headers {
+		message.Headers = append(message.Headers, &headers[i])
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return 0, 0, errMemoryBusClosed
+	}
+
+	partitions := b.log(topic)
+	message.Offset = int64(len(partitions[partition]))
+	partitions[partition] = append(partitions[partition], message)
+	b.cond.Broadcast()
+	return partition, message.Offset, nil
+}
+
+// send appends a message forwarded by a consumer to a retry or dead-letter topic
+func (b *MemoryBus) send(message *sarama.ProducerMessage) error {
+	var key, value []byte
+	var err error
+	if message.Key != nil {
+		if key, err = message.Key.Encode(); err != nil {
+			return err
+		}
+	}
+	if message.Value != nil {
+		if value, err = message.Value.Encode(); err != nil {
+			return err
+		}
+	}
+	_, _, err = b.append(message.Topic, key, value, message.Headers)
+	return err
+}
+
+// Messages returns the messages of a topic, partition by partition in offset order
+func (b *MemoryBus) Messages(topic string) []*sarama.ConsumerMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var messages []*sarama.ConsumerMessage
+	for _, partition := range b.topics[topic] {
+		messages = append(messages, partition...)
+	}
+	return messages
+}
+
+// Wait blocks until every consumer group has handled all messages of the topics it
+// reads, including the messages published by the handlers themselves, or ctx ends
+func (b *MemoryBus) Wait(ctx context.Context) error {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for !b.idle() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// idle reports whether no group has messages left to handle
+func (b *MemoryBus) idle() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, group := range b.groups {
+		if len(group.members) == 0 {
+			continue
+		}
+		if group.busy > 0 {
+			return false
+		}
+		for partition, offset := range group.offsets {
+			if offset < int64(len(b.topics[partition.topic][partition.partition])) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Close stops the delivery of messages and rejects further publishing
+func (b *MemoryBus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	b.cond.Broadcast()
+}
+
+// join adds a consumer to its group and delivers the partitions of its topics to the group,
+// starting at the configured offset for partitions the group has not read before
+func (b *MemoryBus) join(consumer *memoryConsumer, topics []string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	group, exists := b.groups[consumer.config.GroupID]
+	if !exists {
+		group = &memoryGroup{offsets: make(map[memoryPartition]int64)}
+		b.groups[consumer.config.GroupID] = group
+	}
+	group.members = append(group.members, consumer)
+	metrics.Rebalances.WithLabelValues(consumer.config.GroupID).Inc()
+
+	for _, topic := range topics {
+		for i, messages := range b.log(topic) {
+			partition := memoryPartition{topic: topic, partition: int32(i)}
+			if _, exists := group.offsets[partition]; exists {
+				continue
+			}
+			if consumer.config.Offset == sarama.OffsetOldest {
+				group.offsets[partition] = 0
+			} else {
+				group.offsets[partition] = int64(len(messages))
+			}
+			go b.deliver(group, partition)
+		}
+	}
+	b.cond.Broadcast()
+}
+
+// leave removes a consumer from its group; its partitions move to the remaining members
+func (b *MemoryBus) leave(consumer *memoryConsumer) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	group := b.groups[consumer.config.GroupID]
+	for i, member := range group.members {
+		if member == consumer {
+			group.members = append(group.members[:i], group.members[i+1:]...)
+			metrics.Rebalances.WithLabelValues(consumer.config.GroupID).Inc()
+			break
+		}
+	}
+	b.cond.Broadcast()
+}
+
+// assignee returns the member handling a partition, or nil when no member reads its topic;
+// callers hold mu
+func (g *memoryGroup) assignee(partition memoryPartition) *memoryConsumer {
+	var members []*memoryConsumer
+	for _, member := range g.members {
+		if member.subscribed[partition.topic] {
+			members = append(members, member)
+		}
+	}
+	if len(members) == 0 {
+		return nil
+	}
+	return members[int(partition.partition)%len(members)]
+}
+
+// deliver hands the messages of a partition, one at a time and in order, to the group member
+// the partition is assigned to; a message is delivered again until a member handles it
+func (b *MemoryBus) deliver(group *memoryGroup, partition memoryPartition) {
+	for {
+		b.mu.Lock()
+		var member *memoryConsumer
+		for {
+			if b.closed {
+				b.mu.Unlock()
+				return
+			}
+			member = group.assignee(partition)
+			if member != nil && group.offsets[partition] < int64(len(b.topics[partition.topic][partition.partition])) {
+				break
+			}
+			b.cond.Wait()
+		}
+		offset := group.offsets[partition]
+		message := b.topics[partition.topic][partition.partition][offset]
+		group.busy++
+		member.inflight.Add(1)
+		b.mu.Unlock()
+
+		handled := member.consume(message)
+		member.inflight.Done()
+
+		b.mu.Lock()
+		group.busy--
+		if handled {
+			group.offsets[partition] = offset + 1
+			setLag(member.config.GroupID, partition.topic, partition.partition,
+				int64(len(b.topics[partition.topic][partition.partition])), offset+1)
+		}
+		b.cond.Broadcast()
+		b.mu.Unlock()
+	}
+}
+
+// memoryPublisher implements EventPublisher on a memory bus
+type memoryPublisher struct {
+	typedPublisher
+	bus    *MemoryBus
+	config *PublisherConfig
+}
+
+// NewMemoryPublisher creates a publisher on a memory bus
+func NewMemoryPublisher(bus *MemoryBus, config *PublisherConfig) EventPublisher {
+	p := &memoryPublisher{
+		bus:    bus,
+		config: config,
+	}
+	p.typedPublisher = typedPublisher{publish: p.Publish}
+	return p
+}
+
+// Publish publishes a serialized event to the topic of its type, partitioned by key
+func (p *memoryPublisher) Publish(eventType EventType, key string, payload []byte) error {
+	topic := p.config.TopicRoutes.Topic(eventType)
+	headers, value, err := p.config.encodeEvent(topic, eventType, payload)
+	if err != nil {
+		return err
+	}
+
+	var event BaseEvent
+	json.Unmarshal(payload, &event)
+	span := startProducerSpan(eventType, topic, event.TraceParent, &headers)
+
+	start := time.Now()
+	partition, offset, err := p.bus.append(topic, []byte(key), value, headers)
+	observePublish(topic, eventType, start, err)
+	if err != nil {
+		err = fmt.Errorf("failed to publish %s event: %w", eventType, err)
+		finishSpan(span, err)
+		return err
+	}
+	span.SetTag("messaging.kafka.partition", partition)
+	span.SetTag("messaging.kafka.offset", offset)
+	finishSpan(span, nil)
+	return nil
+}
+
+// Close closes the publisher; the bus stays open for other publishers
+func (p *memoryPublisher) Close() error {
+	return nil
+}
+
+// memoryConsumer implements EventConsumer on a memory bus
+type memoryConsumer struct {
+	bus    *MemoryBus
+	config *ConsumerConfig
+	stages []retryStage
+	handlerRegistry
+	// subscribed holds the topics the consumer reads once started
+	subscribed map[string]bool
+	ctx        context.Context
+	cancel     context.CancelFunc
+	// inflight counts the messages being handled, so Stop can wait for them
+	inflight  sync.WaitGroup
+	connected atomic.Bool
+}
+
+// NewMemoryConsumer creates a consumer on a memory bus
+func NewMemoryConsumer(bus *MemoryBus, config *ConsumerConfig) EventConsumer {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &memoryConsumer{
+		bus:    bus,
+		config: config,
+		stages: config.retryStages(),
+		ctx:    ctx,
+		cancel: cancel,
+
+		subscribed: make(map[string]bool),
+
+		handlerRegistry: newHandlerRegistry(),
+	}
+}
+
+// Start joins the consumer group of the consumer
+func (c *memoryConsumer) Start() error {
+	if err := c.wrapHandlers(); err != nil {
+		return err
+	}
+	topics := c.topics(c.config.TopicRoutes, c.stages)
+	for _, topic := range topics {
+		c.subscribed[topic] = true
+	}
+
+	c.bus.join(c, topics)
+	c.connected.Store(true)
+
+	log.Printf("Memory consumer started for topics: %v, group: %s", topics, c.config.GroupID)
+	return nil
+}
+
+// Stop leaves the consumer group and waits for the messages being handled
+func (c *memoryConsumer) Stop() error {
+	c.bus.leave(c)
+	c.cancel()
+	c.inflight.Wait()
+	c.connected.Store(false)
+	return nil
+}
+
+// Connected reports whether the consumer has joined its consumer group
+func (c *memoryConsumer) Connected() bool {
+	return c.connected.Load()
+}
+
+// consume handles a message the way the Kafka consumer does, retrying and forwarding it to the
+// retry and dead-letter topics, and reports false when it has to be delivered again
+func (c *memoryConsumer) consume(message *sarama.ConsumerMessage) bool {
+	stage := stageOf(c.stages, message.Topic)
+	if stage > 0 && !waitForRetry(c.ctx, message, c.stages[stage].delay) {
+		return false
+	}
+
+	eventType := messageEventType(message.Headers, message.Value)
+	handler, exists := c.handlers[eventType]
+	if !exists {
+		log.Printf("No handler found for event type: %s", eventType)
+		return true
+	}
+
+	err := handleMessage(c.ctx, c.config, handler, eventType, message)
+	if err == nil {
+		return true
+	}
+	if c.ctx.Err() != nil {
+		// Stopping; another member of the group receives the message
+		return false
+	}
+	log.Printf("Error processing message: %v", err)
+
+	topic, retryAt := failureTopic(c.config, c.stages, stage)
+	if topic == "" {
+		log.Printf("Dropping message %s/%d/%d after all retries", message.Topic, message.Partition, message.Offset)
+		return true
+	}
+	if err := c.bus.send(failedMessage(message, topic, max(c.config.RetryAttempts, 1), err, retryAt)); err != nil {
+		log.Printf("Failed to forward message %s/%d/%d to %s: %v", message.Topic, message.Partition, message.Offset, topic, err)
+		return false
+	}
+
+	log.Printf("Forwarded failed message %s/%d/%d to %s", message.Topic, message.Partition, message.Offset, topic)
+	return true
+}
@@ -0,0 +1,331 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// startMemoryConsumer starts a consumer of stock updated events on the bus and stops it
+// when the test ends
+func startMemoryConsumer(t *testing.T, bus *MemoryBus, config *ConsumerConfig, handler func(context.Context, StockUpdatedEvent) error) EventConsumer {
+	t.Helper()
+	if config.Offset == 0 {
+		config.Offset = sarama.OffsetOldest
+	}
+	if config.RetryAttempts == 0 {
+		config.RetryAttempts = 1
+	}
+
+	consumer := NewMemoryConsumer(bus, config)
+	if err := consumer.ConsumeStockUpdated(handler); err != nil {
+		t.Fatalf("failed to register handler: %v", err)
+	}
+	if err := consumer.Start(); err != nil {
+		t.Fatalf("failed to start consumer: %v", err)
+	}
+	t.Cleanup(func() { consumer.Stop() })
+	return consumer
+}
+
+// publishStockUpdated publishes a stock update of the product, keyed by the product
+func publishStockUpdated(t *testing.T, publisher EventPublisher, productID uint, sequence int) {
+	t.Helper()
+	event := StockUpdatedEvent{
+		BaseEvent: NewBaseEvent(context.Background(), EventTypeStockUpdated, "test", strconv.FormatUint(uint64(productID), 10)),
+		Data: StockUpdatedData{
+			ProductID: productID,
+			Quantity:  sequence,
+			NewStock:  sequence,
+			Reason:    "test",
+		},
+	}
+	if err := publisher.PublishStockUpdated(event); err != nil {
+		t.Fatalf("failed to publish: %v", err)
+	}
+}
+
+// waitForBus waits until every consumer group handled all messages
+func waitForBus(t *testing.T, bus *MemoryBus) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := bus.Wait(ctx); err != nil {
+		t.Fatalf("bus did not drain: %v", err)
+	}
+}
+
+func TestMemoryBusDeliversInOrderPerKey(t *testing.T) {
+	bus := NewMemoryBus(3)
+	t.Cleanup(bus.Close)
+	publisher := NewMemoryPublisher(bus, &PublisherConfig{})
+
+	var mu sync.Mutex
+	received := make(map[uint][]int)
+	handler := func(ctx context.Context, event StockUpdatedEvent) error {
+		// Give the other partitions a chance to interleave
+		time.Sleep(time.Duration(event.Data.ProductID%3) * time.Millisecond)
+		mu.Lock()
+		defer mu.Unlock()
+		received[event.Data.ProductID] = append(received[event.Data.ProductID], event.Data.Quantity)
+		return nil
+	}
+
+	// Two members share the partitions of the group
+	startMemoryConsumer(t, bus, &ConsumerConfig{GroupID: "ordering"}, handler)
+	startMemoryConsumer(t, bus, &ConsumerConfig{GroupID: "ordering"}, handler)
+
+	const products, updates = 6, 20
+	for sequence := 1; sequence <= updates; sequence++ {
+		for productID := uint(1); productID <= products; productID++ {
+			publishStockUpdated(t, publisher, productID, sequence)
+		}
+	}
+	waitForBus(t, bus)
+
+	mu.Lock()
+	defer mu.Unlock()
+	for productID := uint(1); productID <= products; productID++ {
+		sequences := received[productID]
+		if len(sequences) != updates {
+			t.Fatalf("product %d received %d updates, want %d", productID, len(sequences), updates)
+		}
+		for i, sequence := range sequences {
+			if sequence != i+1 {
+				t.Fatalf("product %d received updates out of order: %v", productID, sequences)
+			}
+		}
+	}
+}
+
+func TestMemoryBusRedeliversOnHandlerError(t *testing.T) {
+	const group = "redelivery"
+	retryDelay := 20 * time.Millisecond
+	errHandler := errors.New("handler failed")
+
+	tests := []struct {
+		name     string
+		config   ConsumerConfig
+		failures int
+		// wantCalls is the number of times the handler runs
+		wantCalls   int
+		wantRetried int
+		wantDLQ     int
+	}{
+		{
+			name:      "retried in process",
+			config:    ConsumerConfig{RetryAttempts: 3, RetryDelay: time.Millisecond, DLQEnabled: true},
+			failures:  2,
+			wantCalls: 3,
+		},
+		{
+			name:        "retried from the retry topic",
+			config:      ConsumerConfig{RetryAttempts: 1, RetryTopicDelays: []time.Duration{retryDelay}, DLQEnabled: true},
+			failures:    1,
+			wantCalls:   2,
+			wantRetried: 1,
+		},
+		{
+			name:        "dead-lettered after every retry",
+			config:      ConsumerConfig{RetryAttempts: 2, RetryDelay: time.Millisecond, RetryTopicDelays: []time.Duration{retryDelay}, DLQEnabled: true},
+			failures:    -1,
+			wantCalls:   4,
+			wantRetried: 1,
+			wantDLQ:     1,
+		},
+		{
+			name:      "dropped without a dead-letter topic",
+			config:    ConsumerConfig{RetryAttempts: 2, RetryDelay: time.Millisecond},
+			failures:  -1,
+			wantCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := NewMemoryBus(1)
+			t.Cleanup(bus.Close)
+			publisher := NewMemoryPublisher(bus, &PublisherConfig{})
+
+			var mu sync.Mutex
+			calls := 0
+			var handled []int
+			config := tt.config
+			config.GroupID = group
+			startMemoryConsumer(t, bus, &config, func(ctx context.Context, event StockUpdatedEvent) error {
+				mu.Lock()
+				defer mu.Unlock()
+				if event.Data.ProductID == 1 {
+					calls++
+					if tt.failures < 0 || calls <= tt.failures {
+						return errHandler
+					}
+				}
+				handled = append(handled, int(event.Data.ProductID))
+				return nil
+			})
+
+			publishStockUpdated(t, publisher, 1, 1)
+			// A later message behind the failing one is still delivered
+			publishStockUpdated(t, publisher, 2, 1)
+			waitForBus(t, bus)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if calls != tt.wantCalls {
+				t.Errorf("handler ran %d times, want %d", calls, tt.wantCalls)
+			}
+			if !slices.Contains(handled, 2) {
+				t.Errorf("message behind the failing one was not handled: %v", handled)
+			}
+			if got := countRetried(bus, config); got != tt.wantRetried {
+				t.Errorf("retry topics hold %d messages, want %d", got, tt.wantRetried)
+			}
+			if got := len(bus.Messages(DLQTopicName(group))); got != tt.wantDLQ {
+				t.Errorf("dead-letter topic holds %d messages, want %d", got, tt.wantDLQ)
+			}
+		})
+	}
+}
+
+func TestMemoryBusRedeliversWhenConsumerStops(t *testing.T) {
+	bus := NewMemoryBus(1)
+	t.Cleanup(bus.Close)
+	publisher := NewMemoryPublisher(bus, &PublisherConfig{})
+
+	// The first member is stopped while handling the message, so it fails with a cancelled
+	// context and the message goes to the member that takes over the partition
+	started := make(chan struct{})
+	first := startMemoryConsumer(t, bus, &ConsumerConfig{GroupID: "handover", DLQEnabled: true}, func(ctx context.Context, event StockUpdatedEvent) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	publishStockUpdated(t, publisher, 1, 1)
+	<-started
+
+	var mu sync.Mutex
+	var handled []int
+	startMemoryConsumer(t, bus, &ConsumerConfig{GroupID: "handover", DLQEnabled: true}, func(ctx context.Context, event StockUpdatedEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		handled = append(handled, event.Data.Quantity)
+		return nil
+	})
+	if err := first.Stop(); err != nil {
+		t.Fatalf("failed to stop consumer: %v", err)
+	}
+	waitForBus(t, bus)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(handled) != 1 || handled[0] != 1 {
+		t.Fatalf("second member handled %v, want the interrupted message once", handled)
+	}
+	if got := len(bus.Messages(DLQTopicName("handover"))); got != 0 {
+		t.Errorf("dead-letter topic holds %d messages, want none", got)
+	}
+}
+
+func TestMemoryBusFansOutToConsumerGroups(t *testing.T) {
+	bus := NewMemoryBus(3)
+	t.Cleanup(bus.Close)
+	publisher := NewMemoryPublisher(bus, &PublisherConfig{})
+
+	var mu sync.Mutex
+	received := make(map[string][]string)
+	record := func(member string) func(context.Context, StockUpdatedEvent) error {
+		return func(ctx context.Context, event StockUpdatedEvent) error {
+			mu.Lock()
+			defer mu.Unlock()
+			received[member] = append(received[member], event.ID)
+			return nil
+		}
+	}
+
+	// Two groups with one member and one group with two members
+	startMemoryConsumer(t, bus, &ConsumerConfig{GroupID: "product-service"}, record("product"))
+	startMemoryConsumer(t, bus, &ConsumerConfig{GroupID: "order-service"}, record("order"))
+	startMemoryConsumer(t, bus, &ConsumerConfig{GroupID: "basket-service"}, record("basket-1"))
+	startMemoryConsumer(t, bus, &ConsumerConfig{GroupID: "basket-service"}, record("basket-2"))
+
+	const events = 30
+	for i := 0; i < events; i++ {
+		publishStockUpdated(t, publisher, uint(i%10+1), i/10+1)
+	}
+	waitForBus(t, bus)
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, member := range []string{"product", "order"} {
+		if got := len(received[member]); got != events {
+			t.Errorf("%s group received %d events, want %d", member, got, events)
+		}
+	}
+
+	// Members of one group split the partitions, so every event is handled exactly once
+	seen := make(map[string]int)
+	for _, member := range []string{"basket-1", "basket-2"} {
+		if len(received[member]) == 0 {
+			t.Errorf("%s received no partition", member)
+		}
+		for _, id := range received[member] {
+			seen[id]++
+		}
+	}
+	if len(seen) != events {
+		t.Errorf("basket group handled %d distinct events, want %d", len(seen), events)
+	}
+	for id, count := range seen {
+		if count != 1 {
+			t.Errorf("basket group handled event %s %d times", id, count)
+		}
+	}
+}
+
+func TestMemoryBusStartsNewGroupsAtConfiguredOffset(t *testing.T) {
+	bus := NewMemoryBus(1)
+	t.Cleanup(bus.Close)
+	publisher := NewMemoryPublisher(bus, &PublisherConfig{})
+
+	// Create the topic with one message before either group joins
+	publishStockUpdated(t, publisher, 1, 1)
+
+	var mu sync.Mutex
+	counts := make(map[string]int)
+	count := func(group string) func(context.Context, StockUpdatedEvent) error {
+		return func(ctx context.Context, event StockUpdatedEvent) error {
+			mu.Lock()
+			defer mu.Unlock()
+			counts[group]++
+			return nil
+		}
+	}
+	startMemoryConsumer(t, bus, &ConsumerConfig{GroupID: "oldest", Offset: sarama.OffsetOldest}, count("oldest"))
+	startMemoryConsumer(t, bus, &ConsumerConfig{GroupID: "newest", Offset: sarama.OffsetNewest}, count("newest"))
+
+	publishStockUpdated(t, publisher, 1, 2)
+	waitForBus(t, bus)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if counts["oldest"] != 2 || counts["newest"] != 1 {
+		t.Fatalf("received %v, want oldest 2 and newest 1", counts)
+	}
+}
+
+// countRetried counts the messages forwarded to the retry topics of the consumer
+func countRetried(bus *MemoryBus, config ConsumerConfig) int {
+	total := 0
+	for _, delay := range config.RetryTopicDelays {
+		total += len(bus.Messages(RetryTopicName(config.GroupID, delay)))
+	}
+	return total
+}
@@ -0,0 +1,122 @@
+package kafka
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// lagInterval is how often the lag of claimed partitions is updated between messages
+const lagInterval = 10 * time.Second
+
+// metrics are the Prometheus metrics of all publishers and consumers of the process; the
+// scrape target tells services apart, and the group label tells consumers in one service apart
+var metrics = struct {
+	PublishDuration   *prometheus.HistogramVec
+	MessagesPublished *prometheus.CounterVec
+	PublishErrors     *prometheus.CounterVec
+	MessagesConsumed  *prometheus.CounterVec
+	HandlerDuration   *prometheus.HistogramVec
+	HandlerErrors     *prometheus.CounterVec
+	Rebalances        *prometheus.CounterVec
+	ConsumerLag       *prometheus.GaugeVec
+}{
+	PublishDuration: promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "kafka_publish_duration_seconds",
+			Help:    "Time to publish an event until the broker acknowledged it",
+			Buckets: []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+		},
+		[]string{"topic", "event_type"},
+	),
+	MessagesPublished: promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kafka_messages_published_total",
+			Help: "Total number of events published",
+		},
+		[]string{"topic", "event_type"},
+	),
+	PublishErrors: promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kafka_publish_errors_total",
+			Help: "Total number of events that failed to publish",
+		},
+		[]string{"topic", "event_type"},
+	),
+	MessagesConsumed: promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kafka_messages_consumed_total",
+			Help: "Total number of messages consumed, including retry topics",
+		},
+		[]string{"group", "topic", "event_type"},
+	),
+	HandlerDuration: promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "kafka_handler_duration_seconds",
+			Help:    "Duration of a single event handler attempt",
+			Buckets: []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"group", "topic", "event_type"},
+	),
+	HandlerErrors: promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kafka_handler_errors_total",
+			Help: "Total number of failed event handler attempts",
+		},
+		[]string{"group", "topic", "event_type"},
+	),
+	Rebalances: promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kafka_consumer_rebalances_total",
+			Help: "Total number of consumer group sessions started by rebalances",
+		},
+		[]string{"group"},
+	),
+	ConsumerLag: promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kafka_consumer_group_lag",
+			Help: "Messages of a partition the consumer group has not handled yet, from the high-water mark",
+		},
+		[]string{"group", "topic", "partition"},
+	),
+}
+
+// handlerMetrics are the metrics of the handler attempts of one group, topic and event type
+type handlerMetrics struct {
+	duration prometheus.Observer
+	errors   prometheus.Counter
+}
+
+// newHandlerMetrics returns the handler metrics of a consumed message
+func newHandlerMetrics(group, topic string, eventType EventType) handlerMetrics {
+	return handlerMetrics{
+		duration: metrics.HandlerDuration.WithLabelValues(group, topic, string(eventType)),
+		errors:   metrics.HandlerErrors.WithLabelValues(group, topic, string(eventType)),
+	}
+}
+
+// observe records a handler attempt
+func (m handlerMetrics) observe(start time.Time, err error) {
+	m.duration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		m.errors.Inc()
+	}
+}
+
+// observePublish records a publish attempt
+func observePublish(topic string, eventType EventType, start time.Time, err error) {
+	if err != nil {
+		metrics.PublishErrors.WithLabelValues(topic, string(eventType)).Inc()
+		return
+	}
+	metrics.PublishDuration.WithLabelValues(topic, string(eventType)).Observe(time.Since(start).Seconds())
+	metrics.MessagesPublished.WithLabelValues(topic, string(eventType)).Inc()
+}
+
+// setLag records the lag of a partition as the messages between the next offset
+// of the group and the high-water mark
+func setLag(group, topic string, partition int32, highWaterMark, next int64) {
+	metrics.ConsumerLag.WithLabelValues(group, topic, strconv.Itoa(int(partition))).Set(float64(max(highWaterMark-next, 0)))
+}
@@ -0,0 +1,17 @@
+package kafka
+
+import "context"
+
+// Handler handles the payload of a consumed event
+type Handler func(ctx context.Context, data []byte) error
+
+// Middleware wraps a handler with behaviour shared by all events, such as deduplication
+type Middleware func(next Handler) Handler
+
+// chain wraps a handler in middleware, the first middleware outermost
+func chain(handler Handler, middleware []Middleware) Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
@@ -5,22 +5,36 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"strconv"
 	"time"
 
 	"github.com/IBM/sarama"
+	"github.com/ddd-micro/pkg/money"
 )
 
 // kafkaPublisher implements EventPublisher interface
 type kafkaPublisher struct {
+	typedPublisher
 	producer sarama.SyncProducer
 	config   *PublisherConfig
 }
 
+// typedPublisher implements the typed methods of EventPublisher on top of Publish
+type typedPublisher struct {
+	publish func(eventType EventType, key string, payload []byte) error
+}
+
 // PublisherConfig holds configuration for the Kafka publisher
 type PublisherConfig struct {
-	Brokers []string
-	Topic   string
-	Timeout time.Duration
+	Bus         Bus
+	Brokers     []string
+	TopicRoutes TopicRoutes
+	Timeout     time.Duration
+	// CloudEventsMode selects structured or binary CloudEvents messages
+	CloudEventsMode CloudEventsMode
+	// Codec is the default codec; TopicCodecs overrides it per topic
+	Codec       Codec
+	TopicCodecs map[string]Codec
 }
 
 // NewKafkaPublisher creates a new Kafka publisher
@@ -38,77 +52,136 @@ func NewKafkaPublisher(config *PublisherConfig) (EventPublisher, error) {
 		return nil, fmt.Errorf("failed to create Kafka producer: %w", err)
 	}
 
-	return &kafkaPublisher{
+	p := &kafkaPublisher{
 		producer: producer,
 		config:   config,
-	}, nil
+	}
+	p.typedPublisher = typedPublisher{publish: p.Publish}
+	return p, nil
 }
 
 // PublishPaymentCompleted publishes a payment completed event
-func (p *kafkaPublisher) PublishPaymentCompleted(event PaymentCompletedEvent) error {
-	return p.publishEvent(event.BaseEvent.Type, event)
+func (p typedPublisher) PublishPaymentCompleted(event PaymentCompletedEvent) error {
+	return p.publishEvent(event.BaseEvent, event)
 }
 
 // PublishPaymentFailed publishes a payment failed event
-func (p *kafkaPublisher) PublishPaymentFailed(event PaymentFailedEvent) error {
-	return p.publishEvent(event.BaseEvent.Type, event)
+func (p typedPublisher) PublishPaymentFailed(event PaymentFailedEvent) error {
+	return p.publishEvent(event.BaseEvent, event)
 }
 
 // PublishPaymentCancelled publishes a payment cancelled event
-func (p *kafkaPublisher) PublishPaymentCancelled(event PaymentCancelledEvent) error {
-	return p.publishEvent(event.BaseEvent.Type, event)
+func (p typedPublisher) PublishPaymentCancelled(event PaymentCancelledEvent) error {
+	return p.publishEvent(event.BaseEvent, event)
+}
+
+// PublishPaymentRefunded publishes a payment refunded event
+func (p typedPublisher) PublishPaymentRefunded(event PaymentRefundedEvent) error {
+	return p.publishEvent(event.BaseEvent, event)
 }
 
 // PublishStockUpdated publishes a stock updated event
-func (p *kafkaPublisher) PublishStockUpdated(event StockUpdatedEvent) error {
-	return p.publishEvent(event.BaseEvent.Type, event)
+func (p typedPublisher) PublishStockUpdated(event StockUpdatedEvent) error {
+	return p.publishEvent(event.BaseEvent, event)
 }
 
 // PublishBasketCleared publishes a basket cleared event
-func (p *kafkaPublisher) PublishBasketCleared(event BasketClearedEvent) error {
-	return p.publishEvent(event.BaseEvent.Type, event)
+func (p typedPublisher) PublishBasketCleared(event BasketClearedEvent) error {
+	return p.publishEvent(event.BaseEvent, event)
 }
 
 // PublishOrderCreated publishes an order created event
-func (p *kafkaPublisher) PublishOrderCreated(event OrderCreatedEvent) error {
-	return p.publishEvent(event.BaseEvent.Type, event)
+func (p typedPublisher) PublishOrderCreated(event OrderCreatedEvent) error {
+	return p.publishEvent(event.BaseEvent, event)
+}
+
+// PublishOrderCancelled publishes an order cancelled event
+func (p typedPublisher) PublishOrderCancelled(event OrderCancelledEvent) error {
+	return p.publishEvent(event.BaseEvent, event)
+}
+
+// PublishOrderPaymentRejected publishes an order payment rejected event
+func (p typedPublisher) PublishOrderPaymentRejected(event OrderPaymentRejectedEvent) error {
+	return p.publishEvent(event.BaseEvent, event)
 }
 
-// publishEvent publishes a generic event to Kafka
-func (p *kafkaPublisher) publishEvent(eventType EventType, event interface{}) error {
+// publishEvent publishes a generic event, keyed by the aggregate it is about
+func (p typedPublisher) publishEvent(base BaseEvent, event interface{}) error {
 	// Serialize event to JSON
 	eventData, err := json.Marshal(event)
 	if err != nil {
 		return fmt.Errorf("failed to marshal event: %w", err)
 	}
+	if err := ValidateEvent(eventData); err != nil {
+		return err
+	}
+
+	key := base.Subject
+	if key == "" {
+		key = string(base.Type)
+	}
+	return p.publish(base.Type, key, eventData)
+}
+
+// Publish publishes a serialized event to the topic of its type, partitioned by key
+func (p *kafkaPublisher) Publish(eventType EventType, key string, payload []byte) error {
+	topic := p.config.TopicRoutes.Topic(eventType)
+	headers, value, err := p.config.encodeEvent(topic, eventType, payload)
+	if err != nil {
+		return err
+	}
+
+	// Continue the trace of the request that created the event, which may have
+	// ended long ago when the event went through the outbox
+	var event BaseEvent
+	json.Unmarshal(payload, &event)
+	span := startProducerSpan(eventType, topic, event.TraceParent, &headers)
 
 	// Create message
 	message := &sarama.ProducerMessage{
-		Topic: p.config.Topic,
-		Key:   sarama.StringEncoder(eventType),
-		Value: sarama.ByteEncoder(eventData),
-		Headers: []sarama.RecordHeader{
-			{
-				Key:   []byte("event-type"),
-				Value: []byte(eventType),
-			},
-			{
-				Key:   []byte("timestamp"),
-				Value: []byte(time.Now().UTC().Format(time.RFC3339)),
-			},
-		},
+		Topic:   topic,
+		Key:     sarama.StringEncoder(key),
+		Value:   sarama.ByteEncoder(value),
+		Headers: headers,
 	}
 
 	// Send message
+	start := time.Now()
 	partition, offset, err := p.producer.SendMessage(message)
+	observePublish(topic, eventType, start, err)
 	if err != nil {
-		return fmt.Errorf("failed to send message to Kafka: %w", err)
+		err = fmt.Errorf("failed to send message to Kafka: %w", err)
+		finishSpan(span, err)
+		return err
 	}
+	span.SetTag("messaging.kafka.partition", partition)
+	span.SetTag("messaging.kafka.offset", offset)
+	finishSpan(span, nil)
 
 	log.Printf("Event published successfully: type=%s, partition=%d, offset=%d", eventType, partition, offset)
 	return nil
 }
 
+// encodeEvent returns the headers and value of the message carrying a serialized event to a topic
+func (c *PublisherConfig) encodeEvent(topic string, eventType EventType, payload []byte) ([]sarama.RecordHeader, []byte, error) {
+	cloudEventHeaders, value, err := encodeMessage(c.codecFor(topic), c.CloudEventsMode, eventType, payload)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode %s event: %w", eventType, err)
+	}
+
+	headers := append([]sarama.RecordHeader{
+		{
+			Key:   []byte("event-type"),
+			Value: []byte(eventType),
+		},
+		{
+			Key:   []byte("timestamp"),
+			Value: []byte(time.Now().UTC().Format(time.RFC3339)),
+		},
+	}, cloudEventHeaders...)
+	return headers, value, nil
+}
+
 // Close closes the publisher
 func (p *kafkaPublisher) Close() error {
 	return p.producer.Close()
@@ -127,15 +200,15 @@ func NewPaymentEventPublisher(publisher EventPublisher) *PaymentEventPublisher {
 }
 
 // PublishPaymentCompleted publishes a payment completed event with basket clearing
-func (p *PaymentEventPublisher) PublishPaymentCompleted(ctx context.Context, paymentID string, userID uint, orderID string, amount float64, currency string, paymentMethod string, items []PaymentItem, basketID *string) error {
+func (p *PaymentEventPublisher) PublishPaymentCompleted(ctx context.Context, paymentID string, userID uint, orderID string, amount money.Money, paymentMethod string, items []PaymentItem, basketID *string) error {
 	event := PaymentCompletedEvent{
-		BaseEvent: NewBaseEvent(EventTypePaymentCompleted, "payment-service"),
+		BaseEvent: NewBaseEvent(ctx, EventTypePaymentCompleted, "payment-service", paymentID),
 		Data: PaymentCompletedData{
 			PaymentID:     paymentID,
 			UserID:        userID,
 			OrderID:       orderID,
-			Amount:        amount,
-			Currency:      currency,
+			AmountMinor:   amount.Amount,
+			Currency:      amount.Currency,
 			PaymentMethod: paymentMethod,
 			Items:         items,
 			BasketID:      basketID,
@@ -150,15 +223,15 @@ func (p *PaymentEventPublisher) PublishPaymentCompleted(ctx context.Context, pay
 }
 
 // PublishPaymentFailed publishes a payment failed event
-func (p *PaymentEventPublisher) PublishPaymentFailed(ctx context.Context, paymentID string, userID uint, orderID string, amount float64, currency string, paymentMethod string, reason string, basketID *string) error {
+func (p *PaymentEventPublisher) PublishPaymentFailed(ctx context.Context, paymentID string, userID uint, orderID string, amount money.Money, paymentMethod string, reason string, basketID *string) error {
 	event := PaymentFailedEvent{
-		BaseEvent: NewBaseEvent(EventTypePaymentFailed, "payment-service"),
+		BaseEvent: NewBaseEvent(ctx, EventTypePaymentFailed, "payment-service", paymentID),
 		Data: PaymentFailedData{
 			PaymentID:     paymentID,
 			UserID:        userID,
 			OrderID:       orderID,
-			Amount:        amount,
-			Currency:      currency,
+			AmountMinor:   amount.Amount,
+			Currency:      amount.Currency,
 			PaymentMethod: paymentMethod,
 			Reason:        reason,
 			BasketID:      basketID,
@@ -169,15 +242,15 @@ func (p *PaymentEventPublisher) PublishPaymentFailed(ctx context.Context, paymen
 }
 
 // PublishPaymentCancelled publishes a payment cancelled event
-func (p *PaymentEventPublisher) PublishPaymentCancelled(ctx context.Context, paymentID string, userID uint, orderID string, amount float64, currency string, paymentMethod string, reason string, basketID *string) error {
+func (p *PaymentEventPublisher) PublishPaymentCancelled(ctx context.Context, paymentID string, userID uint, orderID string, amount money.Money, paymentMethod string, reason string, basketID *string) error {
 	event := PaymentCancelledEvent{
-		BaseEvent: NewBaseEvent(EventTypePaymentCancelled, "payment-service"),
+		BaseEvent: NewBaseEvent(ctx, EventTypePaymentCancelled, "payment-service", paymentID),
 		Data: PaymentCancelledData{
 			PaymentID:     paymentID,
 			UserID:        userID,
 			OrderID:       orderID,
-			Amount:        amount,
-			Currency:      currency,
+			AmountMinor:   amount.Amount,
+			Currency:      amount.Currency,
 			PaymentMethod: paymentMethod,
 			Reason:        reason,
 			BasketID:      basketID,
@@ -187,12 +260,23 @@ func (p *PaymentEventPublisher) PublishPaymentCancelled(ctx context.Context, pay
 	return p.publisher.PublishPaymentCancelled(event)
 }
 
+// PublishPaymentRefunded publishes a payment refunded event
+func (p *PaymentEventPublisher) PublishPaymentRefunded(ctx context.Context, data PaymentRefundedData) error {
+	event := PaymentRefundedEvent{
+		BaseEvent: NewBaseEvent(ctx, EventTypePaymentRefunded, "payment-service", data.PaymentID),
+		Data:      data,
+	}
+
+	return p.publisher.PublishPaymentRefunded(event)
+}
+
 // PublishStockUpdated publishes a stock updated event
-func (p *PaymentEventPublisher) PublishStockUpdated(ctx context.Context, productID uint, quantity int, newStock int, reason string, orderID *string, paymentID *string) error {
+func (p *PaymentEventPublisher) PublishStockUpdated(ctx context.Context, productID uint, variantID *uint, quantity int, newStock int, reason string, orderID *string, paymentID *string) error {
 	event := StockUpdatedEvent{
-		BaseEvent: NewBaseEvent(EventTypeStockUpdated, "payment-service"),
+		BaseEvent: NewBaseEvent(ctx, EventTypeStockUpdated, "payment-service", strconv.FormatUint(uint64(productID), 10)),
 		Data: StockUpdatedData{
 			ProductID: productID,
+			VariantID: variantID,
 			Quantity:  quantity,
 			NewStock:  newStock,
 			Reason:    reason,
@@ -207,7 +291,7 @@ func (p *PaymentEventPublisher) PublishStockUpdated(ctx context.Context, product
 // PublishBasketCleared publishes a basket cleared event
 func (p *PaymentEventPublisher) PublishBasketCleared(ctx context.Context, userID uint, basketID string, items []PaymentItem, reason string, orderID *string, paymentID *string) error {
 	event := BasketClearedEvent{
-		BaseEvent: NewBaseEvent(EventTypeBasketCleared, "payment-service"),
+		BaseEvent: NewBaseEvent(ctx, EventTypeBasketCleared, "payment-service", basketID),
 		Data: BasketClearedData{
 			UserID:    userID,
 			BasketID:  basketID,
@@ -0,0 +1,163 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// handlerRegistry holds the event handlers of a consumer, shared by the Kafka and in-memory buses
+type handlerRegistry struct {
+	handlers map[EventType]Handler
+	// middleware wraps every handler, the first one outermost
+	middleware []Middleware
+}
+
+// newHandlerRegistry creates an empty handler registry
+func newHandlerRegistry() handlerRegistry {
+	return handlerRegistry{handlers: make(map[EventType]Handler)}
+}
+
+// ConsumePaymentCompleted registers a handler for payment completed events
+func (r *handlerRegistry) ConsumePaymentCompleted(handler func(context.Context, PaymentCompletedEvent) error) error {
+	r.handlers[EventTypePaymentCompleted] = func(ctx context.Context, data []byte) error {
+		var event PaymentCompletedEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal payment completed event: %w", err)
+		}
+		return handler(ctx, event)
+	}
+	return nil
+}
+
+// ConsumePaymentFailed registers a handler for payment failed events
+func (r *handlerRegistry) ConsumePaymentFailed(handler func(context.Context, PaymentFailedEvent) error) error {
+	r.handlers[EventTypePaymentFailed] = func(ctx context.Context, data []byte) error {
+		var event PaymentFailedEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal payment failed event: %w", err)
+		}
+		return handler(ctx, event)
+	}
+	return nil
+}
+
+// ConsumePaymentCancelled registers a handler for payment cancelled events
+func (r *handlerRegistry) ConsumePaymentCancelled(handler func(context.Context, PaymentCancelledEvent) error) error {
+	r.handlers[EventTypePaymentCancelled] = func(ctx context.Context, data []byte) error {
+		var event PaymentCancelledEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal payment cancelled event: %w", err)
+		}
+		return handler(ctx, event)
+	}
+	return nil
+}
+
+// ConsumePaymentRefunded registers a handler for payment refunded events
+func (r *handlerRegistry) ConsumePaymentRefunded(handler func(context.Context, PaymentRefundedEvent) error) error {
+	r.handlers[EventTypePaymentRefunded] = func(ctx context.Context, data []byte) error {
+		var event PaymentRefundedEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal payment refunded event: %w", err)
+		}
+		return handler(ctx, event)
+	}
+	return nil
+}
+
+// ConsumeStockUpdated registers a handler for stock updated events
+func (r *handlerRegistry) ConsumeStockUpdated(handler func(context.Context, StockUpdatedEvent) error) error {
+	r.handlers[EventTypeStockUpdated] = func(ctx context.Context, data []byte) error {
+		var event StockUpdatedEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal stock updated event: %w", err)
+		}
+		return handler(ctx, event)
+	}
+	return nil
+}
+
+// ConsumeBasketCleared registers a handler for basket cleared events
+func (r *handlerRegistry) ConsumeBasketCleared(handler func(context.Context, BasketClearedEvent) error) error {
+	r.handlers[EventTypeBasketCleared] = func(ctx context.Context, data []byte) error {
+		var event BasketClearedEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal basket cleared event: %w", err)
+		}
+		return handler(ctx, event)
+	}
+	return nil
+}
+
+// ConsumeOrderCreated registers a handler for order created events
+func (r *handlerRegistry) ConsumeOrderCreated(handler func(context.Context, OrderCreatedEvent) error) error {
+	r.handlers[EventTypeOrderCreated] = func(ctx context.Context, data []byte) error {
+		var event OrderCreatedEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal order created event: %w", err)
+		}
+		return handler(ctx, event)
+	}
+	return nil
+}
+
+// ConsumeOrderCancelled registers a handler for order cancelled events
+func (r *handlerRegistry) ConsumeOrderCancelled(handler func(context.Context, OrderCancelledEvent) error) error {
+	r.handlers[EventTypeOrderCancelled] = func(ctx context.Context, data []byte) error {
+		var event OrderCancelledEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal order cancelled event: %w", err)
+		}
+		return handler(ctx, event)
+	}
+	return nil
+}
+
+// ConsumeOrderPaymentRejected registers a handler for order payment rejected events
+func (r *handlerRegistry) ConsumeOrderPaymentRejected(handler func(context.Context, OrderPaymentRejectedEvent) error) error {
+	r.handlers[EventTypeOrderPaymentRejected] = func(ctx context.Context, data []byte) error {
+		var event OrderPaymentRejectedEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return fmt.Errorf("failed to unmarshal order payment rejected event: %w", err)
+		}
+		return handler(ctx, event)
+	}
+	return nil
+}
+
+// Use adds middleware wrapping every registered handler
+func (r *handlerRegistry) Use(middleware ...Middleware) {
+	r.middleware = append(r.middleware, middleware...)
+}
+
+// wrapHandlers wraps every registered handler in the middleware; call it once on Start
+func (r *handlerRegistry) wrapHandlers() error {
+	if len(r.handlers) == 0 {
+		return fmt.Errorf("no event handlers registered")
+	}
+	for eventType, handler := range r.handlers {
+		r.handlers[eventType] = chain(handler, r.middleware)
+	}
+	return nil
+}
+
+// topics returns the topics of the registered handlers followed by the retry topics,
+// so the consumer never reads events it has no handler for
+func (r *handlerRegistry) topics(routes TopicRoutes, stages []retryStage) []string {
+	seen := make(map[string]bool)
+	var topics []string
+	for eventType := range r.handlers {
+		if topic := routes.Topic(eventType); !seen[topic] {
+			seen[topic] = true
+			topics = append(topics, topic)
+		}
+	}
+	sort.Strings(topics)
+
+	for _, stage := range stages[1:] {
+		topics = append(topics, stage.topic)
+	}
+	return topics
+}
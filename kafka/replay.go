@@ -0,0 +1,333 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// HeaderReplayedFrom marks a replayed event with the position it was read from
+const HeaderReplayedFrom = "x-replayed-from"
+
+// Record is an event read from a topic, as exported to and replayed from JSONL files
+type Record struct {
+	Topic       string          `json:"topic"`
+	Partition   int32           `json:"partition"`
+	Offset      int64           `json:"offset"`
+	Timestamp   time.Time       `json:"timestamp"`
+	Key         string          `json:"key,omitempty"`
+	EventType   EventType       `json:"event_type"`
+	EventID     string          `json:"event_id,omitempty"`
+	AggregateID string          `json:"aggregate_id,omitempty"`
+	Event       json.RawMessage `json:"event,omitempty"`
+	// DecodeError explains why Event is missing
+	DecodeError string `json:"decode_error,omitempty"`
+}
+
+// RecordFilter selects records by position, time, event type and aggregate; zero values match everything
+type RecordFilter struct {
+	// Partition limits reading to one partition; -1 reads all partitions
+	Partition  int32
+	FromOffset int64
+	// ToOffset is inclusive; 0 reads up to the end of the partition
+	ToOffset int64
+	// Since and Until select records by message timestamp, Until exclusive
+	Since       time.Time
+	Until       time.Time
+	EventType   EventType
+	AggregateID string
+	// Limit caps the number of records returned; 0 returns all
+	Limit int
+}
+
+// Match reports whether a record passes the event type, aggregate and time filters
+func (f RecordFilter) Match(record Record) bool {
+	switch {
+	case f.Partition >= 0 && record.Partition != f.Partition:
+		return false
+	case f.EventType != "" && record.EventType != f.EventType:
+		return false
+	case f.AggregateID != "" && record.AggregateID != f.AggregateID:
+		return false
+	case !f.Since.IsZero() && record.Timestamp.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !record.Timestamp.Before(f.Until):
+		return false
+	}
+	return true
+}
+
+// NewRecord decodes a consumed message, whichever codec and CloudEvents mode it was sent with
+func NewRecord(message *sarama.ConsumerMessage) Record {
+	record := Record{
+		Topic:     message.Topic,
+		Partition: message.Partition,
+		Offset:    message.Offset,
+		Timestamp: message.Timestamp,
+		Key:       string(message.Key),
+		EventType: messageEventType(message.Headers, message.Value),
+	}
+
+	event, err := decodeMessage(message.Headers, message.Value)
+	if err != nil {
+		record.DecodeError = err.Error()
+		return record
+	}
+	var base BaseEvent
+	if err := json.Unmarshal(event, &base); err != nil {
+		record.DecodeError = err.Error()
+		return record
+	}
+	record.Event = event
+	record.EventID = base.ID
+	record.AggregateID = base.Subject
+	return record
+}
+
+// Position returns where a record was read, as topic/partition/offset
+func (r Record) Position() string {
+	return fmt.Sprintf("%s/%d/%d", r.Topic, r.Partition, r.Offset)
+}
+
+// ReadRecords reads the matching records currently in a topic
+func ReadRecords(client sarama.Client, topic string, filter RecordFilter) ([]Record, error) {
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consumer: %w", err)
+	}
+	defer consumer.Close()
+
+	partitions, err := consumer.Partitions(topic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partitions of %s: %w", topic, err)
+	}
+
+	var records []Record
+	for _, partition := range partitions {
+		if filter.Partition >= 0 && partition != filter.Partition {
+			continue
+		}
+
+		read, err := readRecords(client, consumer, topic, partition, filter, filter.Limit-len(records))
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, read...)
+		if filter.Limit > 0 && len(records) >= filter.Limit {
+			break
+		}
+	}
+	return records, nil
+}
+
+// readRecords reads the matching records of one partition up to its current end
+func readRecords(client sarama.Client, consumer sarama.Consumer, topic string, partition int32, filter RecordFilter, limit int) ([]Record, error) {
+	oldest, err := client.GetOffset(topic, partition, sarama.OffsetOldest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get oldest offset of %s/%d: %w", topic, partition, err)
+	}
+	newest, err := client.GetOffset(topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get newest offset of %s/%d: %w", topic, partition, err)
+	}
+
+	from := max(oldest, filter.FromOffset)
+	to := newest - 1
+	if filter.ToOffset > 0 {
+		to = min(to, filter.ToOffset)
+	}
+
+	// Skip the messages outside the time range without reading them
+	if !filter.Since.IsZero() {
+		offset, err := offsetForTime(client, topic, partition, filter.Since, newest)
+		if err != nil {
+			return nil, err
+		}
+		from = max(from, offset)
+	}
+	if !filter.Until.IsZero() {
+		offset, err := offsetForTime(client, topic, partition, filter.Until, newest)
+		if err != nil {
+			return nil, err
+		}
+		to = min(to, offset-1)
+	}
+	if from > to {
+		return nil, nil
+	}
+
+	partitionConsumer, err := consumer.ConsumePartition(topic, partition, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume %s/%d: %w", topic, partition, err)
+	}
+	defer partitionConsumer.Close()
+
+	var records []Record
+	for message := range partitionConsumer.Messages() {
+		if record := NewRecord(message); filter.Match(record) {
+			records = append(records, record)
+		}
+		if message.Offset >= to || (limit > 0 && len(records) >= limit) {
+			break
+		}
+	}
+	return records, nil
+}
+
+// offsetForTime returns the first offset of a partition with a timestamp at or after t,
+// or newest when there is none
+func offsetForTime(client sarama.Client, topic string, partition int32, t time.Time, newest int64) (int64, error) {
+	offset, err := client.GetOffset(topic, partition, t.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("failed to get offset of %s/%d at %s: %w", topic, partition, t.Format(time.RFC3339), err)
+	}
+	if offset < 0 {
+		return newest, nil
+	}
+	return offset, nil
+}
+
+// TailRecords passes the matching records of topics to fn as they arrive, until ctx ends or fn
+// fails; it starts at the end of every partition, or at the beginning when fromOldest is set
+func TailRecords(ctx context.Context, client sarama.Client, topics []string, filter RecordFilter, fromOldest bool, fn func(Record) error) error {
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		return fmt.Errorf("failed to create consumer: %w", err)
+	}
+	defer consumer.Close()
+
+	offset := sarama.OffsetNewest
+	if fromOldest {
+		offset = sarama.OffsetOldest
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var partitionConsumers []sarama.PartitionConsumer
+	defer func() {
+		for _, partitionConsumer := range partitionConsumers {
+			partitionConsumer.AsyncClose()
+		}
+		wg.Wait()
+	}()
+
+	messages := make(chan *sarama.ConsumerMessage)
+	for _, topic := range topics {
+		partitions, err := consumer.Partitions(topic)
+		if err != nil {
+			return fmt.Errorf("failed to list partitions of %s: %w", topic, err)
+		}
+		for _, partition := range partitions {
+			if filter.Partition >= 0 && partition != filter.Partition {
+				continue
+			}
+
+			partitionConsumer, err := consumer.ConsumePartition(topic, partition, offset)
+			if err != nil {
+				return fmt.Errorf("failed to consume %s/%d: %w", topic, partition, err)
+			}
+			partitionConsumers = append(partitionConsumers, partitionConsumer)
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for message := range partitionConsumer.Messages() {
+					select {
+					case messages <- message:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case message := <-messages:
+			if record := NewRecord(message); filter.Match(record) {
+				if err := fn(record); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// ReplayTarget selects where replayed events are sent; the zero value sends every event
+// to the topic of its type, so every consumer group handles it again
+type ReplayTarget struct {
+	// Topic sends all events to one topic
+	Topic string
+	// Group sends the events to the retry topic of a consumer group with RetryDelay,
+	// so only that group handles them again, without waiting for the delay
+	Group      string
+	RetryDelay time.Duration
+	// NewIDs gives the events new IDs, so consumers do not skip them as already handled
+	NewIDs bool
+}
+
+// TopicOf returns the topic a record is replayed to
+func (t ReplayTarget) TopicOf(routes TopicRoutes, record Record) string {
+	switch {
+	case t.Group != "":
+		return RetryTopicName(t.Group, t.RetryDelay)
+	case t.Topic != "":
+		return t.Topic
+	default:
+		return routes.Topic(record.EventType)
+	}
+}
+
+// Replay publishes the event of a record again, encoded with the codec of the target topic
+func Replay(producer sarama.SyncProducer, config *PublisherConfig, record Record, target ReplayTarget) error {
+	if len(record.Event) == 0 {
+		return fmt.Errorf("record %s has no decodable event: %s", record.Position(), record.DecodeError)
+	}
+
+	payload := []byte(record.Event)
+	if target.NewIDs {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(payload, &fields); err != nil {
+			return fmt.Errorf("failed to decode event of record %s: %w", record.Position(), err)
+		}
+		fields["id"], _ = json.Marshal(generateEventID())
+		payload, _ = json.Marshal(fields)
+	}
+
+	topic := target.TopicOf(config.TopicRoutes, record)
+	headers, value, err := config.encodeEvent(topic, record.EventType, payload)
+	if err != nil {
+		return err
+	}
+	headers = append(headers, sarama.RecordHeader{Key: []byte(HeaderReplayedFrom), Value: []byte(record.Position())})
+	if target.Group != "" {
+		headers = append(headers, sarama.RecordHeader{Key: []byte(HeaderRetryAt), Value: []byte(time.Now().UTC().Format(time.RFC3339Nano))})
+	}
+
+	key := record.Key
+	if key == "" {
+		key = record.AggregateID
+	}
+	message := &sarama.ProducerMessage{
+		Topic:   topic,
+		Value:   sarama.ByteEncoder(value),
+		Headers: headers,
+	}
+	if key != "" {
+		message.Key = sarama.StringEncoder(key)
+	}
+
+	if _, _, err := producer.SendMessage(message); err != nil {
+		return fmt.Errorf("failed to replay %s to %s: %w", record.Position(), topic, err)
+	}
+	return nil
+}
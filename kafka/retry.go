@@ -0,0 +1,167 @@
+package kafka
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// Headers carrying the failure metadata of messages in retry and dead-letter topics
+const (
+	HeaderError             = "x-error"
+	HeaderAttempts          = "x-attempts"
+	HeaderOriginalTopic     = "x-original-topic"
+	HeaderOriginalPartition = "x-original-partition"
+	HeaderOriginalOffset    = "x-original-offset"
+	HeaderFailedAt          = "x-failed-at"
+	HeaderRetryAt           = "x-retry-at"
+)
+
+// failureHeaders are replaced whenever a message moves to the next retry stage
+var failureHeaders = map[string]bool{
+	HeaderError:             true,
+	HeaderAttempts:          true,
+	HeaderOriginalTopic:     true,
+	HeaderOriginalPartition: true,
+	HeaderOriginalOffset:    true,
+	HeaderFailedAt:          true,
+	HeaderRetryAt:           true,
+}
+
+// RetryTopicName returns the name of a consumer group's retry topic with the given delay,
+// e.g. product-service.retry.10m; retries of one group never reach other groups
+func RetryTopicName(group string, delay time.Duration) string {
+	return group + ".retry." + formatDelay(delay)
+}
+
+// DLQTopicName returns the name of the dead-letter topic of a consumer group
+func DLQTopicName(group string) string {
+	return group + ".dlq"
+}
+
+// formatDelay formats a delay in its largest whole unit, e.g. 1m instead of 1m0s
+func formatDelay(delay time.Duration) string {
+	switch {
+	case delay >= time.Hour && delay%time.Hour == 0:
+		return strconv.FormatInt(int64(delay/time.Hour), 10) + "h"
+	case delay >= time.Minute && delay%time.Minute == 0:
+		return strconv.FormatInt(int64(delay/time.Minute), 10) + "m"
+	case delay%time.Second == 0:
+		return strconv.FormatInt(int64(delay/time.Second), 10) + "s"
+	default:
+		return delay.String()
+	}
+}
+
+// retryStage is a retry topic the consumer reads; stage 0 stands for the event topics
+type retryStage struct {
+	topic string
+	delay time.Duration
+}
+
+// retryStages returns the event topics stage followed by the retry topics of the group
+func (c *ConsumerConfig) retryStages() []retryStage {
+	stages := []retryStage{{}}
+	for _, delay := range c.RetryTopicDelays {
+		stages = append(stages, retryStage{topic: RetryTopicName(c.GroupID, delay), delay: delay})
+	}
+	return stages
+}
+
+// backoff returns the delay before the given in-process retry attempt (1-based):
+// exponential from RetryDelay, capped at MaxRetryDelay, with up to half of it as jitter
+func (c *ConsumerConfig) backoff(attempt int) time.Duration {
+	delay := c.RetryDelay << min(attempt-1, 20)
+	if c.MaxRetryDelay > 0 && (delay <= 0 || delay > c.MaxRetryDelay) {
+		delay = c.MaxRetryDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// failedMessage builds the message sent to the next retry stage or the dead-letter topic
+func failedMessage(message *sarama.ConsumerMessage, topic string, attempts int, handlerErr error, retryAt time.Time) *sarama.ProducerMessage {
+	var headers []sarama.RecordHeader
+	originalTopic := message.Topic
+	originalPartition := strconv.FormatInt(int64(message.Partition), 10)
+	originalOffset := strconv.FormatInt(message.Offset, 10)
+
+	for _, header := range message.Headers {
+		key := string(header.Key)
+		if !failureHeaders[key] {
+			headers = append(headers, *header)
+			continue
+		}
+
+		// Keep pointing at the message as it was first consumed, and count all attempts
+		switch key {
+		case HeaderOriginalTopic:
+			originalTopic = string(header.Value)
+		case HeaderOriginalPartition:
+			originalPartition = string(header.Value)
+		case HeaderOriginalOffset:
+			originalOffset = string(header.Value)
+		case HeaderAttempts:
+			if previous, err := strconv.Atoi(string(header.Value)); err == nil {
+				attempts += previous
+			}
+		}
+	}
+
+	headers = append(headers,
+		sarama.RecordHeader{Key: []byte(HeaderError), Value: []byte(handlerErr.Error())},
+		sarama.RecordHeader{Key: []byte(HeaderAttempts), Value: []byte(strconv.Itoa(attempts))},
+		sarama.RecordHeader{Key: []byte(HeaderOriginalTopic), Value: []byte(originalTopic)},
+		sarama.RecordHeader{Key: []byte(HeaderOriginalPartition), Value: []byte(originalPartition)},
+		sarama.RecordHeader{Key: []byte(HeaderOriginalOffset), Value: []byte(originalOffset)},
+		sarama.RecordHeader{Key: []byte(HeaderFailedAt), Value: []byte(time.Now().UTC().Format(time.RFC3339))},
+	)
+	if !retryAt.IsZero() {
+		headers = append(headers, sarama.RecordHeader{Key: []byte(HeaderRetryAt), Value: []byte(retryAt.UTC().Format(time.RFC3339Nano))})
+	}
+
+	producerMessage := &sarama.ProducerMessage{
+		Topic:   topic,
+		Value:   sarama.ByteEncoder(message.Value),
+		Headers: headers,
+	}
+	if message.Key != nil {
+		producerMessage.Key = sarama.ByteEncoder(message.Key)
+	}
+	return producerMessage
+}
+
+// headerValue returns the value of a message header, or "" when it is missing
+func headerValue(headers []*sarama.RecordHeader, key string) string {
+	for _, header := range headers {
+		if string(header.Key) == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+// parseDelays parses a comma separated list of durations such as "1m,10m"
+func parseDelays(value string) ([]time.Duration, error) {
+	var delays []time.Duration
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		delay, err := time.ParseDuration(part)
+		if err != nil || delay <= 0 {
+			return nil, fmt.Errorf("invalid retry topic delay %q", part)
+		}
+		delays = append(delays, delay)
+	}
+	return delays, nil
+}
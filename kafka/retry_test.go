@@ -0,0 +1,198 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// busProducer sends the messages of Redrive to a memory bus
+type busProducer struct {
+	sarama.SyncProducer
+	bus *MemoryBus
+}
+
+func (p *busProducer) SendMessage(message *sarama.ProducerMessage) (int32, int64, error) {
+	return 0, 0, p.bus.send(message)
+}
+
+func TestConsumerMovesFailingMessageThroughRetryStagesToDLQ(t *testing.T) {
+	const group = "payment-service"
+	retryDelays := []time.Duration{20 * time.Millisecond, 40 * time.Millisecond}
+	eventTopic := DefaultTopicRoutes.Topic(EventTypeStockUpdated)
+
+	bus := NewMemoryBus(1)
+	t.Cleanup(bus.Close)
+	publisher := NewMemoryPublisher(bus, &PublisherConfig{})
+
+	var mu sync.Mutex
+	var calls []time.Time
+	failing := true
+	handled := 0
+	startMemoryConsumer(t, bus, &ConsumerConfig{
+		GroupID:          group,
+		RetryAttempts:    2,
+		RetryDelay:       10 * time.Millisecond,
+		RetryTopicDelays: retryDelays,
+		DLQEnabled:       true,
+	}, func(ctx context.Context, event StockUpdatedEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if failing {
+			calls = append(calls, time.Now())
+			return errors.New("handler failed")
+		}
+		handled++
+		return nil
+	})
+
+	// Another group handles the same event without failing
+	startMemoryConsumer(t, bus, &ConsumerConfig{
+		GroupID:          "order-service",
+		RetryTopicDelays: retryDelays,
+		DLQEnabled:       true,
+	}, func(ctx context.Context, event StockUpdatedEvent) error {
+		return nil
+	})
+
+	publishStockUpdated(t, publisher, 1, 1)
+	waitForBus(t, bus)
+
+	mu.Lock()
+	// Two in-process attempts on the event topic and on each retry topic
+	if len(calls) != 6 {
+		t.Fatalf("handler ran %d times, want 6", len(calls))
+	}
+	// In-process retries wait at least half the backoff, retry topics their delay
+	waits := []time.Duration{5 * time.Millisecond, retryDelays[0], 5 * time.Millisecond, retryDelays[1], 5 * time.Millisecond}
+	for i, want := range waits {
+		if got := calls[i+1].Sub(calls[i]); got < want {
+			t.Errorf("attempt %d ran %v after the previous one, want at least %v", i+2, got, want)
+		}
+	}
+	mu.Unlock()
+
+	for stage, delay := range retryDelays {
+		topic := RetryTopicName(group, delay)
+		messages := bus.Messages(topic)
+		if len(messages) != 1 {
+			t.Fatalf("%s holds %d messages, want 1", topic, len(messages))
+		}
+		headers := messages[0].Headers
+		if got, want := headerValue(headers, HeaderAttempts), strconv.Itoa(2*(stage+1)); got != want {
+			t.Errorf("%s attempts = %s, want %s", topic, got, want)
+		}
+		if got := headerValue(headers, HeaderOriginalTopic); got != eventTopic {
+			t.Errorf("%s original topic = %q, want %q", topic, got, eventTopic)
+		}
+		if headerValue(headers, HeaderRetryAt) == "" {
+			t.Errorf("%s message has no retry time", topic)
+		}
+	}
+
+	dlq := bus.Messages(DLQTopicName(group))
+	if len(dlq) != 1 {
+		t.Fatalf("dead-letter topic holds %d messages, want 1", len(dlq))
+	}
+	letter := newDeadLetter(dlq[0])
+	if letter.Attempts != 6 || letter.OriginalTopic != eventTopic || letter.OriginalOffset != 0 ||
+		letter.EventType != EventTypeStockUpdated || letter.Key != "1" || !strings.Contains(letter.Error, "handler failed") {
+		t.Errorf("dead letter = %+v", letter)
+	}
+	if letter.Headers[HeaderRetryAt] != "" {
+		t.Error("dead letter still carries the retry time of its last retry topic")
+	}
+
+	// Retries stay within the group that failed
+	for _, delay := range retryDelays {
+		if got := len(bus.Messages(RetryTopicName("order-service", delay))); got != 0 {
+			t.Errorf("order-service retry topic holds %d messages, want none", got)
+		}
+	}
+	if got := len(bus.Messages(DLQTopicName("order-service"))); got != 0 {
+		t.Errorf("order-service dead-letter topic holds %d messages, want none", got)
+	}
+
+	// Once the handler is fixed, the re-driven dead letter is handled without its failure history
+	mu.Lock()
+	failing = false
+	mu.Unlock()
+	if err := Redrive(&busProducer{bus: bus}, letter); err != nil {
+		t.Fatalf("Redrive() error = %v", err)
+	}
+	waitForBus(t, bus)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if handled != 1 {
+		t.Fatalf("re-driven message handled %d times, want 1", handled)
+	}
+	redriven := bus.Messages(eventTopic)[1]
+	if headerValue(redriven.Headers, HeaderAttempts) != "" || headerValue(redriven.Headers, HeaderError) != "" {
+		t.Errorf("re-driven message kept its failure headers")
+	}
+	if got, want := headerValue(redriven.Headers, HeaderRedrivenFrom), DLQTopicName(group)+"/0/0"; got != want {
+		t.Errorf("re-driven from %q, want %q", got, want)
+	}
+
+	event, err := letter.Event()
+	if err != nil {
+		t.Fatalf("Event() error = %v", err)
+	}
+	var decoded StockUpdatedEvent
+	if err := json.Unmarshal(event, &decoded); err != nil || decoded.Data.ProductID != 1 {
+		t.Errorf("dead letter event = %s, want the stock update of product 1", event)
+	}
+}
+
+func TestConsumerConfigBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ConsumerConfig
+		attempt int
+		want    time.Duration
+	}{
+		{name: "first retry", config: ConsumerConfig{RetryDelay: time.Second}, attempt: 1, want: time.Second},
+		{name: "doubles per attempt", config: ConsumerConfig{RetryDelay: time.Second}, attempt: 3, want: 4 * time.Second},
+		{name: "capped", config: ConsumerConfig{RetryDelay: time.Second, MaxRetryDelay: 3 * time.Second}, attempt: 3, want: 3 * time.Second},
+		{name: "overflow capped", config: ConsumerConfig{RetryDelay: time.Second, MaxRetryDelay: time.Minute}, attempt: 100, want: time.Minute},
+		{name: "no delay", config: ConsumerConfig{}, attempt: 1, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The jitter keeps the delay between half and all of the backoff
+			for range 20 {
+				got := tt.config.backoff(tt.attempt)
+				if got < tt.want/2 || got > tt.want {
+					t.Fatalf("backoff(%d) = %v, want between %v and %v", tt.attempt, got, tt.want/2, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryTopicName(t *testing.T) {
+	tests := []struct {
+		delay time.Duration
+		want  string
+	}{
+		{delay: 10 * time.Minute, want: "product-service.retry.10m"},
+		{delay: 2 * time.Hour, want: "product-service.retry.2h"},
+		{delay: 90 * time.Second, want: "product-service.retry.90s"},
+		{delay: 1500 * time.Millisecond, want: "product-service.retry.1.5s"},
+	}
+
+	for _, tt := range tests {
+		if got := RetryTopicName("product-service", tt.delay); got != tt.want {
+			t.Errorf("RetryTopicName(%v) = %q, want %q", tt.delay, got, tt.want)
+		}
+	}
+}
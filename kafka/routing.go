@@ -0,0 +1,66 @@
+package kafka
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TopicRoutes maps event types to the topics they are published to
+type TopicRoutes map[EventType]string
+
+// DefaultTopicRoutes keeps the events of one aggregate on one topic, so consumers see
+// them in the order they happened; KAFKA_TOPIC_ROUTES overrides single event types
+var DefaultTopicRoutes = TopicRoutes{
+	EventTypePaymentCompleted:     "payment-events",
+	EventTypePaymentFailed:        "payment-events",
+	EventTypePaymentCancelled:     "payment-events",
+	EventTypePaymentRefunded:      "payment-events",
+	EventTypeStockUpdated:         "stock-events",
+	EventTypeBasketCleared:        "basket-events",
+	EventTypeOrderCreated:         "order-events",
+	EventTypeOrderCancelled:       "order-events",
+	EventTypeOrderPaymentRejected: "order-events",
+}
+
+// Topic returns the topic of an event type; unrouted event types get a topic of their own
+func (r TopicRoutes) Topic(eventType EventType) string {
+	if topic, exists := r[eventType]; exists {
+		return topic
+	}
+	if topic, exists := DefaultTopicRoutes[eventType]; exists {
+		return topic
+	}
+	return string(eventType)
+}
+
+// Topics returns the topics of all event types, sorted
+func (r TopicRoutes) Topics() []string {
+	seen := make(map[string]bool)
+	var topics []string
+	for eventType := range eventData {
+		if topic := r.Topic(eventType); !seen[topic] {
+			seen[topic] = true
+			topics = append(topics, topic)
+		}
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+// parseTopicRoutes parses routes such as "stock.updated=inventory-events,order.created=orders"
+func parseTopicRoutes(value string) (TopicRoutes, error) {
+	routes := make(TopicRoutes)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		eventType, topic, found := strings.Cut(part, "=")
+		if !found || strings.TrimSpace(topic) == "" {
+			return nil, fmt.Errorf("invalid topic route %q", part)
+		}
+		routes[EventType(strings.TrimSpace(eventType))] = strings.TrimSpace(topic)
+	}
+	return routes, nil
+}
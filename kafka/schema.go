@@ -0,0 +1,473 @@
+package kafka
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// schemaFiles holds the committed JSON Schema of the data of every event type and
+// version, as schemas/<event type>/<version>.json
+//
+//go:embed schemas
+var schemaFiles embed.FS
+
+// eventData maps every event type to the data struct of its latest schema version
+var eventData = map[EventType]interface{}{
+	EventTypePaymentCompleted:     PaymentCompletedData{},
+	EventTypePaymentFailed:        PaymentFailedData{},
+	EventTypePaymentCancelled:     PaymentCancelledData{},
+	EventTypePaymentRefunded:      PaymentRefundedData{},
+	EventTypeStockUpdated:         StockUpdatedData{},
+	EventTypeBasketCleared:        BasketClearedData{},
+	EventTypeOrderCreated:         OrderCreatedData{},
+	EventTypeOrderCancelled:       OrderCancelledData{},
+	EventTypeOrderPaymentRejected: OrderPaymentRejectedData{},
+}
+
+// JSONSchema is the subset of JSON Schema used to describe event data
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Type                 SchemaTypes            `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+}
+
+// SchemaTypes is the JSON Schema type keyword, a single type or a list of types
+type SchemaTypes []string
+
+// MarshalJSON writes a single type as a string
+func (t SchemaTypes) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON reads a single type or a list of types
+func (t *SchemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = SchemaTypes{single}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+// has reports whether a value of the type is allowed
+func (t SchemaTypes) has(name string) bool {
+	for _, allowed := range t {
+		if allowed == name || (allowed == "number" && name == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// schemaRegistry holds the committed schemas by event type and version
+type schemaRegistry struct {
+	schemas  map[EventType]map[string]*JSONSchema
+	versions map[EventType][]string
+}
+
+var (
+	registry     *schemaRegistry
+	registryErr  error
+	registryOnce sync.Once
+)
+
+// loadRegistry loads the committed schemas once
+func loadRegistry() (*schemaRegistry, error) {
+	registryOnce.Do(func() {
+		registry, registryErr = readRegistry()
+	})
+	return registry, registryErr
+}
+
+// readRegistry reads the embedded schema files
+func readRegistry() (*schemaRegistry, error) {
+	r := &schemaRegistry{
+		schemas:  make(map[EventType]map[string]*JSONSchema),
+		versions: make(map[EventType][]string),
+	}
+
+	types, err := schemaFiles.ReadDir("schemas")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event schemas: %w", err)
+	}
+	for _, dir := range types {
+		eventType := EventType(dir.Name())
+		files, err := schemaFiles.ReadDir(path.Join("schemas", dir.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s schemas: %w", eventType, err)
+		}
+
+		r.schemas[eventType] = make(map[string]*JSONSchema)
+		for _, file := range files {
+			version := strings.TrimSuffix(file.Name(), ".json")
+			data, err := schemaFiles.ReadFile(path.Join("schemas", dir.Name(), file.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s schema %s: %w", eventType, version, err)
+			}
+
+			var schema JSONSchema
+			if err := json.Unmarshal(data, &schema); err != nil {
+				return nil, fmt.Errorf("invalid %s schema %s: %w", eventType, version, err)
+			}
+			r.schemas[eventType][version] = &schema
+			r.versions[eventType] = append(r.versions[eventType], version)
+		}
+		sort.Slice(r.versions[eventType], func(i, j int) bool {
+			return compareVersions(r.versions[eventType][i], r.versions[eventType][j]) < 0
+		})
+	}
+	return r, nil
+}
+
+// LatestSchemaVersion returns the latest committed schema version of an event type
+func LatestSchemaVersion(eventType EventType) string {
+	r, err := loadRegistry()
+	if err != nil || len(r.versions[eventType]) == 0 {
+		return "1.0"
+	}
+	versions := r.versions[eventType]
+	return versions[len(versions)-1]
+}
+
+// SchemaVersions returns the committed schema versions of an event type, oldest first
+func SchemaVersions(eventType EventType) []string {
+	r, err := loadRegistry()
+	if err != nil {
+		return nil
+	}
+	return append([]string(nil), r.versions[eventType]...)
+}
+
+// ValidateEvent checks the data of a serialized event against the schema of its type and version
+func ValidateEvent(payload []byte) error {
+	r, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+
+	var event struct {
+		Type    EventType       `json:"type"`
+		Version string          `json:"version"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	schema, exists := r.schemas[event.Type][event.Version]
+	if !exists {
+		return fmt.Errorf("no schema registered for %s version %s", event.Type, event.Version)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(event.Data))
+	decoder.UseNumber()
+	var data interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return fmt.Errorf("failed to unmarshal %s data: %w", event.Type, err)
+	}
+
+	if err := schema.validate("data", data); err != nil {
+		return fmt.Errorf("%s event does not match schema %s: %w", event.Type, event.Version, err)
+	}
+	return nil
+}
+
+// validate checks a decoded JSON value against the schema
+func (s *JSONSchema) validate(at string, value interface{}) error {
+	if len(s.Type) > 0 && !s.Type.has(jsonType(value)) {
+		return fmt.Errorf("%s: expected %s, got %s", at, strings.Join(s.Type, " or "), jsonType(value))
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, exists := value[name]; !exists {
+				return fmt.Errorf("%s: missing required property %q", at, name)
+			}
+		}
+		for name, property := range value {
+			schema, exists := s.Properties[name]
+			if !exists {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return fmt.Errorf("%s: unknown property %q", at, name)
+				}
+				continue
+			}
+			if err := schema.validate(at+"."+name, property); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range value {
+				if err := s.Items.validate(fmt.Sprintf("%s[%d]", at, i), item); err != nil {
+					return err
+				}
+			}
+		}
+	case string:
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, value); err != nil {
+				return fmt.Errorf("%s: invalid date-time %q", at, value)
+			}
+		}
+	}
+	return nil
+}
+
+// jsonType returns the JSON Schema type of a value decoded with UseNumber
+func jsonType(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := value.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// GenerateSchema returns the JSON Schema of the latest data struct of an event type
+func GenerateSchema(eventType EventType) (*JSONSchema, error) {
+	data, exists := eventData[eventType]
+	if !exists {
+		return nil, fmt.Errorf("unknown event type %s", eventType)
+	}
+
+	schema := schemaOf(reflect.TypeOf(data))
+	schema.Schema = "https://json-schema.org/draft/2020-12/schema"
+	schema.Title = string(eventType)
+	return schema, nil
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaOf derives the schema of a Go type from its JSON encoding
+func schemaOf(t reflect.Type) *JSONSchema {
+	switch {
+	case t == timeType:
+		return &JSONSchema{Type: SchemaTypes{"string"}, Format: "date-time"}
+	case t.Kind() == reflect.Ptr:
+		schema := schemaOf(t.Elem())
+		schema.Type = append(schema.Type, "null")
+		return schema
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		closed := false
+		schema := &JSONSchema{
+			Type:                 SchemaTypes{"object"},
+			Properties:           make(map[string]*JSONSchema),
+			AdditionalProperties: &closed,
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+
+			schema.Properties[name] = schemaOf(field.Type)
+			if !strings.Contains(options, "omitempty") && field.Type.Kind() != reflect.Ptr {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+		sort.Strings(schema.Required)
+		return schema
+	case reflect.Slice, reflect.Array:
+		return &JSONSchema{Type: SchemaTypes{"array", "null"}, Items: schemaOf(t.Elem())}
+	case reflect.Map:
+		return &JSONSchema{Type: SchemaTypes{"object", "null"}}
+	case reflect.String:
+		return &JSONSchema{Type: SchemaTypes{"string"}}
+	case reflect.Bool:
+		return &JSONSchema{Type: SchemaTypes{"boolean"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: SchemaTypes{"integer"}}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: SchemaTypes{"number"}}
+	default:
+		return &JSONSchema{}
+	}
+}
+
+// CheckSchemaCompatibility reports every event type whose data struct no longer matches
+// its latest committed schema, and every incompatible schema change without an upcaster
+func CheckSchemaCompatibility() []error {
+	r, err := loadRegistry()
+	if err != nil {
+		return []error{err}
+	}
+
+	var problems []error
+	for eventType := range r.schemas {
+		if _, exists := eventData[eventType]; !exists {
+			problems = append(problems, fmt.Errorf("schemas committed for unknown event type %s", eventType))
+		}
+	}
+
+	for eventType := range eventData {
+		problems = append(problems, checkEventSchemas(r, eventType)...)
+	}
+
+	sort.Slice(problems, func(i, j int) bool { return problems[i].Error() < problems[j].Error() })
+	return problems
+}
+
+// CheckEventSchemaCompatibility reports whether the data struct of one event type still
+// matches its latest committed schema, and every incompatible change between its
+// consecutive schema versions that has no upcaster
+func CheckEventSchemaCompatibility(eventType EventType) []error {
+	r, err := loadRegistry()
+	if err != nil {
+		return []error{err}
+	}
+	if _, exists := eventData[eventType]; !exists {
+		return []error{fmt.Errorf("unknown event type %s", eventType)}
+	}
+	return checkEventSchemas(r, eventType)
+}
+
+// checkEventSchemas compares the consecutive schema versions of an event type and its
+// latest version with the current data struct
+func checkEventSchemas(r *schemaRegistry, eventType EventType) []error {
+	current, _ := GenerateSchema(eventType)
+	versions := r.versions[eventType]
+	if len(versions) == 0 {
+		return []error{fmt.Errorf("%s has no committed schema; commit this as schemas/%s/1.0.json:\n%s",
+			eventType, eventType, formatSchema(current))}
+	}
+
+	var problems []error
+	for i := 1; i < len(versions); i++ {
+		from, to := versions[i-1], versions[i]
+		if _, exists := upcasters[upcasterKey{eventType, from}]; exists {
+			continue
+		}
+		for _, problem := range compatibilityProblems("data", r.schemas[eventType][from], r.schemas[eventType][to]) {
+			problems = append(problems, fmt.Errorf("%s %s -> %s is not backward compatible and has no upcaster: %s",
+				eventType, from, to, problem))
+		}
+	}
+
+	latest := versions[len(versions)-1]
+	if !sameSchema(r.schemas[eventType][latest], current) {
+		advice := "add the next minor version"
+		if len(compatibilityProblems("data", r.schemas[eventType][latest], current)) > 0 {
+			advice = "add the next major version and an upcaster from " + latest
+		}
+		problems = append(problems, fmt.Errorf("%s data no longer matches schema %s; %s with this schema:\n%s",
+			eventType, latest, advice, formatSchema(current)))
+	}
+	return problems
+}
+
+// compatibilityProblems lists why data written with the old schema may not be readable
+// with the new one
+func compatibilityProblems(at string, before, after *JSONSchema) []string {
+	var problems []string
+	for _, name := range before.Type {
+		if !after.Type.has(name) {
+			problems = append(problems, fmt.Sprintf("%s no longer accepts %s", at, name))
+		}
+	}
+	if after.Format != "" && after.Format != before.Format {
+		problems = append(problems, fmt.Sprintf("%s changed format to %s", at, after.Format))
+	}
+
+	required := make(map[string]bool)
+	for _, name := range before.Required {
+		required[name] = true
+	}
+	for _, name := range after.Required {
+		if !required[name] {
+			problems = append(problems, fmt.Sprintf("%s.%s is newly required", at, name))
+		}
+	}
+
+	closed := after.AdditionalProperties != nil && !*after.AdditionalProperties
+	for name, beforeProperty := range before.Properties {
+		afterProperty, exists := after.Properties[name]
+		if !exists {
+			if closed {
+				problems = append(problems, fmt.Sprintf("%s.%s was removed", at, name))
+			}
+			continue
+		}
+		problems = append(problems, compatibilityProblems(at+"."+name, beforeProperty, afterProperty)...)
+	}
+
+	if before.Items != nil && after.Items != nil {
+		problems = append(problems, compatibilityProblems(at+"[]", before.Items, after.Items)...)
+	}
+	return problems
+}
+
+// sameSchema reports whether two schemas describe the same data
+func sameSchema(a, b *JSONSchema) bool {
+	normalize := func(s *JSONSchema) []byte {
+		copied := *s
+		copied.Schema, copied.Title = "", ""
+		data, _ := json.Marshal(&copied)
+		return data
+	}
+	return bytes.Equal(normalize(a), normalize(b))
+}
+
+// formatSchema renders a schema as it is committed
+func formatSchema(schema *JSONSchema) string {
+	data, _ := json.MarshalIndent(schema, "", "  ")
+	return string(data)
+}
+
+// compareVersions orders schema versions such as 1.0, 1.1 and 2.0 numerically
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	return 0
+}
@@ -0,0 +1,109 @@
+package kafka
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompatibilityProblems(t *testing.T) {
+	closed := false
+	base := func() *JSONSchema {
+		return &JSONSchema{
+			Type: SchemaTypes{"object"},
+			Properties: map[string]*JSONSchema{
+				"payment_id": {Type: SchemaTypes{"string"}},
+				"amount":     {Type: SchemaTypes{"integer"}},
+				"note":       {Type: SchemaTypes{"string", "null"}},
+			},
+			Required:             []string{"amount", "payment_id"},
+			AdditionalProperties: &closed,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		change func(s *JSONSchema)
+		want   string
+	}{
+		{
+			name: "optional property added",
+			change: func(s *JSONSchema) {
+				s.Properties["currency"] = &JSONSchema{Type: SchemaTypes{"string"}}
+			},
+		},
+		{
+			name: "type widened",
+			change: func(s *JSONSchema) {
+				s.Properties["amount"].Type = SchemaTypes{"number"}
+			},
+		},
+		{
+			name: "property made optional",
+			change: func(s *JSONSchema) {
+				s.Required = []string{"payment_id"}
+			},
+		},
+		{
+			name: "required property added",
+			change: func(s *JSONSchema) {
+				s.Properties["currency"] = &JSONSchema{Type: SchemaTypes{"string"}}
+				s.Required = append(s.Required, "currency")
+			},
+			want: "data.currency is newly required",
+		},
+		{
+			name: "property removed",
+			change: func(s *JSONSchema) {
+				delete(s.Properties, "note")
+			},
+			want: "data.note was removed",
+		},
+		{
+			name: "type narrowed",
+			change: func(s *JSONSchema) {
+				s.Properties["note"].Type = SchemaTypes{"string"}
+			},
+			want: "data.note no longer accepts null",
+		},
+		{
+			name: "format added",
+			change: func(s *JSONSchema) {
+				s.Properties["note"].Format = "date-time"
+			},
+			want: "data.note changed format to date-time",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			after := base()
+			tt.change(after)
+
+			problems := compatibilityProblems("data", base(), after)
+			if tt.want == "" {
+				if len(problems) > 0 {
+					t.Fatalf("compatibilityProblems() = %v, want none", problems)
+				}
+				return
+			}
+			if len(problems) != 1 || !strings.Contains(problems[0], tt.want) {
+				t.Fatalf("compatibilityProblems() = %v, want %q", problems, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchemaVersionsAreOrderedNumerically(t *testing.T) {
+	if got := compareVersions("1.2", "1.10"); got >= 0 {
+		t.Errorf("compareVersions(1.2, 1.10) = %d, want < 0", got)
+	}
+
+	for eventType := range eventData {
+		versions := SchemaVersions(eventType)
+		for i := 1; i < len(versions); i++ {
+			if compareVersions(versions[i-1], versions[i]) >= 0 {
+				t.Errorf("SchemaVersions(%s) = %v, not in ascending order", eventType, versions)
+			}
+		}
+	}
+}
@@ -0,0 +1,26 @@
+// Package schematest fails tests when event structs change without a compatible schema version
+package schematest
+
+import (
+	"testing"
+
+	"github.com/ddd-micro/kafka"
+)
+
+// AssertBackwardCompatible fails the test when an event data struct no longer matches its
+// latest committed schema, or a schema version breaks older payloads without an upcaster
+func AssertBackwardCompatible(t testing.TB) {
+	t.Helper()
+	for _, problem := range kafka.CheckSchemaCompatibility() {
+		t.Error(problem)
+	}
+}
+
+// AssertEventBackwardCompatible fails the test when the schema versions of one event type
+// break older payloads without an upcaster, or its data struct no longer matches the latest one
+func AssertEventBackwardCompatible(t testing.TB, eventType kafka.EventType) {
+	t.Helper()
+	for _, problem := range kafka.CheckEventSchemaCompatibility(eventType) {
+		t.Error(problem)
+	}
+}
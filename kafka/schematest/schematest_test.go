@@ -0,0 +1,45 @@
+package schematest_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ddd-micro/kafka"
+	"github.com/ddd-micro/kafka/schematest"
+)
+
+func TestEventSchemasAreBackwardCompatible(t *testing.T) {
+	schematest.AssertBackwardCompatible(t)
+}
+
+func TestEachSchemaDirectoryIsBackwardCompatible(t *testing.T) {
+	dirs, err := os.ReadDir(filepath.Join("..", "schemas"))
+	if err != nil {
+		t.Fatalf("failed to read schema directories: %v", err)
+	}
+
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		eventType := kafka.EventType(dir.Name())
+		versions := kafka.SchemaVersions(eventType)
+
+		t.Run(dir.Name()+" "+strings.Join(versions, " -> "), func(t *testing.T) {
+			files, err := os.ReadDir(filepath.Join("..", "schemas", dir.Name()))
+			if err != nil {
+				t.Fatalf("failed to read %s schemas: %v", eventType, err)
+			}
+			if len(versions) != len(files) {
+				t.Fatalf("loaded %d schema versions of %s, want one per file (%d)", len(versions), eventType, len(files))
+			}
+			if latest := kafka.LatestSchemaVersion(eventType); latest != versions[len(versions)-1] {
+				t.Errorf("LatestSchemaVersion(%s) = %s, want %s", eventType, latest, versions[len(versions)-1])
+			}
+
+			schematest.AssertEventBackwardCompatible(t, eventType)
+		})
+	}
+}
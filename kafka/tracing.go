@@ -0,0 +1,137 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/IBM/sarama"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/uber/jaeger-client-go"
+)
+
+// HeaderTraceParent carries the W3C trace context of a message for tooling outside the tracer
+const HeaderTraceParent = "traceparent"
+
+// traceParent formats the span in ctx as a W3C traceparent, or "" when there is none
+func traceParent(ctx context.Context) string {
+	span := opentracing.SpanFromContext(ctx)
+	if span == nil {
+		return ""
+	}
+	return formatTraceParent(span.Context())
+}
+
+// formatTraceParent formats a Jaeger span context as a W3C traceparent
+func formatTraceParent(spanContext opentracing.SpanContext) string {
+	sc, ok := spanContext.(jaeger.SpanContext)
+	if !ok || !sc.IsValid() {
+		return ""
+	}
+
+	flags := 0
+	if sc.IsSampled() {
+		flags = 1
+	}
+	return fmt.Sprintf("00-%016x%016x-%016x-%02x", sc.TraceID().High, sc.TraceID().Low, uint64(sc.SpanID()), flags)
+}
+
+// parseTraceParent parses a W3C traceparent into a Jaeger span context
+func parseTraceParent(value string) (opentracing.SpanContext, bool) {
+	parts := strings.Split(value, "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return nil, false
+	}
+
+	high, err1 := strconv.ParseUint(parts[1][:16], 16, 64)
+	low, err2 := strconv.ParseUint(parts[1][16:], 16, 64)
+	spanID, err3 := strconv.ParseUint(parts[2], 16, 64)
+	flags, err4 := strconv.ParseUint(parts[3], 16, 8)
+	if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+		return nil, false
+	}
+
+	sc := jaeger.NewSpanContext(jaeger.TraceID{High: high, Low: low}, jaeger.SpanID(spanID), 0, flags&1 == 1, nil)
+	return sc, sc.IsValid()
+}
+
+// producerHeaders writes the span context into the headers of a produced message
+type producerHeaders struct {
+	headers *[]sarama.RecordHeader
+}
+
+// Set implements opentracing.TextMapWriter
+func (c producerHeaders) Set(key, value string) {
+	*c.headers = append(*c.headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+}
+
+// consumerHeaders reads the span context from the headers of a consumed message
+type consumerHeaders []*sarama.RecordHeader
+
+// ForeachKey implements opentracing.TextMapReader
+func (c consumerHeaders) ForeachKey(handler func(key, value string) error) error {
+	for _, header := range c {
+		if err := handler(string(header.Key), string(header.Value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// startProducerSpan starts the span of publishing an event, as a child of the span
+// that created the event, and injects it into the message headers
+func startProducerSpan(eventType EventType, topic, eventTraceParent string, headers *[]sarama.RecordHeader) opentracing.Span {
+	opts := []opentracing.StartSpanOption{ext.SpanKindProducer}
+	if parent, ok := parseTraceParent(eventTraceParent); ok {
+		opts = append(opts, opentracing.ChildOf(parent))
+	}
+
+	tracer := opentracing.GlobalTracer()
+	span := tracer.StartSpan("kafka.produce "+string(eventType), opts...)
+	ext.Component.Set(span, "kafka")
+	ext.MessageBusDestination.Set(span, topic)
+	span.SetTag("messaging.system", "kafka")
+	span.SetTag("event.type", string(eventType))
+
+	carrier := producerHeaders{headers: headers}
+	if err := tracer.Inject(span.Context(), opentracing.TextMap, carrier); err == nil {
+		if value := formatTraceParent(span.Context()); value != "" {
+			carrier.Set(HeaderTraceParent, value)
+		}
+	}
+	return span
+}
+
+// startConsumerSpan starts the span of handling a message as a child of the span that
+// produced it, and returns a context carrying the span for the handler
+func startConsumerSpan(ctx context.Context, message *sarama.ConsumerMessage, eventType EventType, eventTraceParent string) (opentracing.Span, context.Context) {
+	opts := []opentracing.StartSpanOption{ext.SpanKindConsumer}
+	tracer := opentracing.GlobalTracer()
+	if parent, err := tracer.Extract(opentracing.TextMap, consumerHeaders(message.Headers)); err == nil {
+		opts = append(opts, opentracing.ChildOf(parent))
+	} else if parent, ok := parseTraceParent(headerValue(message.Headers, HeaderTraceParent)); ok {
+		opts = append(opts, opentracing.ChildOf(parent))
+	} else if parent, ok := parseTraceParent(eventTraceParent); ok {
+		opts = append(opts, opentracing.ChildOf(parent))
+	}
+
+	span := tracer.StartSpan("kafka.consume "+string(eventType), opts...)
+	ext.Component.Set(span, "kafka")
+	ext.MessageBusDestination.Set(span, message.Topic)
+	span.SetTag("messaging.system", "kafka")
+	span.SetTag("messaging.kafka.partition", message.Partition)
+	span.SetTag("messaging.kafka.offset", message.Offset)
+	span.SetTag("event.type", string(eventType))
+
+	return span, opentracing.ContextWithSpan(ctx, span)
+}
+
+// finishSpan records the outcome of a span and finishes it
+func finishSpan(span opentracing.Span, err error) {
+	if err != nil {
+		ext.LogError(span, err)
+	}
+	span.Finish()
+}
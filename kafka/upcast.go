@@ -0,0 +1,66 @@
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Upcaster turns the data of one schema version into the data of the next version
+type Upcaster struct {
+	To      string
+	Convert func(data map[string]interface{}) (map[string]interface{}, error)
+}
+
+// upcasterKey identifies the upcaster from one version of an event type
+type upcasterKey struct {
+	eventType EventType
+	from      string
+}
+
+// upcasters turn old payload versions into newer ones, one version at a time. Register
+// one whenever a schema version is not backward compatible with the previous one, e.g.
+//
+//	{EventTypeStockUpdated, "1.0"}: {To: "2.0", Convert: renameQuantityToDelta},
+var upcasters = map[upcasterKey]Upcaster{}
+
+// Upcast converts a structured event to the latest schema version of its type,
+// so handlers always decode the current data structs
+func Upcast(payload []byte) ([]byte, error) {
+	var event map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+
+	var eventType EventType
+	var version string
+	json.Unmarshal(event["type"], &eventType)
+	json.Unmarshal(event["version"], &version)
+
+	upcaster, exists := upcasters[upcasterKey{eventType, version}]
+	if !exists {
+		return payload, nil
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(event["data"], &data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s data: %w", eventType, err)
+	}
+
+	for exists {
+		converted, err := upcaster.Convert(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upcast %s from %s to %s: %w", eventType, version, upcaster.To, err)
+		}
+		data, version = converted, upcaster.To
+		upcaster, exists = upcasters[upcasterKey{eventType, version}]
+	}
+
+	var err error
+	if event["data"], err = json.Marshal(data); err != nil {
+		return nil, fmt.Errorf("failed to marshal upcast %s data: %w", eventType, err)
+	}
+	if event["version"], err = json.Marshal(version); err != nil {
+		return nil, err
+	}
+	return json.Marshal(event)
+}
@@ -0,0 +1,46 @@
+// Package catalog reads the prices and variants of the products sent by the product
+// service, so that every service selling them prices an item the same way.
+package catalog
+
+import (
+	"errors"
+	"fmt"
+
+	productpb "github.com/ddd-micro/api/proto/product"
+	"github.com/ddd-micro/pkg/money"
+)
+
+// ErrVariantNotFound is returned when a product has no variant of the given ID
+var ErrVariantNotFound = errors.New("product variant not found")
+
+// Currency returns the currency of a product, assuming money.LegacyCurrency for
+// products sent by product services that predate currencies
+func Currency(product *productpb.Product) string {
+	if product.Currency == "" {
+		return money.LegacyCurrency
+	}
+	return product.Currency
+}
+
+// Variant finds a variant of a product by ID
+func Variant(product *productpb.Product, variantID uint) (*productpb.ProductVariant, error) {
+	for _, variant := range product.Variants {
+		if variant.Id == uint32(variantID) {
+			return variant, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: variant %d of product %d", ErrVariantNotFound, variantID, product.Id)
+}
+
+// ItemPrice returns the current price of a product, or the effective price of the variant
+// when one is given. Products that predate minor unit prices only carry a decimal price.
+func ItemPrice(product *productpb.Product, variantID *uint) (money.Money, error) {
+	if variantID != nil {
+		variant, err := Variant(product, *variantID)
+		if err != nil {
+			return money.Money{}, err
+		}
+		return money.New(variant.PriceMinor, Currency(product)), nil
+	}
+	return money.FromMinorOrMajor(product.PriceMinor, product.Price, Currency(product))
+}
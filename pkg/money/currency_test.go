@@ -0,0 +1,66 @@
+package money
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExponent(t *testing.T) {
+	tests := []struct {
+		currency string
+		want     int
+		wantErr  error
+	}{
+		{currency: "JPY", want: 0},
+		{currency: "KRW", want: 0},
+		{currency: "CLP", want: 0},
+		{currency: "USD", want: 2},
+		{currency: "EUR", want: 2},
+		{currency: "KWD", want: 3},
+		{currency: "BHD", want: 3},
+		{currency: "TND", want: 3},
+		{currency: "CLF", want: 4},
+		{currency: "jpy", want: 0},
+		{currency: " kwd ", want: 3},
+		{currency: "XXX", wantErr: ErrUnknownCurrency},
+		{currency: "", wantErr: ErrUnknownCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.currency, func(t *testing.T) {
+			got, err := Exponent(tt.currency)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Exponent(%q) error = %v, want %v", tt.currency, err, tt.wantErr)
+				}
+				if IsValidCurrency(tt.currency) {
+					t.Errorf("IsValidCurrency(%q) = true", tt.currency)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Exponent(%q) error = %v", tt.currency, err)
+			}
+			if got != tt.want {
+				t.Errorf("Exponent(%q) = %d, want %d", tt.currency, got, tt.want)
+			}
+			if !IsValidCurrency(tt.currency) {
+				t.Errorf("IsValidCurrency(%q) = false", tt.currency)
+			}
+		})
+	}
+}
+
+func TestExponentSQL(t *testing.T) {
+	sql := ExponentSQL("currency")
+
+	for _, want := range []string{"CASE UPPER(currency)", "WHEN 'JPY' THEN 0", "WHEN 'KWD' THEN 3", "ELSE 2 END"} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("ExponentSQL() = %q, missing %q", sql, want)
+		}
+	}
+	if strings.Contains(sql, "'USD'") {
+		t.Errorf("ExponentSQL() = %q, lists two-decimal currencies", sql)
+	}
+}
@@ -0,0 +1,330 @@
+// Package money provides an integer Money type that stores amounts in the minor
+// unit of their ISO-4217 currency, e.g. cents for USD, so that prices never go
+// through binary floating point arithmetic.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Money errors
+var (
+	ErrCurrencyMismatch = errors.New("money: currency mismatch")
+	ErrUnknownCurrency  = errors.New("money: unknown currency")
+	ErrInvalidAmount    = errors.New("money: invalid amount")
+	ErrOverflow         = errors.New("money: amount overflows int64")
+	ErrInvalidRatios    = errors.New("money: invalid allocation ratios")
+)
+
+// Money is an amount in the minor unit of its currency
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// New creates an amount from minor units, e.g. New(1999, "USD") is 19.99 USD
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: normalizeCurrency(currency)}
+}
+
+// Zero creates a zero amount in the given currency
+func Zero(currency string) Money {
+	return New(0, currency)
+}
+
+// Parse parses a decimal amount in major units such as "19.99". Digits beyond the
+// currency exponent are rounded half to even.
+func Parse(amount, currency string) (Money, error) {
+	exponent, err := Exponent(currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	s := strings.TrimSpace(amount)
+	negative := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		negative = true
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" && fraction == "" || !isDigits(whole) || !isDigits(fraction) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+
+	// Pad the fraction to the exponent and keep the excess digits for rounding
+	if len(fraction) < exponent {
+		fraction += strings.Repeat("0", exponent-len(fraction))
+	}
+	excess := fraction[exponent:]
+	digits := strings.TrimLeft(whole+fraction[:exponent], "0")
+	if digits == "" {
+		digits = "0"
+	}
+
+	minor, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrOverflow, amount)
+	}
+	if roundUp(excess, minor) {
+		if minor == math.MaxInt64 {
+			return Money{}, fmt.Errorf("%w: %q", ErrOverflow, amount)
+		}
+		minor++
+	}
+	if negative {
+		minor = -minor
+	}
+
+	return New(minor, currency), nil
+}
+
+// FromMajor converts an amount in major units, e.g. 19.99, to Money. The float is
+// converted through its shortest decimal representation, so 19.99 becomes exactly
+// 1999 cents instead of 1998.
+func FromMajor(amount float64, currency string) (Money, error) {
+	if math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return Money{}, fmt.Errorf("%w: %v", ErrInvalidAmount, amount)
+	}
+	return Parse(strconv.FormatFloat(amount, 'f', -1, 64), currency)
+}
+
+// LegacyCurrency is assumed for amounts sent by services that predate currencies
+const LegacyCurrency = "USD"
+
+// FromMinorOrMajor returns an amount sent in minor units, or converts the decimal amount
+// sent instead by services that predate minor units. An empty currency is LegacyCurrency.
+func FromMinorOrMajor(minor int64, major float64, currency string) (Money, error) {
+	if currency == "" {
+		currency = LegacyCurrency
+	}
+	if minor != 0 {
+		return New(minor, currency), nil
+	}
+	return FromMajor(major, currency)
+}
+
+// Major returns the amount in major units. It is meant for display and APIs that
+// still speak decimals; never use it for arithmetic.
+func (m Money) Major() float64 {
+	major, _ := strconv.ParseFloat(m.Decimal(), 64)
+	return major
+}
+
+// Decimal formats the amount in major units, e.g. "19.99"
+func (m Money) Decimal() string {
+	exponent := exponentOrDefault(m.Currency)
+	digits := strconv.FormatUint(absUint(m.Amount), 10)
+	sign := ""
+	if m.Amount < 0 {
+		sign = "-"
+	}
+	if exponent == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+	point := len(digits) - exponent
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// String formats the amount with its currency, e.g. "19.99 USD"
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+// IsZero checks if the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsPositive checks if the amount is greater than zero
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+// IsNegative checks if the amount is less than zero
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// SameCurrency checks if both amounts are in the same currency
+func (m Money) SameCurrency(other Money) bool {
+	return normalizeCurrency(m.Currency) == normalizeCurrency(other.Currency)
+}
+
+// Add returns the sum of both amounts
+func (m Money) Add(other Money) (Money, error) {
+	if !m.SameCurrency(other) {
+		return Money{}, mismatch(m, other)
+	}
+	sum := m.Amount + other.Amount
+	if (other.Amount > 0 && sum < m.Amount) || (other.Amount < 0 && sum > m.Amount) {
+		return Money{}, ErrOverflow
+	}
+	return New(sum, m.Currency), nil
+}
+
+// Sub returns the difference of both amounts
+func (m Money) Sub(other Money) (Money, error) {
+	if other.Amount == math.MinInt64 {
+		return Money{}, ErrOverflow
+	}
+	return m.Add(other.Negate())
+}
+
+// Compare returns -1, 0 or +1 depending on whether m is less than, equal to or
+// greater than other
+func (m Money) Compare(other Money) (int, error) {
+	if !m.SameCurrency(other) {
+		return 0, mismatch(m, other)
+	}
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Multiply returns the amount multiplied by a whole factor, e.g. a quantity
+func (m Money) Multiply(factor int64) Money {
+	return New(m.Amount*factor, m.Currency)
+}
+
+// Negate returns the amount with the opposite sign
+func (m Money) Negate() Money {
+	return New(-m.Amount, m.Currency)
+}
+
+// Allocate splits the amount by the given ratios. Each share is rounded half to
+// even and the rounding remainder is handed out one minor unit at a time, so the
+// shares always add up to the original amount.
+func (m Money) Allocate(ratios ...int64) ([]Money, error) {
+	if len(ratios) == 0 {
+		return nil, ErrInvalidRatios
+	}
+
+	total := new(big.Int)
+	for _, ratio := range ratios {
+		if ratio < 0 {
+			return nil, ErrInvalidRatios
+		}
+		total.Add(total, big.NewInt(ratio))
+	}
+	if total.Sign() == 0 {
+		return nil, ErrInvalidRatios
+	}
+
+	shares := make([]Money, len(ratios))
+	remainder := m.Amount
+	for i, ratio := range ratios {
+		share := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(ratio))
+		shares[i] = New(roundHalfEven(share, total), m.Currency)
+		remainder -= shares[i].Amount
+	}
+
+	// Hand out what rounding left over, skipping shares with a zero ratio
+	step := int64(1)
+	if remainder < 0 {
+		step = -1
+	}
+	for i := 0; remainder != 0; i = (i + 1) % len(shares) {
+		if ratios[i] == 0 {
+			continue
+		}
+		shares[i].Amount += step
+		remainder -= step
+	}
+
+	return shares, nil
+}
+
+// Split divides the amount into n shares that differ by at most one minor unit
+func (m Money) Split(n int) ([]Money, error) {
+	if n <= 0 {
+		return nil, ErrInvalidRatios
+	}
+	ratios := make([]int64, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return m.Allocate(ratios...)
+}
+
+// roundHalfEven divides num by den, rounding ties to the even neighbour
+func roundHalfEven(num, den *big.Int) int64 {
+	quotient, remainder := new(big.Int).QuoRem(num, den, new(big.Int))
+	if remainder.Sign() == 0 {
+		return quotient.Int64()
+	}
+
+	twice := new(big.Int).Abs(remainder)
+	twice.Lsh(twice, 1)
+	switch twice.Cmp(new(big.Int).Abs(den)) {
+	case 1:
+		quotient.Add(quotient, big.NewInt(int64(num.Sign()*den.Sign())))
+	case 0:
+		if quotient.Bit(0) == 1 {
+			quotient.Add(quotient, big.NewInt(int64(num.Sign()*den.Sign())))
+		}
+	}
+	return quotient.Int64()
+}
+
+// roundUp decides whether the dropped digits round the kept value up, half to even
+func roundUp(excess string, kept int64) bool {
+	if excess == "" {
+		return false
+	}
+	switch {
+	case excess[0] > '5':
+		return true
+	case excess[0] < '5':
+		return false
+	case strings.Trim(excess[1:], "0") != "":
+		return true
+	}
+	return kept%2 == 1
+}
+
+// exponentOrDefault returns the currency exponent, assuming two digits for unknown currencies
+func exponentOrDefault(currency string) int {
+	if exponent, err := Exponent(currency); err == nil {
+		return exponent
+	}
+	return 2
+}
+
+// mismatch builds a currency mismatch error
+func mismatch(a, b Money) error {
+	return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, a.Currency, b.Currency)
+}
+
+// isDigits checks if the string only contains ASCII digits
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// absUint returns the absolute value of an int64 without overflowing on MinInt64
+func absUint(n int64) uint64 {
+	if n < 0 {
+		return uint64(-(n + 1)) + 1
+	}
+	return uint64(n)
+}
@@ -0,0 +1,311 @@
+package money
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		currency string
+		want     Money
+		wantErr  error
+	}{
+		{name: "two decimals", amount: "19.99", currency: "USD", want: New(1999, "USD")},
+		{name: "short fraction", amount: "19.9", currency: "USD", want: New(1990, "USD")},
+		{name: "whole amount", amount: "19", currency: "USD", want: New(1900, "USD")},
+		{name: "leading point", amount: ".5", currency: "USD", want: New(50, "USD")},
+		{name: "trailing point", amount: "5.", currency: "USD", want: New(500, "USD")},
+		{name: "negative", amount: "-3.10", currency: "USD", want: New(-310, "USD")},
+		{name: "explicit plus", amount: "+1.00", currency: "USD", want: New(100, "USD")},
+		{name: "surrounding spaces", amount: " 7.25 ", currency: "USD", want: New(725, "USD")},
+		{name: "lower case currency", amount: "1.50", currency: "eur", want: New(150, "EUR")},
+		{name: "zero decimal currency", amount: "1000", currency: "JPY", want: New(1000, "JPY")},
+		{name: "three decimal currency", amount: "1.234", currency: "KWD", want: New(1234, "KWD")},
+		{name: "largest amount", amount: "92233720368547758.07", currency: "USD", want: New(math.MaxInt64, "USD")},
+		{name: "empty", amount: "", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "lone point", amount: ".", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "letters", amount: "abc", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "two points", amount: "1.2.3", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "exponent notation", amount: "1e3", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "unknown currency", amount: "1.00", currency: "XXX", wantErr: ErrUnknownCurrency},
+		{name: "overflow", amount: "99999999999999999999", currency: "USD", wantErr: ErrOverflow},
+		{name: "overflow by rounding", amount: "92233720368547758.075", currency: "USD", wantErr: ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.amount, tt.currency)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Parse(%q, %q) error = %v, want %v", tt.amount, tt.currency, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q, %q) error = %v", tt.amount, tt.currency, err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q, %q) = %v, want %v", tt.amount, tt.currency, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRoundsHalfToEven(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		want     int64
+	}{
+		{amount: "0.125", currency: "USD", want: 12},
+		{amount: "0.135", currency: "USD", want: 14},
+		{amount: "0.145", currency: "USD", want: 14},
+		{amount: "1.005", currency: "USD", want: 100},
+		{amount: "1.015", currency: "USD", want: 102},
+		{amount: "-0.125", currency: "USD", want: -12},
+		{amount: "-1.015", currency: "USD", want: -102},
+		{amount: "1.0051", currency: "USD", want: 101},
+		{amount: "1.0049", currency: "USD", want: 100},
+		{amount: "1.00500", currency: "USD", want: 100},
+		{amount: "1000.5", currency: "JPY", want: 1000},
+		{amount: "1001.5", currency: "JPY", want: 1002},
+		{amount: "1.2345", currency: "KWD", want: 1234},
+		{amount: "1.2355", currency: "KWD", want: 1236},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.amount+" "+tt.currency, func(t *testing.T) {
+			got, err := Parse(tt.amount, tt.currency)
+			if err != nil {
+				t.Fatalf("Parse(%q, %q) error = %v", tt.amount, tt.currency, err)
+			}
+			if got.Amount != tt.want {
+				t.Errorf("Parse(%q, %q) = %d, want %d", tt.amount, tt.currency, got.Amount, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromMajor(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   float64
+		currency string
+		want     Money
+		wantErr  error
+	}{
+		{name: "not truncated by binary representation", amount: 19.99, currency: "USD", want: New(1999, "USD")},
+		{name: "float addition noise", amount: 0.1 + 0.2, currency: "USD", want: New(30, "USD")},
+		{name: "tie rounds up to even", amount: 2.675, currency: "USD", want: New(268, "USD")},
+		{name: "tie rounds down to even", amount: 2.665, currency: "USD", want: New(266, "USD")},
+		{name: "below a minor unit", amount: 1e-7, currency: "USD", want: New(0, "USD")},
+		{name: "negative", amount: -4.5, currency: "EUR", want: New(-450, "EUR")},
+		{name: "zero decimal currency", amount: 1234, currency: "JPY", want: New(1234, "JPY")},
+		{name: "three decimal currency", amount: 0.125, currency: "BHD", want: New(125, "BHD")},
+		{name: "not a number", amount: math.NaN(), currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "infinity", amount: math.Inf(1), currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "unknown currency", amount: 1, currency: "XXX", wantErr: ErrUnknownCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromMajor(tt.amount, tt.currency)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("FromMajor(%v, %q) error = %v, want %v", tt.amount, tt.currency, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FromMajor(%v, %q) error = %v", tt.amount, tt.currency, err)
+			}
+			if got != tt.want {
+				t.Errorf("FromMajor(%v, %q) = %v, want %v", tt.amount, tt.currency, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromMinorOrMajor(t *testing.T) {
+	tests := []struct {
+		name     string
+		minor    int64
+		major    float64
+		currency string
+		want     Money
+	}{
+		{name: "minor units win", minor: 1999, major: 5, currency: "EUR", want: New(1999, "EUR")},
+		{name: "decimal of a legacy sender", major: 19.99, currency: "EUR", want: New(1999, "EUR")},
+		{name: "legacy currency", minor: 500, want: New(500, LegacyCurrency)},
+		{name: "zero", currency: "USD", want: New(0, "USD")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromMinorOrMajor(tt.minor, tt.major, tt.currency)
+			if err != nil {
+				t.Fatalf("FromMinorOrMajor(%d, %v, %q) error = %v", tt.minor, tt.major, tt.currency, err)
+			}
+			if got != tt.want {
+				t.Errorf("FromMinorOrMajor(%d, %v, %q) = %v, want %v", tt.minor, tt.major, tt.currency, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{money: New(1999, "USD"), want: "19.99"},
+		{money: New(5, "USD"), want: "0.05"},
+		{money: New(-5, "USD"), want: "-0.05"},
+		{money: New(0, "USD"), want: "0.00"},
+		{money: New(1999, "JPY"), want: "1999"},
+		{money: New(-1999, "JPY"), want: "-1999"},
+		{money: New(1234, "KWD"), want: "1.234"},
+		{money: New(5, "KWD"), want: "0.005"},
+		{money: New(math.MinInt64, "USD"), want: "-92233720368547758.08"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want+" "+tt.money.Currency, func(t *testing.T) {
+			if got := tt.money.Decimal(); got != tt.want {
+				t.Errorf("Decimal() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := New(1999, "usd").String(); got != "19.99 USD" {
+		t.Errorf("String() = %q, want %q", got, "19.99 USD")
+	}
+}
+
+func TestAdd(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    Money
+		want    Money
+		wantErr error
+	}{
+		{name: "same currency", a: New(100, "USD"), b: New(250, "USD"), want: New(350, "USD")},
+		{name: "currency case", a: Money{Amount: 100, Currency: "usd"}, b: New(1, "USD"), want: New(101, "USD")},
+		{name: "negative", a: New(100, "EUR"), b: New(-250, "EUR"), want: New(-150, "EUR")},
+		{name: "currency mismatch", a: New(100, "USD"), b: New(100, "EUR"), wantErr: ErrCurrencyMismatch},
+		{name: "mismatched exponents", a: New(100, "USD"), b: New(100, "JPY"), wantErr: ErrCurrencyMismatch},
+		{name: "overflow", a: New(math.MaxInt64, "USD"), b: New(1, "USD"), wantErr: ErrOverflow},
+		{name: "underflow", a: New(math.MinInt64, "USD"), b: New(-1, "USD"), wantErr: ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.a.Add(tt.b)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("%v.Add(%v) error = %v, want %v", tt.a, tt.b, err, tt.wantErr)
+				}
+				if got != (Money{}) {
+					t.Errorf("%v.Add(%v) = %v on error, want zero value", tt.a, tt.b, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%v.Add(%v) error = %v", tt.a, tt.b, err)
+			}
+			if got != tt.want {
+				t.Errorf("%v.Add(%v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCurrencyMismatch(t *testing.T) {
+	usd, eur := New(100, "USD"), New(100, "EUR")
+
+	if _, err := usd.Sub(eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Sub() error = %v, want %v", err, ErrCurrencyMismatch)
+	}
+	if _, err := usd.Compare(eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Compare() error = %v, want %v", err, ErrCurrencyMismatch)
+	}
+	if usd.SameCurrency(eur) {
+		t.Error("SameCurrency() = true for USD and EUR")
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  Money
+		ratios  []int64
+		want    []int64
+		wantErr error
+	}{
+		{name: "even split remainder goes first", amount: New(100, "USD"), ratios: []int64{1, 1, 1}, want: []int64{34, 33, 33}},
+		{name: "exact ratios", amount: New(1000, "USD"), ratios: []int64{70, 30}, want: []int64{700, 300}},
+		{name: "ties round to even", amount: New(5, "USD"), ratios: []int64{1, 1}, want: []int64{3, 2}},
+		{name: "amount smaller than shares", amount: New(1, "USD"), ratios: []int64{1, 1, 1}, want: []int64{1, 0, 0}},
+		{name: "rounded up shares give back the excess", amount: New(15, "USD"), ratios: []int64{1, 1, 1, 1}, want: []int64{3, 4, 4, 4}},
+		{name: "zero ratio gets nothing", amount: New(10, "USD"), ratios: []int64{0, 1, 1}, want: []int64{0, 5, 5}},
+		{name: "zero ratio skipped for remainder", amount: New(7, "USD"), ratios: []int64{0, 1, 1}, want: []int64{0, 3, 4}},
+		{name: "negative amount", amount: New(-100, "USD"), ratios: []int64{1, 1, 1}, want: []int64{-34, -33, -33}},
+		{name: "zero decimal currency", amount: New(1000, "JPY"), ratios: []int64{1, 2}, want: []int64{333, 667}},
+		{name: "no ratios", amount: New(100, "USD"), wantErr: ErrInvalidRatios},
+		{name: "negative ratio", amount: New(100, "USD"), ratios: []int64{1, -1}, wantErr: ErrInvalidRatios},
+		{name: "all zero ratios", amount: New(100, "USD"), ratios: []int64{0, 0}, wantErr: ErrInvalidRatios},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares, err := tt.amount.Allocate(tt.ratios...)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Allocate(%v) error = %v, want %v", tt.ratios, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Allocate(%v) error = %v", tt.ratios, err)
+			}
+			if len(shares) != len(tt.want) {
+				t.Fatalf("Allocate(%v) returned %d shares, want %d", tt.ratios, len(shares), len(tt.want))
+			}
+
+			var total int64
+			for i, share := range shares {
+				if share.Amount != tt.want[i] {
+					t.Errorf("share %d = %d, want %d", i, share.Amount, tt.want[i])
+				}
+				if share.Currency != tt.amount.Currency {
+					t.Errorf("share %d currency = %q, want %q", i, share.Currency, tt.amount.Currency)
+				}
+				total += share.Amount
+			}
+			if total != tt.amount.Amount {
+				t.Errorf("shares add up to %d, want %d", total, tt.amount.Amount)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	shares, err := New(1000, "USD").Split(3)
+	if err != nil {
+		t.Fatalf("Split(3) error = %v", err)
+	}
+	want := []int64{334, 333, 333}
+	for i, share := range shares {
+		if share.Amount != want[i] {
+			t.Errorf("share %d = %d, want %d", i, share.Amount, want[i])
+		}
+	}
+
+	if _, err := New(1000, "USD").Split(0); !errors.Is(err, ErrInvalidRatios) {
+		t.Errorf("Split(0) error = %v, want %v", err, ErrInvalidRatios)
+	}
+}
@@ -0,0 +1,80 @@
+// Package outbox implements the transactional outbox: events are stored in the
+// same database transaction as the aggregate change and a relay publishes them
+// to Kafka afterwards, so a committed change never loses its events.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ddd-micro/kafka"
+	"github.com/ddd-micro/pkg/gormtx"
+	"gorm.io/gorm"
+)
+
+// Message is an event waiting in the outbox to be published
+type Message struct {
+	ID            uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	AggregateType string     `gorm:"size:50;not null;index:idx_outbox_aggregate" json:"aggregate_type"`
+	AggregateID   string     `gorm:"size:100;not null;index:idx_outbox_aggregate" json:"aggregate_id"`
+	EventType     string     `gorm:"size:100;not null" json:"event_type"`
+	Payload       []byte     `gorm:"type:bytea;not null" json:"payload"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	LastError     string     `gorm:"type:text" json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `gorm:"not null" json:"next_attempt_at"`
+	CreatedAt     time.Time  `gorm:"not null" json:"created_at"`
+	PublishedAt   *time.Time `gorm:"index" json:"published_at,omitempty"`
+}
+
+// TableName returns the table name for Message
+func (Message) TableName() string {
+	return "outbox_messages"
+}
+
+// Key returns the partition key of the message; events of one aggregate share it
+func (m *Message) Key() string {
+	return m.AggregateID
+}
+
+// Migrate creates the outbox table
+func Migrate(db *gorm.DB) error {
+	return db.AutoMigrate(&Message{})
+}
+
+// Store writes events to the outbox
+type Store struct {
+	db *gorm.DB
+}
+
+// NewStore creates a new outbox store
+func NewStore(db *gorm.DB) *Store {
+	return &Store{db: db}
+}
+
+// Add serializes the event and stores it for the aggregate. It joins the
+// transaction carried by ctx, so the event commits with the aggregate change.
+func (s *Store) Add(ctx context.Context, aggregateType, aggregateID string, eventType kafka.EventType, event interface{}) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox event: %w", err)
+	}
+	if err := kafka.ValidateEvent(payload); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	message := &Message{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     string(eventType),
+		Payload:       payload,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+	if err := gormtx.DB(ctx, s.db).Create(message).Error; err != nil {
+		return fmt.Errorf("failed to store outbox event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package outbox
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Metrics holds the prometheus metrics of the outbox relay
+type Metrics struct {
+	PendingMessages   prometheus.Gauge
+	OldestPendingAge  prometheus.Gauge
+	PublishLag        *prometheus.HistogramVec
+	MessagesPublished *prometheus.CounterVec
+	PublishFailures   *prometheus.CounterVec
+	MessagesDeleted   prometheus.Counter
+}
+
+// NewMetrics registers the relay metrics under the namespace, e.g. "payment_service"
+func NewMetrics(namespace string) *Metrics {
+	return &Metrics{
+		PendingMessages: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "outbox_pending_messages",
+				Help:      "Number of outbox messages waiting to be published",
+			},
+		),
+		OldestPendingAge: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Name:      "outbox_oldest_pending_age_seconds",
+				Help:      "Age of the oldest outbox message waiting to be published",
+			},
+		),
+		PublishLag: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "outbox_publish_lag_seconds",
+				Help:      "Time between storing an outbox message and publishing it to Kafka",
+				Buckets:   []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 300, 900},
+			},
+			[]string{"event_type"},
+		),
+		MessagesPublished: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "outbox_messages_published_total",
+				Help:      "Total number of outbox messages published to Kafka",
+			},
+			[]string{"event_type"},
+		),
+		PublishFailures: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "outbox_publish_failures_total",
+				Help:      "Total number of failed attempts to publish outbox messages",
+			},
+			[]string{"event_type"},
+		),
+		MessagesDeleted: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "outbox_messages_deleted_total",
+				Help:      "Total number of published outbox messages deleted after the retention period",
+			},
+		),
+	}
+}
@@ -0,0 +1,297 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ddd-micro/kafka"
+	"gorm.io/gorm"
+)
+
+// RelayConfig holds configuration for the outbox relay
+type RelayConfig struct {
+	// Name identifies the relay; relays with the same name never run concurrently
+	Name         string
+	PollInterval time.Duration
+	BatchSize    int
+	RetryBackoff time.Duration
+	MaxBackoff   time.Duration
+	// Retention is how long published messages are kept; zero keeps them forever
+	Retention     time.Duration
+	SweepInterval time.Duration
+}
+
+// LoadRelayConfig loads relay configuration from environment variables
+func LoadRelayConfig(name string) RelayConfig {
+	return RelayConfig{
+		Name:          name,
+		PollInterval:  getEnvAsDuration("OUTBOX_POLL_INTERVAL", time.Second),
+		BatchSize:     getEnvAsInt("OUTBOX_BATCH_SIZE", 100),
+		RetryBackoff:  getEnvAsDuration("OUTBOX_RETRY_BACKOFF", time.Second),
+		MaxBackoff:    getEnvAsDuration("OUTBOX_MAX_BACKOFF", 5*time.Minute),
+		Retention:     getEnvAsDuration("OUTBOX_RETENTION", 7*24*time.Hour),
+		SweepInterval: getEnvAsDuration("OUTBOX_SWEEP_INTERVAL", time.Hour),
+	}
+}
+
+// Relay publishes outbox messages to Kafka in the order they were stored per aggregate
+type Relay struct {
+	db        *gorm.DB
+	publisher kafka.EventPublisher
+	config    RelayConfig
+	metrics   *Metrics
+	lockID    int64
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewRelay creates a new outbox relay
+func NewRelay(db *gorm.DB, publisher kafka.EventPublisher, config RelayConfig, metrics *Metrics) *Relay {
+	h := fnv.New64a()
+	h.Write([]byte("outbox:" + config.Name))
+
+	return &Relay{
+		db:        db,
+		publisher: publisher,
+		config:    config,
+		metrics:   metrics,
+		lockID:    int64(h.Sum64()),
+	}
+}
+
+// Start starts polling the outbox in the background
+func (r *Relay) Start(ctx context.Context) error {
+	ctx, r.cancel = context.WithCancel(ctx)
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.config.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := r.RelayPending(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Outbox relay %s failed: %v", r.config.Name, err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	if r.config.Retention > 0 && r.config.SweepInterval > 0 {
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			r.sweep(ctx)
+		}()
+	}
+
+	log.Printf("Outbox relay %s started", r.config.Name)
+	return nil
+}
+
+// sweep periodically deletes published messages older than the retention period
+func (r *Relay) sweep(ctx context.Context) {
+	ticker := time.NewTicker(r.config.SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		deleted, err := r.DeletePublished(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Outbox relay %s failed to delete published messages: %v", r.config.Name, err)
+		}
+		if deleted > 0 {
+			log.Printf("Outbox relay %s deleted %d published messages", r.config.Name, deleted)
+		}
+	}
+}
+
+// Stop stops the relay and waits for the running batch to finish
+func (r *Relay) Stop() error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+	log.Printf("Outbox relay %s stopped", r.config.Name)
+	return nil
+}
+
+// RelayPending publishes one batch of pending messages and returns how many were published.
+// A message that fails to publish holds back the later messages of its aggregate until
+// it is retried, so consumers always see the events of an aggregate in order.
+func (r *Relay) RelayPending(ctx context.Context) (int, error) {
+	published := 0
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Only one relay instance publishes at a time, otherwise two instances
+		// could publish the events of one aggregate out of order
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", r.lockID).Scan(&locked).Error; err != nil {
+			return fmt.Errorf("failed to acquire outbox lock: %w", err)
+		}
+		if !locked {
+			return nil
+		}
+
+		now := time.Now().UTC()
+		var messages []Message
+		if err := tx.
+			Where("published_at IS NULL").
+			Where(`NOT EXISTS (
+				SELECT 1 FROM outbox_messages waiting
+				WHERE waiting.published_at IS NULL
+				AND waiting.aggregate_type = outbox_messages.aggregate_type
+				AND waiting.aggregate_id = outbox_messages.aggregate_id
+				AND waiting.id <= outbox_messages.id
+				AND waiting.next_attempt_at > ?)`, now).
+			Order("id").
+			Limit(r.config.BatchSize).
+			Find(&messages).Error; err != nil {
+			return fmt.Errorf("failed to load outbox messages: %w", err)
+		}
+
+		blocked := make(map[string]bool)
+		for i := range messages {
+			message := &messages[i]
+			aggregate := message.AggregateType + ":" + message.AggregateID
+			if blocked[aggregate] {
+				continue
+			}
+
+			if err := r.publisher.Publish(kafka.EventType(message.EventType), message.Key(), message.Payload); err != nil {
+				blocked[aggregate] = true
+				r.metrics.PublishFailures.WithLabelValues(message.EventType).Inc()
+				if err := r.scheduleRetry(tx, message, err); err != nil {
+					return err
+				}
+				continue
+			}
+
+			publishedAt := time.Now().UTC()
+			if err := tx.Model(message).Update("published_at", publishedAt).Error; err != nil {
+				return fmt.Errorf("failed to mark outbox message %d published: %w", message.ID, err)
+			}
+			r.metrics.MessagesPublished.WithLabelValues(message.EventType).Inc()
+			r.metrics.PublishLag.WithLabelValues(message.EventType).Observe(publishedAt.Sub(message.CreatedAt).Seconds())
+			published++
+		}
+
+		return r.updateDepth(tx)
+	})
+
+	return published, err
+}
+
+// DeletePublished deletes the messages published longer ago than the retention period
+// and returns how many were deleted. Rows are deleted in batches so that a large
+// backlog never holds locks on the table for long. Unpublished messages are never deleted.
+func (r *Relay) DeletePublished(ctx context.Context) (int64, error) {
+	if r.config.Retention <= 0 {
+		return 0, nil
+	}
+
+	cutoff := time.Now().UTC().Add(-r.config.Retention)
+	batchSize := max(r.config.BatchSize, 1)
+
+	var deleted int64
+	for {
+		batch := r.db.Model(&Message{}).
+			Select("id").
+			Where("published_at IS NOT NULL AND published_at < ?", cutoff).
+			Order("id").
+			Limit(batchSize)
+
+		result := r.db.WithContext(ctx).Where("id IN (?)", batch).Delete(&Message{})
+		if result.Error != nil {
+			return deleted, fmt.Errorf("failed to delete published outbox messages: %w", result.Error)
+		}
+
+		deleted += result.RowsAffected
+		r.metrics.MessagesDeleted.Add(float64(result.RowsAffected))
+		if result.RowsAffected < int64(batchSize) {
+			return deleted, nil
+		}
+	}
+}
+
+// scheduleRetry records the failed attempt and backs off exponentially
+func (r *Relay) scheduleRetry(tx *gorm.DB, message *Message, publishErr error) error {
+	log.Printf("Failed to publish outbox message %d (%s, attempt %d): %v",
+		message.ID, message.EventType, message.Attempts+1, publishErr)
+
+	backoff := r.config.RetryBackoff << min(message.Attempts, 20)
+	if backoff <= 0 || backoff > r.config.MaxBackoff {
+		backoff = r.config.MaxBackoff
+	}
+
+	if err := tx.Model(message).Updates(map[string]interface{}{
+		"attempts":        message.Attempts + 1,
+		"last_error":      publishErr.Error(),
+		"next_attempt_at": time.Now().UTC().Add(backoff),
+	}).Error; err != nil {
+		return fmt.Errorf("failed to reschedule outbox message %d: %w", message.ID, err)
+	}
+	return nil
+}
+
+// updateDepth refreshes the pending depth and oldest pending age gauges
+func (r *Relay) updateDepth(tx *gorm.DB) error {
+	pending := tx.Model(&Message{}).Where("published_at IS NULL").Session(&gorm.Session{})
+
+	var depth int64
+	if err := pending.Count(&depth).Error; err != nil {
+		return fmt.Errorf("failed to count pending outbox messages: %w", err)
+	}
+	r.metrics.PendingMessages.Set(float64(depth))
+	if depth == 0 {
+		r.metrics.OldestPendingAge.Set(0)
+		return nil
+	}
+
+	// The oldest message is scanned into a Message rather than through MIN(created_at),
+	// so its timestamp parses the same way on every driver
+	var oldest Message
+	if err := pending.Select("created_at").Order("created_at").Take(&oldest).Error; err != nil {
+		return fmt.Errorf("failed to load oldest pending outbox message: %w", err)
+	}
+	r.metrics.OldestPendingAge.Set(time.Since(oldest.CreatedAt).Seconds())
+	return nil
+}
+
+// Helper functions
+
+func getEnvAsInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
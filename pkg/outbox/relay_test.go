@@ -0,0 +1,297 @@
+package outbox_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ddd-micro/kafka"
+	"github.com/ddd-micro/pkg/outbox"
+	gosqlite "github.com/glebarez/go-sqlite"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// relayMetrics is shared by the tests, as the relay metrics register globally
+var relayMetrics = outbox.NewMetrics("outbox_relay_test")
+
+// lockHeld makes the advisory lock look taken by another relay instance
+var lockHeld atomic.Bool
+
+func init() {
+	gosqlite.MustRegisterScalarFunction("pg_try_advisory_xact_lock", 1, func(ctx *gosqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		return !lockHeld.Load(), nil
+	})
+}
+
+// fakePublisher records the published messages and fails the next publishes of a key
+type fakePublisher struct {
+	kafka.EventPublisher
+
+	mu        sync.Mutex
+	failures  map[string]int
+	published []string
+}
+
+func (p *fakePublisher) Publish(eventType kafka.EventType, key string, payload []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.failures[key] > 0 {
+		p.failures[key]--
+		return errors.New("broker unavailable")
+	}
+	p.published = append(p.published, key+":"+string(payload))
+	return nil
+}
+
+func (p *fakePublisher) failNext(key string, times int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.failures == nil {
+		p.failures = make(map[string]int)
+	}
+	p.failures[key] += times
+}
+
+func (p *fakePublisher) take() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	published := p.published
+	p.published = nil
+	return published
+}
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database: %v", err)
+	}
+	// Every connection to :memory: opens its own database
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := outbox.Migrate(db); err != nil {
+		t.Fatalf("failed to migrate outbox: %v", err)
+	}
+	return db
+}
+
+// addMessage stores a pending message of the aggregate with the payload as its body
+func addMessage(t *testing.T, db *gorm.DB, aggregateID, payload string) *outbox.Message {
+	t.Helper()
+	now := time.Now().UTC()
+	message := &outbox.Message{
+		AggregateType: "payment",
+		AggregateID:   aggregateID,
+		EventType:     string(kafka.EventTypePaymentCompleted),
+		Payload:       []byte(payload),
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+	if err := db.Create(message).Error; err != nil {
+		t.Fatalf("failed to store message: %v", err)
+	}
+	return message
+}
+
+func reload(t *testing.T, db *gorm.DB, message *outbox.Message) *outbox.Message {
+	t.Helper()
+	var stored outbox.Message
+	if err := db.First(&stored, message.ID).Error; err != nil {
+		t.Fatalf("failed to load message %d: %v", message.ID, err)
+	}
+	return &stored
+}
+
+func relayPending(t *testing.T, relay *outbox.Relay, want int) {
+	t.Helper()
+	published, err := relay.RelayPending(context.Background())
+	if err != nil {
+		t.Fatalf("RelayPending() error = %v", err)
+	}
+	if published != want {
+		t.Fatalf("RelayPending() published %d messages, want %d", published, want)
+	}
+}
+
+func TestRelayPendingHoldsBackAggregateAfterFailure(t *testing.T) {
+	db := newTestDB(t)
+	publisher := &fakePublisher{}
+	relay := outbox.NewRelay(db, publisher, outbox.RelayConfig{
+		BatchSize:    100,
+		RetryBackoff: time.Minute,
+		MaxBackoff:   time.Hour,
+	}, relayMetrics)
+
+	first := addMessage(t, db, "a", "1")
+	addMessage(t, db, "b", "1")
+	addMessage(t, db, "a", "2")
+	addMessage(t, db, "b", "2")
+	publisher.failNext("a", 1)
+
+	// The failed message of a holds back the rest of a, but not b
+	relayPending(t, relay, 2)
+	if got, want := publisher.take(), []string{"b:1", "b:2"}; !slices.Equal(got, want) {
+		t.Fatalf("published %v, want %v", got, want)
+	}
+
+	failed := reload(t, db, first)
+	if failed.PublishedAt != nil || failed.Attempts != 1 || failed.LastError != "broker unavailable" {
+		t.Fatalf("failed message = %+v, want it rescheduled after one attempt", failed)
+	}
+
+	// Nothing of a is published while the failed message backs off
+	relayPending(t, relay, 0)
+	if got := publisher.take(); len(got) != 0 {
+		t.Fatalf("published %v while backing off, want nothing", got)
+	}
+
+	// Once the backoff passes, a is published in the order it was stored
+	if err := db.Model(first).Update("next_attempt_at", time.Now().UTC().Add(-time.Second)).Error; err != nil {
+		t.Fatalf("failed to expire backoff: %v", err)
+	}
+	relayPending(t, relay, 2)
+	if got, want := publisher.take(), []string{"a:1", "a:2"}; !slices.Equal(got, want) {
+		t.Fatalf("published %v, want %v", got, want)
+	}
+	if reload(t, db, first).PublishedAt == nil {
+		t.Error("retried message is not marked published")
+	}
+}
+
+func TestRelayPendingBacksOffExponentially(t *testing.T) {
+	tests := []struct {
+		name     string
+		attempts int
+		want     time.Duration
+	}{
+		{name: "first failure", attempts: 0, want: time.Second},
+		{name: "third failure", attempts: 2, want: 4 * time.Second},
+		{name: "capped", attempts: 10, want: time.Minute},
+		{name: "overflow", attempts: 200, want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			publisher := &fakePublisher{}
+			relay := outbox.NewRelay(db, publisher, outbox.RelayConfig{
+				BatchSize:    100,
+				RetryBackoff: time.Second,
+				MaxBackoff:   time.Minute,
+			}, relayMetrics)
+
+			message := addMessage(t, db, "a", "1")
+			if err := db.Model(message).Update("attempts", tt.attempts).Error; err != nil {
+				t.Fatalf("failed to set attempts: %v", err)
+			}
+			publisher.failNext("a", 1)
+
+			before := time.Now().UTC()
+			relayPending(t, relay, 0)
+			after := time.Now().UTC()
+
+			stored := reload(t, db, message)
+			if stored.Attempts != tt.attempts+1 {
+				t.Errorf("attempts = %d, want %d", stored.Attempts, tt.attempts+1)
+			}
+			if stored.NextAttemptAt.Before(before.Add(tt.want)) || stored.NextAttemptAt.After(after.Add(tt.want)) {
+				t.Errorf("next attempt in %v, want %v", stored.NextAttemptAt.Sub(before), tt.want)
+			}
+		})
+	}
+}
+
+func TestRelayPendingSkipsWhileAnotherRelayHoldsTheLock(t *testing.T) {
+	db := newTestDB(t)
+	publisher := &fakePublisher{}
+	relay := outbox.NewRelay(db, publisher, outbox.RelayConfig{BatchSize: 100}, relayMetrics)
+	message := addMessage(t, db, "a", "1")
+
+	lockHeld.Store(true)
+	relayPending(t, relay, 0)
+	lockHeld.Store(false)
+
+	if got := publisher.take(); len(got) != 0 {
+		t.Fatalf("published %v without the lock, want nothing", got)
+	}
+	if stored := reload(t, db, message); stored.PublishedAt != nil || stored.Attempts != 0 {
+		t.Fatalf("message = %+v, want it untouched", stored)
+	}
+
+	relayPending(t, relay, 1)
+}
+
+func TestDeletePublished(t *testing.T) {
+	db := newTestDB(t)
+	relay := outbox.NewRelay(db, &fakePublisher{}, outbox.RelayConfig{
+		BatchSize: 2,
+		Retention: time.Hour,
+	}, relayMetrics)
+
+	old := time.Now().UTC().Add(-2 * time.Hour)
+	recent := time.Now().UTC().Add(-time.Minute)
+	var expired []*outbox.Message
+	for range 5 {
+		message := addMessage(t, db, "a", "old")
+		if err := db.Model(message).Update("published_at", old).Error; err != nil {
+			t.Fatalf("failed to publish message: %v", err)
+		}
+		expired = append(expired, message)
+	}
+	kept := addMessage(t, db, "a", "recent")
+	if err := db.Model(kept).Update("published_at", recent).Error; err != nil {
+		t.Fatalf("failed to publish message: %v", err)
+	}
+	pending := addMessage(t, db, "b", "pending")
+	if err := db.Model(pending).Update("created_at", old).Error; err != nil {
+		t.Fatalf("failed to age message: %v", err)
+	}
+
+	// Five expired messages take three batches of two
+	deleted, err := relay.DeletePublished(context.Background())
+	if err != nil {
+		t.Fatalf("DeletePublished() error = %v", err)
+	}
+	if deleted != int64(len(expired)) {
+		t.Errorf("deleted %d messages, want %d", deleted, len(expired))
+	}
+
+	var remaining []outbox.Message
+	if err := db.Order("id").Find(&remaining).Error; err != nil {
+		t.Fatalf("failed to load messages: %v", err)
+	}
+	if len(remaining) != 2 || remaining[0].ID != kept.ID || remaining[1].ID != pending.ID {
+		t.Errorf("remaining messages = %+v, want the recent and the pending message", remaining)
+	}
+}
+
+func TestDeletePublishedKeepsEverythingWithoutRetention(t *testing.T) {
+	db := newTestDB(t)
+	relay := outbox.NewRelay(db, &fakePublisher{}, outbox.RelayConfig{BatchSize: 100}, relayMetrics)
+	message := addMessage(t, db, "a", "1")
+	if err := db.Model(message).Update("published_at", time.Now().UTC().Add(-365*24*time.Hour)).Error; err != nil {
+		t.Fatalf("failed to publish message: %v", err)
+	}
+
+	deleted, err := relay.DeletePublished(context.Background())
+	if err != nil {
+		t.Fatalf("DeletePublished() error = %v", err)
+	}
+	if deleted != 0 {
+		t.Errorf("deleted %d messages, want none", deleted)
+	}
+}